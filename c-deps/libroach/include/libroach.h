@@ -299,10 +299,24 @@ DBStatus MVCCFindSplitKey(DBIterator* iter, DBKey start, DBKey end, DBKey min_sp
 // TODO(peter): We could investigate using
 // https://github.com/petermattis/cppgo to generate C++ code that can
 // read the Go roachpb.Transaction structure.
+typedef struct {
+  int32_t start;
+  int32_t end;
+} DBIgnoredSeqNumRange;
+
+// DBIgnoredSeqNums is the list of the ranges of sequence numbers of a
+// transaction whose writes were rolled back to a savepoint. It mirrors
+// the layout of a Go []enginepb.IgnoredSeqNumRange.
+typedef struct {
+  DBIgnoredSeqNumRange* ranges;
+  int len;
+} DBIgnoredSeqNums;
+
 typedef struct {
   DBSlice id;
   uint32_t epoch;
   DBTimestamp max_timestamp;
+  DBIgnoredSeqNums ignored_seqnums;
 } DBTxn;
 
 typedef struct {
@@ -325,8 +339,12 @@ typedef struct {
 
 DBScanResults MVCCGet(DBIterator* iter, DBSlice key, DBTimestamp timestamp, DBTxn txn,
                       bool consistent, bool tombstones);
+// MVCCScan scans the keys in [start, end). If locking is set, the intents of
+// other transactions conflict with the scan regardless of their timestamp,
+// and if skip_locked is also set, the keys holding such intents are skipped.
 DBScanResults MVCCScan(DBIterator* iter, DBSlice start, DBSlice end, DBTimestamp timestamp,
-                       int64_t max_keys, DBTxn txn, bool consistent, bool reverse, bool tombstones);
+                       int64_t max_keys, DBTxn txn, bool consistent, bool reverse, bool tombstones,
+                       bool locking, bool skip_locked);
 
 // DBStatsResult contains various runtime stats for RocksDB.
 typedef struct {
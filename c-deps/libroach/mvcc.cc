@@ -281,19 +281,21 @@ DBScanResults MVCCGet(DBIterator* iter, DBSlice key, DBTimestamp timestamp, DBTx
   const DBSlice end = {0, 0};
   ScopedStats scoped_iter(iter);
   mvccForwardScanner scanner(iter, key, end, timestamp, 1 /* max_keys */, txn, consistent,
-                             tombstones);
+                             tombstones, false /* locking */, false /* skip_locked */);
   return scanner.get();
 }
 
 DBScanResults MVCCScan(DBIterator* iter, DBSlice start, DBSlice end, DBTimestamp timestamp,
                        int64_t max_keys, DBTxn txn, bool consistent, bool reverse,
-                       bool tombstones) {
+                       bool tombstones, bool locking, bool skip_locked) {
   ScopedStats scoped_iter(iter);
   if (reverse) {
-    mvccReverseScanner scanner(iter, end, start, timestamp, max_keys, txn, consistent, tombstones);
+    mvccReverseScanner scanner(iter, end, start, timestamp, max_keys, txn, consistent, tombstones,
+                               locking, skip_locked);
     return scanner.scan();
   } else {
-    mvccForwardScanner scanner(iter, start, end, timestamp, max_keys, txn, consistent, tombstones);
+    mvccForwardScanner scanner(iter, start, end, timestamp, max_keys, txn, consistent, tombstones,
+                               locking, skip_locked);
     return scanner.scan();
   }
 }
@@ -51,7 +51,7 @@ static const int kMaxItersBeforeSeek = 10;
 template <bool reverse> class mvccScanner {
  public:
   mvccScanner(DBIterator* iter, DBSlice start, DBSlice end, DBTimestamp timestamp, int64_t max_keys,
-              DBTxn txn, bool consistent, bool tombstones)
+              DBTxn txn, bool consistent, bool tombstones, bool locking, bool skip_locked)
       : iter_(iter),
         iter_rep_(iter->rep.get()),
         start_key_(ToSlice(start)),
@@ -61,8 +61,11 @@ template <bool reverse> class mvccScanner {
         txn_id_(ToSlice(txn.id)),
         txn_epoch_(txn.epoch),
         txn_max_timestamp_(txn.max_timestamp),
+        txn_ignored_seqnums_(txn.ignored_seqnums),
         consistent_(consistent),
         tombstones_(tombstones),
+        locking_(locking),
+        skip_locked_(skip_locked),
         check_uncertainty_(timestamp < txn.max_timestamp),
         kvs_(new chunkedBuffer),
         intents_(new rocksdb::WriteBatch),
@@ -226,12 +229,34 @@ template <bool reverse> class mvccScanner {
 
     const bool own_intent = (meta_.txn().id() == txn_id_);
     const DBTimestamp meta_timestamp = ToDBTimestamp(meta_.timestamp());
-    if (timestamp_ < meta_timestamp && !own_intent) {
+    if (skip_locked_ && !own_intent) {
+      // 5a. The key contains an intent which was not written by our
+      // transaction, and we're performing a locking read which skips
+      // the keys locked by other transactions (SKIP LOCKED). Skip the
+      // key entirely.
+      return advanceKey();
+    }
+
+    if (meta_.lock_only() && !locking_ && !own_intent) {
+      // 5b. The key contains an intent which only locks the key on
+      // behalf of another transaction; its value is a copy of the
+      // previous version. Non-locking reads don't conflict with such
+      // intents: read the previous version as if the intent didn't
+      // exist.
+      if (timestamp_ < meta_timestamp) {
+        return seekVersion(timestamp_, false);
+      }
+      return seekVersion(PrevTimestamp(meta_timestamp), false);
+    }
+
+    if (timestamp_ < meta_timestamp && !own_intent && !locking_) {
       // 5. The key contains an intent, but we're reading before the
       // intent. Seek to the desired version. Note that if we own the
       // intent (i.e. we're reading transactionally) we want to read
       // the intent regardless of our read timestamp and fall into
-      // case 8 below.
+      // case 8 below. Locking reads conflict with the intents of other
+      // transactions regardless of their timestamp and fall into case
+      // 7 below.
       return seekVersion(timestamp_, false);
     }
 
@@ -263,6 +288,20 @@ template <bool reverse> class mvccScanner {
       return advanceKey();
     }
 
+    if (txn_epoch_ == meta_.txn().epoch() && seqNumIsIgnored(meta_.txn().sequence())) {
+      // 8a. We're reading our own txn's intent, but its write was rolled
+      // back to a savepoint. Read the latest write in the intent history
+      // that was not rolled back, at the timestamp of the intent. If there
+      // is none, read the previous value as if the intent didn't exist.
+      for (int i = meta_.intent_history_size() - 1; i >= 0; i--) {
+        const auto& intent = meta_.intent_history(i);
+        if (!seqNumIsIgnored(intent.sequence())) {
+          return addIntentHistoryAndAdvance(meta_timestamp, intent.value());
+        }
+      }
+      return seekVersion(PrevTimestamp(meta_timestamp), false);
+    }
+
     if (txn_epoch_ == meta_.txn().epoch()) {
       // 8. We're reading our own txn's intent. Note that we read at
       // the intent timestamp, not at our read timestamp as the intent
@@ -417,6 +456,35 @@ template <bool reverse> class mvccScanner {
     return advanceKey();
   }
 
+  // addIntentHistoryAndAdvance is like addAndAdvance, but for a value
+  // from the intent history of the current key, which is added at the
+  // specified timestamp.
+  bool addIntentHistoryAndAdvance(DBTimestamp ts, const std::string& value) {
+    if (value.size() > 0 || tombstones_) {
+      kvs_->Put(EncodeKey(cur_key_, ts.wall_time, ts.logical), value);
+      if (kvs_->Count() == max_keys_) {
+        return false;
+      }
+    }
+    return advanceKey();
+  }
+
+  // seqNumIsIgnored returns true iff the sequence number is in one of
+  // the ranges of sequence numbers of our txn that were rolled back to
+  // a savepoint. The ranges are sorted and non-overlapping.
+  bool seqNumIsIgnored(int32_t seq) const {
+    for (int i = txn_ignored_seqnums_.len - 1; i >= 0; i--) {
+      const DBIgnoredSeqNumRange& range = txn_ignored_seqnums_.ranges[i];
+      if (seq > range.end) {
+        return false;
+      }
+      if (seq >= range.start) {
+        return true;
+      }
+    }
+    return false;
+  }
+
   // seekVersion advances the iterator to point to an MVCC version for
   // the specified key that is earlier than <ts_wall_time,
   // ts_logical>. Returns false if the iterator is exhausted or an
@@ -594,8 +662,11 @@ template <bool reverse> class mvccScanner {
   const rocksdb::Slice txn_id_;
   const uint32_t txn_epoch_;
   const DBTimestamp txn_max_timestamp_;
+  const DBIgnoredSeqNums txn_ignored_seqnums_;
   const bool consistent_;
   const bool tombstones_;
+  const bool locking_;
+  const bool skip_locked_;
   const bool check_uncertainty_;
   DBScanResults results_;
   std::unique_ptr<chunkedBuffer> kvs_;
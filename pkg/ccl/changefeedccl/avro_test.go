@@ -201,7 +201,7 @@ func TestAvroSchema(t *testing.T) {
 			sqlbase.ColumnType_COLLATEDSTRING, sqlbase.ColumnType_NAME, sqlbase.ColumnType_OID,
 			sqlbase.ColumnType_UUID, sqlbase.ColumnType_ARRAY, sqlbase.ColumnType_INET,
 			sqlbase.ColumnType_TIME, sqlbase.ColumnType_JSONB, sqlbase.ColumnType_BIT,
-			sqlbase.ColumnType_TUPLE:
+			sqlbase.ColumnType_TUPLE, sqlbase.ColumnType_TSVECTOR, sqlbase.ColumnType_TSQUERY:
 			continue
 			// TODO(dan): Implement these.
 		}
@@ -361,6 +361,13 @@ func validateChangefeedTable(
 			`CHANGEFEEDs are currently supported on tables with exactly 1 column family: %s has %d`,
 			tableDesc.Name, len(tableDesc.Families))
 	}
+	for i := range tableDesc.Columns {
+		if tableDesc.Columns[i].Virtual {
+			return errors.Errorf(
+				`CHANGEFEEDs are currently not supported on tables with virtual columns: %s has virtual column %s`,
+				tableDesc.Name, tableDesc.Columns[i].Name)
+		}
+	}
 
 	if tableDesc.State == sqlbase.TableDescriptor_DROP {
 		return errors.Errorf(`"%s" was dropped or truncated`, t.StatementTimeName)
@@ -856,7 +856,7 @@ func allPartitioningTests(rng *rand.Rand) []partitioningTest {
 		case sqlbase.ColumnType_COLLATEDSTRING:
 			typ.Locale = sqlbase.RandCollationLocale(rng)
 			colType = fmt.Sprintf(`STRING COLLATE %s`, *typ.Locale)
-		case sqlbase.ColumnType_JSONB:
+		case sqlbase.ColumnType_JSONB, sqlbase.ColumnType_TSVECTOR, sqlbase.ColumnType_TSQUERY:
 			// Not indexable.
 			continue
 		}
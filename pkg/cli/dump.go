@@ -102,6 +102,16 @@ func runDump(cmd *cobra.Command, args []string) error {
 		return collectOrder[mds[i].ID] < collectOrder[mds[j].ID]
 	})
 
+	// User-defined functions belong to the database, so they are only
+	// dumped along with the entire database.
+	var fnStmts []string
+	if tableNames == nil && dumpCtx.dumpMode != dumpDataOnly {
+		fnStmts, err = getFunctionStatements(conn, dbName, ts)
+		if err != nil {
+			return err
+		}
+	}
+
 	w := os.Stdout
 
 	if dumpCtx.dumpMode != dumpDataOnly {
@@ -113,6 +123,14 @@ func runDump(cmd *cobra.Command, args []string) error {
 				return err
 			}
 		}
+		// The functions are created after the tables, as their bodies are
+		// checked against the tables they query.
+		for i, stmt := range fnStmts {
+			if i > 0 || len(mds) > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "%s;\n", stmt)
+		}
 	}
 	if dumpCtx.dumpMode != dumpSchemaOnly {
 		for _, md := range mds {
@@ -267,6 +285,42 @@ func getTableNames(conn *sqlConn, dbName string, ts string) (tableNames []string
 	return tableNames, nil
 }
 
+// getFunctionStatements retrieves the CREATE FUNCTION statements of the
+// user-defined functions in the given database, in creation order.
+func getFunctionStatements(conn *sqlConn, dbName string, ts string) (stmts []string, err error) {
+	rows, err := conn.Query(fmt.Sprintf(`
+		SELECT create_statement
+		FROM "".crdb_internal.create_function_statements
+		AS OF SYSTEM TIME %s
+		WHERE database_name = $1
+		ORDER BY function_id
+		`, lex.EscapeSQLString(ts)), []driver.Value{dbName})
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]driver.Value, 1)
+	for {
+		if err := rows.Next(vals); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		stmtI := vals[0]
+		stmt, ok := stmtI.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected value: %T", stmtI)
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	return stmts, nil
+}
+
 func getBasicMetadata(conn *sqlConn, dbName, tableName string, ts string) (basicMetadata, error) {
 	name := tree.NewTableName(tree.Name(dbName), tree.Name(tableName))
 
@@ -67,6 +67,7 @@ func main() {
 					sqlbase.ColumnType_OID,         // our 8-byte ints are usually out of range for pg
 					sqlbase.ColumnType_FLOAT,       // slight rounding differences at the end
 					sqlbase.ColumnType_TIMESTAMPTZ, // slight timezone differences
+					sqlbase.ColumnType_ENUM,        // requires a user-defined type
 					// tested manually below:
 					sqlbase.ColumnType_ARRAY,
 					sqlbase.ColumnType_TUPLE:
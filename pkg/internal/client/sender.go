@@ -208,8 +208,24 @@ type TxnSender interface {
 	// However, this is used by DistSQL for sending the transaction over the wire
 	// when it creates flows.
 	SerializeTxn() *roachpb.Transaction
+
+	// CreateSavepoint establishes a savepoint. The transaction can later be
+	// rolled back to it with RollbackToSavepoint, as long as it hasn't
+	// restarted in the meantime.
+	CreateSavepoint(context.Context) (SavepointToken, error)
+
+	// RollbackToSavepoint rolls the transaction back to the given savepoint:
+	// all the writes performed since the savepoint was created are discarded,
+	// and the non-retriable error that may have been encountered in the
+	// meantime is cleared so that the transaction can continue.
+	RollbackToSavepoint(context.Context, SavepointToken) error
 }
 
+// SavepointToken represents a savepoint of a transaction, as created by
+// TxnSender.CreateSavepoint. It is opaque to the users of the TxnSender, which
+// only hand it back to the TxnSender that created it.
+type SavepointToken interface{}
+
 // TxnStatusOpt represents options for TxnSender.GetMeta().
 type TxnStatusOpt int
 
@@ -361,6 +377,16 @@ func (m *MockTransactionalSender) UpdateStateOnRemoteRetryableErr(
 // DisablePipelining is part of the client.TxnSender interface.
 func (m *MockTransactionalSender) DisablePipelining() error { return nil }
 
+// CreateSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) CreateSavepoint(context.Context) (SavepointToken, error) {
+	panic("unimplemented")
+}
+
+// RollbackToSavepoint is part of the client.TxnSender interface.
+func (m *MockTransactionalSender) RollbackToSavepoint(context.Context, SavepointToken) error {
+	panic("unimplemented")
+}
+
 // MockTxnSenderFactory is a TxnSenderFactory producing MockTxnSenders.
 type MockTxnSenderFactory struct {
 	senderFunc func(context.Context, *roachpb.Transaction, roachpb.BatchRequest) (
@@ -983,6 +983,23 @@ func (txn *Txn) IsSerializablePushAndRefreshNotPossible() bool {
 	return txn.mu.sender.IsSerializablePushAndRefreshNotPossible()
 }
 
+// CreateSavepoint establishes a savepoint, to which the transaction can later
+// be rolled back with RollbackToSavepoint.
+func (txn *Txn) CreateSavepoint(ctx context.Context) (SavepointToken, error) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.CreateSavepoint(ctx)
+}
+
+// RollbackToSavepoint discards all the writes performed by the transaction
+// since the given savepoint was created. It fails if the transaction has
+// restarted since then.
+func (txn *Txn) RollbackToSavepoint(ctx context.Context, s SavepointToken) error {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.RollbackToSavepoint(ctx, s)
+}
+
 // Type returns the transaction's type.
 func (txn *Txn) Type() TxnType {
 	return txn.typ
@@ -28,6 +28,8 @@ var _ Details = BackupDetails{}
 var _ Details = RestoreDetails{}
 var _ Details = SchemaChangeDetails{}
 var _ Details = ChangefeedDetails{}
+var _ Details = RowLevelTTLDetails{}
+var _ Details = MaterializedViewRefreshDetails{}
 
 // ProgressDetails is a marker interface for job progress details proto structs.
 type ProgressDetails interface{}
@@ -36,6 +38,8 @@ var _ ProgressDetails = BackupProgress{}
 var _ ProgressDetails = RestoreProgress{}
 var _ ProgressDetails = SchemaChangeProgress{}
 var _ ProgressDetails = ChangefeedProgress{}
+var _ ProgressDetails = RowLevelTTLProgress{}
+var _ ProgressDetails = MaterializedViewRefreshProgress{}
 
 // Type returns the payload's job type.
 func (p *Payload) Type() Type {
@@ -55,6 +59,10 @@ func DetailsType(d isPayload_Details) Type {
 		return TypeImport
 	case *Payload_Changefeed:
 		return TypeChangefeed
+	case *Payload_RowLevelTTL:
+		return TypeRowLevelTTL
+	case *Payload_MaterializedViewRefresh:
+		return TypeMaterializedViewRefresh
 	default:
 		panic(fmt.Sprintf("Payload.Type called on a payload with an unknown details type: %T", d))
 	}
@@ -79,6 +87,10 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_Import{Import: &d}
 	case ChangefeedProgress:
 		return &Progress_Changefeed{Changefeed: &d}
+	case RowLevelTTLProgress:
+		return &Progress_RowLevelTTL{RowLevelTTL: &d}
+	case MaterializedViewRefreshProgress:
+		return &Progress_MaterializedViewRefresh{MaterializedViewRefresh: &d}
 	default:
 		panic(fmt.Sprintf("WrapProgressDetails: unknown details type %T", d))
 	}
@@ -98,6 +110,10 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.Import
 	case *Payload_Changefeed:
 		return *d.Changefeed
+	case *Payload_RowLevelTTL:
+		return *d.RowLevelTTL
+	case *Payload_MaterializedViewRefresh:
+		return *d.MaterializedViewRefresh
 	default:
 		return nil
 	}
@@ -117,6 +133,10 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.Import
 	case *Progress_Changefeed:
 		return *d.Changefeed
+	case *Progress_RowLevelTTL:
+		return *d.RowLevelTTL
+	case *Progress_MaterializedViewRefresh:
+		return *d.MaterializedViewRefresh
 	default:
 		return nil
 	}
@@ -149,6 +169,10 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_Import{Import: &d}
 	case ChangefeedDetails:
 		return &Payload_Changefeed{Changefeed: &d}
+	case RowLevelTTLDetails:
+		return &Payload_RowLevelTTL{RowLevelTTL: &d}
+	case MaterializedViewRefreshDetails:
+		return &Payload_MaterializedViewRefresh{MaterializedViewRefresh: &d}
 	default:
 		panic(fmt.Sprintf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -22,7 +22,8 @@ import (
 
 // Metrics are for production monitoring of each job type.
 type Metrics struct {
-	Changefeed metric.Struct
+	Changefeed  metric.Struct
+	RowLevelTTL metric.Struct
 }
 
 // MetricStruct implements the metric.Struct interface.
@@ -33,8 +34,15 @@ func (m *Metrics) InitHooks(histogramWindowInterval time.Duration) {
 	if MakeChangefeedMetricsHook != nil {
 		m.Changefeed = MakeChangefeedMetricsHook(histogramWindowInterval)
 	}
+	if MakeRowLevelTTLMetricsHook != nil {
+		m.RowLevelTTL = MakeRowLevelTTLMetricsHook(histogramWindowInterval)
+	}
 }
 
 // MakeChangefeedMetricsHook allows for registration of changefeed metrics from
 // ccl code.
 var MakeChangefeedMetricsHook func(time.Duration) metric.Struct
+
+// MakeRowLevelTTLMetricsHook allows for registration of the metrics of the
+// row-level TTL jobs from the sql package.
+var MakeRowLevelTTLMetricsHook func(time.Duration) metric.Struct
@@ -105,6 +105,10 @@ type TxnCoordSender struct {
 		syncutil.Mutex
 
 		txnState txnState
+		// txnErrorUnrecoverable is set along with txnError when the error left
+		// the transaction in an unknown state, in which case rolling back to a
+		// savepoint can't make the transaction usable again.
+		txnErrorUnrecoverable bool
 
 		// active is set whenever the transaction has sent any requests.
 		active bool
@@ -676,6 +680,7 @@ func (tc *TxnCoordSender) Send(
 
 		if !retriable {
 			tc.mu.txnState = txnError
+			tc.mu.txnErrorUnrecoverable = !errorAllowsSavepointRollback(ba, pErr)
 		}
 
 		return nil, pErr
@@ -1029,6 +1034,7 @@ func (tc *TxnCoordSender) ManualRestart(
 	// The txn might have entered the txnError state after the epoch was bumped.
 	// Reset the state for the retry.
 	tc.mu.txnState = txnPending
+	tc.mu.txnErrorUnrecoverable = false
 }
 
 // IsSerializablePushAndRefreshNotPossible is part of the client.TxnSender interface.
@@ -0,0 +1,129 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kv
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/pkg/errors"
+)
+
+// savepoint captures the state of a transaction at the time a savepoint was
+// created. It is the TxnCoordSender's implementation of
+// client.SavepointToken.
+type savepoint struct {
+	// txnID and epoch identify the transaction attempt that created the
+	// savepoint. A savepoint cannot be rolled back to once the transaction has
+	// restarted, since the writes that preceded the savepoint have been
+	// discarded as well.
+	txnID uuid.UUID
+	epoch uint32
+
+	// seqNum is the sequence number of the last request sent by the
+	// transaction before the savepoint was created. Rolling back to the
+	// savepoint discards the writes with higher sequence numbers.
+	seqNum int32
+}
+
+var _ client.SavepointToken = &savepoint{}
+
+// CreateSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) CreateSavepoint(ctx context.Context) (client.SavepointToken, error) {
+	if tc.typ != client.RootTxn {
+		return nil, errors.Errorf("cannot create savepoint in non-root txn")
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if err := tc.assertNotFinalizedLocked(); err != nil {
+		return nil, err
+	}
+	return &savepoint{
+		txnID:  tc.mu.txn.ID,
+		epoch:  tc.mu.txn.Epoch,
+		seqNum: tc.interceptorAlloc.txnSeqNumAllocator.seqNumCounter,
+	}, nil
+}
+
+// RollbackToSavepoint is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) RollbackToSavepoint(
+	ctx context.Context, token client.SavepointToken,
+) error {
+	if tc.typ != client.RootTxn {
+		return errors.Errorf("cannot rollback to savepoint in non-root txn")
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if err := tc.assertNotFinalizedLocked(); err != nil {
+		return err
+	}
+	sp := token.(*savepoint)
+	if sp.txnID != tc.mu.txn.ID || sp.epoch != tc.mu.txn.Epoch {
+		return errors.Errorf("cannot rollback to savepoint created by an earlier transaction attempt")
+	}
+	if tc.mu.txnState == txnError && tc.mu.txnErrorUnrecoverable {
+		return errors.Errorf("cannot rollback to savepoint after an ambiguous result or a failed commit")
+	}
+
+	// Ignore all the writes performed after the savepoint was created.
+	if seqNum := tc.interceptorAlloc.txnSeqNumAllocator.seqNumCounter; seqNum > sp.seqNum {
+		tc.mu.txn.IgnoredSeqNums = enginepb.AddIgnoredSeqNumRange(
+			tc.mu.txn.IgnoredSeqNums,
+			enginepb.IgnoredSeqNumRange{Start: sp.seqNum + 1, End: seqNum})
+	}
+
+	// The non-retriable error that may have been encountered after the
+	// savepoint was created has been rolled back along with the writes, so the
+	// transaction can continue.
+	if tc.mu.txnState == txnError {
+		tc.mu.txnState = txnPending
+	}
+	return nil
+}
+
+// errorAllowsSavepointRollback returns whether a transaction is still well
+// defined after the given non-retriable error was returned for ba, in which
+// case rolling back to a savepoint created before ba discards its effects.
+// This is not the case after an ambiguous result, since the requests might
+// have been applied anyway, nor after an attempt to commit, since the
+// transaction record might have been finalized.
+func errorAllowsSavepointRollback(ba roachpb.BatchRequest, pErr *roachpb.Error) bool {
+	if _, ok := pErr.GetDetail().(*roachpb.AmbiguousResultError); ok {
+		return false
+	}
+	if req, ok := ba.GetArg(roachpb.EndTransaction); ok && req.(*roachpb.EndTransactionRequest).Commit {
+		return false
+	}
+	if txn := pErr.GetTxn(); txn != nil && txn.Status != roachpb.PENDING {
+		return false
+	}
+	return true
+}
+
+// assertNotFinalizedLocked returns an error if the transaction has already
+// committed or rolled back.
+func (tc *TxnCoordSender) assertNotFinalizedLocked() error {
+	if tc.mu.txnState == txnFinalized {
+		return errors.Errorf("cannot use savepoints in a committed or rolled back transaction")
+	}
+	return nil
+}
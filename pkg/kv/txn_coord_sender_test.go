@@ -2495,3 +2495,87 @@ func TestLeafTxnClientRejectError(t *testing.T) {
 		t.Fatalf("expected UnhandledRetryableError(TransactionAbortedError), got: (%T) %v", err, err)
 	}
 }
+
+// Test that rolling back to a savepoint only resumes a transaction after an
+// error that left the transaction well defined.
+func TestRollbackToSavepointAfterError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+	ambient := log.AmbientContext{Tracer: tracing.NewTracer()}
+
+	testCases := []struct {
+		name        string
+		err         *roachpb.Error
+		commit      bool
+		recoverable bool
+	}{
+		{"write error", roachpb.NewErrorf("injected err"), false, true},
+		{"ambiguous write", roachpb.NewError(roachpb.NewAmbiguousResultError("injected err")), false, false},
+		{"failed commit", roachpb.NewErrorf("injected err"), true, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stopper := stop.NewStopper()
+			defer stopper.Stop(ctx)
+			sender := &mockSender{}
+			factory := NewTxnCoordSenderFactory(
+				TxnCoordSenderFactoryConfig{
+					AmbientCtx: ambient,
+					Clock:      clock,
+					Stopper:    stopper,
+				},
+				sender,
+			)
+			db := client.NewDB(ambient, factory, clock)
+
+			sender.match(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+				if req, ok := ba.GetArg(roachpb.EndTransaction); ok {
+					if req.(*roachpb.EndTransactionRequest).Commit {
+						return nil, tc.err
+					}
+					return nil, nil
+				}
+				if req, ok := ba.GetArg(roachpb.Put); ok && req.Header().Key.Equal(roachpb.Key("b")) {
+					return nil, tc.err
+				}
+				return nil, nil
+			})
+
+			txn := client.NewTxn(ctx, db, roachpb.NodeID(1), client.RootTxn)
+			defer func() { _ = txn.Rollback(ctx) }()
+			if err := txn.Put(ctx, "a", "x"); err != nil {
+				t.Fatal(err)
+			}
+			sp, err := txn.CreateSavepoint(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.commit {
+				err = txn.Commit(ctx)
+			} else {
+				err = txn.Put(ctx, "b", "y")
+			}
+			if !testutils.IsError(err, "injected err") {
+				t.Fatalf("expected injected error, got %v", err)
+			}
+
+			err = txn.RollbackToSavepoint(ctx, sp)
+			if !tc.recoverable {
+				if !testutils.IsError(err, "cannot rollback to savepoint after an ambiguous result or a failed commit") {
+					t.Fatalf("expected savepoint rollback to be rejected, got %v", err)
+				}
+				if err := txn.Put(ctx, "c", "z"); !testutils.IsError(err, "txn already encountered an error") {
+					t.Fatalf("expected the transaction to remain failed, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := txn.Put(ctx, "c", "z"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -1011,8 +1011,21 @@ func (drr *DeleteRangeRequest) flags() int {
 // Note that ClearRange commands cannot be part of a transaction as
 // they clear all MVCC versions.
 func (*ClearRangeRequest) flags() int { return isWrite | isRange | isAlone }
-func (*ScanRequest) flags() int       { return isRead | isRange | isTxn | updatesReadTSCache | needsRefresh }
-func (*ReverseScanRequest) flags() int {
+
+// A locking scan writes intents on the keys that it returns, so it is also a
+// transactional write. Its intents don't change the values of the keys, but
+// like any other write, they must not be written beneath reads of other
+// transactions.
+func (sr *ScanRequest) flags() int {
+	if sr.KeyLocking != NON_LOCKING {
+		return isRead | isWrite | isRange | isTxn | isTxnWrite | updatesReadTSCache | needsRefresh | consultsTSCache
+	}
+	return isRead | isRange | isTxn | updatesReadTSCache | needsRefresh
+}
+func (rsr *ReverseScanRequest) flags() int {
+	if rsr.KeyLocking != NON_LOCKING {
+		return isRead | isWrite | isRange | isReverse | isTxn | isTxnWrite | updatesReadTSCache | needsRefresh | consultsTSCache
+	}
 	return isRead | isRange | isReverse | isTxn | updatesReadTSCache | needsRefresh
 }
 func (*BeginTransactionRequest) flags() int { return isWrite | isTxn | consultsTSCache }
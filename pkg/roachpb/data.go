@@ -808,6 +808,9 @@ func (t Transaction) Clone() Transaction {
 	// Note that we're not cloning the span keys under the assumption that the
 	// keys themselves are not mutable.
 	t.Intents = append([]Span(nil), t.Intents...)
+	if t.IgnoredSeqNums != nil {
+		t.IgnoredSeqNums = append([]enginepb.IgnoredSeqNumRange(nil), t.IgnoredSeqNums...)
+	}
 	return t
 }
 
@@ -926,6 +929,9 @@ func (t *Transaction) Restart(
 	t.UpgradePriority(upgradePriority)
 	t.WriteTooOld = false
 	t.Sequence = 0
+	// The sequence numbers of the new epoch start over, and none of them have
+	// been rolled back.
+	t.IgnoredSeqNums = nil
 	// Reset Writing. Since we're using a new epoch, we don't care about the abort
 	// cache.
 	t.Writing = false
@@ -986,6 +992,11 @@ func (t *Transaction) Update(o *Transaction) {
 
 	if t.Epoch < o.Epoch {
 		t.Epoch = o.Epoch
+		t.IgnoredSeqNums = o.IgnoredSeqNums
+	} else if t.Epoch == o.Epoch &&
+		ignoredSeqNumsCount(t.IgnoredSeqNums) < ignoredSeqNumsCount(o.IgnoredSeqNums) {
+		// Within an epoch, the set of ignored sequence numbers only grows.
+		t.IgnoredSeqNums = o.IgnoredSeqNums
 	}
 
 	t.Timestamp.Forward(o.Timestamp)
@@ -1018,6 +1029,16 @@ func (t *Transaction) Update(o *Transaction) {
 	}
 }
 
+// ignoredSeqNumsCount returns the number of sequence numbers in the given
+// ignored ranges.
+func ignoredSeqNumsCount(ignored []enginepb.IgnoredSeqNumRange) int32 {
+	var n int32
+	for _, r := range ignored {
+		n += r.End - r.Start + 1
+	}
+	return n
+}
+
 // UpgradePriority sets transaction priority to the maximum of current
 // priority and the specified minPriority. The exception is if the
 // current priority is set to the minimum, in which case the minimum
@@ -475,12 +475,13 @@ func TestFastPathObservedTimestamp(t *testing.T) {
 
 var nonZeroTxn = Transaction{
 	TxnMeta: enginepb.TxnMeta{
-		Key:       Key("foo"),
-		ID:        uuid.MakeV4(),
-		Epoch:     2,
-		Timestamp: makeTS(20, 21),
-		Priority:  957356782,
-		Sequence:  123,
+		Key:            Key("foo"),
+		ID:             uuid.MakeV4(),
+		Epoch:          2,
+		Timestamp:      makeTS(20, 21),
+		Priority:       957356782,
+		Sequence:       123,
+		IgnoredSeqNums: []enginepb.IgnoredSeqNumRange{{Start: 5, End: 7}},
 	},
 	Name:                     "name",
 	Status:                   COMMITTED,
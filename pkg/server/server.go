@@ -632,6 +632,10 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		AuditLogger: log.NewSecondaryLogger(
 			s.cfg.SQLAuditLogDirName, "sql-audit", true /*enableGc*/, true, /*forceSyncWrites*/
 		),
+
+		NotificationRegistry: sql.NewNotificationRegistry(
+			s.cfg.AmbientCtx, s.st, s.distSender, s.clock, s.stopper,
+		),
 	}
 
 	if sqlSchemaChangerTestingKnobs := s.cfg.TestingKnobs.SQLSchemaChanger; sqlSchemaChangerTestingKnobs != nil {
@@ -1554,6 +1558,12 @@ func (s *Server) Start(ctx context.Context) error {
 		}
 	}
 
+	// Start the cleaner of the temporary tables of sessions that didn't get to
+	// drop them, for example because their node crashed.
+	sql.NewTemporaryObjectCleaner(
+		s.st, s.db, s.internalExecutor, s.sessionRegistry, &s.nodeIDContainer, s.nodeLiveness.IsLive,
+	).Start(ctx, s.stopper)
+
 	// Before serving SQL requests, we have to make sure the database is
 	// in an acceptable form for this version of the software.
 	// We have to do this after actually starting up the server to be able to
@@ -58,6 +58,18 @@ var (
 		),
 		90*24*time.Hour, // 90 days
 	)
+
+	// notificationsTTL is the TTL for rows in system.notifications. Listening
+	// sessions receive notifications as they are written, so the table only
+	// needs to retain recent ones.
+	notificationsTTL = settings.RegisterDurationSetting(
+		"server.notifications.ttl",
+		fmt.Sprintf(
+			"if nonzero, notifications older than this duration are deleted every %s",
+			systemLogGCPeriod,
+		),
+		24*time.Hour,
+	)
 )
 
 // gcSystemLog deletes entries in the given system log table between
@@ -147,8 +159,8 @@ type systemLogGCConfig struct {
 	timestampLowerBound time.Time
 }
 
-// startSystemLogsGC starts a worker which periodically GCs system.rangelog
-// and system.eventlog.
+// startSystemLogsGC starts a worker which periodically GCs system.rangelog,
+// system.eventlog and system.notifications.
 // The TTLs for each of these logs is retrieved from cluster settings.
 func (s *Server) startSystemLogsGC(ctx context.Context) {
 	systemLogsToGC := map[string]*systemLogGCConfig{
@@ -160,6 +172,10 @@ func (s *Server) startSystemLogsGC(ctx context.Context) {
 			ttl:                 eventLogTTL,
 			timestampLowerBound: timeutil.Unix(0, 0),
 		},
+		"notifications": {
+			ttl:                 notificationsTTL,
+			timestampLowerBound: timeutil.Unix(0, 0),
+		},
 	}
 
 	s.stopper.RunWorker(ctx, func(ctx context.Context) {
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachange"
@@ -136,7 +137,8 @@ func (n *alterTableNode) startExec(params runParams) error {
 
 			// We're checking to see if a user is trying add a non-nullable column without a default to a
 			// non empty table by scanning the primary index span with a limit of 1 to see if any key exists.
-			if !col.Nullable && (col.DefaultExpr == nil && !col.IsComputed()) {
+			// Virtual computed columns are not backfilled, so their values cannot be validated either.
+			if !col.Nullable && (col.DefaultExpr == nil && (!col.IsComputed() || col.Virtual)) {
 				kvs, err := params.p.txn.Scan(params.ctx, n.tableDesc.PrimaryIndexSpan().Key, n.tableDesc.PrimaryIndexSpan().EndKey, 1)
 				if err != nil {
 					return err
@@ -184,6 +186,13 @@ func (n *alterTableNode) startExec(params runParams) error {
 				if d.PrimaryKey {
 					return fmt.Errorf("multiple primary keys for table %q are not allowed", n.tableDesc.Name)
 				}
+				if d.Deferrability != tree.NotDeferrable {
+					// The existing rows would need to be checked for duplicates,
+					// which the index backfill doesn't do for indexes that are
+					// not encoded as unique.
+					return pgerror.UnimplementedWithIssueDetailError(31632, "alter table add deferrable unique",
+						"deferrable UNIQUE constraints can only be declared with CREATE TABLE")
+				}
 				idx := sqlbase.IndexDescriptor{
 					Name:             string(d.Name),
 					Unique:           true,
@@ -192,6 +201,21 @@ func (n *alterTableNode) startExec(params runParams) error {
 				if err := idx.FillColumns(d.Columns); err != nil {
 					return err
 				}
+				if d.Sharded != nil {
+					if d.PartitionBy != nil {
+						return pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+							"partitioned indexes cannot also be hash sharded")
+					}
+					shardCol, err := setupShardedIndex(
+						n.tableDesc, &idx, d.Sharded, &params.p.semaCtx, params.EvalContext(),
+					)
+					if err != nil {
+						return err
+					}
+					if shardCol != nil {
+						n.tableDesc.AddColumnMutation(*shardCol, sqlbase.DescriptorMutation_ADD)
+					}
+				}
 				if d.PartitionBy != nil {
 					partitioning, err := CreatePartitioning(
 						params.ctx, params.p.ExecCfg().Settings,
@@ -201,6 +225,14 @@ func (n *alterTableNode) startExec(params runParams) error {
 					}
 					idx.Partitioning = partitioning
 				}
+				if d.Predicate != nil {
+					if err := makeIndexPredicate(
+						params.ctx, n.tableDesc, &idx, d.Predicate,
+						&params.p.semaCtx, params.EvalContext(), n.n.Table,
+					); err != nil {
+						return err
+					}
+				}
 				_, dropped, err := n.tableDesc.FindIndexByName(string(d.Name))
 				if err == nil {
 					if dropped {
@@ -286,6 +318,18 @@ func (n *alterTableNode) startExec(params runParams) error {
 			if dropped {
 				continue
 			}
+			if columnHasPendingTypeChange(n.tableDesc, col.ID) {
+				return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+					"column %q in the middle of a type change, try again later", col.Name)
+			}
+			if columnHasPendingNotNullValidation(n.tableDesc, col.ID) {
+				return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+					"column %q in the middle of a NOT NULL validation, try again later", col.Name)
+			}
+			if ttl := n.tableDesc.RowLevelTTL; ttl != nil && ttl.ColumnID == col.ID {
+				return pgerror.NewErrorf(pgerror.CodeInvalidColumnReferenceError,
+					"cannot drop column %q: it holds the expiration time of the rows of the table", col.Name)
+			}
 
 			// If the dropped column uses a sequence, remove references to it from that sequence.
 			if len(col.UsesSequenceIds) > 0 {
@@ -325,6 +369,13 @@ func (n *alterTableNode) startExec(params runParams) error {
 				}
 			}
 
+			for _, idx := range n.tableDesc.AllNonDropIndexes() {
+				if idx.IsSharded() && idx.Sharded.Name == col.Name {
+					return pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
+						"column %q is the shard column of hash sharded index %q; drop the index instead",
+						col.Name, idx.Name)
+				}
+			}
 			if n.tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
 				return fmt.Errorf("column %q is referenced by the primary key", col.Name)
 			}
@@ -379,6 +430,13 @@ func (n *alterTableNode) startExec(params runParams) error {
 						containsThisColumn = true
 					}
 				}
+				// A partial index cannot outlive a column referenced by its
+				// predicate.
+				for _, id := range idx.PredicateColumnIDs {
+					if id == col.ID {
+						containsThisColumn = true
+					}
+				}
 
 				// Perform the DROP.
 				if containsThisColumn {
@@ -445,6 +503,10 @@ func (n *alterTableNode) startExec(params runParams) error {
 			case sqlbase.ConstraintTypeCheck:
 				for i := range n.tableDesc.Checks {
 					if n.tableDesc.Checks[i].Name == name {
+						if n.tableDesc.Checks[i].Validity == sqlbase.ConstraintValidity_Validating {
+							return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+								"constraint %q in the middle of being validated, try again later", name)
+						}
 						n.tableDesc.Checks = append(n.tableDesc.Checks[:i], n.tableDesc.Checks[i+1:]...)
 						descriptorChanged = true
 						break
@@ -535,6 +597,14 @@ func (n *alterTableNode) startExec(params runParams) error {
 			if dropped {
 				return fmt.Errorf("column %q in the middle of being dropped", t.GetColumn())
 			}
+			if columnHasPendingTypeChange(n.tableDesc, col.ID) {
+				return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+					"column %q in the middle of a type change, try again later", col.Name)
+			}
+			if columnHasPendingNotNullValidation(n.tableDesc, col.ID) {
+				return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+					"column %q in the middle of a NOT NULL validation, try again later", col.Name)
+			}
 			if err := applyColumnMutation(n.tableDesc, &col, t, params); err != nil {
 				return err
 			}
@@ -665,6 +735,12 @@ func applyColumnMutation(
 ) error {
 	switch t := mut.(type) {
 	case *tree.AlterTableAlterColumnType:
+		toType, err := params.p.semaCtx.ResolveCastTargetType(t.ToType)
+		if err != nil {
+			return err
+		}
+		t.ToType = toType.(coltypes.T)
+
 		// Convert the parsed type into one of the basic datum types.
 		datum := coltypes.CastTargetToDatumType(t.ToType)
 
@@ -699,26 +775,27 @@ func applyColumnMutation(
 			return nil
 		}
 
-		kind, err := schemachange.ClassifyConversion(&col.Type, &nextType)
-		if err != nil {
-			return err
-		}
+		// A USING expression always requires the column data to be rewritten.
+		if t.Using == nil {
+			kind, err := schemachange.ClassifyConversion(&col.Type, &nextType)
+			if err != nil {
+				return err
+			}
 
-		switch kind {
-		case schemachange.ColumnConversionDangerous, schemachange.ColumnConversionImpossible:
-			// We're not going to make it impossible for the user to perform
-			// this conversion, but we do want them to explicit about
-			// what they're going for.
-			return pgerror.NewErrorf(pgerror.CodeCannotCoerceError,
-				"the requested type conversion (%s -> %s) requires an explicit USING expression",
-				col.Type.SQLString(), nextType.SQLString())
-		case schemachange.ColumnConversionTrivial:
-			col.Type = nextType
-		default:
-			return pgerror.UnimplementedWithIssueDetailError(9851,
-				fmt.Sprintf("%s->%s", col.Type.SQLString(), nextType.SQLString()),
-				"type conversion not yet implemented")
+			switch kind {
+			case schemachange.ColumnConversionDangerous, schemachange.ColumnConversionImpossible:
+				// We're not going to make it impossible for the user to perform
+				// this conversion, but we do want them to explicit about
+				// what they're going for.
+				return pgerror.NewErrorf(pgerror.CodeCannotCoerceError,
+					"the requested type conversion (%s -> %s) requires an explicit USING expression",
+					col.Type.SQLString(), nextType.SQLString())
+			case schemachange.ColumnConversionTrivial:
+				col.Type = nextType
+				return nil
+			}
 		}
+		return alterColumnTypeGeneral(tableDesc, col, t, nextType, params)
 
 	case *tree.AlterTableSetDefault:
 		if len(col.UsesSequenceIds) > 0 {
@@ -751,6 +828,12 @@ func applyColumnMutation(
 			}
 		}
 
+	case *tree.AlterTableSetNotNull:
+		if !col.Nullable {
+			return nil
+		}
+		return setColumnNotNull(tableDesc, col, params)
+
 	case *tree.AlterTableDropNotNull:
 		col.Nullable = true
 
@@ -759,11 +842,347 @@ func applyColumnMutation(
 			return pgerror.NewErrorf(pgerror.CodeInvalidColumnDefinitionError,
 				"column %q is not a computed column", col.Name)
 		}
+		if col.Virtual {
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"cannot drop the expression of virtual column %q", col.Name)
+		}
 		col.ComputeExpr = nil
 	}
 	return nil
 }
 
+// alterColumnTypeGeneral performs an ALTER COLUMN TYPE conversion which
+// requires rewriting the column data. A new column of the requested type,
+// computed from the old one by the USING expression, is added along with
+// copies of the secondary indexes referencing the old column. Once they have
+// been backfilled, a ComputedColumnSwap mutation makes them take the place
+// of the old column and indexes, which are then dropped.
+func alterColumnTypeGeneral(
+	tableDesc *sqlbase.MutableTableDescriptor,
+	col *sqlbase.ColumnDescriptor,
+	t *tree.AlterTableAlterColumnType,
+	nextType sqlbase.ColumnType,
+	params runParams,
+) error {
+	if tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
+		return pgerror.UnimplementedWithIssueDetailError(9851,
+			fmt.Sprintf("%s->%s", col.Type.SQLString(), nextType.SQLString()),
+			"type conversion of a primary key column not yet implemented")
+	}
+	if err := checkColumnTypeCanBeRewritten(tableDesc, col); err != nil {
+		return err
+	}
+
+	// The new column is computed by the USING expression, or by a cast of
+	// the old column if there is none, converted to the requested type.
+	using := t.Using
+	if using == nil {
+		using = &tree.ColumnItem{ColumnName: tree.Name(col.Name)}
+	}
+	var computeExpr tree.Expr = &tree.CastExpr{
+		Expr: using, Type: t.ToType, SyntaxMode: tree.CastShort,
+	}
+	if t.Collation != "" {
+		computeExpr = &tree.CollateExpr{Expr: computeExpr, Locale: t.Collation}
+	}
+	if err := iterColDescriptorsInExpr(tableDesc, computeExpr, func(c sqlbase.ColumnDescriptor) error {
+		if c.IsComputed() {
+			return pgerror.NewError(pgerror.CodeInvalidColumnReferenceError,
+				"USING expression cannot reference computed columns")
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	replacedExpr, _, err := replaceVars(tableDesc, computeExpr)
+	if err != nil {
+		return err
+	}
+	if _, err := sqlbase.SanitizeVarFreeExpr(
+		replacedExpr, nextType.ToDatumType(), "USING", &params.p.semaCtx, params.EvalContext(), false, /* allowImpure */
+	); err != nil {
+		return err
+	}
+	serializedExpr := tree.Serialize(computeExpr)
+
+	newCol := sqlbase.ColumnDescriptor{
+		Name:        uniqueColumnName(tableDesc, col.Name+"_new"),
+		Type:        nextType,
+		Nullable:    col.Nullable,
+		Hidden:      col.Hidden,
+		ComputeExpr: &serializedExpr,
+	}
+	if col.DefaultExpr != nil {
+		defaultExpr, err := parser.ParseExpr(*col.DefaultExpr)
+		if err != nil {
+			return err
+		}
+		if _, err := sqlbase.SanitizeVarFreeExpr(
+			defaultExpr, nextType.ToDatumType(), "DEFAULT", &params.p.semaCtx, params.EvalContext(), true, /* allowImpure */
+		); err != nil {
+			return pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"default for column %q cannot be cast automatically to type %s",
+				col.Name, nextType.SQLString())
+		}
+		newCol.DefaultExpr = col.DefaultExpr
+	}
+	tableDesc.AddColumnMutation(newCol, sqlbase.DescriptorMutation_ADD)
+	for _, family := range tableDesc.Families {
+		for _, id := range family.ColumnIDs {
+			if id == col.ID {
+				if err := tableDesc.AddColumnToFamilyMaybeCreate(
+					newCol.Name, family.Name, false /* create */, false, /* ifNotExists */
+				); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Rebuild the secondary indexes referencing the column.
+	var oldIndexIDs []sqlbase.IndexID
+	var newIndexNames []string
+	for _, idx := range tableDesc.Indexes {
+		if !idx.ContainsColumnID(col.ID) {
+			continue
+		}
+		newIdx := idx
+		newIdx.ID = 0
+		newIdx.Name = uniqueIndexName(tableDesc, idx.Name+"_new")
+		newIdx.ColumnNames = renameColumnInNames(idx.ColumnNames, col.Name, newCol.Name)
+		newIdx.ColumnDirections = append([]sqlbase.IndexDescriptor_Direction(nil), idx.ColumnDirections...)
+		newIdx.StoreColumnNames = renameColumnInNames(idx.StoreColumnNames, col.Name, newCol.Name)
+		newIdx.ColumnIDs = nil
+		newIdx.ExtraColumnIDs = nil
+		newIdx.StoreColumnIDs = nil
+		newIdx.CompositeColumnIDs = nil
+		if err := tableDesc.AddIndexMutation(newIdx, sqlbase.DescriptorMutation_ADD); err != nil {
+			return err
+		}
+		oldIndexIDs = append(oldIndexIDs, idx.ID)
+		newIndexNames = append(newIndexNames, newIdx.Name)
+	}
+
+	// The IDs of the new column and indexes are needed by the swap.
+	if err := tableDesc.AllocateIDs(); err != nil {
+		return err
+	}
+	swap := sqlbase.ComputedColumnSwap{OldColumnID: col.ID, OldIndexIDs: oldIndexIDs}
+	allocated, _, err := tableDesc.FindColumnByName(tree.Name(newCol.Name))
+	if err != nil {
+		return err
+	}
+	swap.NewColumnID = allocated.ID
+	for _, name := range newIndexNames {
+		idx, _, err := tableDesc.FindIndexByName(name)
+		if err != nil {
+			return err
+		}
+		swap.NewIndexIDs = append(swap.NewIndexIDs, idx.ID)
+	}
+	tableDesc.AddComputedColumnSwapMutation(swap)
+	return nil
+}
+
+// checkColumnTypeCanBeRewritten returns an error if the data of the column
+// cannot be rewritten by alterColumnTypeGeneral because of the objects
+// depending on it.
+func checkColumnTypeCanBeRewritten(
+	tableDesc *sqlbase.MutableTableDescriptor, col *sqlbase.ColumnDescriptor,
+) error {
+	if _, err := tableDesc.FindActiveColumnByID(col.ID); err != nil {
+		return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"column %q in the middle of being added, try again later", col.Name)
+	}
+	if col.IsComputed() {
+		return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"cannot alter type of computed column %q", col.Name)
+	}
+	if len(col.UsesSequenceIds) > 0 {
+		return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"cannot alter type of column %q which depends on a sequence", col.Name)
+	}
+	for _, ref := range tableDesc.DependedOnBy {
+		for _, id := range ref.ColumnIDs {
+			if id == col.ID {
+				return sqlbase.NewDependentObjectError(fmt.Sprintf(
+					"cannot alter type of column %q because a view depends on it", col.Name))
+			}
+		}
+	}
+	for _, check := range tableDesc.Checks {
+		if used, err := check.UsesColumn(tableDesc.TableDesc(), col.ID); err != nil {
+			return err
+		} else if used {
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"cannot alter type of column %q used in check constraint %q", col.Name, check.Name)
+		}
+	}
+	for _, c := range tableDesc.Columns {
+		if !c.IsComputed() {
+			continue
+		}
+		expr, err := parser.ParseExpr(*c.ComputeExpr)
+		if err != nil {
+			return err
+		}
+		if err := iterColDescriptorsInExpr(tableDesc, expr, func(ref sqlbase.ColumnDescriptor) error {
+			if ref.ID == col.ID {
+				return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+					"cannot alter type of column %q used by computed column %q", col.Name, c.Name)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	for _, m := range tableDesc.Mutations {
+		if idx := m.GetIndex(); idx != nil && idx.ContainsColumnID(col.ID) {
+			return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+				"column %q is referenced by index %q in the middle of a schema change, try again later",
+				col.Name, idx.Name)
+		}
+	}
+	for _, idx := range tableDesc.Indexes {
+		if !idx.ContainsColumnID(col.ID) {
+			continue
+		}
+		if idx.ForeignKey.IsSet() || len(idx.ReferencedBy) > 0 {
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"cannot alter type of column %q referenced by foreign key index %q", col.Name, idx.Name)
+		}
+		if idx.IsInterleaved() || idx.Partitioning.NumColumns > 0 {
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"cannot alter type of column %q referenced by interleaved or partitioned index %q",
+				col.Name, idx.Name)
+		}
+		for _, id := range idx.PredicateColumnIDs {
+			if id == col.ID {
+				return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+					"cannot alter type of column %q used in the predicate of index %q", col.Name, idx.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// columnHasPendingTypeChange returns whether the column with the given ID is
+// being replaced, or is replacing another column, by an ALTER COLUMN TYPE
+// conversion which hasn't completed yet.
+func columnHasPendingTypeChange(desc *sqlbase.MutableTableDescriptor, id sqlbase.ColumnID) bool {
+	for _, m := range desc.Mutations {
+		if swap := m.GetComputedColumnSwap(); swap != nil && m.Direction == sqlbase.DescriptorMutation_ADD {
+			if swap.OldColumnID == id || swap.NewColumnID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// columnHasPendingNotNullValidation returns whether the column with the
+// given ID is being validated by an ALTER COLUMN SET NOT NULL.
+func columnHasPendingNotNullValidation(
+	desc *sqlbase.MutableTableDescriptor, id sqlbase.ColumnID,
+) bool {
+	for _, m := range desc.Mutations {
+		if ck := m.GetCheck(); ck != nil && ck.IsNonNullConstraint && m.Direction == sqlbase.DescriptorMutation_ADD {
+			if len(ck.ColumnIDs) == 1 && ck.ColumnIDs[0] == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setColumnNotNull makes the column non-nullable. The existing data of the
+// table is validated by the schema changer, and a NOT NULL check constraint
+// prevents NULLs from being written in the meantime. Once the validation
+// succeeds, the check constraint is replaced by the column becoming
+// non-nullable. A table created in the same transaction is validated right
+// away instead.
+func setColumnNotNull(
+	tableDesc *sqlbase.MutableTableDescriptor, col *sqlbase.ColumnDescriptor, params runParams,
+) error {
+	if _, err := tableDesc.FindActiveColumnByID(col.ID); err != nil {
+		return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"column %q in the middle of being added, try again later", col.Name)
+	}
+	info, err := tableDesc.GetConstraintInfo(params.ctx, nil)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s_auto_not_null", col.Name)
+	for i := 1; ; i++ {
+		if _, ok := info[name]; !ok {
+			break
+		}
+		name = fmt.Sprintf("%s_auto_not_null%d", col.Name, i)
+	}
+	ck := sqlbase.TableDescriptor_CheckConstraint{
+		Expr: tree.Serialize(&tree.ComparisonExpr{
+			Operator: tree.IsDistinctFrom,
+			Left:     &tree.ColumnItem{ColumnName: tree.Name(col.Name)},
+			Right:    tree.DNull,
+		}),
+		Name:                name,
+		Validity:            sqlbase.ConstraintValidity_Validating,
+		ColumnIDs:           []sqlbase.ColumnID{col.ID},
+		IsNonNullConstraint: true,
+	}
+
+	if tableDesc.IsNewTable() {
+		if err := params.p.validateCheck(params.ctx, tableDesc.TableDesc(), &ck); err != nil {
+			return err
+		}
+		col.Nullable = false
+		return nil
+	}
+	tableDesc.Checks = append(tableDesc.Checks, &ck)
+	tableDesc.AddCheckValidationMutation(ck)
+	return nil
+}
+
+// uniqueColumnName returns name, or name followed by a number if a column
+// with that name already exists.
+func uniqueColumnName(desc *sqlbase.MutableTableDescriptor, name string) string {
+	candidate := name
+	for i := 1; ; i++ {
+		if _, _, err := desc.FindColumnByName(tree.Name(candidate)); err != nil {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+// uniqueIndexName returns name, or name followed by a number if an index
+// with that name already exists.
+func uniqueIndexName(desc *sqlbase.MutableTableDescriptor, name string) string {
+	candidate := name
+	for i := 1; ; i++ {
+		if _, _, err := desc.FindIndexByName(candidate); err != nil {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+// renameColumnInNames returns a copy of names in which from is replaced
+// by to.
+func renameColumnInNames(names []string, from, to string) []string {
+	if names == nil {
+		return nil
+	}
+	renamed := make([]string, len(names))
+	for i, name := range names {
+		if name == from {
+			name = to
+		}
+		renamed[i] = name
+	}
+	return renamed
+}
+
 func labeledRowValues(cols []sqlbase.ColumnDescriptor, values tree.Datums) string {
 	var s bytes.Buffer
 	for i := range cols {
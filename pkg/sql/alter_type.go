@@ -0,0 +1,284 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type alterTypeNode struct {
+	n       *tree.AlterType
+	typDesc *sqlbase.TypeDescriptor
+}
+
+// AlterType applies a type modification operation to a user-defined type.
+// Privileges: CREATE on type.
+func (p *planner) AlterType(ctx context.Context, n *tree.AlterType) (planNode, error) {
+	typDesc, err := p.resolveTypeDescForChange(ctx, string(n.Name), true /* required */)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, typDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &alterTypeNode{n: n, typDesc: typDesc}, nil
+}
+
+func (n *alterTypeNode) startExec(params runParams) error {
+	switch t := n.n.Cmd.(type) {
+	case *tree.AlterTypeAddValue:
+		if t.IfNotExists && n.typDesc.FindEnumMember(t.NewVal) != -1 {
+			return nil
+		}
+		var neighbor string
+		var before bool
+		if t.Placement != nil {
+			neighbor, before = t.Placement.ExistingVal, t.Placement.Before
+		}
+		if err := n.typDesc.AddEnumMember(t.NewVal, neighbor, before); err != nil {
+			return err
+		}
+		if err := n.addEnumMember(params, t.NewVal); err != nil {
+			return err
+		}
+	default:
+		return pgerror.NewAssertionErrorf("unsupported alter command: %T", t)
+	}
+
+	// Record this type alteration in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the type descriptor
+	// update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogAlterType,
+		int32(n.typDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TypeName  string
+			Statement string
+			User      string
+		}{n.typDesc.Name, n.n.String(), params.SessionData().User},
+	)
+}
+
+// addEnumMember writes the type descriptor to which the READ_ONLY member
+// with the given label has been added. The member can't be written until
+// all the nodes can decode it: each table that uses the type gets an
+// EnumMemberPromotion mutation, which waits for the new version of the
+// table, with the new member, to be leased everywhere. The schema changer
+// makes the member writable once the mutation has completed on all the
+// tables.
+func (n *alterTypeNode) addEnumMember(params runParams, label string) error {
+	tableIDs, err := tablesReferencingType(params.ctx, params.p.txn, n.typDesc.ID)
+	if err != nil {
+		return err
+	}
+	var tableDescs, newTableDescs []*sqlbase.MutableTableDescriptor
+	for _, id := range tableIDs {
+		tableDesc, err := params.p.Tables().getMutableTableVersionByID(params.ctx, id, params.p.txn)
+		if err != nil {
+			return err
+		}
+		if tableDesc.IsNewTable() {
+			// A table created in this transaction isn't leased by any node
+			// yet.
+			newTableDescs = append(newTableDescs, tableDesc)
+		} else {
+			tableDescs = append(tableDescs, tableDesc)
+		}
+	}
+	if len(tableDescs) == 0 {
+		// No node can have a table descriptor without the new member.
+		n.typDesc.PromoteEnumMember(label)
+	}
+
+	if err := n.typDesc.Validate(); err != nil {
+		return err
+	}
+	descKey := sqlbase.MakeDescMetadataKey(n.typDesc.ID)
+	descDesc := sqlbase.WrapDescriptor(n.typDesc)
+	if params.p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(params.ctx, 2, "Put %s -> %s", descKey, descDesc)
+	}
+	if err := params.p.txn.Put(params.ctx, descKey, descDesc); err != nil {
+		return err
+	}
+
+	// The descriptors of the new tables are used by the rest of the
+	// transaction; their column types need the new member.
+	for _, tableDesc := range newTableDescs {
+		if err := tableDesc.HydrateEnumTypes(params.ctx, params.p.txn); err != nil {
+			return err
+		}
+	}
+	promotion := sqlbase.EnumMemberPromotion{TypeID: n.typDesc.ID, Label: label}
+	for _, tableDesc := range tableDescs {
+		tableDesc.AddEnumMemberPromotionMutation(promotion)
+		mutationID, err := params.p.createOrUpdateSchemaChangeJob(
+			params.ctx, tableDesc, tree.AsStringWithFlags(n.n, tree.FmtAlwaysQualifyTableNames))
+		if err != nil {
+			return err
+		}
+		if err := params.p.writeSchemaChange(params.ctx, tableDesc, mutationID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *alterTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (n *alterTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *alterTypeNode) Close(context.Context)        {}
+
+// resolveTypeDescForChange looks up the descriptor of the user-defined type
+// with the given name in the current database, bypassing the descriptor
+// caches. It returns nil if the type does not exist and required is false.
+func (p *planner) resolveTypeDescForChange(
+	ctx context.Context, name string, required bool,
+) (*sqlbase.TypeDescriptor, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /*required*/)
+	if err != nil {
+		return nil, err
+	}
+	typDesc, err := getTypeDesc(ctx, p.txn, dbDesc, name)
+	if err != nil {
+		return nil, err
+	}
+	if typDesc == nil && required {
+		return nil, tree.NewUndefinedTypeError(name)
+	}
+	return typDesc, nil
+}
+
+// maybePromoteEnumMember makes the member of an ENUM type promoted by the
+// given mutation writable, if the mutation has completed on all the tables
+// that use the type. It is called by the schema changer in the transaction
+// that completes the mutation on one of the tables, and returns true if the
+// member was promoted.
+func maybePromoteEnumMember(
+	ctx context.Context, txn *client.Txn, promotion sqlbase.EnumMemberPromotion,
+) (bool, error) {
+	descs, err := GetAllDescriptors(ctx, txn)
+	if err != nil {
+		return false, err
+	}
+	for _, desc := range descs {
+		table, ok := desc.(*sqlbase.TableDescriptor)
+		if !ok || table.Dropped() {
+			continue
+		}
+		for _, m := range table.Mutations {
+			if p := m.GetEnumMemberPromotion(); p != nil && *p == promotion {
+				return false, nil
+			}
+		}
+	}
+	typDesc, err := sqlbase.GetTypeDescFromID(ctx, txn, promotion.TypeID)
+	if err != nil {
+		return false, err
+	}
+	if !typDesc.PromoteEnumMember(promotion.Label) {
+		return false, nil
+	}
+	descKey := sqlbase.MakeDescMetadataKey(typDesc.ID)
+	if err := txn.Put(ctx, descKey, sqlbase.WrapDescriptor(typDesc)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// refreshTablesReferencingType increments the version of the tables that use
+// the type with the given ID, except the table with ID exceptID, so that
+// their leased descriptors pick up the changes to the type.
+func refreshTablesReferencingType(
+	ctx context.Context, db *client.DB, leaseMgr *LeaseManager, typeID, exceptID sqlbase.ID,
+) error {
+	var tableIDs []sqlbase.ID
+	if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		var err error
+		tableIDs, err = tablesReferencingType(ctx, txn, typeID)
+		return err
+	}); err != nil {
+		return err
+	}
+	for _, id := range tableIDs {
+		if id == exceptID {
+			continue
+		}
+		if _, err := leaseMgr.Publish(ctx, id, func(*sqlbase.MutableTableDescriptor) error {
+			// Publish() increments the version.
+			return nil
+		}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tablesReferencingType returns the IDs of the tables that have columns of the
+// user-defined type with the given ID.
+func tablesReferencingType(
+	ctx context.Context, txn *client.Txn, typeID sqlbase.ID,
+) ([]sqlbase.ID, error) {
+	descs, err := GetAllDescriptors(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	var ids []sqlbase.ID
+	for _, desc := range descs {
+		table, ok := desc.(*sqlbase.TableDescriptor)
+		if !ok || table.Dropped() {
+			continue
+		}
+		if len(columnsOfType(table, typeID)) > 0 {
+			ids = append(ids, table.ID)
+		}
+	}
+	return ids, nil
+}
+
+// columnsOfType returns the columns of the table, including those being
+// added or dropped in mutations, that are of the user-defined type with the
+// given ID.
+func columnsOfType(table *sqlbase.TableDescriptor, typeID sqlbase.ID) []sqlbase.ColumnDescriptor {
+	var cols []sqlbase.ColumnDescriptor
+	isOfType := func(col *sqlbase.ColumnDescriptor) bool {
+		return col.Type.SemanticType == sqlbase.ColumnType_ENUM && col.Type.EnumTypeID == typeID
+	}
+	for i := range table.Columns {
+		if isOfType(&table.Columns[i]) {
+			cols = append(cols, table.Columns[i])
+		}
+	}
+	for _, m := range table.Mutations {
+		if col := m.GetColumn(); col != nil && isOfType(col) {
+			cols = append(cols, *col)
+		}
+	}
+	return cols
+}
@@ -0,0 +1,172 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// applyJoinPlanRightSideFn creates a plan for the right side of an apply
+// join, given a row of the left side.
+type applyJoinPlanRightSideFn func(leftRow tree.Datums) (planNode, error)
+
+// applyJoinNode implements an apply join: a join whose right side refers to
+// the columns of its left side, and which could not be decorrelated by the
+// optimizer. For every row of the left side, a plan for the right side is
+// created, in which the references to the left side are replaced by the
+// values of the row, and then executed.
+//
+// Only inner, left outer, semi and anti joins are supported.
+type applyJoinNode struct {
+	joinType sqlbase.JoinType
+
+	// input is the left side of the join.
+	input planDataSource
+
+	// pred evaluates the ON condition; it has no equality columns.
+	pred *joinPredicate
+
+	// columns are the result columns of the join.
+	columns sqlbase.ResultColumns
+
+	// rightCols are the columns produced by the plans of the right side.
+	rightCols sqlbase.ResultColumns
+
+	planRightSideFn applyJoinPlanRightSideFn
+
+	run applyJoinRun
+}
+
+// applyJoinRun contains the run-time state of applyJoinNode during local
+// execution.
+type applyJoinRun struct {
+	// leftRow is the current row of the left side.
+	leftRow tree.Datums
+	// leftMatched is set once the current row of the left side has matched a
+	// row of the right side.
+	leftMatched bool
+
+	// right is the plan of the right side for the current row of the left
+	// side, or nil if it is not running.
+	right planNode
+
+	// out is the row most recently returned by Next.
+	out tree.Datums
+
+	// rightNulls is a row of NULLs for the right side, used by left outer
+	// joins for the rows of the left side that have no match.
+	rightNulls tree.Datums
+}
+
+func (n *applyJoinNode) startExec(params runParams) error {
+	n.run.out = make(tree.Datums, len(n.columns))
+	n.run.rightNulls = make(tree.Datums, len(n.rightCols))
+	for i := range n.run.rightNulls {
+		n.run.rightNulls[i] = tree.DNull
+	}
+	return nil
+}
+
+func (n *applyJoinNode) Next(params runParams) (bool, error) {
+	for {
+		if err := params.p.cancelChecker.Check(); err != nil {
+			return false, err
+		}
+
+		if n.run.right != nil {
+			ok, err := n.run.right.Next(params)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				rightRow := n.run.right.Values()
+				pass, err := n.pred.eval(params.EvalContext(), n.run.leftRow, rightRow)
+				if err != nil {
+					return false, err
+				}
+				if !pass {
+					continue
+				}
+				n.run.leftMatched = true
+				switch n.joinType {
+				case sqlbase.LeftSemiJoin:
+					// The left row is emitted once, no matter how many rows of
+					// the right side match it.
+					n.closeRight(params.ctx)
+					copy(n.run.out, n.run.leftRow)
+					return true, nil
+				case sqlbase.LeftAntiJoin:
+					// The left row is not emitted.
+					n.closeRight(params.ctx)
+					continue
+				}
+				n.pred.prepareRow(n.run.out, n.run.leftRow, rightRow)
+				return true, nil
+			}
+
+			// The right side is exhausted.
+			n.closeRight(params.ctx)
+			if !n.run.leftMatched {
+				switch n.joinType {
+				case sqlbase.LeftOuterJoin:
+					n.pred.prepareRow(n.run.out, n.run.leftRow, n.run.rightNulls)
+					return true, nil
+				case sqlbase.LeftAntiJoin:
+					copy(n.run.out, n.run.leftRow)
+					return true, nil
+				}
+			}
+			continue
+		}
+
+		// Move on to the next row of the left side.
+		ok, err := n.input.plan.Next(params)
+		if err != nil || !ok {
+			return false, err
+		}
+		n.run.leftRow = append(n.run.leftRow[:0], n.input.plan.Values()...)
+		n.run.leftMatched = false
+
+		plan, err := n.planRightSideFn(n.run.leftRow)
+		if err != nil {
+			return false, err
+		}
+		if err := startExec(params, plan); err != nil {
+			plan.Close(params.ctx)
+			return false, err
+		}
+		n.run.right = plan
+	}
+}
+
+// closeRight closes the plan of the right side, if it is running.
+func (n *applyJoinNode) closeRight(ctx context.Context) {
+	if n.run.right != nil {
+		n.run.right.Close(ctx)
+		n.run.right = nil
+	}
+}
+
+func (n *applyJoinNode) Values() tree.Datums {
+	return n.run.out
+}
+
+func (n *applyJoinNode) Close(ctx context.Context) {
+	n.closeRight(ctx)
+	n.input.plan.Close(ctx)
+}
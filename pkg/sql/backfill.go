@@ -23,9 +23,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/backfill"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -55,6 +58,10 @@ const (
 	// many ranges.
 	indexBackfillChunkSize = 100
 
+	// materializedViewBackfillChunkSize is the maximum number of rows of a
+	// materialized view written per chunk when the view is populated.
+	materializedViewBackfillChunkSize = 100
+
 	// checkpointInterval is the interval after which a checkpoint of the
 	// schema change is posted.
 	checkpointInterval = 2 * time.Minute
@@ -113,6 +120,7 @@ func (sc *SchemaChanger) runBackfill(
 	// mutations. Collect the elements that are part of the mutation.
 	var droppedIndexDescs []sqlbase.IndexDescriptor
 	var addedIndexDescs []sqlbase.IndexDescriptor
+	var addedChecks []sqlbase.TableDescriptor_CheckConstraint
 
 	var tableDesc *sqlbase.TableDescriptor
 	if err := sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
@@ -145,6 +153,12 @@ func (sc *SchemaChanger) runBackfill(
 				}
 			case *sqlbase.DescriptorMutation_Index:
 				addedIndexDescs = append(addedIndexDescs, *t.Index)
+			case *sqlbase.DescriptorMutation_ComputedColumnSwap:
+				// The swap itself requires no backfill.
+			case *sqlbase.DescriptorMutation_Check:
+				addedChecks = append(addedChecks, *t.Check)
+			case *sqlbase.DescriptorMutation_EnumMemberPromotion:
+				// The promotion of an enum value requires no backfill.
 			default:
 				return errors.Errorf("unsupported mutation: %+v", m)
 			}
@@ -157,6 +171,12 @@ func (sc *SchemaChanger) runBackfill(
 				if !sc.canClearRangeForDrop(t.Index) {
 					droppedIndexDescs = append(droppedIndexDescs, *t.Index)
 				}
+			case *sqlbase.DescriptorMutation_ComputedColumnSwap:
+				// A rolled back swap requires no backfill.
+			case *sqlbase.DescriptorMutation_Check:
+				// A rolled back check validation requires no backfill.
+			case *sqlbase.DescriptorMutation_EnumMemberPromotion:
+				// A rolled back promotion requires no backfill.
 			default:
 				return errors.Errorf("unsupported mutation: %+v", m)
 			}
@@ -186,9 +206,53 @@ func (sc *SchemaChanger) runBackfill(
 		}
 	}
 
+	// Validate check constraints against the existing data.
+	if len(addedChecks) > 0 {
+		if err := sc.validateChecks(ctx, lease, addedChecks); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// validateChecks validates the check constraints of check mutations. The
+// check constraints are enforced on writes by all the nodes by now, so only
+// the existing data has to be validated.
+func (sc *SchemaChanger) validateChecks(
+	ctx context.Context,
+	lease *sqlbase.TableDescriptor_SchemaChangeLease,
+	checks []sqlbase.TableDescriptor_CheckConstraint,
+) error {
+	if err := sc.ExtendLease(ctx, lease); err != nil {
+		return err
+	}
+	return sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		tableDesc, err := sqlbase.GetTableDescFromID(ctx, txn, sc.tableID)
+		if err != nil {
+			return err
+		}
+		for _, ck := range checks {
+			// Use the check constraint of the table descriptor rather than the
+			// copy in the mutation, which isn't updated by column renames.
+			found := false
+			for _, c := range tableDesc.Checks {
+				if c.Name == ck.Name {
+					if err := validateCheckInTxn(ctx, txn, sc.execCfg, tableDesc, c); err != nil {
+						return err
+					}
+					found = true
+					break
+				}
+			}
+			if !found {
+				return errors.Errorf("check %q of mutation not found", ck.Name)
+			}
+		}
+		return nil
+	})
+}
+
 func (sc *SchemaChanger) getTableVersion(
 	ctx context.Context, txn *client.Txn, tc *TableCollection, version sqlbase.DescriptorVersion,
 ) (*sqlbase.ImmutableTableDescriptor, error) {
@@ -517,59 +581,71 @@ func runSchemaChangesInTxn(
 		return nil
 	}
 
-	// Only needed because columnBackfillInTxn() backfills
-	// all column mutations.
-	doneColumnBackfill := false
-	for _, m := range tableDesc.Mutations {
-		immutDesc := sqlbase.NewImmutableTableDescriptor(*tableDesc.TableDesc())
-		switch m.Direction {
-		case sqlbase.DescriptorMutation_ADD:
-			switch m.Descriptor_.(type) {
-			case *sqlbase.DescriptorMutation_Column:
-				if doneColumnBackfill || !sqlbase.ColumnNeedsBackfill(m.GetColumn()) {
-					break
-				}
-				if err := columnBackfillInTxn(ctx, txn, tc, evalCtx, immutDesc, traceKV); err != nil {
-					return err
-				}
-				doneColumnBackfill = true
+	// Completing a mutation can queue up new ones, e.g. a ComputedColumnSwap
+	// queues up the drop of the replaced column and indexes, so keep going
+	// until none are left.
+	for len(tableDesc.Mutations) > 0 {
+		numMutations := len(tableDesc.Mutations)
+		// Only needed because columnBackfillInTxn() backfills
+		// all column mutations.
+		doneColumnBackfill := false
+		for _, m := range tableDesc.Mutations[:numMutations] {
+			immutDesc := sqlbase.NewImmutableTableDescriptor(*tableDesc.TableDesc())
+			switch m.Direction {
+			case sqlbase.DescriptorMutation_ADD:
+				switch m.Descriptor_.(type) {
+				case *sqlbase.DescriptorMutation_Column:
+					if doneColumnBackfill || !sqlbase.ColumnNeedsBackfill(m.GetColumn()) {
+						break
+					}
+					if err := columnBackfillInTxn(ctx, txn, tc, evalCtx, immutDesc, traceKV); err != nil {
+						return err
+					}
+					doneColumnBackfill = true
 
-			case *sqlbase.DescriptorMutation_Index:
-				if err := indexBackfillInTxn(ctx, txn, immutDesc, traceKV); err != nil {
-					return err
-				}
+				case *sqlbase.DescriptorMutation_Index:
+					if err := indexBackfillInTxn(ctx, txn, evalCtx, immutDesc, traceKV); err != nil {
+						return err
+					}
 
-			default:
-				return errors.Errorf("unsupported mutation: %+v", m)
-			}
+				case *sqlbase.DescriptorMutation_ComputedColumnSwap:
+					// The new column and indexes have been backfilled above.
 
-		case sqlbase.DescriptorMutation_DROP:
-			// Drop the name and drop the associated data later.
-			switch m.Descriptor_.(type) {
-			case *sqlbase.DescriptorMutation_Column:
-				if doneColumnBackfill {
-					break
+				default:
+					return errors.Errorf("unsupported mutation: %+v", m)
 				}
-				if err := columnBackfillInTxn(ctx, txn, tc, evalCtx, immutDesc, traceKV); err != nil {
-					return err
-				}
-				doneColumnBackfill = true
 
-			case *sqlbase.DescriptorMutation_Index:
-				if err := indexTruncateInTxn(ctx, txn, execCfg, immutDesc, traceKV); err != nil {
-					return err
+			case sqlbase.DescriptorMutation_DROP:
+				// Drop the name and drop the associated data later.
+				switch t := m.Descriptor_.(type) {
+				case *sqlbase.DescriptorMutation_Column:
+					if doneColumnBackfill {
+						break
+					}
+					if err := columnBackfillInTxn(ctx, txn, tc, evalCtx, immutDesc, traceKV); err != nil {
+						return err
+					}
+					doneColumnBackfill = true
+
+				case *sqlbase.DescriptorMutation_Index:
+					if err := indexTruncateInTxn(ctx, txn, execCfg, immutDesc, t.Index, traceKV); err != nil {
+						return err
+					}
+
+				case *sqlbase.DescriptorMutation_ComputedColumnSwap:
+					// A rolled back swap requires no backfill.
+
+				default:
+					return errors.Errorf("unsupported mutation: %+v", m)
 				}
 
-			default:
-				return errors.Errorf("unsupported mutation: %+v", m)
 			}
-
-		}
-		if err := tableDesc.MakeMutationComplete(m); err != nil {
-			return err
+			if err := tableDesc.MakeMutationComplete(m); err != nil {
+				return err
+			}
 		}
+		tableDesc.Mutations = tableDesc.Mutations[numMutations:]
 	}
-	tableDesc.Mutations = nil
 
 	return nil
 }
@@ -630,10 +706,14 @@ func columnBackfillInTxn(
 }
 
 func indexBackfillInTxn(
-	ctx context.Context, txn *client.Txn, tableDesc *sqlbase.ImmutableTableDescriptor, traceKV bool,
+	ctx context.Context,
+	txn *client.Txn,
+	evalCtx *tree.EvalContext,
+	tableDesc *sqlbase.ImmutableTableDescriptor,
+	traceKV bool,
 ) error {
 	var backfiller backfill.IndexBackfiller
-	if err := backfiller.Init(tableDesc); err != nil {
+	if err := backfiller.Init(evalCtx, tableDesc); err != nil {
 		return err
 	}
 	sp := tableDesc.PrimaryIndexSpan()
@@ -648,15 +728,192 @@ func indexBackfillInTxn(
 	return nil
 }
 
+// backfillMaterializedView populates a materialized view that is being
+// added with the result of its query. The query is evaluated as of a single
+// timestamp and its result is written in chunks, each in its own
+// transaction. The schema change lease is held throughout so that no other
+// node populates the view at the same time. The view isn't public until the
+// backfill is done, so an interrupted backfill is simply started over.
+func (sc *SchemaChanger) backfillMaterializedView(
+	ctx context.Context, evalCtx *extendedEvalContext, table *sqlbase.TableDescriptor,
+) error {
+	lease, err := sc.AcquireLease(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := sc.ReleaseLease(ctx, lease); err != nil {
+			log.Warning(ctx, err)
+		}
+	}()
+
+	log.Infof(ctx, "Running backfill for materialized view %q, v=%d", table.Name, table.Version)
+
+	// Remove the rows written by an earlier attempt, if any.
+	tableDesc := sqlbase.NewImmutableTableDescriptor(*table)
+	sp := tableDesc.TableSpan()
+	if err := sc.db.DelRange(ctx, sp.Key, sp.EndKey); err != nil {
+		return err
+	}
+
+	readAsOf := sc.clock.Now()
+	chunkSize := sc.getChunkSize(materializedViewBackfillChunkSize)
+	return runMaterializedViewQuery(ctx, sc.execCfg, tableDesc, readAsOf, chunkSize,
+		func(rows []tree.Datums) error {
+			if err := sc.ExtendLease(ctx, &lease); err != nil {
+				return err
+			}
+			return sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+				return insertMaterializedViewRows(
+					ctx, txn, &evalCtx.EvalContext, tableDesc, rows, false, /* traceKV */
+				)
+			})
+		})
+}
+
+// runMaterializedViewQuery evaluates the query of a materialized view as of
+// the given timestamp and passes its result to fn in chunks of at most
+// chunkSize rows. The query runs in its own read-only transaction, which is
+// not retried, so fn never sees the same rows twice.
+func runMaterializedViewQuery(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	tableDesc *sqlbase.ImmutableTableDescriptor,
+	readAsOf hlc.Timestamp,
+	chunkSize int64,
+	fn func(rows []tree.Datums) error,
+) error {
+	stmt, err := parser.ParseOne(tableDesc.ViewQuery)
+	if err != nil {
+		return err
+	}
+	txn := client.NewTxn(ctx, execCfg.DB, execCfg.NodeID.Get(), client.RootTxn)
+	txn.SetFixedTimestamp(ctx, readAsOf)
+	p, cleanup := newInternalPlanner(
+		"materialized-view-backfill", txn, security.RootUser, &MemoryMetrics{}, execCfg,
+	)
+	defer cleanup()
+
+	if err := func() error {
+		planStmt := Statement{SQL: tableDesc.ViewQuery, AST: stmt}
+		optimizerPlanned, err := p.optionallyUseOptimizer(ctx, *p.SessionData(), planStmt)
+		if !optimizerPlanned && err == nil {
+			err = p.makePlan(ctx, planStmt)
+		}
+		defer p.curPlan.close(ctx)
+		if err != nil {
+			return err
+		}
+
+		chunk := make([]tree.Datums, 0, chunkSize)
+		rw := newCallbackResultWriter(func(ctx context.Context, row tree.Datums) error {
+			// The receiver reuses the row, so it has to be copied.
+			chunk = append(chunk, append(tree.Datums(nil), row...))
+			if int64(len(chunk)) < chunkSize {
+				return nil
+			}
+			err := fn(chunk)
+			chunk = chunk[:0]
+			return err
+		})
+		recv := MakeDistSQLReceiver(
+			ctx,
+			rw,
+			stmt.StatementType(),
+			execCfg.RangeDescriptorCache,
+			execCfg.LeaseHolderCache,
+			txn,
+			func(ts hlc.Timestamp) {
+				_ = execCfg.Clock.Update(ts)
+			},
+			p.ExtendedEvalContext().Tracing,
+		)
+		defer recv.Release()
+
+		evalCtx := p.ExtendedEvalContext()
+		planCtx := execCfg.DistSQLPlanner.NewPlanningCtx(ctx, evalCtx, txn)
+		planCtx.planner = p
+		planCtx.stmtType = recv.stmtType
+		if len(p.curPlan.subqueryPlans) != 0 {
+			evalCtxFactory := func() *extendedEvalContext {
+				subqueryEvalCtx := *evalCtx
+				return &subqueryEvalCtx
+			}
+			if !execCfg.DistSQLPlanner.PlanAndRunSubqueries(
+				ctx, p, evalCtxFactory, p.curPlan.subqueryPlans, recv, true, /* maybeDistribute */
+			) {
+				return rw.Err()
+			}
+		}
+		execCfg.DistSQLPlanner.PlanAndRun(ctx, evalCtx, planCtx, txn, p.curPlan.plan, recv)
+		if err := rw.Err(); err != nil {
+			return err
+		}
+		if len(chunk) > 0 {
+			return fn(chunk)
+		}
+		return nil
+	}(); err != nil {
+		txn.CleanupOnError(ctx, err)
+		return err
+	}
+	return txn.Commit(ctx)
+}
+
+// insertMaterializedViewRows writes rows produced by the query of a
+// materialized view. The columns of the query are followed by the hidden
+// primary key column of the view, which is filled in from its default
+// expression.
+func insertMaterializedViewRows(
+	ctx context.Context,
+	txn *client.Txn,
+	evalCtx *tree.EvalContext,
+	tableDesc *sqlbase.ImmutableTableDescriptor,
+	rows []tree.Datums,
+	traceKV bool,
+) error {
+	ri, err := row.MakeInserter(
+		txn, tableDesc, nil /* fkTables */, tableDesc.Columns, row.SkipFKs, evalCtx,
+		&sqlbase.DatumAlloc{},
+	)
+	if err != nil {
+		return err
+	}
+	defaultExprs, err := sqlbase.MakeDefaultExprs(
+		tableDesc.Columns, &transform.ExprTransformContext{}, evalCtx,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowVals := make(tree.Datums, len(tableDesc.Columns))
+	b := txn.NewBatch()
+	for _, viewRow := range rows {
+		copy(rowVals, viewRow)
+		for j := len(viewRow); j < len(rowVals); j++ {
+			if rowVals[j], err = defaultExprs[j].Eval(evalCtx); err != nil {
+				return err
+			}
+		}
+		if err := ri.InsertRow(ctx, b, rowVals, false /* overwrite */, row.SkipFKs, traceKV); err != nil {
+			return err
+		}
+	}
+	if err := txn.Run(ctx, b); err != nil {
+		return row.ConvertBatchError(ctx, tableDesc, b)
+	}
+	return nil
+}
+
 func indexTruncateInTxn(
 	ctx context.Context,
 	txn *client.Txn,
 	execCfg *ExecutorConfig,
 	tableDesc *sqlbase.ImmutableTableDescriptor,
+	idx *sqlbase.IndexDescriptor,
 	traceKV bool,
 ) error {
 	alloc := &sqlbase.DatumAlloc{}
-	idx := tableDesc.Mutations[0].GetIndex()
 	var sp roachpb.Span
 	for done := false; !done; done = sp.Key == nil {
 		rd, err := row.MakeDeleter(
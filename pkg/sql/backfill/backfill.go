@@ -123,6 +123,7 @@ func (cb *ColumnBackfiller) Init(
 		Cols:            desc.Columns,
 		ValNeededForCol: valNeededForCol,
 	}
+	cb.fetcher.SetEvalContext(cb.evalCtx)
 	return cb.fetcher.Init(
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &cb.alloc, tableArgs,
 	)
@@ -237,8 +238,11 @@ func (cb *ColumnBackfiller) RunColumnBackfillChunk(
 
 			// Added computed column values should be usable for the next
 			// added columns being backfilled. They have already been type
-			// checked.
+			// checked, but must still honor the column widths.
 			if j < len(cb.added) {
+				if val, err = sqlbase.LimitValueWidth(cb.added[j].Type, val, &cb.added[j].Name); err != nil {
+					return roachpb.Key{}, sqlbase.NewInvalidSchemaDefinitionError(err)
+				}
 				iv.CurSourceRow = append(iv.CurSourceRow, val)
 			}
 			updateValues[j] = val
@@ -302,10 +306,19 @@ type IndexBackfiller struct {
 
 	types   []sqlbase.ColumnType
 	rowVals tree.Datums
+
+	// predicates holds the predicates of the added partial indexes, parallel
+	// to added; it is nil if none of them are partial.
+	predicates []tree.TypedExpr
+	ivars      sqlbase.RowIndexedVarContainer
+	evalCtx    *tree.EvalContext
 }
 
 // Init initializes an IndexBackfiller.
-func (ib *IndexBackfiller) Init(desc *sqlbase.ImmutableTableDescriptor) error {
+func (ib *IndexBackfiller) Init(
+	evalCtx *tree.EvalContext, desc *sqlbase.ImmutableTableDescriptor,
+) error {
+	ib.evalCtx = evalCtx
 	numCols := len(desc.Columns)
 	cols := desc.Columns
 	if len(desc.Mutations) > 0 {
@@ -334,9 +347,22 @@ func (ib *IndexBackfiller) Init(desc *sqlbase.ImmutableTableDescriptor) error {
 					valNeededForCol.Add(i)
 				}
 			}
+			for _, colID := range idx.PredicateColumnIDs {
+				for i, col := range cols {
+					if col.ID == colID {
+						valNeededForCol.Add(i)
+					}
+				}
+			}
 		}
 	}
 
+	var err error
+	ib.predicates, err = sqlbase.MakePartialIndexExprs(ib.added, desc, ib.evalCtx)
+	if err != nil {
+		return err
+	}
+
 	ib.types = make([]sqlbase.ColumnType, len(cols))
 	for i := range cols {
 		ib.types[i] = cols[i].Type
@@ -346,6 +372,7 @@ func (ib *IndexBackfiller) Init(desc *sqlbase.ImmutableTableDescriptor) error {
 	for i, c := range cols {
 		ib.colIdxMap[c.ID] = i
 	}
+	ib.ivars = sqlbase.RowIndexedVarContainer{Cols: desc.Columns, Mapping: ib.colIdxMap}
 
 	tableArgs := row.FetcherTableArgs{
 		Desc:            desc,
@@ -354,6 +381,7 @@ func (ib *IndexBackfiller) Init(desc *sqlbase.ImmutableTableDescriptor) error {
 		Cols:            cols,
 		ValNeededForCol: valNeededForCol,
 	}
+	ib.fetcher.SetEvalContext(ib.evalCtx)
 	return ib.fetcher.Init(
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &ib.alloc, tableArgs,
 	)
@@ -386,7 +414,6 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 		return nil, nil, err
 	}
 
-	buffer := make([]sqlbase.IndexEntry, len(ib.added))
 	for i := int64(0); i < chunkSize; i++ {
 		encRow, _, _, err := ib.fetcher.NextRow(ctx)
 		if err != nil {
@@ -402,17 +429,27 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 			return nil, nil, err
 		}
 
-		// We're resetting the length of this slice for variable length indexes such as inverted
-		// indexes which can append entries to the end of the slice. If we don't do this, then everything
-		// EncodeSecondaryIndexes appends to secondaryIndexEntries for a row, would stay in the slice for
-		// subsequent rows and we would then have duplicates in entries on output.
-		buffer = buffer[:len(ib.added)]
-		if buffer, err = sqlbase.EncodeSecondaryIndexes(
-			tableDesc.TableDesc(), ib.added, ib.colIdxMap,
-			ib.rowVals, buffer); err != nil {
-			return nil, nil, err
+		// Each index is encoded separately, as inverted indexes can produce
+		// any number of entries for a row and only the rows satisfying the
+		// predicate of a partial index get an entry in it.
+		ib.ivars.CurSourceRow = ib.rowVals
+		for j := range ib.added {
+			if ib.predicates != nil && ib.predicates[j] != nil {
+				ok, err := sqlbase.EvalPartialIndexPredicate(ib.evalCtx, ib.predicates[j], &ib.ivars)
+				if err != nil {
+					return nil, nil, err
+				}
+				if !ok {
+					continue
+				}
+			}
+			indexEntries, err := sqlbase.EncodeSecondaryIndex(
+				tableDesc.TableDesc(), &ib.added[j], ib.colIdxMap, ib.rowVals)
+			if err != nil {
+				return nil, nil, err
+			}
+			entries = append(entries, indexEntries...)
 		}
-		entries = append(entries, buffer...)
 	}
 	return entries, ib.fetcher.Key(), nil
 }
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -35,6 +37,22 @@ func (p *planner) validateCheckExpr(
 	if err != nil {
 		return err
 	}
+	row, err := p.findCheckViolation(ctx, expr, tableName, tableDesc)
+	if err != nil {
+		return err
+	}
+	if row != nil {
+		return errors.Errorf("validation of CHECK %q failed on row: %s",
+			expr.String(), labeledRowValues(tableDesc.Columns, row))
+	}
+	return nil
+}
+
+// findCheckViolation returns a row of the table which violates the check
+// expression, or nil if there is none.
+func (p *planner) findCheckViolation(
+	ctx context.Context, expr tree.Expr, tableName tree.TableExpr, tableDesc *sqlbase.TableDescriptor,
+) (tree.Datums, error) {
 	sel := &tree.SelectClause{
 		Exprs: sqlbase.ColumnsSelectors(tableDesc.Columns, false /* forUpdateOrDelete */),
 		From:  &tree.From{Tables: tree.TableExprs{tableName}},
@@ -47,11 +65,11 @@ func (p *planner) validateCheckExpr(
 	// complexity seems unjustified.
 	rows, err := p.SelectClause(ctx, sel, nil, lim, nil, nil, publicColumns)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	rows, err = p.optimizePlan(ctx, rows, allColumns(rows))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer rows.Close(ctx)
 
@@ -61,17 +79,67 @@ func (p *planner) validateCheckExpr(
 		p:               p,
 	}
 	if err := startPlan(params, rows); err != nil {
-		return err
+		return nil, err
 	}
 	next, err := rows.Next(params)
+	if err != nil {
+		return nil, err
+	}
+	if !next {
+		return nil, nil
+	}
+	return append(tree.Datums(nil), rows.Values()...), nil
+}
+
+// validateCheckInTxn validates a check constraint of the table against the
+// existing data in the given transaction. It is used by the schema changer
+// to validate the check constraints of check mutations.
+func validateCheckInTxn(
+	ctx context.Context,
+	txn *client.Txn,
+	execCfg *ExecutorConfig,
+	tableDesc *sqlbase.TableDescriptor,
+	ck *sqlbase.TableDescriptor_CheckConstraint,
+) error {
+	p, cleanup := newInternalPlanner(
+		"validate-check", txn, security.RootUser, &MemoryMetrics{}, execCfg,
+	)
+	defer cleanup()
+	return p.validateCheck(ctx, tableDesc, ck)
+}
+
+// validateCheck validates a check constraint of the table against the
+// existing data. Unlike validateCheckExpr, it refers to the table by ID and
+// reports a NOT NULL check constraint as such.
+func (p *planner) validateCheck(
+	ctx context.Context,
+	tableDesc *sqlbase.TableDescriptor,
+	ck *sqlbase.TableDescriptor_CheckConstraint,
+) error {
+	expr, err := parser.ParseExpr(ck.Expr)
 	if err != nil {
 		return err
 	}
-	if next {
-		return errors.Errorf("validation of CHECK %q failed on row: %s",
-			expr.String(), labeledRowValues(tableDesc.Columns, rows.Values()))
+	tableRef := &tree.TableRef{
+		TableID: int64(tableDesc.ID),
+		As:      tree.AliasClause{Alias: tree.Name(tableDesc.Name)},
 	}
-	return nil
+	row, err := p.findCheckViolation(ctx, expr, tableRef, tableDesc)
+	if err != nil || row == nil {
+		return err
+	}
+	if ck.IsNonNullConstraint && len(ck.ColumnIDs) == 1 {
+		col, err := tableDesc.FindActiveColumnByID(ck.ColumnIDs[0])
+		if err != nil {
+			return err
+		}
+		return pgerror.NewErrorf(pgerror.CodeNotNullViolationError,
+			"validation of NOT NULL constraint of column %q failed on row: %s",
+			col.Name, labeledRowValues(tableDesc.Columns, row))
+	}
+	return pgerror.NewErrorf(pgerror.CodeCheckViolationError,
+		"validation of CHECK %q failed on row: %s",
+		expr.String(), labeledRowValues(tableDesc.Columns, row))
 }
 
 func (p *planner) validateForeignKey(
@@ -94,6 +94,11 @@ var (
 	// JSON is an immutable T instance.
 	JSON = &TJSON{}
 
+	// TSVector is an immutable T instance.
+	TSVector = &TTSVector{}
+	// TSQuery is an immutable T instance.
+	TSQuery = &TTSQuery{}
+
 	// Oid is an immutable T instance.
 	Oid = &TOid{Name: "OID"}
 	// RegClass is an immutable T instance.
@@ -195,8 +200,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"pg_lsn":        -1,
 	"point":         21286,
 	"polygon":       21286,
-	"tsquery":       7821,
-	"tsvector":      7821,
 	"txid_snapshot": -1,
 	"xml":           -1,
 }
@@ -52,7 +52,7 @@ func (node *TArray) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
 // element type for an array column type.
 func canBeInArrayColType(t T) bool {
 	switch t.(type) {
-	case *TJSON:
+	case *TJSON, *TTSVector, *TTSQuery, *TUserDefined, *TEnum:
 		return false
 	default:
 		return true
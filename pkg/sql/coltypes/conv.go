@@ -86,6 +86,10 @@ func DatumTypeToColumnType(t types.T) (T, error) {
 		return Interval, nil
 	case types.JSON:
 		return JSON, nil
+	case types.TSVector:
+		return TSVector, nil
+	case types.TSQuery:
+		return TSQuery, nil
 	case types.UUID:
 		return UUID, nil
 	case types.INet:
@@ -131,6 +135,8 @@ func DatumTypeToColumnType(t types.T) (T, error) {
 			colTyp[i] = elemTyp
 		}
 		return colTyp, nil
+	case types.TEnum:
+		return &TEnum{Typ: typ}, nil
 	case types.TOidWrapper:
 		return DatumTypeToColumnType(typ.T)
 	}
@@ -180,6 +186,10 @@ func CastTargetToDatumType(t CastTargetType) types.T {
 		return types.Interval
 	case *TJSON:
 		return types.JSON
+	case *TTSVector:
+		return types.TSVector
+	case *TTSQuery:
+		return types.TSQuery
 	case *TUUID:
 		return types.UUID
 	case *TIPAddr:
@@ -205,6 +215,8 @@ func CastTargetToDatumType(t CastTargetType) types.T {
 		return ret
 	case *TOid:
 		return TOidToType(ct)
+	case *TEnum:
+		return ct.Typ
 	default:
 		panic(fmt.Sprintf("unexpected CastTarget %T", t))
 	}
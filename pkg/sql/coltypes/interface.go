@@ -64,6 +64,7 @@ func (*TBytes) columnType()          {}
 func (*TCollatedString) columnType() {}
 func (*TDate) columnType()           {}
 func (*TDecimal) columnType()        {}
+func (*TEnum) columnType()           {}
 func (*TFloat) columnType()          {}
 func (*TIPAddr) columnType()         {}
 func (*TInt) columnType()            {}
@@ -76,7 +77,10 @@ func (*TString) columnType()         {}
 func (*TTime) columnType()           {}
 func (*TTimestamp) columnType()      {}
 func (*TTimestampTZ) columnType()    {}
+func (*TTSQuery) columnType()        {}
+func (*TTSVector) columnType()       {}
 func (*TUUID) columnType()           {}
+func (*TUserDefined) columnType()    {}
 func (*TVector) columnType()         {}
 func (TTuple) columnType()           {}
 
@@ -88,6 +92,7 @@ func (*TBytes) castTargetType()          {}
 func (*TCollatedString) castTargetType() {}
 func (*TDate) castTargetType()           {}
 func (*TDecimal) castTargetType()        {}
+func (*TEnum) castTargetType()           {}
 func (*TFloat) castTargetType()          {}
 func (*TIPAddr) castTargetType()         {}
 func (*TInt) castTargetType()            {}
@@ -100,7 +105,10 @@ func (*TString) castTargetType()         {}
 func (*TTime) castTargetType()           {}
 func (*TTimestamp) castTargetType()      {}
 func (*TTimestampTZ) castTargetType()    {}
+func (*TTSQuery) castTargetType()        {}
+func (*TTSVector) castTargetType()       {}
 func (*TUUID) castTargetType()           {}
+func (*TUserDefined) castTargetType()    {}
 func (*TVector) castTargetType()         {}
 func (TTuple) castTargetType()           {}
 
@@ -111,6 +119,7 @@ func (node *TBytes) String() string          { return ColTypeAsString(node) }
 func (node *TCollatedString) String() string { return ColTypeAsString(node) }
 func (node *TDate) String() string           { return ColTypeAsString(node) }
 func (node *TDecimal) String() string        { return ColTypeAsString(node) }
+func (node *TEnum) String() string           { return ColTypeAsString(node) }
 func (node *TFloat) String() string          { return ColTypeAsString(node) }
 func (node *TIPAddr) String() string         { return ColTypeAsString(node) }
 func (node *TInt) String() string            { return ColTypeAsString(node) }
@@ -123,6 +132,9 @@ func (node *TString) String() string         { return ColTypeAsString(node) }
 func (node *TTime) String() string           { return ColTypeAsString(node) }
 func (node *TTimestamp) String() string      { return ColTypeAsString(node) }
 func (node *TTimestampTZ) String() string    { return ColTypeAsString(node) }
+func (node *TTSQuery) String() string        { return ColTypeAsString(node) }
+func (node *TTSVector) String() string       { return ColTypeAsString(node) }
 func (node *TUUID) String() string           { return ColTypeAsString(node) }
+func (node *TUserDefined) String() string    { return ColTypeAsString(node) }
 func (node *TVector) String() string         { return ColTypeAsString(node) }
 func (node TTuple) String() string           { return ColTypeAsString(node) }
@@ -0,0 +1,64 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package coltypes
+
+import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
+
+// TUserDefined represents a reference by name to a user-defined type. It
+// is produced by the parser for type names that are not built in, and must
+// be resolved to a concrete column type (e.g. TEnum) before it can be used.
+type TUserDefined struct {
+	Name string
+}
+
+// TypeName implements the ColTypeFormatter interface.
+func (node *TUserDefined) TypeName() string { return node.Name }
+
+// Format implements the ColTypeFormatter interface.
+func (node *TUserDefined) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
+	formatUserDefinedTypeName(buf, node.Name, f)
+}
+
+// TEnum represents a user-defined ENUM type.
+type TEnum struct {
+	Typ types.TEnum
+}
+
+// TypeName implements the ColTypeFormatter interface.
+func (node *TEnum) TypeName() string { return node.Typ.Name }
+
+// Format implements the ColTypeFormatter interface.
+func (node *TEnum) Format(buf *bytes.Buffer, f lex.EncodeFlags) {
+	formatUserDefinedTypeName(buf, node.Typ.Name, f)
+}
+
+// formatUserDefinedTypeName formats the name of a user-defined type such
+// that it is parsed back as a type name. The grammar only recognizes
+// identifiers that are not keywords in type position, so keywords are
+// always quoted.
+func formatUserDefinedTypeName(buf *bytes.Buffer, name string, f lex.EncodeFlags) {
+	if _, ok := lex.Keywords[name]; ok && !f.HasFlags(lex.EncBareIdentifiers) {
+		buf.WriteByte('"')
+		buf.WriteString(name)
+		buf.WriteByte('"')
+		return
+	}
+	lex.EncodeRestrictedSQLIdent(buf, name, f)
+}
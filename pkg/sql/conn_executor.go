@@ -522,6 +522,10 @@ func (s *Server) newConnExecutor(
 	ex.sessionTracing.ex = ex
 	ex.transitionCtx.sessionTracing = &ex.sessionTracing
 
+	if sender, ok := clientComm.(NotificationSender); ok && s.cfg.NotificationRegistry != nil {
+		ex.notificationListener = s.cfg.NotificationRegistry.newListener(sender)
+	}
+
 	return ex, nil
 }
 
@@ -669,6 +673,9 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	_ = ex.synchronizeParallelStmts(ctx)
 
 	if closeType == normalClose {
+		// An aborted txn might have kept its KV txn open for ROLLBACK TO SAVEPOINT;
+		// the event below does not roll it back.
+		ex.rollbackAbortedKVTxn(ex.state.Ctx)
 		// We'll cleanup the SQL txn by creating a non-retriable (commit:true) event.
 		// This event is guaranteed to be accepted in every state.
 		ev := eventNonRetriableErr{IsCommit: fsm.FromBool(true)}
@@ -685,6 +692,15 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	}
 
 	if closeType != panicClose {
+		// Drop the temporary tables of the session, if it created any.
+		if ex.sessionData.SearchPath.GetTemporarySchemaName() != "" {
+			if err := cleanupSessionTemporarySchemas(
+				ctx, ex.server.cfg.DB, ex.server.cfg.InternalExecutor, ex.sessionID,
+			); err != nil {
+				log.Warningf(ctx, "error cleaning up temporary schemas: %s", err)
+			}
+		}
+
 		// Close all statements and prepared portals by first unifying the namespaces
 		// and the closing what remains.
 		ex.commitPrepStmtNamespace(ctx)
@@ -702,6 +718,10 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		ex.eventLog = nil
 	}
 
+	if ex.notificationListener != nil {
+		ex.notificationListener.unlistenAll()
+	}
+
 	if closeType != panicClose {
 		ex.state.mon.Stop(ctx)
 		ex.sessionMon.Stop(ctx)
@@ -754,6 +774,10 @@ type connExecutor struct {
 	// if traceSessionEventLogEnabled; it is used by ex.sessionEventf()
 	eventLog trace.EventLog
 
+	// notificationListener tracks the channels the session listens on. It is
+	// nil if the clientComm cannot deliver notifications.
+	notificationListener *notificationListener
+
 	// stmtCounterDisabled, if set, makes this connExecutor not contribute to
 	// statement counter metrics and to "statement summary" stats. This is used by
 	// "internal" SQL executors.
@@ -969,6 +993,16 @@ func (ex *connExecutor) resetExtraTxnState(
 	ex.extraTxnState.tables.databaseCache = dbCacheHolder.getDatabaseCache()
 
 	ex.extraTxnState.autoRetryCounter = 0
+
+	// The savepoints are created again by the statements that are going to be
+	// retried.
+	ex.state.savepoints = nil
+	ex.state.numDDL = 0
+
+	// Drop any constraint checks left queued by statements that are going to be
+	// retried or by a transaction that is over.
+	ex.state.takeDeferredConstraintChecks()
+	ex.state.takeStatementConstraintChecks()
 	return nil
 }
 
@@ -1684,6 +1718,16 @@ func (ex *connExecutor) setTransactionModes(modes tree.TransactionModes) error {
 	return ex.state.setReadOnlyMode(modes.ReadWriteMode)
 }
 
+// setConstraintsMode implements the txnModesSetter interface.
+func (ex *connExecutor) setConstraintsMode(
+	ctx context.Context, names []string, deferred bool,
+) error {
+	// Checks queued for constraints that become immediate are run right away,
+	// as if the constraints had been immediate all along.
+	return runDeferredConstraintChecks(
+		ctx, ex.state.mu.txn, ex.state.setConstraintsMode(names, deferred))
+}
+
 func priorityToProto(mode tree.UserPriority) (roachpb.UserPriority, error) {
 	var pri roachpb.UserPriority
 	switch mode {
@@ -1744,6 +1788,9 @@ func (ex *connExecutor) evalCtx(
 			Sequence:      p,
 			StmtTimestamp: stmtTS,
 
+			ConstraintDeferrer: &ex.state,
+			Notifier:           p,
+
 			Txn:              txn,
 			SessionData:      &ex.sessionData,
 			TxnState:         ex.getTransactionState(),
@@ -1760,6 +1807,7 @@ func (ex *connExecutor) evalCtx(
 			InternalExecutor: &ie,
 		},
 		SessionMutator:  &ex.dataMutator,
+		SessionID:       ex.sessionID,
 		VirtualSchemas:  ex.server.cfg.VirtualSchemas,
 		Tracing:         &ex.sessionTracing,
 		StatusServer:    ex.server.cfg.StatusServer,
@@ -1770,6 +1818,8 @@ func (ex *connExecutor) evalCtx(
 		TxnModesSetter:  ex,
 		SchemaChangers:  &ex.extraTxnState.schemaChangers,
 		schemaAccessors: scInterface,
+
+		NotificationListener: ex.notificationListener,
 	}
 }
 
@@ -1825,6 +1875,8 @@ func (ex *connExecutor) resetPlanner(
 	p.semaCtx.Location = &ex.sessionData.DataConversion.Location
 	p.semaCtx.SearchPath = ex.sessionData.SearchPath
 	p.semaCtx.AsOfTimestamp = nil
+	p.semaCtx.TypeResolver = p
+	p.semaCtx.FunctionResolver = p
 
 	p.extendedEvalCtx = ex.evalCtx(ctx, p, stmtTS)
 	p.extendedEvalCtx.ClusterID = ex.server.cfg.ClusterID()
@@ -40,7 +40,8 @@ import (
 	"github.com/pkg/errors"
 )
 
-// RestartSavepointName is the only savepoint ident that we accept.
+// RestartSavepointName is the savepoint ident used for client-directed
+// retries. Savepoints with other names are regular savepoints.
 const RestartSavepointName string = "cockroach_restart"
 
 var errSavepointNotUsed = pgerror.NewErrorf(
@@ -223,6 +224,12 @@ func (ex *connExecutor) execStmtInOpenState(
 		return ev, payload, nil
 
 	case *tree.ReleaseSavepoint:
+		if !ex.isRestartSavepoint(s.Savepoint) {
+			if err := ex.releaseSavepoint(s.Savepoint); err != nil {
+				return makeErrEvent(err)
+			}
+			return nil, nil, nil
+		}
 		if err := ex.validateSavepointName(s.Savepoint); err != nil {
 			return makeErrEvent(err)
 		}
@@ -241,6 +248,13 @@ func (ex *connExecutor) execStmtInOpenState(
 		return ev, payload, nil
 
 	case *tree.Savepoint:
+		if !ex.isRestartSavepoint(s.Name) {
+			// Note that Savepoint doesn't have a corresponding plan node.
+			if err := ex.createSavepoint(ctx, s.Name); err != nil {
+				return makeErrEvent(err)
+			}
+			return nil, nil, nil
+		}
 		// Ensure that the user isn't trying to run BEGIN; SAVEPOINT; SAVEPOINT;
 		if ex.state.activeSavepointName != "" {
 			err := fmt.Errorf("SAVEPOINT may not be nested")
@@ -268,6 +282,12 @@ func (ex *connExecutor) execStmtInOpenState(
 		return eventRetryIntentSet{}, nil /* payload */, nil
 
 	case *tree.RollbackToSavepoint:
+		if !ex.isRestartSavepoint(s.Savepoint) {
+			if err := ex.rollbackToSavepoint(ctx, s.Savepoint); err != nil {
+				return makeErrEvent(err)
+			}
+			return nil, nil, nil
+		}
 		if err := ex.validateSavepointName(s.Savepoint); err != nil {
 			return makeErrEvent(err)
 		}
@@ -292,7 +312,16 @@ func (ex *connExecutor) execStmtInOpenState(
 		}
 		typeHints := make(tree.PlaceholderTypes, len(s.Types))
 		for i, t := range s.Types {
-			typeHints[strconv.Itoa(i+1)] = coltypes.CastTargetToDatumType(t)
+			castType := coltypes.CastTargetType(t)
+			if _, ok := t.(*coltypes.TUserDefined); ok {
+				p := &ex.planner
+				ex.resetPlanner(ctx, p, ex.state.mu.txn, ex.server.cfg.Clock.PhysicalTime())
+				var err error
+				if castType, err = p.semaCtx.ResolveCastTargetType(t); err != nil {
+					return makeErrEvent(err)
+				}
+			}
+			typeHints[strconv.Itoa(i+1)] = coltypes.CastTargetToDatumType(castType)
 		}
 		if _, err := ex.addPreparedStmt(
 			ctx, name, Statement{SQL: s.Statement.String(), AST: s.Statement}, typeHints,
@@ -335,6 +364,10 @@ func (ex *connExecutor) execStmtInOpenState(
 	// For regular statements (the ones that get to this point), we don't return
 	// any event unless an an error happens.
 
+	if stmt.AST.StatementType() == tree.DDL {
+		ex.state.numDDL++
+	}
+
 	var p *planner
 	stmtTS := ex.server.cfg.Clock.PhysicalTime()
 	// Only run statements asynchronously through the parallelize queue if the
@@ -421,6 +454,15 @@ func (ex *connExecutor) execStmtInOpenState(
 			return makeErrEvent(err)
 		}
 
+		// Run the checks of the constraints that are not deferred until the
+		// end of the transaction, like those of deferrable UNIQUE constraints
+		// that are checked once all the rows of the statement are written.
+		if err := runDeferredConstraintChecks(
+			ctx, ex.state.mu.txn, ex.state.takeStatementConstraintChecks(),
+		); err != nil {
+			return makeErrEvent(err)
+		}
+
 		txn := ex.state.mu.txn
 		if !os.ImplicitTxn.Get() && txn.IsSerializablePushAndRefreshNotPossible() {
 			rc, canAutoRetry := ex.getRewindTxnCapability()
@@ -590,6 +632,16 @@ func (ex *connExecutor) commitSQLTransaction(
 		isRelease = true
 	}
 
+	// Run the constraint checks that were deferred until the end of the
+	// transaction. The checks of the last statement of an implicit
+	// transaction are still pending when the statement's writes were not
+	// committed in the same batch.
+	checks := append(
+		ex.state.takeStatementConstraintChecks(), ex.state.takeDeferredConstraintChecks()...)
+	if err := runDeferredConstraintChecks(ctx, ex.state.mu.txn, checks); err != nil {
+		return ex.makeErrEvent(err, stmt)
+	}
+
 	if err := ex.checkTableTwoVersionInvariant(ctx); err != nil {
 		return ex.makeErrEvent(err, stmt)
 	}
@@ -1067,8 +1119,10 @@ func (ex *connExecutor) execStmtInNoTxnState(
 // execStmtInAbortedState executes a statement in a txn that's in state
 // Aborted or RestartWait. All statements result in error events except:
 // - COMMIT / ROLLBACK: aborts the current transaction.
-// - ROLLBACK TO SAVEPOINT / SAVEPOINT: reopens the current transaction,
-//   allowing it to be retried.
+// - ROLLBACK TO SAVEPOINT / SAVEPOINT cockroach_restart: reopens the current
+//   transaction, allowing it to be retried.
+// - ROLLBACK TO SAVEPOINT of a regular savepoint: rolls back the error along
+//   with the savepoint, moving the transaction back to Open.
 func (ex *connExecutor) execStmtInAbortedState(
 	ctx context.Context, stmt Statement, res RestrictedCommandResult,
 ) (fsm.Event, fsm.EventPayload) {
@@ -1082,12 +1136,23 @@ func (ex *connExecutor) execStmtInAbortedState(
 			return ev, payload
 		}
 		ex.state.activeSavepointName = ""
+		ex.rollbackAbortedKVTxn(ctx)
 
 		// Note: Postgres replies to COMMIT of failed txn with "ROLLBACK" too.
 		res.ResetStmtType((*tree.RollbackTransaction)(nil))
 
 		return eventTxnFinish{}, eventTxnFinishPayload{commit: false}
 	case *tree.RollbackToSavepoint, *tree.Savepoint:
+		if n, ok := s.(*tree.RollbackToSavepoint); ok && !ex.isRestartSavepoint(n.Savepoint) {
+			return ex.rollbackToSavepointInAbortedState(ctx, n.Savepoint)
+		}
+		if n, ok := s.(*tree.Savepoint); ok && !ex.isRestartSavepoint(n.Name) {
+			ev := eventNonRetriableErr{IsCommit: fsm.False}
+			payload := eventNonRetriableErrPayload{
+				err: sqlbase.NewTransactionAbortedError("" /* customMsg */),
+			}
+			return ev, payload
+		}
 		// We accept both the "ROLLBACK TO SAVEPOINT cockroach_restart" and the
 		// "SAVEPOINT cockroach_restart" commands to indicate client intent to
 		// retry a transaction in a RestartWait state.
@@ -1142,6 +1207,7 @@ func (ex *connExecutor) execStmtInAbortedState(
 		// state, so this is consistent.
 		// We start a new txn with the same sql timestamp and isolation as the
 		// current one.
+		ex.rollbackAbortedKVTxn(ctx)
 
 		ev := eventTxnStart{
 			ImplicitTxn: fsm.False,
@@ -1376,24 +1442,12 @@ func (ex *connExecutor) incrementStmtCounter(stmt Statement) {
 	}
 }
 
-// validateSavepointName validates that it is that the provided ident
-// matches the active savepoint name, begins with RestartSavepointName,
-// or that force_savepoint_restart==true. We accept everything with the
-// desired prefix because at least the C++ libpqxx appends sequence
-// numbers to the savepoint name specified by the user.
+// validateSavepointName validates that the provided restart savepoint ident
+// (see isRestartSavepoint) matches the active savepoint name, if any.
 func (ex *connExecutor) validateSavepointName(savepoint tree.Name) error {
-	if ex.state.activeSavepointName != "" {
-		if savepoint == ex.state.activeSavepointName {
-			return nil
-		}
+	if ex.state.activeSavepointName != "" && savepoint != ex.state.activeSavepointName {
 		return pgerror.NewErrorf(pgerror.CodeInvalidSavepointSpecificationError,
 			`SAVEPOINT %q is in use`, tree.ErrString(&ex.state.activeSavepointName))
 	}
-	if !ex.sessionData.ForceSavepointRestart && !strings.HasPrefix(string(savepoint), RestartSavepointName) {
-		return pgerror.UnimplementedWithIssueHintError(10735,
-			"SAVEPOINT not supported except for "+RestartSavepointName,
-			"Retryable transactions with arbitrary SAVEPOINT names can be enabled "+
-				"with SET force_savepoint_restart=true")
-	}
 	return nil
 }
@@ -0,0 +1,119 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// isRestartSavepoint returns whether the savepoint with the given name is used
+// for client-directed retries (see RestartSavepointName), as opposed to a
+// regular savepoint. We accept everything with the RestartSavepointName
+// prefix because at least the C++ libpqxx appends sequence numbers to the
+// savepoint name specified by the user.
+func (ex *connExecutor) isRestartSavepoint(name tree.Name) bool {
+	return ex.sessionData.ForceSavepointRestart ||
+		strings.HasPrefix(string(name), RestartSavepointName)
+}
+
+// createSavepoint executes a SAVEPOINT statement for a regular savepoint.
+func (ex *connExecutor) createSavepoint(ctx context.Context, name tree.Name) error {
+	token, err := ex.state.mu.txn.CreateSavepoint(ctx)
+	if err != nil {
+		return err
+	}
+	ex.state.savepoints = append(ex.state.savepoints, sqlSavepoint{
+		name:           name,
+		kvToken:        token,
+		numDDL:         ex.state.numDDL,
+		deferredChecks: ex.state.copyDeferredConstraintChecks(),
+	})
+	return nil
+}
+
+// releaseSavepoint executes a RELEASE SAVEPOINT statement for a regular
+// savepoint. The savepoint is removed, along with the savepoints created after
+// it; the writes performed after it are kept.
+func (ex *connExecutor) releaseSavepoint(name tree.Name) error {
+	idx := ex.state.findSavepoint(name)
+	if idx < 0 {
+		return newSavepointDoesNotExistError(name)
+	}
+	ex.state.savepoints = ex.state.savepoints[:idx]
+	return nil
+}
+
+// rollbackToSavepoint executes a ROLLBACK TO SAVEPOINT statement for a regular
+// savepoint. The writes performed after the savepoint are discarded and the
+// savepoints created after it are removed. The savepoint itself remains, so it
+// can be rolled back to again.
+func (ex *connExecutor) rollbackToSavepoint(ctx context.Context, name tree.Name) error {
+	idx := ex.state.findSavepoint(name)
+	if idx < 0 {
+		return newSavepointDoesNotExistError(name)
+	}
+	sp := &ex.state.savepoints[idx]
+	if sp.numDDL != ex.state.numDDL {
+		// The descriptors modified by the transaction are not versioned along
+		// with the savepoints, so the schema changes cannot be rolled back.
+		return pgerror.UnimplementedWithIssueError(10735,
+			"ROLLBACK TO SAVEPOINT not supported after schema changes")
+	}
+	if err := ex.state.mu.txn.RollbackToSavepoint(ctx, sp.kvToken); err != nil {
+		return err
+	}
+	ex.state.resetDeferredConstraintChecks(sp.deferredChecks)
+	ex.state.savepoints = ex.state.savepoints[:idx+1]
+	return nil
+}
+
+// rollbackToSavepointInAbortedState executes a ROLLBACK TO SAVEPOINT
+// statement for a regular savepoint in the Aborted state. The error that
+// aborted the transaction is rolled back along with the savepoint, and the
+// transaction moves back to the Open state.
+func (ex *connExecutor) rollbackToSavepointInAbortedState(
+	ctx context.Context, name tree.Name,
+) (fsm.Event, fsm.EventPayload) {
+	if err := ex.rollbackToSavepoint(ctx, name); err != nil {
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{err: err}
+		return ev, payload
+	}
+	return eventSavepointRollback{}, nil
+}
+
+// rollbackAbortedKVTxn rolls back the KV txn of a SQL txn in the Aborted
+// state if the KV txn was kept open because the SQL txn had savepoints (see
+// abortExplicitTxn).
+func (ex *connExecutor) rollbackAbortedKVTxn(ctx context.Context) {
+	if _, ok := ex.machine.CurState().(stateAborted); !ok || len(ex.state.savepoints) == 0 {
+		return
+	}
+	ex.state.savepoints = nil
+	if err := ex.state.mu.txn.Rollback(ctx); err != nil {
+		log.Warningf(ctx, "txn rollback failed: %s", err)
+	}
+}
+
+func newSavepointDoesNotExistError(name tree.Name) error {
+	return pgerror.NewErrorf(pgerror.CodeInvalidSavepointSpecificationError,
+		"savepoint %s does not exist", tree.ErrString(&name))
+}
@@ -211,18 +211,25 @@ var _ payloadWithError = eventRetriableErrPayload{}
 // cockroach_restart. It moves the state to CommitWait.
 type eventTxnReleased struct{}
 
+// eventSavepointRollback is generated by a ROLLBACK TO SAVEPOINT of a regular
+// (i.e. not cockroach_restart) savepoint in the Aborted state. The error that
+// aborted the transaction was rolled back along with the savepoint, so the
+// transaction moves back to Open.
+type eventSavepointRollback struct{}
+
 // payloadWithError is a common interface for the payloads that wrap an error.
 type payloadWithError interface {
 	errorCause() error
 }
 
-func (eventRetryIntentSet) Event()  {}
-func (eventTxnStart) Event()        {}
-func (eventTxnFinish) Event()       {}
-func (eventTxnRestart) Event()      {}
-func (eventNonRetriableErr) Event() {}
-func (eventRetriableErr) Event()    {}
-func (eventTxnReleased) Event()     {}
+func (eventRetryIntentSet) Event()    {}
+func (eventTxnStart) Event()          {}
+func (eventTxnFinish) Event()         {}
+func (eventTxnRestart) Event()        {}
+func (eventNonRetriableErr) Event()   {}
+func (eventRetriableErr) Event()      {}
+func (eventTxnReleased) Event()       {}
+func (eventSavepointRollback) Event() {}
 
 // TxnStateTransitions describe the transitions used by a connExecutor's
 // fsm.Machine. Args.Extended is a txnState, which is muted by the Actions.
@@ -325,14 +332,8 @@ var TxnStateTransitions = Compile(Pattern{
 	// Handle the errors in explicit txns. They move us to Aborted.
 	stateOpen{ImplicitTxn: False, RetryIntent: Var("retryIntent")}: {
 		eventNonRetriableErr{IsCommit: False}: {
-			Next: stateAborted{RetryIntent: Var("retryIntent")},
-			Action: func(args Args) error {
-				ts := args.Extended.(*txnState)
-				ts.mu.txn.CleanupOnError(ts.Ctx, args.Payload.(payloadWithError).errorCause())
-				ts.setAdvanceInfo(skipBatch, noRewind, txnAborted)
-				ts.txnAbortCount.Inc(1)
-				return nil
-			},
+			Next:   stateAborted{RetryIntent: Var("retryIntent")},
+			Action: abortExplicitTxn,
 		},
 		// SAVEPOINT cockroach_restart: we just change the state (RetryIntent) if it
 		// wasn't set already.
@@ -353,13 +354,7 @@ var TxnStateTransitions = Compile(Pattern{
 		eventRetriableErr{CanAutoRetry: False, IsCommit: False}: {
 			Description: "RetryIntent not set, so handled like non-retriable err",
 			Next:        stateAborted{RetryIntent: False},
-			Action: func(args Args) error {
-				ts := args.Extended.(*txnState)
-				ts.mu.txn.CleanupOnError(ts.Ctx, args.Payload.(payloadWithError).errorCause())
-				ts.setAdvanceInfo(skipBatch, noRewind, txnAborted)
-				ts.txnAbortCount.Inc(1)
-				return nil
-			},
+			Action:      abortExplicitTxn,
 		},
 	},
 	stateOpen{ImplicitTxn: False, RetryIntent: True}: {
@@ -423,6 +418,14 @@ var TxnStateTransitions = Compile(Pattern{
 				return nil
 			},
 		},
+		eventSavepointRollback{}: {
+			Description: "ROLLBACK TO SAVEPOINT (not cockroach_restart)",
+			Next:        stateOpen{ImplicitTxn: False, RetryIntent: Var("retryIntent")},
+			Action: func(args Args) error {
+				args.Extended.(*txnState).setAdvanceInfo(advanceOne, noRewind, noEvent)
+				return nil
+			},
+		},
 	},
 	stateAborted{RetryIntent: True}: {
 		// ROLLBACK TO SAVEPOINT. We accept this in the Aborted state for the
@@ -513,6 +516,22 @@ var TxnStateTransitions = Compile(Pattern{
 	},
 })
 
+// abortExplicitTxn moves an explicit txn to the Aborted state after an error.
+// The KV txn is rolled back, unless the SQL txn has savepoints; in that case
+// the KV txn is kept open, since a ROLLBACK TO SAVEPOINT can roll back the
+// error and move the txn back to Open.
+func abortExplicitTxn(args Args) error {
+	ts := args.Extended.(*txnState)
+	if len(ts.savepoints) > 0 {
+		ts.setAdvanceInfo(skipBatch, noRewind, noEvent)
+		return nil
+	}
+	ts.mu.txn.CleanupOnError(ts.Ctx, args.Payload.(payloadWithError).errorCause())
+	ts.setAdvanceInfo(skipBatch, noRewind, txnAborted)
+	ts.txnAbortCount.Inc(1)
+	return nil
+}
+
 // cleanupAndFinish rolls back the KV txn and finishes the SQL txn.
 func cleanupAndFinish(args Args) error {
 	ts := args.Extended.(*txnState)
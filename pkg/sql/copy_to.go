@@ -0,0 +1,102 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// CopyTo implements the COPY ... TO STDOUT statement.
+// See https://www.postgresql.org/docs/10/static/sql-copy.html.
+//
+// The statement is planned like the query it exports. The rows it produces
+// are encoded as CopyData messages by the pgwire layer, which recognizes the
+// statement when the result is created.
+func (p *planner) CopyTo(ctx context.Context, n *tree.CopyTo) (planNode, error) {
+	if err := validateCopyOptions(&n.Options); err != nil {
+		return nil, err
+	}
+	if n.Query != nil {
+		return p.newPlan(ctx, n.Query, nil /* desiredTypes */)
+	}
+
+	sel := &tree.SelectClause{
+		From: &tree.From{Tables: tree.TableExprs{&n.Table}},
+	}
+	if len(n.Columns) == 0 {
+		sel.Exprs = tree.SelectExprs{tree.StarSelectExpr()}
+	} else {
+		sel.Exprs = make(tree.SelectExprs, len(n.Columns))
+		for i := range n.Columns {
+			sel.Exprs[i].Expr = tree.NewUnresolvedName(string(n.Columns[i]))
+		}
+	}
+	return p.newPlan(ctx, &tree.Select{Select: sel}, nil /* desiredTypes */)
+}
+
+// copyTextDelimiterBlacklist contains the characters that cannot be used as
+// the delimiter of the text format, since they could be confused with the
+// escape sequences.
+const copyTextDelimiterBlacklist = "\\.abcdefghijklmnopqrstuvwxyz0123456789"
+
+// validateCopyOptions checks that the options of a COPY statement are
+// consistent with each other, using the same rules as PostgreSQL.
+func validateCopyOptions(opts *tree.CopyOptions) error {
+	if opts.FileFormat == tree.CopyFormatBinary {
+		if opts.Delimiter != nil {
+			return pgerror.NewError(pgerror.CodeSyntaxError, "cannot specify DELIMITER in BINARY mode")
+		}
+		if opts.Null != nil {
+			return pgerror.NewError(pgerror.CodeSyntaxError, "cannot specify NULL in BINARY mode")
+		}
+	}
+	if opts.Header != nil && *opts.Header && opts.FileFormat != tree.CopyFormatCSV {
+		return pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"COPY HEADER available only in CSV mode")
+	}
+	if opts.Delimiter != nil {
+		delim := *opts.Delimiter
+		if len(delim) != 1 {
+			return pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+				"COPY delimiter must be a single one-byte character")
+		}
+		if delim[0] == '\n' || delim[0] == '\r' {
+			return pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+				"COPY delimiter cannot be newline or carriage return")
+		}
+		if opts.FileFormat == tree.CopyFormatCSV {
+			if delim[0] == '"' {
+				return pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+					"COPY delimiter and quote must be different")
+			}
+		} else if strings.Contains(copyTextDelimiterBlacklist, delim) {
+			return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"COPY delimiter cannot be %q", delim)
+		}
+		if opts.Null != nil && strings.Contains(*opts.Null, delim) {
+			return pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+				"COPY delimiter must not appear in the NULL specification")
+		}
+	}
+	if opts.Null != nil && strings.ContainsAny(*opts.Null, "\r\n") {
+		return pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+			"COPY null representation cannot use newline or carriage return")
+	}
+	return nil
+}
@@ -59,6 +59,7 @@ var crdbInternal = virtualSchema{
 		crdbInternalClusterQueriesTable,
 		crdbInternalClusterSessionsTable,
 		crdbInternalClusterSettingsTable,
+		crdbInternalCreateFunctionStmtsTable,
 		crdbInternalCreateStmtsTable,
 		crdbInternalFeatureUsage,
 		crdbInternalForwardDependenciesTable,
@@ -301,6 +302,16 @@ CREATE TABLE crdb_internal.schema_changes (
 					mutType = "INDEX"
 					targetID = tree.NewDInt(tree.DInt(int64(d.Index.ID)))
 					targetName = tree.NewDString(d.Index.Name)
+				case *sqlbase.DescriptorMutation_ComputedColumnSwap:
+					mutType = "COLUMN SWAP"
+					targetID = tree.NewDInt(tree.DInt(int64(d.ComputedColumnSwap.OldColumnID)))
+				case *sqlbase.DescriptorMutation_Check:
+					mutType = "CHECK"
+					targetName = tree.NewDString(d.Check.Name)
+				case *sqlbase.DescriptorMutation_EnumMemberPromotion:
+					mutType = "ENUM VALUE"
+					targetID = tree.NewDInt(tree.DInt(int64(d.EnumMemberPromotion.TypeID)))
+					targetName = tree.NewDString(d.EnumMemberPromotion.Label)
 				}
 				if err := addRow(
 					tableID,
@@ -1018,6 +1029,40 @@ CREATE TABLE crdb_internal.builtin_functions (
 	},
 }
 
+// crdbInternalCreateFunctionStmtsTable exposes the CREATE FUNCTION
+// statements of the user-defined functions.
+var crdbInternalCreateFunctionStmtsTable = virtualSchemaTable{
+	schema: `
+CREATE TABLE crdb_internal.create_function_statements (
+  database_id      INT NOT NULL,
+  database_name    STRING NOT NULL,
+  function_id      INT NOT NULL,
+  function_name    STRING NOT NULL,
+  create_statement STRING NOT NULL
+)
+`,
+	populate: func(ctx context.Context, p *planner, dbContext *DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachDatabaseDesc(ctx, p, dbContext, func(db *sqlbase.DatabaseDescriptor) error {
+			for _, entry := range db.Functions {
+				fnDesc := &sqlbase.FunctionDescriptor{}
+				if err := getDescriptorByID(ctx, p.txn, entry.ID, fnDesc); err != nil {
+					return err
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(db.ID)),
+					tree.NewDString(db.Name),
+					tree.NewDInt(tree.DInt(fnDesc.ID)),
+					tree.NewDString(fnDesc.Name),
+					tree.NewDString(ShowCreateFunction(fnDesc)),
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	},
+}
+
 // crdbInternalCreateStmtsTable exposes the CREATE TABLE/CREATE VIEW
 // statements.
 var crdbInternalCreateStmtsTable = virtualSchemaTable{
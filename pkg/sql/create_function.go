@@ -0,0 +1,266 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type createFunctionNode struct {
+	n      *tree.CreateFunction
+	dbDesc *sqlbase.DatabaseDescriptor
+	desc   sqlbase.FunctionDescriptor
+	// replaced is the descriptor of the overload replaced by CREATE OR
+	// REPLACE, if any.
+	replaced *sqlbase.FunctionDescriptor
+}
+
+// CreateFunction creates a user-defined function.
+// Privileges: CREATE on database.
+//
+// User-defined functions are created in the current database. Like
+// user-defined types, they are recorded in the descriptor of the database.
+// A function can have several overloads, which differ by the types of their
+// arguments.
+func (p *planner) CreateFunction(ctx context.Context, n *tree.CreateFunction) (planNode, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /*required*/)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	name := string(n.Name)
+	// Builtin functions take precedence during name resolution, so a
+	// user-defined function with the same name could never be called.
+	if _, ok := tree.FunDefs[name]; ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeDuplicateFunctionError,
+			"function %s already exists as a builtin function", name)
+	}
+
+	// Inherit permissions from the database descriptor.
+	desc := sqlbase.FunctionDescriptor{
+		Name:       name,
+		ParentID:   dbDesc.ID,
+		ArgNames:   make([]string, len(n.Args)),
+		ArgTypes:   make([]sqlbase.ColumnType, len(n.Args)),
+		ReturnsSet: n.ReturnsSet,
+		Body:       n.Body,
+		Privileges: dbDesc.GetPrivileges(),
+	}
+	for i, arg := range n.Args {
+		if arg.Name != "" {
+			for _, prev := range desc.ArgNames[:i] {
+				if prev == string(arg.Name) {
+					return nil, pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+						"parameter name %q used more than once", prev)
+				}
+			}
+		}
+		desc.ArgNames[i] = string(arg.Name)
+		if desc.ArgTypes[i], err = p.resolveFunctionType(arg.Type); err != nil {
+			return nil, err
+		}
+	}
+	if desc.ReturnType, err = p.resolveFunctionType(n.ReturnType); err != nil {
+		return nil, err
+	}
+
+	existing, err := getFunctionDescs(ctx, p.txn, dbDesc, name)
+	if err != nil {
+		return nil, err
+	}
+	var replaced *sqlbase.FunctionDescriptor
+	for _, fnDesc := range existing {
+		if !typesEquivalent(fnDesc.ArgDatumTypes(), desc.ArgDatumTypes()) {
+			if fnDesc.ReturnsSet != desc.ReturnsSet {
+				return nil, pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+					"function %s cannot both return a set and a single value", name)
+			}
+			continue
+		}
+		if !n.Replace {
+			return nil, pgerror.NewErrorf(pgerror.CodeDuplicateFunctionError,
+				"function %s already exists", desc.Signature())
+		}
+		if fnDesc.ReturnsSet != desc.ReturnsSet ||
+			!fnDesc.ReturnType.ToDatumType().Equivalent(desc.ReturnType.ToDatumType()) {
+			return nil, pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+				"cannot change return type of existing function %s", desc.Signature())
+		}
+		replaced = fnDesc
+	}
+
+	if err := p.checkFunctionBody(ctx, &desc); err != nil {
+		return nil, err
+	}
+
+	return &createFunctionNode{
+		n:        n,
+		dbDesc:   dbDesc,
+		desc:     desc,
+		replaced: replaced,
+	}, nil
+}
+
+// resolveFunctionType returns the column type corresponding to the type of an
+// argument or of the result of a user-defined function.
+func (p *planner) resolveFunctionType(t coltypes.T) (sqlbase.ColumnType, error) {
+	typ, err := p.semaCtx.ResolveCastTargetType(t)
+	if err != nil {
+		return sqlbase.ColumnType{}, err
+	}
+	return sqlbase.DatumTypeToColumnType(coltypes.CastTargetToDatumType(typ))
+}
+
+// checkFunctionBody verifies that the body of the given function is a query
+// that produces a single column of the return type of the function.
+func (p *planner) checkFunctionBody(ctx context.Context, desc *sqlbase.FunctionDescriptor) error {
+	// The body is planned with NULL arguments.
+	body, err := parseFunctionBody(desc, func(i int) (tree.Expr, error) {
+		return makeFunctionArgCast(tree.DNull, desc.ArgTypes[i])
+	})
+	if err != nil {
+		return err
+	}
+	// Calls to user-defined functions are resolved upfront, as name
+	// resolution in the heuristic planner only knows about builtins.
+	w := functionBodyWalker{fn: func(expr tree.Expr) (err error, recurse bool, newExpr tree.Expr) {
+		if f, ok := expr.(*tree.FuncExpr); ok {
+			if _, err := p.semaCtx.ResolveFunction(&f.Func); err != nil {
+				return err, false, expr
+			}
+		}
+		return nil, true, expr
+	}}
+	if err := w.walkSelect(body); err != nil {
+		return err
+	}
+
+	plan, err := p.Select(ctx, body, []types.T{})
+	if err != nil {
+		return err
+	}
+	// The plan will not be needed further.
+	defer plan.Close(ctx)
+
+	retType := desc.ReturnType.ToDatumType()
+	cols := planColumns(plan)
+	if len(cols) != 1 {
+		return pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"return type mismatch in function declared to return %s", retType).SetDetailf(
+			"Final statement must return exactly one column.")
+	}
+	if typ := cols[0].Typ; typ != types.Unknown && !typ.Equivalent(retType) {
+		return pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"return type mismatch in function declared to return %s", retType).SetDetailf(
+			"Actual return type is %s.", typ)
+	}
+	return nil
+}
+
+// typesEquivalent returns whether the two lists of types have the same
+// length and pairwise equivalent types.
+func typesEquivalent(a, b []types.T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equivalent(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	desc := n.desc
+	b := &client.Batch{}
+	if n.replaced != nil {
+		// CREATE OR REPLACE keeps the ID and the privileges of the function.
+		desc.ID = n.replaced.ID
+		desc.Privileges = n.replaced.Privileges
+		if err := desc.Validate(); err != nil {
+			return err
+		}
+		descKey := sqlbase.MakeDescMetadataKey(desc.ID)
+		descDesc := sqlbase.WrapDescriptor(&desc)
+		if params.p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(params.ctx, 2, "Put %s -> %s", descKey, descDesc)
+		}
+		b.Put(descKey, descDesc)
+	} else {
+		id, err := GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB)
+		if err != nil {
+			return err
+		}
+		desc.ID = id
+		if err := desc.Validate(); err != nil {
+			return err
+		}
+
+		n.dbDesc.AddFunction(desc.Name, id)
+		if err := n.dbDesc.Validate(); err != nil {
+			return err
+		}
+
+		descKey := sqlbase.MakeDescMetadataKey(id)
+		descDesc := sqlbase.WrapDescriptor(&desc)
+		dbDescKey := sqlbase.MakeDescMetadataKey(n.dbDesc.ID)
+		dbDescDesc := sqlbase.WrapDescriptor(n.dbDesc)
+		if params.p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(params.ctx, 2, "CPut %s -> %s", descKey, descDesc)
+			log.VEventf(params.ctx, 2, "Put %s -> %s", dbDescKey, dbDescDesc)
+		}
+		b.CPut(descKey, descDesc, nil)
+		b.Put(dbDescKey, dbDescDesc)
+	}
+	if err := params.p.txn.Run(params.ctx, b); err != nil {
+		return err
+	}
+
+	// Log Create Function event. This is an auditable log event and is
+	// recorded in the same transaction as the function descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogCreateFunction,
+		int32(desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			FunctionName string
+			Statement    string
+			User         string
+		}{desc.Signature(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createFunctionNode) Close(context.Context)        {}
@@ -17,10 +17,12 @@ package sql
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
 
@@ -72,15 +74,72 @@ func MakeIndexDescriptor(n *tree.CreateIndex) (*sqlbase.IndexDescriptor, error)
 		if n.Unique {
 			return nil, pgerror.NewError(pgerror.CodeInvalidSQLStatementNameError, "inverted indexes can't be unique")
 		}
+
+		if n.Predicate != nil {
+			return nil, pgerror.NewError(pgerror.CodeInvalidSQLStatementNameError, "inverted indexes can't be partial")
+		}
 		indexDesc.Type = sqlbase.IndexDescriptor_INVERTED
 	}
 
+	if n.Sharded != nil {
+		if n.Interleave != nil {
+			return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError, "interleaved indexes cannot also be hash sharded")
+		}
+		if n.PartitionBy != nil {
+			return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError, "partitioned indexes cannot also be hash sharded")
+		}
+	}
+
 	if err := indexDesc.FillColumns(n.Columns); err != nil {
 		return nil, err
 	}
 	return &indexDesc, nil
 }
 
+// makeIndexPredicate validates the predicate of a partial index and stores its
+// serialized form, along with the columns it references, in idx.
+func makeIndexPredicate(
+	ctx context.Context,
+	desc *sqlbase.MutableTableDescriptor,
+	idx *sqlbase.IndexDescriptor,
+	pred tree.Expr,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+	tableName tree.TableName,
+) error {
+	expr, colIDsUsed, err := replaceVars(desc, pred)
+	if err != nil {
+		return err
+	}
+
+	// The predicate is evaluated every time a row is written, so it must be
+	// deterministic: otherwise the entries in the index could not be found
+	// again when the row is updated or deleted.
+	if _, err := sqlbase.SanitizeVarFreeExpr(
+		expr, types.Bool, "index predicate", semaCtx, evalCtx, false, /* allowImpure */
+	); err != nil {
+		return err
+	}
+
+	colIDs := make([]sqlbase.ColumnID, 0, len(colIDsUsed))
+	for colID := range colIDsUsed {
+		colIDs = append(colIDs, colID)
+	}
+	sort.Sort(sqlbase.ColumnIDs(colIDs))
+
+	sourceInfo := sqlbase.NewSourceInfoForSingleTable(
+		tableName, sqlbase.ResultColumnsFromColDescs(desc.Columns),
+	)
+	expr, err = dequalifyColumnRefs(ctx, sqlbase.MultiSourceInfo{sourceInfo}, pred)
+	if err != nil {
+		return err
+	}
+
+	idx.Predicate = tree.Serialize(expr)
+	idx.PredicateColumnIDs = colIDs
+	return nil
+}
+
 func (n *createIndexNode) startExec(params runParams) error {
 	_, dropped, err := n.tableDesc.FindIndexByName(string(n.n.Name))
 	if err == nil {
@@ -97,6 +156,29 @@ func (n *createIndexNode) startExec(params runParams) error {
 		return err
 	}
 
+	if n.n.Sharded != nil {
+		shardCol, err := setupShardedIndex(
+			n.tableDesc, indexDesc, n.n.Sharded, &params.p.semaCtx, params.EvalContext(),
+		)
+		if err != nil {
+			return err
+		}
+		if shardCol != nil {
+			// The shard column is added by the same schema change as the index,
+			// and is backfilled before it.
+			n.tableDesc.AddColumnMutation(*shardCol, sqlbase.DescriptorMutation_ADD)
+		}
+	}
+
+	if n.n.Predicate != nil {
+		if err := makeIndexPredicate(
+			params.ctx, n.tableDesc, indexDesc, n.n.Predicate,
+			&params.p.semaCtx, params.EvalContext(), n.n.Table,
+		); err != nil {
+			return err
+		}
+	}
+
 	if n.n.PartitionBy != nil {
 		partitioning, err := CreatePartitioning(params.ctx, params.p.ExecCfg().Settings,
 			params.EvalContext(), n.tableDesc, indexDesc, n.n.PartitionBy)
@@ -133,6 +215,12 @@ func (n *createIndexNode) startExec(params runParams) error {
 		return err
 	}
 
+	if index := n.tableDesc.Mutations[mutationIdx].GetIndex(); index.IsSharded() {
+		if err := params.p.preSplitShardedIndex(params.ctx, n.tableDesc.TableDesc(), index); err != nil {
+			return err
+		}
+	}
+
 	// Record index creation in the event log. This is an auditable log
 	// event and is recorded in the same transaction as the table descriptor
 	// update.
@@ -0,0 +1,135 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type createSchemaNode struct {
+	n      *tree.CreateSchema
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateSchema creates a user-defined schema.
+// Privileges: CREATE on database.
+//   Notes: postgres requires CREATE on the database.
+//
+// User-defined schemas are created in the current database. Their names are
+// recorded in the descriptor of the database, and the relations they contain
+// are recorded in system.namespace under the ID of the schema.
+func (p *planner) CreateSchema(ctx context.Context, n *tree.CreateSchema) (planNode, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /*required*/)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	name := string(n.Schema)
+	if _, ok := p.getVirtualTabler().getVirtualSchemaEntry(name); ok || name == tree.PublicSchema {
+		if n.IfNotExists {
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, sqlbase.NewSchemaAlreadyExistsError(name)
+	}
+	if strings.HasPrefix(name, "pg_") {
+		return nil, pgerror.NewErrorf(pgerror.CodeReservedNameError,
+			"unacceptable schema name %q", name).SetDetailf(
+			`The prefix "pg_" is reserved for system schemas.`)
+	}
+
+	return &createSchemaNode{
+		n:      n,
+		dbDesc: dbDesc,
+	}, nil
+}
+
+func (n *createSchemaNode) startExec(params runParams) error {
+	name := string(n.n.Schema)
+	if _, ok := n.dbDesc.FindSchema(name); ok {
+		if n.n.IfNotExists {
+			return nil
+		}
+		return sqlbase.NewSchemaAlreadyExistsError(name)
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB)
+	if err != nil {
+		return err
+	}
+	// Inherit permissions from the database descriptor.
+	desc := sqlbase.SchemaDescriptor{
+		Name:       name,
+		ID:         id,
+		ParentID:   n.dbDesc.ID,
+		Privileges: n.dbDesc.GetPrivileges(),
+	}
+	if err := desc.Validate(); err != nil {
+		return err
+	}
+
+	n.dbDesc.AddSchema(name, id)
+	if err := n.dbDesc.Validate(); err != nil {
+		return err
+	}
+
+	b := &client.Batch{}
+	descKey := sqlbase.MakeDescMetadataKey(id)
+	descDesc := sqlbase.WrapDescriptor(&desc)
+	dbDescKey := sqlbase.MakeDescMetadataKey(n.dbDesc.ID)
+	dbDescDesc := sqlbase.WrapDescriptor(n.dbDesc)
+	if params.p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(params.ctx, 2, "CPut %s -> %s", descKey, descDesc)
+		log.VEventf(params.ctx, 2, "Put %s -> %s", dbDescKey, dbDescDesc)
+	}
+	b.CPut(descKey, descDesc, nil)
+	b.Put(dbDescKey, dbDescDesc)
+	if err := params.p.txn.Run(params.ctx, b); err != nil {
+		return err
+	}
+
+	// Log Create Schema event. This is an auditable log event and is
+	// recorded in the same transaction as the schema descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogCreateSchema,
+		int32(desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			SchemaName string
+			Statement  string
+			User       string
+		}{name, n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createSchemaNode) Next(runParams) (bool, error) { return false, nil }
+func (*createSchemaNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createSchemaNode) Close(context.Context)        {}
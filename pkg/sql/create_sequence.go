@@ -20,8 +20,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
-	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 )
@@ -36,8 +37,11 @@ func (p *planner) CreateSequence(ctx context.Context, n *tree.CreateSequence) (p
 	if err != nil {
 		return nil, err
 	}
+	if sessiondata.IsTemporarySchemaName(n.Name.Schema()) {
+		return nil, pgerror.UnimplementedWithIssueError(5807, "temporary sequences")
+	}
 
-	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+	if _, err := p.checkCreatePrivilegeInSchema(ctx, dbDesc, n.Name.Schema()); err != nil {
 		return nil, err
 	}
 
@@ -48,7 +52,7 @@ func (p *planner) CreateSequence(ctx context.Context, n *tree.CreateSequence) (p
 }
 
 func (n *createSequenceNode) startExec(params runParams) error {
-	tKey := getSequenceKey(n.dbDesc, n.n.Name.Table())
+	tKey := getSequenceKey(n.dbDesc, &n.n.Name)
 	if exists, err := descExists(params.ctx, params.p.txn, tKey.Key()); err == nil && exists {
 		if n.n.IfNotExists {
 			// If the sequence exists but the user specified IF NOT EXISTS, return without doing anything.
@@ -62,8 +66,15 @@ func (n *createSequenceNode) startExec(params runParams) error {
 	return doCreateSequence(params, n.n.String(), n.dbDesc, &n.n.Name, n.n.Options)
 }
 
-func getSequenceKey(dbDesc *DatabaseDescriptor, seqName string) tableKey {
-	return tableKey{parentID: dbDesc.ID, name: seqName}
+// getSequenceKey returns the system.namespace key of the sequence with the
+// given name. The sequences of user-defined schemas are recorded under the ID
+// of their schema.
+func getSequenceKey(dbDesc *DatabaseDescriptor, seqName *ObjectName) tableKey {
+	parentID := dbDesc.ID
+	if scID, ok := dbDesc.FindSchema(seqName.Schema()); ok {
+		parentID = scID
+	}
+	return tableKey{parentID: parentID, name: seqName.Table()}
 }
 
 // doCreateSequence performs the creation of a sequence in KV. The
@@ -80,19 +91,30 @@ func doCreateSequence(
 		return err
 	}
 
-	// Inherit permissions from the database descriptor.
+	// Inherit permissions from the database descriptor, or from the
+	// descriptor of the user-defined schema.
+	scDesc, err := getSchemaDesc(params.ctx, params.p.txn, dbDesc, name.Schema())
+	if err != nil {
+		return err
+	}
 	privs := dbDesc.GetPrivileges()
+	if scDesc != nil {
+		privs = scDesc.GetPrivileges()
+	}
 
 	desc, err := MakeSequenceTableDesc(name.Table(), opts,
 		dbDesc.ID, id, params.p.txn.CommitTimestamp(), privs, params.EvalContext().Settings)
 	if err != nil {
 		return err
 	}
+	if scDesc != nil {
+		desc.SchemaID = scDesc.ID
+	}
 
 	// makeSequenceTableDesc already validates the table. No call to
 	// desc.ValidateTable() needed here.
 
-	key := getSequenceKey(dbDesc, name.Table()).Key()
+	key := getSequenceKey(dbDesc, name).Key()
 	if err = params.p.createDescriptorWithID(params.ctx, key, id, &desc, params.EvalContext().Settings); err != nil {
 		return err
 	}
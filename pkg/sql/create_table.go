@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -38,28 +39,41 @@ import (
 )
 
 type createTableNode struct {
-	n          *tree.CreateTable
-	dbDesc     *sqlbase.DatabaseDescriptor
+	n      *tree.CreateTable
+	dbDesc *sqlbase.DatabaseDescriptor
+	// scDesc is the user-defined schema of the table, if any.
+	scDesc     *sqlbase.SchemaDescriptor
 	sourcePlan planNode
 
 	run createTableRun
 }
 
 // CreateTable creates a table.
-// Privileges: CREATE on database.
+// Privileges: CREATE on database, or CREATE on schema for tables in
+// user-defined schemas.
 //   Notes: postgres/mysql require CREATE on database.
 func (p *planner) CreateTable(ctx context.Context, n *tree.CreateTable) (planNode, error) {
+	if err := p.maybeUseTemporarySchema(n); err != nil {
+		return nil, err
+	}
+
 	dbDesc, err := p.ResolveUncachedDatabase(ctx, &n.Table)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+	scDesc, err := p.checkCreatePrivilegeInSchema(ctx, dbDesc, n.Table.Schema())
+	if err != nil {
 		return nil, err
 	}
 
 	n.HoistConstraints()
 
+	if n.As() && len(n.StorageParams) > 0 {
+		return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"storage parameters are not supported with CREATE TABLE AS")
+	}
+
 	var sourcePlan planNode
 	if n.As() {
 		// The sourcePlan is needed to determine the set of columns to use
@@ -80,7 +94,53 @@ func (p *planner) CreateTable(ctx context.Context, n *tree.CreateTable) (planNod
 		}
 	}
 
-	return &createTableNode{n: n, dbDesc: dbDesc, sourcePlan: sourcePlan}, nil
+	return &createTableNode{n: n, dbDesc: dbDesc, scDesc: scDesc, sourcePlan: sourcePlan}, nil
+}
+
+// checkCreatePrivilegeInSchema checks that the user has the CREATE privilege
+// on the user-defined schema in which a relation is created, or on the
+// database if the relation is not created in a user-defined schema. It
+// returns the descriptor of the user-defined schema, if any.
+func (p *planner) checkCreatePrivilegeInSchema(
+	ctx context.Context, dbDesc *sqlbase.DatabaseDescriptor, scName string,
+) (*sqlbase.SchemaDescriptor, error) {
+	scDesc, err := getSchemaDesc(ctx, p.txn, dbDesc, scName)
+	if err != nil {
+		return nil, err
+	}
+	var privDesc sqlbase.DescriptorProto = dbDesc
+	if scDesc != nil {
+		privDesc = scDesc
+	}
+	if err := p.CheckPrivilege(ctx, privDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	return scDesc, nil
+}
+
+// maybeUseTemporarySchema points the name of the table to be created at the
+// temporary schema of the session if the table is temporary, or if the name
+// designates the temporary schema explicitly. Temporary tables can't be
+// created in any other schema.
+func (p *planner) maybeUseTemporarySchema(n *tree.CreateTable) error {
+	tempSchemaName := temporarySchemaName(p.ExtendedEvalContext().SessionID)
+	if n.Table.ExplicitSchema {
+		switch scName := n.Table.Schema(); {
+		case scName == sessiondata.PgTempSchemaName || scName == tempSchemaName:
+			n.Temporary = true
+		case sessiondata.IsTemporarySchemaName(scName):
+			return pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+				"cannot create relations in temporary schemas of other sessions")
+		case n.Temporary:
+			return pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+				"cannot create temporary relation in non-temporary schema")
+		}
+	}
+	if n.Temporary {
+		n.Table.SchemaName = tree.Name(tempSchemaName)
+		n.Table.ExplicitSchema = true
+	}
+	return nil
 }
 
 // createTableRun contains the run-time state of createTableNode
@@ -91,7 +151,21 @@ type createTableRun struct {
 }
 
 func (n *createTableNode) startExec(params runParams) error {
-	tKey := tableKey{parentID: n.dbDesc.ID, name: n.n.Table.Table()}
+	// Temporary tables are recorded in system.namespace under the ID of the
+	// temporary schema of the session, which is created on first use. The
+	// tables of user-defined schemas are recorded under the ID of their
+	// schema.
+	parentID := n.dbDesc.ID
+	if n.n.Temporary {
+		var err error
+		if parentID, err = params.p.getOrCreateTemporarySchema(params.ctx, n.dbDesc.ID); err != nil {
+			return err
+		}
+	} else if n.scDesc != nil {
+		parentID = n.scDesc.ID
+	}
+
+	tKey := tableKey{parentID: parentID, name: n.n.Table.Table()}
 	key := tKey.Key()
 	if exists, err := descExists(params.ctx, params.p.txn, key); err == nil && exists {
 		if n.n.IfNotExists {
@@ -110,6 +184,9 @@ func (n *createTableNode) startExec(params runParams) error {
 	// If a new system table is being created (which should only be doable by
 	// an internal user account), make sure it gets the correct privileges.
 	privs := n.dbDesc.GetPrivileges()
+	if n.scDesc != nil {
+		privs = n.scDesc.GetPrivileges()
+	}
 	if n.dbDesc.ID == keys.SystemDatabaseID {
 		privs = sqlbase.NewDefaultPrivilegeDescriptor()
 	}
@@ -121,6 +198,9 @@ func (n *createTableNode) startExec(params runParams) error {
 		desc, err = makeTableDescIfAs(
 			n.n, n.dbDesc.ID, id, creationTime, planColumns(n.sourcePlan),
 			privs, &params.p.semaCtx, params.EvalContext())
+		if n.n.Temporary {
+			desc.TemporarySchemaID = parentID
+		}
 	} else {
 		affected = make(map[sqlbase.ID]*sqlbase.MutableTableDescriptor)
 		desc, err = makeTableDesc(params, n.n, n.dbDesc.ID, id, creationTime, privs, affected)
@@ -128,6 +208,9 @@ func (n *createTableNode) startExec(params runParams) error {
 	if err != nil {
 		return err
 	}
+	if n.scDesc != nil {
+		desc.SchemaID = n.scDesc.ID
+	}
 
 	if desc.Adding() {
 		// if this table and all its references are created in the same
@@ -172,6 +255,12 @@ func (n *createTableNode) startExec(params runParams) error {
 		return err
 	}
 
+	for _, index := range desc.AllNonDropIndexes() {
+		if err := params.p.preSplitShardedIndex(params.ctx, desc.TableDesc(), &index); err != nil {
+			return err
+		}
+	}
+
 	// Log Create Table event. This is an auditable log event and is
 	// recorded in the same transaction as the table descriptor update.
 	if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
@@ -189,6 +278,12 @@ func (n *createTableNode) startExec(params runParams) error {
 		return err
 	}
 
+	if desc.RowLevelTTL != nil {
+		if err := params.p.createRowLevelTTLJob(params.ctx, &desc); err != nil {
+			return err
+		}
+	}
+
 	if n.n.As() {
 		// This is a very simplified version of the INSERT logic: no CHECK
 		// expressions, no FK checks, no arbitrary insertion order, no
@@ -202,6 +297,7 @@ func (n *createTableNode) startExec(params runParams) error {
 			nil,
 			desc.Columns,
 			row.SkipFKs,
+			params.EvalContext(),
 			&params.p.alloc)
 		if err != nil {
 			return err
@@ -306,10 +402,14 @@ const (
 
 // Referenced cols must be unique, thus referenced indexes must match exactly.
 // Referencing cols have no uniqueness requirement and thus may match a strict
-// prefix of an index.
+// prefix of an index. Partial indexes never match, as they don't contain every
+// row of the table.
 func matchesIndex(
 	cols []sqlbase.ColumnDescriptor, idx sqlbase.IndexDescriptor, exact indexMatch,
 ) bool {
+	if idx.IsPartial() {
+		return false
+	}
 	if len(cols) > len(idx.ColumnIDs) || (exact && len(cols) != len(idx.ColumnIDs)) {
 		return false
 	}
@@ -408,6 +508,12 @@ func ResolveFK(
 	if err != nil {
 		return err
 	}
+	if target.IsTemporary() != tbl.IsTemporary() {
+		// Temporary tables disappear with their session, which would leave the
+		// references of permanent tables dangling, and vice versa.
+		return pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+			"constraints on temporary tables may reference only temporary tables")
+	}
 	if target.ID == tbl.ID {
 		// When adding a self-ref FK to an _existing_ table, we want to make sure
 		// we edit the same copy.
@@ -528,6 +634,9 @@ func ResolveFK(
 		SharedPrefixLen: int32(len(srcCols)),
 		OnDelete:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Delete],
 		OnUpdate:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Update],
+
+		Deferrable:        d.Deferrability != tree.NotDeferrable,
+		InitiallyDeferred: d.Deferrability == tree.DeferrableInitiallyDeferred,
 	}
 
 	if ts != NewTable {
@@ -957,6 +1066,16 @@ func MakeTableDesc(
 ) (sqlbase.MutableTableDescriptor, error) {
 	desc := InitTableDescriptor(id, parentID, n.Table.Table(), creationTime, privileges)
 
+	if n.Temporary {
+		// The temporary schema has been created by the caller. Its ID must be
+		// known before foreign keys are resolved below.
+		scID, err := getTemporarySchemaID(ctx, txn, parentID, n.Table.Schema())
+		if err != nil {
+			return desc, err
+		}
+		desc.TemporarySchemaID = scID
+	}
+
 	for _, def := range n.Defs {
 		if d, ok := def.(*tree.ColumnTableDef); ok {
 			if !desc.IsVirtualTable() {
@@ -1000,6 +1119,18 @@ func MakeTableDesc(
 		}
 	}
 
+	for _, param := range n.StorageParams {
+		switch key := string(param.Key); key {
+		case ttlExpireAfterParam:
+			if err := addRowLevelTTL(&desc, param.Value, semaCtx, evalCtx); err != nil {
+				return desc, err
+			}
+		default:
+			return desc, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"unrecognized storage parameter %q", key)
+		}
+	}
+
 	// Now that we've constructed our columns, we pop into any of our computed
 	// columns so that we can dequalify any column references.
 	sourceInfo := sqlbase.NewSourceInfoForSingleTable(
@@ -1040,6 +1171,13 @@ func MakeTableDesc(
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return desc, err
 			}
+			if d.Sharded != nil {
+				if err := addShardedIndexToNewTable(
+					&desc, &idx, d.Sharded, d.PartitionBy, semaCtx, evalCtx,
+				); err != nil {
+					return desc, err
+				}
+			}
 			if d.PartitionBy != nil {
 				partitioning, err := CreatePartitioning(ctx, st, evalCtx, &desc, &idx, d.PartitionBy)
 				if err != nil {
@@ -1047,6 +1185,13 @@ func MakeTableDesc(
 				}
 				idx.Partitioning = partitioning
 			}
+			if d.Predicate != nil {
+				if err := makeIndexPredicate(
+					ctx, &desc, &idx, d.Predicate, semaCtx, evalCtx, n.Table,
+				); err != nil {
+					return desc, err
+				}
+			}
 			if err := desc.AddIndex(idx, false); err != nil {
 				return desc, err
 			}
@@ -1059,9 +1204,31 @@ func MakeTableDesc(
 				Unique:           true,
 				StoreColumnNames: d.Storing.ToStrings(),
 			}
+			if d.Deferrability != tree.NotDeferrable {
+				// The uniqueness of a deferrable constraint is not enforced by
+				// the encoding of its index, see DeferrableUnique.
+				idx.Unique = false
+				idx.DeferrableUnique = true
+				idx.InitiallyDeferred = d.Deferrability == tree.DeferrableInitiallyDeferred
+			}
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return desc, err
 			}
+			if d.Sharded != nil {
+				partitionBy := d.PartitionBy
+				if d.PrimaryKey {
+					if n.Interleave != nil {
+						return desc, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+							"interleaved indexes cannot also be hash sharded")
+					}
+					partitionBy = n.PartitionBy
+				}
+				if err := addShardedIndexToNewTable(
+					&desc, &idx, d.Sharded, partitionBy, semaCtx, evalCtx,
+				); err != nil {
+					return desc, err
+				}
+			}
 			if d.PartitionBy != nil {
 				partitioning, err := CreatePartitioning(ctx, st, evalCtx, &desc, &idx, d.PartitionBy)
 				if err != nil {
@@ -1069,6 +1236,13 @@ func MakeTableDesc(
 				}
 				idx.Partitioning = partitioning
 			}
+			if d.Predicate != nil {
+				if err := makeIndexPredicate(
+					ctx, &desc, &idx, d.Predicate, semaCtx, evalCtx, n.Table,
+				); err != nil {
+					return desc, err
+				}
+			}
 			if err := desc.AddIndex(idx, d.PrimaryKey); err != nil {
 				return desc, err
 			}
@@ -1115,6 +1289,14 @@ func MakeTableDesc(
 		return desc, err
 	}
 
+	if desc.RowLevelTTL != nil {
+		col, _, err := desc.FindColumnByName(rowLevelTTLColumnName)
+		if err != nil {
+			return desc, err
+		}
+		desc.RowLevelTTL.ColumnID = col.ID
+	}
+
 	if n.Interleave != nil {
 		if err := addInterleave(ctx, txn, vt, &desc, &desc.PrimaryIndex, n.Interleave); err != nil {
 			return desc, err
@@ -1194,6 +1376,13 @@ func makeTableDesc(
 	privileges *sqlbase.PrivilegeDescriptor,
 	affected map[sqlbase.ID]*sqlbase.MutableTableDescriptor,
 ) (ret sqlbase.MutableTableDescriptor, err error) {
+	// Replace any LIKE table definitions by the definitions copied from
+	// their source tables, as required by MakeTableDesc.
+	n, hiddenCols, err := params.p.expandLikeTableDefs(params.ctx, n)
+	if err != nil {
+		return ret, err
+	}
+
 	// Process any SERIAL columns to remove the SERIAL type,
 	// as required by MakeTableDesc.
 	createStmt := n
@@ -1214,6 +1403,10 @@ func makeTableDesc(
 			return ret, err
 		}
 		if seqName != nil {
+			if n.Temporary {
+				return ret, pgerror.UnimplementedWithIssueError(5807,
+					"SERIAL columns backed by sequences are not supported in temporary tables")
+			}
 			if err := doCreateSequence(params, n.String(), seqDbDesc, seqName, seqOpts); err != nil {
 				return ret, err
 			}
@@ -1244,7 +1437,223 @@ func makeTableDesc(
 			params.p.EvalContext(),
 		)
 	})
-	return ret, err
+	if err != nil {
+		return ret, err
+	}
+	// The columns copied from hidden columns of the source tables of LIKE
+	// table definitions are hidden as well.
+	for _, name := range hiddenCols {
+		for i := range ret.Columns {
+			if ret.Columns[i].Name == string(name) {
+				ret.Columns[i].Hidden = true
+			}
+		}
+	}
+	return ret, nil
+}
+
+// expandLikeTableDefs returns a copy of the given CREATE TABLE statement in
+// which every LIKE table definition is replaced by the definitions of the
+// columns of its source table. Depending on the options of the LIKE
+// definition, the defaults, the CHECK constraints and the indexes of the
+// source table are copied as well. As in PostgreSQL, the NOT NULL constraints
+// are always copied, and foreign keys are never copied. Column families,
+// interleaving and partitioning are not copied either. The statement is
+// returned as-is if it has no LIKE table definitions. The names of the copied
+// columns that are hidden in their source table are returned as well, since
+// a column definition can't specify that the column is hidden.
+func (p *planner) expandLikeTableDefs(
+	ctx context.Context, n *tree.CreateTable,
+) (_ *tree.CreateTable, hiddenCols []tree.Name, _ error) {
+	hasLike := false
+	for _, def := range n.Defs {
+		if _, ok := def.(*tree.LikeTableDef); ok {
+			hasLike = true
+			break
+		}
+	}
+	if !hasLike {
+		return n, nil, nil
+	}
+
+	defs := make(tree.TableDefs, 0, len(n.Defs))
+	for _, def := range n.Defs {
+		d, ok := def.(*tree.LikeTableDef)
+		if !ok {
+			defs = append(defs, def)
+			continue
+		}
+		likeDefs, likeHiddenCols, err := p.makeLikeTableDefs(ctx, d)
+		if err != nil {
+			return nil, nil, err
+		}
+		defs = append(defs, likeDefs...)
+		hiddenCols = append(hiddenCols, likeHiddenCols...)
+	}
+	newCreateStmt := *n
+	newCreateStmt.Defs = defs
+	return &newCreateStmt, hiddenCols, nil
+}
+
+// makeLikeTableDefs returns the table definitions copied from the source
+// table of a LIKE table definition, and the names of the copied columns that
+// are hidden. See expandLikeTableDefs.
+func (p *planner) makeLikeTableDefs(
+	ctx context.Context, d *tree.LikeTableDef,
+) (_ tree.TableDefs, hiddenCols []tree.Name, _ error) {
+	tn := d.Name
+	desc, err := p.ResolveUncachedTableDescriptor(ctx, &tn, true /*required*/, requireTableDesc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.SELECT); err != nil {
+		return nil, nil, err
+	}
+	opts := d.EffectiveOpts()
+
+	// The shard columns of the hash sharded indexes that are copied are
+	// recreated along with the indexes.
+	shardCols := make(map[string]struct{})
+	if opts.Has(tree.LikeTableOptIndexes) {
+		for _, idx := range desc.AllNonDropIndexes() {
+			if idx.IsSharded() {
+				shardCols[idx.Sharded.Name] = struct{}{}
+			}
+		}
+	}
+
+	var defs tree.TableDefs
+	for i := range desc.Columns {
+		col := &desc.Columns[i]
+		if isImplicitRowIDColumn(desc.TableDesc(), col) {
+			// The implicit rowid column is not copied. The new table gets its
+			// own if it needs one.
+			continue
+		}
+		if _, ok := shardCols[col.Name]; ok {
+			continue
+		}
+		colDef, err := makeLikeColumnDef(col)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !opts.Has(tree.LikeTableOptDefaults) {
+			colDef.DefaultExpr.Expr = nil
+		}
+		defs = append(defs, colDef)
+		if col.Hidden {
+			hiddenCols = append(hiddenCols, colDef.Name)
+		}
+	}
+
+	if opts.Has(tree.LikeTableOptConstraints) {
+		for _, ck := range desc.Checks {
+			if ck.IsNonNullConstraint {
+				// NOT NULL constraints are copied with the columns.
+				continue
+			}
+			expr, err := parser.ParseExpr(ck.Expr)
+			if err != nil {
+				return nil, nil, err
+			}
+			defs = append(defs, &tree.CheckConstraintTableDef{Name: tree.Name(ck.Name), Expr: expr})
+		}
+	}
+
+	if opts.Has(tree.LikeTableOptIndexes) && desc.IsPhysicalTable() {
+		pkCol, err := desc.FindColumnByID(desc.PrimaryIndex.ColumnIDs[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		if !isImplicitRowIDColumn(desc.TableDesc(), pkCol) {
+			idxDef, err := makeLikeIndexDef(&desc.PrimaryIndex)
+			if err != nil {
+				return nil, nil, err
+			}
+			defs = append(defs, &tree.UniqueConstraintTableDef{IndexTableDef: idxDef, PrimaryKey: true})
+		}
+		for i := range desc.Indexes {
+			idxDef, err := makeLikeIndexDef(&desc.Indexes[i])
+			if err != nil {
+				return nil, nil, err
+			}
+			// The names of the secondary indexes are generated from the name
+			// of the new table, so that the indexes copied from several tables
+			// can't conflict.
+			idxDef.Name = ""
+			if desc.Indexes[i].Unique {
+				defs = append(defs, &tree.UniqueConstraintTableDef{IndexTableDef: idxDef})
+			} else if desc.Indexes[i].DeferrableUnique {
+				deferrability := tree.DeferrableInitiallyImmediate
+				if desc.Indexes[i].InitiallyDeferred {
+					deferrability = tree.DeferrableInitiallyDeferred
+				}
+				defs = append(defs, &tree.UniqueConstraintTableDef{
+					IndexTableDef: idxDef, Deferrability: deferrability,
+				})
+			} else {
+				defs = append(defs, &idxDef)
+			}
+		}
+	}
+	return defs, hiddenCols, nil
+}
+
+// isImplicitRowIDColumn returns true if col is the hidden rowid column that
+// was added as the primary key of a table created without one.
+func isImplicitRowIDColumn(desc *sqlbase.TableDescriptor, col *sqlbase.ColumnDescriptor) bool {
+	return col.Hidden && col.DefaultExpr != nil && *col.DefaultExpr == "unique_rowid()" &&
+		len(desc.PrimaryIndex.ColumnIDs) == 1 && desc.PrimaryIndex.ColumnIDs[0] == col.ID
+}
+
+// makeLikeColumnDef returns the definition of a column copied by a LIKE table
+// definition. It is obtained by parsing the SQL representation of the column
+// used by SHOW CREATE TABLE, which preserves its type, nullability, default
+// and computed expression.
+func makeLikeColumnDef(col *sqlbase.ColumnDescriptor) (*tree.ColumnTableDef, error) {
+	stmt, err := parser.ParseOne(fmt.Sprintf("CREATE TABLE t (%s)", col.SQLString()))
+	if err != nil {
+		return nil, err
+	}
+	return stmt.(*tree.CreateTable).Defs[0].(*tree.ColumnTableDef), nil
+}
+
+// makeLikeIndexDef returns the definition of an index copied by a LIKE table
+// definition.
+func makeLikeIndexDef(idx *sqlbase.IndexDescriptor) (tree.IndexTableDef, error) {
+	colNames, colDirs := idx.ColumnNames, idx.ColumnDirections
+	if idx.IsSharded() {
+		// The shard column is recreated from the USING HASH clause.
+		colNames, colDirs = colNames[1:], colDirs[1:]
+	}
+	def := tree.IndexTableDef{
+		Name:     tree.Name(idx.Name),
+		Columns:  make(tree.IndexElemList, len(colNames)),
+		Storing:  make(tree.NameList, len(idx.StoreColumnNames)),
+		Inverted: idx.Type == sqlbase.IndexDescriptor_INVERTED,
+	}
+	for i, name := range colNames {
+		def.Columns[i].Column = tree.Name(name)
+		if colDirs[i] == sqlbase.IndexDescriptor_DESC {
+			def.Columns[i].Direction = tree.Descending
+		}
+	}
+	if idx.IsSharded() {
+		def.Sharded = &tree.ShardedIndexDef{
+			ShardBuckets: tree.NewDInt(tree.DInt(idx.Sharded.ShardBuckets)),
+		}
+	}
+	for i, name := range idx.StoreColumnNames {
+		def.Storing[i] = tree.Name(name)
+	}
+	if idx.IsPartial() {
+		pred, err := parser.ParseExpr(idx.Predicate)
+		if err != nil {
+			return def, err
+		}
+		def.Predicate = pred
+	}
+	return def, nil
 }
 
 // dummyColumnItem is used in MakeCheckConstraint to construct an expression
@@ -1372,6 +1781,20 @@ func validateComputedColumn(
 		)
 	}
 
+	// Virtual computed columns are not stored in the primary index.
+	if d.Computed.Virtual {
+		if d.HasColumnFamily() {
+			return pgerror.NewError(pgerror.CodeInvalidTableDefinitionError,
+				"virtual computed columns cannot be part of a column family")
+		}
+		for _, colName := range desc.PrimaryIndex.ColumnNames {
+			if colName == string(d.Name) {
+				return pgerror.NewError(pgerror.CodeInvalidTableDefinitionError,
+					"virtual computed columns cannot be part of the primary key")
+			}
+		}
+	}
+
 	dependencies := make(map[string]struct{})
 	// First, check that no column in the expression is a computed column.
 	if err := iterColDescriptorsInExpr(desc, d.Computed.Expr, func(c sqlbase.ColumnDescriptor) error {
@@ -0,0 +1,127 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type createTypeNode struct {
+	n      *tree.CreateType
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateType creates a user-defined type.
+// Privileges: CREATE on database.
+//
+// User-defined types are created in the current database. Their names are
+// recorded in the descriptor of the database instead of the namespace table,
+// so that they are not mistaken for relations.
+func (p *planner) CreateType(ctx context.Context, n *tree.CreateType) (planNode, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /*required*/)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createTypeNode{
+		n:      n,
+		dbDesc: dbDesc,
+	}, nil
+}
+
+func (n *createTypeNode) startExec(params runParams) error {
+	name := string(n.n.Name)
+	if _, ok := n.dbDesc.FindType(name); ok {
+		return sqlbase.NewTypeAlreadyExistsError(name)
+	}
+	// Types and relations share a namespace in PostgreSQL, where every
+	// relation has a row type of the same name.
+	tKey := tableKey{parentID: n.dbDesc.ID, name: name}
+	if exists, err := descExists(params.ctx, params.p.txn, tKey.Key()); err != nil {
+		return err
+	} else if exists {
+		return sqlbase.NewTypeAlreadyExistsError(name)
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB)
+	if err != nil {
+		return err
+	}
+	// Inherit permissions from the database descriptor.
+	desc := sqlbase.TypeDescriptor{
+		Name:       name,
+		ID:         id,
+		ParentID:   n.dbDesc.ID,
+		Privileges: n.dbDesc.GetPrivileges(),
+	}
+	if err := desc.InitEnumMembers(n.n.EnumLabels); err != nil {
+		return err
+	}
+	if err := desc.Validate(); err != nil {
+		return err
+	}
+
+	n.dbDesc.AddType(name, id)
+	if err := n.dbDesc.Validate(); err != nil {
+		return err
+	}
+
+	b := &client.Batch{}
+	descKey := sqlbase.MakeDescMetadataKey(id)
+	descDesc := sqlbase.WrapDescriptor(&desc)
+	dbDescKey := sqlbase.MakeDescMetadataKey(n.dbDesc.ID)
+	dbDescDesc := sqlbase.WrapDescriptor(n.dbDesc)
+	if params.p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(params.ctx, 2, "CPut %s -> %s", descKey, descDesc)
+		log.VEventf(params.ctx, 2, "Put %s -> %s", dbDescKey, dbDescDesc)
+	}
+	b.CPut(descKey, descDesc, nil)
+	b.Put(dbDescKey, dbDescDesc)
+	if err := params.p.txn.Run(params.ctx, b); err != nil {
+		return err
+	}
+
+	// Log Create Type event. This is an auditable log event and is
+	// recorded in the same transaction as the type descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogCreateType,
+		int32(desc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			TypeName  string
+			Statement string
+			User      string
+		}{name, n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTypeNode) Close(context.Context)        {}
@@ -19,8 +19,9 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
-	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -29,8 +30,10 @@ import (
 
 // createViewNode represents a CREATE VIEW statement.
 type createViewNode struct {
-	n             *tree.CreateView
-	dbDesc        *sqlbase.DatabaseDescriptor
+	n      *tree.CreateView
+	dbDesc *sqlbase.DatabaseDescriptor
+	// scDesc is the user-defined schema of the view, if any.
+	scDesc        *sqlbase.SchemaDescriptor
 	sourceColumns sqlbase.ResultColumns
 	// planDeps tracks which tables and views the view being created
 	// depends on. This is collected during the construction of
@@ -48,8 +51,12 @@ func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode,
 	if err != nil {
 		return nil, err
 	}
+	if sessiondata.IsTemporarySchemaName(n.Name.Schema()) {
+		return nil, pgerror.UnimplementedWithIssueError(5807, "temporary views")
+	}
 
-	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+	scDesc, err := p.checkCreatePrivilegeInSchema(ctx, dbDesc, n.Name.Schema())
+	if err != nil {
 		return nil, err
 	}
 
@@ -65,6 +72,16 @@ func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode,
 		return nil, err
 	}
 
+	// Views are permanent, so they can't depend on temporary tables, which
+	// disappear with their session.
+	for _, dep := range planDeps {
+		if dep.desc.IsTemporary() {
+			return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"cannot create view %q because it depends on temporary table %q",
+				tree.ErrString(&n.Name), dep.desc.Name)
+		}
+	}
+
 	// Ensure that all the table names pretty-print as fully qualified,
 	// so we store that in the view descriptor.
 	//
@@ -104,6 +121,7 @@ func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode,
 	return &createViewNode{
 		n:             n,
 		dbDesc:        dbDesc,
+		scDesc:        scDesc,
 		sourceColumns: sourceColumns,
 		planDeps:      planDeps,
 	}, nil
@@ -111,7 +129,11 @@ func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode,
 
 func (n *createViewNode) startExec(params runParams) error {
 	viewName := n.n.Name.Table()
-	tKey := tableKey{parentID: n.dbDesc.ID, name: viewName}
+	parentID := n.dbDesc.ID
+	if n.scDesc != nil {
+		parentID = n.scDesc.ID
+	}
+	tKey := tableKey{parentID: parentID, name: viewName}
 	key := tKey.Key()
 	if exists, err := descExists(params.ctx, params.p.txn, key); err == nil && exists {
 		// TODO(a-robinson): Support CREATE OR REPLACE commands.
@@ -125,8 +147,12 @@ func (n *createViewNode) startExec(params runParams) error {
 		return err
 	}
 
-	// Inherit permissions from the database descriptor.
+	// Inherit permissions from the database descriptor, or from the
+	// descriptor of the user-defined schema.
 	privs := n.dbDesc.GetPrivileges()
+	if n.scDesc != nil {
+		privs = n.scDesc.GetPrivileges()
+	}
 
 	desc, err := n.makeViewTableDesc(
 		params,
@@ -140,12 +166,21 @@ func (n *createViewNode) startExec(params runParams) error {
 	if err != nil {
 		return err
 	}
+	if n.scDesc != nil {
+		desc.SchemaID = n.scDesc.ID
+	}
 
 	// Collect all the tables/views this view depends on.
 	for backrefID := range n.planDeps {
 		desc.DependsOn = append(desc.DependsOn, backrefID)
 	}
 
+	if desc.MaterializedView() {
+		// The view is populated by the schema changer, which makes it public
+		// once the result of its query has been written.
+		desc.State = sqlbase.TableDescriptor_ADD
+	}
+
 	if err = params.p.createDescriptorWithID(
 		params.ctx, key, id, &desc, params.EvalContext().Settings); err != nil {
 		return err
@@ -215,6 +250,7 @@ func (n *createViewNode) makeViewTableDesc(
 	desc := InitTableDescriptor(id, parentID, viewName,
 		params.p.txn.CommitTimestamp(), privileges)
 	desc.ViewQuery = tree.AsStringWithFlags(n.n.AsSource, tree.FmtParsable)
+	desc.IsMaterializedView = n.n.Materialized
 	for i, colRes := range resultColumns {
 		colType, err := coltypes.DatumTypeToColumnType(colRes.Typ)
 		if err != nil {
@@ -254,6 +290,7 @@ func MakeViewTableDesc(
 	viewName := n.Name.Table()
 	desc := InitTableDescriptor(id, parentID, viewName, creationTime, privileges)
 	desc.ViewQuery = tree.AsStringWithFlags(n.AsSource, tree.FmtParsable)
+	desc.IsMaterializedView = n.Materialized
 
 	for i, colRes := range resultColumns {
 		colType, err := coltypes.DatumTypeToColumnType(colRes.Typ)
@@ -167,6 +167,12 @@ func (p *planner) getDataSource(
 	case *tree.AliasedTableExpr:
 		// Alias clause: source AS alias(cols...)
 
+		if t.Lateral {
+			// LATERAL is only supported by the optimizer.
+			return planDataSource{}, pgerror.UnimplementedWithIssueDetailError(24560,
+				"lateral", "LATERAL is not supported without the cost-based optimizer")
+		}
+
 		if t.IndexFlags != nil {
 			indexFlags = t.IndexFlags
 		}
@@ -176,6 +182,12 @@ func (p *planner) getDataSource(
 			return src, err
 		}
 
+		if len(p.curPlan.locking) > 0 {
+			if err := p.applyLocking(src, t); err != nil {
+				return planDataSource{}, err
+			}
+		}
+
 		if t.Ordinality {
 			// The WITH ORDINALITY clause numbers the rows coming out of the
 			// data source. See the comments next to the definition of
@@ -309,7 +321,8 @@ func (p *planner) getPlanForDesc(
 	indexFlags *tree.IndexFlags,
 	colCfg scanColumnsConfig,
 ) (planDataSource, error) {
-	if desc.IsView() {
+	// Materialized views are read from their stored contents, like tables.
+	if desc.IsView() && !desc.MaterializedView() {
 		if colCfg.wantedColumns != nil {
 			return planDataSource{},
 				errors.Errorf("cannot specify an explicit column list when accessing a view by reference")
@@ -319,7 +332,7 @@ func (p *planner) getPlanForDesc(
 	if desc.IsSequence() {
 		return p.getSequenceSource(ctx, *tn, desc)
 	}
-	if !desc.IsTable() {
+	if !desc.IsTable() && !desc.MaterializedView() {
 		return planDataSource{}, errors.Errorf(
 			"unexpected table descriptor of type %s for %q", desc.TypeName(), tree.ErrString(tn))
 	}
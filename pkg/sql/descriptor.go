@@ -195,6 +195,9 @@ func getDescriptorByID(
 		if err := table.Validate(ctx, txn, nil /* clusterVersion */); err != nil {
 			return err
 		}
+		if err := table.HydrateEnumTypes(ctx, txn); err != nil {
+			return err
+		}
 		*t = *table
 	case *sqlbase.DatabaseDescriptor:
 		database := desc.GetDatabase()
@@ -206,6 +209,36 @@ func getDescriptorByID(
 			return err
 		}
 		*t = *database
+	case *sqlbase.TypeDescriptor:
+		typ := desc.GetType()
+		if typ == nil {
+			return errors.Errorf("%q is not a type", desc.String())
+		}
+
+		if err := typ.Validate(); err != nil {
+			return err
+		}
+		*t = *typ
+	case *sqlbase.FunctionDescriptor:
+		fn := desc.GetFunction()
+		if fn == nil {
+			return errors.Errorf("%q is not a function", desc.String())
+		}
+
+		if err := fn.Validate(); err != nil {
+			return err
+		}
+		*t = *fn
+	case *sqlbase.SchemaDescriptor:
+		sc := desc.GetSchema()
+		if sc == nil {
+			return errors.Errorf("%q is not a schema", desc.String())
+		}
+
+		if err := sc.Validate(); err != nil {
+			return err
+		}
+		*t = *sc
 	}
 	return nil
 }
@@ -230,6 +263,12 @@ func GetAllDescriptors(ctx context.Context, txn *client.Txn) ([]sqlbase.Descript
 			descs[i] = desc.GetTable()
 		case *sqlbase.Descriptor_Database:
 			descs[i] = desc.GetDatabase()
+		case *sqlbase.Descriptor_Type:
+			descs[i] = desc.GetType()
+		case *sqlbase.Descriptor_Function:
+			descs[i] = desc.GetFunction()
+		case *sqlbase.Descriptor_Schema:
+			descs[i] = desc.GetSchema()
 		default:
 			return nil, errors.Errorf("Descriptor.Union has unexpected type %T", t)
 		}
@@ -38,10 +38,47 @@ func (p *planner) Discard(ctx context.Context, s *tree.Discard) (planNode, error
 
 		// DEALLOCATE ALL
 		p.preparedStatements.DeleteAll(ctx)
+
+		// UNLISTEN *
+		if l := p.extendedEvalCtx.NotificationListener; l != nil {
+			l.unlistenAll()
+		}
+
+		// DISCARD TEMP
+		return p.discardTemporaryTables(ctx)
+	case tree.DiscardModeTemp:
+		return p.discardTemporaryTables(ctx)
 	default:
 		return nil, pgerror.NewAssertionErrorf("unknown mode for DISCARD: %d", s.Mode)
 	}
-	return newZeroNode(nil /* columns */), nil
+}
+
+// discardTemporaryTables drops all the temporary tables of the session. The
+// temporary schemas themselves are left in place until the session ends.
+func (p *planner) discardTemporaryTables(ctx context.Context) (planNode, error) {
+	tempSchemaName := p.SessionData().SearchPath.GetTemporarySchemaName()
+	if tempSchemaName == "" {
+		return newZeroNode(nil /* columns */), nil
+	}
+	ie := p.ExecCfg().InternalExecutor
+	schemas, err := getTemporarySchemas(ctx, ie, p.txn, func(scName string) bool {
+		return scName == tempSchemaName
+	})
+	if err != nil {
+		return nil, err
+	}
+	var names tree.TableNames
+	for _, sc := range schemas {
+		tableNames, err := getTemporaryTableNames(ctx, ie, p.txn, sc)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, tableNames...)
+	}
+	if len(names) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+	return p.DropTable(ctx, &tree.DropTable{Names: names, DropBehavior: tree.DropCascade})
 }
 
 func resetSessionVars(ctx context.Context, m *sessionDataMutator) error {
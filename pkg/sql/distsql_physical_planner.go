@@ -210,9 +210,14 @@ func (v *distSQLExprCheckVisitor) VisitPre(expr tree.Expr) (recurse bool, newExp
 	case *tree.DOid:
 		v.err = newQueryNotSupportedError("OID expressions are not supported by distsql")
 		return false, expr
+	case *tree.DEnum:
+		// Expressions are sent to remote nodes as strings, which cannot be
+		// type checked without resolving the user-defined type.
+		v.err = newQueryNotSupportedError("ENUM expressions are not supported by distsql")
+		return false, expr
 	case *tree.CastExpr:
 		switch t.Type.(type) {
-		case *coltypes.TOid:
+		case *coltypes.TOid, *coltypes.TEnum:
 			v.err = newQueryNotSupportedErrorf("cast to %s is not supported by distsql", t.Type)
 			return false, expr
 		}
@@ -368,6 +373,10 @@ func (dsp *DistSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 		return rec, nil
 
 	case *scanNode:
+		if n.isLocking() {
+			// Locking scans lay down intents, which requires the root transaction.
+			return cannotDistribute, newQueryNotSupportedError("locking scans cannot be distributed")
+		}
 		rec := canDistribute
 		if n.softLimit != 0 {
 			// We don't yet recommend distributing plans where soft limits propagate
@@ -404,6 +413,9 @@ func (dsp *DistSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 		if _, err := dsp.checkSupportForNode(n.input); err != nil {
 			return cannotDistribute, err
 		}
+		if n.table.isLocking() {
+			return cannotDistribute, newQueryNotSupportedError("locking scans cannot be distributed")
+		}
 		return shouldDistribute, nil
 
 	case *groupNode:
@@ -842,10 +854,12 @@ func initTableReaderSpec(
 ) (*distsqlpb.TableReaderSpec, distsqlpb.PostProcessSpec, error) {
 	s := distsqlplan.NewTableReaderSpec()
 	*s = distsqlpb.TableReaderSpec{
-		Table:      *n.desc.TableDesc(),
-		Reverse:    n.reverse,
-		IsCheck:    n.run.isCheck,
-		Visibility: n.colCfg.visibility.toDistSQLScanVisibility(),
+		Table:          *n.desc.TableDesc(),
+		Reverse:        n.reverse,
+		IsCheck:        n.run.isCheck,
+		Visibility:     n.colCfg.visibility.toDistSQLScanVisibility(),
+		LockForUpdate:  n.isLocking(),
+		LockWaitPolicy: kvWaitPolicy(n.lockingWaitPolicy),
 
 		// Retain the capacity of the spans slice.
 		Spans: s.Spans[:0],
@@ -1818,9 +1832,11 @@ func (dsp *DistSQLPlanner) createPlanForIndexJoin(
 	}
 
 	joinReaderSpec := distsqlpb.JoinReaderSpec{
-		Table:      *n.index.desc.TableDesc(),
-		IndexIdx:   0,
-		Visibility: n.table.colCfg.visibility.toDistSQLScanVisibility(),
+		Table:          *n.index.desc.TableDesc(),
+		IndexIdx:       0,
+		Visibility:     n.table.colCfg.visibility.toDistSQLScanVisibility(),
+		LockForUpdate:  n.table.isLocking(),
+		LockWaitPolicy: kvWaitPolicy(n.table.lockingWaitPolicy),
 	}
 
 	filter, err := distsqlplan.MakeExpression(
@@ -1886,8 +1902,10 @@ func (dsp *DistSQLPlanner) createPlanForLookupJoin(
 	}
 
 	joinReaderSpec := distsqlpb.JoinReaderSpec{
-		Table: *n.table.desc.TableDesc(),
-		Type:  n.joinType,
+		Table:          *n.table.desc.TableDesc(),
+		Type:           n.joinType,
+		LockForUpdate:  n.table.isLocking(),
+		LockWaitPolicy: kvWaitPolicy(n.table.lockingWaitPolicy),
 	}
 	joinReaderSpec.IndexIdx, err = getIndexIdx(n.table)
 	if err != nil {
@@ -123,6 +123,12 @@ type aggregatorBase struct {
 
 	bucketsAcc mon.BoundAccount
 
+	// orderedSetMemMonitor and orderedSetDiskMonitor are used by the row
+	// containers of ordered-set aggregates. They are only set if temp storage
+	// is enabled and there are ordered-set aggregates.
+	orderedSetMemMonitor  *mon.BytesMonitor
+	orderedSetDiskMonitor *mon.BytesMonitor
+
 	// isScalar can only be set if there are no groupCols, and it means that we
 	// will generate a result row even if there are no input rows. Used for
 	// queries like SELECT MAX(n) FROM t.
@@ -224,6 +230,14 @@ func (ag *aggregatorBase) init(
 		if err != nil {
 			return err
 		}
+		// The aggregated values of ordered-set aggregates are stored in row
+		// containers which can spill to disk.
+		if name := strings.ToLower(aggInfo.Func.String()); builtins.IsOrderedSetAggregate(name) {
+			ag.initOrderedSetMonitors(ctx, flowCtx)
+			if ag.orderedSetDiskMonitor != nil {
+				aggConstructor = ag.makeOrderedSetAggregateConstructor(name, argTypes[0])
+			}
+		}
 
 		ag.funcs[i] = ag.newAggregateFuncHolder(aggConstructor, arguments)
 		if aggInfo.Distinct {
@@ -424,6 +438,7 @@ func (ag *hashAggregator) close() {
 				ag.buckets[bucket].close(ag.Ctx)
 			}
 		}
+		ag.stopOrderedSetMonitors(ag.Ctx)
 		ag.MemMonitor.Stop(ag.Ctx)
 	}
 }
@@ -435,6 +450,7 @@ func (ag *orderedAggregator) close() {
 		if ag.bucket != nil {
 			ag.bucket.close(ag.Ctx)
 		}
+		ag.stopOrderedSetMonitors(ag.Ctx)
 		ag.MemMonitor.Stop(ag.Ctx)
 	}
 }
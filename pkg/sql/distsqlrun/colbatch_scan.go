@@ -89,6 +89,17 @@ func newColBatchScan(
 
 	neededColumns := helper.neededColumns()
 
+	// The CFetcher cannot compute virtual computed columns, which are not
+	// stored in the primary index. Refuse to scan them, so that the flow is
+	// set up with the row-by-row processors instead.
+	if spec.IndexIdx == 0 {
+		for i := range spec.Table.Columns {
+			if col := &spec.Table.Columns[i]; col.Virtual && neededColumns.Contains(i) {
+				return nil, errors.Errorf("virtual computed column %q is not supported", col.Name)
+			}
+		}
+	}
+
 	columnIdxMap := spec.Table.ColumnIdxMapWithMutations(returnMutations)
 	fetcher := row.CFetcher{}
 	if _, _, err := initCRowFetcher(
@@ -97,6 +108,12 @@ func newColBatchScan(
 	); err != nil {
 		return nil, err
 	}
+	if spec.LockForUpdate {
+		if err := checkCanLockForUpdate(flowCtx); err != nil {
+			return nil, err
+		}
+		fetcher.SetLocking(spec.LockWaitPolicy)
+	}
 
 	nSpans := len(spec.Spans)
 	spans := make(roachpb.Spans, nSpans)
@@ -60,7 +60,7 @@ func newIndexBackfiller(
 	}
 	ib.backfiller.chunkBackfiller = ib
 
-	if err := ib.IndexBackfiller.Init(ib.desc); err != nil {
+	if err := ib.IndexBackfiller.Init(ib.flowCtx.NewEvalCtx(), ib.desc); err != nil {
 		return nil, err
 	}
 
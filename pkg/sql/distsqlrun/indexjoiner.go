@@ -101,6 +101,7 @@ func newIndexJoiner(
 	); err != nil {
 		return nil, err
 	}
+	ij.fetcher.SetEvalContext(ij.evalCtx)
 	if _, _, err := initRowFetcher(
 		&ij.fetcher,
 		&ij.desc,
@@ -114,6 +115,12 @@ func newIndexJoiner(
 	); err != nil {
 		return nil, err
 	}
+	if spec.LockForUpdate {
+		if err := checkCanLockForUpdate(flowCtx); err != nil {
+			return nil, err
+		}
+		ij.fetcher.SetLocking(spec.LockWaitPolicy)
+	}
 	ij.fetcherInput = &rowFetcherWrapper{Fetcher: &ij.fetcher}
 
 	if sp := opentracing.SpanFromContext(flowCtx.EvalCtx.Ctx()); sp != nil && tracing.IsRecording(sp) {
@@ -354,6 +354,7 @@ func newInterleavedReaderJoiner(
 		descendantJoinSide: descendantJoinSide,
 	}
 
+	irj.fetcher.SetEvalContext(flowCtx.NewEvalCtx())
 	if err := irj.initRowFetcher(
 		spec.Tables, spec.Reverse, &irj.alloc,
 	); err != nil {
@@ -140,6 +140,11 @@ func newJoinReader(
 	if spec.Visibility != distsqlpb.ScanVisibility_PUBLIC {
 		return nil, pgerror.NewAssertionErrorf("joinReader specified with visibility %+v", spec.Visibility)
 	}
+	if spec.LockForUpdate {
+		if err := checkCanLockForUpdate(flowCtx); err != nil {
+			return nil, err
+		}
+	}
 
 	jr := &joinReader{
 		desc:                 spec.Table,
@@ -216,6 +221,7 @@ func newJoinReader(
 		// needed output columns.
 		neededIndexColumns = getIndexColSet(&jr.desc.PrimaryIndex, jr.colIdxMap)
 		jr.primaryFetcher = &row.Fetcher{}
+		jr.primaryFetcher.SetEvalContext(jr.evalCtx)
 		_, _, err = initRowFetcher(
 			jr.primaryFetcher, &jr.desc, 0 /* indexIdx */, jr.colIdxMap, false, /* reverse */
 			jr.neededRightCols(), false /* isCheck */, &jr.alloc,
@@ -224,6 +230,9 @@ func newJoinReader(
 		if err != nil {
 			return nil, err
 		}
+		if spec.LockForUpdate {
+			jr.primaryFetcher.SetLocking(spec.LockWaitPolicy)
+		}
 		jr.primaryColumnTypes, err = getPrimaryColumnTypes(&jr.desc)
 		if err != nil {
 			return nil, err
@@ -235,6 +244,7 @@ func newJoinReader(
 			jr.primaryFetcherInput = NewInputStatCollector(jr.primaryFetcherInput)
 		}
 	}
+	jr.fetcher.SetEvalContext(jr.evalCtx)
 	_, _, err = initRowFetcher(
 		&jr.fetcher, &jr.desc, int(spec.IndexIdx), jr.colIdxMap, false, /* reverse */
 		neededIndexColumns, false /* isCheck */, &jr.alloc,
@@ -243,6 +253,9 @@ func newJoinReader(
 	if err != nil {
 		return nil, err
 	}
+	if spec.LockForUpdate {
+		jr.fetcher.SetLocking(spec.LockWaitPolicy)
+	}
 	jr.fetcherInput = &rowFetcherWrapper{Fetcher: &jr.fetcher}
 	if collectingStats {
 		jr.input = NewInputStatCollector(jr.input)
@@ -0,0 +1,157 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"context"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// orderedSetAggregate is the tree.AggregateFunc used by aggregators for
+// ordered-set aggregates (e.g. percentile_disc) when temp storage is enabled.
+// Unlike the builtin implementation, which keeps the aggregated values of a
+// group in memory, it stores them in a diskBackedRowContainer which falls
+// back to disk once the memory limit of the aggregator's ordered-set
+// aggregates is reached.
+type orderedSetAggregate struct {
+	name      string
+	evalCtx   *tree.EvalContext
+	arguments tree.Datums
+	typ       sqlbase.ColumnType
+
+	rows       diskBackedRowContainer
+	datumAlloc sqlbase.DatumAlloc
+}
+
+var _ tree.AggregateFunc = &orderedSetAggregate{}
+
+const sizeOfOrderedSetAggregate = int64(unsafe.Sizeof(orderedSetAggregate{}))
+
+// initOrderedSetMonitors sets up the monitors used by the row containers of
+// ordered-set aggregates, if temp storage is enabled. The monitors are left
+// nil otherwise, in which case the in-memory builtin implementation is used.
+func (ag *aggregatorBase) initOrderedSetMonitors(ctx context.Context, flowCtx *FlowCtx) {
+	if ag.orderedSetDiskMonitor != nil {
+		return
+	}
+	useTempStorage := settingUseTempStorageSorts.Get(&flowCtx.Settings.SV) ||
+		flowCtx.testingKnobs.MemoryLimitBytes > 0
+	if !useTempStorage {
+		return
+	}
+	// Limit the memory use by creating a child monitor with a hard limit. The
+	// row containers will overflow to disk if this limit is not enough.
+	limit := flowCtx.testingKnobs.MemoryLimitBytes
+	if limit <= 0 {
+		limit = settingWorkMemBytes.Get(&flowCtx.Settings.SV)
+	}
+	limitedMon := mon.MakeMonitorInheritWithLimit(
+		"aggregator-ordered-set-limited", limit, flowCtx.EvalCtx.Mon,
+	)
+	limitedMon.Start(ctx, flowCtx.EvalCtx.Mon, mon.BoundAccount{})
+	ag.orderedSetMemMonitor = &limitedMon
+	ag.orderedSetDiskMonitor = NewMonitor(ctx, flowCtx.diskMonitor, "aggregator-ordered-set-disk")
+}
+
+// stopOrderedSetMonitors stops the monitors set up by initOrderedSetMonitors.
+// All the buckets must have been closed.
+func (ag *aggregatorBase) stopOrderedSetMonitors(ctx context.Context) {
+	if ag.orderedSetDiskMonitor != nil {
+		ag.orderedSetMemMonitor.Stop(ctx)
+		ag.orderedSetDiskMonitor.Stop(ctx)
+	}
+}
+
+// makeOrderedSetAggregateConstructor returns a constructor for the
+// ordered-set aggregate with the given name over values of the given type.
+// initOrderedSetMonitors must have set up the monitors.
+func (ag *aggregatorBase) makeOrderedSetAggregateConstructor(
+	name string, typ sqlbase.ColumnType,
+) func(*tree.EvalContext, tree.Datums) tree.AggregateFunc {
+	return func(evalCtx *tree.EvalContext, arguments tree.Datums) tree.AggregateFunc {
+		a := &orderedSetAggregate{
+			name:      name,
+			evalCtx:   evalCtx,
+			arguments: arguments,
+			typ:       typ,
+		}
+		a.rows.init(
+			sqlbase.ColumnOrdering{{ColIdx: 0, Direction: encoding.Ascending}},
+			[]sqlbase.ColumnType{typ},
+			evalCtx,
+			ag.flowCtx.TempStorage,
+			ag.orderedSetMemMonitor,
+			ag.orderedSetDiskMonitor,
+		)
+		return a
+	}
+}
+
+// Add is part of the tree.AggregateFunc interface. NULLs are ignored.
+func (a *orderedSetAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	return a.rows.AddRow(ctx, sqlbase.EncDatumRow{sqlbase.DatumToEncDatum(a.typ, datum)})
+}
+
+// Result is part of the tree.AggregateFunc interface.
+func (a *orderedSetAggregate) Result() (tree.Datum, error) {
+	ctx := a.evalCtx.Ctx()
+	n := a.rows.Len()
+	if n == 0 {
+		return tree.DNull, nil
+	}
+	a.rows.Sort(ctx)
+	i := a.rows.NewIterator(ctx)
+	defer i.Close()
+	i.Rewind()
+	return builtins.EvalOrderedSetAggregate(
+		a.name, a.evalCtx, a.arguments, n, func() (tree.Datum, error) {
+			if ok, err := i.Valid(); err != nil {
+				return nil, err
+			} else if !ok {
+				return nil, pgerror.NewAssertionErrorf("expected %d values in %s", n, a.name)
+			}
+			row, err := i.Row()
+			if err != nil {
+				return nil, err
+			}
+			if err := row[0].EnsureDecoded(&a.typ, &a.datumAlloc); err != nil {
+				return nil, err
+			}
+			d := row[0].Datum
+			i.Next()
+			return d, nil
+		},
+	)
+}
+
+// Close is part of the tree.AggregateFunc interface.
+func (a *orderedSetAggregate) Close(ctx context.Context) {
+	a.rows.Close(ctx)
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *orderedSetAggregate) Size() int64 {
+	return sizeOfOrderedSetAggregate
+}
@@ -124,6 +124,7 @@ func newScrubTableReader(
 		}
 	}
 
+	tr.fetcher.SetEvalContext(tr.evalCtx)
 	if _, _, err := initRowFetcher(
 		&tr.fetcher, &tr.tableDesc, int(spec.IndexIdx), tr.tableDesc.ColumnIdxMap(), spec.Reverse,
 		neededColumns, true /* isCheck */, &tr.alloc,
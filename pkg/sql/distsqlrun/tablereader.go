@@ -18,8 +18,10 @@ import (
 	"context"
 	"sync"
 
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -110,12 +112,19 @@ func newTableReader(
 	neededColumns := tr.out.neededColumns()
 
 	columnIdxMap := spec.Table.ColumnIdxMapWithMutations(returnMutations)
+	tr.fetcher.SetEvalContext(tr.evalCtx)
 	if _, _, err := initRowFetcher(
 		&tr.fetcher, &spec.Table, int(spec.IndexIdx), columnIdxMap, spec.Reverse,
 		neededColumns, spec.IsCheck, &tr.alloc, spec.Visibility,
 	); err != nil {
 		return nil, err
 	}
+	if spec.LockForUpdate {
+		if err := checkCanLockForUpdate(flowCtx); err != nil {
+			return nil, err
+		}
+		tr.fetcher.SetLocking(spec.LockWaitPolicy)
+	}
 
 	nSpans := len(spec.Spans)
 	if cap(tr.spans) >= nSpans {
@@ -202,6 +211,15 @@ func initRowFetcher(
 	return index, isSecondaryIndex, nil
 }
 
+// checkCanLockForUpdate verifies that a processor that locks the rows it reads
+// runs with the root transaction: leaf transactions cannot lay down intents.
+func checkCanLockForUpdate(flowCtx *FlowCtx) error {
+	if flowCtx.txn == nil || flowCtx.txn.Type() != client.RootTxn {
+		return pgerror.NewAssertionErrorf("locking reads require the root transaction")
+	}
+	return nil
+}
+
 func (tr *tableReader) generateTrailingMeta(ctx context.Context) []ProducerMetadata {
 	var trailingMeta []ProducerMetadata
 	if !tr.ignoreMisplannedRanges {
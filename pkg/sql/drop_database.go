@@ -68,6 +68,14 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 	if err != nil {
 		return nil, err
 	}
+	// The tables of the user-defined schemas of the database go with it too.
+	for _, sc := range dbDesc.Schemas {
+		scTbNames, err := GetObjectNames(ctx, p.txn, p, dbDesc, sc.Name, true /*explicitPrefix*/)
+		if err != nil {
+			return nil, err
+		}
+		tbNames = append(tbNames, scTbNames...)
+	}
 
 	if len(tbNames) > 0 {
 		switch n.DropBehavior {
@@ -171,6 +179,31 @@ func (n *dropDatabaseNode) startExec(params runParams) error {
 	b.Del(descKey)
 	b.Del(nameKey)
 
+	// The user-defined types of the database are dropped with it.
+	for _, t := range n.dbDesc.Types {
+		typDescKey := sqlbase.MakeDescMetadataKey(t.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", typDescKey)
+		}
+		b.Del(typDescKey)
+	}
+	// So are its user-defined functions.
+	for _, f := range n.dbDesc.Functions {
+		fnDescKey := sqlbase.MakeDescMetadataKey(f.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", fnDescKey)
+		}
+		b.Del(fnDescKey)
+	}
+	// And its user-defined schemas.
+	for _, sc := range n.dbDesc.Schemas {
+		scDescKey := sqlbase.MakeDescMetadataKey(sc.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", scDescKey)
+		}
+		b.Del(scDescKey)
+	}
+
 	// No job was created because no tables were dropped, so zone config can be
 	// immediately removed.
 	if jobID == 0 {
@@ -0,0 +1,135 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type dropFunctionNode struct {
+	n      *tree.DropFunction
+	dbDesc *sqlbase.DatabaseDescriptor
+	fnDesc *sqlbase.FunctionDescriptor
+}
+
+// DropFunction drops a user-defined function.
+// Privileges: DROP on function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /*required*/)
+	if err != nil {
+		return nil, err
+	}
+
+	fnDescs, err := getFunctionDescs(ctx, p.txn, dbDesc, string(n.Name))
+	if err != nil {
+		return nil, err
+	}
+	var fnDesc *sqlbase.FunctionDescriptor
+	if n.HasArgs {
+		argTypes := make([]types.T, len(n.Args))
+		for i, arg := range n.Args {
+			typ, err := p.semaCtx.ResolveCastTargetType(arg.Type)
+			if err != nil {
+				return nil, err
+			}
+			argTypes[i] = coltypes.CastTargetToDatumType(typ)
+		}
+		for _, d := range fnDescs {
+			if typesEquivalent(d.ArgDatumTypes(), argTypes) {
+				fnDesc = d
+				break
+			}
+		}
+	} else if len(fnDescs) > 1 {
+		return nil, pgerror.NewErrorf(pgerror.CodeAmbiguousFunctionError,
+			"function name %q is not unique", string(n.Name)).SetHintf(
+			"Specify the argument list to select the function unambiguously.")
+	} else if len(fnDescs) == 1 {
+		fnDesc = fnDescs[0]
+	}
+
+	if fnDesc == nil {
+		if n.IfExists {
+			return newZeroNode(nil /* columns */), nil
+		}
+		name := tree.ErrString(&n.Name)
+		if n.HasArgs {
+			name += tree.ErrString(&n.Args)
+		}
+		return nil, pgerror.NewErrorf(pgerror.CodeUndefinedFunctionError,
+			"function %s does not exist", name)
+	}
+
+	if err := p.CheckPrivilege(ctx, fnDesc, privilege.DROP); err != nil {
+		return nil, err
+	}
+
+	return &dropFunctionNode{n: n, dbDesc: dbDesc, fnDesc: fnDesc}, nil
+}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	ctx := params.ctx
+	b := &client.Batch{}
+	descKey := sqlbase.MakeDescMetadataKey(n.fnDesc.ID)
+	if params.p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Del %s", descKey)
+	}
+	b.Del(descKey)
+
+	n.dbDesc.RemoveFunction(n.fnDesc.ID)
+	if err := n.dbDesc.Validate(); err != nil {
+		return err
+	}
+	dbDescKey := sqlbase.MakeDescMetadataKey(n.dbDesc.ID)
+	dbDescDesc := sqlbase.WrapDescriptor(n.dbDesc)
+	if params.p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Put %s -> %s", dbDescKey, dbDescDesc)
+	}
+	b.Put(dbDescKey, dbDescDesc)
+	if err := params.p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	// Log Drop Function event. This is an auditable log event and is
+	// recorded in the same transaction as the function descriptor update.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		ctx,
+		params.p.txn,
+		EventLogDropFunction,
+		int32(n.fnDesc.ID),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			FunctionName string
+			Statement    string
+			User         string
+		}{n.fnDesc.Signature(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*dropFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropFunctionNode) Close(context.Context)        {}
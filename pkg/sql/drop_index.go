@@ -181,7 +181,7 @@ func (p *planner) dropIndexByName(
 		}
 	}
 
-	if idx.Unique && behavior != tree.DropCascade && constraintBehavior != ignoreIdxConstraint {
+	if (idx.Unique || idx.DeferrableUnique) && behavior != tree.DropCascade && constraintBehavior != ignoreIdxConstraint {
 		return errors.Errorf("index %q is in use as unique constraint (use CASCADE if you really want to drop it)", idx.Name)
 	}
 
@@ -222,6 +222,18 @@ func (p *planner) dropIndexByName(
 		return fmt.Errorf("index %q in the middle of being added, try again later", idxName)
 	}
 
+	// The shard column of a hash sharded index is dropped along with the last
+	// index using it.
+	if idx.IsSharded() && !shardColumnInUse(tableDesc, idx.Sharded.Name, idx.ID) {
+		for i := range tableDesc.Columns {
+			if tableDesc.Columns[i].Name == idx.Sharded.Name {
+				tableDesc.AddColumnMutation(tableDesc.Columns[i], sqlbase.DescriptorMutation_DROP)
+				tableDesc.Columns = append(tableDesc.Columns[:i], tableDesc.Columns[i+1:]...)
+				break
+			}
+		}
+	}
+
 	if err := tableDesc.Validate(ctx, p.txn, p.EvalContext().Settings); err != nil {
 		return err
 	}
@@ -0,0 +1,207 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type dropSchemaNode struct {
+	n      *tree.DropSchema
+	dbDesc *sqlbase.DatabaseDescriptor
+	sd     []*sqlbase.SchemaDescriptor
+	td     []toDelete
+}
+
+// DropSchema drops user-defined schemas.
+// Privileges: DROP on schema and DROP on all tables in the schema.
+//   Notes: postgres allows only the schema owner to DROP a schema.
+//
+// Unlike DROP DATABASE, the default behavior is RESTRICT: non-empty schemas
+// are only dropped when CASCADE is specified.
+func (p *planner) DropSchema(ctx context.Context, n *tree.DropSchema) (planNode, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /*required*/)
+	if err != nil {
+		return nil, err
+	}
+
+	sd := make([]*sqlbase.SchemaDescriptor, 0, len(n.Names))
+	var tbNames TableNames
+	for _, name := range n.Names {
+		scDesc, err := getSchemaDesc(ctx, p.txn, dbDesc, string(name))
+		if err != nil {
+			return nil, err
+		}
+		if scDesc == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, sqlbase.NewUndefinedSchemaError(string(name))
+		}
+
+		if err := p.CheckPrivilege(ctx, scDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+
+		scTbNames, err := GetObjectNames(ctx, p.txn, p, dbDesc, scDesc.Name, true /*explicitPrefix*/)
+		if err != nil {
+			return nil, err
+		}
+		if len(scTbNames) > 0 && n.DropBehavior != tree.DropCascade {
+			return nil, pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
+				"schema %q is not empty and CASCADE was not specified",
+				tree.ErrNameString(&scDesc.Name))
+		}
+		tbNames = append(tbNames, scTbNames...)
+
+		sd = append(sd, scDesc)
+	}
+
+	if len(sd) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	td := make([]toDelete, 0, len(tbNames))
+	for i := range tbNames {
+		tbDesc, err := p.prepareDrop(ctx, &tbNames[i], false /*required*/, anyDescType)
+		if err != nil {
+			return nil, err
+		}
+		if tbDesc == nil {
+			continue
+		}
+		// Recursively check permissions on all dependent views, since some may
+		// be in other schemas.
+		for _, ref := range tbDesc.DependedOnBy {
+			if err := p.canRemoveDependentView(ctx, tbDesc, ref, tree.DropCascade); err != nil {
+				return nil, err
+			}
+		}
+		td = append(td, toDelete{&tbNames[i], tbDesc})
+	}
+
+	td, err = p.filterCascadedTables(ctx, td)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dropSchemaNode{n: n, dbDesc: dbDesc, sd: sd, td: td}, nil
+}
+
+func (n *dropSchemaNode) startExec(params runParams) error {
+	ctx := params.ctx
+	p := params.p
+	tbNameStrings := make([]string, 0, len(n.td))
+	droppedTableDetails := make([]jobspb.DroppedTableDetails, 0, len(n.td))
+	tableDescs := make([]*sqlbase.MutableTableDescriptor, 0, len(n.td))
+
+	for _, toDel := range n.td {
+		if toDel.desc.IsView() {
+			continue
+		}
+		droppedTableDetails = append(droppedTableDetails, jobspb.DroppedTableDetails{
+			Name: toDel.tn.FQString(),
+			ID:   toDel.desc.ID,
+		})
+		tableDescs = append(tableDescs, toDel.desc)
+	}
+
+	if _, err := p.createDropTablesJob(
+		ctx,
+		tableDescs,
+		droppedTableDetails,
+		tree.AsStringWithFlags(n.n, tree.FmtAlwaysQualifyTableNames),
+		true, /* drainNames */
+		sqlbase.InvalidID /* droppedDatabaseID */); err != nil {
+		return err
+	}
+
+	for _, toDel := range n.td {
+		tbDesc := toDel.desc
+		if tbDesc.IsView() {
+			cascadedViews, err := p.dropViewImpl(ctx, tbDesc, tree.DropCascade)
+			if err != nil {
+				return err
+			}
+			tbNameStrings = append(tbNameStrings, cascadedViews...)
+		} else {
+			cascadedViews, err := p.dropTableImpl(params, tbDesc)
+			if err != nil {
+				return err
+			}
+			tbNameStrings = append(tbNameStrings, cascadedViews...)
+		}
+		tbNameStrings = append(tbNameStrings, toDel.tn.FQString())
+	}
+
+	b := &client.Batch{}
+	for _, scDesc := range n.sd {
+		descKey := sqlbase.MakeDescMetadataKey(scDesc.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", descKey)
+		}
+		b.Del(descKey)
+		n.dbDesc.RemoveSchema(scDesc.Name)
+	}
+	if err := n.dbDesc.Validate(); err != nil {
+		return err
+	}
+	dbDescKey := sqlbase.MakeDescMetadataKey(n.dbDesc.ID)
+	dbDescDesc := sqlbase.WrapDescriptor(n.dbDesc)
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Put %s -> %s", dbDescKey, dbDescDesc)
+	}
+	b.Put(dbDescKey, dbDescDesc)
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	for _, scDesc := range n.sd {
+		// Log a Drop Schema event for this schema. This is an auditable log
+		// event and is recorded in the same transaction as the schema
+		// descriptor update.
+		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+			ctx,
+			p.txn,
+			EventLogDropSchema,
+			int32(scDesc.ID),
+			int32(params.extendedEvalCtx.NodeID),
+			struct {
+				SchemaName           string
+				Statement            string
+				User                 string
+				DroppedSchemaObjects []string
+			}{scDesc.Name, n.n.String(), p.SessionData().User, tbNameStrings},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropSchemaNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropSchemaNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropSchemaNode) Close(context.Context)        {}
@@ -0,0 +1,132 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type dropTypeNode struct {
+	n      *tree.DropType
+	dbDesc *sqlbase.DatabaseDescriptor
+	td     []*sqlbase.TypeDescriptor
+}
+
+// DropType drops user-defined types.
+// Privileges: DROP on type.
+func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, error) {
+	if n.DropBehavior == tree.DropCascade {
+		return nil, pgerror.UnimplementedWithIssueError(24873, "DROP TYPE ... CASCADE is not supported")
+	}
+	if p.CurrentDatabase() == "" {
+		return nil, errNoDatabase
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /*required*/)
+	if err != nil {
+		return nil, err
+	}
+
+	td := make([]*sqlbase.TypeDescriptor, 0, len(n.Names))
+	for _, name := range n.Names {
+		typDesc, err := getTypeDesc(ctx, p.txn, dbDesc, string(name))
+		if err != nil {
+			return nil, err
+		}
+		if typDesc == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, tree.NewUndefinedTypeError(string(name))
+		}
+
+		if err := p.CheckPrivilege(ctx, typDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+
+		tableIDs, err := tablesReferencingType(ctx, p.txn, typDesc.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(tableIDs) > 0 {
+			return nil, pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
+				"cannot drop type %q because other objects depend on it", typDesc.Name)
+		}
+
+		td = append(td, typDesc)
+	}
+
+	if len(td) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	return &dropTypeNode{n: n, dbDesc: dbDesc, td: td}, nil
+}
+
+func (n *dropTypeNode) startExec(params runParams) error {
+	ctx := params.ctx
+	b := &client.Batch{}
+	for _, typDesc := range n.td {
+		descKey := sqlbase.MakeDescMetadataKey(typDesc.ID)
+		if params.p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", descKey)
+		}
+		b.Del(descKey)
+		n.dbDesc.RemoveType(typDesc.Name)
+	}
+	if err := n.dbDesc.Validate(); err != nil {
+		return err
+	}
+	dbDescKey := sqlbase.MakeDescMetadataKey(n.dbDesc.ID)
+	dbDescDesc := sqlbase.WrapDescriptor(n.dbDesc)
+	if params.p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "Put %s -> %s", dbDescKey, dbDescDesc)
+	}
+	b.Put(dbDescKey, dbDescDesc)
+	if err := params.p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	for _, typDesc := range n.td {
+		// Log a Drop Type event for this type. This is an auditable log event
+		// and is recorded in the same transaction as the type descriptor
+		// update.
+		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+			ctx,
+			params.p.txn,
+			EventLogDropType,
+			int32(typDesc.ID),
+			int32(params.extendedEvalCtx.NodeID),
+			struct {
+				TypeName  string
+				Statement string
+				User      string
+			}{typDesc.Name, n.n.String(), params.SessionData().User},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropTypeNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropTypeNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropTypeNode) Close(context.Context)        {}
@@ -128,6 +128,28 @@ func (n *DropUserNode) startExec(params runParams) error {
 		return err
 	}
 	lCtx := newInternalLookupCtx(descs, nil /*prefix - we want all descriptors */)
+
+	// Then check all the user-defined schemas.
+	for _, desc := range descs {
+		scDesc, ok := desc.(*sqlbase.SchemaDescriptor)
+		if !ok {
+			continue
+		}
+		for _, u := range scDesc.GetPrivileges().Users {
+			if _, ok := userNames[u.User]; ok {
+				if f.Len() > 0 {
+					f.WriteString(", ")
+				}
+				if dbName, ok := lCtx.dbNames[scDesc.ParentID]; ok {
+					f.FormatName(dbName)
+					f.WriteByte('.')
+				}
+				f.FormatNameP(&scDesc.Name)
+				break
+			}
+		}
+	}
+
 	for _, tbID := range lCtx.tbIDs {
 		table := lCtx.tbDescs[tbID]
 		if !tableIsVisible(table, true /*allowAdding*/) {
@@ -69,7 +69,7 @@ func TestUnimplementedCounts(t *testing.T) {
 	s, db, _ := serverutils.StartServer(t, params)
 	defer s.Stopper().Stop(context.TODO())
 
-	if _, err := db.Exec("CREATE TABLE t(x INT8)"); err != nil {
+	if _, err := db.Exec("CREATE TABLE t(x INT8 PRIMARY KEY)"); err != nil {
 		t.Fatal(err)
 	}
 
@@ -320,6 +320,10 @@ type ExecutorConfig struct {
 	AuditLogger      *log.SecondaryLogger
 	InternalExecutor *InternalExecutor
 
+	// NotificationRegistry delivers the notifications sent with NOTIFY to the
+	// sessions of this node which LISTEN to them.
+	NotificationRegistry *NotificationRegistry
+
 	TestingKnobs              *ExecutorTestingKnobs
 	SchemaChangerTestingKnobs *SchemaChangerTestingKnobs
 	DistSQLRunTestingKnobs    *distsqlrun.TestingKnobs
@@ -639,6 +643,7 @@ func checkResultType(typ types.T) error {
 			}
 		case istype(types.FamCollatedString):
 		case istype(types.FamTuple):
+		case istype(types.FamEnum):
 		case istype(types.FamPlaceholder):
 			return errors.Errorf("could not determine data type of %s", typ)
 		default:
@@ -813,6 +818,13 @@ func (r *SessionRegistry) deregister(id ClusterWideID) {
 	r.Unlock()
 }
 
+func (r *SessionRegistry) hasSession(id ClusterWideID) bool {
+	r.Lock()
+	defer r.Unlock()
+	_, ok := r.sessions[id]
+	return ok
+}
+
 type registrySession interface {
 	user() string
 	cancelQuery(queryID ClusterWideID) bool
@@ -1657,7 +1669,12 @@ func (m *sessionDataMutator) SetSafeUpdates(val bool) {
 }
 
 func (m *sessionDataMutator) SetSearchPath(val sessiondata.SearchPath) {
-	m.data.SearchPath = val
+	// The temporary schema of the session survives changes to search_path.
+	m.data.SearchPath = val.WithTemporarySchemaName(m.data.SearchPath.GetTemporarySchemaName())
+}
+
+func (m *sessionDataMutator) SetTemporarySchemaName(val string) {
+	m.data.SearchPath = m.data.SearchPath.WithTemporarySchemaName(val)
 }
 
 func (m *sessionDataMutator) SetLocation(loc *time.Location) {
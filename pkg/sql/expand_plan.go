@@ -286,6 +286,12 @@ func doExpandPlan(
 	case *max1RowNode:
 		n.plan, err = doExpandPlan(ctx, p, noParams, n.plan)
 
+	case *recursiveCTENode:
+		n.initial, err = doExpandPlan(ctx, p, noParams, n.initial)
+
+	case *applyJoinNode:
+		n.input.plan, err = doExpandPlan(ctx, p, noParams, n.input.plan)
+
 	case *sortNode:
 		if !n.ordering.IsPrefixOf(params.desiredOrdering) {
 			params.desiredOrdering = n.ordering
@@ -346,11 +352,14 @@ func doExpandPlan(
 
 	case *valuesNode:
 	case *virtualTableNode:
+	case *scanBufferNode:
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *commentOnTableNode:
+	case *refreshViewNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
 	case *renameIndexNode:
@@ -362,12 +371,18 @@ func doExpandPlan(
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
+	case *createFunctionNode:
+	case *createSchemaNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropTypeNode:
+	case *dropFunctionNode:
+	case *dropSchemaNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
@@ -759,6 +774,12 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *max1RowNode:
 		n.plan = p.simplifyOrderings(n.plan, usefulOrdering)
 
+	case *recursiveCTENode:
+		n.initial = p.simplifyOrderings(n.initial, nil)
+
+	case *applyJoinNode:
+		n.input.plan = p.simplifyOrderings(n.input.plan, nil)
+
 	case *spoolNode:
 		n.source = p.simplifyOrderings(n.source, usefulOrdering)
 
@@ -857,11 +878,14 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 
 	case *valuesNode:
 	case *virtualTableNode:
+	case *scanBufferNode:
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
 	case *commentOnTableNode:
+	case *refreshViewNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
 	case *renameIndexNode:
@@ -873,12 +897,18 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
+	case *createFunctionNode:
+	case *createSchemaNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropTypeNode:
+	case *dropFunctionNode:
+	case *dropSchemaNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
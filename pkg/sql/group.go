@@ -590,7 +590,7 @@ var _ tree.Visitor = &extractAggregatesVisitor{}
 // an IndexedVar that refers to the index of the function.
 func (v *extractAggregatesVisitor) addAggregation(f *aggregateFuncHolder) *tree.IndexedVar {
 	for i, g := range v.groupNode.funcs {
-		if aggregateFuncsEqual(f, g) {
+		if aggregateFuncsEqual(v.planner.EvalContext(), f, g) {
 			return v.ivarHelper.IndexedVarWithType(i, f.resultType)
 		}
 	}
@@ -837,9 +837,10 @@ func (a *aggregateFuncHolder) isDistinct() bool {
 	return a.run.seen != nil
 }
 
-func aggregateFuncsEqual(a, b *aggregateFuncHolder) bool {
+func aggregateFuncsEqual(evalCtx *tree.EvalContext, a, b *aggregateFuncHolder) bool {
 	return a.funcName == b.funcName && a.resultType == b.resultType &&
-		a.argRenderIdx == b.argRenderIdx && a.filterRenderIdx == b.filterRenderIdx
+		a.argRenderIdx == b.argRenderIdx && a.filterRenderIdx == b.filterRenderIdx &&
+		!a.arguments.IsDistinctFrom(evalCtx, b.arguments)
 }
 
 func (a *aggregateFuncHolder) close(ctx context.Context) {
@@ -0,0 +1,218 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// Hash sharded indexes avoid the single hot range that an index on a
+// sequential key, like a timestamp or a counter, turns into under a write
+// heavy workload. An index created with USING HASH WITH BUCKET_COUNT = n is
+// prefixed with a hidden computed column, crdb_internal_<columns>_shard_<n>,
+// whose value is a hash of the indexed columns modulo n: consecutive rows are
+// thus written to n different parts of the index, which are pre-split into
+// separate ranges when the index is created.
+//
+// The shard column is an ordinary stored computed column, so rows are written
+// to the right shard by the usual computed column machinery. The optimizer
+// knows how the shard is computed (see opt.Index.Sharding): it adds the shard
+// to constraints on the indexed columns, and scans all the shards and merges
+// their results when an ordering on the indexed columns is required.
+
+var hashShardedRangePreSplitMax = settings.RegisterNonNegativeIntSetting(
+	"sql.hash_sharded_range_pre_split.max",
+	"the maximum number of ranges a hash sharded index is split into when it is created",
+	16,
+)
+
+// evalShardBucketCount evaluates the BUCKET_COUNT of a hash sharded index.
+func evalShardBucketCount(
+	shardBuckets tree.Expr, semaCtx *tree.SemaContext, evalCtx *tree.EvalContext,
+) (int32, error) {
+	typedExpr, err := sqlbase.SanitizeVarFreeExpr(
+		shardBuckets, types.Int, "BUCKET_COUNT", semaCtx, evalCtx, false, /* allowImpure */
+	)
+	if err != nil {
+		return 0, err
+	}
+	d, err := typedExpr.Eval(evalCtx)
+	if err != nil {
+		return 0, err
+	}
+	buckets, ok := tree.AsDInt(d)
+	if !ok || buckets < 2 {
+		return 0, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"BUCKET_COUNT must be an integer greater than 1, got %s", tree.AsString(d))
+	}
+	if buckets > math.MaxInt32 {
+		return 0, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"BUCKET_COUNT must be at most %d, got %d", math.MaxInt32, buckets)
+	}
+	return int32(buckets), nil
+}
+
+// makeShardColumnDesc returns the descriptor of the hidden shard column of a
+// hash sharded index on the given columns.
+func makeShardColumnDesc(
+	colNames []string, buckets int32, semaCtx *tree.SemaContext, evalCtx *tree.EvalContext,
+) (*sqlbase.ColumnDescriptor, error) {
+	expr, err := parser.ParseExpr(sqlbase.MakeHashShardComputeExpr(colNames, buckets))
+	if err != nil {
+		return nil, err
+	}
+	def := &tree.ColumnTableDef{
+		Name: tree.Name(sqlbase.GetShardColumnName(colNames, buckets)),
+		Type: coltypes.Int4,
+	}
+	def.Nullable.Nullability = tree.NotNull
+	def.Computed.Computed = true
+	def.Computed.Expr = expr
+	col, _, _, err := sqlbase.MakeColumnDefDescs(def, semaCtx, evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	col.Hidden = true
+	return col, nil
+}
+
+// setupShardedIndex turns idx, whose columns have already been filled in from
+// the index definition, into a hash sharded index: the shard column is
+// prepended to the columns of the index. It returns the descriptor of the
+// shard column if the caller needs to add it to the table, or nil if the
+// column already exists because another hash sharded index on the same
+// columns uses it.
+func setupShardedIndex(
+	desc *sqlbase.MutableTableDescriptor,
+	idx *sqlbase.IndexDescriptor,
+	sharded *tree.ShardedIndexDef,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+) (*sqlbase.ColumnDescriptor, error) {
+	if idx.Type == sqlbase.IndexDescriptor_INVERTED {
+		return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"inverted indexes can't be hash sharded")
+	}
+	buckets, err := evalShardBucketCount(sharded.ShardBuckets, semaCtx, evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	colNames := idx.ColumnNames
+	shardCol, err := makeShardColumnDesc(colNames, buckets, semaCtx, evalCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, dropped, err := desc.FindColumnByName(tree.Name(shardCol.Name))
+	if err == nil {
+		if dropped {
+			return nil, fmt.Errorf("column %q being dropped, try again later", existing.Name)
+		}
+		if !existing.Hidden || !existing.IsComputed() || *existing.ComputeExpr != *shardCol.ComputeExpr {
+			return nil, pgerror.NewErrorf(pgerror.CodeDuplicateColumnError,
+				"column %q already exists and is not the shard column of a hash sharded index",
+				existing.Name)
+		}
+		shardCol = nil
+	}
+
+	idx.ColumnNames = append([]string{sqlbase.GetShardColumnName(colNames, buckets)}, colNames...)
+	idx.ColumnDirections = append(
+		[]sqlbase.IndexDescriptor_Direction{sqlbase.IndexDescriptor_ASC}, idx.ColumnDirections...,
+	)
+	idx.Sharded = sqlbase.ShardedDescriptor{
+		IsSharded:    true,
+		Name:         idx.ColumnNames[0],
+		ShardBuckets: buckets,
+		ColumnNames:  colNames,
+	}
+	return shardCol, nil
+}
+
+// shardColumnInUse returns true if an index of the table other than the given
+// one, including the indexes being added, is hash sharded with the given
+// shard column.
+func shardColumnInUse(
+	desc *sqlbase.MutableTableDescriptor, shardColName string, except sqlbase.IndexID,
+) bool {
+	for _, idx := range desc.AllNonDropIndexes() {
+		if idx.ID != except && idx.IsSharded() && idx.Sharded.Name == shardColName {
+			return true
+		}
+	}
+	return false
+}
+
+// preSplitShardedIndex splits a newly created hash sharded index at the
+// boundaries of its buckets, so that writes to the different buckets go to
+// different ranges from the start. There are at most
+// sql.hash_sharded_range_pre_split.max ranges; when there are more buckets,
+// each range spans several of them.
+func (p *planner) preSplitShardedIndex(
+	ctx context.Context, desc *sqlbase.TableDescriptor, idx *sqlbase.IndexDescriptor,
+) error {
+	if !idx.IsSharded() {
+		return nil
+	}
+	maxRanges := hashShardedRangePreSplitMax.Get(&p.ExecCfg().Settings.SV)
+	if maxRanges <= 1 {
+		return nil
+	}
+	buckets := int64(idx.Sharded.ShardBuckets)
+	step := (buckets + maxRanges - 1) / maxRanges
+	prefix := sqlbase.MakeIndexKeyPrefix(desc, idx.ID)
+	for b := step; b < buckets; b += step {
+		key := encoding.EncodeVarintAscending(append([]byte(nil), prefix...), b)
+		if err := p.ExecCfg().DB.AdminSplit(ctx, key, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addShardedIndexToNewTable sets up a hash sharded index declared in a CREATE
+// TABLE statement, and adds its shard column to the table if needed.
+func addShardedIndexToNewTable(
+	desc *sqlbase.MutableTableDescriptor,
+	idx *sqlbase.IndexDescriptor,
+	sharded *tree.ShardedIndexDef,
+	partitionBy *tree.PartitionBy,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+) error {
+	if partitionBy != nil {
+		return pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"partitioned indexes cannot also be hash sharded")
+	}
+	shardCol, err := setupShardedIndex(desc, idx, sharded, semaCtx, evalCtx)
+	if err != nil {
+		return err
+	}
+	if shardCol != nil {
+		desc.AddColumn(*shardCol)
+	}
+	return nil
+}
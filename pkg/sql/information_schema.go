@@ -788,6 +788,14 @@ CREATE TABLE information_schema.schema_privileges (
 		return forEachDatabaseDesc(ctx, p, dbContext, func(db *sqlbase.DatabaseDescriptor) error {
 			return forEachSchemaName(ctx, p, db, func(scName string) error {
 				privs := db.Privileges.Show()
+				// User-defined schemas have privileges of their own.
+				scDesc, err := getSchemaDesc(ctx, p.txn, db, scName)
+				if err != nil {
+					return err
+				}
+				if scDesc != nil {
+					privs = scDesc.Privileges.Show()
+				}
 				dbNameStr := tree.NewDString(db.Name)
 				scNameStr := tree.NewDString(scName)
 				for _, u := range privs {
@@ -1001,15 +1009,15 @@ CREATE TABLE information_schema.table_constraints (
 
 				for conName, c := range conInfo {
 					if err := addRow(
-						dbNameStr,                       // constraint_catalog
-						scNameStr,                       // constraint_schema
-						tree.NewDString(conName),        // constraint_name
-						dbNameStr,                       // table_catalog
-						scNameStr,                       // table_schema
-						tbNameStr,                       // table_name
-						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						dbNameStr,                         // constraint_catalog
+						scNameStr,                         // constraint_schema
+						tree.NewDString(conName),          // constraint_name
+						dbNameStr,                         // table_catalog
+						scNameStr,                         // table_schema
+						tbNameStr,                         // table_name
+						tree.NewDString(string(c.Kind)),   // constraint_type
+						yesOrNoDatum(c.Deferrable),        // is_deferrable
+						yesOrNoDatum(c.InitiallyDeferred), // initially_deferred
 					); err != nil {
 						return err
 					}
@@ -1189,6 +1197,10 @@ func forEachSchemaName(
 	for _, schema := range p.getVirtualTabler().getEntries() {
 		scNames = append(scNames, schema.desc.Name)
 	}
+	// Handle user-defined schemas.
+	for _, schema := range db.Schemas {
+		scNames = append(scNames, schema.Name)
+	}
 	sort.Strings(scNames)
 	for _, sc := range scNames {
 		if err := fn(sc); err != nil {
@@ -1363,13 +1375,43 @@ func forEachTableDescWithTableLookupInternal(
 	}
 
 	// Physical descriptors next.
+	//
+	// Temporary tables are only visible to the session that created them, in
+	// its temporary schema. The ID of that schema is looked up at most once
+	// per database.
+	tempSchemaName := p.SessionData().SearchPath.GetTemporarySchemaName()
+	tempSchemaIDs := make(map[sqlbase.ID]sqlbase.ID)
 	for _, tbID := range lCtx.tbIDs {
 		table := lCtx.tbDescs[tbID]
 		dbDesc, parentExists := lCtx.dbDescs[table.GetParentID()]
 		if table.Dropped() || !userCanSeeTable(ctx, p, table, allowAdding) || !parentExists {
 			continue
 		}
-		if err := fn(dbDesc, tree.PublicSchema, table, lCtx); err != nil {
+		scName := tree.PublicSchema
+		if table.IsTemporary() {
+			if tempSchemaName == "" {
+				continue
+			}
+			scID, ok := tempSchemaIDs[dbDesc.ID]
+			if !ok {
+				if scID, err = getTemporarySchemaID(ctx, p.txn, dbDesc.ID, tempSchemaName); err != nil {
+					return err
+				}
+				tempSchemaIDs[dbDesc.ID] = scID
+			}
+			if scID != table.TemporarySchemaID {
+				continue
+			}
+			scName = tempSchemaName
+		} else if table.SchemaID != 0 {
+			name, ok := dbDesc.FindSchemaName(table.SchemaID)
+			if !ok {
+				// The schema is being dropped.
+				continue
+			}
+			scName = name
+		}
+		if err := fn(dbDesc, scName, table, lCtx); err != nil {
 			return err
 		}
 	}
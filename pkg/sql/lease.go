@@ -509,6 +509,9 @@ func (s LeaseStore) getForExpiration(
 		if !tableDesc.ModificationTime.Less(prevTimestamp) {
 			return pgerror.NewAssertionErrorf("unable to read table= (%d, %s)", id, expiration)
 		}
+		if err := tableDesc.HydrateEnumTypes(ctx, txn); err != nil {
+			return err
+		}
 		// Create a tableVersionState with the table and without a lease.
 		table = &tableVersionState{
 			ImmutableTableDescriptor: *sqlbase.NewImmutableTableDescriptor(*tableDesc),
@@ -1229,7 +1232,7 @@ func (c *tableNameCache) insert(table *tableVersionState) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	key := makeTableNameCacheKey(table.ParentID, table.Name)
+	key := makeTableNameCacheKey(table.GetNamespaceParentID(), table.Name)
 	existing, ok := c.tables[key]
 	if !ok {
 		c.tables[key] = table
@@ -1249,7 +1252,7 @@ func (c *tableNameCache) remove(table *tableVersionState) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	key := makeTableNameCacheKey(table.ParentID, table.Name)
+	key := makeTableNameCacheKey(table.GetNamespaceParentID(), table.Name)
 	existing, ok := c.tables[key]
 	if !ok {
 		// Table for lease not found in table name cache. This can happen if we had
@@ -1344,7 +1347,7 @@ func (m *LeaseManager) SetExecCfg(execCfg *ExecutorConfig) {
 func nameMatchesTable(
 	table *sqlbase.ImmutableTableDescriptor, dbID sqlbase.ID, tableName string,
 ) bool {
-	return table.ParentID == dbID && table.Name == tableName
+	return table.GetNamespaceParentID() == dbID && table.Name == tableName
 }
 
 // findNewest returns the newest table version state for the tableID.
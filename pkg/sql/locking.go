@@ -0,0 +1,94 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// checkLockingClause verifies that the locking clause (FOR UPDATE, FOR SHARE,
+// etc.) of a SELECT statement can be applied to the given select statement.
+// The rows read from the tables of the FROM clause are locked by the scans
+// (see scanNode.setLocking).
+func (p *planner) checkLockingClause(
+	locking tree.LockingClause, stmt tree.SelectStatement,
+) error {
+	if p.EvalContext().TxnReadOnly {
+		strength := tree.ForNone
+		for _, item := range locking {
+			strength = strength.Max(item.Strength)
+		}
+		return readOnlyError("SELECT " + strength.String())
+	}
+	return locking.Validate(stmt)
+}
+
+// applyLocking configures the scan of the given data source to lock the rows
+// it reads, if the current locking clause applies to it.
+func (p *planner) applyLocking(src planDataSource, t *tree.AliasedTableExpr) error {
+	strength, waitPolicy := p.curPlan.locking.ForSource(t)
+	if strength == tree.ForNone {
+		return nil
+	}
+	scan, ok := src.plan.(*scanNode)
+	if !ok {
+		return pgerror.UnimplementedWithIssueErrorf(6583,
+			"%s is only supported on tables", strength)
+	}
+	return scan.setLocking(strength, waitPolicy)
+}
+
+// setLocking configures the scan to lock the rows it reads with the given
+// strength and wait policy.
+//
+// All the locking strengths acquire the same exclusive lock: the scan lays
+// down a lock-only intent on each key it reads (see engine.MVCCLock), which
+// conflicts with the writes and the locking reads of other transactions but
+// doesn't block their non-locking reads. Only the keys of the index being
+// scanned are locked.
+func (n *scanNode) setLocking(
+	strength tree.LockingStrength, waitPolicy tree.LockingWaitPolicy,
+) error {
+	if waitPolicy == tree.LockWaitSkip && len(n.desc.Families) > 1 {
+		// Skipping the locked keys of a row stored in several column families
+		// would return the row with only some of its columns.
+		return pgerror.UnimplementedWithIssueErrorf(6583,
+			"%s with SKIP LOCKED is not supported on tables with multiple column families",
+			strength)
+	}
+	n.lockingStrength = strength
+	n.lockingWaitPolicy = waitPolicy
+	return nil
+}
+
+// isLocking returns true if the scan locks the rows it reads.
+func (n *scanNode) isLocking() bool {
+	return n.lockingStrength != tree.ForNone
+}
+
+// kvWaitPolicy returns the KV wait policy that implements the given locking
+// wait policy.
+func kvWaitPolicy(waitPolicy tree.LockingWaitPolicy) roachpb.WaitPolicy {
+	switch waitPolicy {
+	case tree.LockWaitSkip:
+		return roachpb.WaitPolicy_SKIP
+	case tree.LockWaitError:
+		return roachpb.WaitPolicy_ERROR
+	default:
+		return roachpb.WaitPolicy_BLOCK
+	}
+}
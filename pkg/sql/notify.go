@@ -0,0 +1,403 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/pkg/errors"
+)
+
+// LISTEN and NOTIFY are implemented on top of the system.notifications table.
+// NOTIFY (and pg_notify()) insert a row in the table as part of the current
+// transaction, so that notifications are only sent if the transaction
+// commits. Every node runs a rangefeed over the table while some of its
+// sessions are listening and hands the notifications to these sessions; the
+// pgwire connection then writes them to the client when it is not running a
+// transaction.
+//
+// Unlike PostgreSQL, LISTEN and UNLISTEN take effect immediately instead of
+// when the transaction commits, and a notification can be delivered more than
+// once if the rangefeed has to be restarted.
+
+const (
+	// maxNotificationChannelLength is the maximum length of a channel name,
+	// as in PostgreSQL (NAMEDATALEN - 1).
+	maxNotificationChannelLength = 63
+	// maxNotificationPayloadLength is the maximum length of a payload, as in
+	// PostgreSQL.
+	maxNotificationPayloadLength = 8000
+)
+
+// rangefeedEnabledSettingName is the name of the cluster setting, registered
+// by the storage package, which enables rangefeeds.
+const rangefeedEnabledSettingName = "kv.rangefeed.enabled"
+
+// Notification is an asynchronous notification sent with NOTIFY or
+// pg_notify().
+type Notification struct {
+	Channel string
+	Payload string
+	// NodeID is the ID of the node the notifying session was connected to. It
+	// is reported to clients in place of the PostgreSQL process ID.
+	NodeID roachpb.NodeID
+}
+
+// NotificationSender is implemented by the ClientComms which can deliver
+// asynchronous notifications to their client. Sessions whose ClientComm does
+// not implement it cannot use LISTEN.
+type NotificationSender interface {
+	// SendNotification queues a notification for delivery to the client. It
+	// is called from a goroutine other than the connExecutor's and must not
+	// block.
+	SendNotification(Notification)
+}
+
+// NotificationRegistry hands the notifications recorded in
+// system.notifications to the sessions of this node that listen on their
+// channel. It maintains a rangefeed over the table as long as any session is
+// listening.
+type NotificationRegistry struct {
+	log.AmbientContext
+	settings *cluster.Settings
+	ds       *kv.DistSender
+	clock    *hlc.Clock
+	stopper  *stop.Stopper
+
+	mu struct {
+		syncutil.Mutex
+		// listeners maps each channel to the sessions listening on it.
+		listeners map[string]map[*notificationListener]struct{}
+		// cancel stops the running rangefeed. It is nil when no session listens.
+		cancel context.CancelFunc
+	}
+}
+
+// NewNotificationRegistry creates a NotificationRegistry.
+func NewNotificationRegistry(
+	ambient log.AmbientContext,
+	st *cluster.Settings,
+	ds *kv.DistSender,
+	clock *hlc.Clock,
+	stopper *stop.Stopper,
+) *NotificationRegistry {
+	r := &NotificationRegistry{
+		AmbientContext: ambient,
+		settings:       st,
+		ds:             ds,
+		clock:          clock,
+		stopper:        stopper,
+	}
+	r.AddLogTag("notifications", nil)
+	r.mu.listeners = make(map[string]map[*notificationListener]struct{})
+	return r
+}
+
+// newListener creates the listener of a session whose notifications are
+// delivered through sender.
+func (r *NotificationRegistry) newListener(sender NotificationSender) *notificationListener {
+	return &notificationListener{
+		registry: r,
+		sender:   sender,
+		channels: make(map[string]struct{}),
+	}
+}
+
+// register adds l to the listeners of channel, starting the rangefeed if l is
+// the first listener.
+func (r *NotificationRegistry) register(l *notificationListener, channel string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	listeners, ok := r.mu.listeners[channel]
+	if !ok {
+		listeners = make(map[*notificationListener]struct{})
+		r.mu.listeners[channel] = listeners
+	}
+	listeners[l] = struct{}{}
+
+	if r.mu.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(r.AnnotateCtx(context.Background()))
+	startTS := r.clock.Now()
+	if err := r.stopper.RunAsyncTask(ctx, "notifications-rangefeed", func(ctx context.Context) {
+		r.runRangefeed(ctx, startTS)
+	}); err != nil {
+		// The server is shutting down.
+		cancel()
+		return
+	}
+	r.mu.cancel = cancel
+}
+
+// unregister removes l from the listeners of channel, stopping the rangefeed
+// if l was the last listener.
+func (r *NotificationRegistry) unregister(l *notificationListener, channel string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	listeners := r.mu.listeners[channel]
+	delete(listeners, l)
+	if len(listeners) == 0 {
+		delete(r.mu.listeners, channel)
+	}
+	if len(r.mu.listeners) == 0 && r.mu.cancel != nil {
+		r.mu.cancel()
+		r.mu.cancel = nil
+	}
+}
+
+// dispatch hands n to the sessions listening on its channel. ctx is the
+// context of the rangefeed which received n; notifications received by a
+// rangefeed which has since been stopped are dropped.
+func (r *NotificationRegistry) dispatch(ctx context.Context, n Notification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// The rangefeed is canceled while holding r.mu.
+	if ctx.Err() != nil {
+		return
+	}
+	for l := range r.mu.listeners[n.Channel] {
+		l.sender.SendNotification(n)
+	}
+}
+
+// runRangefeed watches system.notifications from startTS on and dispatches
+// the notifications it receives until ctx is canceled. The rangefeed is
+// restarted from the last timestamp resolved over the whole table if it
+// fails.
+func (r *NotificationRegistry) runRangefeed(ctx context.Context, startTS hlc.Timestamp) {
+	var dec notificationDecoder
+	if err := dec.init(); err != nil {
+		log.Errorf(ctx, "unable to decode notifications: %v", err)
+		return
+	}
+	span := sqlbase.NotificationsTable.PrimaryIndexSpan()
+	resolved := startTS
+
+	for re := retry.StartWithCtx(ctx, base.DefaultRetryOptions()); re.Next(); {
+		eventC := make(chan *roachpb.RangeFeedEvent, 128)
+		req := &roachpb.RangeFeedRequest{
+			Header: roachpb.Header{Timestamp: resolved},
+			Span:   span,
+		}
+		g := ctxgroup.WithContext(ctx)
+		g.GoCtx(func(ctx context.Context) error {
+			return r.ds.RangeFeed(ctx, req, eventC).GoError()
+		})
+		g.GoCtx(func(feedCtx context.Context) error {
+			for {
+				select {
+				case e := <-eventC:
+					switch t := e.GetValue().(type) {
+					case *roachpb.RangeFeedValue:
+						if !t.Value.IsPresent() {
+							// The notification was garbage collected.
+							continue
+						}
+						n, err := dec.decode(feedCtx, roachpb.KeyValue{Key: t.Key, Value: t.Value})
+						if err != nil {
+							return err
+						}
+						r.dispatch(ctx, n)
+					case *roachpb.RangeFeedCheckpoint:
+						if t.Span.Contains(span) && resolved.Less(t.ResolvedTS) {
+							resolved = t.ResolvedTS
+						}
+					}
+				case <-feedCtx.Done():
+					return feedCtx.Err()
+				}
+			}
+		})
+		err := g.Wait()
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warningf(ctx, "notifications rangefeed failed, restarting from %s: %v", resolved, err)
+	}
+}
+
+// notificationDecoder decodes the rows of system.notifications received by a
+// rangefeed.
+type notificationDecoder struct {
+	alloc sqlbase.DatumAlloc
+	rf    row.Fetcher
+	kvs   row.SpanKVFetcher
+}
+
+func (d *notificationDecoder) init() error {
+	desc := sqlbase.NewImmutableTableDescriptor(sqlbase.NotificationsTable)
+	colIdxMap := make(map[sqlbase.ColumnID]int, len(desc.Columns))
+	var valNeededForCol util.FastIntSet
+	for i, col := range desc.Columns {
+		colIdxMap[col.ID] = i
+		valNeededForCol.Add(i)
+	}
+	return d.rf.Init(
+		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &d.alloc,
+		row.FetcherTableArgs{
+			Spans:            desc.AllIndexSpans(),
+			Desc:             desc,
+			Index:            &desc.PrimaryIndex,
+			ColIdxMap:        colIdxMap,
+			IsSecondaryIndex: false,
+			Cols:             desc.Columns,
+			ValNeededForCol:  valNeededForCol,
+		},
+	)
+}
+
+func (d *notificationDecoder) decode(
+	ctx context.Context, kv roachpb.KeyValue,
+) (Notification, error) {
+	d.kvs.KVs = append(d.kvs.KVs[:0], kv)
+	if err := d.rf.StartScanFrom(ctx, &d.kvs); err != nil {
+		return Notification{}, err
+	}
+	datums, _, _, err := d.rf.NextRowDecoded(ctx)
+	if err != nil {
+		return Notification{}, err
+	}
+	if datums == nil {
+		return Notification{}, errors.Errorf("no notification in key %s", kv.Key)
+	}
+	return Notification{
+		Channel: string(tree.MustBeDString(datums[2])),
+		Payload: string(tree.MustBeDString(datums[3])),
+		NodeID:  roachpb.NodeID(tree.MustBeDInt(datums[4])),
+	}, nil
+}
+
+// notificationListener tracks the channels a session listens on. It is only
+// used by the session's goroutine.
+type notificationListener struct {
+	registry *NotificationRegistry
+	sender   NotificationSender
+	channels map[string]struct{}
+}
+
+func (l *notificationListener) listen(channel string) {
+	if _, ok := l.channels[channel]; ok {
+		return
+	}
+	l.channels[channel] = struct{}{}
+	l.registry.register(l, channel)
+}
+
+func (l *notificationListener) unlisten(channel string) {
+	if _, ok := l.channels[channel]; !ok {
+		return
+	}
+	delete(l.channels, channel)
+	l.registry.unregister(l, channel)
+}
+
+func (l *notificationListener) unlistenAll() {
+	for channel := range l.channels {
+		l.unlisten(channel)
+	}
+}
+
+// rangefeedsEnabled returns whether rangefeeds, needed to receive
+// notifications, are enabled.
+func rangefeedsEnabled(sv *settings.Values) bool {
+	s, ok := settings.Lookup(rangefeedEnabledSettingName)
+	if !ok {
+		return false
+	}
+	b, ok := s.(*settings.BoolSetting)
+	return ok && b.Get(sv)
+}
+
+// Listen implements the LISTEN statement.
+// See https://www.postgresql.org/docs/10/static/sql-listen.html.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	l := p.extendedEvalCtx.NotificationListener
+	if l == nil {
+		return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"LISTEN is not supported by this connection")
+	}
+	if !rangefeedsEnabled(&p.ExecCfg().Settings.SV) {
+		return nil, pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"LISTEN requires rangefeeds to be enabled").SetHintf(
+			"SET CLUSTER SETTING %s = true", rangefeedEnabledSettingName)
+	}
+	l.listen(string(n.Channel))
+	return newZeroNode(nil /* columns */), nil
+}
+
+// Unlisten implements the UNLISTEN statement.
+// See https://www.postgresql.org/docs/10/static/sql-unlisten.html.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	if l := p.extendedEvalCtx.NotificationListener; l != nil {
+		if n.Channel == "" {
+			l.unlistenAll()
+		} else {
+			l.unlisten(string(n.Channel))
+		}
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// Notify implements the NOTIFY statement.
+// See https://www.postgresql.org/docs/10/static/sql-notify.html.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	if err := p.SendNotification(ctx, string(n.Channel), n.Payload); err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// SendNotification is part of the tree.Notifier interface.
+func (p *planner) SendNotification(ctx context.Context, channel, payload string) error {
+	if channel == "" {
+		return pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+			"channel name cannot be empty")
+	}
+	if len(channel) > maxNotificationChannelLength {
+		return pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+			"channel name too long")
+	}
+	if len(payload) >= maxNotificationPayloadLength {
+		return pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+			"payload string too long")
+	}
+
+	const insertNotificationStmt = `
+INSERT INTO system.notifications (timestamp, channel, payload, "reportingID")
+VALUES (now(), $1, $2, $3)
+`
+	_, err := p.ExecCfg().InternalExecutor.Exec(
+		ctx, "notify", p.txn, insertNotificationStmt,
+		channel, payload, int32(p.ExecCfg().NodeID.Get()),
+	)
+	return err
+}
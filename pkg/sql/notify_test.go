@@ -0,0 +1,117 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/lib/pq"
+)
+
+// TestListenNotify checks that notifications sent with NOTIFY and pg_notify()
+// are delivered asynchronously to the connections listening on their channel.
+func TestListenNotify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+
+	pgURL, cleanup := sqlutils.PGUrl(t, s.ServingAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanup()
+	l := pq.NewListener(pgURL.String(), time.Second, time.Minute, nil /* eventCallback */)
+	defer func() { _ = l.Close() }()
+	if err := l.Listen("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// waitFor receives notifications until all the expected payloads were seen
+	// on channel foo. Notifications can be delivered more than once and the
+	// order of notifications sent by different transactions is not guaranteed.
+	waitFor := func(expected ...string) {
+		t.Helper()
+		remaining := make(map[string]bool)
+		for _, payload := range expected {
+			remaining[payload] = true
+		}
+		for len(remaining) > 0 {
+			select {
+			case n := <-l.Notify:
+				if n == nil {
+					// The listener reconnected.
+					continue
+				}
+				if n.Channel != "foo" {
+					t.Fatalf("unexpected notification on channel %q: %q", n.Channel, n.Extra)
+				}
+				if n.Extra == "rolled back" {
+					t.Fatalf("received a notification sent by an aborted transaction")
+				}
+				if n.BePid != int(s.NodeID()) {
+					t.Fatalf("expected node ID %d, got %d", s.NodeID(), n.BePid)
+				}
+				delete(remaining, n.Extra)
+			case <-time.After(testutils.DefaultSucceedsSoonDuration):
+				t.Fatalf("timed out waiting for notifications %v", remaining)
+			}
+		}
+	}
+
+	sqlDB.Exec(t, `NOTIFY foo, 'hello'`)
+	sqlDB.Exec(t, `NOTIFY bar, 'other channel'`)
+	sqlDB.Exec(t, `SELECT pg_notify('foo', 'world')`)
+	waitFor("hello", "world")
+
+	// Notifications are only sent when the transaction commits.
+	sqlDB.Exec(t, `BEGIN; NOTIFY foo, 'rolled back'; ROLLBACK`)
+	sqlDB.Exec(t, `BEGIN; NOTIFY foo, 'committed'; COMMIT`)
+	waitFor("committed")
+
+	// Notifications are no longer received after UNLISTEN.
+	if err := l.Unlisten("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Listen("bar"); err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.Exec(t, `NOTIFY foo, 'not listening'`)
+	sqlDB.Exec(t, `NOTIFY bar, 'listening'`)
+	for {
+		select {
+		case n := <-l.Notify:
+			if n == nil {
+				continue
+			}
+			if n.Channel == "foo" && n.Extra == "not listening" {
+				t.Fatal("received a notification after UNLISTEN")
+			}
+			if n.Channel == "bar" && n.Extra == "listening" {
+				return
+			}
+		case <-time.After(testutils.DefaultSucceedsSoonDuration):
+			t.Fatal("timed out waiting for notification")
+		}
+	}
+}
@@ -88,6 +88,11 @@ type Table interface {
 	// information_schema tables.
 	IsVirtualTable() bool
 
+	// IsMaterializedView returns true if this table stores the contents of a
+	// materialized view. Such a table can be read like any other, but it can
+	// only be written by refreshing the view.
+	IsMaterializedView() bool
+
 	// InternalID returns the table's globally-unique ID.
 	InternalID() uint64
 
@@ -190,6 +195,13 @@ type Column interface {
 	// computed columns, but they can depend on all other columns, including
 	// columns with default values.
 	ComputedExprStr() string
+
+	// IsVirtual returns true if the column is a virtual computed column. Its
+	// values are not stored in the primary index, but they are computed by the
+	// row fetcher when the primary index is scanned, so the primary index still
+	// includes the column. Secondary indexes store virtual columns like any
+	// other column.
+	IsVirtual() bool
 }
 
 // MutationColumn describes a single column that is being added to a table or
@@ -237,7 +249,8 @@ type Index interface {
 	// Table returns a reference to the table this index is based on.
 	Table() Table
 
-	// IsInverted returns true if this is a JSON inverted index.
+	// IsInverted returns true if this is an inverted index on a JSON, ARRAY,
+	// TSVECTOR or STRING column.
 	IsInverted() bool
 
 	// ColumnCount returns the number of columns in the index. This includes
@@ -308,6 +321,17 @@ type Index interface {
 	// of an outbound foreign key relation. Returns false for the second
 	// return value if there is no foreign key reference on this index.
 	ForeignKey() (ForeignKeyReference, bool)
+
+	// Predicate returns the SQL text of the predicate of a partial index. Column
+	// references in the predicate are not qualified by a table name. Returns
+	// false for the second return value if the index is not partial, in which
+	// case it contains every row of the table.
+	Predicate() (string, bool)
+
+	// Sharding returns a description of how the rows of a hash sharded index
+	// are spread over its shards. Returns false for the second return value if
+	// the index is not hash sharded.
+	Sharding() (ShardedIndex, bool)
 }
 
 // TableStatistic is an interface to a table statistic. Each statistic is
@@ -354,6 +378,19 @@ type ForeignKeyReference struct {
 	PrefixLen int32
 }
 
+// ShardedIndex describes a hash sharded index. The first column of a hash
+// sharded index is its shard column, whose value is computed from the values
+// of the ColumnCount columns that follow it in the index: it is a hash of these
+// values modulo BucketCount (see sqlbase.HashShardBucket).
+type ShardedIndex struct {
+	// BucketCount is the number of shards of the index.
+	BucketCount int32
+
+	// ColumnCount is the number of index columns, after the shard column, that
+	// the shard is computed from.
+	ColumnCount int
+}
+
 // FormatCatalogTable nicely formats a catalog table using a treeprinter for
 // debugging and testing.
 func FormatCatalogTable(cat Catalog, tab Table, tp treeprinter.Node) {
@@ -363,6 +400,9 @@ func FormatCatalogTable(cat Catalog, tab Table, tp treeprinter.Node) {
 	for i := 0; i < tab.ColumnCount(); i++ {
 		buf.Reset()
 		formatColumn(tab.Column(i), &buf)
+		if tab.Column(i).IsVirtual() {
+			fmt.Fprintf(&buf, " (virtual)")
+		}
 		child.Child(buf.String())
 	}
 
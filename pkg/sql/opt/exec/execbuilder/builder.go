@@ -35,6 +35,18 @@ type Builder struct {
 	// expressions we built. Each entry is associated with a tree.Subquery
 	// expression node.
 	subqueries []exec.Subquery
+
+	// workTable is set when building the recursive expression of a RecursiveCTE
+	// for one iteration; it is the node that returns the working table, and the
+	// WorkTableScan operator with workTableCols is built as this node.
+	workTable     exec.Node
+	workTableCols opt.ColList
+
+	// outerBindings is set when building the right side of an apply join for
+	// one row of the left side; it maps the outer columns that refer to the
+	// left side (or to the left side of an enclosing apply join) to the values
+	// of that row.
+	outerBindings map[opt.ColumnID]tree.Datum
 }
 
 // New constructs an instance of the execution node builder using the
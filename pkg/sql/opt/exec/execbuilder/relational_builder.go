@@ -181,6 +181,12 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.ProjectSetExpr:
 		ep, err = b.buildProjectSet(t)
 
+	case *memo.RecursiveCTEExpr:
+		ep, err = b.buildRecursiveCTE(t)
+
+	case *memo.WorkTableScanExpr:
+		ep, err = b.buildWorkTableScan(t)
+
 	case *memo.InsertExpr:
 		ep, err = b.buildInsert(t)
 
@@ -197,7 +203,8 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 			break
 		}
 		if opt.IsJoinApplyOp(e) {
-			return execPlan{}, b.decorrelationError()
+			ep, err = b.buildApplyJoin(e)
+			break
 		}
 	}
 	if err != nil {
@@ -289,6 +296,8 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 		var err error
 		if idx.IsInverted() {
 			err = fmt.Errorf("index \"%s\" is inverted and cannot be used for this query", idx.IdxName())
+		} else if _, isPartial := idx.Predicate(); isPartial {
+			err = fmt.Errorf("index \"%s\" is partial and cannot be used for this query", idx.IdxName())
 		} else {
 			// This should never happen.
 			err = fmt.Errorf("index \"%s\" cannot be used for this query", idx.IdxName())
@@ -296,6 +305,10 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	if err := b.checkLocking(scan.Locking); err != nil {
+		return execPlan{}, err
+	}
+
 	needed, output := b.getColumns(scan.Cols, scan.Table)
 	res := execPlan{outputCols: output}
 
@@ -308,6 +321,7 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 		// HardLimit.Reverse() is taken into account by ScanIsReverse.
 		ordering.ScanIsReverse(scan, &scan.RequiredPhysical().Ordering),
 		res.reqOrdering(scan),
+		scan.Locking,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -316,6 +330,16 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 	return res, nil
 }
 
+// checkLocking raises an error if an operator locks the rows it reads as
+// part of a read-only transaction.
+func (b *Builder) checkLocking(locking opt.Locking) error {
+	if locking.IsLocking() && b.evalCtx.TxnReadOnly {
+		return pgerror.NewErrorf(pgerror.CodeReadOnlySQLTransactionError,
+			"cannot execute SELECT %s in a read-only transaction", locking.Strength)
+	}
+	return nil
+}
+
 func (b *Builder) buildVirtualScan(scan *memo.VirtualScanExpr) (execPlan, error) {
 	md := b.mem.Metadata()
 	tab := md.Table(scan.Table)
@@ -460,6 +484,93 @@ func (b *Builder) buildHashJoin(join memo.RelExpr) (execPlan, error) {
 	return ep, nil
 }
 
+// buildApplyJoin builds an apply join that could not be decorrelated. The
+// right side is built again for every row of the left side, with the outer
+// columns that refer to the left side bound to the values of the row.
+func (b *Builder) buildApplyJoin(join memo.RelExpr) (execPlan, error) {
+	switch join.Op() {
+	case opt.InnerJoinApplyOp, opt.LeftJoinApplyOp, opt.SemiJoinApplyOp, opt.AntiJoinApplyOp:
+	default:
+		return execPlan{}, b.decorrelationError()
+	}
+	joinType := joinOpToJoinType(join.Op())
+	leftExpr := join.Child(0).(memo.RelExpr)
+	rightExpr := join.Child(1).(memo.RelExpr)
+	filters := join.Child(2).(*memo.FiltersExpr)
+
+	if rightExpr.Relational().HasSubquery {
+		// The right side is built for every row, after the subqueries of the
+		// plan have already been run.
+		return execPlan{}, b.decorrelationError()
+	}
+
+	left, err := b.buildRelational(leftExpr)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// The plans of the right side output the columns in this order.
+	rightCols := opt.ColSetToList(rightExpr.Relational().OutputCols)
+	var rightOutputCols opt.ColMap
+	md := b.mem.Metadata()
+	rightColumns := make(sqlbase.ResultColumns, len(rightCols))
+	for i, col := range rightCols {
+		rightOutputCols.Set(int(col), i)
+		rightColumns[i].Name = md.ColumnLabel(col)
+		rightColumns[i].Typ = md.ColumnType(col)
+	}
+
+	allCols := joinOutputMap(left.outputCols, rightOutputCols)
+	ctx := buildScalarCtx{
+		ivh:     tree.MakeIndexedVarHelper(nil /* container */, allCols.Len()),
+		ivarMap: allCols,
+	}
+	var onExpr tree.TypedExpr
+	if len(*filters) != 0 {
+		onExpr, err = b.buildScalar(&ctx, filters)
+		if err != nil {
+			return execPlan{}, err
+		}
+	}
+
+	// The outer columns of the right side that are bound by the left side.
+	boundCols := rightExpr.Relational().OuterCols.Intersection(leftExpr.Relational().OutputCols)
+	planRightSideFn := func(leftRow tree.Datums) (exec.Node, error) {
+		// Use a separate builder, so that the outer columns are replaced by
+		// the values of the left row. Bindings of enclosing apply joins are
+		// inherited.
+		innerBld := New(b.factory, b.mem, rightExpr, b.evalCtx)
+		innerBld.outerBindings = make(map[opt.ColumnID]tree.Datum, len(b.outerBindings)+boundCols.Len())
+		for col, d := range b.outerBindings {
+			innerBld.outerBindings[col] = d
+		}
+		boundCols.ForEach(func(col int) {
+			innerBld.outerBindings[opt.ColumnID(col)] = leftRow[left.getColumnOrdinal(opt.ColumnID(col))]
+		})
+
+		plan, err := innerBld.buildRelational(rightExpr)
+		if err != nil {
+			return nil, err
+		}
+		plan, err = innerBld.ensureColumns(plan, rightCols, nil /* colNames */, nil /* provided */)
+		if err != nil {
+			return nil, err
+		}
+		return plan.root, nil
+	}
+
+	ep := execPlan{outputCols: allCols}
+	if joinType == sqlbase.LeftSemiJoin || joinType == sqlbase.LeftAntiJoin {
+		// For semi and anti join, only the left columns are output.
+		ep.outputCols = left.outputCols
+	}
+	ep.root, err = b.factory.ConstructApplyJoin(joinType, left.root, rightColumns, onExpr, planRightSideFn)
+	if err != nil {
+		return execPlan{}, err
+	}
+	return ep, nil
+}
+
 func (b *Builder) buildMergeJoin(join *memo.MergeJoinExpr) (execPlan, error) {
 	joinType := joinOpToJoinType(join.JoinType)
 
@@ -532,10 +643,10 @@ func joinOutputMap(left, right opt.ColMap) opt.ColMap {
 
 func joinOpToJoinType(op opt.Operator) sqlbase.JoinType {
 	switch op {
-	case opt.InnerJoinOp:
+	case opt.InnerJoinOp, opt.InnerJoinApplyOp:
 		return sqlbase.InnerJoin
 
-	case opt.LeftJoinOp:
+	case opt.LeftJoinOp, opt.LeftJoinApplyOp:
 		return sqlbase.LeftOuterJoin
 
 	case opt.RightJoinOp:
@@ -544,10 +655,10 @@ func joinOpToJoinType(op opt.Operator) sqlbase.JoinType {
 	case opt.FullJoinOp:
 		return sqlbase.FullOuterJoin
 
-	case opt.SemiJoinOp:
+	case opt.SemiJoinOp, opt.SemiJoinApplyOp:
 		return sqlbase.LeftSemiJoin
 
-	case opt.AntiJoinOp:
+	case opt.AntiJoinOp, opt.AntiJoinApplyOp:
 		return sqlbase.LeftAntiJoin
 
 	default:
@@ -846,7 +957,7 @@ func (b *Builder) buildIndexJoin(join *memo.IndexJoinExpr) (execPlan, error) {
 	}
 
 	res.root, err = b.factory.ConstructIndexJoin(
-		input.root, md.Table(join.Table), needed, reqOrdering,
+		input.root, md.Table(join.Table), needed, reqOrdering, join.Locking,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -862,6 +973,9 @@ func (b *Builder) buildIndexJoin(join *memo.IndexJoinExpr) (execPlan, error) {
 }
 
 func (b *Builder) buildLookupJoin(join *memo.LookupJoinExpr) (execPlan, error) {
+	if err := b.checkLocking(join.Locking); err != nil {
+		return execPlan{}, err
+	}
 	input, err := b.buildRelational(join.Input)
 	if err != nil {
 		return execPlan{}, err
@@ -901,6 +1015,7 @@ func (b *Builder) buildLookupJoin(join *memo.LookupJoinExpr) (execPlan, error) {
 		lookupOrdinals,
 		onExpr,
 		res.reqOrdering(join),
+		join.Locking,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -1003,6 +1118,70 @@ func (b *Builder) buildMax1Row(max1Row *memo.Max1RowExpr) (execPlan, error) {
 
 }
 
+func (b *Builder) buildRecursiveCTE(rec *memo.RecursiveCTEExpr) (execPlan, error) {
+	if rec.Recursive.Relational().HasSubquery {
+		// The recursive expression is built again for every iteration, after the
+		// subqueries of the plan have already been run.
+		return execPlan{}, pgerror.UnimplementedWithIssueErrorf(21085,
+			"subqueries in the recursive term of recursive query %q are not supported", rec.Name)
+	}
+
+	initial, err := b.buildRelational(rec.Initial)
+	if err != nil {
+		return execPlan{}, err
+	}
+	// Make sure we have the columns in the correct order.
+	initial, err = b.ensureColumns(initial, rec.InitialCols, nil /* colNames */, nil /* provided */)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	fn := func(workTable exec.Node) (exec.Node, error) {
+		// Use a separate builder, so that the working table of this iteration
+		// replaces the WorkTableScan.
+		innerBld := New(b.factory, b.mem, rec.Recursive, b.evalCtx)
+		innerBld.workTable = workTable
+		innerBld.workTableCols = rec.WorkTableCols
+
+		plan, err := innerBld.buildRelational(rec.Recursive)
+		if err != nil {
+			return nil, err
+		}
+		// Ensure columns are output in the same order as the working table.
+		plan, err = innerBld.ensureColumns(
+			plan, rec.RecursiveCols, nil /* colNames */, nil, /* provided */
+		)
+		if err != nil {
+			return nil, err
+		}
+		return plan.root, nil
+	}
+
+	var ep execPlan
+	ep.root, err = b.factory.ConstructRecursiveCTE(initial.root, fn, rec.Name, rec.Deduplicate)
+	if err != nil {
+		return execPlan{}, err
+	}
+	for i, col := range rec.OutCols {
+		ep.outputCols.Set(int(col), i)
+	}
+	return ep, nil
+}
+
+func (b *Builder) buildWorkTableScan(scan *memo.WorkTableScanExpr) (execPlan, error) {
+	if b.workTable == nil || !scan.Cols.Equals(b.workTableCols) {
+		return execPlan{}, errors.Errorf("working table of recursive query %q not available", scan.Name)
+	}
+	var ep execPlan
+	ep.root = b.workTable
+	for i, col := range scan.Cols {
+		ep.outputCols.Set(int(col), i)
+	}
+	// The working table can only be scanned once per iteration.
+	b.workTable = nil
+	return ep, nil
+}
+
 func (b *Builder) buildProjectSet(projectSet *memo.ProjectSetExpr) (execPlan, error) {
 	input, err := b.buildRelational(projectSet.Input)
 	if err != nil {
@@ -133,7 +133,11 @@ func (b *Builder) buildNull(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.Ty
 func (b *Builder) buildVariable(
 	ctx *buildScalarCtx, scalar opt.ScalarExpr,
 ) (tree.TypedExpr, error) {
-	return b.indexedVar(ctx, b.mem.Metadata(), *scalar.Private().(*opt.ColumnID)), nil
+	colID := *scalar.Private().(*opt.ColumnID)
+	if d, ok := b.outerBindings[colID]; ok {
+		return d, nil
+	}
+	return b.indexedVar(ctx, b.mem.Metadata(), colID), nil
 }
 
 func (b *Builder) indexedVar(
@@ -53,6 +53,7 @@ type Factory interface {
 	//     in the constraint.
 	//   - If hardLimit > 0, then only up to hardLimit rows can be returned from
 	//     the scan.
+	//   - If locking.IsLocking() is true, the scan locks the rows it reads.
 	ConstructScan(
 		table opt.Table,
 		index opt.Index,
@@ -61,6 +62,7 @@ type Factory interface {
 		hardLimit int64,
 		reverse bool,
 		reqOrdering OutputOrdering,
+		locking opt.Locking,
 	) (Node, error)
 
 	// ConstructVirtualScan returns a node that represents the scan of a virtual
@@ -104,6 +106,23 @@ type Factory interface {
 		extraOnCond tree.TypedExpr,
 	) (Node, error)
 
+	// ConstructApplyJoin returns a node that runs an apply join: for every row
+	// of the left input, the ApplyJoinPlanRightSideFn is used to create a plan
+	// for the right side, in which the outer columns that refer to the left
+	// side are replaced by the values of the row. Only inner, left outer, semi
+	// and anti joins are supported. rightColumns describes the columns of the
+	// plans of the right side.
+	//
+	// The onCond expression can refer to columns from both inputs using
+	// IndexedVars (first the left columns, then the right columns).
+	ConstructApplyJoin(
+		joinType sqlbase.JoinType,
+		left Node,
+		rightColumns sqlbase.ResultColumns,
+		onCond tree.TypedExpr,
+		planRightSideFn ApplyJoinPlanRightSideFn,
+	) (Node, error)
+
 	// ConstructMergeJoin returns a node that (under distsql) runs a merge join.
 	// The ON expression can refer to columns from both inputs using IndexedVars
 	// (first the left columns, then the right columns). In addition, the i-th
@@ -158,9 +177,14 @@ type Factory interface {
 
 	// ConstructIndexJoin returns a node that performs an index join.
 	// The input must be created by ConstructScan for the same table; cols is the
-	// set of columns produced by the index join.
+	// set of columns produced by the index join. The looked up rows are locked
+	// according to the given locking mode.
 	ConstructIndexJoin(
-		input Node, table opt.Table, cols ColumnOrdinalSet, reqOrdering OutputOrdering,
+		input Node,
+		table opt.Table,
+		cols ColumnOrdinalSet,
+		reqOrdering OutputOrdering,
+		locking opt.Locking,
 	) (Node, error)
 
 	// ConstructLookupJoin returns a node that preforms a lookup join.
@@ -169,7 +193,8 @@ type Factory interface {
 	// we are retrieving.
 	//
 	// The node produces the columns in the input and lookupCols (ordered by
-	// ordinal). The ON condition can refer to these using IndexedVars.
+	// ordinal). The ON condition can refer to these using IndexedVars. The
+	// looked up rows are locked according to the given locking mode.
 	ConstructLookupJoin(
 		joinType sqlbase.JoinType,
 		input Node,
@@ -179,6 +204,7 @@ type Factory interface {
 		lookupCols ColumnOrdinalSet,
 		onCond tree.TypedExpr,
 		reqOrdering OutputOrdering,
+		locking opt.Locking,
 	) (Node, error)
 
 	// ConstructZigzagJoin returns a node that performs a zigzag join.
@@ -218,6 +244,21 @@ type Factory interface {
 		n Node, exprs tree.TypedExprs, zipCols sqlbase.ResultColumns, numColsPerGen []int,
 	) (Node, error)
 
+	// ConstructRecursiveCTE returns a node that executes a recursive CTE:
+	//   - the initial plan is run first; the results are emitted and also saved
+	//     in a buffer.
+	//   - so long as the last buffer is not empty:
+	//     - the RecursiveCTEIterationFn is used to create a plan for the
+	//       recursive side; a node which returns the contents of the last buffer
+	//       is passed to this function.
+	//     - the plan is executed; the results are emitted and also saved in a new
+	//       buffer for the next iteration.
+	// If deduplicate is set, rows that were already emitted are discarded. The
+	// label is used for EXPLAIN and error messages.
+	ConstructRecursiveCTE(
+		initial Node, fn RecursiveCTEIterationFn, label string, deduplicate bool,
+	) (Node, error)
+
 	// RenameColumns modifies the column names of a node.
 	RenameColumns(input Node, colNames []string) (Node, error)
 
@@ -275,6 +316,15 @@ type Factory interface {
 // configuration parameters.
 type OutputOrdering sqlbase.ColumnOrdering
 
+// RecursiveCTEIterationFn creates a plan for an iteration of WITH RECURSIVE,
+// given a node that returns the result of the previous iteration (the "working
+// table").
+type RecursiveCTEIterationFn func(workTable Node) (Node, error)
+
+// ApplyJoinPlanRightSideFn creates a plan for the right side of an apply join,
+// given a row of the left side.
+type ApplyJoinPlanRightSideFn func(leftRow tree.Datums) (Node, error)
+
 // Subquery encapsulates information about a subquery that is part of a plan.
 type Subquery struct {
 	// ExprNode is a reference to a tree.Subquery node that has been created for
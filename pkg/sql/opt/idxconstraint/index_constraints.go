@@ -28,6 +28,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 )
 
 // Convenience aliases to avoid the constraint prefix everywhere.
@@ -761,6 +763,18 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 	case opt.ContainsOp:
 		lhs, rhs := nd.Child(0), nd.Child(1)
 
+		if c.isIndexColumn(rhs, 0 /* index */) && opt.IsConstValueOp(lhs) {
+			// This is `const @> col`, i.e. `col <@ const`.
+			leftDatum := memo.ExtractConstDatum(lhs)
+			if leftDatum == tree.DNull {
+				c.contradiction(0 /* offset */, out)
+				return true
+			}
+			if arr, ok := leftDatum.(*tree.DArray); ok {
+				return c.makeArrayContainedBySpans(arr, out)
+			}
+		}
+
 		if !c.isIndexColumn(lhs, 0 /* index */) || !opt.IsConstValueOp(rhs) {
 			c.unconstrained(0 /* offset */, out)
 			return false
@@ -773,6 +787,10 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 			return true
 		}
 
+		if arr, ok := rightDatum.(*tree.DArray); ok {
+			return c.makeArrayContainsSpans(arr, out)
+		}
+
 		rd := rightDatum.(*tree.DJSON).JSON
 
 		switch rd.Type() {
@@ -834,6 +852,71 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 			return true
 		}
 
+	case opt.OverlapsOp:
+		lhs, rhs := nd.Child(0), nd.Child(1)
+
+		// The && operator is commutative.
+		if c.isIndexColumn(rhs, 0 /* index */) {
+			lhs, rhs = rhs, lhs
+		}
+		if !c.isIndexColumn(lhs, 0 /* index */) || !opt.IsConstValueOp(rhs) {
+			c.unconstrained(0 /* offset */, out)
+			return false
+		}
+
+		rightDatum := memo.ExtractConstDatum(rhs)
+		if rightDatum == tree.DNull {
+			c.contradiction(0 /* offset */, out)
+			return true
+		}
+		if arr, ok := rightDatum.(*tree.DArray); ok {
+			return c.makeArrayOverlapsSpans(arr, out)
+		}
+
+	case opt.TSMatchesOp:
+		lhs, rhs := nd.Child(0), nd.Child(1)
+
+		// The @@ operator is commutative when it compares a tsvector and a
+		// tsquery.
+		if c.isIndexColumn(rhs, 0 /* index */) {
+			lhs, rhs = rhs, lhs
+		}
+		if !c.isIndexColumn(lhs, 0 /* index */) || !opt.IsConstValueOp(rhs) {
+			c.unconstrained(0 /* offset */, out)
+			return false
+		}
+
+		rightDatum := memo.ExtractConstDatum(rhs)
+		if rightDatum == tree.DNull {
+			c.contradiction(0 /* offset */, out)
+			return true
+		}
+		if q, ok := rightDatum.(*tree.DTSQuery); ok {
+			return c.makeTSMatchesSpans(q, out)
+		}
+
+	case opt.LikeOp, opt.ILikeOp, opt.RegMatchOp, opt.RegIMatchOp:
+		lhs, rhs := nd.Child(0), nd.Child(1)
+		if !c.isIndexColumn(lhs, 0 /* index */) || !opt.IsConstValueOp(rhs) {
+			c.unconstrained(0 /* offset */, out)
+			return false
+		}
+
+		rightDatum := memo.ExtractConstDatum(rhs)
+		if rightDatum == tree.DNull {
+			c.contradiction(0 /* offset */, out)
+			return true
+		}
+		if pattern, ok := rightDatum.(*tree.DString); ok {
+			var trigrams []string
+			if nd.Op() == opt.LikeOp || nd.Op() == opt.ILikeOp {
+				trigrams = trigram.LikeTrigrams(string(*pattern))
+			} else {
+				trigrams = trigram.RegexpTrigrams(string(*pattern))
+			}
+			return c.makeTrigramSpans(trigrams, out)
+		}
+
 	case opt.AndOp, opt.FiltersOp:
 		for i, n := 0, nd.ChildCount(); i < n; i++ {
 			tight := c.makeInvertedIndexSpansForExpr(nd.Child(i), out)
@@ -860,6 +943,171 @@ func (c *indexConstraintCtx) makeInvertedIndexSpansForExpr(
 	return false
 }
 
+// makeTSMatchesSpans generates the spans for `col @@ q`, where col is the
+// TSVECTOR column of the inverted index. A tsvector is indexed under each of
+// its lexemes; if every tsvector matched by q contains a given lexeme, we scan
+// the entries for that lexeme.
+func (c *indexConstraintCtx) makeTSMatchesSpans(
+	q *tree.DTSQuery, out *constraint.Constraint,
+) (tight bool) {
+	if q.Empty() {
+		// The empty query matches nothing.
+		c.contradiction(0 /* offset */, out)
+		return true
+	}
+	lexeme, exact, ok := q.RequiredLexeme()
+	if !ok {
+		// For example, a disjunction can match tsvectors that have no lexeme
+		// in common.
+		c.unconstrained(0 /* offset */, out)
+		return false
+	}
+	key := tree.NewDTSVector(tsearch.TSVector{{Lexeme: lexeme}})
+	c.eqSpan(0 /* offset */, key, out)
+	return exact
+}
+
+// makeTrigramSpans generates the spans for a LIKE, ILIKE or regular expression
+// match on the STRING column of the inverted index, given the trigrams that
+// every matched string contains. A string is indexed under each of its
+// trigrams, so a matched string has an entry for every one of them: out is set
+// to the spans of the trigram that sorts last, and the spans of the others are
+// added to the constraints whose primary keys are intersected with it (see
+// Instance.IntersectConstraints). The match itself must still be checked on the
+// scanned rows.
+func (c *indexConstraintCtx) makeTrigramSpans(
+	trigrams []string, out *constraint.Constraint,
+) (tight bool) {
+	if len(trigrams) == 0 {
+		c.unconstrained(0 /* offset */, out)
+		return false
+	}
+	for i := range trigrams {
+		key := tree.NewDArray(types.String)
+		key.Array = tree.Datums{tree.NewDString(trigrams[i])}
+		if i == len(trigrams)-1 {
+			c.eqSpan(0 /* offset */, key, out)
+			break
+		}
+		var other constraint.Constraint
+		c.eqSpan(0 /* offset */, key, &other)
+		c.intersectConstraints = append(c.intersectConstraints, other)
+	}
+	return false
+}
+
+// makeArrayContainsSpans generates the spans for `col @> arr`, where col is
+// the ARRAY column of the inverted index. An array is only indexed under each
+// of its distinct non-NULL elements, so an array that contains arr has an
+// entry for every element of arr; we scan the entries for the first one.
+func (c *indexConstraintCtx) makeArrayContainsSpans(
+	arr *tree.DArray, out *constraint.Constraint,
+) (tight bool) {
+	for _, d := range arr.Array {
+		if d == tree.DNull {
+			// A NULL element is never contained in an array.
+			c.contradiction(0 /* offset */, out)
+			return true
+		}
+	}
+	elems := distinctArrayElements(c.evalCtx, arr)
+	if len(elems) == 0 {
+		// Every array contains the empty array.
+		c.unconstrained(0 /* offset */, out)
+		return false
+	}
+	c.eqSpan(0 /* offset */, makeInvertedArrayKey(arr.ParamTyp, elems[:1]), out)
+	// The span is tight if arr has a single distinct element.
+	return len(elems) == 1
+}
+
+// makeArrayContainedBySpans generates the spans for `col <@ arr`, where col
+// is the ARRAY column of the inverted index. An array contained by arr is
+// either empty or only has elements of arr, so we scan the entries for the
+// empty array and for each distinct element of arr.
+func (c *indexConstraintCtx) makeArrayContainedBySpans(
+	arr *tree.DArray, out *constraint.Constraint,
+) (tight bool) {
+	elems := distinctArrayElements(c.evalCtx, arr)
+
+	// This is the span for empty arrays, which are contained by any array.
+	c.eqSpan(0 /* offset */, makeInvertedArrayKey(arr.ParamTyp, nil /* elems */), out)
+	if len(elems) == 0 {
+		return true
+	}
+
+	// These are the spans for arrays containing each element. They are not
+	// tight, since these arrays can have other elements too.
+	var other constraint.Constraint
+	c.makeArrayElementSpans(arr.ParamTyp, elems, &other)
+	out.UnionWith(c.evalCtx, &other)
+	return false
+}
+
+// makeArrayOverlapsSpans generates the spans for `col && arr`, where col is
+// the ARRAY column of the inverted index. We scan the entries for each
+// distinct element of arr.
+func (c *indexConstraintCtx) makeArrayOverlapsSpans(
+	arr *tree.DArray, out *constraint.Constraint,
+) (tight bool) {
+	elems := distinctArrayElements(c.evalCtx, arr)
+	if len(elems) == 0 {
+		// No array overlaps with an array without non-NULL elements.
+		c.contradiction(0 /* offset */, out)
+		return true
+	}
+	c.makeArrayElementSpans(arr.ParamTyp, elems, out)
+	return true
+}
+
+// makeArrayElementSpans generates the union of the spans for the arrays
+// containing each of the given elements. Since an array with several elements
+// has several index entries, the same primary key can be scanned more than
+// once if there is more than one element; duplicateKeys is set in that case.
+func (c *indexConstraintCtx) makeArrayElementSpans(
+	typ types.T, elems tree.Datums, out *constraint.Constraint,
+) {
+	c.eqSpan(0 /* offset */, makeInvertedArrayKey(typ, elems[:1]), out)
+	for i := 1; i < len(elems); i++ {
+		var other constraint.Constraint
+		c.eqSpan(0 /* offset */, makeInvertedArrayKey(typ, elems[i:i+1]), &other)
+		out.UnionWith(c.evalCtx, &other)
+	}
+	if len(elems) > 1 {
+		c.duplicateKeys = true
+	}
+}
+
+// distinctArrayElements returns the distinct non-NULL elements of arr.
+func distinctArrayElements(evalCtx *tree.EvalContext, arr *tree.DArray) tree.Datums {
+	var elems tree.Datums
+	for _, d := range arr.Array {
+		if d == tree.DNull {
+			continue
+		}
+		seen := false
+		for _, e := range elems {
+			if d.Compare(evalCtx, e) == 0 {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			elems = append(elems, d)
+		}
+	}
+	return elems
+}
+
+// makeInvertedArrayKey returns the array with the given elements, which must
+// be empty or have a single element so that it encodes to a single inverted
+// index key.
+func makeInvertedArrayKey(typ types.T, elems tree.Datums) *tree.DArray {
+	arr := tree.NewDArray(typ)
+	arr.Array = elems
+	return arr
+}
+
 // getMaxSimplifyPrefix finds the longest prefix (maxSimplifyPrefix) such that
 // every span has the same first maxSimplifyPrefix values for the start and end
 // key. For example, for:
@@ -1018,10 +1266,12 @@ type Instance struct {
 
 	filters memo.FiltersExpr
 
-	constraint   constraint.Constraint
-	consolidated constraint.Constraint
-	tight        bool
-	initialized  bool
+	constraint           constraint.Constraint
+	consolidated         constraint.Constraint
+	tight                bool
+	duplicateKeys        bool
+	intersectConstraints []constraint.Constraint
+	initialized          bool
 }
 
 // Init processes the filter and calculates the spans.
@@ -1037,6 +1287,11 @@ func (ic *Instance) Init(
 	ic.indexConstraintCtx.init(columns, notNullCols, isInverted, evalCtx, factory)
 	if isInverted {
 		ic.tight = ic.makeInvertedIndexSpansForExpr(&ic.filters, &ic.constraint)
+		// The spans generated while simplifying the filter later on don't
+		// affect the constraint, so we remember the flag and the intersected
+		// constraints now.
+		ic.duplicateKeys = ic.indexConstraintCtx.duplicateKeys
+		ic.intersectConstraints = ic.indexConstraintCtx.intersectConstraints
 	} else {
 		ic.tight = ic.makeSpansForExpr(0 /* offset */, &ic.filters, &ic.constraint)
 	}
@@ -1071,6 +1326,23 @@ func (ic *Instance) Constraint() *constraint.Constraint {
 	return &ic.consolidated
 }
 
+// DuplicateKeys returns true if scanning the spans of the constraint created
+// by Init can return the same primary key more than once. This can only
+// happen for an inverted index, where a row has an entry for each of its
+// keys; the duplicates must be removed before the rows are used.
+func (ic *Instance) DuplicateKeys() bool {
+	return ic.duplicateKeys
+}
+
+// IntersectConstraints returns additional constraints on the inverted index
+// created by Init. Every row matched by the filter has an entry in the spans of
+// each of them as well as in the spans of the constraint, so only the primary
+// keys found by scanning all of them need to be looked up. The result is empty
+// if there are no such constraints.
+func (ic *Instance) IntersectConstraints() []constraint.Constraint {
+	return ic.intersectConstraints
+}
+
 // RemainingFilters calculates a simplified FiltersExpr that needs to be applied
 // within the returned Spans.
 func (ic *Instance) RemainingFilters() memo.FiltersExpr {
@@ -1109,6 +1381,15 @@ type indexConstraintCtx struct {
 	// index (so AND is no longer just span intersection).
 	isInverted bool
 
+	// duplicateKeys is set if the spans generated for an inverted index can
+	// contain the same PK more than once (see Instance.DuplicateKeys).
+	duplicateKeys bool
+
+	// intersectConstraints holds the constraints on an inverted index whose
+	// PKs are intersected with the generated spans (see
+	// Instance.IntersectConstraints).
+	intersectConstraints []constraint.Constraint
+
 	evalCtx *tree.EvalContext
 
 	// We pre-initialize the KeyContext for each suffix of the index columns.
@@ -1128,6 +1409,8 @@ func (c *indexConstraintCtx) init(
 	c.columns = columns
 	c.notNullCols = notNullCols
 	c.isInverted = isInverted
+	c.duplicateKeys = false
+	c.intersectConstraints = nil
 	c.evalCtx = evalCtx
 	c.factory = factory
 
@@ -159,6 +159,14 @@ func TestIndexConstraints(t *testing.T) {
 				for i := 0; i < result.Spans.Count(); i++ {
 					fmt.Fprintf(&buf, "%s\n", result.Spans.Get(i))
 				}
+				if ic.DuplicateKeys() {
+					fmt.Fprintf(&buf, "Duplicate keys\n")
+				}
+				for _, c := range ic.IntersectConstraints() {
+					for i := 0; i < c.Spans.Count(); i++ {
+						fmt.Fprintf(&buf, "Intersect: %s\n", c.Spans.Get(i))
+					}
+				}
 				remainingFilter := ic.RemainingFilters()
 				if !remainingFilter.IsTrue() {
 					execBld := execbuilder.New(nil /* execFactory */, f.Memo(), &remainingFilter, &evalCtx)
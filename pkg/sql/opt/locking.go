@@ -0,0 +1,59 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package opt
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// Locking describes the row-level locks that an operator which reads from a
+// table (Scan, IndexJoin, LookupJoin) acquires on the rows it reads, as
+// requested by the locking clause of a SELECT statement (FOR UPDATE, FOR
+// SHARE, etc.). The zero value does not lock.
+type Locking struct {
+	// Strength is the strength of the requested locks. If it is ForNone, the
+	// rows are not locked.
+	Strength tree.LockingStrength
+
+	// WaitPolicy is the behavior of the operator when it reads a row that is
+	// locked by another transaction.
+	WaitPolicy tree.LockingWaitPolicy
+}
+
+// IsLocking returns true if the rows are locked.
+func (l Locking) IsLocking() bool {
+	return l.Strength != tree.ForNone
+}
+
+// String returns a short description of the locking mode, like
+// "for-update,skip-locked".
+func (l Locking) String() string {
+	if !l.IsLocking() {
+		return "none"
+	}
+	s := formatLockingName(l.Strength.String())
+	if l.WaitPolicy != tree.LockWaitBlock {
+		s += "," + formatLockingName(l.WaitPolicy.String())
+	}
+	return s
+}
+
+// formatLockingName turns the SQL syntax of a locking strength or wait
+// policy, like "FOR UPDATE", into its short form, like "for-update".
+func formatLockingName(name string) string {
+	return strings.Replace(strings.ToLower(name), " ", "-", -1)
+}
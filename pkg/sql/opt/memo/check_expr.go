@@ -94,6 +94,15 @@ func (m *Memo) checkExpr(e opt.Expr) {
 	case *SelectExpr:
 		checkFilters(t.Filters)
 
+	case *RecursiveCTEExpr:
+		if len(t.WorkTableCols) != len(t.InitialCols) ||
+			len(t.RecursiveCols) != len(t.InitialCols) ||
+			len(t.OutCols) != len(t.InitialCols) {
+			panic(fmt.Sprintf("lists in RecursiveCTEPrivate are not all the same length. "+
+				"work table:%d, initial:%d, recursive:%d, out:%d",
+				len(t.WorkTableCols), len(t.InitialCols), len(t.RecursiveCols), len(t.OutCols)))
+		}
+
 	case *AggregationsExpr:
 		var checkAggs func(scalar opt.ScalarExpr)
 		checkAggs = func(scalar opt.ScalarExpr) {
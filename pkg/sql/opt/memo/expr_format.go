@@ -165,7 +165,7 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		f.Buffer.WriteByte(')')
 
 	case *ScanExpr, *VirtualScanExpr, *IndexJoinExpr, *ShowTraceForSessionExpr,
-		*InsertExpr, *UpdateExpr:
+		*InsertExpr, *UpdateExpr, *RecursiveCTEExpr, *WorkTableScanExpr:
 		fmt.Fprintf(f.Buffer, "%v", e.Op())
 		FormatPrivate(f, e.Private(), required)
 
@@ -200,6 +200,12 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		*UnionAllExpr, *IntersectAllExpr, *ExceptAllExpr:
 		colList = e.Private().(*SetPrivate).OutCols
 
+	case *RecursiveCTEExpr:
+		colList = t.OutCols
+
+	case *WorkTableScanExpr:
+		colList = t.Cols
+
 	default:
 		// Fall back to writing output columns in column id order.
 		colList = opt.ColSetToList(e.Relational().OutputCols)
@@ -237,6 +243,13 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		f.formatColList(e, tp, "left columns:", private.LeftCols)
 		f.formatColList(e, tp, "right columns:", private.RightCols)
 
+	// Special-case handling for recursive CTEs to show the initial and
+	// recursive input columns that correspond to the output columns.
+	case *RecursiveCTEExpr:
+		f.formatColList(e, tp, "working table columns:", t.WorkTableCols)
+		f.formatColList(e, tp, "initial columns:", t.InitialCols)
+		f.formatColList(e, tp, "recursive columns:", t.RecursiveCols)
+
 	case *ScanExpr:
 		if t.Constraint != nil {
 			tp.Childf("constraint: %s", t.Constraint)
@@ -252,6 +265,17 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 				tp.Childf("flags: force-index=%s", idx.IdxName())
 			}
 		}
+		if t.Locking.IsLocking() {
+			tp.Childf("locking: %s", t.Locking)
+		}
+		if t.DuplicateKeys {
+			tp.Child("duplicate-keys")
+		}
+
+	case *IndexJoinExpr:
+		if t.Locking.IsLocking() {
+			tp.Childf("locking: %s", t.Locking)
+		}
 
 	case *LookupJoinExpr:
 		idxCols := make(opt.ColList, len(t.KeyCols))
@@ -260,6 +284,9 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			idxCols[i] = t.Table.ColumnID(idx.Column(i).Ordinal)
 		}
 		tp.Childf("key columns: %v = %v", t.KeyCols, idxCols)
+		if t.Locking.IsLocking() {
+			tp.Childf("locking: %s", t.Locking)
+		}
 
 	case *ZigzagJoinExpr:
 		tp.Childf("eq columns: %v = %v", t.LeftEqCols, t.RightEqCols)
@@ -696,6 +723,12 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 	case *FunctionPrivate:
 		fmt.Fprintf(f.Buffer, " %s", t.Name)
 
+	case *RecursiveCTEPrivate:
+		fmt.Fprintf(f.Buffer, " %s", t.Name)
+
+	case *WorkTableScanPrivate:
+		fmt.Fprintf(f.Buffer, " %s", t.Name)
+
 	case *physical.OrderingChoice:
 		if !t.Any() {
 			fmt.Fprintf(f.Buffer, " ordering=%s", t)
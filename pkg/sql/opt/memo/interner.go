@@ -363,6 +363,13 @@ func (h *hasher) HashDatum(val tree.Datum) {
 		h.HashUint64(uint64(*t))
 	case *tree.DJSON:
 		h.HashString(t.String())
+	case *tree.DTSVector:
+		h.HashString(t.TSVector.String())
+	case *tree.DTSQuery:
+		h.HashString(t.TSQuery.String())
+	case *tree.DEnum:
+		h.HashUint64(uint64(t.EnumTyp.ID))
+		h.HashBytes(t.PhysicalRep)
 	case *tree.DTuple:
 		for _, d := range t.D {
 			h.HashDatum(d)
@@ -456,6 +463,13 @@ func (h *hasher) HashScanFlags(val ScanFlags) {
 	h.hash *= prime64
 }
 
+func (h *hasher) HashLocking(val opt.Locking) {
+	h.hash ^= internHash(val.Strength)
+	h.hash *= prime64
+	h.hash ^= internHash(val.WaitPolicy)
+	h.hash *= prime64
+}
+
 func (h *hasher) HashSubquery(val *tree.Subquery) {
 	h.hash ^= internHash(uintptr(unsafe.Pointer(val)))
 	h.hash *= prime64
@@ -626,6 +640,18 @@ func (h *hasher) IsDatumEqual(l, r tree.Datum) bool {
 		if rt, ok := r.(*tree.DJSON); ok {
 			return h.IsStringEqual(lt.String(), rt.String())
 		}
+	case *tree.DTSVector:
+		if rt, ok := r.(*tree.DTSVector); ok {
+			return h.IsStringEqual(lt.TSVector.String(), rt.TSVector.String())
+		}
+	case *tree.DTSQuery:
+		if rt, ok := r.(*tree.DTSQuery); ok {
+			return h.IsStringEqual(lt.TSQuery.String(), rt.TSQuery.String())
+		}
+	case *tree.DEnum:
+		if rt, ok := r.(*tree.DEnum); ok {
+			return lt.EnumTyp.ID == rt.EnumTyp.ID && bytes.Equal(lt.PhysicalRep, rt.PhysicalRep)
+		}
 	case *tree.DTuple:
 		if rt, ok := r.(*tree.DTuple); ok {
 			if len(lt.D) != len(rt.D) {
@@ -699,6 +725,10 @@ func (h *hasher) IsScanFlagsEqual(l, r ScanFlags) bool {
 	return l == r
 }
 
+func (h *hasher) IsLockingEqual(l, r opt.Locking) bool {
+	return l == r
+}
+
 func (h *hasher) IsSubqueryEqual(l, r *tree.Subquery) bool {
 	return l == r
 }
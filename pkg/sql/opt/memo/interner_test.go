@@ -292,6 +292,13 @@ func TestInterner(t *testing.T) {
 			{val1: ScanFlags{NoIndexJoin: true, Index: 1}, val2: ScanFlags{NoIndexJoin: false, Index: 1}, equal: false},
 		}},
 
+		{hashFn: in.hasher.HashLocking, eqFn: in.hasher.IsLockingEqual, variations: []testVariation{
+			{val1: opt.Locking{}, val2: opt.Locking{}, equal: true},
+			{val1: opt.Locking{Strength: tree.ForUpdate}, val2: opt.Locking{Strength: tree.ForUpdate}, equal: true},
+			{val1: opt.Locking{Strength: tree.ForUpdate}, val2: opt.Locking{Strength: tree.ForShare}, equal: false},
+			{val1: opt.Locking{Strength: tree.ForUpdate}, val2: opt.Locking{Strength: tree.ForUpdate, WaitPolicy: tree.LockWaitSkip}, equal: false},
+		}},
+
 		{hashFn: in.hasher.HashSubquery, eqFn: in.hasher.IsSubqueryEqual, variations: []testVariation{
 			{val1: (*tree.Subquery)(nil), val2: (*tree.Subquery)(nil), equal: true},
 			{val1: &tree.Subquery{}, val2: &tree.Subquery{}, equal: false},
@@ -94,8 +94,11 @@ func (b *logicalPropsBuilder) buildScanProps(scan *ScanExpr, rel *props.Relation
 	} else {
 		// Initialize key FD's from the table schema, including constant columns from
 		// the constraint, minus any columns that are not projected by the Scan
-		// operator.
-		rel.FuncDeps.CopyFrom(makeTableFuncDep(md, scan.Table))
+		// operator. If the scan can return duplicate keys, the table's keys are
+		// not keys of the scan.
+		if !scan.DuplicateKeys {
+			rel.FuncDeps.CopyFrom(makeTableFuncDep(md, scan.Table))
+		}
 		if scan.Constraint != nil {
 			rel.FuncDeps.AddConstants(scan.Constraint.ExtractConstCols(b.evalCtx))
 		}
@@ -367,16 +370,11 @@ func (b *logicalPropsBuilder) buildJoinProps(join RelExpr, rel *props.Relational
 	// Outer Columns
 	// -------------
 	// Outer columns were initially set by buildSharedProps. Remove any that are
-	// bound by the input columns.
+	// bound by the input columns. This includes the outer columns of the right
+	// side of an apply join that are bound by the left side; the other outer
+	// columns of the right side remain outer columns of the join.
 	inputCols := h.leftProps.OutputCols.Union(h.rightProps.OutputCols)
 	rel.OuterCols.DifferenceWith(inputCols)
-	if opt.IsJoinApplyOp(join) {
-		// Outer columns of right side of apply join can be bound by output columns
-		// of left side of apply join. Since this is apply join, there is always a
-		// right input.
-		rightOuterCols := join.Child(1).(RelExpr).Relational().OuterCols
-		rel.OuterCols.DifferenceWith(rightOuterCols)
-	}
 
 	// Functional Dependencies
 	// -----------------------
@@ -949,6 +947,79 @@ func (b *logicalPropsBuilder) buildProjectSetProps(
 	}
 }
 
+func (b *logicalPropsBuilder) buildRecursiveCTEProps(
+	rec *RecursiveCTEExpr, rel *props.Relational,
+) {
+	BuildSharedProps(b.mem, rec, &rel.Shared)
+
+	// Output Columns
+	// --------------
+	// Output columns are stored in the definition.
+	rel.OutputCols = rec.OutCols.ToSet()
+
+	// Not Null Columns
+	// ----------------
+	// All columns are assumed to be nullable.
+
+	// Outer Columns
+	// -------------
+	// Outer columns were already derived by buildSharedProps.
+
+	// Functional Dependencies
+	// -----------------------
+	if rec.Deduplicate {
+		// Duplicate rows are eliminated, so a strict key exists.
+		rel.FuncDeps.AddStrictKey(rel.OutputCols, rel.OutputCols)
+	}
+
+	// Cardinality
+	// -----------
+	// The number of iterations is not known, so don't make any assumptions
+	// about cardinality of output.
+	rel.Cardinality = props.AnyCardinality
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildRecursiveCTE(rec, rel)
+	}
+}
+
+func (b *logicalPropsBuilder) buildWorkTableScanProps(
+	scan *WorkTableScanExpr, rel *props.Relational,
+) {
+	BuildSharedProps(b.mem, scan, &rel.Shared)
+
+	// Output Columns
+	// --------------
+	// Output columns are stored in the definition.
+	rel.OutputCols = scan.Cols.ToSet()
+
+	// Not Null Columns
+	// ----------------
+	// All columns are assumed to be nullable.
+
+	// Outer Columns
+	// -------------
+	// Outer columns were already derived by buildSharedProps.
+
+	// Functional Dependencies
+	// -----------------------
+	// WorkTableScan operator has an empty FD set.
+
+	// Cardinality
+	// -----------
+	// The working table of any iteration other than the last one is non-empty,
+	// but we don't make any assumptions about it.
+	rel.Cardinality = props.AnyCardinality
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildWorkTableScan(scan, rel)
+	}
+}
+
 func (b *logicalPropsBuilder) buildInsertProps(ins *InsertExpr, rel *props.Relational) {
 	BuildSharedProps(b.mem, ins, &rel.Shared)
 
@@ -1221,6 +1292,11 @@ func makeTableFuncDep(md *opt.Metadata, tabID opt.TableID) *props.FuncDepSet {
 			// Skip inverted indexes for now.
 			continue
 		}
+		if _, isPartial := index.Predicate(); isPartial {
+			// The key of a partial index is only unique among the rows that
+			// satisfy its predicate.
+			continue
+		}
 
 		// If index has a separate lax key, add a lax key FD. Otherwise, add a
 		// strict key. See the comment for opt.Index.LaxKeyColumnCount.
@@ -0,0 +1,34 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package memo
+
+import "github.com/cockroachdb/cockroach/pkg/sql/opt"
+
+// PartialIndexPredicatesAnnID is the annotation under which the predicates of
+// a table's partial indexes are stored in the metadata. The annotation is set
+// by the optbuilder when it builds a Scan of the table, since building the
+// predicates requires name resolution and type checking; its value is a
+// map[int]FiltersExpr (see PartialIndexPredicates).
+var PartialIndexPredicatesAnnID = opt.NewTableAnnID()
+
+// PartialIndexPredicates returns the predicates of the partial indexes of the
+// given table, keyed by index ordinal. Each predicate is normalized and split
+// into conjuncts, the same way as the filters of a Select, so that its
+// conjuncts can be matched against the filters of a query. Returns nil if the
+// table has no partial indexes.
+func PartialIndexPredicates(md *opt.Metadata, tabID opt.TableID) map[int]FiltersExpr {
+	preds, _ := md.TableAnnotation(tabID, PartialIndexPredicatesAnnID).(map[int]FiltersExpr)
+	return preds
+}
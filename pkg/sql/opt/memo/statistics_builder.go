@@ -334,7 +334,7 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 	case opt.InsertOp, opt.UpdateOp:
 		return sb.colStatMutation(colSet, e)
 
-	case opt.ExplainOp, opt.ShowTraceForSessionOp:
+	case opt.ExplainOp, opt.ShowTraceForSessionOp, opt.RecursiveCTEOp, opt.WorkTableScanOp:
 		relProps := e.Relational()
 		return sb.colStatLeaf(colSet, &relProps.Stats, &relProps.FuncDeps, relProps.NotNullCols)
 	}
@@ -495,6 +495,11 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 		s.ApplySelectivity(sb.selectivityFromNullCounts(cols, scan, s, inputRowCount))
 	}
 
+	if pred, ok := PartialIndexPredicates(sb.md, scan.Table)[scan.Index]; ok {
+		// A partial index only contains the rows that satisfy its predicate.
+		s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(float64(len(pred))))
+	}
+
 	sb.finalizeFromCardinality(relProps)
 }
 
@@ -1705,6 +1710,44 @@ func (sb *statisticsBuilder) colStatRowNumber(
 	return colStat
 }
 
+// +---------------+
+// | Recursive CTE |
+// +---------------+
+
+func (sb *statisticsBuilder) buildRecursiveCTE(
+	rec *RecursiveCTEExpr, relProps *props.Relational,
+) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+
+	// The number of iterations is not known; treat the result like a table
+	// without statistics.
+	s.RowCount = unknownRowCount
+	sb.finalizeFromCardinality(relProps)
+}
+
+// +-----------------+
+// | Work Table Scan |
+// +-----------------+
+
+func (sb *statisticsBuilder) buildWorkTableScan(
+	scan *WorkTableScanExpr, relProps *props.Relational,
+) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+
+	// The working table changes with every iteration; treat it like a table
+	// without statistics.
+	s.RowCount = unknownRowCount
+	sb.finalizeFromCardinality(relProps)
+}
+
 // +-------------+
 // | Project Set |
 // +-------------+
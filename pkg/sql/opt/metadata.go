@@ -80,6 +80,7 @@ func (t TableID) index() int {
 // Currently, the following annotations are in use:
 //   - WeakKeys: weak keys derived from the base table
 //   - Stats: statistics derived from the base table
+//   - PartialIndexPredicates: predicates of the table's partial indexes
 //
 // To add an additional annotation, increase the value of maxTableAnnIDCount and
 // add a call to NewTableAnnID.
@@ -92,7 +93,7 @@ var tableAnnIDCount TableAnnID
 // called. Calling more than this number of times results in a panic. Having
 // a maximum enables a static annotation array to be inlined into the metadata
 // table struct.
-const maxTableAnnIDCount = 2
+const maxTableAnnIDCount = 3
 
 // Metadata assigns unique ids to the columns, tables, and other metadata used
 // within the scope of a particular query. Because it is specific to one query,
@@ -141,6 +142,9 @@ type mdDependency struct {
 
 // mdTable stores information about one of the tables stored in the metadata.
 type mdTable struct {
+	// id is the identifier of the table in the metadata.
+	id TableID
+
 	// tab is a reference to the table in the catalog.
 	tab Table
 
@@ -352,7 +356,7 @@ func (md *Metadata) AddTable(tab Table) TableID {
 	if md.tables == nil {
 		md.tables = make([]mdTable, 0, 4)
 	}
-	md.tables = append(md.tables, mdTable{tab: tab})
+	md.tables = append(md.tables, mdTable{id: tabID, tab: tab})
 
 	colCount := tab.ColumnCount()
 	if md.cols == nil {
@@ -377,6 +381,16 @@ func (md *Metadata) Table(tabID TableID) Table {
 	return md.tables[tabID.index()].tab
 }
 
+// AllTables returns the metadata ids of all the tables that have been added to
+// the metadata, in the order in which they were added.
+func (md *Metadata) AllTables() []TableID {
+	tabIDs := make([]TableID, len(md.tables))
+	for i := range md.tables {
+		tabIDs[i] = md.tables[i].id
+	}
+	return tabIDs
+}
+
 // TableByDescID looks up the catalog table associated with the given descriptor id.
 func (md *Metadata) TableByDescID(tabID uint64) Table {
 	for _, mdTab := range md.tables {
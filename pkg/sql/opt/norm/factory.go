@@ -187,6 +187,26 @@ func (f *Factory) AssignPlaceholders(from *memo.Memo) (err error) {
 	// ids they had in the "from" memo.
 	f.mem.Metadata().AddMetadata(from.Metadata())
 
+	// The predicates of partial indexes are scalar expressions interned in the
+	// "from" memo, so they must be rebuilt in this memo in order for their
+	// conjuncts to be comparable with the copied filters.
+	md := f.mem.Metadata()
+	for _, tabID := range md.AllTables() {
+		preds := memo.PartialIndexPredicates(md, tabID)
+		if preds == nil {
+			continue
+		}
+		newPreds := make(map[int]memo.FiltersExpr, len(preds))
+		for ord, pred := range preds {
+			newPred := make(memo.FiltersExpr, len(pred))
+			for i := range pred {
+				newPred[i].Condition = f.assignPlaceholders(pred[i].Condition).(opt.ScalarExpr)
+			}
+			newPreds[ord] = newPred
+		}
+		md.SetTableAnnotation(tabID, memo.PartialIndexPredicatesAnnID, newPreds)
+	}
+
 	// Replace all placeholders with their assigned values.
 	dst := f.assignPlaceholders(src)
 	f.Memo().SetRoot(dst.(memo.RelExpr), from.RootProps())
@@ -96,6 +96,11 @@ type Builder struct {
 	// subquery contains a pointer to the subquery which is currently being built
 	// (if any).
 	subquery *subquery
+
+	// locking is the locking clause (FOR UPDATE, FOR SHARE, etc.) of the SELECT
+	// statement whose FROM clause is currently being built, if any. It does not
+	// apply to the FROM clauses of nested statements.
+	locking tree.LockingClause
 }
 
 // New creates a new Builder structure initialized with the given
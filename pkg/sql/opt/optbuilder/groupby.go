@@ -416,6 +416,9 @@ func (b *Builder) buildGrouping(
 func (b *Builder) buildAggregateFunction(
 	f *tree.FuncExpr, def *memo.FunctionPrivate, inScope *scope,
 ) *aggregateInfo {
+	if f.WithinGroup {
+		panic(unimplementedf("ordered-set aggregate %s is not supported", def.Name))
+	}
 	if len(f.Exprs) > 1 {
 		// TODO: #10495
 		panic(builderError{pgerror.UnimplementedWithIssueError(
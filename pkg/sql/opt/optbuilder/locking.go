@@ -0,0 +1,38 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// lockingForSource returns the row-level locking mode that the locking clause
+// of the current SELECT statement requests for the given data source of its
+// FROM clause.
+func (b *Builder) lockingForSource(source *tree.AliasedTableExpr) opt.Locking {
+	strength, waitPolicy := b.locking.ForSource(source)
+	return opt.Locking{Strength: strength, WaitPolicy: waitPolicy}
+}
+
+// checkNoLocking raises an error if the given locking mode locks rows: it is
+// called for the data sources other than tables, whose rows cannot be locked.
+func checkNoLocking(locking opt.Locking) {
+	if locking.IsLocking() {
+		panic(builderError{pgerror.UnimplementedWithIssueErrorf(6583,
+			"%s is only supported on tables", locking.Strength)})
+	}
+}
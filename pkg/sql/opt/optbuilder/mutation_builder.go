@@ -463,6 +463,7 @@ func (mb *mutationBuilder) buildInputForUpdate(inScope *scope, upd *tree.Update)
 		mb.tab.Name(),
 		nil, /* ordinals */
 		nil, /* indexFlags */
+		opt.Locking{},
 		includeMutations,
 		inScope,
 	)
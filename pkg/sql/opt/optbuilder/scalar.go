@@ -17,6 +17,7 @@ package optbuilder
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -415,7 +416,7 @@ func (b *Builder) buildFunction(
 		panic(unimplementedf("window functions are not supported"))
 	}
 
-	def, err := f.Func.Resolve(b.semaCtx.SearchPath)
+	def, err := b.semaCtx.ResolveFunction(&f.Func)
 	if err != nil {
 		panic(builderError{err})
 	}
@@ -445,6 +446,53 @@ func (b *Builder) buildFunction(
 	return b.finishBuildScalar(f, out, inScope, outScope, outCol)
 }
 
+// inlineFunction returns the body of the user-defined function called by f,
+// in which the placeholders that stand for the arguments of the function are
+// replaced by the arguments of the call. It returns nil if the function
+// cannot be inlined, in which case the call is built like any other.
+func inlineFunction(f *tree.FuncExpr, def *tree.FunctionDefinition) tree.Expr {
+	if len(def.Definition) != 1 || f.Type != 0 || f.Filter != nil || f.WindowDef != nil {
+		return nil
+	}
+	overload, ok := def.Definition[0].(*tree.Overload)
+	if !ok || overload.InlineBody == nil || overload.Types.Length() != len(f.Exprs) {
+		return nil
+	}
+	// Arguments that are used more than once in the body are evaluated once
+	// per use once inlined, so only simple arguments can be repeated.
+	uses := make([]int, len(f.Exprs))
+	body, err := tree.SimpleVisit(overload.InlineBody, func(expr tree.Expr) (error, bool, tree.Expr) {
+		if p, ok := expr.(*tree.Placeholder); ok {
+			i, err := strconv.Atoi(p.Name)
+			if err != nil || i < 1 || i > len(f.Exprs) {
+				return pgerror.NewAssertionErrorf("invalid placeholder in body of %s", def.Name), false, expr
+			}
+			uses[i-1]++
+			return nil, false, &tree.ParenExpr{Expr: f.Exprs[i-1]}
+		}
+		return nil, true, expr
+	})
+	if err != nil {
+		panic(builderError{err})
+	}
+	for i, n := range uses {
+		if n > 1 && !isSimpleInlineArg(f.Exprs[i]) {
+			return nil
+		}
+	}
+	return body
+}
+
+// isSimpleInlineArg returns whether the given argument of a call can be
+// duplicated when the called function is inlined.
+func isSimpleInlineArg(expr tree.Expr) bool {
+	switch expr.(type) {
+	case tree.Datum, tree.Constant, *tree.UnresolvedName, *tree.Placeholder, *tree.IndexedVar:
+		return true
+	}
+	return false
+}
+
 // buildRangeCond builds a RANGE clause as a simpler expression. Examples:
 // x BETWEEN a AND b                ->  x >= a AND x <= b
 // x NOT BETWEEN a AND b            ->  NOT (x >= a AND x <= b)
@@ -585,6 +633,10 @@ func (b *Builder) constructComparison(
 	case tree.ContainedBy:
 		// This is just syntatic sugar that reverses the operands.
 		return b.factory.ConstructContains(right, left)
+	case tree.Overlaps:
+		return b.factory.ConstructOverlaps(left, right)
+	case tree.TSMatches:
+		return b.factory.ConstructTSMatches(left, right)
 	case tree.JSONExists:
 		return b.factory.ConstructJsonExists(left, right)
 	case tree.JSONAllExists:
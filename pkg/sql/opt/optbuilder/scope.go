@@ -74,6 +74,11 @@ type scope struct {
 	// scopes.
 	ctes map[string]*cteSource
 
+	// lateral is set if this scope contains the columns of the data sources
+	// that precede a lateral data source in a FROM clause (see
+	// Builder.makeLateralScope). It is not a query level of its own.
+	lateral bool
+
 	// context is the current context in the SQL query (e.g., "SELECT" or
 	// "HAVING"). It is used for error messages.
 	context string
@@ -89,6 +94,10 @@ type cteSource struct {
 	// to only having a single reference to a given CTE, so if this is set then
 	// this CTE has already been referenced and may not be referenced again.
 	used bool
+
+	// recursive is set if this is the self-reference of a recursive CTE from
+	// within its own recursive query; expr is then a WorkTableScan.
+	recursive bool
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -503,6 +512,12 @@ func (s *scope) endAggFunc(cols opt.ColSet) (aggInScope, aggOutScope *scope) {
 
 	for curr := s; curr != nil; curr = curr.parent {
 		if cols.Len() == 0 || cols.Intersects(curr.colSet()) {
+			if curr.lateral {
+				// The aggregate would belong to the query whose FROM clause
+				// contains the lateral data source.
+				panic(builderError{pgerror.NewErrorf(pgerror.CodeGroupingError,
+					"aggregate functions are not allowed in FROM clause of their own query level")})
+			}
 			if curr.groupby.aggInScope == nil {
 				curr.groupby.aggInScope = curr.replace()
 			}
@@ -788,11 +803,15 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 			panic(unimplementedf("window functions are not supported"))
 		}
 
-		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
+		def, err := s.builder.semaCtx.ResolveFunction(&t.Func)
 		if err != nil {
 			panic(builderError{err})
 		}
 
+		if inlined := inlineFunction(t, def); inlined != nil {
+			return true, inlined
+		}
+
 		if isGenerator(def) && s.replaceSRFs {
 			expr = s.replaceSRF(t, def)
 			break
@@ -978,7 +997,7 @@ func (s *scope) replaceCount(
 			if _, err := e.TypeCheck(&tree.SemaContext{}, types.Any); err != nil {
 				panic(builderError{err})
 			}
-			newDef, err := e.Func.Resolve(s.builder.semaCtx.SearchPath)
+			newDef, err := s.builder.semaCtx.ResolveFunction(&e.Func)
 			if err != nil {
 				panic(builderError{err})
 			}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/pkg/errors"
 )
@@ -43,7 +44,7 @@ const (
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
 func (b *Builder) buildDataSource(
-	texpr tree.TableExpr, indexFlags *tree.IndexFlags, inScope *scope,
+	texpr tree.TableExpr, indexFlags *tree.IndexFlags, locking opt.Locking, inScope *scope,
 ) (outScope *scope) {
 	// NB: The case statements are sorted lexicographically.
 	switch source := texpr.(type) {
@@ -51,8 +52,11 @@ func (b *Builder) buildDataSource(
 		if source.IndexFlags != nil {
 			indexFlags = source.IndexFlags
 		}
+		if len(b.locking) > 0 {
+			locking = b.lockingForSource(source)
+		}
 
-		outScope = b.buildDataSource(source.Expr, indexFlags, inScope)
+		outScope = b.buildDataSource(source.Expr, indexFlags, locking, inScope)
 
 		if source.Ordinality {
 			outScope = b.buildWithOrdinality("ordinality", outScope)
@@ -64,6 +68,7 @@ func (b *Builder) buildDataSource(
 		return outScope
 
 	case *tree.JoinTableExpr:
+		checkNoLocking(locking)
 		return b.buildJoin(source, inScope)
 
 	case *tree.TableName:
@@ -71,7 +76,12 @@ func (b *Builder) buildDataSource(
 
 		// CTEs take precedence over other data sources.
 		if cte := inScope.resolveCTE(tn); cte != nil {
+			checkNoLocking(locking)
 			if cte.used {
+				if cte.recursive {
+					panic(builderError{pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+						"recursive reference to query %q must not appear more than once", tn)})
+				}
 				panic(builderError{fmt.Errorf("unsupported multiple use of CTE clause %q", tn)})
 			}
 			cte.used = true
@@ -88,20 +98,25 @@ func (b *Builder) buildDataSource(
 		ds := b.resolveDataSource(tn, privilege.SELECT)
 		switch t := ds.(type) {
 		case opt.Table:
-			return b.buildScan(t, tn, nil /* ordinals */, indexFlags, excludeMutations, inScope)
+			return b.buildScan(
+				t, tn, nil /* ordinals */, indexFlags, locking, excludeMutations, inScope,
+			)
 		case opt.View:
+			checkNoLocking(locking)
 			return b.buildView(t, inScope)
 		default:
 			panic(unimplementedf("sequences are not supported"))
 		}
 
 	case *tree.ParenTableExpr:
-		return b.buildDataSource(source.Expr, indexFlags, inScope)
+		return b.buildDataSource(source.Expr, indexFlags, locking, inScope)
 
 	case *tree.RowsFromExpr:
+		checkNoLocking(locking)
 		return b.buildZip(source.Items, inScope)
 
 	case *tree.Subquery:
+		checkNoLocking(locking)
 		outScope = b.buildStmt(source.Select, inScope)
 
 		// Treat the subquery result as an anonymous data source (i.e. column names
@@ -113,6 +128,10 @@ func (b *Builder) buildDataSource(
 		return outScope
 
 	case *tree.StatementSource:
+		checkNoLocking(locking)
+		// The locking clause does not apply to the FROM clause of the statement.
+		defer func(saved tree.LockingClause) { b.locking = saved }(b.locking)
+		b.locking = nil
 		outScope = b.buildStmt(source.Statement, inScope)
 		if len(outScope.cols) == 0 {
 			panic(builderError{pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
@@ -124,7 +143,7 @@ func (b *Builder) buildDataSource(
 		ds := b.resolveDataSourceRef(source, privilege.SELECT)
 		switch t := ds.(type) {
 		case opt.Table:
-			outScope = b.buildScanFromTableRef(t, source, indexFlags, inScope)
+			outScope = b.buildScanFromTableRef(t, source, indexFlags, locking, inScope)
 		default:
 			panic(unimplementedf("view and sequence numeric refs are not supported"))
 		}
@@ -238,7 +257,11 @@ func (b *Builder) renameSource(as tree.AliasClause, scope *scope) {
 // Note, the query SELECT * FROM [53() as t] is unsupported. Column lists must
 // be non-empty
 func (b *Builder) buildScanFromTableRef(
-	tab opt.Table, ref *tree.TableRef, indexFlags *tree.IndexFlags, inScope *scope,
+	tab opt.Table,
+	ref *tree.TableRef,
+	indexFlags *tree.IndexFlags,
+	locking opt.Locking,
+	inScope *scope,
 ) (outScope *scope) {
 	if ref.Columns != nil && len(ref.Columns) == 0 {
 		panic(builderError{pgerror.NewErrorf(pgerror.CodeSyntaxError,
@@ -261,13 +284,14 @@ func (b *Builder) buildScanFromTableRef(
 			ordinals[i] = ord
 		}
 	}
-	return b.buildScan(tab, tab.Name(), ordinals, indexFlags, excludeMutations, inScope)
+	return b.buildScan(tab, tab.Name(), ordinals, indexFlags, locking, excludeMutations, inScope)
 }
 
 // buildScan builds a memo group for a ScanOp or VirtualScanOp expression on the
 // given table with the given table name. If the ordinals slice is not nil, then
 // only columns with ordinals in that list are projected by the scan. Otherwise,
-// all columns from the table are projected.
+// all columns from the table are projected. If locking.IsLocking() is true, the
+// scan locks the rows it reads.
 //
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
@@ -276,6 +300,7 @@ func (b *Builder) buildScan(
 	tn *tree.TableName,
 	ordinals []int,
 	indexFlags *tree.IndexFlags,
+	locking opt.Locking,
 	scanMutationCols bool,
 	inScope *scope,
 ) (outScope *scope) {
@@ -320,10 +345,11 @@ func (b *Builder) buildScan(
 		if indexFlags != nil {
 			panic(builderError{errors.Errorf("index flags not allowed with virtual tables")})
 		}
+		checkNoLocking(locking)
 		private := memo.VirtualScanPrivate{Table: tabID, Cols: tabColIDs}
 		outScope.expr = b.factory.ConstructVirtualScan(&private)
 	} else {
-		private := memo.ScanPrivate{Table: tabID, Cols: tabColIDs}
+		private := memo.ScanPrivate{Table: tabID, Cols: tabColIDs, Locking: locking}
 
 		if indexFlags != nil {
 			private.Flags.NoIndexJoin = indexFlags.NoIndexJoin
@@ -350,6 +376,7 @@ func (b *Builder) buildScan(
 			}
 		}
 
+		b.addPartialIndexPredicatesForTable(tabID)
 		outScope.expr = b.factory.ConstructScan(&private)
 	}
 	return outScope
@@ -376,37 +403,43 @@ func (b *Builder) buildWithOrdinality(colName string, inScope *scope) (outScope
 	return inScope
 }
 
-func (b *Builder) buildCTE(ctes []*tree.CTE, inScope *scope) (outScope *scope) {
+func (b *Builder) buildCTE(with *tree.With, inScope *scope) (outScope *scope) {
 	outScope = inScope.push()
 
 	outScope.ctes = make(map[string]*cteSource)
-	for i := range ctes {
-		cteScope := b.buildStmt(ctes[i].Stmt, outScope)
-		cols := cteScope.cols
-		name := ctes[i].Name.Alias
+	for _, cte := range with.CTEList {
+		name := cte.Name.Alias
 
 		if _, ok := outScope.ctes[name.String()]; ok {
 			panic(builderError{
-				fmt.Errorf("WITH query name %s specified more than once", ctes[i].Name.Alias),
+				fmt.Errorf("WITH query name %s specified more than once", name),
 			})
 		}
 
+		var cteScope *scope
+		if with.Recursive {
+			cteScope = b.buildRecursiveCTE(cte, outScope)
+		} else {
+			cteScope = b.buildStmt(cte.Stmt, outScope)
+		}
+		cols := cteScope.cols
+
 		// Names for the output columns can optionally be specified.
-		if ctes[i].Name.Cols != nil {
-			if len(cteScope.cols) != len(ctes[i].Name.Cols) {
+		if cte.Name.Cols != nil {
+			if len(cteScope.cols) != len(cte.Name.Cols) {
 				panic(builderError{
 					fmt.Errorf(
 						"source %q has %d columns available but %d columns specified",
-						name, len(cteScope.cols), len(ctes[i].Name.Cols),
+						name, len(cteScope.cols), len(cte.Name.Cols),
 					),
 				})
 			}
 
 			cols = make([]scopeColumn, len(cteScope.cols))
-			tableName := tree.MakeUnqualifiedTableName(ctes[i].Name.Alias)
+			tableName := tree.MakeUnqualifiedTableName(name)
 			copy(cols, cteScope.cols)
 			for j := range cols {
-				cols[j].name = ctes[i].Name.Cols[j]
+				cols[j].name = cte.Name.Cols[j]
 				cols[j].table = tableName
 			}
 		}
@@ -416,8 +449,8 @@ func (b *Builder) buildCTE(ctes []*tree.CTE, inScope *scope) (outScope *scope) {
 				"WITH clause %q does not have a RETURNING clause", tree.ErrString(&name))})
 		}
 
-		outScope.ctes[ctes[i].Name.Alias.String()] = &cteSource{
-			name: ctes[i].Name,
+		outScope.ctes[name.String()] = &cteSource{
+			name: cte.Name,
 			cols: cols,
 			expr: cteScope.expr,
 		}
@@ -426,6 +459,100 @@ func (b *Builder) buildCTE(ctes []*tree.CTE, inScope *scope) (outScope *scope) {
 	return outScope
 }
 
+// buildRecursiveCTE builds a CTE defined in a WITH RECURSIVE clause. A
+// recursive CTE has the form:
+//
+//   <initial query> UNION [ALL] <recursive query>
+//
+// where the recursive query refers to the CTE itself. The reference is built
+// as a WorkTableScan, which returns the rows produced by the previous
+// iteration (see RecursiveCTE in relational.opt). If the CTE does not have
+// this form or does not refer to itself, it is built like a regular CTE.
+func (b *Builder) buildRecursiveCTE(cte *tree.CTE, inScope *scope) (outScope *scope) {
+	sel, ok := cte.Stmt.(*tree.Select)
+	if !ok || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return b.buildStmt(cte.Stmt, inScope)
+	}
+	union, ok := sel.Select.(*tree.UnionClause)
+	if !ok || union.Type != tree.UnionOp {
+		return b.buildStmt(cte.Stmt, inScope)
+	}
+	name := cte.Name.Alias
+
+	initialScope := b.buildSelect(union.Left, nil /* desiredTypes */, inScope)
+	initialScope.removeHiddenCols()
+
+	// Synthesize the columns of the working table, which is what the
+	// recursive query refers to.
+	workScope := inScope.push()
+	tableName := tree.MakeUnqualifiedTableName(name)
+	initialTypes := make([]types.T, len(initialScope.cols))
+	for i := range initialScope.cols {
+		initialTypes[i] = initialScope.cols[i].typ
+		colName := string(initialScope.cols[i].name)
+		if len(cte.Name.Cols) == len(initialScope.cols) {
+			colName = string(cte.Name.Cols[i])
+		}
+		col := b.synthesizeColumn(workScope, colName, initialTypes[i], nil, nil /* scalar */)
+		col.table = tableName
+	}
+	workTableCols := colsToColList(workScope.cols)
+	workScope.expr = b.factory.ConstructWorkTableScan(&memo.WorkTableScanPrivate{
+		Name: string(name),
+		Cols: workTableCols,
+	})
+
+	workTable := &cteSource{
+		name:      cte.Name,
+		cols:      workScope.cols,
+		expr:      workScope.expr,
+		recursive: true,
+	}
+	inScope.ctes[name.String()] = workTable
+	recursiveScope := b.buildSelect(union.Right, initialTypes, inScope)
+	delete(inScope.ctes, name.String())
+
+	if !workTable.used {
+		// The CTE does not refer to itself.
+		return b.buildStmt(cte.Stmt, inScope)
+	}
+	recursiveScope.removeHiddenCols()
+
+	if len(initialScope.cols) != len(recursiveScope.cols) {
+		panic(builderError{pgerror.NewErrorf(
+			pgerror.CodeSyntaxError,
+			"each %v query must have the same number of columns: %d vs %d",
+			union.Type, len(initialScope.cols), len(recursiveScope.cols),
+		)})
+	}
+
+	outScope = inScope.push()
+	for i := range initialScope.cols {
+		l, r := initialTypes[i], recursiveScope.cols[i].typ
+		if !(l.Equivalent(r) || r == types.Unknown) {
+			panic(builderError{pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s overall",
+				name, i+1, l, r)})
+		}
+		// The output columns contain values from both the initial and the
+		// recursive query, so new columns are synthesized (like for UNION).
+		b.synthesizeColumn(outScope, string(initialScope.cols[i].name), l, nil, nil /* scalar */)
+	}
+
+	private := memo.RecursiveCTEPrivate{
+		Name:          string(name),
+		WorkTableCols: workTableCols,
+		InitialCols:   colsToColList(initialScope.cols),
+		RecursiveCols: colsToColList(recursiveScope.cols),
+		OutCols:       colsToColList(outScope.cols),
+		Deduplicate:   !union.All,
+	}
+	outScope.expr = b.factory.ConstructRecursiveCTE(
+		initialScope.expr.(memo.RelExpr), recursiveScope.expr.(memo.RelExpr), &private,
+	)
+	return outScope
+}
+
 // checkCTEUsage ensures that a CTE that contains a mutation (like INSERT) is
 // used at least once by the query. Otherwise, it might not be executed.
 func (b *Builder) checkCTEUsage(inScope *scope) {
@@ -451,7 +578,7 @@ func (b *Builder) buildSelectStmt(
 		return b.buildSelect(stmt.Select, desiredTypes, inScope)
 
 	case *tree.SelectClause:
-		return b.buildSelectClause(stmt, nil /* orderBy */, desiredTypes, inScope)
+		return b.buildSelectClause(b.expandGroupingSets(stmt), nil /* orderBy */, desiredTypes, inScope)
 
 	case *tree.UnionClause:
 		return b.buildUnion(stmt, desiredTypes, inScope)
@@ -476,6 +603,7 @@ func (b *Builder) buildSelect(
 	orderBy := stmt.OrderBy
 	limit := stmt.Limit
 	with := stmt.With
+	locking := stmt.Locking
 
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
 		stmt = s.Select
@@ -506,17 +634,31 @@ func (b *Builder) buildSelect(
 			}
 			limit = stmt.Limit
 		}
+		if len(stmt.Locking) > 0 {
+			locking = append(locking[:len(locking):len(locking)], stmt.Locking...)
+		}
+	}
+
+	if len(locking) > 0 {
+		if err := locking.Validate(stmt.Select); err != nil {
+			panic(builderError{err})
+		}
 	}
 
 	if with != nil {
-		inScope = b.buildCTE(with.CTEList, inScope)
+		inScope = b.buildCTE(with, inScope)
 		defer b.checkCTEUsage(inScope)
 	}
 
+	// The locking clause only applies to the FROM clause of this SELECT, and
+	// not to the CTEs or any nested SELECT statement.
+	defer func(saved tree.LockingClause) { b.locking = saved }(b.locking)
+	b.locking = locking
+
 	// NB: The case statements are sorted lexicographically.
 	switch t := stmt.Select.(type) {
 	case *tree.SelectClause:
-		outScope = b.buildSelectClause(t, orderBy, desiredTypes, inScope)
+		outScope = b.buildSelectClause(b.expandGroupingSets(t), orderBy, desiredTypes, inScope)
 
 	case *tree.UnionClause:
 		outScope = b.buildUnion(t, desiredTypes, inScope)
@@ -550,6 +692,17 @@ func (b *Builder) buildSelect(
 	return outScope
 }
 
+// expandGroupingSets rewrites a SELECT clause that uses grouping sets into a
+// SELECT clause with a single aggregation (see tree.ExpandGroupingSets). The
+// SELECT clause is returned unchanged if it does not use grouping sets.
+func (b *Builder) expandGroupingSets(sel *tree.SelectClause) *tree.SelectClause {
+	expanded, err := tree.ExpandGroupingSets(sel, b.semaCtx.SearchPath)
+	if err != nil {
+		panic(builderError{err})
+	}
+	return expanded
+}
+
 // buildSelectClause builds a set of memo groups that represent the given
 // select clause. We pass the entire select statement rather than just the
 // select clause in order to handle ORDER BY scoping rules. ORDER BY can sort
@@ -620,7 +773,7 @@ func (b *Builder) buildFrom(from *tree.From, inScope *scope) (outScope *scope) {
 	}
 
 	if len(from.Tables) > 0 {
-		outScope = b.buildFromTables(from.Tables, inScope)
+		outScope = b.buildFromTables(from.Tables, nil /* lateralScope */, inScope)
 	} else {
 		outScope = inScope.push()
 		outScope.expr = b.factory.ConstructValues(memo.ScalarListWithEmptyTuple, opt.ColList{})
@@ -629,6 +782,55 @@ func (b *Builder) buildFrom(from *tree.From, inScope *scope) (outScope *scope) {
 	return outScope
 }
 
+// addPartialIndexPredicatesForTable builds the predicates of the partial
+// indexes of the given table, if there are any, and stores them in the table's
+// metadata so that the optimizer can use a partial index when a query filter
+// implies its predicate (see memo.PartialIndexPredicates).
+func (b *Builder) addPartialIndexPredicatesForTable(tabID opt.TableID) {
+	md := b.factory.Metadata()
+	tab := md.Table(tabID)
+
+	var predScope *scope
+	var preds map[int]memo.FiltersExpr
+	for i := 0; i < tab.IndexCount(); i++ {
+		predStr, isPartial := tab.Index(i).Predicate()
+		if !isPartial {
+			continue
+		}
+
+		if predScope == nil {
+			// The predicates may refer to any of the columns of the table.
+			predScope = b.allocScope()
+			predScope.cols = make([]scopeColumn, tab.ColumnCount())
+			for ord := range predScope.cols {
+				col := tab.Column(ord)
+				predScope.cols[ord] = scopeColumn{
+					id:    tabID.ColumnID(ord),
+					name:  col.ColName(),
+					table: *tab.Name(),
+					typ:   col.DatumType(),
+				}
+			}
+			predScope.context = "index predicate"
+			preds = make(map[int]memo.FiltersExpr)
+		}
+
+		expr, err := parser.ParseExpr(predStr)
+		if err != nil {
+			panic(builderError{err})
+		}
+		texpr := predScope.resolveAndRequireType(expr, types.Bool)
+		pred := b.buildScalar(texpr, predScope, nil, nil, nil)
+
+		// Normalize the predicate into a list of conjuncts, in the same way
+		// that the conditions of a Select are normalized.
+		preds[i] = b.factory.CustomFuncs().SimplifyFilters(memo.FiltersExpr{{Condition: pred}})
+	}
+	if preds != nil {
+		md.SetTableAnnotation(tabID, memo.PartialIndexPredicatesAnnID, preds)
+	}
+}
+
 // buildWhere builds a set of memo groups that represent the given WHERE clause.
 //
 // See Builder.buildStmt for a description of the remaining input and return
@@ -668,17 +870,34 @@ func (b *Builder) buildWhere(where *tree.Where, inScope *scope) {
 //
 //   SELECT * FROM a JOIN (b JOIN c ON true) ON true
 //
+// Lateral data sources (see isLateral) can refer to the columns of the tables
+// that precede them in the list. lateralScope contains these columns; it is
+// nil for the first table of the list. A join whose right side refers to the
+// columns of its left side is built as an apply join (see constructJoin).
+//
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
-func (b *Builder) buildFromTables(tables tree.TableExprs, inScope *scope) (outScope *scope) {
-	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, inScope)
+func (b *Builder) buildFromTables(
+	tables tree.TableExprs, lateralScope, inScope *scope,
+) (outScope *scope) {
+	if lateralScope != nil && isLateral(tables[0]) {
+		outScope = b.buildDataSource(tables[0], nil /* indexFlags */, opt.Locking{}, lateralScope)
+	} else {
+		outScope = b.buildDataSource(tables[0], nil /* indexFlags */, opt.Locking{}, inScope)
+	}
 
 	// Recursively build table join.
 	tables = tables[1:]
 	if len(tables) == 0 {
 		return outScope
 	}
-	tableScope := b.buildFromTables(tables, inScope)
+	for _, table := range tables {
+		if isLateral(table) {
+			lateralScope = b.makeLateralScope(lateralScope, outScope, inScope)
+			break
+		}
+	}
+	tableScope := b.buildFromTables(tables, lateralScope, inScope)
 
 	// Check that the same table name is not used multiple times.
 	b.validateJoinTableNames(outScope, tableScope)
@@ -687,10 +906,40 @@ func (b *Builder) buildFromTables(tables tree.TableExprs, inScope *scope) (outSc
 
 	left := outScope.expr.(memo.RelExpr)
 	right := tableScope.expr.(memo.RelExpr)
-	outScope.expr = b.factory.ConstructInnerJoin(left, right, memo.TrueFilter)
+	outScope.expr = b.constructJoin(sqlbase.InnerJoin, left, right, memo.TrueFilter)
 	return outScope
 }
 
+// isLateral returns whether the given data source can refer to the columns
+// of the data sources that precede it in the FROM clause. This is the case
+// of data sources marked LATERAL and, as in Postgres, of set-returning
+// functions.
+func isLateral(texpr tree.TableExpr) bool {
+	switch t := texpr.(type) {
+	case *tree.AliasedTableExpr:
+		return t.Lateral || isLateral(t.Expr)
+	case *tree.ParenTableExpr:
+		return isLateral(t.Expr)
+	case *tree.RowsFromExpr:
+		return true
+	}
+	return false
+}
+
+// makeLateralScope returns the scope in which a lateral data source is built.
+// It contains the columns of prev, if not nil, followed by the columns of src,
+// and its parent is inScope. The columns of the data source that refer to the
+// lateral scope are outer columns of the data source.
+func (b *Builder) makeLateralScope(prev, src, inScope *scope) *scope {
+	lateralScope := inScope.push()
+	lateralScope.lateral = true
+	if prev != nil {
+		lateralScope.appendColumnsFromScope(prev)
+	}
+	lateralScope.appendColumnsFromScope(src)
+	return lateralScope
+}
+
 // validateAsOf ensures that any AS OF SYSTEM TIME timestamp is consistent with
 // that of the root statement.
 func (b *Builder) validateAsOf(asOf tree.AsOfClause) {
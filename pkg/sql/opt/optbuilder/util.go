@@ -394,10 +394,12 @@ func (b *Builder) assertNoAggregationOrWindowing(expr tree.Expr, op string) {
 
 // resolveTable returns the data source in the catalog with the given name. If
 // the name does not resolve to a table, or if the current user does not have
-// the given privilege, then resolveTable raises an error.
+// the given privilege, then resolveTable raises an error. Materialized views
+// are only resolved for reading, since their contents can only be changed by
+// REFRESH MATERIALIZED VIEW.
 func (b *Builder) resolveTable(tn *tree.TableName, priv privilege.Kind) opt.Table {
 	tab, ok := b.resolveDataSource(tn, priv).(opt.Table)
-	if !ok {
+	if !ok || (tab.IsMaterializedView() && priv != privilege.SELECT) {
 		panic(builderError{sqlbase.NewWrongObjectTypeError(tn, "table")})
 	}
 	return tab
@@ -253,6 +253,7 @@ func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 	if def.Computed.Expr != nil {
 		s := tree.Serialize(def.Computed.Expr)
 		col.ComputedExpr = &s
+		col.Virtual = def.Computed.Virtual
 	}
 
 	// Add mutation columns to the Mutations list.
@@ -270,6 +271,12 @@ func (tt *Table) addIndex(def *tree.IndexTableDef, typ indexType) *Index {
 		table:    tt,
 	}
 
+	// The shard column of a hash sharded index is its first column.
+	if def.Sharded != nil {
+		shardColName := tt.addShardColumn(def, idx)
+		idx.addColumn(tt, shardColName, tree.Ascending, keyCol)
+	}
+
 	// Add explicit columns and mark primary key columns as not null.
 	notNullIndex := true
 	for _, colDef := range def.Columns {
@@ -336,6 +343,10 @@ func (tt *Table) addIndex(def *tree.IndexTableDef, typ indexType) *Index {
 		}
 	}
 
+	if def.Predicate != nil {
+		idx.predicate = tree.Serialize(def.Predicate)
+	}
+
 	// Add storing columns.
 	for _, name := range def.Storing {
 		// Only add storing columns that weren't added as part of adding implicit
@@ -357,6 +368,43 @@ func (tt *Table) addIndex(def *tree.IndexTableDef, typ indexType) *Index {
 	return idx
 }
 
+// addShardColumn adds the hidden shard column of a hash sharded index to the
+// table, unless another index already added it, and records the sharding of
+// the index. It returns the name of the shard column.
+func (tt *Table) addShardColumn(def *tree.IndexTableDef, idx *Index) string {
+	buckets, err := def.Sharded.ShardBuckets.(*tree.NumVal).AsInt32()
+	if err != nil {
+		panic(err)
+	}
+	colNames := make([]string, len(def.Columns))
+	for i := range def.Columns {
+		colNames[i] = string(def.Columns[i].Column)
+	}
+	idx.sharding = opt.ShardedIndex{BucketCount: buckets, ColumnCount: len(colNames)}
+	idx.shardingSet = true
+
+	name := sqlbase.GetShardColumnName(colNames, buckets)
+	for _, col := range tt.Columns {
+		if col.Name == name {
+			return name
+		}
+	}
+	expr := sqlbase.MakeHashShardComputeExpr(colNames, buckets)
+	tt.Columns = append(tt.Columns, &Column{
+		Name:         name,
+		Type:         types.Int,
+		Hidden:       true,
+		ComputedExpr: &expr,
+	})
+	if len(tt.Indexes) != 0 {
+		// The primary index stores every column of the table.
+		tt.Indexes[opt.PrimaryIndex].addColumnByOrdinal(
+			tt, len(tt.Columns)-1, tree.Ascending, nonKeyCol,
+		)
+	}
+	return name
+}
+
 func (tt *Table) makeIndexName(defName tree.Name, typ indexType) string {
 	name := string(defName)
 	if name == "" {
@@ -350,6 +350,11 @@ func (tt *Table) IsVirtualTable() bool {
 	return tt.IsVirtual
 }
 
+// IsMaterializedView is part of the opt.Table interface.
+func (tt *Table) IsMaterializedView() bool {
+	return false
+}
+
 // ColumnCount is part of the opt.Table interface.
 func (tt *Table) ColumnCount() int {
 	return len(tt.Columns) + len(tt.Mutations)
@@ -433,6 +438,15 @@ type Index struct {
 	// index reference.
 	foreignKey opt.ForeignKeyReference
 	fkSet      bool
+
+	// predicate is the SQL text of the predicate of a partial index, or the
+	// empty string if the index is not partial.
+	predicate string
+
+	// sharding describes how the index is hash sharded, if shardingSet is
+	// true.
+	sharding    opt.ShardedIndex
+	shardingSet bool
 }
 
 // IdxName is part of the opt.Index interface.
@@ -480,6 +494,16 @@ func (ti *Index) ForeignKey() (opt.ForeignKeyReference, bool) {
 	return ti.foreignKey, ti.fkSet
 }
 
+// Predicate is part of the opt.Index interface.
+func (ti *Index) Predicate() (string, bool) {
+	return ti.predicate, ti.predicate != ""
+}
+
+// Sharding is part of the opt.Index interface.
+func (ti *Index) Sharding() (opt.ShardedIndex, bool) {
+	return ti.sharding, ti.shardingSet
+}
+
 // Column implements the opt.Column interface for testing purposes.
 type Column struct {
 	Hidden       bool
@@ -488,6 +512,7 @@ type Column struct {
 	Type         types.T
 	DefaultExpr  *string
 	ComputedExpr *string
+	Virtual      bool
 }
 
 var _ opt.Column = &Column{}
@@ -541,6 +566,11 @@ func (tc *Column) ComputedExprStr() string {
 	return *tc.ComputedExpr
 }
 
+// IsVirtual is part of the opt.Column interface.
+func (tc *Column) IsVirtual() bool {
+	return tc.Virtual
+}
+
 // TableStat implements the opt.TableStatistic interface for testing purposes.
 type TableStat struct {
 	js stats.JSONStatistic
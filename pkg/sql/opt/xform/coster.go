@@ -214,6 +214,18 @@ func (c *coster) computeScanCost(scan *memo.ScanExpr, required *physical.Require
 	rowCount := scan.Relational().Stats.RowCount
 	perRowCost := c.rowScanCost(scan.Table, scan.Index, scan.Cols.Len())
 
+	if scan.Index == opt.PrimaryIndex {
+		// Virtual computed columns are not stored in the primary index, so they
+		// have to be computed for every scanned row.
+		md := c.mem.Metadata()
+		tab := md.Table(scan.Table)
+		scan.Cols.ForEach(func(i int) {
+			if tab.Column(md.ColumnOrdinal(opt.ColumnID(i))).IsVirtual() {
+				perRowCost += cpuCostFactor
+			}
+		})
+	}
+
 	if ordering.ScanIsReverse(scan, &required.Ordering) {
 		if rowCount > 1 {
 			// Need to do binary search to seek to the previous row.
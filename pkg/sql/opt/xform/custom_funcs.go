@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 )
 
@@ -176,6 +177,10 @@ func (c *CustomFuncs) GenerateIndexScans(grp memo.RelExpr, scanPrivate *memo.Sca
 //        $outerFilter
 //      )
 //
+// A partial index is only considered if the filter implies its predicate (see
+// partialIndexRemainingFilters). Since such an index only contains rows that
+// satisfy the filter, it is scanned even if no constraint can be derived for
+// it.
 func (c *CustomFuncs) GenerateConstrainedScans(
 	grp memo.RelExpr, scanPrivate *memo.ScanPrivate, filters memo.FiltersExpr,
 ) {
@@ -185,12 +190,39 @@ func (c *CustomFuncs) GenerateConstrainedScans(
 	// Iterate over all indexes.
 	var iter scanIndexIter
 	iter.init(c.e.mem, scanPrivate)
+	iter.includePartial = true
 	for iter.next() {
+		indexFilters := filters
+		_, isPartial := iter.index.Predicate()
+		if isPartial {
+			var ok bool
+			indexFilters, ok = c.partialIndexRemainingFilters(
+				filters, scanPrivate.Table, iter.indexOrdinal)
+			if !ok {
+				continue
+			}
+		}
+
+		// If the index is hash sharded, add the filter on its shard column that
+		// is implied by the filters on the columns the shard is computed from.
+		shardFilter, isSharded := c.makeShardFilter(indexFilters, scanPrivate.Table, iter.index)
+		if isSharded {
+			indexFilters = append(indexFilters[:len(indexFilters):len(indexFilters)], shardFilter)
+		}
+
 		// Check whether the filter can constrain the index.
 		constraint, remaining, ok := c.tryConstrainIndex(
-			filters, scanPrivate.Table, iter.indexOrdinal, false /* isInverted */)
+			indexFilters, scanPrivate.Table, iter.indexOrdinal, false /* isInverted */)
 		if !ok {
-			continue
+			if !isPartial {
+				continue
+			}
+			remaining = indexFilters
+		}
+		if isSharded {
+			// The shard filter is implied by the other filters, and the shard
+			// column may not be projected by the Scan: never keep it.
+			remaining = c.removeShardFilter(remaining, shardFilter.Condition)
 		}
 
 		// Construct new constrained ScanPrivate.
@@ -250,6 +282,11 @@ func (c *CustomFuncs) HasInvertedIndexes(scanPrivate *memo.ScanPrivate) bool {
 // project columns other than the primary key columns. The reason it's pre-
 // constrained is that we cannot treat an inverted index in the same way as a
 // regular index, since it does not actually contain the indexed column.
+//
+// If every row matched by the filter has several entries in the index, for
+// example one for each of the trigrams of a LIKE pattern, the primary keys of
+// one Scan per entry are intersected, and the rows are fetched with a lookup
+// join on the primary index instead.
 func (c *CustomFuncs) GenerateInvertedIndexScans(
 	grp memo.RelExpr, scanPrivate *memo.ScanPrivate, filters memo.FiltersExpr,
 ) {
@@ -261,20 +298,25 @@ func (c *CustomFuncs) GenerateInvertedIndexScans(
 	iter.init(c.e.mem, scanPrivate)
 	for iter.nextInverted() {
 		// Check whether the filter can constrain the index.
-		constraint, remaining, ok := c.tryConstrainIndex(
-			filters, scanPrivate.Table, iter.indexOrdinal, true /* isInverted */)
-		if !ok {
+		var ic idxconstraint.Instance
+		c.initIndexConstraints(
+			&ic, filters, scanPrivate.Table, iter.indexOrdinal, true /* isInverted */)
+		constraint := ic.Constraint()
+		if constraint.IsUnconstrained() {
 			continue
 		}
+		remaining := ic.RemainingFilters()
 
-		// Construct new ScanOpDef with the new index and constraint.
+		// Construct new ScanOpDef with the new index and constraint. Make copy
+		// of constraint so that idxconstraint instance is not referenced.
 		newScanPrivate := *scanPrivate
 		newScanPrivate.Index = iter.indexOrdinal
-		newScanPrivate.Constraint = constraint
+		newScanPrivate.Constraint = copyConstraint(constraint)
+		newScanPrivate.DuplicateKeys = ic.DuplicateKeys()
 
-		// Though the index is marked as containing the JSONB column being
-		// indexed, it doesn't actually, and it's only valid to extract the
-		// primary key columns from it.
+		// Though the index is marked as containing the JSONB, ARRAY, TSVECTOR
+		// or STRING column being indexed, it doesn't actually, and it's only
+		// valid to extract the primary key columns from it.
 		newScanPrivate.Cols = sb.primaryKeyCols()
 
 		// The Scan operator always goes in a new group, since it's always nested
@@ -284,9 +326,19 @@ func (c *CustomFuncs) GenerateInvertedIndexScans(
 		// correct columns, but it's difficult to tell at this point.
 		sb.setScan(&newScanPrivate)
 
+		// If every matched row also has an entry in the spans of other
+		// constraints, only look up the primary keys found in all of them.
+		sb.addIntersect(ic.IntersectConstraints())
+
 		// If remaining filter exists, split it into one part that can be pushed
 		// below the IndexJoin, and one part that needs to stay above.
 		remaining = sb.addSelectAfterSplit(remaining, newScanPrivate.Cols)
+
+		// If the scan can return the same primary key more than once, remove
+		// the duplicates before looking up the rows.
+		if newScanPrivate.DuplicateKeys {
+			sb.addDistinctOn(newScanPrivate.Cols)
+		}
 		sb.addIndexJoin(scanPrivate.Cols)
 		sb.addSelect(remaining)
 
@@ -294,6 +346,86 @@ func (c *CustomFuncs) GenerateInvertedIndexScans(
 	}
 }
 
+// partialIndexRemainingFilters checks whether the given filters imply the
+// predicate of the given partial index, in which case the index contains every
+// row that can satisfy the filters. A conjunct of the predicate is implied if
+// it is identical to one of the filters, or if it has tight constraints that
+// contain the constraints of one of the filters. For example, the filter
+// (a > 10) implies the predicate (a > 0).
+//
+// If the predicate is implied, the filters are returned without the conjuncts
+// that are identical to conjuncts of the predicate, since every row of the
+// index already satisfies them.
+func (c *CustomFuncs) partialIndexRemainingFilters(
+	filters memo.FiltersExpr, tabID opt.TableID, indexOrd int,
+) (remainingFilters memo.FiltersExpr, ok bool) {
+	pred, ok := memo.PartialIndexPredicates(c.e.mem.Metadata(), tabID)[indexOrd]
+	if !ok {
+		// The predicate was not built, so it cannot be proven to be implied.
+		return nil, false
+	}
+
+	var redundant util.FastIntSet
+	for i := range pred {
+		implied := false
+		for j := range filters {
+			if filters[j].Condition == pred[i].Condition {
+				redundant.Add(j)
+				implied = true
+				break
+			}
+		}
+		if !implied && !c.filtersImplyConjunct(filters, &pred[i]) {
+			return nil, false
+		}
+	}
+
+	if redundant.Empty() {
+		return filters, true
+	}
+	remainingFilters = make(memo.FiltersExpr, 0, len(filters)-redundant.Len())
+	for i := range filters {
+		if !redundant.Contains(i) {
+			remainingFilters = append(remainingFilters, filters[i])
+		}
+	}
+	return remainingFilters, true
+}
+
+// filtersImplyConjunct returns true if the tight constraint derived from the
+// given conjunct of a partial index predicate contains the constraint derived
+// from one of the filters over the same columns.
+func (c *CustomFuncs) filtersImplyConjunct(
+	filters memo.FiltersExpr, conjunct *memo.FiltersItem,
+) bool {
+	conjProps := conjunct.ScalarProps(c.e.mem)
+	if !conjProps.TightConstraints || conjProps.Constraints.Length() != 1 {
+		return false
+	}
+	conjConstraint := conjProps.Constraints.Constraint(0)
+
+	for i := range filters {
+		cset := filters[i].ScalarProps(c.e.mem).Constraints
+		for j := 0; j < cset.Length(); j++ {
+			filterConstraint := cset.Constraint(j)
+			if !filterConstraint.Columns.Equals(&conjConstraint.Columns) {
+				continue
+			}
+			contained := true
+			for k := 0; k < filterConstraint.Spans.Count(); k++ {
+				if !conjConstraint.ContainsSpan(c.e.evalCtx, filterConstraint.Spans.Get(k)) {
+					contained = false
+					break
+				}
+			}
+			if contained {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // tryConstrainIndex tries to derive a constraint for the given index from the
 // specified filter. If a constraint is derived, it is returned along with any
 // filter remaining after extracting the constraint. If no constraint can be
@@ -306,6 +438,30 @@ func (c *CustomFuncs) tryConstrainIndex(
 		return nil, nil, false
 	}
 
+	// Generate index constraints.
+	var ic idxconstraint.Instance
+	c.initIndexConstraints(&ic, filters, tabID, indexOrd, isInverted)
+	constraint = ic.Constraint()
+	if constraint.IsUnconstrained() {
+		return nil, nil, false
+	}
+
+	// Return 0 if no remaining filter.
+	remaining := ic.RemainingFilters()
+
+	// Make copy of constraint so that idxconstraint instance is not referenced.
+	return copyConstraint(constraint), remaining, true
+}
+
+// initIndexConstraints initializes ic with the constraints derived for the
+// given index from the specified filter.
+func (c *CustomFuncs) initIndexConstraints(
+	ic *idxconstraint.Instance,
+	filters memo.FiltersExpr,
+	tabID opt.TableID,
+	indexOrd int,
+	isInverted bool,
+) {
 	// Fill out data structures needed to initialize the idxconstraint library.
 	// Use LaxKeyColumnCount, since all columns <= LaxKeyColumnCount are
 	// guaranteed to be part of each row's key (i.e. not stored in row's value,
@@ -324,20 +480,12 @@ func (c *CustomFuncs) tryConstrainIndex(
 		}
 	}
 
-	// Generate index constraints.
-	var ic idxconstraint.Instance
 	ic.Init(filters, columns, notNullCols, isInverted, c.e.evalCtx, c.e.f)
-	constraint = ic.Constraint()
-	if constraint.IsUnconstrained() {
-		return nil, nil, false
-	}
-
-	// Return 0 if no remaining filter.
-	remaining := ic.RemainingFilters()
+}
 
-	// Make copy of constraint so that idxconstraint instance is not referenced.
-	copy := *constraint
-	return &copy, remaining, true
+func copyConstraint(c *constraint.Constraint) *constraint.Constraint {
+	copy := *c
+	return &copy
 }
 
 // canMaybeConstrainIndex performs two checks that can quickly rule out the
@@ -385,6 +533,171 @@ func (c *CustomFuncs) canMaybeConstrainIndex(
 	return false
 }
 
+// maxShardedScanFanOut is the maximum number of shards of a hash sharded index
+// that are scanned separately, either to constrain every shard of the index or
+// to merge the ordered rows of the shards.
+const maxShardedScanFanOut = 64
+
+// makeShardFilter returns a filter on the shard column of the given index,
+// which is implied by the given filters, if the index is hash sharded:
+//
+//  - if the filters restrict every column that the shard is computed from to
+//    a few constant values, then the filter restricts the shard column to the
+//    shards of these values.
+//  - otherwise, if the filters reference the first column the shard is
+//    computed from, then the filter lists all the shards. This allows the
+//    filters on that column to constrain every shard of the index.
+//
+// makeShardFilter returns ok=false if no such filter can be derived.
+func (c *CustomFuncs) makeShardFilter(
+	filters memo.FiltersExpr, tabID opt.TableID, index opt.Index,
+) (_ memo.FiltersItem, ok bool) {
+	sharding, ok := index.Sharding()
+	if !ok {
+		return memo.FiltersItem{}, false
+	}
+
+	var buckets []int
+	if combos, ok := c.shardedColumnValues(filters, tabID, index, sharding); ok {
+		var bucketSet util.FastIntSet
+		for _, datums := range combos {
+			bucket, err := sqlbase.HashShardBucket(datums, sharding.BucketCount)
+			if err != nil {
+				return memo.FiltersItem{}, false
+			}
+			bucketSet.Add(int(bucket))
+		}
+		buckets = bucketSet.Ordered()
+	} else {
+		firstCol := tabID.ColumnID(index.Column(1).Ordinal)
+		referenced := false
+		for i := range filters {
+			if filters[i].ScalarProps(c.e.mem).OuterCols.Contains(int(firstCol)) {
+				referenced = true
+				break
+			}
+		}
+		if !referenced || sharding.BucketCount > maxShardedScanFanOut {
+			return memo.FiltersItem{}, false
+		}
+		buckets = make([]int, sharding.BucketCount)
+		for i := range buckets {
+			buckets[i] = i
+		}
+	}
+
+	shardCol := c.e.f.ConstructVariable(tabID.ColumnID(index.Column(0).Ordinal))
+	if len(buckets) == 1 {
+		bucket := c.e.f.ConstructConst(tree.NewDInt(tree.DInt(buckets[0])))
+		return memo.FiltersItem{Condition: c.e.f.ConstructEq(shardCol, bucket)}, true
+	}
+	elems := make(memo.ScalarListExpr, len(buckets))
+	elemTypes := make([]types.T, len(buckets))
+	for i := range buckets {
+		elems[i] = c.e.f.ConstructConst(tree.NewDInt(tree.DInt(buckets[i])))
+		elemTypes[i] = types.Int
+	}
+	tuple := c.e.f.ConstructTuple(elems, types.TTuple{Types: elemTypes})
+	return memo.FiltersItem{Condition: c.e.f.ConstructIn(shardCol, tuple)}, true
+}
+
+// removeShardFilter returns the given filters without the shard filter built
+// by makeShardFilter.
+func (c *CustomFuncs) removeShardFilter(
+	filters memo.FiltersExpr, shardFilter opt.ScalarExpr,
+) memo.FiltersExpr {
+	for i := range filters {
+		if filters[i].Condition == shardFilter {
+			newFilters := make(memo.FiltersExpr, 0, len(filters)-1)
+			newFilters = append(newFilters, filters[:i]...)
+			return append(newFilters, filters[i+1:]...)
+		}
+	}
+	return filters
+}
+
+// shardedColumnValues returns every combination of values of the columns that
+// the shard of the given hash sharded index is computed from, if the filters
+// restrict each of these columns to a set of constant values with an equality
+// or an IN condition. It returns ok=false if some column is not restricted this
+// way, or if there are more than maxShardedScanFanOut combinations.
+func (c *CustomFuncs) shardedColumnValues(
+	filters memo.FiltersExpr, tabID opt.TableID, index opt.Index, sharding opt.ShardedIndex,
+) (combos []tree.Datums, ok bool) {
+	md := c.e.mem.Metadata()
+	combos = []tree.Datums{nil}
+	for i := 1; i <= sharding.ColumnCount; i++ {
+		col := tabID.ColumnID(index.Column(i).Ordinal)
+		vals := c.constColumnValues(filters, col, md.ColumnType(col))
+		if len(vals) == 0 || len(combos)*len(vals) > maxShardedScanFanOut {
+			return nil, false
+		}
+		newCombos := make([]tree.Datums, 0, len(combos)*len(vals))
+		for _, combo := range combos {
+			for _, val := range vals {
+				newCombo := make(tree.Datums, len(combo), len(combo)+1)
+				copy(newCombo, combo)
+				newCombos = append(newCombos, append(newCombo, val))
+			}
+		}
+		combos = newCombos
+	}
+	return combos, true
+}
+
+// constColumnValues returns the constant values that the given column is
+// restricted to by an equality or an IN condition in the filters, or nil if
+// there is no such condition. NULL values are skipped, as no row can match
+// them. Values that don't have the type of the column are not returned, since
+// their shard can't be computed.
+func (c *CustomFuncs) constColumnValues(
+	filters memo.FiltersExpr, col opt.ColumnID, typ types.T,
+) tree.Datums {
+	for i := range filters {
+		var left, right opt.ScalarExpr
+		switch t := filters[i].Condition.(type) {
+		case *memo.EqExpr:
+			left, right = t.Left, t.Right
+		case *memo.InExpr:
+			left, right = t.Left, t.Right
+		default:
+			continue
+		}
+		if v, ok := left.(*memo.VariableExpr); !ok || v.Col != col {
+			continue
+		}
+
+		elems := memo.ScalarListExpr{right}
+		if filters[i].Condition.Op() == opt.InOp {
+			tuple, ok := right.(*memo.TupleExpr)
+			if !ok {
+				continue
+			}
+			elems = tuple.Elems
+		}
+		vals := make(tree.Datums, 0, len(elems))
+		for _, elem := range elems {
+			if !opt.IsConstValueOp(elem) {
+				vals = nil
+				break
+			}
+			val := memo.ExtractConstDatum(elem)
+			if val == tree.DNull {
+				continue
+			}
+			if !val.ResolvedType().Equivalent(typ) {
+				vals = nil
+				break
+			}
+			vals = append(vals, val)
+		}
+		if len(vals) > 0 {
+			return vals
+		}
+	}
+	return nil
+}
+
 // ----------------------------------------------------------------------
 //
 // Limit Rules
@@ -507,6 +820,195 @@ func (c *CustomFuncs) GenerateLimitedScans(
 	}
 }
 
+// CanSplitShardedScan returns true if the given Scan operator reads a hash
+// sharded index that cannot provide the required ordering, but that could if
+// the shard column was ignored. The rows of each shard are sorted according to
+// the index columns that follow the shard column, so in this case the Scan can
+// be split into one limited Scan per shard, whose results are merged by the
+// SplitLimitedShardedScan rule. The Scan must read at most
+// maxShardedScanFanOut shards, and each span of its constraint, if any, must
+// fall within a single shard.
+func (c *CustomFuncs) CanSplitShardedScan(
+	scanPrivate *memo.ScanPrivate, required physical.OrderingChoice,
+) bool {
+	if scanPrivate.HardLimit != 0 || required.Any() {
+		return false
+	}
+	md := c.e.mem.Metadata()
+	index := md.Table(scanPrivate.Table).Index(scanPrivate.Index)
+	if _, ok := index.Sharding(); !ok || index.IsInverted() {
+		return false
+	}
+	if ok, _ := ordering.ScanPrivateCanProvide(md, scanPrivate, &required); ok {
+		// The limit can be pushed into the Scan directly.
+		return false
+	}
+	if _, _, ok := c.shardedScanSpans(scanPrivate); !ok {
+		return false
+	}
+	shardRequired := c.withOptionalShardColumn(scanPrivate, required)
+	ok, _ := ordering.ScanPrivateCanProvide(md, scanPrivate, &shardRequired)
+	return ok
+}
+
+// SplitShardedScan constructs a UnionAll of Scan operators, one for each shard
+// read by the given Scan operator of a hash sharded index. Each Scan only reads
+// the rows of its shard, and is limited to the given number of rows in the
+// required ordering. The UnionAll returns the same columns as the given Scan,
+// and must be wrapped in a Limit operator that merges the results of the
+// shards according to the required ordering. See CanSplitShardedScan.
+func (c *CustomFuncs) SplitShardedScan(
+	scanPrivate *memo.ScanPrivate, limit tree.Datum, required physical.OrderingChoice,
+) memo.RelExpr {
+	md := c.e.mem.Metadata()
+	tab := md.Table(scanPrivate.Table)
+	index := tab.Index(scanPrivate.Index)
+	shardCol := scanPrivate.Table.ColumnID(index.Column(0).Ordinal)
+	buckets, bucketSpans, _ := c.shardedScanSpans(scanPrivate)
+
+	// All the shards are scanned in the same direction, which is determined by
+	// the required ordering.
+	shardRequired := c.withOptionalShardColumn(scanPrivate, required)
+	_, reverse := ordering.ScanPrivateCanProvide(md, scanPrivate, &shardRequired)
+	hardLimit := memo.MakeScanLimit(int64(*limit.(*tree.DInt)), reverse)
+
+	outCols := opt.ColSetToList(scanPrivate.Cols)
+	var union memo.RelExpr
+	var unionCols opt.ColList
+	for i, bucket := range buckets {
+		// Each Scan needs its own columns, so it reads a new instance of the
+		// table.
+		tabID := md.AddTable(tab)
+		remap := func(col opt.ColumnID) opt.ColumnID {
+			return tabID.ColumnID(md.ColumnOrdinal(col))
+		}
+
+		newScanPrivate := memo.ScanPrivate{
+			Table:     tabID,
+			Index:     scanPrivate.Index,
+			HardLimit: hardLimit,
+			Flags:     scanPrivate.Flags,
+			Locking:   scanPrivate.Locking,
+		}
+		cols := make(opt.ColList, len(outCols))
+		for j, col := range outCols {
+			cols[j] = remap(col)
+			newScanPrivate.Cols.Add(int(cols[j]))
+		}
+
+		var keyCols constraint.Columns
+		var spans constraint.Spans
+		if bucketSpans == nil {
+			keyCols.InitSingle(opt.MakeOrderingColumn(remap(shardCol), false /* descending */))
+			key := constraint.MakeKey(bucket)
+			var span constraint.Span
+			span.Init(key, constraint.IncludeBoundary, key, constraint.IncludeBoundary)
+			spans.InitSingleSpan(&span)
+		} else {
+			oldCols := &scanPrivate.Constraint.Columns
+			orderingCols := make([]opt.OrderingColumn, oldCols.Count())
+			for j := range orderingCols {
+				col := oldCols.Get(j)
+				orderingCols[j] = opt.MakeOrderingColumn(remap(col.ID()), col.Descending())
+			}
+			keyCols.Init(orderingCols)
+			spans = bucketSpans[i]
+		}
+		keyCtx := constraint.MakeKeyContext(&keyCols, c.e.evalCtx)
+		newScanPrivate.Constraint = &constraint.Constraint{}
+		newScanPrivate.Constraint.Init(&keyCtx, &spans)
+		scan := c.e.f.ConstructScan(&newScanPrivate)
+
+		if union == nil {
+			union, unionCols = scan, cols
+			continue
+		}
+
+		// The last UnionAll returns the columns of the original Scan; the others
+		// need new columns.
+		newUnionCols := outCols
+		if i < len(buckets)-1 {
+			newUnionCols = make(opt.ColList, len(outCols))
+			for j, col := range outCols {
+				newUnionCols[j] = md.AddColumn(md.ColumnLabel(col), md.ColumnType(col))
+			}
+		}
+		union = c.e.f.ConstructUnionAll(union, scan, &memo.SetPrivate{
+			LeftCols:  unionCols,
+			RightCols: cols,
+			OutCols:   newUnionCols,
+		})
+		unionCols = newUnionCols
+	}
+	return union
+}
+
+// shardedScanSpans returns the shards read by the given Scan operator of a
+// hash sharded index. If the Scan is constrained, it also returns, for each
+// shard, the spans of the constraint that fall within the shard; bucketSpans
+// is nil otherwise. It returns ok=false if the Scan reads more than
+// maxShardedScanFanOut shards, or if a span of its constraint covers more than
+// one shard.
+func (c *CustomFuncs) shardedScanSpans(
+	scanPrivate *memo.ScanPrivate,
+) (buckets tree.Datums, bucketSpans []constraint.Spans, ok bool) {
+	md := c.e.mem.Metadata()
+	index := md.Table(scanPrivate.Table).Index(scanPrivate.Index)
+	sharding, _ := index.Sharding()
+
+	if scanPrivate.Constraint == nil {
+		if sharding.BucketCount > maxShardedScanFanOut {
+			return nil, nil, false
+		}
+		buckets = make(tree.Datums, sharding.BucketCount)
+		for i := range buckets {
+			buckets[i] = tree.NewDInt(tree.DInt(i))
+		}
+		return buckets, nil, true
+	}
+
+	cons := scanPrivate.Constraint
+	shardCol := scanPrivate.Table.ColumnID(index.Column(0).Ordinal)
+	if cons.Columns.Get(0).ID() != shardCol {
+		return nil, nil, false
+	}
+	keyCtx := constraint.MakeKeyContext(&cons.Columns, c.e.evalCtx)
+	for i := 0; i < cons.Spans.Count(); i++ {
+		span := cons.Spans.Get(i)
+		start, end := span.StartKey(), span.EndKey()
+		if start.IsEmpty() || end.IsEmpty() ||
+			keyCtx.Compare(0 /* colIdx */, start.Value(0), end.Value(0)) != 0 {
+			return nil, nil, false
+		}
+		// The spans are ordered, so the spans of a shard are contiguous.
+		n := len(buckets)
+		if n == 0 || keyCtx.Compare(0 /* colIdx */, buckets[n-1], start.Value(0)) != 0 {
+			if n == maxShardedScanFanOut {
+				return nil, nil, false
+			}
+			buckets = append(buckets, start.Value(0))
+			bucketSpans = append(bucketSpans, constraint.Spans{})
+			n++
+		}
+		bucketSpans[n-1].Append(span)
+	}
+	return buckets, bucketSpans, len(buckets) > 0
+}
+
+// withOptionalShardColumn returns a copy of the given ordering in which the
+// shard column of the hash sharded index read by the given Scan operator is
+// optional.
+func (c *CustomFuncs) withOptionalShardColumn(
+	scanPrivate *memo.ScanPrivate, required physical.OrderingChoice,
+) physical.OrderingChoice {
+	index := c.e.mem.Metadata().Table(scanPrivate.Table).Index(scanPrivate.Index)
+	shardCol := scanPrivate.Table.ColumnID(index.Column(0).Ordinal)
+	shardRequired := required.Copy()
+	shardRequired.Optional = shardRequired.Optional.Copy()
+	shardRequired.Optional.Add(int(shardCol))
+	return shardRequired
+}
+
 // ----------------------------------------------------------------------
 //
 // Join Rules
@@ -654,6 +1156,7 @@ func (c *CustomFuncs) GenerateLookupJoins(
 		lookupJoin.JoinType = joinType
 		lookupJoin.Table = scanPrivate.Table
 		lookupJoin.Index = iter.indexOrdinal
+		lookupJoin.Locking = scanPrivate.Locking
 
 		// Find the longest prefix of index key columns that are equality columns.
 		numIndexKeyCols := iter.index.LaxKeyColumnCount()
@@ -745,6 +1248,7 @@ func (c *CustomFuncs) GenerateLookupJoins(
 		indexJoin.Index = opt.PrimaryIndex
 		indexJoin.KeyCols = pkCols
 		indexJoin.Cols = scanPrivate.Cols.Union(inputProps.OutputCols)
+		indexJoin.Locking = scanPrivate.Locking
 
 		// Create the LookupJoin for the index join in the same group.
 		c.e.mem.AddLookupJoinToGroup(&indexJoin, grp)
@@ -844,6 +1348,11 @@ func (c *CustomFuncs) GenerateZigzagJoins(
 		return
 	}
 
+	// The zigzag joiner doesn't lock the rows it reads.
+	if scanPrivate.Locking.IsLocking() {
+		return
+	}
+
 	fixedCols := memo.ExtractConstColumns(filters, c.e.mem, c.e.evalCtx)
 
 	if fixedCols.Len() == 0 {
@@ -1180,6 +1689,12 @@ type scanIndexIter struct {
 	indexOrdinal int
 	index        opt.Index
 	cols         opt.ColSet
+
+	// includePartial is true if next should also enumerate partial indexes.
+	// A partial index only contains the rows that satisfy its predicate, so
+	// callers that set it must only use a partial index when the filters of
+	// the query imply that predicate.
+	includePartial bool
 }
 
 func (it *scanIndexIter) init(mem *memo.Memo, scanPrivate *memo.ScanPrivate) {
@@ -1192,8 +1707,9 @@ func (it *scanIndexIter) init(mem *memo.Memo, scanPrivate *memo.ScanPrivate) {
 
 // next advances iteration to the next index of the Scan operator's table. This
 // is the primary index if it's the first time next is called, or a secondary
-// index thereafter. Inverted index are skipped. If the ForceIndex flag is set,
-// then all indexes except the forced index are skipped. When there are no more
+// index thereafter. Inverted index are skipped, and so are partial indexes
+// unless includePartial is set. If the ForceIndex flag is set, then all
+// indexes except the forced index are skipped. When there are no more
 // indexes to enumerate, next returns false. The current index is accessible via
 // the iterator's "index" field.
 func (it *scanIndexIter) next() bool {
@@ -1207,6 +1723,9 @@ func (it *scanIndexIter) next() bool {
 		if it.index.IsInverted() {
 			continue
 		}
+		if _, isPartial := it.index.Predicate(); isPartial && !it.includePartial {
+			continue
+		}
 		if it.scanPrivate.Flags.ForceIndex && it.scanPrivate.Flags.Index != it.indexOrdinal {
 			// If we are forcing a specific index, ignore the others.
 			continue
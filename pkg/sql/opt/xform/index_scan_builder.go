@@ -16,6 +16,7 @@ package xform
 
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
 )
@@ -29,6 +30,13 @@ import (
 //     $indexJoinPrivate
 //   )
 //
+// A DistinctOn expression can also be added between the inner filter and the
+// index join, in order to remove duplicate keys returned by an inverted index
+// scan. The Scan can also be replaced by an Intersect of the primary keys
+// returned by scanning several constraints on an inverted index. Since the
+// IndexJoin operator can only look up the rows of a Scan, a LookupJoin on the
+// primary index is used in its place in those cases.
+//
 // make the following calls:
 //
 //   var sb indexScanBuilder
@@ -47,6 +55,8 @@ type indexScanBuilder struct {
 	scanPrivate      memo.ScanPrivate
 	innerFilters     memo.FiltersExpr
 	outerFilters     memo.FiltersExpr
+	intersect        []constraint.Constraint
+	distinctCols     opt.ColSet
 	indexJoinPrivate memo.IndexJoinPrivate
 }
 
@@ -69,6 +79,17 @@ func (b *indexScanBuilder) primaryKeyCols() opt.ColSet {
 	return b.pkCols
 }
 
+// primaryKeyColList returns the columns from the scanned table's primary index,
+// in the same order as the index columns.
+func (b *indexScanBuilder) primaryKeyColList() opt.ColList {
+	primaryIndex := b.c.e.mem.Metadata().Table(b.tabID).Index(opt.PrimaryIndex)
+	pkCols := make(opt.ColList, primaryIndex.KeyColumnCount())
+	for i := range pkCols {
+		pkCols[i] = b.tabID.ColumnID(primaryIndex.Column(i).Ordinal)
+	}
+	return pkCols
+}
+
 // setScan constructs a standalone Scan expression. As a side effect, it clears
 // any expressions added during previous invocations of the builder. setScan
 // makes a copy of scanPrivate so that it doesn't escape.
@@ -76,9 +97,21 @@ func (b *indexScanBuilder) setScan(scanPrivate *memo.ScanPrivate) {
 	b.scanPrivate = *scanPrivate
 	b.innerFilters = nil
 	b.outerFilters = nil
+	b.intersect = nil
+	b.distinctCols = opt.ColSet{}
 	b.indexJoinPrivate = memo.IndexJoinPrivate{}
 }
 
+// addIntersect replaces the Scan expression with an Intersect of the primary
+// keys it returns and those returned by scanning each of the given constraints
+// on the same index. It must be called before any other add method.
+func (b *indexScanBuilder) addIntersect(constraints []constraint.Constraint) {
+	if b.innerFilters != nil || !b.distinctCols.Empty() || b.indexJoinPrivate.Table != 0 {
+		panic("cannot add intersect after other expressions have been added")
+	}
+	b.intersect = constraints
+}
+
 // addSelect wraps the input expression with a Select expression having the
 // given filter.
 func (b *indexScanBuilder) addSelect(filters memo.FiltersExpr) {
@@ -128,6 +161,16 @@ func (b *indexScanBuilder) addSelectAfterSplit(
 	return b.c.ExtractUnboundConditions(filters, cols)
 }
 
+// addDistinctOn wraps the input expression with a DistinctOn expression that
+// groups on the given columns, so that each combination of their values is
+// only returned once.
+func (b *indexScanBuilder) addDistinctOn(cols opt.ColSet) {
+	if b.indexJoinPrivate.Table != 0 {
+		panic("cannot add distinct on after an index join has been added")
+	}
+	b.distinctCols = cols
+}
+
 // addIndexJoin wraps the input expression with an IndexJoin expression that
 // produces the given set of columns by lookup in the primary index.
 func (b *indexScanBuilder) addIndexJoin(cols opt.ColSet) {
@@ -138,8 +181,9 @@ func (b *indexScanBuilder) addIndexJoin(cols opt.ColSet) {
 		panic("cannot add index join after an outer filter has been added")
 	}
 	b.indexJoinPrivate = memo.IndexJoinPrivate{
-		Table: b.tabID,
-		Cols:  cols,
+		Table:   b.tabID,
+		Cols:    cols,
+		Locking: b.scanPrivate.Locking,
 	}
 }
 
@@ -147,15 +191,24 @@ func (b *indexScanBuilder) addIndexJoin(cols opt.ColSet) {
 // expressions that were specified by previous calls to various add methods.
 func (b *indexScanBuilder) build(grp memo.RelExpr) {
 	// 1. Only scan.
-	if len(b.innerFilters) == 0 && b.indexJoinPrivate.Table == 0 {
+	if len(b.innerFilters) == 0 && b.distinctCols.Empty() && b.indexJoinPrivate.Table == 0 {
+		if len(b.intersect) != 0 {
+			panic("cannot build intersect without an index join")
+		}
 		b.mem.AddScanToGroup(&memo.ScanExpr{ScanPrivate: b.scanPrivate}, grp)
 		return
 	}
 
-	// 2. Wrap scan in inner filter if it was added.
-	input := b.f.ConstructScan(&b.scanPrivate)
+	// 2. Wrap scan (or intersect, if it was added) in inner filter if it was
+	// added.
+	var input memo.RelExpr
+	if len(b.intersect) != 0 {
+		input = b.buildIntersect()
+	} else {
+		input = b.f.ConstructScan(&b.scanPrivate)
+	}
 	if len(b.innerFilters) != 0 {
-		if b.indexJoinPrivate.Table == 0 {
+		if b.distinctCols.Empty() && b.indexJoinPrivate.Table == 0 {
 			b.mem.AddSelectToGroup(&memo.SelectExpr{Input: input, Filters: b.innerFilters}, grp)
 			return
 		}
@@ -163,8 +216,46 @@ func (b *indexScanBuilder) build(grp memo.RelExpr) {
 		input = b.f.ConstructSelect(input, b.innerFilters)
 	}
 
-	// 3. Wrap input in index join if it was added.
-	if b.indexJoinPrivate.Table != 0 {
+	// 3. Wrap input in distinct on if it was added.
+	if !b.distinctCols.Empty() {
+		private := memo.GroupingPrivate{GroupingCols: b.distinctCols}
+		if b.indexJoinPrivate.Table == 0 {
+			distinctOn := &memo.DistinctOnExpr{
+				Input:           input,
+				Aggregations:    memo.EmptyAggregationsExpr,
+				GroupingPrivate: private,
+			}
+			b.mem.AddDistinctOnToGroup(distinctOn, grp)
+			return
+		}
+
+		input = b.f.ConstructDistinctOn(input, memo.EmptyAggregationsExpr, &private)
+	}
+
+	// 4. Wrap input in index join if it was added. The IndexJoin operator can
+	// only look up the rows of a Scan, so use a LookupJoin on the primary index
+	// if the input is an intersect or was wrapped in a distinct on.
+	if b.indexJoinPrivate.Table != 0 && (len(b.intersect) != 0 || !b.distinctCols.Empty()) {
+		private := memo.LookupJoinPrivate{
+			JoinType: opt.InnerJoinOp,
+			Table:    b.indexJoinPrivate.Table,
+			Index:    opt.PrimaryIndex,
+			KeyCols:  b.primaryKeyColList(),
+			Cols:     b.indexJoinPrivate.Cols,
+			Locking:  b.indexJoinPrivate.Locking,
+		}
+		if len(b.outerFilters) == 0 {
+			lookupJoin := &memo.LookupJoinExpr{
+				Input:             input,
+				On:                memo.TrueFilter,
+				LookupJoinPrivate: private,
+			}
+			b.mem.AddLookupJoinToGroup(lookupJoin, grp)
+			return
+		}
+
+		input = b.f.ConstructLookupJoin(input, memo.TrueFilter, &private)
+	} else if b.indexJoinPrivate.Table != 0 {
 		if len(b.outerFilters) == 0 {
 			indexJoin := &memo.IndexJoinExpr{Input: input, IndexJoinPrivate: b.indexJoinPrivate}
 			b.mem.AddIndexJoinToGroup(indexJoin, grp)
@@ -174,11 +265,78 @@ func (b *indexScanBuilder) build(grp memo.RelExpr) {
 		input = b.f.ConstructIndexJoin(input, &b.indexJoinPrivate)
 	}
 
-	// 4. Wrap input in outer filter (which must exist at this point).
+	// 5. Wrap input in outer filter (which must exist at this point).
 	if len(b.outerFilters) == 0 {
-		// indexJoinDef == 0: outerFilters == 0 handled by #1 and #2 above.
-		// indexJoinDef != 0: outerFilters == 0 handled by #3 above.
+		// indexJoinDef == 0: outerFilters == 0 handled by #1, #2 and #3 above.
+		// indexJoinDef != 0: outerFilters == 0 handled by #4 above.
 		panic("outer filter cannot be 0 at this point")
 	}
 	b.mem.AddSelectToGroup(&memo.SelectExpr{Input: input, Filters: b.outerFilters}, grp)
 }
+
+// buildIntersect constructs an Intersect of the primary keys returned by the
+// Scan and by scanning each of the intersected constraints. Each Scan needs its
+// own columns, so it reads a new instance of the table; the last Intersect
+// returns the columns of the original Scan.
+func (b *indexScanBuilder) buildIntersect() memo.RelExpr {
+	md := b.mem.Metadata()
+	tab := md.Table(b.tabID)
+	outCols := opt.ColSetToList(b.scanPrivate.Cols)
+
+	var intersect memo.RelExpr
+	var intersectCols opt.ColList
+	for i := -1; i < len(b.intersect); i++ {
+		cons := b.scanPrivate.Constraint
+		if i >= 0 {
+			cons = &b.intersect[i]
+		}
+
+		tabID := md.AddTable(tab)
+		remap := func(col opt.ColumnID) opt.ColumnID {
+			return tabID.ColumnID(md.ColumnOrdinal(col))
+		}
+
+		newScanPrivate := b.scanPrivate
+		newScanPrivate.Table = tabID
+		newScanPrivate.Cols = opt.ColSet{}
+		cols := make(opt.ColList, len(outCols))
+		for j, col := range outCols {
+			cols[j] = remap(col)
+			newScanPrivate.Cols.Add(int(cols[j]))
+		}
+
+		orderingCols := make([]opt.OrderingColumn, cons.Columns.Count())
+		for j := range orderingCols {
+			col := cons.Columns.Get(j)
+			orderingCols[j] = opt.MakeOrderingColumn(remap(col.ID()), col.Descending())
+		}
+		var keyCols constraint.Columns
+		keyCols.Init(orderingCols)
+		keyCtx := constraint.MakeKeyContext(&keyCols, b.c.e.evalCtx)
+		newScanPrivate.Constraint = &constraint.Constraint{}
+		newScanPrivate.Constraint.Init(&keyCtx, &cons.Spans)
+		scan := b.f.ConstructScan(&newScanPrivate)
+
+		if intersect == nil {
+			intersect, intersectCols = scan, cols
+			continue
+		}
+
+		// The last Intersect returns the columns of the original Scan; the
+		// others need new columns.
+		newIntersectCols := outCols
+		if i < len(b.intersect)-1 {
+			newIntersectCols = make(opt.ColList, len(outCols))
+			for j, col := range outCols {
+				newIntersectCols[j] = md.AddColumn(md.ColumnLabel(col), md.ColumnType(col))
+			}
+		}
+		intersect = b.f.ConstructIntersect(intersect, scan, &memo.SetPrivate{
+			LeftCols:  intersectCols,
+			RightCols: cols,
+			OutCols:   newIntersectCols,
+		})
+		intersectCols = newIntersectCols
+	}
+	return intersect
+}
@@ -121,7 +121,8 @@ func (oc *optCatalog) newDataSource(
 	// Create wrapper for the data source now.
 	var ds opt.DataSource
 	switch {
-	case desc.IsTable():
+	case desc.IsTable() || desc.MaterializedView():
+		// Materialized views are read from their stored contents, like tables.
 		stats, err := oc.statsCache.GetTableStats(context.TODO(), desc.ID)
 		if err != nil {
 			// Ignore any error. We still want to be able to run queries even if we lose
@@ -317,6 +318,11 @@ func (ot *optTable) IsVirtualTable() bool {
 	return ot.desc.IsVirtualTable()
 }
 
+// IsMaterializedView is part of the opt.Table interface.
+func (ot *optTable) IsMaterializedView() bool {
+	return ot.desc.MaterializedView()
+}
+
 // ColumnCount is part of the opt.Table interface.
 func (ot *optTable) ColumnCount() int {
 	return len(ot.desc.Columns) + len(ot.mutations)
@@ -409,7 +415,10 @@ type optIndex struct {
 	tab  *optTable
 	desc *sqlbase.IndexDescriptor
 	// storedCols is the set of non-PK columns if this is the primary index,
-	// otherwise it is desc.StoreColumnIDs.
+	// otherwise it is desc.StoreColumnIDs. The virtual computed columns of the
+	// table are part of the primary index even though their values are not
+	// stored: the row fetcher computes them when they are scanned (see
+	// opt.Column.IsVirtual).
 	storedCols []sqlbase.ColumnID
 
 	numCols       int
@@ -554,6 +563,22 @@ func (oi *optIndex) ForeignKey() (opt.ForeignKeyReference, bool) {
 	return oi.foreignKey, oi.desc.ForeignKey.IsSet()
 }
 
+// Predicate is part of the opt.Index interface.
+func (oi *optIndex) Predicate() (string, bool) {
+	return oi.desc.Predicate, oi.desc.IsPartial()
+}
+
+// Sharding is part of the opt.Index interface.
+func (oi *optIndex) Sharding() (opt.ShardedIndex, bool) {
+	if !oi.desc.IsSharded() {
+		return opt.ShardedIndex{}, false
+	}
+	return opt.ShardedIndex{
+		BucketCount: oi.desc.Sharded.ShardBuckets,
+		ColumnCount: len(oi.desc.Sharded.ColumnNames),
+	}, true
+}
+
 // Table is part of the opt.Index interface.
 func (oi *optIndex) Table() opt.Table {
 	return oi.tab
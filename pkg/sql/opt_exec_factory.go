@@ -70,6 +70,7 @@ func (ef *execFactory) ConstructScan(
 	hardLimit int64,
 	reverse bool,
 	reqOrdering exec.OutputOrdering,
+	locking opt.Locking,
 ) (exec.Node, error) {
 	tabDesc := table.(*optTable).desc
 	indexDesc := index.(*optIndex).desc
@@ -95,6 +96,11 @@ func (ef *execFactory) ConstructScan(
 	scan.run.isSecondaryIndex = (indexDesc != &tabDesc.PrimaryIndex)
 	scan.hardLimit = hardLimit
 	scan.reverse = reverse
+	if locking.IsLocking() {
+		if err := scan.setLocking(locking.Strength, locking.WaitPolicy); err != nil {
+			return nil, err
+		}
+	}
 	var err error
 	scan.spans, err = spansFromConstraint(
 		tabDesc, indexDesc, indexConstraint, cols, scan.isDeleteSource)
@@ -282,6 +288,41 @@ func (ef *execFactory) ConstructHashJoin(
 	return p.makeJoinNode(leftSrc, rightSrc, pred), nil
 }
 
+// ConstructApplyJoin is part of the exec.Factory interface.
+func (ef *execFactory) ConstructApplyJoin(
+	joinType sqlbase.JoinType,
+	left exec.Node,
+	rightColumns sqlbase.ResultColumns,
+	onCond tree.TypedExpr,
+	planRightSideFn exec.ApplyJoinPlanRightSideFn,
+) (exec.Node, error) {
+	leftSrc := asDataSource(left)
+	rightInfo := sqlbase.NewSourceInfoForSingleTable(sqlbase.AnonymousTable, rightColumns)
+	pred, _, err := ef.planner.makeJoinPredicate(
+		context.TODO(), leftSrc.info, rightInfo, joinType, nil, /* cond */
+	)
+	if err != nil {
+		return nil, err
+	}
+	pred.onCond = pred.iVarHelper.Rebind(
+		onCond, false /* alsoReset */, false, /* normalizeToNonNil */
+	)
+	return &applyJoinNode{
+		joinType:  joinType,
+		input:     leftSrc,
+		pred:      pred,
+		columns:   pred.info.SourceColumns,
+		rightCols: rightColumns,
+		planRightSideFn: func(leftRow tree.Datums) (planNode, error) {
+			plan, err := planRightSideFn(leftRow)
+			if err != nil {
+				return nil, err
+			}
+			return plan.(planNode), nil
+		},
+	}, nil
+}
+
 // ConstructMergeJoin is part of the exec.Factory interface.
 func (ef *execFactory) ConstructMergeJoin(
 	joinType sqlbase.JoinType,
@@ -498,7 +539,11 @@ func (ef *execFactory) ConstructOrdinality(input exec.Node, colName string) (exe
 
 // ConstructIndexJoin is part of the exec.Factory interface.
 func (ef *execFactory) ConstructIndexJoin(
-	input exec.Node, table opt.Table, cols exec.ColumnOrdinalSet, reqOrdering exec.OutputOrdering,
+	input exec.Node,
+	table opt.Table,
+	cols exec.ColumnOrdinalSet,
+	reqOrdering exec.OutputOrdering,
+	locking opt.Locking,
 ) (exec.Node, error) {
 	tabDesc := table.(*optTable).desc
 	colCfg := makeScanColumnsConfig(table, cols)
@@ -527,6 +572,11 @@ func (ef *execFactory) ConstructIndexJoin(
 	tableScan.index = &primaryIndex
 	tableScan.run.isSecondaryIndex = false
 	tableScan.disableBatchLimit()
+	if locking.IsLocking() {
+		if err := tableScan.setLocking(locking.Strength, locking.WaitPolicy); err != nil {
+			return nil, err
+		}
+	}
 
 	primaryKeyColumns, colIDtoRowIndex := processIndexJoinColumns(tableScan, scan)
 	primaryKeyPrefix := roachpb.Key(sqlbase.MakeIndexKeyPrefix(tabDesc.TableDesc(), tableScan.index.ID))
@@ -557,6 +607,7 @@ func (ef *execFactory) ConstructLookupJoin(
 	lookupCols exec.ColumnOrdinalSet,
 	onCond tree.TypedExpr,
 	reqOrdering exec.OutputOrdering,
+	locking opt.Locking,
 ) (exec.Node, error) {
 	tabDesc := table.(*optTable).desc
 	indexDesc := index.(*optIndex).desc
@@ -569,6 +620,11 @@ func (ef *execFactory) ConstructLookupJoin(
 
 	tableScan.index = indexDesc
 	tableScan.run.isSecondaryIndex = (indexDesc != &tabDesc.PrimaryIndex)
+	if locking.IsLocking() {
+		if err := tableScan.setLocking(locking.Strength, locking.WaitPolicy); err != nil {
+			return nil, err
+		}
+	}
 
 	n := &lookupJoinNode{
 		input:    input.(planNode),
@@ -729,6 +785,24 @@ func (ef *execFactory) ConstructMax1Row(input exec.Node) (exec.Node, error) {
 	}, nil
 }
 
+// ConstructRecursiveCTE is part of the exec.Factory interface.
+func (ef *execFactory) ConstructRecursiveCTE(
+	initial exec.Node, fn exec.RecursiveCTEIterationFn, label string, deduplicate bool,
+) (exec.Node, error) {
+	return &recursiveCTENode{
+		initial: initial.(planNode),
+		genIterationFn: func(_ context.Context, workTable planNode) (planNode, error) {
+			plan, err := fn(workTable)
+			if err != nil {
+				return nil, err
+			}
+			return plan.(planNode), nil
+		},
+		label:       label,
+		deduplicate: deduplicate,
+	}, nil
+}
+
 // ConstructProjectSet is part of the exec.Factory interface.
 func (ef *execFactory) ConstructProjectSet(
 	n exec.Node, exprs tree.TypedExprs, zipCols sqlbase.ResultColumns, numColsPerGen []int,
@@ -885,7 +959,7 @@ func (ef *execFactory) ConstructInsert(
 
 	// Create the table insert, which does the bulk of the work.
 	ri, err := row.MakeInserter(ef.planner.txn, tabDesc, fkTables, colDescs,
-		row.CheckFKs, &ef.planner.alloc)
+		row.CheckFKs, ef.planner.EvalContext(), &ef.planner.alloc)
 	if err != nil {
 		return nil, err
 	}
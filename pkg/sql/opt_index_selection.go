@@ -109,6 +109,12 @@ func (p *planner) selectIndex(
 
 	candidates := make([]*indexInfo, 0, len(s.desc.Indexes)+1)
 	if s.specifiedIndex != nil {
+		// The heuristic planner doesn't reason about the predicates of partial
+		// indexes, so it can't use them to answer queries.
+		if s.specifiedIndex.IsPartial() {
+			return nil, fmt.Errorf("index \"%s\" is partial and cannot be used for this query",
+				s.specifiedIndex.Name)
+		}
 		// An explicit secondary index was requested. Only add it to the candidate
 		// indexes list.
 		candidates = append(candidates, &indexInfo{
@@ -121,6 +127,9 @@ func (p *planner) selectIndex(
 			index: &s.desc.PrimaryIndex,
 		})
 		for i := range s.desc.Indexes {
+			if s.desc.Indexes[i].IsPartial() {
+				continue
+			}
 			candidates = append(candidates, &indexInfo{
 				desc:  s.desc,
 				index: &s.desc.Indexes[i],
@@ -163,10 +172,13 @@ func (p *planner) selectIndex(
 	}
 
 	// Remove any inverted indexes that don't generate any spans, a full-scan of
-	// an inverted index is always invalid.
+	// an inverted index is always invalid. Also remove those whose spans can
+	// return the same primary key more than once, since the duplicates are not
+	// removed from the scan results.
 	for i := 0; i < len(candidates); {
 		c := candidates[i].ic.Constraint()
-		if candidates[i].index.Type == sqlbase.IndexDescriptor_INVERTED && (c == nil || c.IsUnconstrained()) {
+		if candidates[i].index.Type == sqlbase.IndexDescriptor_INVERTED &&
+			(c == nil || c.IsUnconstrained() || candidates[i].ic.DuplicateKeys()) {
 			candidates[i] = candidates[len(candidates)-1]
 			candidates = candidates[:len(candidates)-1]
 		} else {
@@ -127,6 +127,12 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *max1RowNode:
 		p.setUnlimited(n.plan)
 
+	case *recursiveCTENode:
+		p.setUnlimited(n.initial)
+
+	case *applyJoinNode:
+		p.setUnlimited(n.input.plan)
+
 	case *joinNode:
 		p.setUnlimited(n.left.plan)
 		p.setUnlimited(n.right.plan)
@@ -206,10 +212,13 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 
 	case *valuesNode:
 	case *virtualTableNode:
+	case *scanBufferNode:
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
+	case *refreshViewNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
 	case *renameIndexNode:
@@ -222,12 +231,18 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
+	case *createFunctionNode:
+	case *createSchemaNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropTypeNode:
+	case *dropFunctionNode:
+	case *dropSchemaNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
@@ -48,6 +48,16 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *max1RowNode:
 		setNeededColumns(n.plan, needed)
 
+	case *recursiveCTENode:
+		// The rows of the initial query are fed back into the recursive
+		// query, which may use any of the columns.
+		setNeededColumns(n.initial, allColumns(n.initial))
+
+	case *applyJoinNode:
+		// The plans of the right side may refer to any column of the left
+		// side.
+		setNeededColumns(n.input.plan, allColumns(n.input.plan))
+
 	case *spoolNode:
 		setNeededColumns(n.source, needed)
 
@@ -257,7 +267,9 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *alterIndexNode:
 	case *alterTableNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterUserSetPasswordNode:
+	case *refreshViewNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
 	case *renameIndexNode:
@@ -270,15 +282,22 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *createTypeNode:
+	case *createFunctionNode:
+	case *createSchemaNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropTypeNode:
+	case *dropFunctionNode:
+	case *dropSchemaNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
+	case *scanBufferNode:
 	case *hookFnNode:
 	case *sequenceSelectNode:
 	case *setVarNode:
@@ -67,6 +67,9 @@ func TestContextualHelp(t *testing.T) {
 		{`ALTER SEQUENCE blah RENAME ??`, `ALTER SEQUENCE`},
 		{`ALTER SEQUENCE blah RENAME TO blih ??`, `ALTER SEQUENCE`},
 
+		{`ALTER TYPE ??`, `ALTER TYPE`},
+		{`ALTER TYPE blah ADD VALUE ??`, `ALTER TYPE`},
+
 		{`ALTER USER IF ??`, `ALTER USER`},
 		{`ALTER USER foo WITH PASSWORD ??`, `ALTER USER`},
 
@@ -107,12 +110,22 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE ROLE bleh ??`, `CREATE ROLE`},
 
 		{`CREATE VIEW blah (??`, `CREATE VIEW`},
+		{`CREATE MATERIALIZED VIEW blah (??`, `CREATE VIEW`},
 		{`CREATE VIEW blah AS (SELECT c FROM x) ??`, `CREATE VIEW`},
 		{`CREATE VIEW blah AS SELECT c FROM x ??`, `SELECT`},
 		{`CREATE VIEW blah AS (??`, `<SELECTCLAUSE>`},
 
 		{`CREATE SEQUENCE ??`, `CREATE SEQUENCE`},
 
+		{`CREATE TYPE blah AS ENUM ('a') ??`, `CREATE TYPE`},
+
+		{`CREATE SCHEMA ??`, `CREATE SCHEMA`},
+		{`CREATE SCHEMA blah ??`, `CREATE SCHEMA`},
+
+		{`CREATE FUNCTION ??`, `CREATE FUNCTION`},
+		{`CREATE OR REPLACE FUNCTION blah(??`, `CREATE FUNCTION`},
+		{`CREATE FUNCTION blah() RETURNS INT ??`, `CREATE FUNCTION`},
+
 		{`CREATE STATISTICS ??`, `CREATE STATISTICS`},
 
 		{`CREATE TABLE blah (??`, `CREATE TABLE`},
@@ -130,6 +143,7 @@ func TestContextualHelp(t *testing.T) {
 
 		{`DISCARD ALL ??`, `DISCARD`},
 		{`DISCARD ??`, `DISCARD`},
+		{`DISCARD TEMP ??`, `DISCARD`},
 
 		{`DROP ??`, `DROP`},
 
@@ -147,6 +161,15 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP SEQUENCE IF ??`, `DROP SEQUENCE`},
 		{`DROP SEQUENCE IF EXISTS blih, bloh ??`, `DROP SEQUENCE`},
 
+		{`DROP TYPE ??`, `DROP TYPE`},
+		{`DROP TYPE blah ??`, `DROP TYPE`},
+
+		{`DROP SCHEMA ??`, `DROP SCHEMA`},
+		{`DROP SCHEMA blah ??`, `DROP SCHEMA`},
+
+		{`DROP FUNCTION ??`, `DROP FUNCTION`},
+		{`DROP FUNCTION blah(??`, `DROP FUNCTION`},
+
 		{`DROP TABLE blah ??`, `DROP TABLE`},
 		{`DROP TABLE IF ??`, `DROP TABLE`},
 		{`DROP TABLE IF EXISTS blih, bloh ??`, `DROP TABLE`},
@@ -167,6 +190,13 @@ func TestContextualHelp(t *testing.T) {
 		{`EXPLAIN UPDATE xx SET x = y ??`, `UPDATE`},
 		{`SELECT * FROM [EXPLAIN ??`, `EXPLAIN`},
 
+		{`LISTEN ??`, `LISTEN`},
+		{`LISTEN foo ??`, `LISTEN`},
+
+		{`NOTIFY ??`, `NOTIFY`},
+		{`NOTIFY foo ??`, `NOTIFY`},
+		{`NOTIFY foo, ??`, `NOTIFY`},
+
 		{`PREPARE foo ??`, `PREPARE`},
 		{`PREPARE foo (??`, `PREPARE`},
 		{`PREPARE foo AS SELECT 1 ??`, `SELECT`},
@@ -211,6 +241,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`RESUME ??`, `RESUME JOBS`},
 
+		{`REFRESH ??`, `REFRESH`},
+		{`REFRESH MATERIALIZED VIEW ??`, `REFRESH`},
+
 		{`REVOKE ALL ??`, `REVOKE`},
 		{`REVOKE ALL ON foo FROM ??`, `REVOKE`},
 		{`REVOKE ALL ON foo FROM bar ??`, `REVOKE`},
@@ -300,6 +333,9 @@ func TestContextualHelp(t *testing.T) {
 		{`TRUNCATE foo ??`, `TRUNCATE`},
 		{`TRUNCATE foo, ??`, `TRUNCATE`},
 
+		{`UNLISTEN ??`, `UNLISTEN`},
+		{`UNLISTEN foo ??`, `UNLISTEN`},
+
 		{`SELECT 1 ??`, `SELECT`},
 		{`SELECT * FROM ??`, `<SOURCE>`},
 		{`SELECT 1 FROM foo ??`, `SELECT`},
@@ -325,6 +361,8 @@ func TestContextualHelp(t *testing.T) {
 
 		{`SET TRANSACTION ??`, `SET TRANSACTION`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
 		{`SET TIME ??`, `SET SESSION`},
 		{`SET TIME ZONE 'UTC' ??`, `SET SESSION`},
 		{`SET blah TO ??`, `SET SESSION`},
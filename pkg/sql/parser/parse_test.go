@@ -89,6 +89,13 @@ func TestParse(t *testing.T) {
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d (e, f)`},
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d.e (f, g)`},
 		{`CREATE UNIQUE INDEX a ON b.c (d)`},
+		{`CREATE INDEX a ON b (c) WHERE d > 0`},
+		{`CREATE INDEX a ON b (c) STORING (d) WHERE e IS NOT NULL`},
+		{`CREATE UNIQUE INDEX a ON b (c) WHERE d = 'active'`},
+		{`CREATE INDEX IF NOT EXISTS a ON b (c) WHERE d`},
+		{`CREATE INDEX a ON b (c) USING HASH WITH BUCKET_COUNT = 8`},
+		{`CREATE INDEX a ON b (c, d DESC) USING HASH WITH BUCKET_COUNT = $1 STORING (e)`},
+		{`CREATE UNIQUE INDEX IF NOT EXISTS a ON b (c) USING HASH WITH BUCKET_COUNT = 16`},
 		{`CREATE INVERTED INDEX a ON b (c)`},
 		{`CREATE INVERTED INDEX a ON b.c (d)`},
 		{`CREATE INVERTED INDEX a ON b (c) STORING (d)`},
@@ -110,6 +117,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b TIME)`},
 		{`CREATE TABLE a (b UUID)`},
 		{`CREATE TABLE a (b INET)`},
+		{`CREATE TABLE a (b TSVECTOR)`},
+		{`CREATE TABLE a (b TSQUERY)`},
 		{`CREATE TABLE a (b "char")`},
 		{`CREATE TABLE a (b INT8 NULL)`},
 		{`CREATE TABLE a (b INT8 CONSTRAINT maybe NULL)`},
@@ -130,6 +139,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (a INT8 CONSTRAINT one CHECK (a > 0) CONSTRAINT two CHECK (a < 10))`},
 		// "0" lost quotes previously.
 		{`CREATE TABLE a (b INT8, c STRING, PRIMARY KEY (b, c, "0"))`},
+		{`CREATE TABLE a (b INT8, c STRING, PRIMARY KEY (b, c) USING HASH WITH BUCKET_COUNT = 8)`},
+		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d PRIMARY KEY (b) USING HASH WITH BUCKET_COUNT = 8)`},
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other)`},
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other ON UPDATE RESTRICT)`},
 		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other ON DELETE RESTRICT)`},
@@ -157,6 +168,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, UNIQUE (b))`},
 		{`CREATE TABLE a (b INT8, UNIQUE (b) STORING (c))`},
 		{`CREATE TABLE a (b INT8, INDEX (b))`},
+		{`CREATE TABLE a (b INT8, c INT8, INDEX (b) WHERE c > 0)`},
+		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE (b) WHERE c = 'x')`},
 		{`CREATE TABLE a (b INT8, INVERTED INDEX (b))`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON UPDATE RESTRICT)`},
@@ -179,7 +192,15 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON UPDATE SET DEFAULT)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo (bar))`},
+		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other (c) ON UPDATE CASCADE DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8, UNIQUE (b) DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c INT8, CONSTRAINT u UNIQUE (b, c) STORING (d) DEFERRABLE INITIALLY DEFERRED)`},
 		{`CREATE TABLE a (b INT8, INDEX (b) STORING (c))`},
+		{`CREATE TABLE a (b INT8, INDEX (b) USING HASH WITH BUCKET_COUNT = 4 STORING (c))`},
+		{`CREATE TABLE a (b INT8, CONSTRAINT c UNIQUE (b) USING HASH WITH BUCKET_COUNT = 4)`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX (b ASC, c DESC) STORING (c))`},
 		{`CREATE TABLE a (b INT8, INDEX (b) INTERLEAVE IN PARENT c (d, e))`},
 		{`CREATE TABLE a (b INT8, FAMILY (b))`},
@@ -189,6 +210,11 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a.b (b INT8)`},
 		{`CREATE TABLE IF NOT EXISTS a (b INT8)`},
 		{`CREATE TABLE a (b INT8 AS (a + b) STORED)`},
+		{`CREATE TABLE a (b INT8 AS (a + b) VIRTUAL)`},
+		{`CREATE TABLE a (LIKE b)`},
+		{`CREATE TABLE a (LIKE b INCLUDING ALL)`},
+		{`CREATE TABLE a (LIKE b INCLUDING ALL EXCLUDING INDEXES, c INT8)`},
+		{`CREATE TABLE a (LIKE b.c INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`},
 		{`CREATE TABLE view (view INT8)`},
 
 		{`CREATE TABLE a (b INT8 CONSTRAINT c PRIMARY KEY)`},
@@ -223,6 +249,8 @@ func TestParse(t *testing.T) {
 		{`ALTER INDEX a@idx PARTITION BY LIST (b) (PARTITION p1 VALUES IN (1))`},
 
 		{`CREATE TABLE a AS SELECT * FROM b`},
+		{`CREATE TEMPORARY TABLE a AS SELECT * FROM b`},
+		{`CREATE TEMPORARY TABLE IF NOT EXISTS a AS SELECT * FROM b`},
 		{`CREATE TABLE IF NOT EXISTS a AS SELECT * FROM b`},
 		{`CREATE TABLE a AS SELECT * FROM b ORDER BY c`},
 		{`CREATE TABLE IF NOT EXISTS a AS SELECT * FROM b ORDER BY c`},
@@ -230,6 +258,13 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE IF NOT EXISTS a AS SELECT * FROM b LIMIT 3`},
 		{`CREATE TABLE a AS VALUES ('one', 1), ('two', 2), ('three', 3)`},
 		{`CREATE TABLE IF NOT EXISTS a AS VALUES ('one', 1), ('two', 2), ('three', 3)`},
+		{`CREATE TABLE a (b INT8) WITH (ttl_expire_after = '30 days')`},
+		{`CREATE TABLE a (b INT8) WITH (ttl_expire_after = $1)`},
+		{`CREATE TEMPORARY TABLE IF NOT EXISTS a (b INT8) WITH (c = 'd', e)`},
+		{`CREATE TABLE a (b INT8) PARTITION BY LIST (b) (PARTITION c VALUES IN (1)) WITH (ttl_expire_after = '1 day')`},
+		{`CREATE TABLE a WITH (ttl_expire_after = '1 day') AS SELECT * FROM b`},
+		{`CREATE TABLE IF NOT EXISTS a (c) WITH (ttl_expire_after = '1 day') AS SELECT * FROM b`},
+
 		{`CREATE TABLE a (str, num) AS VALUES ('one', 1), ('two', 2), ('three', 3)`},
 		{`CREATE TABLE IF NOT EXISTS a (str, num) AS VALUES ('one', 1), ('two', 2), ('three', 3)`},
 		{`CREATE TABLE a AS SELECT * FROM b UNION SELECT * FROM c`},
@@ -249,6 +284,25 @@ func TestParse(t *testing.T) {
 		{`CREATE VIEW a AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a (x, y) AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a AS TABLE b`},
+		{`CREATE MATERIALIZED VIEW a AS SELECT * FROM b`},
+		{`CREATE MATERIALIZED VIEW a (x, y) AS SELECT c, d FROM b`},
+		{`EXPLAIN CREATE MATERIALIZED VIEW a AS SELECT * FROM b`},
+		{`REFRESH MATERIALIZED VIEW a`},
+		{`REFRESH MATERIALIZED VIEW a.b`},
+
+		{`CREATE TYPE a AS ENUM ()`},
+		{`CREATE TYPE a AS ENUM ('b')`},
+		{`CREATE TYPE a AS ENUM ('b', 'c')`},
+		{`EXPLAIN CREATE TYPE a AS ENUM ('b', 'c')`},
+
+		{`CREATE SCHEMA a`},
+		{`EXPLAIN CREATE SCHEMA a`},
+		{`CREATE SCHEMA IF NOT EXISTS a`},
+
+		{`CREATE FUNCTION a() RETURNS INT8 LANGUAGE SQL AS 'SELECT 1'`},
+		{`CREATE FUNCTION a(b INT8, STRING) RETURNS STRING LANGUAGE SQL AS 'SELECT $2 || b'`},
+		{`CREATE OR REPLACE FUNCTION a(b INT8) RETURNS SETOF INT8 LANGUAGE SQL AS 'SELECT c FROM d WHERE e = b'`},
+		{`EXPLAIN CREATE FUNCTION a() RETURNS INT8 LANGUAGE SQL AS 'SELECT 1'`},
 
 		{`CREATE SEQUENCE a`},
 		{`EXPLAIN CREATE SEQUENCE a`},
@@ -290,6 +344,15 @@ func TestParse(t *testing.T) {
 		{`DELETE FROM a WHERE a = b ORDER BY c LIMIT d RETURNING e`},
 
 		{`DISCARD ALL`},
+		{`DISCARD TEMP`},
+
+		{`LISTEN foo`},
+		{`LISTEN "Foo"`},
+		{`UNLISTEN foo`},
+		{`UNLISTEN *`},
+		{`NOTIFY foo`},
+		{`NOTIFY foo, 'bar'`},
+		{`NOTIFY foo, e'it\'s'`},
 
 		{`DROP DATABASE a`},
 		{`EXPLAIN DROP DATABASE a`},
@@ -333,6 +396,24 @@ func TestParse(t *testing.T) {
 		{`DROP SEQUENCE a RESTRICT`},
 		{`DROP SEQUENCE IF EXISTS a, b RESTRICT`},
 		{`DROP SEQUENCE a.b CASCADE`},
+		{`DROP TYPE a`},
+		{`EXPLAIN DROP TYPE a`},
+		{`DROP TYPE a, b`},
+		{`DROP TYPE IF EXISTS a`},
+		{`DROP TYPE IF EXISTS a, b RESTRICT`},
+		{`DROP TYPE a CASCADE`},
+
+		{`DROP SCHEMA a`},
+		{`EXPLAIN DROP SCHEMA a`},
+		{`DROP SCHEMA a, b`},
+		{`DROP SCHEMA IF EXISTS a, b RESTRICT`},
+		{`DROP SCHEMA a CASCADE`},
+		{`DROP FUNCTION a`},
+		{`DROP FUNCTION a()`},
+		{`DROP FUNCTION a(INT8, b STRING)`},
+		{`DROP FUNCTION IF EXISTS a`},
+		{`DROP FUNCTION IF EXISTS a(INT8)`},
+		{`EXPLAIN DROP FUNCTION a`},
 		{`DROP SEQUENCE a, b CASCADE`},
 
 		{`CANCEL JOBS SELECT a`},
@@ -442,6 +523,7 @@ func TestParse(t *testing.T) {
 		{`SHOW GRANTS ON TABLE foo, db.foo`},
 		{`SHOW GRANTS ON DATABASE foo, bar`},
 		{`SHOW GRANTS ON DATABASE foo FOR bar`},
+		{`SHOW GRANTS ON SCHEMA foo, bar`},
 		{`SHOW GRANTS FOR bar, baz`},
 
 		{`SHOW GRANTS ON ROLE`},
@@ -506,6 +588,7 @@ func TestParse(t *testing.T) {
 		{`GRANT SELECT, INSERT ON DATABASE bar TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO "test-user"`},
+		{`GRANT CREATE ON SCHEMA foo TO root`},
 		{`GRANT rolea, roleb TO usera, userb`},
 		{`GRANT rolea, roleb TO usera, userb WITH ADMIN OPTION`},
 
@@ -516,6 +599,7 @@ func TestParse(t *testing.T) {
 		{`REVOKE INSERT ON DATABASE foo FROM root`},
 		{`REVOKE ALL ON DATABASE foo FROM root, test`},
 		{`REVOKE SELECT, INSERT ON DATABASE bar FROM foo, bar, baz`},
+		{`REVOKE CREATE ON SCHEMA foo, bar FROM root`},
 		{`REVOKE SELECT, INSERT ON DATABASE db1, db2 FROM foo, bar, baz`},
 		{`REVOKE rolea, roleb FROM usera, userb`},
 		{`REVOKE ADMIN OPTION FOR rolea, roleb FROM usera, userb`},
@@ -563,6 +647,10 @@ func TestParse(t *testing.T) {
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING 1, 2`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING a + b`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING NOTHING`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_pkey DO NOTHING`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_pkey DO UPDATE SET a = 1 WHERE b > 2`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT (a) WHERE b > 3 DO NOTHING`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT (a, b) WHERE b > 3 DO UPDATE SET a = excluded.a`},
 
 		{`SELECT 1 + 1`},
 		{`SELECT -1`},
@@ -598,6 +686,9 @@ func TestParse(t *testing.T) {
 		{`SELECT 'Deutsch' COLLATE "DE"`},
 		{`SELECT a @> b`},
 		{`SELECT a <@ b`},
+		{`SELECT a && b`},
+		{`SELECT a @@ b`},
+		{`SELECT a @@ b || c`},
 		{`SELECT a ? b`},
 		{`SELECT a ?| b`},
 		{`SELECT a ?& b`},
@@ -653,6 +744,8 @@ func TestParse(t *testing.T) {
 		{`SELECT JSONB 'foo', 'foo'::JSONB`},
 		{`SELECT SERIAL8 'foo', 'foo'::SERIAL8`},
 
+		{`SELECT 'foo'::TSVECTOR, 'foo'::TSQUERY`},
+
 		{`SELECT 'foo'::DECIMAL(1)`},
 		{`SELECT 'foo'::DECIMAL(1,2)`},
 		{`SELECT 'foo'::BIT(3)`},
@@ -693,6 +786,11 @@ func TestParse(t *testing.T) {
 		{`SELECT "FROM" FROM t`},
 		{`SELECT CAST(1 AS STRING)`},
 		{`SELECT ANNOTATE_TYPE(1, STRING)`},
+		{`SELECT CAST(1 AS notatype)`},
+		{`SELECT ANNOTATE_TYPE(1, notatype)`},
+		{`SELECT 'f'::blah`},
+		{`SELECT 'f'::"user"`},
+		{`CREATE TABLE a (b mood)`},
 		{`SELECT a FROM t AS bar`},
 		{`SELECT a FROM t AS bar (bar1)`},
 		{`SELECT a FROM t AS bar (bar1, bar2, bar3)`},
@@ -704,6 +802,10 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM (SELECT 1 FROM t) AS bar (bar1, bar2, bar3)`},
 		{`SELECT a FROM (SELECT 1 FROM t) WITH ORDINALITY`},
 		{`SELECT a FROM (SELECT 1 FROM t) WITH ORDINALITY AS bar`},
+		{`SELECT * FROM ab, LATERAL (SELECT * FROM kv WHERE k = a)`},
+		{`SELECT * FROM ab, LATERAL (SELECT * FROM kv WHERE k = a) WITH ORDINALITY AS x`},
+		{`SELECT * FROM ab JOIN LATERAL (SELECT * FROM kv WHERE k = a) AS x ON true`},
+		{`SELECT * FROM ab LEFT JOIN LATERAL (SELECT * FROM kv WHERE k = a LIMIT 2) AS x ON x.v > b`},
 		{`SELECT a FROM ROWS FROM (a(x), b(y), c(z))`},
 		{`SELECT a FROM t1, t2`},
 		{`SELECT a FROM t AS t1`},
@@ -792,6 +894,16 @@ func TestParse(t *testing.T) {
 
 		{`SELECT 1 FROM t GROUP BY a`},
 		{`SELECT 1 FROM t GROUP BY a, b`},
+		{`SELECT percentile_disc(0.5) WITHIN GROUP (ORDER BY a) FROM t`},
+		{`SELECT percentile_cont(0.99) WITHIN GROUP (ORDER BY a + b) FILTER (WHERE c) FROM t GROUP BY d`},
+		{`SELECT mode() WITHIN GROUP (ORDER BY a) FROM t`},
+		{`SELECT a, b, sum(c) FROM t GROUP BY ROLLUP (a, b)`},
+		{`SELECT a, b, sum(c) FROM t GROUP BY CUBE (a, (b, c))`},
+		{`SELECT a, b, sum(c) FROM t GROUP BY GROUPING SETS ((a), (a, b), ())`},
+		{`SELECT a, b, sum(c) FROM t GROUP BY GROUPING SETS (ROLLUP (a), CUBE (b))`},
+		{`SELECT a, sum(c) FROM t GROUP BY a, ()`},
+		{`SELECT a, b, GROUPING(a, b) FROM t GROUP BY ROLLUP (a, b) HAVING GROUPING(a) = 0`},
+		{`SELECT rollup(a), cube(b) FROM t`},
 
 		{`SELECT a FROM t HAVING a = b`},
 
@@ -910,6 +1022,22 @@ func TestParse(t *testing.T) {
 		{`SELECT DISTINCT a, b FROM t`},
 		{`SELECT DISTINCT ON (a, b) c FROM t`},
 
+		{`SELECT * FROM t FOR UPDATE`},
+		{`SELECT * FROM t FOR NO KEY UPDATE`},
+		{`SELECT * FROM t FOR SHARE`},
+		{`SELECT * FROM t FOR KEY SHARE`},
+		{`SELECT * FROM t WHERE a = 1 ORDER BY b LIMIT 1 FOR UPDATE`},
+		{`SELECT * FROM t, u FOR UPDATE OF t`},
+		{`SELECT * FROM t, u FOR UPDATE OF t, u NOWAIT`},
+		{`SELECT * FROM t, u FOR SHARE OF t FOR UPDATE OF u SKIP LOCKED`},
+		{`WITH a AS (SELECT 1) SELECT * FROM t FOR UPDATE`},
+
+		{`WITH a AS (SELECT 1) SELECT * FROM a`},
+		{`WITH a (b) AS (SELECT 1) SELECT b FROM a`},
+		{`WITH RECURSIVE a AS (TABLE b) SELECT c`},
+		{`WITH RECURSIVE a (x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM a WHERE x < 10) SELECT * FROM a`},
+		{`WITH RECURSIVE a (x) AS (VALUES (1) UNION SELECT x + 1 FROM a) SELECT * FROM a LIMIT 5`},
+
 		{`SET a = 3`},
 		{`EXPLAIN SET a = 3`},
 		{`SET a = 3, 4`},
@@ -924,6 +1052,10 @@ func TestParse(t *testing.T) {
 		{`SET TRANSACTION PRIORITY NORMAL`},
 		{`SET TRANSACTION PRIORITY HIGH`},
 		{`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE, PRIORITY HIGH`},
+		{`SET CONSTRAINTS ALL DEFERRED`},
+		{`SET CONSTRAINTS ALL IMMEDIATE`},
+		{`SET CONSTRAINTS foo DEFERRED`},
+		{`SET CONSTRAINTS foo, bar IMMEDIATE`},
 
 		{`SET TRACING = off`},
 		{`EXPLAIN SET TRACING = off`},
@@ -1033,6 +1165,7 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT NULL`},
 		{`ALTER TABLE a ALTER COLUMN b DROP DEFAULT`},
 		{`ALTER TABLE a ALTER COLUMN b DROP NOT NULL`},
+		{`ALTER TABLE a ALTER COLUMN b SET NOT NULL`},
 		{`ALTER TABLE a ALTER COLUMN b DROP STORED`},
 
 		{`ALTER TABLE a ALTER COLUMN b SET DATA TYPE INT8`},
@@ -1041,6 +1174,12 @@ func TestParse(t *testing.T) {
 
 		{`COPY t FROM STDIN`},
 		{`COPY t (a, b, c) FROM STDIN`},
+		{`COPY t TO STDOUT`},
+		{`COPY t (a, b, c) TO STDOUT`},
+		{`COPY (SELECT a, b FROM t WHERE c > 1) TO STDOUT`},
+		{`COPY t TO STDOUT WITH (FORMAT csv, DELIMITER '|', NULL 'null', HEADER)`},
+		{`COPY t TO STDOUT WITH (FORMAT csv, HEADER false)`},
+		{`COPY (TABLE t) TO STDOUT WITH (FORMAT binary)`},
 
 		{`ALTER TABLE a SPLIT AT VALUES (1)`},
 		{`EXPLAIN ALTER TABLE a SPLIT AT VALUES (1)`},
@@ -1141,6 +1280,12 @@ func TestParse(t *testing.T) {
 		{`ALTER SEQUENCE IF EXISTS a INCREMENT BY 5 START WITH 1000`},
 		{`ALTER SEQUENCE IF EXISTS a NO CYCLE CACHE 1`},
 
+		{`ALTER TYPE a ADD VALUE 'b'`},
+		{`EXPLAIN ALTER TYPE a ADD VALUE 'b'`},
+		{`ALTER TYPE a ADD VALUE IF NOT EXISTS 'b'`},
+		{`ALTER TYPE a ADD VALUE 'b' BEFORE 'c'`},
+		{`ALTER TYPE a ADD VALUE IF NOT EXISTS 'b' AFTER 'c'`},
+
 		{`EXPERIMENTAL SCRUB DATABASE x`},
 		{`EXPLAIN EXPERIMENTAL SCRUB DATABASE x`},
 		{`EXPERIMENTAL SCRUB DATABASE x AS OF SYSTEM TIME 1`},
@@ -1238,6 +1383,16 @@ func TestParse2(t *testing.T) {
 		sql      string
 		expected string
 	}{
+		{`SELECT * FROM t FOR READ ONLY`,
+			`SELECT * FROM t`},
+		{`SELECT a FROM t GROUP BY rollup(a, b), cube(c)`,
+			`SELECT a FROM t GROUP BY ROLLUP (a, b), CUBE (c)`},
+		{`SELECT a FROM t GROUP BY grouping sets (a, (b))`,
+			`SELECT a FROM t GROUP BY GROUPING SETS (a, (b))`},
+		{`SELECT grouping(a) FROM t GROUP BY ROLLUP (a)`,
+			`SELECT GROUPING(a) FROM t GROUP BY ROLLUP (a)`},
+		{`SELECT percentile_cont(0.5) within group (order by a asc) FROM t`,
+			`SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY a) FROM t`},
 		{`CREATE DATABASE a WITH ENCODING = 'foo'`,
 			`CREATE DATABASE a ENCODING = 'foo'`},
 		{`CREATE DATABASE a TEMPLATE = template0`,
@@ -1265,6 +1420,8 @@ func TestParse2(t *testing.T) {
 			`CREATE TABLE a (b FLOAT8, c FLOAT4, d FLOAT8, e FLOAT4, f FLOAT8)`},
 		{`CREATE TABLE a (b NUMERIC, c NUMERIC(10), d DEC)`,
 			`CREATE TABLE a (b DECIMAL, c DECIMAL(10), d DECIMAL)`},
+		{`CREATE TABLE a (b INT8) WITHOUT OIDS`,
+			`CREATE TABLE a (b INT8)`},
 		{`CREATE TABLE a (b BOOLEAN)`,
 			`CREATE TABLE a (b BOOL)`},
 		{`CREATE TABLE a (b TEXT)`,
@@ -1287,6 +1444,9 @@ func TestParse2(t *testing.T) {
 		{`SELECT CAST(1 AS "timestamp")`, `SELECT CAST(1 AS TIMESTAMP)`},
 		{`SELECT CAST(1 AS _int8)`, `SELECT CAST(1 AS INT8[])`},
 		{`SELECT CAST(1 AS "_int8")`, `SELECT CAST(1 AS INT8[])`},
+		{`SELECT CAST(1.2+2.3 AS notatype)`, `SELECT CAST(1.2 + 2.3 AS notatype)`},
+		{`SELECT 'f'::"blah"`, `SELECT 'f'::blah`},
+		{`SELECT foo''`, `SELECT foo ''`},
 
 		{`SELECT 'a' FROM t@{FORCE_INDEX=bar}`, `SELECT 'a' FROM t@bar`},
 
@@ -1322,7 +1482,6 @@ func TestParse2(t *testing.T) {
 
 		{`SELECT b <<= c`, `SELECT inet_contained_by_or_equals(b, c)`},
 		{`SELECT b >>= c`, `SELECT inet_contains_or_equals(b, c)`},
-		{`SELECT b && c`, `SELECT inet_contains_or_contained_by(b, c)`},
 
 		{`SELECT NUMERIC 'foo'`, `SELECT DECIMAL 'foo'`},
 		{`SELECT REAL 'foo'`, `SELECT FLOAT4 'foo'`},
@@ -1551,6 +1710,10 @@ func TestParse2(t *testing.T) {
 			`SELECT a FROM ROWS FROM (generate_series(1, 32))`},
 		{`SELECT a FROM generate_series(1, 32) AS s (x)`,
 			`SELECT a FROM ROWS FROM (generate_series(1, 32)) AS s (x)`},
+		{`SELECT * FROM ab, LATERAL foo(a)`,
+			`SELECT * FROM ab, LATERAL ROWS FROM (foo(a))`},
+		{`SELECT * FROM ab, LATERAL foo(a) WITH ORDINALITY AS x`,
+			`SELECT * FROM ab, LATERAL ROWS FROM (foo(a)) WITH ORDINALITY AS x`},
 		{`SELECT a FROM generate_series(1, 32) WITH ORDINALITY AS s (x)`,
 			`SELECT a FROM ROWS FROM (generate_series(1, 32)) WITH ORDINALITY AS s (x)`},
 
@@ -1664,6 +1827,18 @@ func TestParse2(t *testing.T) {
 		{`ALTER USER foo WITH PASSWORD bar`,
 			`ALTER USER 'foo' WITH PASSWORD 'bar'`},
 
+		{`DISCARD TEMPORARY`, `DISCARD TEMP`},
+		{`NOTIFY foo, ''`, `NOTIFY foo`},
+
+		{`COPY t TO STDOUT CSV HEADER`, `COPY t TO STDOUT WITH (FORMAT csv, HEADER)`},
+		{`COPY t TO STDOUT WITH BINARY`, `COPY t TO STDOUT WITH (FORMAT binary)`},
+		{`COPY t TO STDOUT WITH DELIMITER AS ',' NULL ''`, `COPY t TO STDOUT WITH (DELIMITER ',', NULL '')`},
+		{`COPY t TO STDOUT (format 'CSV', header on)`, `COPY t TO STDOUT WITH (FORMAT csv, HEADER)`},
+		{`COPY t TO STDOUT (header 0, format text)`, `COPY t TO STDOUT WITH (FORMAT text, HEADER false)`},
+		{`CREATE TEMP TABLE a (b INT8)`, `CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE LOCAL TEMPORARY TABLE a (b INT8)`, `CREATE TEMPORARY TABLE a (b INT8)`},
+		{`CREATE GLOBAL TEMP TABLE IF NOT EXISTS a (b INT8)`, `CREATE TEMPORARY TABLE IF NOT EXISTS a (b INT8)`},
+
 		// Identifier handling for zone configs.
 
 		{`ALTER TABLE t CONFIGURE ZONE = NULL`,
@@ -1816,6 +1991,30 @@ func TestParse2(t *testing.T) {
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON UPDATE SET DEFAULT ON DELETE NO ACTION)`,
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON UPDATE SET DEFAULT)`,
 		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY DEFERRED)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, UNIQUE (b) INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8, UNIQUE (b) DEFERRABLE INITIALLY DEFERRED)`,
+		},
+		{
+			`CREATE TABLE a (b INT8, UNIQUE (b) INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, UNIQUE (b))`,
+		},
+		{
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other)`,
+		},
+		{
+			`CREATE FUNCTION a(b INT) RETURNS INT AS 'SELECT b + 1' LANGUAGE sql`,
+			`CREATE FUNCTION a(b INT8) RETURNS INT8 LANGUAGE SQL AS 'SELECT b + 1'`,
+		},
 		{
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON UPDATE NO ACTION ON DELETE SET DEFAULT)`,
 			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON DELETE SET DEFAULT)`,
@@ -1842,6 +2041,7 @@ func TestParse2(t *testing.T) {
 		{`ALTER TABLE a ADD b INT8 FAMILY fam_a`, `ALTER TABLE a ADD COLUMN b INT8 FAMILY fam_a`},
 		{`ALTER TABLE a DROP b`, `ALTER TABLE a DROP COLUMN b`},
 		{`ALTER TABLE a ALTER b DROP NOT NULL`, `ALTER TABLE a ALTER COLUMN b DROP NOT NULL`},
+		{`ALTER TABLE a ALTER b SET NOT NULL`, `ALTER TABLE a ALTER COLUMN b SET NOT NULL`},
 		{`ALTER TABLE a ALTER b TYPE INT8`, `ALTER TABLE a ALTER COLUMN b SET DATA TYPE INT8`},
 		{`EXPLAIN ANALYZE SELECT 1`, `EXPLAIN ANALYZE (DISTSQL) SELECT 1`},
 
@@ -1958,6 +2158,10 @@ SET TIME ZONE INTERVAL 'foobar'
 		{`SELECT INTERVAL 'foo'`, `could not parse "foo" as type interval: interval: missing unit at position 0: "foo" at or near "EOF"
 SELECT INTERVAL 'foo'
                      ^
+`},
+		{`SELECT percentile_disc(DISTINCT 0.5) WITHIN GROUP (ORDER BY a)`, `cannot use DISTINCT with WITHIN GROUP at or near "EOF"
+SELECT percentile_disc(DISTINCT 0.5) WITHIN GROUP (ORDER BY a)
+                                                              ^
 `},
 		{`SELECT 1 /* hello`, `unterminated comment
 SELECT 1 /* hello
@@ -2067,11 +2271,6 @@ HINT: try \h SELECT`},
 SELECT 1e-
        ^
 HINT: try \h SELECT`},
-		{"SELECT foo''",
-			`type does not exist at or near ""
-SELECT foo''
-          ^
-`},
 		{
 			`SELECT 0x FROM t`,
 			`invalid hexadecimal numeric literal
@@ -2113,6 +2312,20 @@ HINT: try \h <SOURCE>`,
 SELECT a FROM foo@{FORCE_INDEX=}
                                ^
 HINT: try \h <SOURCE>`,
+		},
+		{
+			`COPY t TO STDOUT WITH (FORMAT xml)`,
+			`COPY format "xml" not recognized at or near "xml"
+COPY t TO STDOUT WITH (FORMAT xml)
+                              ^
+`,
+		},
+		{
+			`COPY t TO STDOUT WITH CSV BINARY`,
+			`conflicting or redundant options at or near "binary"
+COPY t TO STDOUT WITH CSV BINARY
+                          ^
+`,
 		},
 		{
 			`SELECT a FROM foo@{FORCE_INDEX=bar,FORCE_INDEX=baz}`,
@@ -2148,20 +2361,6 @@ HINT: try \h INSERT`,
 ALTER TABLE t RENAME COLUMN x TO family
                                  ^
 HINT: try \h ALTER TABLE`,
-		},
-		{
-			`SELECT CAST(1.2+2.3 AS notatype)`,
-			`type does not exist at or near "notatype"
-SELECT CAST(1.2+2.3 AS notatype)
-                       ^
-`,
-		},
-		{
-			`SELECT ANNOTATE_TYPE(1.2+2.3, notatype)`,
-			`type does not exist at or near "notatype"
-SELECT ANNOTATE_TYPE(1.2+2.3, notatype)
-                              ^
-`,
 		},
 		{
 			`CREATE USER foo WITH PASSWORD`,
@@ -2224,13 +2423,6 @@ SELECT EXISTS(SELECT 1)[1]
 			`+ ANY <array> is invalid because "+" is not a boolean operator at or near "EOF"
 SELECT 1 + ANY ARRAY[1, 2, 3]
                              ^
-`,
-		},
-		{
-			`SELECT 'f'::"blah"`,
-			`type does not exist at or near "blah"
-SELECT 'f'::"blah"
-            ^
 `,
 		},
 		// Ensure that the support for ON ROLE <namelist> doesn't leak
@@ -2575,7 +2767,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		expected string
 	}{
 		{`ALTER TABLE a ALTER CONSTRAINT foo`, 31632, `alter constraint`},
-		{`ALTER TABLE a ALTER b SET NOT NULL`, 28751, ``},
 		{`ALTER TABLE a RENAME CONSTRAINT b TO c`, 32555, ``},
 
 		{`COMMENT ON COLUMN a.b IS 'a'`, 19472, `column`},
@@ -2589,14 +2780,11 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE EXTENSION a`, 0, `create extension a`},
 		{`CREATE FOREIGN DATA WRAPPER a`, 0, `create fdw`},
 		{`CREATE FOREIGN TABLE a`, 0, `create foreign table`},
-		{`CREATE FUNCTION a`, 17511, `create`},
-		{`CREATE OR REPLACE FUNCTION a`, 17511, `create`},
+		{`CREATE FUNCTION a() RETURNS INT8 LANGUAGE plpgsql AS 'b'`, 17511, `create function language plpgsql`},
 		{`CREATE LANGUAGE a`, 17511, `create language a`},
-		{`CREATE MATERIALIZED VIEW a`, 24747, ``},
 		{`CREATE OPERATOR a`, 0, `create operator`},
 		{`CREATE PUBLICATION a`, 0, `create publication`},
 		{`CREATE RULE a`, 0, `create rule`},
-		{`CREATE SCHEMA a`, 26443, `create`},
 		{`CREATE SERVER a`, 0, `create server`},
 		{`CREATE SUBSCRIPTION a`, 0, `create subscription`},
 		{`CREATE TEXT SEARCH a`, 7821, `create text`},
@@ -2610,52 +2798,35 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP EXTENSION a`, 0, `drop extension a`},
 		{`DROP FOREIGN TABLE a`, 0, `drop foreign table`},
 		{`DROP FOREIGN DATA WRAPPER a`, 0, `drop fdw`},
-		{`DROP FUNCTION a`, 17511, `drop `},
 		{`DROP LANGUAGE a`, 17511, `drop language a`},
 		{`DROP OPERATOR a`, 0, `drop operator`},
 		{`DROP PUBLICATION a`, 0, `drop publication`},
 		{`DROP RULE a`, 0, `drop rule`},
-		{`DROP SCHEMA a`, 26443, `drop`},
 		{`DROP SERVER a`, 0, `drop server`},
 		{`DROP SUBSCRIPTION a`, 0, `drop subscription`},
 		{`DROP TEXT SEARCH a`, 7821, `drop text`},
 		{`DROP TRIGGER a`, 28296, `drop`},
-		{`DROP TYPE a`, 27793, `drop type`},
 
 		{`DISCARD PLANS`, 0, `discard plans`},
 		{`DISCARD SEQUENCES`, 0, `discard sequences`},
-		{`DISCARD TEMP`, 0, `discard temp`},
-		{`DISCARD TEMPORARY`, 0, `discard temp`},
 
-		{`SET CONSTRAINTS foo`, 0, `set constraints`},
 		{`SET LOCAL foo = bar`, 32562, ``},
 		{`SET foo FROM CURRENT`, 0, `set from current`},
 
-		{`CREATE TEMP TABLE a(b INT8)`, 5807, ``},
 		{`CREATE UNLOGGED TABLE a(b INT8)`, 0, `create unlogged`},
 		{`CREATE TEMP VIEW a AS SELECT b`, 5807, ``},
 		{`CREATE TEMP SEQUENCE a`, 5807, ``},
 
-		{`CREATE TABLE a(LIKE b)`, 30840, ``},
 
 		{`CREATE TABLE a(b INT8) WITH OIDS`, 0, `create table with oids`},
 		{`CREATE TABLE a(b INT8) WITH foo = bar`, 0, `create table with foo`},
 
 		{`CREATE TABLE a AS SELECT b WITH NO DATA`, 0, `create table as with no data`},
 
-		{`CREATE TABLE a(b INT8 AS (123) VIRTUAL)`, 0, `virtual computed columns`},
 		{`CREATE TABLE a(b INT8 REFERENCES c(x) MATCH FULL`, 20305, `match full`},
 		{`CREATE TABLE a(b INT8 REFERENCES c(x) MATCH PARTIAL`, 20305, `match partial`},
 		{`CREATE TABLE a(b INT8 REFERENCES c(x) MATCH SIMPLE`, 20305, `match simple`},
 
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE)`, 31632, `deferrable`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY DEFERRED)`, 31632, `initially deferred`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY IMMEDIATE)`, 31632, `initially immediate`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY DEFERRED)`, 31632, `initially deferred`},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY IMMEDIATE)`, 31632, `initially immediate`},
-		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable`},
-		{`CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)`, 31632, `deferrable`},
-
 		{`CREATE SEQUENCE a AS DOUBLE PRECISION`, 25110, `FLOAT8`},
 		{`CREATE SEQUENCE a OWNED BY b`, 26382, ``},
 
@@ -2663,13 +2834,11 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE RECURSIVE VIEW a AS SELECT b`, 0, `create recursive view`},
 
 		{`CREATE TYPE a AS (b)`, 27792, ``},
-		{`CREATE TYPE a AS ENUM (b)`, 24873, ``},
 		{`CREATE TYPE a AS RANGE b`, 27791, ``},
 		{`CREATE TYPE a (b)`, 27793, `base`},
 		{`CREATE TYPE a`, 27793, `shell`},
 		{`CREATE DOMAIN a`, 27796, `create`},
 
-		{`CREATE INDEX a ON b(c) WHERE d > 0`, 9683, ``},
 		{`CREATE INDEX a ON b USING HASH (c)`, 0, `index using hash`},
 		{`CREATE INDEX a ON b USING GIST (c)`, 0, `index using gist`},
 		{`CREATE INDEX a ON b USING SPGIST (c)`, 0, `index using spgist`},
@@ -2680,13 +2849,9 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE INDEX a ON b(foo(c))`, 9682, ``},
 
 		{`INSERT INTO foo(a, a.b) VALUES (1,2)`, 27792, ``},
-		{`INSERT INTO foo VALUES (1,2) ON CONFLICT ON CONSTRAINT a DO NOTHING`, 28161, ``},
 
-		{`SELECT * FROM ab, LATERAL (SELECT * FROM kv)`, 24560, `select`},
-		{`SELECT * FROM ab, LATERAL foo(a)`, 24560, `srf`},
 		{`SELECT max(a ORDER BY b) FROM ab`, 23620, ``},
 
-		{`SELECT * FROM a FOR UPDATE`, 6583, ``},
 		{`SELECT * FROM ROWS FROM (a(b) AS (d))`, 0, `ROWS FROM with col_def_list`},
 
 		{`SELECT 123 AT TIME ZONE 'b'`, 32005, ``},
@@ -2713,14 +2878,14 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`SELECT a(b) 'c'`, 0, `a(...) SCONST`},
 		{`SELECT (a,b) OVERLAPS (c,d)`, 0, `overlaps`},
 		{`SELECT UNIQUE (SELECT b)`, 0, `UNIQUE predicate`},
-		{`SELECT GROUPING (a,b,c)`, 0, `d_expr grouping`},
 		{`SELECT a(VARIADIC b)`, 0, `variadic`},
 		{`SELECT a(b, c, VARIADIC b)`, 0, `variadic`},
 		{`SELECT COLLATION FOR (a)`, 32563, ``},
 		{`SELECT CURRENT_TIME`, 26097, `current_time`},
 		{`SELECT CURRENT_TIME()`, 26097, `current_time`},
 		{`SELECT TREAT (a AS INT8)`, 0, `treat`},
-		{`SELECT a(b) WITHIN GROUP (ORDER BY c)`, 0, `within group`},
+		{`SELECT a(b) WITHIN GROUP (ORDER BY c DESC)`, 0, `within group desc`},
+		{`SELECT a(b) WITHIN GROUP (ORDER BY c, d)`, 0, `within group with multiple expressions`},
 
 		{`CREATE TABLE a(b BOX)`, 21286, `box`},
 		{`CREATE TABLE a(b CIDR)`, 18846, `cidr`},
@@ -2734,15 +2899,11 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b PG_LSN)`, 0, `pg_lsn`},
 		{`CREATE TABLE a(b POINT)`, 21286, `point`},
 		{`CREATE TABLE a(b POLYGON)`, 21286, `polygon`},
-		{`CREATE TABLE a(b TSQUERY)`, 7821, `tsquery`},
-		{`CREATE TABLE a(b TSVECTOR)`, 7821, `tsvector`},
 		{`CREATE TABLE a(b TXID_SNAPSHOT)`, 0, `txid_snapshot`},
 		{`CREATE TABLE a(b XML)`, 0, `xml`},
 		{`CREATE TABLE a(b TIMETZ)`, 26097, `type`},
 
-		{`INSERT INTO a VALUES (1) ON CONFLICT (x) WHERE x > 3 DO NOTHING`, 32557, ``},
 
-		{`WITH RECURSIVE a AS (TABLE b) SELECT c`, 21085, ``},
 
 		{`UPDATE foo SET (a, a.b) = (1, 2)`, 27792, ``},
 		{`UPDATE foo SET a.b = 1`, 27792, ``},
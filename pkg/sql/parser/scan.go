@@ -479,6 +479,10 @@ func (s *scanner) scan(lval *sqlSymType) {
 			s.pos++
 			lval.id = CONTAINS
 			return
+		case '@': // @@
+			s.pos++
+			lval.id = TEXTSEARCH_MATCH
+			return
 		}
 		return
 
@@ -202,6 +202,15 @@ func (u *sqlSymUnion) tblDef() tree.TableDef {
 func (u *sqlSymUnion) tblDefs() tree.TableDefs {
     return u.val.(tree.TableDefs)
 }
+func (u *sqlSymUnion) likeTableOption() tree.LikeTableOption {
+    return u.val.(tree.LikeTableOption)
+}
+func (u *sqlSymUnion) likeTableOptionList() []tree.LikeTableOption {
+    return u.val.([]tree.LikeTableOption)
+}
+func (u *sqlSymUnion) likeTableOpt() tree.LikeTableOpt {
+    return u.val.(tree.LikeTableOpt)
+}
 func (u *sqlSymUnion) colQual() tree.NamedColumnQualification {
     return u.val.(tree.NamedColumnQualification)
 }
@@ -229,6 +238,12 @@ func (u *sqlSymUnion) castTargetType() coltypes.CastTargetType {
 func (u *sqlSymUnion) colTypes() []coltypes.T {
     return u.val.([]coltypes.T)
 }
+func (u *sqlSymUnion) funcArg() tree.FuncArg {
+    return u.val.(tree.FuncArg)
+}
+func (u *sqlSymUnion) funcArgs() tree.FuncArgs {
+    return u.val.(tree.FuncArgs)
+}
 func (u *sqlSymUnion) int64() int64 {
     return u.val.(int64)
 }
@@ -310,6 +325,18 @@ func (u *sqlSymUnion) onConflict() *tree.OnConflict {
 func (u *sqlSymUnion) orderBy() tree.OrderBy {
     return u.val.(tree.OrderBy)
 }
+func (u *sqlSymUnion) lockingClause() tree.LockingClause {
+    return u.val.(tree.LockingClause)
+}
+func (u *sqlSymUnion) lockingItem() *tree.LockingItem {
+    return u.val.(*tree.LockingItem)
+}
+func (u *sqlSymUnion) lockingStrength() tree.LockingStrength {
+    return u.val.(tree.LockingStrength)
+}
+func (u *sqlSymUnion) lockingWaitPolicy() tree.LockingWaitPolicy {
+    return u.val.(tree.LockingWaitPolicy)
+}
 func (u *sqlSymUnion) order() *tree.Order {
     return u.val.(*tree.Order)
 }
@@ -337,6 +364,9 @@ func (u *sqlSymUnion) dir() tree.Direction {
 func (u *sqlSymUnion) alterTableCmd() tree.AlterTableCmd {
     return u.val.(tree.AlterTableCmd)
 }
+func (u *sqlSymUnion) alterTypeAddValuePlacement() *tree.AlterTypeAddValuePlacement {
+    return u.val.(*tree.AlterTypeAddValuePlacement)
+}
 func (u *sqlSymUnion) alterTableCmds() tree.AlterTableCmds {
     return u.val.(tree.AlterTableCmds)
 }
@@ -373,6 +403,9 @@ func (u *sqlSymUnion) interleave() *tree.InterleaveDef {
 func (u *sqlSymUnion) partitionBy() *tree.PartitionBy {
     return u.val.(*tree.PartitionBy)
 }
+func (u *sqlSymUnion) shardedIndexDef() *tree.ShardedIndexDef {
+    return u.val.(*tree.ShardedIndexDef)
+}
 func (u *sqlSymUnion) listPartition() tree.ListPartition {
     return u.val.(tree.ListPartition)
 }
@@ -421,6 +454,9 @@ func (u *sqlSymUnion) kvOptions() []tree.KVOption {
 func (u *sqlSymUnion) transactionModes() tree.TransactionModes {
     return u.val.(tree.TransactionModes)
 }
+func (u *sqlSymUnion) copyOptions() tree.CopyOptions {
+    return u.val.(tree.CopyOptions)
+}
 func (u *sqlSymUnion) referenceAction() tree.ReferenceAction {
     return u.val.(tree.ReferenceAction)
 }
@@ -428,6 +464,10 @@ func (u *sqlSymUnion) referenceActions() tree.ReferenceActions {
     return u.val.(tree.ReferenceActions)
 }
 
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+    return u.val.(tree.ConstraintDeferrability)
+}
+
 func (u *sqlSymUnion) scrubOptions() tree.ScrubOptions {
     return u.val.(tree.ScrubOptions)
 }
@@ -454,7 +494,7 @@ func newNameFromStr(s string) *tree.Name {
 %token <str> PLACEHOLDER
 %token <str> TYPECAST TYPEANNOTATE DOT_DOT
 %token <str> LESS_EQUALS GREATER_EQUALS NOT_EQUALS
-%token <str> NOT_REGMATCH REGIMATCH NOT_REGIMATCH
+%token <str> NOT_REGMATCH REGIMATCH NOT_REGIMATCH TEXTSEARCH_MATCH
 %token <str> ERROR
 
 // If you want to make any keyword changes, add the new keyword here as well as
@@ -462,27 +502,27 @@ func newNameFromStr(s string) *tree.Name {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACTION ADD ADMIN AGGREGATE
+%token <str> ABORT ACTION ADD ADMIN AFTER AGGREGATE
 %token <str> ALL ALTER ANALYSE ANALYZE AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str> ASYMMETRIC AT
 
-%token <str> BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
-%token <str> BLOB BOOL BOOLEAN BOTH BY BYTEA BYTES
+%token <str> BACKUP BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
+%token <str> BLOB BOOL BOOLEAN BOTH BUCKET_COUNT BY BYTEA BYTES
 
 %token <str> CACHE CANCEL CASCADE CASE CAST CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK
 %token <str> CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMIT
 %token <str> COMMITTED COMPACT CONCAT CONFIGURATION CONFIGURATIONS CONFIGURE
 %token <str> CONFLICT CONSTRAINT CONSTRAINTS CONTAINS CONVERSION COPY COVERING CREATE
-%token <str> CROSS CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
+%token <str> CROSS CSV CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
 %token <str> CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
 %token <str> CURRENT_USER CYCLE
 
-%token <str> DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT
-%token <str> DEALLOCATE DEFERRABLE DEFERRED DELETE DESC
+%token <str> DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT DEFAULTS
+%token <str> DEALLOCATE DEFERRABLE DEFERRED DELETE DELIMITER DESC
 %token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> ELSE ENCODING END ENUM ESCAPE EXCEPT
+%token <str> ELSE ENCODING END ENUM ESCAPE EXCEPT EXCLUDING
 %token <str> EXISTS EXECUTE EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT
@@ -494,9 +534,9 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> GLOBAL GRANT GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HIGH HISTOGRAM HOUR
+%token <str> HASH HAVING HEADER HIGH HISTOGRAM HOUR
 
-%token <str> IMMEDIATE IMPORT INCREMENT INCREMENTAL IF IFERROR IFNULL ILIKE IN ISERROR
+%token <str> IMMEDIATE IMPORT INCLUDING INCREMENT INCREMENTAL IF IFERROR IFNULL ILIKE IN ISERROR
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS INET_CONTAINS_OR_CONTAINED_BY
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INJECT INTERLEAVE INITIALLY
 %token <str> INNER INSERT INT INT2VECTOR INT2 INT4 INT8 INT64 INTEGER
@@ -507,13 +547,13 @@ func newNameFromStr(s string) *tree.Name {
 %token <str> KEY KEYS KV
 
 %token <str> LANGUAGE LATERAL LC_CTYPE LC_COLLATE
-%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LIST LOCAL
-%token <str> LOCALTIME LOCALTIMESTAMP LOW LSHIFT
+%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT LIST LISTEN LOCAL
+%token <str> LOCALTIME LOCALTIMESTAMP LOCKED LOW LSHIFT
 
 %token <str> MATCH MATERIALIZED MINVALUE MAXVALUE MINUTE MONTH
 
 %token <str> NAN NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NORMAL
-%token <str> NOT NOTHING NOTNULL NULL NULLIF NUMERIC
+%token <str> NOT NOTHING NOTIFY NOTNULL NOWAIT NULL NULLIF NUMERIC
 
 %token <str> OF OFF OFFSET OID OIDS OIDVECTOR ON ONLY OPTION OPTIONS OR
 %token <str> ORDER ORDINALITY OUT OUTER OVER OVERLAPS OVERLAY OWNED OPERATOR
@@ -524,18 +564,18 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str> QUERIES QUERY
 
-%token <str> RANGE RANGES READ REAL RECURSIVE REF REFERENCES
+%token <str> RANGE RANGES READ REAL RECURSIVE REF REFERENCES REFRESH
 %token <str> REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE
 %token <str> REMOVE_PATH RENAME REPEATABLE REPLACE
-%token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT
+%token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETURNS REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT RULE
 
 %token <str> SAVEPOINT SCATTER SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
 %token <str> SERIAL SERIAL2 SERIAL4 SERIAL8
-%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETTING SETTINGS
-%token <str> SHOW SIMILAR SIMPLE SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
+%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETOF SETS SETTING SETTINGS
+%token <str> SHARE SHOW SIMILAR SIMPLE SKIP SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STRICT STRING STORE STORED STORING SUBSTRING
+%token <str> START STATISTICS STATUS STDIN STDOUT STRICT STRING STORE STORED STORING SUBSTRING
 %token <str> SYMMETRIC SYNTAX SYSTEM SUBSCRIPTION
 
 %token <str> TABLE TABLES TEMP TEMPLATE TEMPORARY TESTING_RANGES EXPERIMENTAL_RANGES TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
@@ -543,7 +583,7 @@ func newNameFromStr(s string) *tree.Name {
 %token <str> TRUNCATE TRUSTED TYPE
 %token <str> TRACING
 
-%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLOGGED
+%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLISTEN UNLOGGED
 %token <str> UPDATE UPSERT USE USER USERS USING UUID
 
 %token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIRTUAL
@@ -581,6 +621,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> alter_index_stmt
 %type <tree.Statement> alter_view_stmt
 %type <tree.Statement> alter_sequence_stmt
+%type <tree.Statement> alter_type_stmt
 %type <tree.Statement> alter_database_stmt
 %type <tree.Statement> alter_user_stmt
 %type <tree.Statement> alter_range_stmt
@@ -629,6 +670,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> cancel_sessions_stmt
 
 // SCRUB
+%type <tree.Statement> refresh_stmt
 %type <tree.Statement> scrub_stmt
 %type <tree.Statement> scrub_database_stmt
 %type <tree.Statement> scrub_table_stmt
@@ -639,13 +681,16 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> comment_stmt
 %type <tree.Statement> commit_stmt
 %type <tree.Statement> copy_from_stmt
+%type <tree.Statement> copy_to_stmt
 
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_changefeed_stmt
 %type <tree.Statement> create_ddl_stmt
 %type <tree.Statement> create_database_stmt
+%type <tree.Statement> create_function_stmt
 %type <tree.Statement> create_index_stmt
 %type <tree.Statement> create_role_stmt
+%type <tree.Statement> create_schema_stmt
 %type <tree.Statement> create_table_stmt
 %type <tree.Statement> create_table_as_stmt
 %type <tree.Statement> create_user_stmt
@@ -659,12 +704,15 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> drop_stmt
 %type <tree.Statement> drop_ddl_stmt
 %type <tree.Statement> drop_database_stmt
+%type <tree.Statement> drop_function_stmt
 %type <tree.Statement> drop_index_stmt
 %type <tree.Statement> drop_role_stmt
+%type <tree.Statement> drop_schema_stmt
 %type <tree.Statement> drop_table_stmt
 %type <tree.Statement> drop_user_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
+%type <tree.Statement> drop_type_stmt
 
 %type <tree.Statement> explain_stmt
 %type <tree.Statement> prepare_stmt
@@ -675,6 +723,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> grant_stmt
 %type <tree.Statement> insert_stmt
 %type <tree.Statement> import_stmt
+%type <tree.Statement> listen_stmt
+%type <tree.Statement> notify_stmt
 %type <tree.Statement> pause_stmt
 %type <tree.Statement> release_stmt
 %type <tree.Statement> reset_stmt reset_session_stmt reset_csetting_stmt
@@ -690,6 +740,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -726,13 +777,19 @@ func newNameFromStr(s string) *tree.Name {
 
 %type <tree.Statement> transaction_stmt
 %type <tree.Statement> truncate_stmt
+%type <tree.Statement> unlisten_stmt
 %type <tree.Statement> update_stmt
 %type <tree.Statement> upsert_stmt
 %type <tree.Statement> use_stmt
 
 %type <[]string> opt_incremental
+%type <[]string> opt_enum_val_list enum_val_list
+%type <[]string> func_language_and_body
+%type <tree.FuncArgs> opt_func_arg_list func_arg_list
+%type <tree.FuncArg> func_arg
+%type <*tree.AlterTypeAddValuePlacement> opt_add_val_placement
 %type <tree.KVOption> kv_option
-%type <[]tree.KVOption> kv_option_list opt_with_options var_set_list
+%type <[]tree.KVOption> kv_option_list opt_with_options var_set_list opt_table_with
 %type <str> import_format
 
 %type <*tree.Select> select_no_parens
@@ -795,6 +852,11 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.DistinctOn> distinct_on_clause
 %type <tree.NameList> opt_column_list insert_column_list
 %type <tree.OrderBy> sort_clause opt_sort_clause
+%type <tree.LockingClause> opt_for_locking_clause for_locking_clause for_locking_items
+%type <*tree.LockingItem> for_locking_item
+%type <tree.LockingStrength> for_locking_strength
+%type <tree.LockingWaitPolicy> opt_nowait_or_skip
+%type <tree.TableNames> opt_locked_rels
 %type <[]*tree.Order> sortby_list
 %type <tree.IndexElemList> index_params
 %type <tree.NameList> name_list privilege_list
@@ -811,6 +873,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <*tree.UpdateExpr> set_clause multiple_set_clause
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
+%type <tree.Exprs> group_by_list
+%type <tree.Expr> group_by_item rollup_clause cube_clause grouping_sets_clause
 %type <*tree.Limit> select_limit
 %type <tree.TableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
@@ -833,8 +897,11 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.DurationField> opt_interval interval_second interval_qualifier
 %type <tree.Expr> overlay_placing
 
+%type <bool> opt_temp
+%type <bool> opt_or_replace opt_setof
 %type <bool> opt_unique
 %type <bool> opt_using_gin_btree
+%type <*tree.ShardedIndexDef> opt_hash_sharded
 
 %type <*tree.Limit> limit_clause offset_clause opt_limit_clause
 %type <tree.Expr> select_limit_value
@@ -843,15 +910,21 @@ func newNameFromStr(s string) *tree.Name {
 %type <empty> first_or_next
 
 %type <tree.Statement> insert_rest
-%type <tree.NameList> opt_conf_expr opt_col_def_list
-%type <*tree.OnConflict> on_conflict
+%type <tree.NameList> opt_col_def_list
+%type <*tree.OnConflict> on_conflict opt_conf_expr
 
 %type <tree.Statement> begin_transaction
 %type <tree.TransactionModes> transaction_mode_list transaction_mode
+%type <tree.CopyOptions> opt_copy_options copy_generic_option_list copy_generic_option
+%type <tree.CopyOptions> copy_legacy_option_list copy_legacy_option
+%type <*string> copy_generic_option_arg
 
 %type <tree.NameList> opt_storing
 %type <*tree.ColumnTableDef> column_def
 %type <tree.TableDef> table_elem
+%type <tree.LikeTableOption> like_table_option
+%type <[]tree.LikeTableOption> like_table_option_list
+%type <tree.LikeTableOpt> like_table_opt
 %type <tree.Expr> where_clause opt_where_clause
 %type <*tree.ArraySubscript> array_subscript
 %type <tree.Expr> opt_slice_bound
@@ -927,6 +1000,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.ColumnQualification> col_qualification_elem
 %type <empty> key_match
 %type <tree.ReferenceActions> reference_actions
+%type <tree.ConstraintDeferrability> opt_deferrable
+%type <bool> constraints_set_mode
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
 %type <tree.Expr> func_application func_expr_common_subexpr special_function
@@ -936,7 +1011,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <[]*tree.CTE> cte_list
 %type <*tree.CTE> common_table_expr
 
-%type <empty> within_group_clause
+%type <tree.OrderBy> within_group_clause
 %type <tree.Expr> filter_clause
 %type <tree.Exprs> opt_partition_clause
 %type <tree.Window> window_clause window_definition_list
@@ -999,7 +1074,7 @@ func newNameFromStr(s string) *tree.Name {
 // funny behavior of UNBOUNDED on the SQL standard, though.
 %nonassoc  UNBOUNDED         // ideally should have same precedence as IDENT
 %nonassoc  IDENT NULL PARTITION RANGE ROWS GROUPS PRECEDING FOLLOWING CUBE ROLLUP
-%left      CONCAT FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH REMOVE_PATH  // multi-character ops
+%left      CONCAT FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH REMOVE_PATH TEXTSEARCH_MATCH  // multi-character ops
 %left      '|'
 %left      '#'
 %left      '&'
@@ -1055,18 +1130,22 @@ stmt:
   HELPTOKEN { return helpWith(sqllex, "") }
 | preparable_stmt  // help texts in sub-rule
 | copy_from_stmt
+| copy_to_stmt
 | comment_stmt
 | execute_stmt      // EXTEND WITH HELP: EXECUTE
 | deallocate_stmt   // EXTEND WITH HELP: DEALLOCATE
 | discard_stmt      // EXTEND WITH HELP: DISCARD
 | export_stmt       // EXTEND WITH HELP: EXPORT
 | grant_stmt        // EXTEND WITH HELP: GRANT
+| listen_stmt       // EXTEND WITH HELP: LISTEN
+| notify_stmt       // EXTEND WITH HELP: NOTIFY
 | prepare_stmt      // EXTEND WITH HELP: PREPARE
 | revoke_stmt       // EXTEND WITH HELP: REVOKE
 | savepoint_stmt    // EXTEND WITH HELP: SAVEPOINT
 | release_stmt      // EXTEND WITH HELP: RELEASE
 | nonpreparable_set_stmt // help texts in sub-rule
 | transaction_stmt  // help texts in sub-rule
+| unlisten_stmt     // EXTEND WITH HELP: UNLISTEN
 | /* EMPTY */
   {
     $$.val = tree.Statement(nil)
@@ -1074,7 +1153,8 @@ stmt:
 
 // %Help: ALTER
 // %Category: Group
-// %Text: ALTER TABLE, ALTER INDEX, ALTER VIEW, ALTER SEQUENCE, ALTER DATABASE, ALTER USER
+// %Text: ALTER TABLE, ALTER INDEX, ALTER VIEW, ALTER SEQUENCE, ALTER DATABASE, ALTER USER,
+// ALTER TYPE
 alter_stmt:
   alter_ddl_stmt      // help texts in sub-rule
 | alter_user_stmt     // EXTEND WITH HELP: ALTER USER
@@ -1087,6 +1167,7 @@ alter_ddl_stmt:
 | alter_sequence_stmt // EXTEND WITH HELP: ALTER SEQUENCE
 | alter_database_stmt // EXTEND WITH HELP: ALTER DATABASE
 | alter_range_stmt    // EXTEND WITH HELP: ALTER RANGE
+| alter_type_stmt     // EXTEND WITH HELP: ALTER TYPE
 
 // %Help: ALTER TABLE - change the definition of a table
 // %Category: DDL
@@ -1224,6 +1305,54 @@ alter_range_stmt:
   alter_zone_range_stmt
 | ALTER RANGE error // SHOW HELP: ALTER RANGE
 
+// %Help: ALTER TYPE - change the definition of a type
+// %Category: DDL
+// %Text:
+// ALTER TYPE <typename> <command>
+//
+// Commands:
+//   ALTER TYPE ... ADD VALUE [IF NOT EXISTS] <value> [ { BEFORE | AFTER } <existing_value> ]
+//
+// %SeeAlso: CREATE TYPE, DROP TYPE
+alter_type_stmt:
+  ALTER TYPE name ADD VALUE SCONST opt_add_val_placement
+  {
+    $$.val = &tree.AlterType{
+      Name: tree.Name($3),
+      Cmd: &tree.AlterTypeAddValue{
+        NewVal: $6,
+        IfNotExists: false,
+        Placement: $7.alterTypeAddValuePlacement(),
+      },
+    }
+  }
+| ALTER TYPE name ADD VALUE IF NOT EXISTS SCONST opt_add_val_placement
+  {
+    $$.val = &tree.AlterType{
+      Name: tree.Name($3),
+      Cmd: &tree.AlterTypeAddValue{
+        NewVal: $9,
+        IfNotExists: true,
+        Placement: $10.alterTypeAddValuePlacement(),
+      },
+    }
+  }
+| ALTER TYPE error // SHOW HELP: ALTER TYPE
+
+opt_add_val_placement:
+  BEFORE SCONST
+  {
+    $$.val = &tree.AlterTypeAddValuePlacement{Before: true, ExistingVal: $2}
+  }
+| AFTER SCONST
+  {
+    $$.val = &tree.AlterTypeAddValuePlacement{Before: false, ExistingVal: $2}
+  }
+| /* EMPTY */
+  {
+    $$.val = (*tree.AlterTypeAddValuePlacement)(nil)
+  }
+
 // %Help: ALTER INDEX - change the definition of an index
 // %Category: DDL
 // %Text:
@@ -1498,7 +1627,10 @@ alter_table_cmd:
     $$.val = &tree.AlterTableDropStored{Column: tree.Name($3)}
   }
   // ALTER TABLE <name> ALTER [COLUMN] <colname> SET NOT NULL
-| ALTER opt_column column_name SET NOT NULL { return unimplementedWithIssue(sqllex, 28751) }
+| ALTER opt_column column_name SET NOT NULL
+  {
+    $$.val = &tree.AlterTableSetNotNull{Column: tree.Name($3)}
+  }
   // ALTER TABLE <name> DROP [COLUMN] IF EXISTS <colname> [RESTRICT|CASCADE]
 | DROP opt_column IF EXISTS column_name opt_drop_behavior
   {
@@ -1902,6 +2034,128 @@ copy_from_stmt:
     }
   }
 
+copy_to_stmt:
+  COPY table_name opt_column_list TO STDOUT opt_copy_options
+  {
+    name, err := tree.NormalizeTableName($2.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = &tree.CopyTo{
+       Table: name,
+       Columns: $3.nameList(),
+       Options: $6.copyOptions(),
+    }
+  }
+| COPY '(' select_stmt ')' TO STDOUT opt_copy_options
+  {
+    $$.val = &tree.CopyTo{Query: $3.slct(), Options: $7.copyOptions()}
+  }
+
+// The parenthesized option list is the syntax introduced in PostgreSQL 9.0.
+// The older syntax, which lists the options without parentheses, is also
+// accepted since it is still used by many clients.
+opt_copy_options:
+  opt_with '(' copy_generic_option_list ')'
+  {
+    $$.val = $3.copyOptions()
+  }
+| opt_with copy_legacy_option_list
+  {
+    $$.val = $2.copyOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.CopyOptions{}
+  }
+
+copy_generic_option_list:
+  copy_generic_option
+  {
+    $$.val = $1.copyOptions()
+  }
+| copy_generic_option_list ',' copy_generic_option
+  {
+    a := $1.copyOptions()
+    b := $3.copyOptions()
+    err := a.Merge(b)
+    if err != nil { sqllex.Error(err.Error()); return 1 }
+    $$.val = a
+  }
+
+copy_generic_option:
+  unrestricted_name copy_generic_option_arg
+  {
+    opt, err := tree.MakeCopyOption($1, $2.strPtr())
+    if err != nil { sqllex.Error(err.Error()); return 1 }
+    $$.val = opt
+  }
+
+copy_generic_option_arg:
+  unrestricted_name
+  {
+    s := $1
+    $$.val = &s
+  }
+| SCONST
+  {
+    s := $1
+    $$.val = &s
+  }
+| ICONST
+  {
+    s := $1.numVal().OrigString
+    $$.val = &s
+  }
+| /* EMPTY */
+  {
+    $$.val = (*string)(nil)
+  }
+
+copy_legacy_option_list:
+  copy_legacy_option
+  {
+    $$.val = $1.copyOptions()
+  }
+| copy_legacy_option_list copy_legacy_option
+  {
+    a := $1.copyOptions()
+    b := $2.copyOptions()
+    err := a.Merge(b)
+    if err != nil { sqllex.Error(err.Error()); return 1 }
+    $$.val = a
+  }
+
+copy_legacy_option:
+  BINARY
+  {
+    $$.val = tree.CopyOptions{FileFormat: tree.CopyFormatBinary}
+  }
+| CSV
+  {
+    $$.val = tree.CopyOptions{FileFormat: tree.CopyFormatCSV}
+  }
+| HEADER
+  {
+    header := true
+    $$.val = tree.CopyOptions{Header: &header}
+  }
+| DELIMITER opt_as SCONST
+  {
+    s := $3
+    $$.val = tree.CopyOptions{Delimiter: &s}
+  }
+| NULL opt_as SCONST
+  {
+    s := $3
+    $$.val = tree.CopyOptions{Null: &s}
+  }
+
+opt_as:
+  AS {}
+| /* EMPTY */ {}
+
 // %Help: CANCEL
 // %Category: Group
 // %Text: CANCEL JOBS, CANCEL QUERIES, CANCEL SESSIONS
@@ -2041,7 +2295,7 @@ comment_text:
 // %Text:
 // CREATE DATABASE, CREATE TABLE, CREATE INDEX, CREATE TABLE AS,
 // CREATE USER, CREATE VIEW, CREATE SEQUENCE, CREATE STATISTICS,
-// CREATE ROLE
+// CREATE ROLE, CREATE TYPE, CREATE FUNCTION, CREATE SCHEMA
 create_stmt:
   create_user_stmt     // EXTEND WITH HELP: CREATE USER
 | create_role_stmt     // EXTEND WITH HELP: CREATE ROLE
@@ -2060,22 +2314,18 @@ create_unsupported:
 | CREATE EXTENSION name error { return unimplemented(sqllex, "create extension " + $3) }
 | CREATE FOREIGN TABLE error { return unimplemented(sqllex, "create foreign table") }
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
-| CREATE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
-| CREATE OR REPLACE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
-| CREATE MATERIALIZED VIEW error { return unimplementedWithIssue(sqllex, 24747) }
 | CREATE OPERATOR error { return unimplemented(sqllex, "create operator") }
 | CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
 | CREATE opt_or_replace RULE error { return unimplemented(sqllex, "create rule") }
-| CREATE SCHEMA error { return unimplementedWithIssueDetail(sqllex, 26443, "create") }
 | CREATE SERVER error { return unimplemented(sqllex, "create server") }
 | CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
 | CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create") }
 
 opt_or_replace:
-  OR REPLACE {}
-| /* EMPTY */ {}
+  OR REPLACE { $$.val = true }
+| /* EMPTY */ { $$.val = false }
 
 opt_trusted:
   TRUSTED {}
@@ -2095,16 +2345,13 @@ drop_unsupported:
 | DROP EXTENSION name error { return unimplemented(sqllex, "drop extension " + $3) }
 | DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
-| DROP FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "drop function") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
 | DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
 | DROP RULE error { return unimplemented(sqllex, "drop rule") }
-| DROP SCHEMA error { return unimplementedWithIssueDetail(sqllex, 26443, "drop") }
 | DROP SERVER error { return unimplemented(sqllex, "drop server") }
 | DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
-| DROP TYPE error { return unimplementedWithIssueDetail(sqllex, 27793, "drop type") }
 | DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
 create_ddl_stmt:
@@ -2115,7 +2362,9 @@ create_ddl_stmt:
 | create_table_as_stmt // EXTEND WITH HELP: CREATE TABLE
 // Error case for both CREATE TABLE and CREATE TABLE ... AS in one
 | CREATE opt_temp TABLE error   // SHOW HELP: CREATE TABLE
-| create_type_stmt     { /* SKIP DOC */ }
+| create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
+| create_function_stmt // EXTEND WITH HELP: CREATE FUNCTION
+| create_schema_stmt   // EXTEND WITH HELP: CREATE SCHEMA
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
 
@@ -2207,7 +2456,7 @@ delete_stmt:
 
 // %Help: DISCARD - reset the session to its initial state
 // %Category: Cfg
-// %Text: DISCARD ALL
+// %Text: DISCARD { ALL | TEMP }
 discard_stmt:
   DISCARD ALL
   {
@@ -2215,15 +2464,62 @@ discard_stmt:
   }
 | DISCARD PLANS { return unimplemented(sqllex, "discard plans") }
 | DISCARD SEQUENCES { return unimplemented(sqllex, "discard sequences") }
-| DISCARD TEMP { return unimplemented(sqllex, "discard temp") }
-| DISCARD TEMPORARY { return unimplemented(sqllex, "discard temp") }
+| DISCARD TEMP
+  {
+    $$.val = &tree.Discard{Mode: tree.DiscardModeTemp}
+  }
+| DISCARD TEMPORARY
+  {
+    $$.val = &tree.Discard{Mode: tree.DiscardModeTemp}
+  }
 | DISCARD error // SHOW HELP: DISCARD
 
+// %Help: LISTEN - listen for notifications on a channel
+// %Category: Misc
+// %Text: LISTEN <channel>
+// %SeeAlso: NOTIFY, UNLISTEN
+listen_stmt:
+  LISTEN name
+  {
+    $$.val = &tree.Listen{Channel: tree.Name($2)}
+  }
+| LISTEN error // SHOW HELP: LISTEN
+
+// %Help: UNLISTEN - stop listening for notifications
+// %Category: Misc
+// %Text: UNLISTEN { <channel> | * }
+// %SeeAlso: LISTEN, NOTIFY
+unlisten_stmt:
+  UNLISTEN name
+  {
+    $$.val = &tree.Unlisten{Channel: tree.Name($2)}
+  }
+| UNLISTEN '*'
+  {
+    $$.val = &tree.Unlisten{}
+  }
+| UNLISTEN error // SHOW HELP: UNLISTEN
+
+// %Help: NOTIFY - send a notification to the sessions listening on a channel
+// %Category: Misc
+// %Text: NOTIFY <channel> [, <payload>]
+// %SeeAlso: LISTEN, UNLISTEN
+notify_stmt:
+  NOTIFY name
+  {
+    $$.val = &tree.Notify{Channel: tree.Name($2)}
+  }
+| NOTIFY name ',' SCONST
+  {
+    $$.val = &tree.Notify{Channel: tree.Name($2), Payload: $4}
+  }
+| NOTIFY error // SHOW HELP: NOTIFY
+
 // %Help: DROP
 // %Category: Group
 // %Text:
 // DROP DATABASE, DROP INDEX, DROP TABLE, DROP VIEW, DROP SEQUENCE,
-// DROP USER, DROP ROLE
+// DROP USER, DROP ROLE, DROP TYPE, DROP FUNCTION, DROP SCHEMA
 drop_stmt:
   drop_ddl_stmt      // help texts in sub-rule
 | drop_role_stmt     // EXTEND WITH HELP: DROP ROLE
@@ -2237,6 +2533,9 @@ drop_ddl_stmt:
 | drop_table_stmt    // EXTEND WITH HELP: DROP TABLE
 | drop_view_stmt     // EXTEND WITH HELP: DROP VIEW
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
+| drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
+| drop_function_stmt // EXTEND WITH HELP: DROP FUNCTION
+| drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
@@ -2268,6 +2567,59 @@ drop_sequence_stmt:
   }
 | DROP SEQUENCE error // SHOW HELP: DROP VIEW
 
+// %Help: DROP TYPE - remove a type
+// %Category: DDL
+// %Text: DROP TYPE [IF EXISTS] <typename> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE TYPE, ALTER TYPE
+drop_type_stmt:
+  DROP TYPE name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropType{Names: $3.nameList(), IfExists: false, DropBehavior: $4.dropBehavior()}
+  }
+| DROP TYPE IF EXISTS name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropType{Names: $5.nameList(), IfExists: true, DropBehavior: $6.dropBehavior()}
+  }
+| DROP TYPE error // SHOW HELP: DROP TYPE
+
+// %Help: DROP SCHEMA - remove a schema
+// %Category: DDL
+// %Text: DROP SCHEMA [IF EXISTS] <schemaname> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE SCHEMA, SHOW SCHEMAS
+drop_schema_stmt:
+  DROP SCHEMA name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropSchema{Names: $3.nameList(), IfExists: false, DropBehavior: $4.dropBehavior()}
+  }
+| DROP SCHEMA IF EXISTS name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropSchema{Names: $5.nameList(), IfExists: true, DropBehavior: $6.dropBehavior()}
+  }
+| DROP SCHEMA error // SHOW HELP: DROP SCHEMA
+
+// %Help: DROP FUNCTION - remove a function
+// %Category: DDL
+// %Text: DROP FUNCTION [IF EXISTS] <name> [( [[<argname>] <argtype> [, ...]] )]
+// %SeeAlso: CREATE FUNCTION
+drop_function_stmt:
+  DROP FUNCTION name
+  {
+    $$.val = &tree.DropFunction{Name: tree.Name($3)}
+  }
+| DROP FUNCTION name '(' opt_func_arg_list ')'
+  {
+    $$.val = &tree.DropFunction{Name: tree.Name($3), Args: $5.funcArgs(), HasArgs: true}
+  }
+| DROP FUNCTION IF EXISTS name
+  {
+    $$.val = &tree.DropFunction{Name: tree.Name($5), IfExists: true}
+  }
+| DROP FUNCTION IF EXISTS name '(' opt_func_arg_list ')'
+  {
+    $$.val = &tree.DropFunction{Name: tree.Name($5), Args: $7.funcArgs(), HasArgs: true, IfExists: true}
+  }
+| DROP FUNCTION error // SHOW HELP: DROP FUNCTION
+
 // %Help: DROP TABLE - remove a table
 // %Category: DDL
 // %Text: DROP TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
@@ -2430,6 +2782,7 @@ preparable_stmt:
 | import_stmt       // EXTEND WITH HELP: IMPORT
 | insert_stmt       // EXTEND WITH HELP: INSERT
 | pause_stmt        // EXTEND WITH HELP: PAUSE JOBS
+| refresh_stmt      // EXTEND WITH HELP: REFRESH
 | reset_stmt        // help texts in sub-rule
 | restore_stmt      // EXTEND WITH HELP: RESTORE
 | resume_stmt       // EXTEND WITH HELP: RESUME JOBS
@@ -2539,6 +2892,7 @@ deallocate_stmt:
 //
 // Targets:
 //   DATABASE <databasename> [, ...]
+//   SCHEMA <schemaname> [, ...]
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //
 // %SeeAlso: REVOKE, WEBDOCS/grant.html
@@ -2570,6 +2924,7 @@ grant_stmt:
 //
 // Targets:
 //   DATABASE <databasename> [, <databasename>]...
+//   SCHEMA <schemaname> [, <schemaname>]...
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //
 // %SeeAlso: GRANT, WEBDOCS/revoke.html
@@ -2670,8 +3025,8 @@ use_stmt:
 // SET remainder, e.g. SET TRANSACTION
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
 | SET LOCAL error { return unimplementedWithIssue(sqllex, 32562) }
 
 // SET SESSION / SET CLUSTER SETTING
@@ -2859,6 +3214,35 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - set the checking mode of deferrable constraints
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS { ALL | <name> [, ...] } { DEFERRED | IMMEDIATE }
+//
+// Only constraints declared DEFERRABLE are affected. The setting lasts until
+// the end of the current transaction.
+// %SeeAlso: SET TRANSACTION, CREATE TABLE
+set_constraints_stmt:
+  SET CONSTRAINTS ALL constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{All: true, Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS name_list constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
+constraints_set_mode:
+  DEFERRED
+  {
+    $$.val = true
+  }
+| IMMEDIATE
+  {
+    $$.val = false
+  }
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -3785,6 +4169,10 @@ targets:
   {
     $$.val = tree.TargetList{Databases: $2.nameList()}
   }
+| SCHEMA name_list
+  {
+    $$.val = tree.TargetList{Schemas: $2.nameList()}
+  }
 
 // target_roles is the variant of targets which recognizes ON ROLES
 // with a name list. This cannot be included in targets directly
@@ -3831,20 +4219,22 @@ pause_stmt:
 // %Help: CREATE TABLE - create a new table
 // %Category: DDL
 // %Text:
-// CREATE TABLE [IF NOT EXISTS] <tablename> ( <elements...> ) [<interleave>]
-// CREATE TABLE [IF NOT EXISTS] <tablename> [( <colnames...> )] AS <source>
+// CREATE [TEMP] TABLE [IF NOT EXISTS] <tablename> ( <elements...> ) [<interleave>] [<storage parameters>]
+// CREATE [TEMP] TABLE [IF NOT EXISTS] <tablename> [( <colnames...> )] AS <source>
 //
 // Table elements:
 //    <name> <type> [<qualifiers...>]
 //    [UNIQUE | INVERTED] INDEX [<name>] ( <colname> [ASC | DESC] [, ...] )
+//                            [USING HASH WITH BUCKET_COUNT = <shard_buckets>]
 //                            [STORING ( <colnames...> )] [<interleave>]
 //    FAMILY [<name>] ( <colnames...> )
 //    [CONSTRAINT <name>] <constraint>
+//    LIKE <tablename> [{INCLUDING | EXCLUDING} {CONSTRAINTS | DEFAULTS | INDEXES | ALL} ...]
 //
 // Table constraints:
-//    PRIMARY KEY ( <colnames...> )
+//    PRIMARY KEY ( <colnames...> ) [USING HASH WITH BUCKET_COUNT = <shard_buckets>]
 //    FOREIGN KEY ( <colnames...> ) REFERENCES <tablename> [( <colnames...> )] [ON DELETE {NO ACTION | RESTRICT}] [ON UPDATE {NO ACTION | RESTRICT}]
-//    UNIQUE ( <colnames... ) [STORING ( <colnames...> )] [<interleave>]
+//    UNIQUE ( <colnames... ) [STORING ( <colnames...> )] [<interleave>] [DEFERRABLE [INITIALLY {DEFERRED | IMMEDIATE}]]
 //    CHECK ( <expr> )
 //
 // Column qualifiers:
@@ -3852,11 +4242,14 @@ pause_stmt:
 //   FAMILY <familyname>, CREATE [IF NOT EXISTS] FAMILY [<familyname>]
 //   REFERENCES <tablename> [( <colnames...> )] [ON DELETE {NO ACTION | RESTRICT}] [ON UPDATE {NO ACTION | RESTRICT}]
 //   COLLATE <collationname>
-//   AS ( <expr> ) STORED
+//   AS ( <expr> ) { STORED | VIRTUAL }
 //
 // Interleave clause:
 //    INTERLEAVE IN PARENT <tablename> ( <colnames...> ) [CASCADE | RESTRICT]
 //
+// Storage parameters:
+//    WITH ( ttl_expire_after = <interval> )
+//
 // %SeeAlso: SHOW TABLES, CREATE VIEW, SHOW CREATE,
 // WEBDOCS/create-table.html
 // WEBDOCS/create-table-as.html
@@ -3876,6 +4269,8 @@ create_table_stmt:
       AsSource: nil,
       AsColumnNames: nil,
       PartitionBy: $9.partitionBy(),
+      Temporary: $2.bool(),
+      StorageParams: $10.kvOptions(),
     }
   }
 | CREATE opt_temp TABLE IF NOT EXISTS table_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by opt_table_with
@@ -3893,12 +4288,26 @@ create_table_stmt:
       AsSource: nil,
       AsColumnNames: nil,
       PartitionBy: $12.partitionBy(),
+      Temporary: $2.bool(),
+      StorageParams: $13.kvOptions(),
     }
   }
 
 opt_table_with:
-  /* EMPTY */     { /* no error */ }
-| WITHOUT OIDS    { /* SKIP DOC */ /* this is also the default in CockroachDB */ }
+  /* EMPTY */
+  {
+    $$.val = nil
+  }
+| WITHOUT OIDS
+  {
+    /* SKIP DOC */
+    /* this is also the default in CockroachDB */
+    $$.val = nil
+  }
+| WITH '(' kv_option_list ')'
+  {
+    $$.val = $3.kvOptions()
+  }
 | WITH name error { return unimplemented(sqllex, "create table with " + $2) }
 
 create_table_as_stmt:
@@ -3916,6 +4325,8 @@ create_table_as_stmt:
       Defs: nil,
       AsSource: $8.slct(),
       AsColumnNames: $5.nameList(),
+      Temporary: $2.bool(),
+      StorageParams: $6.kvOptions(),
     }
   }
 | CREATE opt_temp TABLE IF NOT EXISTS table_name opt_column_list opt_table_with AS select_stmt opt_create_as_data
@@ -3932,6 +4343,8 @@ create_table_as_stmt:
       Defs: nil,
       AsSource: $11.slct(),
       AsColumnNames: $8.nameList(),
+      Temporary: $2.bool(),
+      StorageParams: $9.kvOptions(),
     }
   }
 
@@ -3952,14 +4365,14 @@ opt_create_as_data:
  * so we'll probably continue to treat LOCAL as a noise word.
  */
 opt_temp:
-  TEMPORARY         { return unimplementedWithIssue(sqllex, 5807) }
-| TEMP              { return unimplementedWithIssue(sqllex, 5807) }
-| LOCAL TEMPORARY   { return unimplementedWithIssue(sqllex, 5807) }
-| LOCAL TEMP        { return unimplementedWithIssue(sqllex, 5807) }
-| GLOBAL TEMPORARY  { return unimplementedWithIssue(sqllex, 5807) }
-| GLOBAL TEMP       { return unimplementedWithIssue(sqllex, 5807) }
+  TEMPORARY         { $$.val = true }
+| TEMP              { $$.val = true }
+| LOCAL TEMPORARY   { $$.val = true }
+| LOCAL TEMP        { $$.val = true }
+| GLOBAL TEMPORARY  { $$.val = true }
+| GLOBAL TEMP       { $$.val = true }
 | UNLOGGED          { return unimplemented(sqllex, "create unlogged") }
-| /*EMPTY*/         { /* no error */ }
+| /*EMPTY*/         { $$.val = false }
 
 opt_table_elem_list:
   table_elem_list
@@ -3989,7 +4402,44 @@ table_elem:
   {
     $$.val = $1.constraintDef()
   }
-| LIKE table_name error { return unimplementedWithIssue(sqllex, 30840) }
+| LIKE table_name like_table_option_list
+  {
+    name, err := tree.NormalizeTableName($2.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = &tree.LikeTableDef{
+      Name: name,
+      Options: $3.likeTableOptionList(),
+    }
+  }
+
+like_table_option_list:
+  like_table_option_list like_table_option
+  {
+    $$.val = append($1.likeTableOptionList(), $2.likeTableOption())
+  }
+| /* EMPTY */
+  {
+    $$.val = []tree.LikeTableOption(nil)
+  }
+
+like_table_option:
+  INCLUDING like_table_opt
+  {
+    $$.val = tree.LikeTableOption{Opt: $2.likeTableOpt()}
+  }
+| EXCLUDING like_table_opt
+  {
+    $$.val = tree.LikeTableOption{Excluded: true, Opt: $2.likeTableOpt()}
+  }
+
+like_table_opt:
+  CONSTRAINTS  { $$.val = tree.LikeTableOptConstraints }
+| DEFAULTS     { $$.val = tree.LikeTableOptDefaults }
+| INDEXES      { $$.val = tree.LikeTableOptIndexes }
+| ALL          { $$.val = tree.LikeTableOptAll }
 
 opt_interleave:
   INTERLEAVE IN PARENT table_name '(' name_list ')' opt_interleave_drop_behavior
@@ -4196,7 +4646,7 @@ col_qualification_elem:
   {
     $$.val = &tree.ColumnDefault{Expr: $2.expr()}
   }
-| REFERENCES table_name opt_name_parens key_match reference_actions
+| REFERENCES table_name opt_name_parens key_match reference_actions opt_deferrable
  {
     name, err := tree.NormalizeTableName($2.unresolvedName())
     if err != nil {
@@ -4207,6 +4657,7 @@ col_qualification_elem:
       Table: name,
       Col: tree.Name($3),
       Actions: $5.referenceActions(),
+      Deferrability: $6.constraintDeferrability(),
     }
  }
 | AS '(' a_expr ')' STORED
@@ -4215,34 +4666,38 @@ col_qualification_elem:
  }
 | AS '(' a_expr ')' VIRTUAL
  {
-    return unimplemented(sqllex, "virtual computed columns")
+    $$.val = &tree.ColumnComputedDef{Expr: $3.expr(), Virtual: true}
  }
 | AS error
  {
-    sqllex.Error("syntax error: use AS ( <expr> ) STORED")
+    sqllex.Error("syntax error: use AS ( <expr> ) STORED or AS ( <expr> ) VIRTUAL")
     return 1
  }
 
 index_def:
-  INDEX opt_index_name '(' index_params ')' opt_storing opt_interleave opt_partition_by
+  INDEX opt_index_name '(' index_params ')' opt_hash_sharded opt_storing opt_interleave opt_partition_by opt_where_clause
   {
     $$.val = &tree.IndexTableDef{
       Name:    tree.Name($2),
       Columns: $4.idxElems(),
-      Storing: $6.nameList(),
-      Interleave: $7.interleave(),
-      PartitionBy: $8.partitionBy(),
+      Sharded: $6.shardedIndexDef(),
+      Storing: $7.nameList(),
+      Interleave: $8.interleave(),
+      PartitionBy: $9.partitionBy(),
+      Predicate: $10.expr(),
     }
   }
-| UNIQUE INDEX opt_index_name '(' index_params ')' opt_storing opt_interleave opt_partition_by
+| UNIQUE INDEX opt_index_name '(' index_params ')' opt_hash_sharded opt_storing opt_interleave opt_partition_by opt_where_clause
   {
     $$.val = &tree.UniqueConstraintTableDef{
       IndexTableDef: tree.IndexTableDef {
         Name:    tree.Name($3),
         Columns: $5.idxElems(),
-        Storing: $7.nameList(),
-        Interleave: $8.interleave(),
-        PartitionBy: $9.partitionBy(),
+        Sharded: $7.shardedIndexDef(),
+        Storing: $8.nameList(),
+        Interleave: $9.interleave(),
+        PartitionBy: $10.partitionBy(),
+        Predicate: $11.expr(),
       },
     }
   }
@@ -4281,26 +4736,34 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability() != tree.NotDeferrable {
+      sqllex.Error("CHECK constraints cannot be marked DEFERRABLE")
+      return 1
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
   }
-| UNIQUE '(' index_params ')' opt_storing opt_interleave opt_partition_by  opt_deferrable
+| UNIQUE '(' index_params ')' opt_hash_sharded opt_storing opt_interleave opt_partition_by opt_where_clause opt_deferrable
   {
     $$.val = &tree.UniqueConstraintTableDef{
       IndexTableDef: tree.IndexTableDef{
         Columns: $3.idxElems(),
-        Storing: $5.nameList(),
-        Interleave: $6.interleave(),
-        PartitionBy: $7.partitionBy(),
+        Sharded: $5.shardedIndexDef(),
+        Storing: $6.nameList(),
+        Interleave: $7.interleave(),
+        PartitionBy: $8.partitionBy(),
+        Predicate: $9.expr(),
       },
+      Deferrability: $10.constraintDeferrability(),
     }
   }
-| PRIMARY KEY '(' index_params ')'
+| PRIMARY KEY '(' index_params ')' opt_hash_sharded
   {
     $$.val = &tree.UniqueConstraintTableDef{
       IndexTableDef: tree.IndexTableDef{
         Columns: $4.idxElems(),
+        Sharded: $6.shardedIndexDef(),
       },
       PrimaryKey:    true,
     }
@@ -4318,16 +4781,35 @@ constraint_elem:
       FromCols: $4.nameList(),
       ToCols: $8.nameList(),
       Actions: $10.referenceActions(),
+      Deferrability: $11.constraintDeferrability(),
     }
   }
 
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */
+  {
+    $$.val = tree.NotDeferrable
+  }
+| DEFERRABLE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| DEFERRABLE INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.NotDeferrable
+  }
 
 storing:
   COVERING
@@ -4478,6 +4960,9 @@ numeric_only:
 create_sequence_stmt:
   CREATE opt_temp SEQUENCE sequence_name opt_sequence_option_list
   {
+    if $2.bool() {
+      return unimplementedWithIssue(sqllex, 5807)
+    }
     name, err := tree.NormalizeTableName($4.unresolvedName())
     if err != nil {
       sqllex.Error(err.Error())
@@ -4487,6 +4972,9 @@ create_sequence_stmt:
   }
 | CREATE opt_temp SEQUENCE IF NOT EXISTS sequence_name opt_sequence_option_list
   {
+    if $2.bool() {
+      return unimplementedWithIssue(sqllex, 5807)
+    }
     name, err := tree.NormalizeTableName($7.unresolvedName())
     if err != nil {
       sqllex.Error(err.Error())
@@ -4540,6 +5028,22 @@ truncate_stmt:
   }
 | TRUNCATE error // SHOW HELP: TRUNCATE
 
+// %Help: REFRESH - recompute the contents of a materialized view
+// %Category: DML
+// %Text: REFRESH MATERIALIZED VIEW <viewname>
+// %SeeAlso: CREATE VIEW, SHOW JOBS
+refresh_stmt:
+  REFRESH MATERIALIZED VIEW view_name
+  {
+    name, err := tree.NormalizeTableName($4.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = &tree.RefreshMaterializedView{Name: name}
+  }
+| REFRESH error // SHOW HELP: REFRESH
+
 // %Help: CREATE USER - define a new user
 // %Category: Priv
 // %Text: CREATE USER [IF NOT EXISTS] <name> [ [WITH] PASSWORD <passwd> ]
@@ -4588,11 +5092,16 @@ role_or_group:
 
 // %Help: CREATE VIEW - create a new view
 // %Category: DDL
-// %Text: CREATE VIEW <viewname> [( <colnames...> )] AS <source>
-// %SeeAlso: CREATE TABLE, SHOW CREATE, WEBDOCS/create-view.html
+// %Text:
+// CREATE VIEW <viewname> [( <colnames...> )] AS <source>
+// CREATE MATERIALIZED VIEW <viewname> [( <colnames...> )] AS <source>
+// %SeeAlso: CREATE TABLE, REFRESH, SHOW CREATE, WEBDOCS/create-view.html
 create_view_stmt:
   CREATE opt_temp opt_view_recursive VIEW view_name opt_column_list AS select_stmt
   {
+    if $2.bool() {
+      return unimplementedWithIssue(sqllex, 5807)
+    }
     name, err := tree.NormalizeTableName($5.unresolvedName())
     if err != nil {
       sqllex.Error(err.Error())
@@ -4604,20 +5113,47 @@ create_view_stmt:
       AsSource: $8.slct(),
     }
   }
+| CREATE MATERIALIZED VIEW view_name opt_column_list AS select_stmt
+  {
+    name, err := tree.NormalizeTableName($4.unresolvedName())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = &tree.CreateView{
+      Name: name,
+      ColumnNames: $5.nameList(),
+      AsSource: $7.slct(),
+      Materialized: true,
+    }
+  }
 | CREATE OR REPLACE opt_temp opt_view_recursive VIEW error { return unimplementedWithIssue(sqllex, 24897) }
 | CREATE opt_temp opt_view_recursive VIEW error // SHOW HELP: CREATE VIEW
+| CREATE MATERIALIZED VIEW error // SHOW HELP: CREATE VIEW
 
 opt_view_recursive:
   /* EMPTY */ { /* no error */ }
 | RECURSIVE { return unimplemented(sqllex, "create recursive view") }
 
-// CREATE TYPE/DOMAIN is not yet supported by CockroachDB but we
-// want to report it with the right issue number.
+// %Help: CREATE TYPE - create a new type
+// %Category: DDL
+// %Text: CREATE TYPE <typename> AS ENUM ( [<value> [, ...]] )
+// %SeeAlso: ALTER TYPE, DROP TYPE
+//
+// Types other than ENUM and CREATE DOMAIN are not yet supported by
+// CockroachDB but we want to report them with the right issue number.
 create_type_stmt:
-  // Record/Composite types.
-  CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
   // Enum types.
-| CREATE TYPE type_name AS ENUM '(' error { return unimplementedWithIssue(sqllex, 24873) }
+  CREATE TYPE type_name AS ENUM '(' opt_enum_val_list ')'
+  {
+    name := $3.unresolvedName()
+    if name.NumParts != 1 {
+      return unimplementedWithIssueDetail(sqllex, 24873, "qualified type name")
+    }
+    $$.val = &tree.CreateType{Name: tree.Name(name.Parts[0]), EnumLabels: $7.strs()}
+  }
+  // Record/Composite types.
+| CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
   // Range types.
 | CREATE TYPE type_name AS RANGE error    { return unimplementedWithIssue(sqllex, 27791) }
   // Base (primitive) types.
@@ -4627,12 +5163,113 @@ create_type_stmt:
   // Domain types.
 | CREATE DOMAIN type_name error           { return unimplementedWithIssueDetail(sqllex, 27796, "create") }
 
+opt_enum_val_list:
+  enum_val_list
+  {
+    $$.val = $1.strs()
+  }
+| /* EMPTY */
+  {
+    $$.val = []string(nil)
+  }
+
+enum_val_list:
+  SCONST
+  {
+    $$.val = []string{$1}
+  }
+| enum_val_list ',' SCONST
+  {
+    $$.val = append($1.strs(), $3)
+  }
+
+// %Help: CREATE FUNCTION - define a new function
+// %Category: DDL
+// %Text:
+// CREATE [OR REPLACE] FUNCTION <name> ( [[<argname>] <argtype> [, ...]] )
+//        RETURNS [SETOF] <type>
+//        LANGUAGE SQL
+//        AS '<query>'
+//
+// The query is a SELECT statement which can refer to the arguments by name
+// or as $1, $2, etc.
+// %SeeAlso: DROP FUNCTION
+create_function_stmt:
+  CREATE opt_or_replace FUNCTION name '(' opt_func_arg_list ')' RETURNS opt_setof typename func_language_and_body
+  {
+    langAndBody := $11.strs()
+    if langAndBody[0] != "sql" {
+      return unimplementedWithIssueDetail(sqllex, 17511, "create function language " + langAndBody[0])
+    }
+    $$.val = &tree.CreateFunction{
+      Name: tree.Name($4),
+      Replace: $2.bool(),
+      Args: $6.funcArgs(),
+      ReturnType: $10.colType(),
+      ReturnsSet: $9.bool(),
+      Body: langAndBody[1],
+    }
+  }
+| CREATE opt_or_replace FUNCTION error // SHOW HELP: CREATE FUNCTION
+
+opt_func_arg_list:
+  func_arg_list
+| /* EMPTY */
+  {
+    $$.val = tree.FuncArgs(nil)
+  }
+
+func_arg_list:
+  func_arg
+  {
+    $$.val = tree.FuncArgs{$1.funcArg()}
+  }
+| func_arg_list ',' func_arg
+  {
+    $$.val = append($1.funcArgs(), $3.funcArg())
+  }
+
+// Unlike in PostgreSQL, argument names cannot be keywords: since types can
+// be keywords too, the grammar could not tell them apart.
+func_arg:
+  IDENT typename
+  {
+    $$.val = tree.FuncArg{Name: tree.Name($1), Type: $2.colType()}
+  }
+| typename
+  {
+    $$.val = tree.FuncArg{Type: $1.colType()}
+  }
+
+opt_setof:
+  SETOF
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+// The language and the body of a function can be given in either order.
+func_language_and_body:
+  LANGUAGE name AS SCONST
+  {
+    $$.val = []string{$2, $4}
+  }
+| AS SCONST LANGUAGE name
+  {
+    $$.val = []string{$4, $2}
+  }
+
 // %Help: CREATE INDEX - create a new index
 // %Category: DDL
 // %Text:
 // CREATE [UNIQUE | INVERTED] INDEX [IF NOT EXISTS] [<idxname>]
 //        ON <tablename> ( <colname> [ASC | DESC] [, ...] )
+//        [USING HASH WITH BUCKET_COUNT = <shard_buckets>]
 //        [STORING ( <colnames...> )] [<interleave>]
+//        [WHERE <predicate>]
 //
 // Interleave clause:
 //    INTERLEAVE IN PARENT <tablename> ( <colnames...> ) [CASCADE | RESTRICT]
@@ -4640,7 +5277,7 @@ create_type_stmt:
 // %SeeAlso: CREATE TABLE, SHOW INDEXES, SHOW CREATE,
 // WEBDOCS/create-index.html
 create_index_stmt:
-  CREATE opt_unique INDEX opt_index_name ON table_name opt_using_gin_btree '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
+  CREATE opt_unique INDEX opt_index_name ON table_name opt_using_gin_btree '(' index_params ')' opt_hash_sharded opt_storing opt_interleave opt_partition_by opt_where_clause
   {
     table, err := tree.NormalizeTableName($6.unresolvedName())
     if err != nil {
@@ -4652,13 +5289,15 @@ create_index_stmt:
       Table:   table,
       Unique:  $2.bool(),
       Columns: $9.idxElems(),
-      Storing: $11.nameList(),
-      Interleave: $12.interleave(),
-      PartitionBy: $13.partitionBy(),
+      Sharded: $11.shardedIndexDef(),
+      Storing: $12.nameList(),
+      Interleave: $13.interleave(),
+      PartitionBy: $14.partitionBy(),
       Inverted: $7.bool(),
+      Predicate: $15.expr(),
     }
   }
-| CREATE opt_unique INDEX IF NOT EXISTS index_name ON table_name opt_using_gin_btree '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
+| CREATE opt_unique INDEX IF NOT EXISTS index_name ON table_name opt_using_gin_btree '(' index_params ')' opt_hash_sharded opt_storing opt_interleave opt_partition_by opt_where_clause
   {
     table, err := tree.NormalizeTableName($9.unresolvedName())
     if err != nil {
@@ -4671,13 +5310,15 @@ create_index_stmt:
       Unique:      $2.bool(),
       IfNotExists: true,
       Columns:     $12.idxElems(),
-      Storing:     $14.nameList(),
-      Interleave:  $15.interleave(),
-      PartitionBy: $16.partitionBy(),
+      Sharded:     $14.shardedIndexDef(),
+      Storing:     $15.nameList(),
+      Interleave:  $16.interleave(),
+      PartitionBy: $17.partitionBy(),
       Inverted:    $10.bool(),
+      Predicate:   $18.expr(),
     }
   }
-| CREATE opt_unique INVERTED INDEX opt_index_name ON table_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
+| CREATE opt_unique INVERTED INDEX opt_index_name ON table_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_where_clause
   {
     table, err := tree.NormalizeTableName($7.unresolvedName())
     if err != nil {
@@ -4693,9 +5334,10 @@ create_index_stmt:
       Storing:     $11.nameList(),
       Interleave:  $12.interleave(),
       PartitionBy: $13.partitionBy(),
+      Predicate:   $14.expr(),
     }
   }
-| CREATE opt_unique INVERTED INDEX IF NOT EXISTS index_name ON table_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_idx_where
+| CREATE opt_unique INVERTED INDEX IF NOT EXISTS index_name ON table_name '(' index_params ')' opt_storing opt_interleave opt_partition_by opt_where_clause
   {
     table, err := tree.NormalizeTableName($10.unresolvedName())
     if err != nil {
@@ -4712,14 +5354,11 @@ create_index_stmt:
       Storing:     $14.nameList(),
       Interleave:  $15.interleave(),
       PartitionBy: $16.partitionBy(),
+      Predicate:   $17.expr(),
     }
   }
 | CREATE opt_unique INDEX error // SHOW HELP: CREATE INDEX
 
-opt_idx_where:
-  /* EMPTY */ { /* no error */ }
-| WHERE error { return unimplementedWithIssue(sqllex, 9683) }
-
 opt_using_gin_btree:
   USING name
   {
@@ -4741,6 +5380,18 @@ opt_using_gin_btree:
     $$.val = false
   }
 
+opt_hash_sharded:
+  USING HASH WITH BUCKET_COUNT '=' a_expr
+  {
+    $$.val = &tree.ShardedIndexDef{
+      ShardBuckets: $6.expr(),
+    }
+  }
+| /* EMPTY */
+  {
+    $$.val = (*tree.ShardedIndexDef)(nil)
+  }
+
 opt_unique:
   UNIQUE
   {
@@ -5171,6 +5822,21 @@ transaction_read_mode:
     $$.val = tree.ReadWrite
   }
 
+// %Help: CREATE SCHEMA - create a new schema
+// %Category: DDL
+// %Text: CREATE SCHEMA [IF NOT EXISTS] <schemaname>
+// %SeeAlso: DROP SCHEMA, SHOW SCHEMAS
+create_schema_stmt:
+  CREATE SCHEMA name
+  {
+    $$.val = &tree.CreateSchema{Schema: tree.Name($3), IfNotExists: false}
+  }
+| CREATE SCHEMA IF NOT EXISTS name
+  {
+    $$.val = &tree.CreateSchema{Schema: tree.Name($6), IfNotExists: true}
+  }
+| CREATE SCHEMA error // SHOW HELP: CREATE SCHEMA
+
 // %Help: CREATE DATABASE - create a new database
 // %Category: DDL
 // %Text: CREATE DATABASE [IF NOT EXISTS] <name>
@@ -5356,23 +6022,34 @@ insert_column_item:
 on_conflict:
   ON CONFLICT opt_conf_expr DO UPDATE SET set_clause_list opt_where_clause
   {
-    $$.val = &tree.OnConflict{Columns: $3.nameList(), Exprs: $7.updateExprs(), Where: tree.NewWhere(tree.AstWhere, $8.expr())}
+    oc := $3.onConflict()
+    oc.Exprs = $7.updateExprs()
+    oc.Where = tree.NewWhere(tree.AstWhere, $8.expr())
+    $$.val = oc
   }
 | ON CONFLICT opt_conf_expr DO NOTHING
   {
-    $$.val = &tree.OnConflict{Columns: $3.nameList(), DoNothing: true}
+    oc := $3.onConflict()
+    oc.DoNothing = true
+    $$.val = oc
   }
 
 opt_conf_expr:
   '(' name_list ')'
   {
-    $$.val = $2.nameList()
+    $$.val = &tree.OnConflict{Columns: $2.nameList()}
+  }
+| '(' name_list ')' where_clause
+  {
+    $$.val = &tree.OnConflict{Columns: $2.nameList(), ArbiterPredicate: $4.expr()}
+  }
+| ON CONSTRAINT constraint_name
+  {
+    $$.val = &tree.OnConflict{Constraint: tree.Name($3)}
   }
-| '(' name_list ')' where_clause { return unimplementedWithIssue(sqllex, 32557) }
-| ON CONSTRAINT constraint_name { return unimplementedWithIssue(sqllex, 28161) }
 | /* EMPTY */
   {
-    $$.val = tree.NameList(nil)
+    $$.val = &tree.OnConflict{}
   }
 
 returning_clause:
@@ -5514,34 +6191,106 @@ select_with_parens:
 //      clause.
 //      - 2002-08-28 bjm
 select_no_parens:
-  simple_select opt_for
+  simple_select opt_for_locking_clause
   {
-    $$.val = &tree.Select{Select: $1.selectStmt()}
+    $$.val = &tree.Select{Select: $1.selectStmt(), Locking: $2.lockingClause()}
   }
-| select_clause sort_clause opt_for
+| select_clause sort_clause opt_for_locking_clause
   {
-    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy()}
+    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Locking: $3.lockingClause()}
   }
-| select_clause opt_sort_clause select_limit opt_for
+| select_clause opt_sort_clause select_limit opt_for_locking_clause
   {
-    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $3.limit()}
+    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $3.limit(), Locking: $4.lockingClause()}
   }
-| with_clause select_clause opt_for
+| with_clause select_clause opt_for_locking_clause
   {
-    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt()}
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), Locking: $3.lockingClause()}
   }
-| with_clause select_clause sort_clause opt_for
+| with_clause select_clause sort_clause opt_for_locking_clause
   {
-    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy()}
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Locking: $4.lockingClause()}
   }
-| with_clause select_clause opt_sort_clause select_limit opt_for
+| with_clause select_clause opt_sort_clause select_limit opt_for_locking_clause
   {
-    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit()}
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit(), Locking: $5.lockingClause()}
   }
 
-opt_for:
-  /* EMPTY */ { /* no error */ }
-| FOR error { return unimplementedWithIssue(sqllex, 6583) }
+opt_for_locking_clause:
+  for_locking_clause
+| /* EMPTY */
+  {
+    $$.val = tree.LockingClause(nil)
+  }
+
+for_locking_clause:
+  for_locking_items
+| FOR READ ONLY
+  {
+    $$.val = tree.LockingClause(nil)
+  }
+
+for_locking_items:
+  for_locking_item
+  {
+    $$.val = tree.LockingClause{$1.lockingItem()}
+  }
+| for_locking_items for_locking_item
+  {
+    $$.val = append($1.lockingClause(), $2.lockingItem())
+  }
+
+for_locking_item:
+  for_locking_strength opt_locked_rels opt_nowait_or_skip
+  {
+    $$.val = &tree.LockingItem{
+      Strength:   $1.lockingStrength(),
+      Targets:    $2.tableNames(),
+      WaitPolicy: $3.lockingWaitPolicy(),
+    }
+  }
+
+for_locking_strength:
+  FOR UPDATE
+  {
+    $$.val = tree.ForUpdate
+  }
+| FOR NO KEY UPDATE
+  {
+    $$.val = tree.ForNoKeyUpdate
+  }
+| FOR SHARE
+  {
+    $$.val = tree.ForShare
+  }
+| FOR KEY SHARE
+  {
+    $$.val = tree.ForKeyShare
+  }
+
+opt_locked_rels:
+  /* EMPTY */
+  {
+    $$.val = tree.TableNames(nil)
+  }
+| OF table_name_list
+  {
+    $$.val = $2.tableNames()
+  }
+
+opt_nowait_or_skip:
+  /* EMPTY */
+  {
+    $$.val = tree.LockWaitBlock
+  }
+| SKIP LOCKED
+  {
+    $$.val = tree.LockWaitSkip
+  }
+| NOWAIT
+  {
+    $$.val = tree.LockWaitError
+  }
 
 select_clause:
 // We only provide help if an open parenthesis is provided, because
@@ -5600,6 +6349,8 @@ simple_select:
 //        [ ORDER BY <expr> [ ASC | DESC ] [, ...] ]
 //        [ LIMIT { <expr> | ALL } ]
 //        [ OFFSET <expr> [ ROW | ROWS ] ]
+//        [ FOR { UPDATE | NO KEY UPDATE | SHARE | KEY SHARE } [ OF <tablename> [, ...] ]
+//              [ NOWAIT | SKIP LOCKED ] [...] ]
 // %SeeAlso: WEBDOCS/select-clause.html
 simple_select_clause:
   SELECT opt_all_clause target_list
@@ -5708,7 +6459,10 @@ with_clause:
     /* SKIP DOC */
     $$.val = &tree.With{CTEList: $2.ctes()}
   }
-| WITH RECURSIVE cte_list { return unimplementedWithIssue(sqllex, 21085) }
+| WITH RECURSIVE cte_list
+  {
+    $$.val = &tree.With{Recursive: true, CTEList: $3.ctes()}
+  }
 
 cte_list:
   common_table_expr
@@ -5938,7 +6692,7 @@ first_or_next:
 // Each item in the group_clause list is either an expression tree or a
 // GroupingSet node of some type.
 group_clause:
-  GROUP BY expr_list
+  GROUP BY group_by_list
   {
     $$.val = tree.GroupBy($3.exprs())
   }
@@ -5947,6 +6701,42 @@ group_clause:
     $$.val = tree.GroupBy(nil)
   }
 
+group_by_list:
+  group_by_item
+  {
+    $$.val = tree.Exprs{$1.expr()}
+  }
+| group_by_list ',' group_by_item
+  {
+    $$.val = append($1.exprs(), $3.expr())
+  }
+
+// An empty pair of parentheses, the empty grouping set, is parsed as an
+// empty tuple by a_expr.
+group_by_item:
+  a_expr
+| rollup_clause
+| cube_clause
+| grouping_sets_clause
+
+rollup_clause:
+  ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.Rollup, Exprs: $3.exprs()}
+  }
+
+cube_clause:
+  CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.Cube, Exprs: $3.exprs()}
+  }
+
+grouping_sets_clause:
+  GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.GroupingSets, Exprs: $4.exprs()}
+  }
+
 having_clause:
   HAVING a_expr
   {
@@ -6117,7 +6907,15 @@ table_ref:
       As:         $3.aliasClause(),
     }
   }
-| LATERAL select_with_parens opt_ordinality opt_alias_clause { return unimplementedWithIssueDetail(sqllex, 24560, "select") }
+| LATERAL select_with_parens opt_ordinality opt_alias_clause
+  {
+    $$.val = &tree.AliasedTableExpr{
+      Expr:       &tree.Subquery{Select: $2.selectStmt()},
+      Ordinality: $3.bool(),
+      Lateral:    true,
+      As:         $4.aliasClause(),
+    }
+  }
 | joined_table
   {
     $$.val = $1.tblExpr()
@@ -6131,7 +6929,11 @@ table_ref:
     f := $1.tblExpr()
     $$.val = &tree.AliasedTableExpr{Expr: f, Ordinality: $2.bool(), As: $3.aliasClause()}
   }
-| LATERAL func_table opt_ordinality opt_alias_clause { return unimplementedWithIssueDetail(sqllex, 24560, "srf") }
+| LATERAL func_table opt_ordinality opt_alias_clause
+  {
+    f := $2.tblExpr()
+    $$.val = &tree.AliasedTableExpr{Expr: f, Ordinality: $3.bool(), Lateral: true, As: $4.aliasClause()}
+  }
 // The following syntax is a CockroachDB extension:
 //     SELECT ... FROM [ EXPLAIN .... ] WHERE ...
 //     SELECT ... FROM [ SHOW .... ] WHERE ...
@@ -6558,7 +7360,7 @@ const_typename:
     // See https://www.postgresql.org/docs/9.1/static/datatype-character.html
     // Postgres supports a special character type named "char" (with the quotes)
     // that is a single-character column type. It's used by system tables.
-    // Eventually this clause will be used to parse user-defined types as well,
+    // This clause is also used to parse references to user-defined types,
     // since their names can be quoted.
     if $1 == "char" {
       $$.val = coltypes.QChar
@@ -6569,10 +7371,9 @@ const_typename:
       if !ok {
           switch unimp {
               case 0:
-                // Note: we can only report an unimplemented error for specific
-                // known type names. Anything else may return PII.
-                sqllex.Error("type does not exist")
-                return 1
+                // Any other name refers to a user-defined type, which is
+                // resolved during semantic analysis.
+                $$.val = &coltypes.TUserDefined{Name: $1}
               case -1:
                 return unimplemented(sqllex, "type name " + $1)
               default:
@@ -7029,6 +7830,10 @@ a_expr:
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.ContainedBy, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr TEXTSEARCH_MATCH a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: tree.TSMatches, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr '=' a_expr
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.EQ, Left: $1.expr(), Right: $3.expr()}
@@ -7071,7 +7876,7 @@ a_expr:
   }
 | a_expr INET_CONTAINS_OR_CONTAINED_BY a_expr
   {
-    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("inet_contains_or_contained_by"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
+    $$.val = &tree.ComparisonExpr{Operator: tree.Overlaps, Left: $1.expr(), Right: $3.expr()}
   }
 | a_expr INET_CONTAINS_OR_EQUALS a_expr
   {
@@ -7553,7 +8358,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = tree.NewGroupingFuncExpr($3.exprs())
+  }
 
 func_application:
   func_name '(' ')'
@@ -7591,6 +8399,26 @@ func_expr:
   func_application within_group_clause filter_clause over_clause
   {
     f := $1.expr().(*tree.FuncExpr)
+    if withinGroup := $2.orderBy(); withinGroup != nil {
+      if f.Type == tree.DistinctFuncType {
+        sqllex.Error("cannot use DISTINCT with WITHIN GROUP")
+        return 1
+      }
+      if len(withinGroup) != 1 {
+        return unimplemented(sqllex, "within group with multiple expressions")
+      }
+      if withinGroup[0].OrderType != tree.OrderByColumn {
+        sqllex.Error("ORDER BY INDEX in WITHIN GROUP is not supported")
+        return 1
+      }
+      if withinGroup[0].Direction == tree.Descending {
+        return unimplemented(sqllex, "within group desc")
+      }
+      // The WITHIN GROUP expression is the first argument of ordered-set
+      // aggregates, followed by the direct arguments.
+      f.WithinGroup = true
+      f.Exprs = append(tree.Exprs{withinGroup[0].Expr}, f.Exprs...)
+    }
     f.Filter = $3.expr()
     f.WindowDef = $4.windowDef()
     $$.val = f
@@ -7774,8 +8602,14 @@ special_function:
 
 // Aggregate decoration clauses
 within_group_clause:
-WITHIN GROUP '(' sort_clause ')' { return unimplemented(sqllex, "within group") }
-| /* EMPTY */ {}
+  WITHIN GROUP '(' sort_clause ')'
+  {
+    $$.val = $4.orderBy()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.OrderBy(nil)
+  }
 
 filter_clause:
   FILTER '(' WHERE a_expr ')'
@@ -8774,14 +9608,18 @@ unreserved_keyword:
 | ACTION
 | ADD
 | ADMIN
+| AFTER
 | AGGREGATE
 | ALTER
 | AT
 | BACKUP
+| BEFORE
 | BEGIN
 | BIGSERIAL
+| BINARY
 | BLOB
 | BOOL
+| BUCKET_COUNT
 | BY
 | BYTEA
 | BYTES
@@ -8803,6 +9641,7 @@ unreserved_keyword:
 | CONVERSION
 | COPY
 | COVERING
+| CSV
 | CUBE
 | CURRENT
 | CYCLE
@@ -8812,8 +9651,10 @@ unreserved_keyword:
 | DATE
 | DAY
 | DEALLOCATE
+| DEFAULTS
 | DELETE
 | DEFERRED
+| DELIMITER
 | DISCARD
 | DOMAIN
 | DOUBLE
@@ -8821,6 +9662,7 @@ unreserved_keyword:
 | ENCODING
 | ENUM
 | ESCAPE
+| EXCLUDING
 | EXECUTE
 | EXPERIMENTAL
 | EXPERIMENTAL_AUDIT
@@ -8842,11 +9684,14 @@ unreserved_keyword:
 | GLOBAL
 | GRANTS
 | GROUPS
+| HASH
+| HEADER
 | HIGH
 | HISTOGRAM
 | HOUR
 | IMMEDIATE
 | IMPORT
+| INCLUDING
 | INCREMENT
 | INCREMENTAL
 | INDEXES
@@ -8875,7 +9720,9 @@ unreserved_keyword:
 | LESS
 | LEVEL
 | LIST
+| LISTEN
 | LOCAL
+| LOCKED
 | LOW
 | MATCH
 | MATERIALIZED
@@ -8889,6 +9736,8 @@ unreserved_keyword:
 | NEXT
 | NO
 | NORMAL
+| NOTIFY
+| NOWAIT
 | NO_INDEX_JOIN
 | OF
 | OFF
@@ -8919,6 +9768,7 @@ unreserved_keyword:
 | READ
 | RECURSIVE
 | REF
+| REFRESH
 | REGCLASS
 | REGPROC
 | REGPROCEDURE
@@ -8932,6 +9782,7 @@ unreserved_keyword:
 | RESTORE
 | RESTRICT
 | RESUME
+| RETURNS
 | REVOKE
 | ROLE
 | ROLES
@@ -8960,14 +9811,19 @@ unreserved_keyword:
 | SESSION
 | SESSIONS
 | SET
+| SETOF
+| SETS
+| SHARE
 | SHOW
 | SIMPLE
+| SKIP
 | SMALLSERIAL
 | SNAPSHOT
 | SQL
 | START
 | STATISTICS
 | STDIN
+| STDOUT
 | STORE
 | STORED
 | STORING
@@ -8993,6 +9849,7 @@ unreserved_keyword:
 | UNBOUNDED
 | UNCOMMITTED
 | UNKNOWN
+| UNLISTEN
 | UNLOGGED
 | UPDATE
 | UPSERT
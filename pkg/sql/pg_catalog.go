@@ -26,6 +26,7 @@ import (
 	"unicode"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -391,6 +392,7 @@ var (
 	relKindIndex    = tree.NewDString("i")
 	relKindView     = tree.NewDString("v")
 	relKindSequence = tree.NewDString("S")
+	relKindMatView  = tree.NewDString("m")
 
 	relPersistencePermanent = tree.NewDString("p")
 )
@@ -433,7 +435,9 @@ CREATE TABLE pg_catalog.pg_class (
 			func(db *sqlbase.DatabaseDescriptor, scName string, table *sqlbase.TableDescriptor) error {
 				// The only difference between tables, views and sequences is the relkind column.
 				relKind := relKindTable
-				if table.IsView() {
+				if table.MaterializedView() {
+					relKind = relKindMatView
+				} else if table.IsView() {
 					relKind = relKindView
 				} else if table.IsSequence() {
 					relKind = relKindSequence
@@ -709,6 +713,12 @@ CREATE TABLE pg_catalog.pg_constraint (
 					f.WriteString("UNIQUE (")
 					con.Index.ColNamesFormat(f)
 					f.WriteByte(')')
+					if con.Deferrable {
+						f.WriteString(" DEFERRABLE")
+						if con.InitiallyDeferred {
+							f.WriteString(" INITIALLY DEFERRED")
+						}
+					}
 					condef = tree.NewDString(f.CloseAndGetString())
 
 				case sqlbase.ConstraintTypeCheck:
@@ -727,9 +737,9 @@ CREATE TABLE pg_catalog.pg_constraint (
 					dNameOrNull(conName),                         // conname
 					namespaceOid,                                 // connamespace
 					contype,                                      // contype
-					tree.DBoolFalse,                              // condeferrable
-					tree.DBoolFalse,                              // condeferred
-					tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
+					tree.MakeDBool(tree.DBool(con.Deferrable)),        // condeferrable
+					tree.MakeDBool(tree.DBool(con.InitiallyDeferred)), // condeferred
+					tree.MakeDBool(tree.DBool(!con.Unvalidated)),      // convalidated
 					tblOid,         // conrelid
 					oidZero,        // contypid
 					conindid,       // conindid
@@ -1152,11 +1162,15 @@ CREATE TABLE pg_catalog.pg_index (
 					if err != nil {
 						return err
 					}
+					indpred := tree.DNull
+					if index.IsPartial() {
+						indpred = tree.NewDString(index.Predicate)
+					}
 					return addRow(
 						h.IndexOid(db, scName, table, index), // indexrelid
 						tableOid, // indrelid
 						tree.NewDInt(tree.DInt(len(index.ColumnNames))),                                          // indnatts
-						tree.MakeDBool(tree.DBool(index.Unique)),                                                 // indisunique
+						tree.MakeDBool(tree.DBool(index.Unique || index.DeferrableUnique)),                       // indisunique
 						tree.MakeDBool(tree.DBool(table.IsPhysicalTable() && index.ID == table.PrimaryIndex.ID)), // indisprimary
 						tree.DBoolFalse,                          // indisexclusion
 						tree.MakeDBool(tree.DBool(index.Unique)), // indimmediate
@@ -1171,7 +1185,7 @@ CREATE TABLE pg_catalog.pg_index (
 						indclass,                                 // indclass
 						indoption,                                // indoption
 						tree.DNull,                               // indexprs
-						tree.DNull,                               // indpred
+						indpred,                                  // indpred
 					)
 				})
 			})
@@ -1271,6 +1285,13 @@ func indexDefFromDescriptor(
 		}
 		indexDef.Interleave = intlDef
 	}
+	if index.IsPartial() {
+		pred, err := parser.ParseExpr(index.Predicate)
+		if err != nil {
+			return "", err
+		}
+		indexDef.Predicate = pred
+	}
 	return indexDef.String(), nil
 }
 
@@ -2219,6 +2240,8 @@ var datumToTypeCategory = map[reflect.Type]*tree.DString{
 	reflect.TypeOf(types.String):      typCategoryString,
 	reflect.TypeOf(types.Timestamp):   typCategoryDateTime,
 	reflect.TypeOf(types.TimestampTZ): typCategoryDateTime,
+	reflect.TypeOf(types.TSQuery):     typCategoryUserDefined,
+	reflect.TypeOf(types.TSVector):    typCategoryUserDefined,
 	reflect.TypeOf(types.FamTuple):    typCategoryPseudo,
 	reflect.TypeOf(types.Oid):         typCategoryNumeric,
 	reflect.TypeOf(types.UUID):        typCategoryUserDefined,
@@ -77,6 +77,10 @@ type commandResult struct {
 	// case for queries executed through the simple protocol). Otherwise, it needs
 	// to have an entry for every column.
 	formatCodes []pgwirebase.FormatCode
+
+	// copyOut is set for COPY ... TO STDOUT statements, whose rows are sent
+	// using the Copy-out subprotocol.
+	copyOut *copyOutFormat
 }
 
 func (c *conn) makeCommandResult(
@@ -86,7 +90,7 @@ func (c *conn) makeCommandResult(
 	formatCodes []pgwirebase.FormatCode,
 	conv sessiondata.DataConversionConfig,
 ) commandResult {
-	return commandResult{
+	res := commandResult{
 		conn:           c,
 		pos:            pos,
 		descOpt:        descOpt,
@@ -96,6 +100,10 @@ func (c *conn) makeCommandResult(
 		cmdCompleteTag: stmt.StatementTag(),
 		conv:           conv,
 	}
+	if cp, ok := stmt.(*tree.CopyTo); ok {
+		res.copyOut = makeCopyOutFormat(&cp.Options)
+	}
+	return res
 }
 
 func (c *conn) makeMiscResult(pos sql.CmdPos, typ completionMsgType) commandResult {
@@ -135,6 +143,9 @@ func (r *commandResult) Close(t sql.TransactionStatusIndicator) {
 	// Send a completion message, specific to the type of result.
 	switch r.typ {
 	case commandComplete:
+		if r.copyOut != nil {
+			r.conn.bufferCopyDone(r.copyOut)
+		}
 		tag := cookTag(
 			r.cmdCompleteTag, r.conn.writerState.tagBuf[:0], r.stmtType, r.rowsAffected,
 		)
@@ -213,7 +224,13 @@ func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 	}
 	r.rowsAffected++
 
-	r.conn.bufferRow(ctx, row, r.formatCodes, r.conv)
+	if r.copyOut != nil {
+		if err := r.conn.bufferCopyData(ctx, row, r.copyOut, r.conv); err != nil {
+			return err
+		}
+	} else {
+		r.conn.bufferRow(ctx, row, r.formatCodes, r.conv)
+	}
 	_ /* flushed */, err := r.conn.maybeFlush(r.pos)
 	return err
 }
@@ -221,6 +238,10 @@ func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 // SetColumns is part of the CommandResult interface.
 func (r *commandResult) SetColumns(ctx context.Context, cols sqlbase.ResultColumns) {
 	r.conn.writerState.fi.registerCmd(r.pos)
+	if r.copyOut != nil {
+		r.conn.bufferCopyOutResponse(cols, r.copyOut)
+		return
+	}
 	if r.descOpt == sql.NeedRowDesc {
 		_ /* err */ = r.conn.writeRowDescription(ctx, cols, r.formatCodes, &r.conn.writerState.buf)
 	}
@@ -43,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log/logtags"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/lib/pq/oid"
@@ -89,10 +90,45 @@ type conn struct {
 		// network connection.
 		buf    bytes.Buffer
 		tagBuf [64]byte
+		// readyForQueryIdle is set when buf ends with a ReadyForQuery message
+		// sent outside of a transaction. The client is idle once buf is flushed.
+		readyForQueryIdle bool
+	}
+
+	// notifications groups the state used to deliver asynchronous
+	// notifications, which are sent by other goroutines through
+	// SendNotification().
+	notifications struct {
+		// writeMu serializes the network writes of the notification writer
+		// goroutine with those of the command processor.
+		writeMu syncutil.Mutex
+
+		mu struct {
+			syncutil.Mutex
+			// pending holds the notifications not yet sent to the client.
+			pending []sql.Notification
+			// idle is set when the client waits for a query outside of a
+			// transaction. Notifications are written by the notification writer
+			// goroutine while idle and by the command processor, before
+			// ReadyForQuery, otherwise.
+			idle bool
+		}
+
+		// wakeC is signaled when notifications can be written by the notification
+		// writer goroutine.
+		wakeC chan struct{}
+
+		// msgBuilder and buf are used by the notification writer goroutine.
+		msgBuilder writeBuffer
+		buf        bytes.Buffer
 	}
 
 	readBuf    pgwirebase.ReadBuffer
 	msgBuilder writeBuffer
+
+	// copyOutBuf is used to format the values sent by COPY ... TO STDOUT
+	// before they are escaped.
+	copyOutBuf writeBuffer
 }
 
 // serveConn creates a conn that will serve the netConn. It returns once the
@@ -181,6 +217,9 @@ func newConn(
 	c.writerState.fi.lastFlushed = -1
 	c.writerState.fi.cmdStarts = make(map[sql.CmdPos]int)
 	c.msgBuilder.init(metrics.BytesOutCount)
+	c.copyOutBuf.init(metrics.BytesOutCount)
+	c.notifications.wakeC = make(chan struct{}, 1)
+	c.notifications.msgBuilder.init(metrics.BytesOutCount)
 
 	return c
 }
@@ -319,6 +358,14 @@ func (c *conn) serveImpl(
 			wg.Done()
 			cancelConn()
 		}()
+
+		// The client is idle until it sends its first query.
+		c.setIdle(true)
+		wg.Add(1)
+		go func() {
+			c.writeNotifications(ctx)
+			wg.Done()
+		}()
 	}
 
 	var err error
@@ -334,6 +381,9 @@ Loop:
 		if err != nil {
 			break Loop
 		}
+		// Notifications are no longer written asynchronously once the client
+		// sent a message, until the processor sends the next ReadyForQuery.
+		c.setIdle(false)
 		if log.V(2) {
 			log.Infof(ctx, "pgwire: processing %s", typ)
 		}
@@ -578,6 +628,12 @@ func (c *conn) handleParse(ctx context.Context, buf *pgwirebase.ReadBuffer) erro
 	}
 	endParse := timeutil.Now()
 
+	if _, ok := stmt.(*tree.CopyTo); ok {
+		// COPY ... TO STDOUT is executed like a query, but the client would need
+		// to expect CopyData messages instead of the portal's result rows.
+		return c.stmtBuf.Push(ctx, sql.SendError{Err: pgerror.NewError(
+			pgerror.CodeFeatureNotSupportedError, "COPY TO not supported in extended protocol mode")})
+	}
 	if _, ok := stmt.(*tree.CopyFrom); ok {
 		// We don't support COPY in extended protocol because it'd be complicated:
 		// it wouldn't be the preparing, but the execution that would need to
@@ -793,6 +849,8 @@ func (c *conn) BeginCopyIn(ctx context.Context, columns []sqlbase.ResultColumn)
 	for range columns {
 		c.msgBuilder.putInt16(int16(pgwirebase.FormatText))
 	}
+	c.notifications.writeMu.Lock()
+	defer c.notifications.writeMu.Unlock()
 	return c.msgBuilder.finishMsg(c.conn)
 }
 
@@ -922,7 +980,219 @@ func (c *conn) bufferRow(
 	}
 }
 
+// copyOutFormat describes the encoding of the rows sent by a
+// COPY ... TO STDOUT statement. It is the statement's options with the
+// defaults of the format filled in.
+type copyOutFormat struct {
+	format    tree.CopyFormat
+	delimiter byte
+	null      string
+	header    bool
+}
+
+func makeCopyOutFormat(opts *tree.CopyOptions) *copyOutFormat {
+	f := &copyOutFormat{format: opts.FileFormat, delimiter: '\t', null: `\N`}
+	switch f.format {
+	case tree.CopyFormatUnspecified:
+		f.format = tree.CopyFormatText
+	case tree.CopyFormatCSV:
+		f.delimiter = ','
+		f.null = ""
+	}
+	if opts.Delimiter != nil {
+		f.delimiter = (*opts.Delimiter)[0]
+	}
+	if opts.Null != nil {
+		f.null = *opts.Null
+	}
+	if opts.Header != nil {
+		f.header = *opts.Header
+	}
+	return f
+}
+
+// copyOutBinarySignature starts the binary COPY format. It is followed by the
+// flags field and the length of the header extension area, both zero.
+const copyOutBinarySignature = "PGCOPY\n\377\r\n\000"
+
+// bufferCopyOutResponse starts the Copy-out subprotocol. The rows of a
+// COPY ... TO STDOUT statement are sent in CopyData messages, one per row,
+// instead of DataRow messages.
+func (c *conn) bufferCopyOutResponse(cols sqlbase.ResultColumns, f *copyOutFormat) {
+	fmtCode := pgwirebase.FormatText
+	if f.format == tree.CopyFormatBinary {
+		fmtCode = pgwirebase.FormatBinary
+	}
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyOutResponse)
+	c.msgBuilder.writeByte(byte(fmtCode))
+	c.msgBuilder.putInt16(int16(len(cols)))
+	for range cols {
+		c.msgBuilder.putInt16(int16(fmtCode))
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(fmt.Sprintf("unexpected err from buffer: %s", err))
+	}
+
+	switch {
+	case f.format == tree.CopyFormatBinary:
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		c.msgBuilder.writeString(copyOutBinarySignature)
+		c.msgBuilder.putInt32(0)
+		c.msgBuilder.putInt32(0)
+	case f.format == tree.CopyFormatCSV && f.header:
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		for i := range cols {
+			if i > 0 {
+				c.msgBuilder.writeByte(f.delimiter)
+			}
+			f.writeCSVField(&c.msgBuilder, []byte(cols[i].Name), len(cols) == 1)
+		}
+		c.msgBuilder.writeByte('\n')
+	default:
+		return
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(fmt.Sprintf("unexpected err from buffer: %s", err))
+	}
+}
+
+// bufferCopyData buffers a CopyData message containing a row produced by a
+// COPY ... TO STDOUT statement. Contrary to bufferRow, an error is returned
+// if a value cannot be encoded, since the binary format does not support
+// all the types.
+func (c *conn) bufferCopyData(
+	ctx context.Context, row tree.Datums, f *copyOutFormat, conv sessiondata.DataConversionConfig,
+) error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	if f.format == tree.CopyFormatBinary {
+		c.msgBuilder.putInt16(int16(len(row)))
+		for _, d := range row {
+			c.msgBuilder.writeBinaryDatum(ctx, d, conv.Location)
+		}
+		return c.msgBuilder.finishMsg(&c.writerState.buf)
+	}
+
+	for i, d := range row {
+		if i > 0 {
+			c.msgBuilder.writeByte(f.delimiter)
+		}
+		if d == tree.DNull {
+			c.msgBuilder.writeString(f.null)
+			continue
+		}
+		// Format the value with its length prefix, which is then skipped.
+		c.copyOutBuf.reset()
+		c.copyOutBuf.writeTextDatum(ctx, d, conv)
+		if c.copyOutBuf.err != nil {
+			c.msgBuilder.setError(c.copyOutBuf.err)
+			break
+		}
+		val := c.copyOutBuf.wrapped.Bytes()[4:]
+		if f.format == tree.CopyFormatCSV {
+			f.writeCSVField(&c.msgBuilder, val, len(row) == 1)
+		} else {
+			f.writeTextField(&c.msgBuilder, val)
+		}
+	}
+	c.msgBuilder.writeByte('\n')
+	return c.msgBuilder.finishMsg(&c.writerState.buf)
+}
+
+// writeTextField writes a value in the text format, escaping the backslashes,
+// the control characters and the delimiter.
+func (f *copyOutFormat) writeTextField(b *writeBuffer, val []byte) {
+	start := 0
+	for i, ch := range val {
+		var esc byte
+		switch ch {
+		case '\b':
+			esc = 'b'
+		case '\f':
+			esc = 'f'
+		case '\n':
+			esc = 'n'
+		case '\r':
+			esc = 'r'
+		case '\t':
+			esc = 't'
+		case '\v':
+			esc = 'v'
+		case '\\':
+			esc = '\\'
+		default:
+			if ch != f.delimiter {
+				continue
+			}
+			esc = ch
+		}
+		b.write(val[start:i])
+		b.writeByte('\\')
+		b.writeByte(esc)
+		start = i + 1
+	}
+	b.write(val[start:])
+}
+
+// writeCSVField writes a value in the CSV format. The value is quoted if it
+// could otherwise be confused with the NULL string, the delimiter, the end of
+// the row or, when it is the only field of the row, the end-of-data marker.
+func (f *copyOutFormat) writeCSVField(b *writeBuffer, val []byte, singleField bool) {
+	quote := string(val) == f.null || (singleField && string(val) == `\.`)
+	for i := 0; !quote && i < len(val); i++ {
+		switch val[i] {
+		case f.delimiter, '"', '\r', '\n':
+			quote = true
+		}
+	}
+	if !quote {
+		b.write(val)
+		return
+	}
+	b.writeByte('"')
+	start := 0
+	for i, ch := range val {
+		if ch == '"' {
+			// Quotes are escaped by doubling them.
+			b.write(val[start : i+1])
+			start = i
+		}
+	}
+	b.write(val[start:])
+	b.writeByte('"')
+}
+
+// bufferCopyDone ends the Copy-out subprotocol. It is followed by the
+// CommandComplete message of the COPY ... TO STDOUT statement.
+func (c *conn) bufferCopyDone(f *copyOutFormat) {
+	if f.format == tree.CopyFormatBinary {
+		// The trailer is a field count of -1.
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		c.msgBuilder.putInt16(-1)
+		if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+			panic(fmt.Sprintf("unexpected err from buffer: %s", err))
+		}
+	}
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDone)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(fmt.Sprintf("unexpected err from buffer: %s", err))
+	}
+}
+
 func (c *conn) bufferReadyForQuery(txnStatus byte) {
+	if txnStatus == byte(sql.IdleTxnBlock) {
+		// Notifications received while the client was busy are sent before it
+		// becomes idle again.
+		c.notifications.mu.Lock()
+		pending := c.notifications.mu.pending
+		c.notifications.mu.pending = nil
+		c.notifications.mu.Unlock()
+		for _, n := range pending {
+			if err := writeNotification(n, &c.msgBuilder, &c.writerState.buf); err != nil {
+				panic(fmt.Sprintf("unexpected err from buffer: %s", err))
+			}
+		}
+		c.writerState.readyForQueryIdle = true
+	}
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgReady)
 	c.msgBuilder.writeByte(txnStatus)
 	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
@@ -1105,14 +1375,104 @@ func (c *conn) Flush(pos sql.CmdPos) error {
 	c.writerState.fi.lastFlushed = pos
 	c.writerState.fi.cmdStarts = make(map[sql.CmdPos]int)
 
+	c.notifications.writeMu.Lock()
+	defer c.notifications.writeMu.Unlock()
+	idle := c.writerState.readyForQueryIdle
+	c.writerState.readyForQueryIdle = false
 	_ /* n */, err := c.writerState.buf.WriteTo(c.conn)
 	if err != nil {
 		c.setErr(err)
 		return err
 	}
+	if idle {
+		c.setIdle(true)
+	}
 	return nil
 }
 
+// SendNotification is part of the sql.NotificationSender interface.
+//
+// The notification is written to the network connection right away if the
+// client is idle, and before the next ReadyForQuery message otherwise.
+func (c *conn) SendNotification(n sql.Notification) {
+	c.notifications.mu.Lock()
+	c.notifications.mu.pending = append(c.notifications.mu.pending, n)
+	idle := c.notifications.mu.idle
+	c.notifications.mu.Unlock()
+	if idle {
+		c.wakeNotificationWriter()
+	}
+}
+
+// setIdle records whether the client is idle, waking up the notification writer
+// goroutine if notifications can now be written.
+func (c *conn) setIdle(idle bool) {
+	c.notifications.mu.Lock()
+	c.notifications.mu.idle = idle
+	wake := idle && len(c.notifications.mu.pending) > 0
+	c.notifications.mu.Unlock()
+	if wake {
+		c.wakeNotificationWriter()
+	}
+}
+
+func (c *conn) wakeNotificationWriter() {
+	select {
+	case c.notifications.wakeC <- struct{}{}:
+	default:
+		// The writer has already been woken up.
+	}
+}
+
+// writeNotifications is the notification writer goroutine. It writes the
+// pending notifications to the network connection while the client is idle,
+// until ctx is canceled.
+func (c *conn) writeNotifications(ctx context.Context) {
+	for {
+		select {
+		case <-c.notifications.wakeC:
+		case <-ctx.Done():
+			return
+		}
+
+		c.notifications.writeMu.Lock()
+		var pending []sql.Notification
+		c.notifications.mu.Lock()
+		if c.notifications.mu.idle {
+			pending = c.notifications.mu.pending
+			c.notifications.mu.pending = nil
+		}
+		c.notifications.mu.Unlock()
+		var err error
+		for _, n := range pending {
+			if err = writeNotification(n, &c.notifications.msgBuilder, &c.notifications.buf); err != nil {
+				break
+			}
+		}
+		if err == nil && c.GetErr() == nil {
+			_ /* n */, err = c.notifications.buf.WriteTo(c.conn)
+		}
+		c.notifications.buf.Reset()
+		c.notifications.writeMu.Unlock()
+		if err != nil {
+			// The network connection is broken; the reader goroutine will notice.
+			c.setErr(err)
+			return
+		}
+	}
+}
+
+// writeNotification writes a NotificationResponse message for n to w. The ID
+// of the node the notification was sent from is reported in place of the
+// process ID of the notifying backend.
+func writeNotification(n sql.Notification, msgBuilder *writeBuffer, w io.Writer) error {
+	msgBuilder.initMsg(pgwirebase.ServerMsgNotificationResponse)
+	msgBuilder.putInt32(int32(n.NodeID))
+	msgBuilder.writeTerminatedString(n.Channel)
+	msgBuilder.writeTerminatedString(n.Payload)
+	return msgBuilder.finishMsg(w)
+}
+
 // maybeFlush flushes the buffer to the network connection if it exceeded
 // connResultsBufferSizeBytes.
 func (c *conn) maybeFlush(pos sql.CmdPos) (bool, error) {
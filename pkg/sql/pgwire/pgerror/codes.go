@@ -244,6 +244,7 @@ const (
 	CodeObjectInUseError                  = "55006"
 	CodeCantChangeRuntimeParamError       = "55P02"
 	CodeLockNotAvailableError             = "55P03"
+	CodeUnsafeNewEnumValueUsageError      = "55P04"
 	// Class 57 - Operator Intervention
 	CodeOperatorInterventionError = "57000"
 	CodeQueryCanceledError        = "57014"
@@ -46,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/jackc/pgx"
+	"github.com/jackc/pgx/pgproto3"
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
@@ -2297,6 +2298,164 @@ func TestSessionParameters(t *testing.T) {
 	}
 }
 
+// TestPGWireCopyTo checks the output of COPY ... TO STDOUT, which is sent
+// using the Copy-out subprotocol. lib/pq does not support it, so the
+// messages are read with a bare frontend.
+func TestPGWireCopyTo(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	params := base.TestServerArgs{Insecure: true}
+	s, db, _ := serverutils.StartServer(t, params)
+	ctx := context.TODO()
+	defer s.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (a INT PRIMARY KEY, b STRING, c BOOL)`)
+	sqlDB.Exec(t, `INSERT INTO d.t VALUES
+		(1, 'plain', NULL),
+		(2, e'a\tb\\c\nd', true),
+		(3, 'say "hi", bye', false),
+		(4, '', NULL)`)
+
+	conn, err := net.Dial("tcp", s.ServingAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	fe, err := pgproto3.NewFrontend(conn, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// waitForReady consumes the messages until the next ReadyForQuery.
+	waitForReady := func() {
+		t.Helper()
+		for {
+			msg, err := fe.Receive()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := msg.(*pgproto3.ReadyForQuery); ok {
+				return
+			}
+		}
+	}
+	if err := fe.Send(&pgproto3.StartupMessage{
+		ProtocolVersion: 196608, // Version 3.0
+		Parameters: map[string]string{
+			"user":     security.RootUser,
+			"database": "d",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	waitForReady()
+
+	// copyTo runs a COPY statement and returns the concatenated CopyData
+	// messages and the command tag.
+	copyTo := func(query string) (data, tag string, err error) {
+		t.Helper()
+		if err := fe.Send(&pgproto3.Query{String: query}); err != nil {
+			t.Fatal(err)
+		}
+		defer waitForReady()
+		var buf bytes.Buffer
+		for {
+			msg, err := fe.Receive()
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch msg := msg.(type) {
+			case *pgproto3.CopyOutResponse:
+			case *pgproto3.CopyData:
+				buf.Write(msg.Data)
+			case *pgproto3.CopyDone:
+			case *pgproto3.CommandComplete:
+				return buf.String(), msg.CommandTag, nil
+			case *pgproto3.ErrorResponse:
+				return "", "", errors.New(msg.Message)
+			default:
+				t.Fatalf("unexpected message %#v", msg)
+			}
+		}
+	}
+
+	testData := []struct {
+		query    string
+		expected string
+		tag      string
+	}{
+		{
+			query:    `COPY t TO STDOUT`,
+			expected: "1\tplain\t\\N\n2\ta\\tb\\\\c\\nd\tt\n3\tsay \"hi\", bye\tf\n4\t\t\\N\n",
+			tag:      "COPY 4",
+		},
+		{
+			query:    `COPY t (c, a) TO STDOUT WITH (DELIMITER '|', NULL 'null')`,
+			expected: "null|1\nt|2\nf|3\nnull|4\n",
+			tag:      "COPY 4",
+		},
+		{
+			query: `COPY t TO STDOUT WITH CSV HEADER`,
+			expected: "a,b,c\n1,plain,\n2,\"a\tb\\c\nd\",t\n" +
+				"3,\"say \"\"hi\"\", bye\",f\n4,\"\",\n",
+			tag: "COPY 4",
+		},
+		{
+			query: `COPY (SELECT a FROM t WHERE a = 1) TO STDOUT (FORMAT binary)`,
+			// The header, a row with a single INT8 field and the trailer.
+			expected: "PGCOPY\n\xff\r\n\x00" + "\x00\x00\x00\x00" + "\x00\x00\x00\x00" +
+				"\x00\x01" + "\x00\x00\x00\x08" + "\x00\x00\x00\x00\x00\x00\x00\x01" +
+				"\xff\xff",
+			tag: "COPY 1",
+		},
+		{
+			query:    `COPY (SELECT a FROM t WHERE a > 10) TO STDOUT`,
+			expected: "",
+			tag:      "COPY 0",
+		},
+	}
+	for _, test := range testData {
+		t.Run(test.query, func(t *testing.T) {
+			data, tag, err := copyTo(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, data)
+			}
+			if tag != test.tag {
+				t.Errorf("expected tag %q, got %q", test.tag, tag)
+			}
+		})
+	}
+
+	// Large results are streamed to the client as they are produced.
+	data, tag, err := copyTo(`COPY (SELECT generate_series(1, 100000)) TO STDOUT`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != "COPY 100000" || strings.Count(data, "\n") != 100000 {
+		t.Fatalf("unexpected result: %s, %d lines", tag, strings.Count(data, "\n"))
+	}
+
+	for _, test := range []struct {
+		query       string
+		expectedErr string
+	}{
+		{`COPY t TO STDOUT WITH (FORMAT binary, DELIMITER ',')`, `cannot specify DELIMITER in BINARY mode`},
+		{`COPY t TO STDOUT WITH (HEADER)`, `COPY HEADER available only in CSV mode`},
+		{`COPY t TO STDOUT WITH (DELIMITER '||')`, `COPY delimiter must be a single one-byte character`},
+		{`COPY t TO STDOUT WITH (DELIMITER '|', NULL 'a|b')`, `COPY delimiter must not appear in the NULL specification`},
+		{`COPY nonexistent TO STDOUT`, `relation "nonexistent" does not exist`},
+	} {
+		if _, _, err := copyTo(test.query); !testutils.IsError(err, test.expectedErr) {
+			t.Errorf("%s: expected %q, got %v", test.query, test.expectedErr, err)
+		}
+	}
+}
+
 type pgxTestLogger struct{}
 
 func (l pgxTestLogger) Log(level pgx.LogLevel, msg string, data map[string]interface{}) {
@@ -6,19 +6,22 @@ import "strconv"
 
 const (
 	_ServerMessageType_name_0 = "ServerMsgParseCompleteServerMsgBindCompleteServerMsgCloseComplete"
-	_ServerMessageType_name_1 = "ServerMsgCommandCompleteServerMsgDataRowServerMsgErrorResponse"
-	_ServerMessageType_name_2 = "ServerMsgCopyInResponse"
-	_ServerMessageType_name_3 = "ServerMsgEmptyQuery"
+	_ServerMessageType_name_1 = "ServerMsgNotificationResponse"
+	_ServerMessageType_name_2 = "ServerMsgCommandCompleteServerMsgDataRowServerMsgErrorResponse"
+	_ServerMessageType_name_3 = "ServerMsgCopyInResponseServerMsgCopyOutResponseServerMsgEmptyQuery"
 	_ServerMessageType_name_4 = "ServerMsgAuthServerMsgParameterStatusServerMsgRowDescription"
 	_ServerMessageType_name_5 = "ServerMsgReady"
-	_ServerMessageType_name_6 = "ServerMsgNoData"
-	_ServerMessageType_name_7 = "ServerMsgParameterDescription"
+	_ServerMessageType_name_6 = "ServerMsgCopyDoneServerMsgCopyData"
+	_ServerMessageType_name_7 = "ServerMsgNoData"
+	_ServerMessageType_name_8 = "ServerMsgParameterDescription"
 )
 
 var (
 	_ServerMessageType_index_0 = [...]uint8{0, 22, 43, 65}
-	_ServerMessageType_index_1 = [...]uint8{0, 24, 40, 62}
+	_ServerMessageType_index_2 = [...]uint8{0, 24, 40, 62}
+	_ServerMessageType_index_3 = [...]uint8{0, 23, 47, 66}
 	_ServerMessageType_index_4 = [...]uint8{0, 13, 37, 60}
+	_ServerMessageType_index_6 = [...]uint8{0, 17, 34}
 )
 
 func (i ServerMessageType) String() string {
@@ -26,22 +29,26 @@ func (i ServerMessageType) String() string {
 	case 49 <= i && i <= 51:
 		i -= 49
 		return _ServerMessageType_name_0[_ServerMessageType_index_0[i]:_ServerMessageType_index_0[i+1]]
+	case i == 65:
+		return _ServerMessageType_name_1
 	case 67 <= i && i <= 69:
 		i -= 67
-		return _ServerMessageType_name_1[_ServerMessageType_index_1[i]:_ServerMessageType_index_1[i+1]]
-	case i == 71:
-		return _ServerMessageType_name_2
-	case i == 73:
-		return _ServerMessageType_name_3
+		return _ServerMessageType_name_2[_ServerMessageType_index_2[i]:_ServerMessageType_index_2[i+1]]
+	case 71 <= i && i <= 73:
+		i -= 71
+		return _ServerMessageType_name_3[_ServerMessageType_index_3[i]:_ServerMessageType_index_3[i+1]]
 	case 82 <= i && i <= 84:
 		i -= 82
 		return _ServerMessageType_name_4[_ServerMessageType_index_4[i]:_ServerMessageType_index_4[i+1]]
 	case i == 90:
 		return _ServerMessageType_name_5
+	case 99 <= i && i <= 100:
+		i -= 99
+		return _ServerMessageType_name_6[_ServerMessageType_index_6[i]:_ServerMessageType_index_6[i+1]]
 	case i == 110:
-		return _ServerMessageType_name_6
-	case i == 116:
 		return _ServerMessageType_name_7
+	case i == 116:
+		return _ServerMessageType_name_8
 	default:
 		return "ServerMessageType(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
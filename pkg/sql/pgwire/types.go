@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/lib/pq/oid"
 	"github.com/pkg/errors"
 )
@@ -121,6 +122,9 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DCollatedString:
 		b.writeLengthPrefixedString(v.Contents)
 
+	case *tree.DEnum:
+		b.writeLengthPrefixedString(v.LogicalRep)
+
 	case *tree.DDate:
 		t := timeutil.Unix(int64(*v)*secondsInDay, 0)
 		// Start at offset 4 because `putInt32` clobbers the first 4 bytes.
@@ -152,6 +156,12 @@ func (b *writeBuffer) writeTextDatum(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DTSVector:
+		b.writeLengthPrefixedString(v.TSVector.String())
+
+	case *tree.DTSQuery:
+		b.writeLengthPrefixedString(v.TSQuery.String())
+
 	case *tree.DTuple:
 		b.textFormatter.FormatNode(v)
 		b.writeLengthPrefixedVariablePutbuf()
@@ -372,6 +382,9 @@ func (b *writeBuffer) writeBinaryDatum(
 	case *tree.DCollatedString:
 		b.writeLengthPrefixedString(v.Contents)
 
+	case *tree.DEnum:
+		b.writeLengthPrefixedString(v.LogicalRep)
+
 	case *tree.DTimestamp:
 		b.putInt32(8)
 		b.putInt64(timeToPgBinary(v.Time, nil))
@@ -433,6 +446,42 @@ func (b *writeBuffer) writeBinaryDatum(
 		// Postgres version number, as of writing, `1` is the only valid value.
 		b.writeByte(1)
 		b.writeString(s)
+	case *tree.DTSVector:
+		subWriter := newWriteBuffer(nil /* bytecount */)
+		subWriter.putInt32(int32(len(v.TSVector)))
+		for _, t := range v.TSVector {
+			subWriter.writeTerminatedString(t.Lexeme)
+			subWriter.putInt16(int16(len(t.Positions)))
+			for _, p := range t.Positions {
+				// The weight is stored in the two high bits of the position.
+				subWriter.putInt16(int16(uint16(p.Weight)<<14 | p.Pos))
+			}
+		}
+		b.writeLengthPrefixedBuffer(&subWriter.wrapped)
+	case *tree.DTSQuery:
+		subWriter := newWriteBuffer(nil /* bytecount */)
+		var items []*tsearch.Node
+		collectTSQueryItems(v.Root, &items)
+		subWriter.putInt32(int32(len(items)))
+		for _, n := range items {
+			if n.Op == tsearch.OpOperand {
+				subWriter.writeByte(tsqueryItemValue)
+				subWriter.writeByte(n.Weights)
+				if n.Prefix {
+					subWriter.writeByte(1)
+				} else {
+					subWriter.writeByte(0)
+				}
+				subWriter.writeTerminatedString(n.Lexeme)
+				continue
+			}
+			subWriter.writeByte(tsqueryItemOperator)
+			subWriter.writeByte(tsqueryOperators[n.Op])
+			if n.Op == tsearch.OpPhrase {
+				subWriter.putInt16(int16(n.Distance))
+			}
+		}
+		b.writeLengthPrefixedBuffer(&subWriter.wrapped)
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
@@ -441,6 +490,32 @@ func (b *writeBuffer) writeBinaryDatum(
 	}
 }
 
+// The item types and operator codes of the binary format of tsquery.
+const (
+	tsqueryItemValue    = 1
+	tsqueryItemOperator = 2
+)
+
+var tsqueryOperators = map[tsearch.Operator]byte{
+	tsearch.OpNot:    1,
+	tsearch.OpAnd:    2,
+	tsearch.OpOr:     3,
+	tsearch.OpPhrase: 4,
+}
+
+// collectTSQueryItems appends the nodes of a tsquery to items in the order
+// of its binary format, which is the order in which PostgreSQL stores them:
+// each operator is followed by its right operand, and then by its left
+// operand.
+func collectTSQueryItems(n *tsearch.Node, items *[]*tsearch.Node) {
+	if n == nil {
+		return
+	}
+	*items = append(*items, n)
+	collectTSQueryItems(n.Right, items)
+	collectTSQueryItems(n.Left, items)
+}
+
 const (
 	pgTimeFormat              = "15:04:05.999999"
 	pgDateFormat              = "2006-01-02"
@@ -20,6 +20,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -70,8 +71,14 @@ func (a UncachedPhysicalAccessor) GetDatabaseDesc(
 
 // IsValidSchema implements the SchemaAccessor interface.
 func (a UncachedPhysicalAccessor) IsValidSchema(dbDesc *DatabaseDescriptor, scName string) bool {
-	// At this point, only the public schema is recognized.
-	return scName == tree.PublicSchema
+	if scName == tree.PublicSchema || sessiondata.IsTemporarySchemaName(scName) {
+		return true
+	}
+	if dbDesc == nil {
+		return false
+	}
+	_, ok := dbDesc.FindSchema(scName)
+	return ok
 }
 
 // GetObjectNames implements the SchemaAccessor interface.
@@ -82,16 +89,21 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 	scName string,
 	flags DatabaseListFlags,
 ) (TableNames, error) {
-	if ok := a.IsValidSchema(dbDesc, scName); !ok {
-		if flags.required {
+	log.Eventf(ctx, "fetching list of objects for %q", dbDesc.Name)
+	// The database descriptor may come from a cache that does not know yet
+	// about a recently created schema, so the schema is looked up again.
+	parentID, err := getNamespaceParentID(ctx, txn, dbDesc.ID, scName)
+	if err != nil {
+		return nil, err
+	}
+	if parentID == sqlbase.InvalidID {
+		if flags.required && !sessiondata.IsTemporarySchemaName(scName) {
 			tn := tree.MakeTableNameWithSchema(tree.Name(dbDesc.Name), tree.Name(scName), "")
 			return nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(&tn.TableNamePrefix))
 		}
 		return nil, nil
 	}
-
-	log.Eventf(ctx, "fetching list of objects for %q", dbDesc.Name)
-	prefix := sqlbase.MakeNameMetadataKey(dbDesc.ID, "")
+	prefix := sqlbase.MakeNameMetadataKey(parentID, "")
 	sr, err := txn.Scan(ctx, prefix, prefix.PrefixEnd(), 0)
 	if err != nil {
 		return nil, err
@@ -104,7 +116,12 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 		if err != nil {
 			return nil, err
 		}
-		tn := tree.MakeTableName(tree.Name(dbDesc.Name), tree.Name(tableName))
+		if sessiondata.IsTemporarySchemaName(tableName) {
+			// Temporary schemas are recorded alongside the tables of the
+			// database, but they are not objects themselves.
+			continue
+		}
+		tn := tree.MakeTableNameWithSchema(tree.Name(dbDesc.Name), tree.Name(scName), tree.Name(tableName))
 		tn.ExplicitCatalog = flags.explicitPrefix
 		tn.ExplicitSchema = flags.explicitPrefix
 		tableNames = append(tableNames, tn)
@@ -116,14 +133,6 @@ func (a UncachedPhysicalAccessor) GetObjectNames(
 func (a UncachedPhysicalAccessor) GetObjectDesc(
 	ctx context.Context, txn *client.Txn, name *ObjectName, flags ObjectLookupFlags,
 ) (ObjectDescriptor, *DatabaseDescriptor, error) {
-	// At this point, only the public schema is recognized.
-	if name.Schema() != tree.PublicSchema {
-		if flags.required {
-			return nil, nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(name))
-		}
-		return nil, nil, nil
-	}
-
 	// Look up the database.
 	dbDesc, err := a.GetDatabaseDesc(ctx, txn, name.Catalog(), flags.CommonLookupFlags)
 	if dbDesc == nil || err != nil {
@@ -132,13 +141,29 @@ func (a UncachedPhysicalAccessor) GetObjectDesc(
 		return nil, dbDesc, err
 	}
 
-	// Look up the table using the discovered database descriptor.
-	desc := &sqlbase.TableDescriptor{}
-	found, err := getDescriptor(ctx, txn,
-		tableKey{parentID: dbDesc.ID, name: name.Table()}, desc)
+	// The tables of temporary and user-defined schemas are recorded in
+	// system.namespace under the ID of their schema rather than under the ID
+	// of the database.
+	parentID, err := getNamespaceParentID(ctx, txn, dbDesc.ID, name.Schema())
 	if err != nil {
 		return nil, nil, err
 	}
+	if parentID == sqlbase.InvalidID && !sessiondata.IsTemporarySchemaName(name.Schema()) {
+		if flags.required {
+			return nil, nil, sqlbase.NewUnsupportedSchemaUsageError(tree.ErrString(name))
+		}
+		return nil, nil, nil
+	}
+
+	// Look up the table using the discovered database descriptor.
+	desc := &sqlbase.TableDescriptor{}
+	found := false
+	if parentID != sqlbase.InvalidID {
+		found, err = getDescriptor(ctx, txn, tableKey{parentID: parentID, name: name.Table()}, desc)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 
 	if found {
 		// We have a descriptor. Is it in the right state? We'll keep it if
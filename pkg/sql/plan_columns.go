@@ -57,12 +57,16 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return n.header
 	case *joinNode:
 		return n.columns
+	case *applyJoinNode:
+		return n.columns
 	case *ordinalityNode:
 		return n.columns
 	case *renderNode:
 		return n.columns
 	case *scanNode:
 		return n.resultColumns
+	case *scanBufferNode:
+		return n.columns
 	case *sortNode:
 		return n.columns
 	case *unionNode:
@@ -126,6 +130,8 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return getPlanColumns(n.source.plan, mut)
 	case *max1RowNode:
 		return getPlanColumns(n.plan, mut)
+	case *recursiveCTENode:
+		return getPlanColumns(n.initial, mut)
 	case *limitNode:
 		return getPlanColumns(n.plan, mut)
 	case *spoolNode:
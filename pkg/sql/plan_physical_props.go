@@ -96,14 +96,19 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *DropUserNode:
 	case *alterIndexNode:
 	case *alterSequenceNode:
+	case *alterTypeNode:
 	case *alterTableNode:
 	case *alterUserSetPasswordNode:
+	case *applyJoinNode:
 	case *cancelQueriesNode:
 	case *cancelSessionsNode:
 	case *controlJobsNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
 	case *createSequenceNode:
+	case *createTypeNode:
+	case *createFunctionNode:
+	case *createSchemaNode:
 	case *createStatsNode:
 	case *createTableNode:
 	case *createViewNode:
@@ -111,18 +116,24 @@ func planPhysicalProps(plan planNode) physicalProps {
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropSequenceNode:
+	case *dropTypeNode:
+	case *dropFunctionNode:
+	case *dropSchemaNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *explainDistSQLNode:
 	case *hookFnNode:
 	case *iterativeSortStrategy:
+	case *refreshViewNode:
 	case *relocateNode:
 	case *renameColumnNode:
 	case *renameDatabaseNode:
 	case *renameIndexNode:
 	case *renameTableNode:
+	case *recursiveCTENode:
 	case *rowCountNode:
 	case *rowSourceToPlanNode:
+	case *scanBufferNode:
 	case *scatterNode:
 	case *scrubNode:
 	case *sequenceSelectNode:
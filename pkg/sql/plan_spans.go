@@ -17,6 +17,7 @@ package sql
 import (
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -36,7 +37,8 @@ func collectSpans(params runParams, plan planNode) (reads, writes roachpb.Spans,
 		*valuesNode,
 		*virtualTableNode,
 		*zeroNode,
-		*unaryNode:
+		*unaryNode,
+		*scanBufferNode:
 		return nil, nil, nil
 
 	case *scanNode:
@@ -76,6 +78,17 @@ func collectSpans(params runParams, plan planNode) (reads, writes roachpb.Spans,
 		return collectSpans(params, n.plan)
 	case *max1RowNode:
 		return collectSpans(params, n.plan)
+	case *recursiveCTENode:
+		// The recursive query is only planned during execution, so we don't
+		// know which spans it reads. Conservatively assume it reads
+		// everything.
+		_, writes, err := collectSpans(params, n.initial)
+		return roachpb.Spans{{Key: keys.MinKey, EndKey: keys.MaxKey}}, writes, err
+	case *applyJoinNode:
+		// The right side is only planned during execution, so we don't know
+		// which spans it reads. Conservatively assume it reads everything.
+		_, writes, err := collectSpans(params, n.input.plan)
+		return roachpb.Spans{{Key: keys.MinKey, EndKey: keys.MaxKey}}, writes, err
 	case *spoolNode:
 		return collectSpans(params, n.source)
 	case *sortNode:
@@ -178,7 +191,11 @@ func insertNodeWithValuesSpans(
 		}
 		addWriteKey(primaryKey)
 		for _, secondaryKey := range secondaryKeys {
-			addWriteKey(secondaryKey.Key)
+			// Partial indexes produce empty entries for the rows that don't
+			// satisfy their predicate.
+			if len(secondaryKey.Key) > 0 {
+				addWriteKey(secondaryKey.Key)
+			}
 		}
 
 		// Determine the table spans that foreign key constraints will require
@@ -47,6 +47,10 @@ type extendedEvalContext struct {
 
 	SessionMutator *sessionDataMutator
 
+	// SessionID is the ID of the session. It names the temporary schemas of
+	// the session.
+	SessionID ClusterWideID
+
 	// VirtualSchemas can be used to access virtual tables.
 	VirtualSchemas VirtualTabler
 
@@ -73,6 +77,10 @@ type extendedEvalContext struct {
 	SchemaChangers *schemaChangerCollection
 
 	schemaAccessors *schemaInterface
+
+	// NotificationListener is used by LISTEN and UNLISTEN. It is nil if the
+	// session cannot receive notifications.
+	NotificationListener *notificationListener
 }
 
 // schemaInterface provides access to the database and table descriptors.
@@ -239,6 +247,8 @@ func newInternalPlanner(
 	p.semaCtx = tree.MakeSemaContext(sd.User == security.RootUser /* privileged */)
 	p.semaCtx.Location = &sd.DataConversion.Location
 	p.semaCtx.SearchPath = sd.SearchPath
+	p.semaCtx.TypeResolver = p
+	p.semaCtx.FunctionResolver = p
 
 	plannerMon := mon.MakeUnlimitedMonitor(ctx,
 		fmt.Sprintf("internal-planner.%s.%s", user, opName),
@@ -251,6 +261,7 @@ func newInternalPlanner(
 	)
 	p.extendedEvalCtx.Planner = p
 	p.extendedEvalCtx.Sequence = p
+	p.extendedEvalCtx.Notifier = p
 	p.extendedEvalCtx.ClusterID = execCfg.ClusterID()
 	p.extendedEvalCtx.NodeID = execCfg.NodeID.Get()
 
@@ -561,6 +572,9 @@ func (p *planner) optionallyUseOptimizer(
 // transaction.
 type txnModesSetter interface {
 	setTransactionModes(modes tree.TransactionModes) error
+	// setConstraintsMode implements SET CONSTRAINTS. An empty names list
+	// stands for ALL.
+	setConstraintsMode(ctx context.Context, names []string, deferred bool) error
 }
 
 // sqlStatsCollector is the interface used by SQL execution, through the
@@ -0,0 +1,254 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// recursiveCTEMaxIterations is the maximum number of times the recursive term
+// of a recursive common table expression is evaluated before the query is
+// aborted. It protects the cluster against runaway recursions that never reach
+// a fixpoint.
+var recursiveCTEMaxIterations = settings.RegisterNonNegativeIntSetting(
+	"sql.recursive_cte.max_iterations",
+	"maximum number of iterations of a recursive common table expression (0 = no limit)",
+	10000,
+)
+
+// recursiveCTEIterationFn creates a plan for one iteration of the recursive
+// term of a recursive CTE. The given work table node returns the rows that
+// were produced by the previous iteration.
+type recursiveCTEIterationFn func(ctx context.Context, workTable planNode) (planNode, error)
+
+// recursiveCTENode implements the logic for a recursive CTE:
+//  1. Evaluate the initial query; emit the results and also save them in
+//     a "working" table.
+//  2. So long as the working table is not empty:
+//     - evaluate the recursive query, substituting the current contents of
+//       the working table for the recursive self-reference;
+//     - emit all resulting rows, and save them as the next iteration's
+//       working table.
+// When deduplicate is set (UNION as opposed to UNION ALL), rows that were
+// already emitted are discarded and are not added to the working table.
+//
+// Rows are emitted as soon as they are produced, so a LIMIT above this node
+// can stop the recursion early.
+type recursiveCTENode struct {
+	initial planNode
+
+	genIterationFn recursiveCTEIterationFn
+
+	// label is a string used to describe the node in EXPLAIN and error
+	// messages; it is the name of the CTE.
+	label string
+
+	deduplicate bool
+
+	run recursiveCTERun
+}
+
+// recursiveCTERun contains the run-time state of recursiveCTENode during
+// local execution.
+type recursiveCTERun struct {
+	// current is the plan that is currently producing rows: the initial plan
+	// or the plan for the current iteration.
+	current planNode
+
+	// workingRows contains the rows produced by the previous iteration; they
+	// are scanned by the current iteration.
+	workingRows *sqlbase.RowContainer
+	// nextRows accumulates the rows produced by the current iteration; they
+	// become the working rows of the next iteration.
+	nextRows *sqlbase.RowContainer
+
+	// currentRow is the row most recently returned by Next.
+	currentRow tree.Datums
+
+	// iterations is the number of times the recursive term was evaluated.
+	iterations int64
+
+	// seen contains the encoding of all the rows emitted so far, if
+	// deduplicate is set. The memory used by the map is registered with
+	// seenAcc.
+	seen    map[string]struct{}
+	seenAcc mon.BoundAccount
+	// scratch is a preallocated buffer for encoding rows.
+	scratch []byte
+}
+
+func (n *recursiveCTENode) startExec(params runParams) error {
+	typs := sqlbase.ColTypeInfoFromResCols(planColumns(n.initial))
+	n.run.workingRows = sqlbase.NewRowContainer(
+		params.EvalContext().Mon.MakeBoundAccount(), typs, 0, /* rowCapacity */
+	)
+	n.run.nextRows = sqlbase.NewRowContainer(
+		params.EvalContext().Mon.MakeBoundAccount(), typs, 0, /* rowCapacity */
+	)
+	if n.deduplicate {
+		n.run.seen = make(map[string]struct{})
+		n.run.seenAcc = params.EvalContext().Mon.MakeBoundAccount()
+	}
+	n.run.current = n.initial
+	return nil
+}
+
+func (n *recursiveCTENode) Next(params runParams) (bool, error) {
+	for {
+		if err := params.p.cancelChecker.Check(); err != nil {
+			return false, err
+		}
+
+		if n.run.current != nil {
+			ok, err := n.run.current.Next(params)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				row := n.run.current.Values()
+				if n.deduplicate {
+					isNew, err := n.markSeen(params.ctx, row)
+					if err != nil {
+						return false, err
+					}
+					if !isNew {
+						continue
+					}
+				}
+				n.run.currentRow, err = n.run.nextRows.AddRow(params.ctx, row)
+				if err != nil {
+					return false, err
+				}
+				return true, nil
+			}
+			if n.run.current != n.initial {
+				n.run.current.Close(params.ctx)
+			}
+			n.run.current = nil
+		}
+
+		// The last evaluation of a term did not produce any new rows: we have
+		// reached the fixpoint.
+		if n.run.nextRows.Len() == 0 {
+			return false, nil
+		}
+
+		// The rows produced by the last evaluation become the working table of
+		// the next iteration.
+		n.run.workingRows, n.run.nextRows = n.run.nextRows, n.run.workingRows
+		n.run.nextRows.Clear(params.ctx)
+
+		n.run.iterations++
+		maxIterations := recursiveCTEMaxIterations.Get(&params.EvalContext().Settings.SV)
+		if maxIterations > 0 && n.run.iterations > maxIterations {
+			return false, pgerror.NewErrorf(pgerror.CodeProgramLimitExceededError,
+				"recursive query %q exceeded the maximum of %d iterations", n.label, maxIterations,
+			).SetHintf("the limit can be changed with the cluster setting %s",
+				"sql.recursive_cte.max_iterations")
+		}
+
+		workTable := &scanBufferNode{
+			columns: planColumns(n.initial),
+			label:   n.label,
+			rows:    n.run.workingRows,
+		}
+		plan, err := n.genIterationFn(params.ctx, workTable)
+		if err != nil {
+			return false, err
+		}
+		if err := startExec(params, plan); err != nil {
+			plan.Close(params.ctx)
+			return false, err
+		}
+		n.run.current = plan
+	}
+}
+
+// markSeen records the given row as emitted. It returns false if the row was
+// emitted before.
+func (n *recursiveCTENode) markSeen(ctx context.Context, row tree.Datums) (bool, error) {
+	var err error
+	n.run.scratch, err = sqlbase.EncodeDatumsKeyAscending(n.run.scratch[:0], row)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := n.run.seen[string(n.run.scratch)]; ok {
+		return false, nil
+	}
+	if err := n.run.seenAcc.Grow(ctx, int64(len(n.run.scratch))); err != nil {
+		return false, err
+	}
+	n.run.seen[string(n.run.scratch)] = struct{}{}
+	return true, nil
+}
+
+func (n *recursiveCTENode) Values() tree.Datums {
+	return n.run.currentRow
+}
+
+func (n *recursiveCTENode) Close(ctx context.Context) {
+	if n.run.current != nil && n.run.current != n.initial {
+		n.run.current.Close(ctx)
+	}
+	n.run.current = nil
+	n.initial.Close(ctx)
+	if n.run.workingRows != nil {
+		n.run.workingRows.Close(ctx)
+		n.run.nextRows.Close(ctx)
+		n.run.workingRows = nil
+		n.run.nextRows = nil
+	}
+	if n.run.seen != nil {
+		n.run.seenAcc.Close(ctx)
+		n.run.seen = nil
+	}
+}
+
+// scanBufferNode is the "work table" of a recursive CTE: it returns the rows
+// that were produced by the previous iteration of the recursive term.
+type scanBufferNode struct {
+	columns sqlbase.ResultColumns
+
+	// label is the name of the CTE, used for EXPLAIN.
+	label string
+
+	// rows is owned by the recursiveCTENode. It is nil when the node is only
+	// planned and never executed.
+	rows *sqlbase.RowContainer
+
+	nextRowIdx int
+	currentRow tree.Datums
+}
+
+func (n *scanBufferNode) Next(params runParams) (bool, error) {
+	if n.rows == nil || n.nextRowIdx >= n.rows.Len() {
+		return false, nil
+	}
+	n.currentRow = n.rows.At(n.nextRowIdx)
+	n.nextRowIdx++
+	return true, nil
+}
+
+func (n *scanBufferNode) Values() tree.Datums {
+	return n.currentRow
+}
+
+func (n *scanBufferNode) Close(context.Context) {}
@@ -0,0 +1,149 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/pkg/errors"
+)
+
+type refreshViewNode struct {
+	n *tree.RefreshMaterializedView
+}
+
+// RefreshMaterializedView recomputes the contents of a materialized view.
+// Privileges: UPDATE on view.
+//   Notes: postgres requires ownership of the view.
+func (p *planner) RefreshMaterializedView(
+	ctx context.Context, n *tree.RefreshMaterializedView,
+) (planNode, error) {
+	return &refreshViewNode{n: n}, nil
+}
+
+func (n *refreshViewNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+	if !p.ExtendedEvalContext().TxnImplicit {
+		return errors.Errorf("REFRESH MATERIALIZED VIEW cannot be used inside a transaction")
+	}
+
+	desc, err := p.ResolveMutableTableDescriptor(ctx, &n.n.Name, true /*required*/, requireViewDesc)
+	if err != nil {
+		return err
+	}
+	if !desc.MaterializedView() {
+		return pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+			"%q is not a materialized view", desc.Name)
+	}
+	if err := p.CheckPrivilege(ctx, desc, privilege.UPDATE); err != nil {
+		return err
+	}
+
+	_, errCh, err := p.ExecCfg().JobRegistry.StartJob(ctx, nil /* resultsCh */, jobs.Record{
+		Description:   tree.AsStringWithFlags(n.n, tree.FmtAlwaysQualifyTableNames),
+		Username:      p.User(),
+		DescriptorIDs: sqlbase.IDs{desc.ID},
+		Details:       jobspb.MaterializedViewRefreshDetails{TableID: desc.ID},
+		Progress:      jobspb.MaterializedViewRefreshProgress{},
+	})
+	if err != nil {
+		return err
+	}
+	return <-errCh
+}
+
+func (*refreshViewNode) Next(runParams) (bool, error) { return false, nil }
+func (*refreshViewNode) Values() tree.Datums          { return tree.Datums{} }
+func (*refreshViewNode) Close(context.Context)        {}
+
+type refreshMaterializedViewResumer struct{}
+
+// Resume is part of the jobs.Resumer interface. The old contents of the view
+// are replaced by the result of its query in a single transaction, so readers
+// never observe a partially refreshed view.
+func (r *refreshMaterializedViewResumer) Resume(
+	ctx context.Context, job *jobs.Job, planHookState interface{}, _ chan<- tree.Datums,
+) error {
+	p := planHookState.(PlanHookState)
+	execCfg := p.ExecCfg()
+	details := job.Details().(jobspb.MaterializedViewRefreshDetails)
+
+	var written int64
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		written = 0
+		desc, err := sqlbase.GetTableDescFromID(ctx, txn, details.TableID)
+		if err != nil {
+			return err
+		}
+		if desc.Dropped() {
+			return errors.Errorf("materialized view %q was dropped", desc.Name)
+		}
+		tableDesc := sqlbase.NewImmutableTableDescriptor(*desc)
+		span := tableDesc.TableSpan()
+		if err := txn.DelRange(ctx, span.Key, span.EndKey); err != nil {
+			return err
+		}
+		return runMaterializedViewQuery(
+			ctx, execCfg, tableDesc, txn.OrigTimestamp(), materializedViewBackfillChunkSize,
+			func(rows []tree.Datums) error {
+				written += int64(len(rows))
+				return insertMaterializedViewRows(
+					ctx, txn, &p.ExtendedEvalContext().EvalContext, tableDesc, rows, false, /* traceKV */
+				)
+			},
+		)
+	}); err != nil {
+		return err
+	}
+	return job.SetProgress(ctx, jobspb.MaterializedViewRefreshProgress{RowsWritten: written})
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r *refreshMaterializedViewResumer) OnFailOrCancel(
+	context.Context, *client.Txn, *jobs.Job,
+) error {
+	return nil
+}
+
+// OnSuccess is part of the jobs.Resumer interface.
+func (r *refreshMaterializedViewResumer) OnSuccess(context.Context, *client.Txn, *jobs.Job) error {
+	return nil
+}
+
+// OnTerminal is part of the jobs.Resumer interface.
+func (r *refreshMaterializedViewResumer) OnTerminal(
+	context.Context, *jobs.Job, jobs.Status, chan<- tree.Datums,
+) {
+}
+
+func refreshMaterializedViewResumeHook(typ jobspb.Type, _ *cluster.Settings) jobs.Resumer {
+	if typ != jobspb.TypeMaterializedViewRefresh {
+		return nil
+	}
+	return &refreshMaterializedViewResumer{}
+}
+
+func init() {
+	jobs.AddResumeHook(refreshMaterializedViewResumeHook)
+}
@@ -129,7 +129,8 @@ func (n *renameColumnNode) startExec(params runParams) error {
 		}
 	}
 
-	// Rename the column in computed columns.
+	// Rename the column in computed columns, including the ones being added
+	// by ALTER COLUMN TYPE conversions.
 	for i := range tableDesc.Columns {
 		if tableDesc.Columns[i].IsComputed() {
 			newExpr, err := renameIn(*tableDesc.Columns[i].ComputeExpr)
@@ -139,6 +140,27 @@ func (n *renameColumnNode) startExec(params runParams) error {
 			tableDesc.Columns[i].ComputeExpr = &newExpr
 		}
 	}
+	for _, m := range tableDesc.Mutations {
+		if c := m.GetColumn(); c != nil && c.IsComputed() {
+			newExpr, err := renameIn(*c.ComputeExpr)
+			if err != nil {
+				return err
+			}
+			c.ComputeExpr = &newExpr
+		}
+	}
+
+	// Rename the column in the predicates of partial indexes.
+	if err := tableDesc.ForeachNonDropIndex(func(idx *sqlbase.IndexDescriptor) error {
+		if !idx.IsPartial() {
+			return nil
+		}
+		var err error
+		idx.Predicate, err = renameIn(idx.Predicate)
+		return err
+	}); err != nil {
+		return err
+	}
 
 	// Rename the column in the indexes.
 	tableDesc.RenameColumnDescriptor(col, string(n.n.NewName))
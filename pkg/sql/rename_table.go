@@ -20,8 +20,10 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
@@ -88,6 +90,19 @@ func (n *renameTableNode) startExec(params runParams) error {
 		return err
 	}
 
+	// A temporary table stays in its schema unless another schema is named
+	// explicitly, which is rejected below.
+	if tableDesc.IsTemporary() &&
+		(!newTn.ExplicitSchema || newTn.Schema() == sessiondata.PgTempSchemaName) {
+		newTn.SchemaName = oldTn.SchemaName
+		newTn.ExplicitSchema = true
+	}
+	// So does a table of a user-defined schema.
+	if tableDesc.SchemaID != 0 && !newTn.ExplicitSchema {
+		newTn.SchemaName = oldTn.SchemaName
+		newTn.ExplicitSchema = true
+	}
+
 	// Check if target database exists.
 	// We also look at uncached descriptors here.
 	targetDbDesc, err := p.ResolveUncachedDatabase(ctx, newTn)
@@ -95,7 +110,8 @@ func (n *renameTableNode) startExec(params runParams) error {
 		return err
 	}
 
-	if err := p.CheckPrivilege(ctx, targetDbDesc, privilege.CREATE); err != nil {
+	targetScDesc, err := p.checkCreatePrivilegeInSchema(ctx, targetDbDesc, newTn.Schema())
+	if err != nil {
 		return err
 	}
 
@@ -107,11 +123,22 @@ func (n *renameTableNode) startExec(params runParams) error {
 		return nil
 	}
 
+	if tableDesc.IsTemporary() != sessiondata.IsTemporarySchemaName(newTn.Schema()) ||
+		(tableDesc.IsTemporary() && (targetDbDesc.ID != prevDbDesc.ID || newTn.Schema() != oldTn.Schema())) {
+		return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"cannot move objects into or out of temporary schemas")
+	}
+
+	prevParentID := tableDesc.GetNamespaceParentID()
 	tableDesc.SetName(newTn.Table())
 	tableDesc.ParentID = targetDbDesc.ID
+	tableDesc.SchemaID = 0
+	if targetScDesc != nil {
+		tableDesc.SchemaID = targetScDesc.ID
+	}
 
 	descKey := sqlbase.MakeDescMetadataKey(tableDesc.GetID())
-	newTbKey := tableKey{targetDbDesc.ID, newTn.Table()}.Key()
+	newTbKey := tableKey{tableDesc.GetNamespaceParentID(), newTn.Table()}.Key()
 
 	if err := tableDesc.Validate(ctx, p.txn, p.EvalContext().Settings); err != nil {
 		return err
@@ -121,7 +148,7 @@ func (n *renameTableNode) startExec(params runParams) error {
 	descDesc := sqlbase.WrapDescriptor(tableDesc)
 
 	renameDetails := sqlbase.TableDescriptor_NameInfo{
-		ParentID: prevDbDesc.ID,
+		ParentID: prevParentID,
 		Name:     oldTn.Table()}
 	tableDesc.DrainingNames = append(tableDesc.DrainingNames, renameDetails)
 	if err := p.writeSchemaChange(ctx, tableDesc, sqlbase.InvalidMutationID); err != nil {
@@ -98,6 +98,7 @@ func (p *planner) Select(
 	limit := n.Limit
 	orderBy := n.OrderBy
 	with := n.With
+	locking := n.Locking
 
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
 		wrapped = s.Select.Select
@@ -124,6 +125,25 @@ func (p *planner) Select(
 			}
 			limit = s.Select.Limit
 		}
+		locking = append(locking, s.Select.Locking...)
+	}
+
+	if len(locking) > 0 {
+		if err := p.checkLockingClause(locking, wrapped); err != nil {
+			return nil, err
+		}
+	}
+	// The locking clause only applies to the FROM clause of this SELECT, and
+	// not to any nested SELECT statement.
+	defer func(saved tree.LockingClause) { p.curPlan.locking = saved }(p.curPlan.locking)
+	p.curPlan.locking = locking
+
+	if s, ok := wrapped.(*tree.SelectClause); ok {
+		expanded, err := tree.ExpandGroupingSets(s, p.SessionData().SearchPath)
+		if err != nil {
+			return nil, err
+		}
+		wrapped = expanded
 	}
 
 	switch s := wrapped.(type) {
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
@@ -218,11 +219,16 @@ func ResolveTargetObject(
 			"cannot create %q because the target database or schema does not exist",
 			tree.ErrString(tn)).SetHintf("verify that the current database and search_path are valid and/or the target database exists")
 	}
-	if tn.Schema() != tree.PublicSchema {
-		return nil, pgerror.NewErrorf(pgerror.CodeInvalidNameError,
-			"schema cannot be modified: %q", tree.ErrString(&tn.TableNamePrefix))
+	dbDesc := descI.(*DatabaseDescriptor)
+	if scName := tn.Schema(); scName != tree.PublicSchema && !sessiondata.IsTemporarySchemaName(scName) {
+		// Objects can be created in user-defined schemas, but not in the
+		// virtual schemas.
+		if _, ok := dbDesc.FindSchema(scName); !ok {
+			return nil, pgerror.NewErrorf(pgerror.CodeInvalidNameError,
+				"schema cannot be modified: %q", tree.ErrString(&tn.TableNamePrefix))
+		}
 	}
-	return descI.(*DatabaseDescriptor), nil
+	return dbDesc, nil
 }
 
 func (p *planner) ResolveUncachedDatabase(
@@ -234,6 +240,59 @@ func (p *planner) ResolveUncachedDatabase(
 	return res, err
 }
 
+// ResolveType implements the tree.TypeResolver interface. User-defined types
+// are looked up in the current database.
+func (p *planner) ResolveType(name string) (coltypes.T, error) {
+	ctx := p.EvalContext().Context
+	if p.CurrentDatabase() == "" {
+		return nil, tree.NewUndefinedTypeError(name)
+	}
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /*required*/)
+	if err != nil {
+		return nil, err
+	}
+	typDesc, err := getTypeDesc(ctx, p.txn, dbDesc, name)
+	if err != nil {
+		return nil, err
+	}
+	if typDesc == nil {
+		return nil, tree.NewUndefinedTypeError(name)
+	}
+	return &coltypes.TEnum{Typ: typDesc.DatumType()}, nil
+}
+
+// getTypeDesc looks up the descriptor of the user-defined type with the given
+// name in the given database. It returns nil if there is no such type.
+func getTypeDesc(
+	ctx context.Context, txn *client.Txn, dbDesc *DatabaseDescriptor, name string,
+) (*sqlbase.TypeDescriptor, error) {
+	id, ok := dbDesc.FindType(name)
+	if !ok {
+		return nil, nil
+	}
+	typDesc := &sqlbase.TypeDescriptor{}
+	if err := getDescriptorByID(ctx, txn, id, typDesc); err != nil {
+		return nil, err
+	}
+	return typDesc, nil
+}
+
+// getSchemaDesc looks up the descriptor of the user-defined schema with the
+// given name in the given database. It returns nil if there is no such schema.
+func getSchemaDesc(
+	ctx context.Context, txn *client.Txn, dbDesc *DatabaseDescriptor, name string,
+) (*sqlbase.SchemaDescriptor, error) {
+	id, ok := dbDesc.FindSchema(name)
+	if !ok {
+		return nil, nil
+	}
+	scDesc := &sqlbase.SchemaDescriptor{}
+	if err := getDescriptorByID(ctx, txn, id, scDesc); err != nil {
+		return nil, err
+	}
+	return scDesc, nil
+}
+
 // requiredType can be passed to the ResolveExistingObject function to
 // require the returned descriptor to be of a specific type.
 type requiredType int
@@ -262,7 +321,13 @@ func (p *planner) LookupSchema(
 	if err != nil || dbDesc == nil {
 		return false, nil, err
 	}
-	return sc.IsValidSchema(dbDesc, scName), dbDesc, nil
+	if sc.IsValidSchema(dbDesc, scName) {
+		return true, dbDesc, nil
+	}
+	// The database descriptor may come from a cache that does not know yet
+	// about a recently created schema.
+	parentID, err := getNamespaceParentID(ctx, p.txn, dbDesc.ID, scName)
+	return parentID != sqlbase.InvalidID, dbDesc, err
 }
 
 // LookupObject implements the tree.TableNameExistingResolver interface.
@@ -311,6 +376,32 @@ func getDescriptorsFromTargetList(
 		return descs, nil
 	}
 
+	if targets.Schemas != nil {
+		// Schemas are looked up in the current database.
+		if p.CurrentDatabase() == "" {
+			return nil, errNoDatabase
+		}
+		dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /*required*/)
+		if err != nil {
+			return nil, err
+		}
+		descs := make([]sqlbase.DescriptorProto, 0, len(targets.Schemas))
+		for _, schema := range targets.Schemas {
+			scDesc, err := getSchemaDesc(ctx, p.txn, dbDesc, string(schema))
+			if err != nil {
+				return nil, err
+			}
+			if scDesc == nil {
+				return nil, sqlbase.NewUndefinedSchemaError(string(schema))
+			}
+			descs = append(descs, scDesc)
+		}
+		if len(descs) == 0 {
+			return nil, errNoMatch
+		}
+		return descs, nil
+	}
+
 	if len(targets.Tables) == 0 {
 		return nil, errNoTable
 	}
@@ -349,6 +440,11 @@ func (p *planner) getQualifiedTableName(
 		return "", err
 	}
 	tbName := tree.MakeTableName(tree.Name(dbDesc.Name), tree.Name(desc.Name))
+	if desc.SchemaID != 0 {
+		if scName, ok := dbDesc.FindSchemaName(desc.SchemaID); ok {
+			tbName.SchemaName = tree.Name(scName)
+		}
+	}
 	return tbName.String(), nil
 }
 
@@ -378,6 +378,7 @@ func (c *cascader) addRowDeleter(
 		c.tablesByID,
 		nil, /* requestedCol */
 		CheckFKs,
+		c.evalCtx,
 		c.alloc,
 	)
 	if err != nil {
@@ -397,6 +398,7 @@ func (c *cascader) addRowDeleter(
 		ValNeededForCol:  valNeededForCol,
 	}
 	var rowFetcher Fetcher
+	rowFetcher.SetEvalContext(c.evalCtx)
 	if err := rowFetcher.Init(
 		false, /* reverse */
 		false, /* returnRangeInfo */
@@ -438,6 +440,7 @@ func (c *cascader) addRowUpdater(
 		table.Columns,
 		nil, /* requestedCol */
 		UpdaterDefault,
+		c.evalCtx,
 		c.alloc,
 	)
 	if err != nil {
@@ -457,6 +460,7 @@ func (c *cascader) addRowUpdater(
 		ValNeededForCol:  valNeededForCol,
 	}
 	var rowFetcher Fetcher
+	rowFetcher.SetEvalContext(c.evalCtx)
 	if err := rowFetcher.Init(
 		false, /* reverse */
 		false, /* returnRangeInfo */
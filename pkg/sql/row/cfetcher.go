@@ -134,6 +134,11 @@ type CFetcher struct {
 	// If set, GetRangeInfo() can be used to retrieve the accumulated info.
 	returnRangeInfo bool
 
+	// locking and lockWaitPolicy configure the underlying kvBatchFetcher to
+	// lock the keys it reads. See Fetcher.locking.
+	locking        bool
+	lockWaitPolicy roachpb.WaitPolicy
+
 	// traceKV indicates whether or not session tracing is enabled. It is set
 	// when beginning a new scan.
 	traceKV bool
@@ -222,6 +227,11 @@ func (rf *CFetcher) Init(
 	// required.
 	for col, idx := range table.colIdxMap {
 		if tableArgs.ValNeededForCol.Contains(idx) {
+			// Virtual computed columns are not stored in the primary index,
+			// and newColBatchScan does not plan scans that need them.
+			if !table.isSecondaryIndex && table.cols[idx].Virtual {
+				return errors.Errorf("unhandled virtual column %q", table.cols[idx].Name)
+			}
 			// The idx-th column is required.
 			table.neededCols.Add(int(col))
 		}
@@ -326,6 +336,13 @@ func (rf *CFetcher) Init(
 	return nil
 }
 
+// SetLocking configures the CFetcher to lock the rows it reads for the
+// remainder of the transaction. See Fetcher.SetLocking.
+func (rf *CFetcher) SetLocking(waitPolicy roachpb.WaitPolicy) {
+	rf.locking = true
+	rf.lockWaitPolicy = waitPolicy
+}
+
 // StartScan initializes and starts the key-value scan. Can be used multiple
 // times.
 func (rf *CFetcher) StartScan(
@@ -356,7 +373,10 @@ func (rf *CFetcher) StartScan(
 		firstBatchLimit++
 	}
 
-	f, err := makeKVBatchFetcher(txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo)
+	f, err := makeKVBatchFetcher(
+		txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo,
+		rf.locking, rf.lockWaitPolicy,
+	)
 	if err != nil {
 		return err
 	}
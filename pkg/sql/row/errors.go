@@ -145,3 +145,15 @@ func NewUniquenessConstraintViolationError(
 		strings.Join(valStrs, ","),
 		index.Name)
 }
+
+// NewLockNotAvailableError creates an error that represents a failure to lock
+// a row that is locked by another transaction, on behalf of a locking read
+// with the NOWAIT wait policy.
+func NewLockNotAvailableError(wiErr *roachpb.WriteIntentError) error {
+	var key roachpb.Key
+	if len(wiErr.Intents) > 0 {
+		key = wiErr.Intents[0].Key
+	}
+	return pgerror.NewErrorf(pgerror.CodeLockNotAvailableError,
+		"could not obtain lock on row at key %s", key)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/scrub"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -90,6 +91,14 @@ type tableInfo struct {
 	// id pair at the start of the key.
 	knownPrefixLength int
 
+	// The indexes into the cols array of the needed virtual computed columns,
+	// which are not stored in the primary index, and the expressions used to
+	// compute them. virtualDepColsByIdx is the set of indexes into the cols
+	// array of the columns these expressions depend on.
+	virtualColIdxs      []int
+	virtualExprs        []tree.TypedExpr
+	virtualDepColsByIdx util.FastIntSet
+
 	// -- Fields updated during a scan --
 
 	keyValTypes []sqlbase.ColumnType
@@ -99,6 +108,10 @@ type tableInfo struct {
 	row         sqlbase.EncDatumRow
 	decodedRow  tree.Datums
 
+	// virtualIVars is used to evaluate the expressions of virtual computed
+	// columns over the current row.
+	virtualIVars sqlbase.RowIndexedVarContainer
+
 	// The following fields contain MVCC metadata for each row and may be
 	// returned to users of Fetcher immediately after NextRow returns.
 	// They're not important to ordinary consumers of Fetcher that only
@@ -199,10 +212,22 @@ type Fetcher struct {
 	// If set, GetRangeInfo() can be used to retrieve the accumulated info.
 	returnRangeInfo bool
 
+	// locking, if set, causes the underlying kvBatchFetcher to lock the keys
+	// it reads by laying down lock-only intents on them, on behalf of SELECT
+	// ... FOR UPDATE and FOR SHARE. lockWaitPolicy is the policy used for the
+	// keys that are already locked by other transactions. This requires a root
+	// transaction.
+	locking        bool
+	lockWaitPolicy roachpb.WaitPolicy
+
 	// traceKV indicates whether or not session tracing is enabled. It is set
 	// when beginning a new scan.
 	traceKV bool
 
+	// evalCtx is used to compute the values of virtual computed columns. It is
+	// only set by users of Fetcher that may request such columns.
+	evalCtx *tree.EvalContext
+
 	// -- Fields updated during a scan --
 
 	kvFetcher      kvFetcher
@@ -239,6 +264,13 @@ func (rf *Fetcher) Reset() {
 	}
 }
 
+// SetEvalContext configures the Fetcher to compute the values of the virtual
+// computed columns it is asked for when reading from a primary index, which
+// does not store them. It must be called before Init.
+func (rf *Fetcher) SetEvalContext(evalCtx *tree.EvalContext) {
+	rf.evalCtx = evalCtx
+}
+
 // Init sets up a Fetcher for a given table and index. If we are using a
 // non-primary index, tables.ValNeededForCol can only refer to columns in the
 // index.
@@ -331,12 +363,20 @@ func (rf *Fetcher) Init(
 			}
 		}
 
+		valNeededForCol := tableArgs.ValNeededForCol
+		if !table.isSecondaryIndex {
+			valNeededForCol, err = rf.initVirtualCols(&table, valNeededForCol)
+			if err != nil {
+				return err
+			}
+		}
+
 		table.knownPrefixLength = len(sqlbase.MakeIndexKeyPrefix(table.desc.TableDesc(), table.index.ID))
 
 		var indexColumnIDs []sqlbase.ColumnID
 		indexColumnIDs, table.indexColumnDirs = table.index.FullColumnIDs()
 
-		table.neededValueColsByIdx = tableArgs.ValNeededForCol.Copy()
+		table.neededValueColsByIdx = valNeededForCol.Copy()
 		neededIndexCols := 0
 		nIndexCols := len(indexColumnIDs)
 		if cap(table.indexColIdx) >= nIndexCols {
@@ -433,6 +473,90 @@ func (rf *Fetcher) Init(
 	return nil
 }
 
+// initVirtualCols prepares the computation of the needed virtual computed
+// columns of a table read from its primary index. In the set of needed
+// columns, the virtual columns are replaced by the columns their expressions
+// depend on. The resulting set of indexes of needed columns is returned.
+func (rf *Fetcher) initVirtualCols(
+	table *tableInfo, valNeededForCol util.FastIntSet,
+) (util.FastIntSet, error) {
+	var virtualCols []sqlbase.ColumnDescriptor
+	for i := range table.cols {
+		if table.cols[i].Virtual && table.neededCols.Contains(int(table.cols[i].ID)) {
+			table.virtualColIdxs = append(table.virtualColIdxs, i)
+			virtualCols = append(virtualCols, table.cols[i])
+		}
+	}
+	if len(virtualCols) == 0 {
+		return valNeededForCol, nil
+	}
+	if rf.evalCtx == nil {
+		return valNeededForCol, errors.Errorf(
+			"cannot compute virtual column %q without an evaluation context", virtualCols[0].Name)
+	}
+
+	var txCtx transform.ExprTransformContext
+	exprs, err := sqlbase.MakeComputedExprs(virtualCols, table.desc,
+		tree.NewUnqualifiedTableName(tree.Name(table.desc.Name)), &txCtx, rf.evalCtx, false /* addingCols */)
+	if err != nil {
+		return valNeededForCol, err
+	}
+	table.virtualExprs = exprs
+
+	valNeededForCol = valNeededForCol.Copy()
+	for i, idx := range table.virtualColIdxs {
+		table.neededCols.Remove(int(table.cols[idx].ID))
+		valNeededForCol.Remove(idx)
+
+		// The expression refers to the columns of the table descriptor.
+		var v indexedVarCollector
+		tree.WalkExprConst(&v, exprs[i])
+		for ord, ok := v.idxs.Next(0); ok; ord, ok = v.idxs.Next(ord + 1) {
+			id := table.desc.Columns[ord].ID
+			depIdx, ok := table.colIdxMap[id]
+			if !ok {
+				return valNeededForCol, errors.Errorf(
+					"column %d used by virtual column %q not in colIdxMap", id, table.cols[idx].Name)
+			}
+			table.neededCols.Add(int(id))
+			valNeededForCol.Add(depIdx)
+			table.virtualDepColsByIdx.Add(depIdx)
+		}
+	}
+	table.virtualIVars = sqlbase.RowIndexedVarContainer{
+		CurSourceRow: make(tree.Datums, len(table.cols)),
+		Cols:         table.desc.Columns,
+		Mapping:      table.colIdxMap,
+	}
+	return valNeededForCol, nil
+}
+
+// indexedVarCollector collects the indexes of the IndexedVars of an
+// expression.
+type indexedVarCollector struct {
+	idxs util.FastIntSet
+}
+
+var _ tree.Visitor = &indexedVarCollector{}
+
+func (v *indexedVarCollector) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if ivar, ok := expr.(*tree.IndexedVar); ok {
+		v.idxs.Add(ivar.Idx)
+		return false, expr
+	}
+	return true, expr
+}
+
+func (*indexedVarCollector) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// SetLocking configures the Fetcher to lock the rows it reads for the
+// remainder of the transaction, using the given policy for the rows that are
+// locked by other transactions. It must be called after Init.
+func (rf *Fetcher) SetLocking(waitPolicy roachpb.WaitPolicy) {
+	rf.locking = true
+	rf.lockWaitPolicy = waitPolicy
+}
+
 // StartScan initializes and starts the key-value scan. Can be used multiple
 // times.
 func (rf *Fetcher) StartScan(
@@ -463,7 +587,10 @@ func (rf *Fetcher) StartScan(
 		firstBatchLimit++
 	}
 
-	f, err := makeKVBatchFetcher(txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo)
+	f, err := makeKVBatchFetcher(
+		txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo,
+		rf.locking, rf.lockWaitPolicy,
+	)
 	if err != nil {
 		return err
 	}
@@ -1235,7 +1362,7 @@ func (rf *Fetcher) finalizeRow() error {
 	for i := range table.cols {
 		if rf.valueColsFound == table.neededValueCols {
 			// Found all cols - done!
-			return nil
+			break
 		}
 		if table.neededCols.Contains(int(table.cols[i].ID)) && table.row[i].IsUnset() {
 			// If the row was deleted, we'll be missing any non-primary key
@@ -1270,6 +1397,35 @@ func (rf *Fetcher) finalizeRow() error {
 			rf.valueColsFound++
 		}
 	}
+	return rf.computeVirtualCols(table)
+}
+
+// computeVirtualCols fills in the values of the needed virtual computed
+// columns of the current row.
+func (rf *Fetcher) computeVirtualCols(table *tableInfo) error {
+	if len(table.virtualColIdxs) == 0 {
+		return nil
+	}
+	for idx, ok := table.virtualDepColsByIdx.Next(0); ok; idx, ok = table.virtualDepColsByIdx.Next(idx + 1) {
+		if table.row[idx].IsUnset() {
+			table.virtualIVars.CurSourceRow[idx] = tree.DNull
+			continue
+		}
+		if err := table.row[idx].EnsureDecoded(&table.cols[idx].Type, rf.alloc); err != nil {
+			return err
+		}
+		table.virtualIVars.CurSourceRow[idx] = table.row[idx].Datum
+	}
+
+	rf.evalCtx.PushIVarContainer(&table.virtualIVars)
+	defer rf.evalCtx.PopIVarContainer()
+	for i, idx := range table.virtualColIdxs {
+		d, err := table.virtualExprs[i].Eval(rf.evalCtx)
+		if err != nil {
+			return err
+		}
+		table.row[idx] = sqlbase.DatumToEncDatum(table.cols[idx].Type, d)
+	}
 	return nil
 }
 
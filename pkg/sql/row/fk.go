@@ -367,9 +367,16 @@ func (f *fkBatchChecker) runCheck(
 				for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
 					fkValues[valueIdx] = newRow[fk.ids[colID]]
 				}
-				return pgerror.NewErrorf(pgerror.CodeForeignKeyViolationError,
+				violation := pgerror.NewErrorf(pgerror.CodeForeignKeyViolationError,
 					"foreign key violation: value %s not found in %s@%s %s (txn=%s)",
 					fkValues, fk.searchTable.Name, fk.searchIdx.Name, fk.searchIdx.ColumnNames[:fk.prefixLen], f.txn.ID())
+				deferred, err := fk.maybeDefer(newRow, violation)
+				if err != nil {
+					return err
+				}
+				if !deferred {
+					return violation
+				}
 			}
 		case CheckDeletes:
 			// If we're deleting, then there's a violation if the scan found something.
@@ -383,9 +390,16 @@ func (f *fkBatchChecker) runCheck(
 				for valueIdx, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
 					fkValues[valueIdx] = oldRow[fk.ids[colID]]
 				}
-				return pgerror.NewErrorf(pgerror.CodeForeignKeyViolationError,
+				violation := pgerror.NewErrorf(pgerror.CodeForeignKeyViolationError,
 					"foreign key violation: values %v in columns %s referenced in table %q",
 					fkValues, fk.writeIdx.ColumnNames[:fk.prefixLen], fk.searchTable.Name)
+				deferred, err := fk.maybeDefer(oldRow, violation)
+				if err != nil {
+					return err
+				}
+				if !deferred {
+					return violation
+				}
 			}
 		default:
 			log.Fatalf(ctx, "impossible case: baseFKHelper has dir=%v", fk.dir)
@@ -407,11 +421,21 @@ type fkInsertHelper struct {
 
 var errSkipUnusedFK = errors.New("no columns involved in FK included in writer")
 
+// constraintDeferrer returns the ConstraintDeferrer of the given EvalContext,
+// or nil if foreign key checks cannot be deferred.
+func constraintDeferrer(evalCtx *tree.EvalContext) tree.ConstraintDeferrer {
+	if evalCtx == nil {
+		return nil
+	}
+	return evalCtx.ConstraintDeferrer
+}
+
 func makeFKInsertHelper(
 	txn *client.Txn,
 	table *sqlbase.ImmutableTableDescriptor,
 	otherTables TableLookupsByID,
 	colMap map[sqlbase.ColumnID]int,
+	deferrer tree.ConstraintDeferrer,
 	alloc *sqlbase.DatumAlloc,
 ) (fkInsertHelper, error) {
 	h := fkInsertHelper{
@@ -421,7 +445,8 @@ func makeFKInsertHelper(
 	}
 	for _, idx := range table.AllNonDropIndexes() {
 		if idx.ForeignKey.IsSet() {
-			fk, err := makeBaseFKHelper(txn, otherTables, idx, idx.ForeignKey, colMap, alloc, CheckInserts)
+			fk, err := makeBaseFKHelper(
+				txn, table, otherTables, idx, idx.ForeignKey, colMap, deferrer, alloc, CheckInserts)
 			if err == errSkipUnusedFK {
 				continue
 			}
@@ -500,6 +525,7 @@ func makeFKDeleteHelper(
 	table *sqlbase.ImmutableTableDescriptor,
 	otherTables TableLookupsByID,
 	colMap map[sqlbase.ColumnID]int,
+	deferrer tree.ConstraintDeferrer,
 	alloc *sqlbase.DatumAlloc,
 ) (fkDeleteHelper, error) {
 	h := fkDeleteHelper{
@@ -516,7 +542,8 @@ func makeFKDeleteHelper(
 				// and thus does not need to be checked for FK violations.
 				continue
 			}
-			fk, err := makeBaseFKHelper(txn, otherTables, idx, ref, colMap, alloc, CheckDeletes)
+			fk, err := makeBaseFKHelper(
+				txn, table, otherTables, idx, ref, colMap, deferrer, alloc, CheckDeletes)
 			if err == errSkipUnusedFK {
 				continue
 			}
@@ -568,16 +595,17 @@ func makeFKUpdateHelper(
 	table *sqlbase.ImmutableTableDescriptor,
 	otherTables TableLookupsByID,
 	colMap map[sqlbase.ColumnID]int,
+	deferrer tree.ConstraintDeferrer,
 	alloc *sqlbase.DatumAlloc,
 ) (fkUpdateHelper, error) {
 	ret := fkUpdateHelper{
 		indexIDsToCheck: make(map[sqlbase.IndexID]struct{}),
 	}
 	var err error
-	if ret.inbound, err = makeFKDeleteHelper(txn, table, otherTables, colMap, alloc); err != nil {
+	if ret.inbound, err = makeFKDeleteHelper(txn, table, otherTables, colMap, deferrer, alloc); err != nil {
 		return ret, err
 	}
-	ret.outbound, err = makeFKInsertHelper(txn, table, otherTables, colMap, alloc)
+	ret.outbound, err = makeFKInsertHelper(txn, table, otherTables, colMap, deferrer, alloc)
 	ret.outbound.checker = ret.inbound.checker
 	ret.checker = ret.inbound.checker
 	return ret, err
@@ -635,22 +663,37 @@ type baseFKHelper struct {
 	searchTable  *sqlbase.ImmutableTableDescriptor // the table being searched (for err msg)
 	searchIdx    *sqlbase.IndexDescriptor          // the index that must (not) contain a value
 	prefixLen    int
-	writeIdx     sqlbase.IndexDescriptor  // the index we want to modify
-	searchPrefix []byte                   // prefix of keys in searchIdx
-	ids          map[sqlbase.ColumnID]int // col IDs
-	dir          FKCheck                  // direction of check
+	writeTable   *sqlbase.ImmutableTableDescriptor // the table we want to modify
+	writeIdx     sqlbase.IndexDescriptor           // the index we want to modify
+	writeIDs     map[sqlbase.ColumnID]int          // col IDs of the table we want to modify
+	searchPrefix []byte                            // prefix of keys in searchIdx
+	ids          map[sqlbase.ColumnID]int          // col IDs
+	dir          FKCheck                           // direction of check
+	ref          sqlbase.ForeignKeyReference       // the constraint being checked
+	deferrer     tree.ConstraintDeferrer           // nil if checks cannot be deferred
 }
 
 func makeBaseFKHelper(
 	txn *client.Txn,
+	writeTable *sqlbase.ImmutableTableDescriptor,
 	otherTables TableLookupsByID,
 	writeIdx sqlbase.IndexDescriptor,
 	ref sqlbase.ForeignKeyReference,
 	colMap map[sqlbase.ColumnID]int,
+	deferrer tree.ConstraintDeferrer,
 	alloc *sqlbase.DatumAlloc,
 	dir FKCheck,
 ) (baseFKHelper, error) {
-	b := baseFKHelper{txn: txn, writeIdx: writeIdx, searchTable: otherTables[ref.Table].Table, dir: dir}
+	b := baseFKHelper{
+		txn:         txn,
+		writeTable:  writeTable,
+		writeIdx:    writeIdx,
+		writeIDs:    colMap,
+		searchTable: otherTables[ref.Table].Table,
+		dir:         dir,
+		ref:         ref,
+		deferrer:    deferrer,
+	}
 	if b.searchTable == nil {
 		return b, errors.Errorf("referenced table %d not in provided table map %+v", ref.Table, otherTables)
 	}
@@ -664,6 +707,11 @@ func makeBaseFKHelper(
 		b.prefixLen = len(writeIdx.ColumnIDs)
 	}
 	b.searchIdx = searchIdx
+	if dir == CheckDeletes {
+		// ref is a back reference; the constraint's name and deferrability are
+		// only stored on the referencing index.
+		b.ref = searchIdx.ForeignKey
+	}
 	tableArgs := FetcherTableArgs{
 		Desc:             b.searchTable,
 		Index:            b.searchIdx,
@@ -705,6 +753,47 @@ func (f baseFKHelper) spanForValues(values tree.Datums) (roachpb.Span, error) {
 	return roachpb.Span{Key: key, EndKey: key.PrefixEnd()}, nil
 }
 
+// writeSpanForValues returns the span of writeIdx that contains the given row's
+// values in the foreign key columns.
+func (f baseFKHelper) writeSpanForValues(values tree.Datums) (roachpb.Span, error) {
+	prefix := sqlbase.MakeIndexKeyPrefix(f.writeTable.TableDesc(), f.writeIdx.ID)
+	keyBytes, _, err := sqlbase.EncodePartialIndexKey(
+		f.writeTable.TableDesc(), &f.writeIdx, f.prefixLen, f.writeIDs, values, prefix)
+	if err != nil {
+		return roachpb.Span{}, err
+	}
+	key := roachpb.Key(keyBytes)
+	return roachpb.Span{Key: key, EndKey: key.PrefixEnd()}, nil
+}
+
+// maybeDefer queues the check of a violation found for the given row if the
+// foreign key is deferred in the current transaction. It returns false if the
+// violation must be reported right away.
+func (f baseFKHelper) maybeDefer(row tree.Datums, violation error) (bool, error) {
+	if f.deferrer == nil || row == nil ||
+		!f.deferrer.IsConstraintDeferred(f.ref.Name, f.ref.Deferrable, f.ref.InitiallyDeferred) {
+		return false, nil
+	}
+	searchSpan, err := f.spanForValues(row)
+	if err != nil {
+		return false, err
+	}
+	writeSpan, err := f.writeSpanForValues(row)
+	if err != nil {
+		return false, err
+	}
+	check := tree.DeferredConstraintCheck{Name: f.ref.Name, Err: violation}
+	if f.dir == CheckInserts {
+		// The row being written references a row that is missing for now.
+		check.ReferencingSpan, check.ReferencedSpan = writeSpan, searchSpan
+	} else {
+		// The row being removed is still referenced.
+		check.ReferencingSpan, check.ReferencedSpan = searchSpan, writeSpan
+	}
+	f.deferrer.DeferConstraintCheck(check)
+	return true, nil
+}
+
 func (f baseFKHelper) span() roachpb.Span {
 	key := roachpb.Key(f.searchPrefix)
 	return roachpb.Span{Key: key, EndKey: key.PrefixEnd()}
@@ -17,6 +17,7 @@ package row
 import (
 	"sort"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
@@ -30,6 +31,12 @@ type rowHelper struct {
 	Indexes      []sqlbase.IndexDescriptor
 	indexEntries []sqlbase.IndexEntry
 
+	// evalCtx is used to evaluate the predicates of partial indexes. If it is
+	// nil, the predicates are not evaluated and every row is considered to
+	// belong to every partial index; this is only correct for callers that
+	// remove whole indexes or tables.
+	evalCtx *tree.EvalContext
+
 	// Computed during initialization for pretty-printing.
 	primIndexValDirs []encoding.Direction
 	secIndexValDirs  [][]encoding.Direction
@@ -38,12 +45,16 @@ type rowHelper struct {
 	primaryIndexKeyPrefix []byte
 	primaryIndexCols      map[sqlbase.ColumnID]struct{}
 	sortedColumnFamilies  map[sqlbase.FamilyID][]sqlbase.ColumnID
+	partialIndexPreds     []tree.TypedExpr
+	partialIndexIVars     sqlbase.RowIndexedVarContainer
 }
 
 func newRowHelper(
-	desc *sqlbase.ImmutableTableDescriptor, indexes []sqlbase.IndexDescriptor,
+	desc *sqlbase.ImmutableTableDescriptor,
+	indexes []sqlbase.IndexDescriptor,
+	evalCtx *tree.EvalContext,
 ) rowHelper {
-	rh := rowHelper{TableDesc: desc, Indexes: indexes}
+	rh := rowHelper{TableDesc: desc, Indexes: indexes, evalCtx: evalCtx}
 
 	// Pre-compute the encoding directions of the index key values for
 	// pretty-printing in traces.
@@ -93,9 +104,91 @@ func (rh *rowHelper) encodeSecondaryIndexes(
 	if err != nil {
 		return nil, err
 	}
+	if err := rh.clearPartialIndexEntries(colIDtoRowIndex, values); err != nil {
+		return nil, err
+	}
 	return rh.indexEntries, nil
 }
 
+// clearPartialIndexEntries replaces the entries of the partial indexes whose
+// predicate the row doesn't satisfy with empty entries, which the callers of
+// encodeSecondaryIndexes must skip. Partial indexes are never inverted, so
+// the entries being cleared are always the ones at the position of their
+// index in rh.Indexes.
+func (rh *rowHelper) clearPartialIndexEntries(
+	colIDtoRowIndex map[sqlbase.ColumnID]int, values []tree.Datum,
+) error {
+	if rh.evalCtx == nil {
+		return nil
+	}
+	if rh.partialIndexPreds == nil {
+		preds, err := sqlbase.MakePartialIndexExprs(rh.Indexes, rh.TableDesc, rh.evalCtx)
+		if err != nil {
+			return err
+		}
+		if preds == nil {
+			// Remember that there's nothing to evaluate.
+			preds = []tree.TypedExpr{}
+		}
+		rh.partialIndexPreds = preds
+		rh.partialIndexIVars.Cols = rh.TableDesc.Columns
+	}
+	if len(rh.partialIndexPreds) == 0 {
+		return nil
+	}
+	rh.partialIndexIVars.CurSourceRow = values
+	rh.partialIndexIVars.Mapping = colIDtoRowIndex
+	for i, pred := range rh.partialIndexPreds {
+		if pred == nil {
+			continue
+		}
+		ok, err := sqlbase.EvalPartialIndexPredicate(rh.evalCtx, pred, &rh.partialIndexIVars)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			rh.indexEntries[i] = sqlbase.IndexEntry{}
+		}
+	}
+	return nil
+}
+
+// queueUniqueCheck queues the check of the deferrable UNIQUE constraint of
+// index for a row written with the given values. The check runs when the
+// transaction commits if the constraint is deferred, and when the statement
+// completes otherwise. Rows with a NULL in the unique columns never conflict
+// and are not checked. Nothing is checked without a ConstraintDeferrer, which
+// is only the case for callers that don't write new values.
+func (rh *rowHelper) queueUniqueCheck(
+	index *sqlbase.IndexDescriptor, colIDtoRowIndex map[sqlbase.ColumnID]int, values []tree.Datum,
+) error {
+	deferrer := constraintDeferrer(rh.evalCtx)
+	if deferrer == nil {
+		return nil
+	}
+	key, containsNull, err := sqlbase.EncodePartialIndexKey(
+		rh.TableDesc.TableDesc(), index, len(index.ColumnIDs), colIDtoRowIndex, values,
+		sqlbase.MakeIndexKeyPrefix(rh.TableDesc.TableDesc(), index.ID))
+	if err != nil || containsNull {
+		return err
+	}
+	vals := make([]tree.Datum, len(index.ColumnIDs))
+	for i, colID := range index.ColumnIDs {
+		vals[i] = values[colIDtoRowIndex[colID]]
+	}
+	check := tree.DeferredConstraintCheck{
+		Name:       index.Name,
+		UniqueSpan: roachpb.Span{Key: key, EndKey: roachpb.Key(key).PrefixEnd()},
+		Err:        NewUniquenessConstraintViolationError(index, vals),
+	}
+	if deferrer.IsConstraintDeferred(index.Name, true /* deferrable */, index.InitiallyDeferred) {
+		deferrer.DeferConstraintCheck(check)
+	} else {
+		deferrer.QueueStatementConstraintCheck(check)
+	}
+	return nil
+}
+
 // skipColumnInPK returns true if the value at column colID does not need
 // to be encoded because it is already part of the primary key. Composite
 // datums are considered too, so a composite datum in a PK will return false.
@@ -53,6 +53,11 @@ type txnKVFetcher struct {
 	// returnRangeInfo, if set, causes the kvBatchFetcher to populate rangeInfos.
 	// See also rowFetcher.returnRangeInfo.
 	returnRangeInfo bool
+	// locking, if set, causes the kvBatchFetcher to lock all the keys it
+	// reads, using lockWaitPolicy for the keys that are already locked by
+	// other transactions. See also Fetcher.locking.
+	locking        bool
+	lockWaitPolicy roachpb.WaitPolicy
 
 	fetchEnd bool
 	batchIdx int
@@ -134,6 +139,11 @@ func (f *txnKVFetcher) getBatchSizeForIdx(batchIdx int) int64 {
 // Subsequent batches are larger, up to kvBatchSize.
 //
 // Batch limits can only be used if the spans are ordered.
+//
+// If locking is true, the fetched keys are locked by the scans, which lay down
+// lock-only intents on them; this requires a root transaction. lockWaitPolicy
+// determines what the scans do when they encounter a key that is locked by
+// another transaction.
 func makeKVBatchFetcher(
 	txn *client.Txn,
 	spans roachpb.Spans,
@@ -141,6 +151,8 @@ func makeKVBatchFetcher(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	returnRangeInfo bool,
+	locking bool,
+	lockWaitPolicy roachpb.WaitPolicy,
 ) (txnKVFetcher, error) {
 	if firstBatchLimit < 0 || (!useBatchLimit && firstBatchLimit != 0) {
 		return txnKVFetcher{}, errors.Errorf("invalid batch limit %d (useBatchLimit: %t)",
@@ -192,6 +204,8 @@ func makeKVBatchFetcher(
 		useBatchLimit:   useBatchLimit,
 		firstBatchLimit: firstBatchLimit,
 		returnRangeInfo: returnRangeInfo,
+		locking:         locking,
+		lockWaitPolicy:  lockWaitPolicy,
 	}, nil
 }
 
@@ -200,11 +214,17 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 	var ba roachpb.BatchRequest
 	ba.Header.MaxSpanRequestKeys = f.getBatchSize()
 	ba.Header.ReturnRangeInfo = f.returnRangeInfo
+	keyLocking := roachpb.NON_LOCKING
+	if f.locking {
+		keyLocking = roachpb.EXCLUSIVE_LOCKING
+		ba.Header.WaitPolicy = f.lockWaitPolicy
+	}
 	ba.Requests = make([]roachpb.RequestUnion, len(f.spans))
 	if f.reverse {
 		scans := make([]roachpb.ReverseScanRequest, len(f.spans))
 		for i := range f.spans {
 			scans[i].ScanFormat = roachpb.BATCH_RESPONSE
+			scans[i].KeyLocking = keyLocking
 			scans[i].SetSpan(f.spans[i])
 			ba.Requests[i].MustSetInner(&scans[i])
 		}
@@ -212,6 +232,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 		scans := make([]roachpb.ScanRequest, len(f.spans))
 		for i := range f.spans {
 			scans[i].ScanFormat = roachpb.BATCH_RESPONSE
+			scans[i].KeyLocking = keyLocking
 			scans[i].SetSpan(f.spans[i])
 			ba.Requests[i].MustSetInner(&scans[i])
 		}
@@ -239,6 +260,10 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 
 	br, err := f.txn.Send(ctx, ba)
 	if err != nil {
+		if wiErr, ok := err.GetDetail().(*roachpb.WriteIntentError); ok &&
+			f.locking && f.lockWaitPolicy == roachpb.WaitPolicy_ERROR {
+			return NewLockNotAvailableError(wiErr)
+		}
 		return err.GoError()
 	}
 	if br != nil {
@@ -54,17 +54,19 @@ type Inserter struct {
 
 // MakeInserter creates a Inserter for the given table.
 //
-// insertCols must contain every column in the primary key.
+// insertCols must contain every column in the primary key. evalCtx is used to
+// evaluate the predicates of partial indexes.
 func MakeInserter(
 	txn *client.Txn,
 	tableDesc *sqlbase.ImmutableTableDescriptor,
 	fkTables TableLookupsByID,
 	insertCols []sqlbase.ColumnDescriptor,
 	checkFKs checkFKConstraints,
+	evalCtx *tree.EvalContext,
 	alloc *sqlbase.DatumAlloc,
 ) (Inserter, error) {
 	ri := Inserter{
-		Helper:                newRowHelper(tableDesc, tableDesc.WritableIndexes),
+		Helper:                newRowHelper(tableDesc, tableDesc.WritableIndexes, evalCtx),
 		InsertCols:            insertCols,
 		InsertColIDtoRowIndex: ColIDtoRowIndexFromCols(insertCols),
 		marshaled:             make([]roachpb.Value, len(insertCols)),
@@ -79,7 +81,7 @@ func MakeInserter(
 	if checkFKs == CheckFKs {
 		var err error
 		if ri.Fks, err = makeFKInsertHelper(txn, tableDesc, fkTables,
-			ri.InsertColIDtoRowIndex, alloc); err != nil {
+			ri.InsertColIDtoRowIndex, constraintDeferrer(evalCtx), alloc); err != nil {
 			return ri, err
 		}
 	}
@@ -191,7 +193,20 @@ func (ri *Inserter) InsertRow(
 	putFn = insertInvertedPutFn
 	for i := range secondaryIndexEntries {
 		e := &secondaryIndexEntries[i]
+		if len(e.Key) == 0 {
+			// The row doesn't satisfy the predicate of this partial index.
+			continue
+		}
 		putFn(ctx, b, &e.Key, &e.Value, traceKV)
+		// Inverted indexes can have several entries past the end of
+		// ri.Helper.Indexes, but they are never unique.
+		if i < len(ri.Helper.Indexes) && ri.Helper.Indexes[i].DeferrableUnique {
+			if err := ri.Helper.queueUniqueCheck(
+				&ri.Helper.Indexes[i], ri.InsertColIDtoRowIndex, values,
+			); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -400,7 +415,7 @@ func MakeUpdater(
 	alloc *sqlbase.DatumAlloc,
 ) (Updater, error) {
 	rowUpdater, err := makeUpdaterWithoutCascader(
-		txn, tableDesc, fkTables, updateCols, requestedCols, updateType, alloc,
+		txn, tableDesc, fkTables, updateCols, requestedCols, updateType, evalCtx, alloc,
 	)
 	if err != nil {
 		return Updater{}, err
@@ -429,6 +444,7 @@ func makeUpdaterWithoutCascader(
 	updateCols []sqlbase.ColumnDescriptor,
 	requestedCols []sqlbase.ColumnDescriptor,
 	updateType rowUpdaterType,
+	evalCtx *tree.EvalContext,
 	alloc *sqlbase.DatumAlloc,
 ) (Updater, error) {
 	updateColIDtoRowIndex := ColIDtoRowIndexFromCols(updateCols)
@@ -456,6 +472,13 @@ func makeUpdaterWithoutCascader(
 		if primaryKeyColChange {
 			return true
 		}
+		// If a column referenced by the predicate of a partial index changed,
+		// the row may be entering or leaving the index.
+		for _, id := range index.PredicateColumnIDs {
+			if _, ok := updateColIDtoRowIndex[id]; ok {
+				return true
+			}
+		}
 		return index.RunOverAllColumns(func(id sqlbase.ColumnID) error {
 			if _, ok := updateColIDtoRowIndex[id]; ok {
 				return returnTruePseudoError
@@ -487,12 +510,12 @@ func makeUpdaterWithoutCascader(
 
 	var deleteOnlyHelper *rowHelper
 	if len(deleteOnlyIndexes) > 0 {
-		rh := newRowHelper(tableDesc, deleteOnlyIndexes)
+		rh := newRowHelper(tableDesc, deleteOnlyIndexes, evalCtx)
 		deleteOnlyHelper = &rh
 	}
 
 	ru := Updater{
-		Helper:                newRowHelper(tableDesc, writeIndexes),
+		Helper:                newRowHelper(tableDesc, writeIndexes, evalCtx),
 		DeleteHelper:          deleteOnlyHelper,
 		UpdateCols:            updateCols,
 		updateColIDtoRowIndex: updateColIDtoRowIndex,
@@ -507,14 +530,14 @@ func makeUpdaterWithoutCascader(
 		// them, so request them all.
 		var err error
 		if ru.rd, err = makeRowDeleterWithoutCascader(
-			txn, tableDesc, fkTables, tableCols, SkipFKs, alloc,
+			txn, tableDesc, fkTables, tableCols, SkipFKs, evalCtx, alloc,
 		); err != nil {
 			return Updater{}, err
 		}
 		ru.FetchCols = ru.rd.FetchCols
 		ru.FetchColIDtoRowIndex = ColIDtoRowIndexFromCols(ru.FetchCols)
 		if ru.ri, err = MakeInserter(txn, tableDesc, fkTables,
-			tableCols, SkipFKs, alloc); err != nil {
+			tableCols, SkipFKs, evalCtx, alloc); err != nil {
 			return Updater{}, err
 		}
 	} else {
@@ -564,22 +587,34 @@ func makeUpdaterWithoutCascader(
 		}
 
 		// Fetch all columns from indices that are being update so that they can
-		// be used to create the new kv pairs for those indices.
+		// be used to create the new kv pairs for those indices. The columns
+		// referenced by the predicates of partial indexes are needed to decide
+		// whether the row belongs in them.
 		for _, index := range writeIndexes {
 			if err := index.RunOverAllColumns(maybeAddCol); err != nil {
 				return Updater{}, err
 			}
+			for _, colID := range index.PredicateColumnIDs {
+				if err := maybeAddCol(colID); err != nil {
+					return Updater{}, err
+				}
+			}
 		}
 		for _, index := range deleteOnlyIndexes {
 			if err := index.RunOverAllColumns(maybeAddCol); err != nil {
 				return Updater{}, err
 			}
+			for _, colID := range index.PredicateColumnIDs {
+				if err := maybeAddCol(colID); err != nil {
+					return Updater{}, err
+				}
+			}
 		}
 	}
 
 	var err error
 	if ru.Fks, err = makeFKUpdateHelper(txn, tableDesc, fkTables,
-		ru.FetchColIDtoRowIndex, alloc); err != nil {
+		ru.FetchColIDtoRowIndex, constraintDeferrer(evalCtx), alloc); err != nil {
 		return Updater{}, err
 	}
 	return ru, nil
@@ -737,13 +772,29 @@ func (ru *Updater) UpdateRow(
 			continue
 		}
 
+		// An empty key means that the row doesn't satisfy the predicate of a
+		// partial index, before or after the update.
 		var expValue interface{}
 		if !bytes.Equal(newSecondaryIndexEntry.Key, oldSecondaryIndexEntry.Key) {
 			ru.Fks.addCheckForIndex(ru.Helper.Indexes[i].ID, ru.Helper.Indexes[i].Type)
-			if traceKV {
-				log.VEventf(ctx, 2, "Del %s", keys.PrettyPrint(ru.Helper.secIndexValDirs[i], oldSecondaryIndexEntry.Key))
+			if len(oldSecondaryIndexEntry.Key) > 0 {
+				if traceKV {
+					log.VEventf(ctx, 2, "Del %s", keys.PrettyPrint(ru.Helper.secIndexValDirs[i], oldSecondaryIndexEntry.Key))
+				}
+				batch.Del(oldSecondaryIndexEntry.Key)
+			}
+			if len(newSecondaryIndexEntry.Key) == 0 {
+				continue
+			}
+			if index.DeferrableUnique {
+				if err := ru.Helper.queueUniqueCheck(
+					&ru.Helper.Indexes[i], ru.FetchColIDtoRowIndex, ru.newValues,
+				); err != nil {
+					return nil, err
+				}
 			}
-			batch.Del(oldSecondaryIndexEntry.Key)
+		} else if len(newSecondaryIndexEntry.Key) == 0 {
+			continue
 		} else if !newSecondaryIndexEntry.Value.EqualData(oldSecondaryIndexEntry.Value) {
 			expValue = &oldSecondaryIndexEntry.Value
 		} else {
@@ -760,6 +811,9 @@ func (ru *Updater) UpdateRow(
 	// indexed will be handled separately.
 	if ru.DeleteHelper != nil {
 		for _, deletedSecondaryIndexEntry := range deleteOldSecondaryIndexEntries {
+			if len(deletedSecondaryIndexEntry.Key) == 0 {
+				continue
+			}
 			if traceKV {
 				log.VEventf(ctx, 2, "Del %s", deletedSecondaryIndexEntry.Key)
 			}
@@ -850,7 +904,7 @@ func MakeDeleter(
 	alloc *sqlbase.DatumAlloc,
 ) (Deleter, error) {
 	rowDeleter, err := makeRowDeleterWithoutCascader(
-		txn, tableDesc, fkTables, requestedCols, checkFKs, alloc,
+		txn, tableDesc, fkTables, requestedCols, checkFKs, evalCtx, alloc,
 	)
 	if err != nil {
 		return Deleter{}, err
@@ -873,6 +927,7 @@ func makeRowDeleterWithoutCascader(
 	fkTables TableLookupsByID,
 	requestedCols []sqlbase.ColumnDescriptor,
 	checkFKs checkFKConstraints,
+	evalCtx *tree.EvalContext,
 	alloc *sqlbase.DatumAlloc,
 ) (Deleter, error) {
 	indexes := tableDesc.DeletableIndexes
@@ -908,17 +963,22 @@ func makeRowDeleterWithoutCascader(
 				return Deleter{}, err
 			}
 		}
+		for _, colID := range index.PredicateColumnIDs {
+			if err := maybeAddCol(colID); err != nil {
+				return Deleter{}, err
+			}
+		}
 	}
 
 	rd := Deleter{
-		Helper:               newRowHelper(tableDesc, indexes),
+		Helper:               newRowHelper(tableDesc, indexes, evalCtx),
 		FetchCols:            fetchCols,
 		FetchColIDtoRowIndex: fetchColIDtoRowIndex,
 	}
 	if checkFKs == CheckFKs {
 		var err error
 		if rd.Fks, err = makeFKDeleteHelper(txn, tableDesc, fkTables,
-			fetchColIDtoRowIndex, alloc); err != nil {
+			fetchColIDtoRowIndex, constraintDeferrer(evalCtx), alloc); err != nil {
 			return Deleter{}, err
 		}
 	}
@@ -944,6 +1004,10 @@ func (rd *Deleter) DeleteRow(
 
 	// Delete the row from any secondary indices.
 	for i, secondaryIndexEntry := range secondaryIndexEntries {
+		if len(secondaryIndexEntry.Key) == 0 {
+			// The row doesn't satisfy the predicate of this partial index.
+			continue
+		}
 		if traceKV {
 			log.VEventf(ctx, 2, "Del %s", keys.PrettyPrint(rd.Helper.secIndexValDirs[i], secondaryIndexEntry.Key))
 		}
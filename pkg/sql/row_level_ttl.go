@@ -0,0 +1,516 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// Row-level TTL lets the rows of a table expire. A table created with the
+// ttl_expire_after storage parameter gets a hidden crdb_internal_expiration
+// column, whose default value is the insertion time plus the interval given
+// to the parameter, and a job which periodically deletes the rows whose
+// expiration time has passed. The expiration column is an ordinary column: it
+// can be updated to extend or shorten the life of a row.
+//
+// Each pass of the job walks the primary index of the table one range at a
+// time and deletes the expired rows of each batch in a transaction of its
+// own, so that it never holds many locks at once. Deletions check and cascade
+// foreign keys like DELETE does. The job paces itself with the rate limit of
+// bulk IO writes (kv.bulk_io_write.max_rate), so that it competes with
+// foreground traffic no more than the other bulk operations do.
+
+const (
+	// ttlExpireAfterParam is the storage parameter that makes the rows of a
+	// table expire.
+	ttlExpireAfterParam = "ttl_expire_after"
+	// rowLevelTTLColumnName is the name of the hidden column holding the
+	// expiration time of each row.
+	rowLevelTTLColumnName = "crdb_internal_expiration"
+)
+
+var rowLevelTTLJobInterval = settings.RegisterNonNegativeDurationSetting(
+	"sql.ttl.job_interval",
+	"the amount of time a row-level TTL job waits between passes over its table",
+	5*time.Minute,
+)
+
+var rowLevelTTLDeleteBatchSize = settings.RegisterPositiveIntSetting(
+	"sql.ttl.delete_batch_size",
+	"the number of rows examined, and deleted if expired, in each transaction of a row-level TTL job",
+	100,
+)
+
+var (
+	metaRowLevelTTLRowsDeleted = metric.Metadata{
+		Name:        "sql.ttl.rows_deleted",
+		Help:        "Expired rows deleted by row-level TTL jobs",
+		Measurement: "Rows",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRowLevelTTLBytesDeleted = metric.Metadata{
+		Name:        "sql.ttl.bytes_deleted",
+		Help:        "Size of the expired rows deleted by row-level TTL jobs",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaRowLevelTTLDeleteBatches = metric.Metadata{
+		Name:        "sql.ttl.delete_batches",
+		Help:        "Transactions run by row-level TTL jobs to delete expired rows",
+		Measurement: "Batches",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRowLevelTTLDeleteNanos = metric.Metadata{
+		Name:        "sql.ttl.delete_nanos",
+		Help:        "Time spent by row-level TTL jobs scanning and deleting expired rows",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaRowLevelTTLWaitNanos = metric.Metadata{
+		Name:        "sql.ttl.rate_limit_wait_nanos",
+		Help:        "Time spent by row-level TTL jobs waiting on the bulk IO write rate limit",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+)
+
+// RowLevelTTLMetrics are for production monitoring of row-level TTL jobs.
+type RowLevelTTLMetrics struct {
+	RowsDeleted   *metric.Counter
+	BytesDeleted  *metric.Counter
+	DeleteBatches *metric.Counter
+	DeleteNanos   *metric.Counter
+	WaitNanos     *metric.Counter
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (*RowLevelTTLMetrics) MetricStruct() {}
+
+func makeRowLevelTTLMetrics(time.Duration) metric.Struct {
+	return &RowLevelTTLMetrics{
+		RowsDeleted:   metric.NewCounter(metaRowLevelTTLRowsDeleted),
+		BytesDeleted:  metric.NewCounter(metaRowLevelTTLBytesDeleted),
+		DeleteBatches: metric.NewCounter(metaRowLevelTTLDeleteBatches),
+		DeleteNanos:   metric.NewCounter(metaRowLevelTTLDeleteNanos),
+		WaitNanos:     metric.NewCounter(metaRowLevelTTLWaitNanos),
+	}
+}
+
+func init() {
+	jobs.MakeRowLevelTTLMetricsHook = makeRowLevelTTLMetrics
+	jobs.AddResumeHook(rowLevelTTLResumeHook)
+}
+
+// addRowLevelTTL processes the ttl_expire_after storage parameter of a table
+// being created: it adds the hidden expiration column to the descriptor and
+// records the interval. The ID of the column is filled in by the caller once
+// IDs have been allocated.
+func addRowLevelTTL(
+	desc *sqlbase.MutableTableDescriptor,
+	value tree.Expr,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+) error {
+	if value == nil {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"storage parameter %q requires a value", ttlExpireAfterParam)
+	}
+	typedExpr, err := tree.TypeCheckAndRequire(value, semaCtx, types.String, ttlExpireAfterParam)
+	if err != nil {
+		return err
+	}
+	d, err := typedExpr.Eval(evalCtx)
+	if err != nil {
+		return err
+	}
+	s, ok := tree.AsDString(d)
+	if !ok {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"storage parameter %q requires a value", ttlExpireAfterParam)
+	}
+	interval, err := tree.ParseDInterval(string(s))
+	if err != nil {
+		return err
+	}
+	if interval.Duration.Compare(duration.Duration{}) <= 0 {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"value of storage parameter %q must be a positive interval", ttlExpireAfterParam)
+	}
+
+	def := &tree.ColumnTableDef{Name: rowLevelTTLColumnName, Type: coltypes.TimestampWithTZ}
+	def.Nullable.Nullability = tree.NotNull
+	def.DefaultExpr.Expr = &tree.BinaryExpr{
+		Operator: tree.Plus,
+		Left:     &tree.FuncExpr{Func: tree.WrapFunction("current_timestamp")},
+		Right:    interval,
+	}
+	col, _, _, err := sqlbase.MakeColumnDefDescs(def, semaCtx, evalCtx)
+	if err != nil {
+		return err
+	}
+	col.Hidden = true
+	desc.AddColumn(*col)
+	desc.RowLevelTTL = &sqlbase.TableDescriptor_RowLevelTTL{ExpireAfter: string(s)}
+	return nil
+}
+
+// createRowLevelTTLJob creates the job deleting the expired rows of a table
+// created with row-level TTL. The job is adopted by the registry once the
+// transaction creating the table commits.
+func (p *planner) createRowLevelTTLJob(
+	ctx context.Context, desc *sqlbase.MutableTableDescriptor,
+) error {
+	job := p.ExecCfg().JobRegistry.NewJob(jobs.Record{
+		Description:   fmt.Sprintf("row-level TTL of table %s", tree.NameString(desc.Name)),
+		Username:      p.User(),
+		DescriptorIDs: sqlbase.IDs{desc.ID},
+		Details:       jobspb.RowLevelTTLDetails{TableID: desc.ID},
+		Progress:      jobspb.RowLevelTTLProgress{},
+	})
+	return job.WithTxn(p.txn).Created(ctx)
+}
+
+type rowLevelTTLResumer struct{}
+
+// errRowLevelTTLDone is returned by a pass of a row-level TTL job when the
+// table was dropped or no longer has row-level TTL.
+var errRowLevelTTLDone = errors.New("row-level TTL is no longer enabled")
+
+// Resume is part of the jobs.Resumer interface. The job runs until its
+// table is dropped or it is canceled.
+func (r *rowLevelTTLResumer) Resume(
+	ctx context.Context, job *jobs.Job, planHookState interface{}, _ chan<- tree.Datums,
+) error {
+	execCfg := planHookState.(PlanHookState).ExecCfg()
+	details := job.Details().(jobspb.RowLevelTTLDetails)
+	sv := &execCfg.Settings.SV
+	metrics, _ := execCfg.JobRegistry.MetricsStruct().RowLevelTTL.(*RowLevelTTLMetrics)
+
+	limiter := rate.NewLimiter(
+		rate.Limit(storagebase.BulkIOWriteLimit.Get(sv)), storagebase.BulkIOWriteBurst)
+	for {
+		deleted, err := rowLevelTTLPass(ctx, execCfg, details.TableID, limiter, metrics)
+		if err == errRowLevelTTLDone {
+			log.Infof(ctx, "row-level TTL job %d: table %d was dropped or no longer has row-level TTL",
+				*job.ID(), details.TableID)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := job.RunningStatus(ctx, func(_ context.Context, d jobspb.Details) (jobs.RunningStatus, error) {
+			progress := d.(*jobspb.Progress_RowLevelTTL).RowLevelTTL
+			progress.RowsDeleted += deleted
+			progress.LastPassMicros = timeutil.Now().UnixNano() / time.Microsecond.Nanoseconds()
+			return jobs.RunningStatus(fmt.Sprintf(
+				"deleted %d expired rows in the last pass", deleted)), nil
+		}); err != nil {
+			return err
+		}
+
+		select {
+		case <-time.After(rowLevelTTLJobInterval.Get(sv)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// rowLevelTTLPass deletes the rows of the table that expired before the
+// start of the pass, one range at a time, and returns the number of rows
+// deleted.
+func rowLevelTTLPass(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	tableID sqlbase.ID,
+	limiter *rate.Limiter,
+	metrics *RowLevelTTLMetrics,
+) (int64, error) {
+	cutoff := tree.MakeDTimestampTZ(timeutil.Now(), time.Microsecond)
+
+	var spans []roachpb.Span
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		desc, err := getRowLevelTTLTableDesc(ctx, txn, tableID)
+		if err != nil {
+			return err
+		}
+		spans, err = rangeAlignedSpans(ctx, txn, desc.PrimaryIndexSpan())
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, span := range spans {
+		for span.Key != nil {
+			start := timeutil.Now()
+			var n, size int64
+			var resume roachpb.Span
+			if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+				var err error
+				n, size, resume, err = deleteExpiredRows(
+					ctx, txn, execCfg, tableID, span, cutoff,
+					rowLevelTTLDeleteBatchSize.Get(&execCfg.Settings.SV))
+				return err
+			}); err != nil {
+				return deleted, err
+			}
+			span = resume
+			deleted += n
+			if metrics != nil {
+				metrics.RowsDeleted.Inc(n)
+				metrics.BytesDeleted.Inc(size)
+				metrics.DeleteBatches.Inc(1)
+				metrics.DeleteNanos.Inc(timeutil.Since(start).Nanoseconds())
+			}
+			if err := waitRowLevelTTLRateLimit(ctx, execCfg.Settings, limiter, size, metrics); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// waitRowLevelTTLRateLimit waits until the deletion of size bytes is allowed
+// by the bulk IO write rate limit. As for the writes of the stores, the cost
+// is capped at the burst of the limiter.
+func waitRowLevelTTLRateLimit(
+	ctx context.Context,
+	st *cluster.Settings,
+	limiter *rate.Limiter,
+	size int64,
+	metrics *RowLevelTTLMetrics,
+) error {
+	// Pick up changes to the setting; the limiter is private to the job.
+	limiter.SetLimit(rate.Limit(storagebase.BulkIOWriteLimit.Get(&st.SV)))
+	cost := int(size)
+	if cost > storagebase.BulkIOWriteBurst {
+		cost = storagebase.BulkIOWriteBurst
+	}
+	begin := timeutil.Now()
+	if err := limiter.WaitN(ctx, cost); err != nil {
+		return err
+	}
+	if metrics != nil {
+		metrics.WaitNanos.Inc(timeutil.Since(begin).Nanoseconds())
+	}
+	return nil
+}
+
+// getRowLevelTTLTableDesc reads the descriptor of the table of a row-level
+// TTL job. It returns errRowLevelTTLDone if the table is gone or no longer
+// has row-level TTL.
+func getRowLevelTTLTableDesc(
+	ctx context.Context, txn *client.Txn, tableID sqlbase.ID,
+) (*sqlbase.TableDescriptor, error) {
+	desc, err := sqlbase.GetTableDescFromID(ctx, txn, tableID)
+	if err == sqlbase.ErrDescriptorNotFound {
+		return nil, errRowLevelTTLDone
+	}
+	if err != nil {
+		return nil, err
+	}
+	if desc.Dropped() || desc.RowLevelTTL == nil {
+		return nil, errRowLevelTTLDone
+	}
+	return desc, nil
+}
+
+// rangeAlignedSpans splits span at the boundaries of the ranges it
+// overlaps, so that each batch of deletions only touches a single range.
+func rangeAlignedSpans(
+	ctx context.Context, txn *client.Txn, span roachpb.Span,
+) ([]roachpb.Span, error) {
+	kvs, err := ScanMetaKVs(ctx, txn, span)
+	if err != nil {
+		return nil, err
+	}
+	rspan := roachpb.RSpan{Key: keys.MustAddr(span.Key), EndKey: keys.MustAddr(span.EndKey)}
+	spans := make([]roachpb.Span, 0, len(kvs))
+	for _, kv := range kvs {
+		var rangeDesc roachpb.RangeDescriptor
+		if err := kv.ValueProto(&rangeDesc); err != nil {
+			return nil, err
+		}
+		s, err := rspan.Intersect(&rangeDesc)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, s.AsRawSpanWithNoLocals())
+	}
+	return spans, nil
+}
+
+// deleteExpiredRows examines up to limit rows of span and deletes those
+// whose expiration time is before cutoff. It returns the number of rows
+// deleted, their approximate size in bytes and the part of span left to
+// examine, whose Key is nil if the span was exhausted.
+func deleteExpiredRows(
+	ctx context.Context,
+	txn *client.Txn,
+	execCfg *ExecutorConfig,
+	tableID sqlbase.ID,
+	span roachpb.Span,
+	cutoff *tree.DTimestampTZ,
+	limit int64,
+) (deleted int64, size int64, resume roachpb.Span, err error) {
+	resume = span
+	tableDesc, err := getRowLevelTTLTableDesc(ctx, txn, tableID)
+	if err != nil {
+		return 0, 0, resume, err
+	}
+	desc := sqlbase.NewImmutableTableDescriptor(*tableDesc)
+	ttlCol, err := desc.FindActiveColumnByID(desc.RowLevelTTL.ColumnID)
+	if err != nil {
+		return 0, 0, resume, err
+	}
+
+	// The planner is only used to look up the tables involved in foreign key
+	// checks and cascades, and to analyze their CHECK constraints.
+	p, cleanup := newInternalPlanner("row-level-ttl", txn, security.RootUser, &MemoryMetrics{}, execCfg)
+	defer cleanup()
+	fkTables, err := row.TablesNeededForFKs(
+		ctx,
+		*desc,
+		row.CheckDeletes,
+		func(ctx context.Context, id sqlbase.ID) (row.TableLookup, error) {
+			t, err := sqlbase.GetTableDescFromID(ctx, txn, id)
+			if err != nil {
+				return row.TableLookup{}, err
+			}
+			if t.Adding() {
+				return row.TableLookup{IsAdding: true}, nil
+			}
+			return row.TableLookup{Table: sqlbase.NewImmutableTableDescriptor(*t)}, nil
+		},
+		row.NoCheckPrivilege,
+		p.analyzeExpr,
+	)
+	if err != nil {
+		return 0, 0, resume, err
+	}
+
+	var alloc sqlbase.DatumAlloc
+	rd, err := row.MakeDeleter(
+		txn, desc, fkTables, []sqlbase.ColumnDescriptor{*ttlCol}, row.CheckFKs, p.EvalContext(), &alloc,
+	)
+	if err != nil {
+		return 0, 0, resume, err
+	}
+	ttlIdx := rd.FetchColIDtoRowIndex[ttlCol.ID]
+
+	var valNeededForCol util.FastIntSet
+	for _, idx := range rd.FetchColIDtoRowIndex {
+		valNeededForCol.Add(idx)
+	}
+	var rf row.Fetcher
+	rf.SetEvalContext(p.EvalContext())
+	if err := rf.Init(
+		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &alloc,
+		row.FetcherTableArgs{
+			Desc:            desc,
+			Index:           &desc.PrimaryIndex,
+			ColIdxMap:       rd.FetchColIDtoRowIndex,
+			Cols:            rd.FetchCols,
+			ValNeededForCol: valNeededForCol,
+		},
+	); err != nil {
+		return 0, 0, resume, err
+	}
+	const traceKV = false
+	if err := rf.StartScan(ctx, txn, roachpb.Spans{span}, true /* limitBatches */, limit, traceKV); err != nil {
+		return 0, 0, resume, err
+	}
+
+	b := txn.NewBatch()
+	for i := int64(0); i < limit; i++ {
+		datums, _, _, err := rf.NextRowDecoded(ctx)
+		if err != nil {
+			return 0, 0, resume, err
+		}
+		if datums == nil {
+			resume = roachpb.Span{}
+			break
+		}
+		expiration, ok := datums[ttlIdx].(*tree.DTimestampTZ)
+		if !ok || !expiration.Time.Before(cutoff.Time) {
+			continue
+		}
+		if err := rd.DeleteRow(ctx, b, datums, row.CheckFKs, traceKV); err != nil {
+			return 0, 0, resume, err
+		}
+		deleted++
+		for _, d := range datums {
+			size += int64(d.Size())
+		}
+	}
+	if resume.Key != nil {
+		resume.Key = rf.Key()
+	}
+	if deleted > 0 {
+		if err := txn.Run(ctx, b); err != nil {
+			return 0, 0, resume, row.ConvertBatchError(ctx, desc, b)
+		}
+	}
+	return deleted, size, resume, nil
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r *rowLevelTTLResumer) OnFailOrCancel(context.Context, *client.Txn, *jobs.Job) error {
+	return nil
+}
+
+// OnSuccess is part of the jobs.Resumer interface.
+func (r *rowLevelTTLResumer) OnSuccess(context.Context, *client.Txn, *jobs.Job) error {
+	return nil
+}
+
+// OnTerminal is part of the jobs.Resumer interface.
+func (r *rowLevelTTLResumer) OnTerminal(
+	context.Context, *jobs.Job, jobs.Status, chan<- tree.Datums,
+) {
+}
+
+func rowLevelTTLResumeHook(typ jobspb.Type, _ *cluster.Settings) jobs.Resumer {
+	if typ != jobspb.TypeRowLevelTTL {
+		return nil
+	}
+	return &rowLevelTTLResumer{}
+}
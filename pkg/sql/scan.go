@@ -98,6 +98,13 @@ type scanNode struct {
 
 	disableBatchLimits bool
 
+	// lockingStrength and lockingWaitPolicy are the strength and the wait
+	// policy of the row-level locks requested for the rows read by this scan,
+	// as specified by a SELECT ... FOR UPDATE or FOR SHARE clause. See
+	// setLocking.
+	lockingStrength   tree.LockingStrength
+	lockingWaitPolicy tree.LockingWaitPolicy
+
 	run scanRun
 
 	// This struct must be allocated on the heap and its location stay
@@ -210,8 +217,15 @@ func (n *scanNode) startExec(params runParams) error {
 		Cols:             n.cols,
 		ValNeededForCol:  n.valNeededForCol.Copy(),
 	}
-	return n.run.fetcher.Init(n.reverse, false, /* returnRangeInfo */
-		false /* isCheck */, &params.p.alloc, tableArgs)
+	n.run.fetcher.SetEvalContext(params.EvalContext())
+	if err := n.run.fetcher.Init(n.reverse, false, /* returnRangeInfo */
+		false /* isCheck */, &params.p.alloc, tableArgs); err != nil {
+		return err
+	}
+	if n.isLocking() {
+		n.run.fetcher.SetLocking(kvWaitPolicy(n.lockingWaitPolicy))
+	}
+	return nil
 }
 
 func (n *scanNode) Close(context.Context) {
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -534,7 +535,7 @@ func (sc *SchemaChanger) maybeDropTable(
 
 // maybe make a table PUBLIC if it's in the ADD state.
 func (sc *SchemaChanger) maybeMakeAddTablePublic(
-	ctx context.Context, table *sqlbase.TableDescriptor,
+	ctx context.Context, table *sqlbase.TableDescriptor, evalCtx *extendedEvalContext,
 ) error {
 	if table.Adding() {
 		for _, idx := range table.AllNonDropIndexes() {
@@ -545,6 +546,19 @@ func (sc *SchemaChanger) maybeMakeAddTablePublic(
 			}
 		}
 
+		// A materialized view is populated before it is made public.
+		if table.MaterializedView() {
+			if err := sc.backfillMaterializedView(ctx, evalCtx, table); err != nil {
+				if isPermanentSchemaChangeError(err) {
+					log.Warningf(ctx, "dropping materialized view %q: %v", table.Name, err)
+					if dropErr := sc.dropAddingMaterializedView(ctx); dropErr != nil {
+						log.Warningf(ctx, "unable to drop materialized view %q: %v", table.Name, dropErr)
+					}
+				}
+				return err
+			}
+		}
+
 		if _, err := sc.leaseMgr.Publish(
 			ctx,
 			table.ID,
@@ -564,6 +578,30 @@ func (sc *SchemaChanger) maybeMakeAddTablePublic(
 	return nil
 }
 
+// dropAddingMaterializedView drops a materialized view that could not be
+// populated, removing the back-references to it from the relations it depends
+// on. The data of the view is cleaned up by the asynchronous schema changer.
+func (sc *SchemaChanger) dropAddingMaterializedView(ctx context.Context) error {
+	return sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		p, cleanup := newInternalPlanner(
+			"drop-materialized-view", txn, security.RootUser, &MemoryMetrics{}, sc.execCfg,
+		)
+		defer cleanup()
+		// The schema changes queued by the drop are picked up by the
+		// asynchronous schema changer.
+		p.extendedEvalCtx.SchemaChangers = &schemaChangerCollection{}
+		viewDesc, err := p.Tables().getMutableTableVersionByID(ctx, sc.tableID, txn)
+		if err != nil {
+			return err
+		}
+		if viewDesc.Dropped() {
+			return nil
+		}
+		_, err = p.dropViewImpl(ctx, viewDesc, tree.DropRestrict)
+		return err
+	})
+}
+
 func (sc *SchemaChanger) maybeGCMutations(
 	ctx context.Context, inSession bool, table *sqlbase.TableDescriptor,
 ) error {
@@ -779,7 +817,7 @@ func (sc *SchemaChanger) exec(
 		return err
 	}
 
-	if err := sc.maybeMakeAddTablePublic(ctx, tableDesc); err != nil {
+	if err := sc.maybeMakeAddTablePublic(ctx, tableDesc, evalCtx); err != nil {
 		return err
 	}
 
@@ -961,14 +999,26 @@ func (sc *SchemaChanger) waitToUpdateLeases(ctx context.Context, tableID sqlbase
 // It ensures that all nodes are on the current (pre-update) version of the
 // schema.
 // Returns the updated descriptor.
+//
+// If finalizing the mutations queues up new ones (see ComputedColumnSwap), a
+// job is created for them, and the schema changer switches to them if they
+// are first in line.
 func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescriptor, error) {
 	isRollback := false
 	jobSucceeded := true
+	cleanupMutationID := sqlbase.InvalidMutationID
+	cleanupFirstInLine := false
+	var cleanupJob *jobs.Job
+	var promotions []sqlbase.EnumMemberPromotion
+	var promotedTypeIDs []sqlbase.ID
 	now := timeutil.Now().UnixNano()
-	return sc.leaseMgr.Publish(ctx, sc.tableID, func(desc *sqlbase.MutableTableDescriptor) error {
+	desc, err := sc.leaseMgr.Publish(ctx, sc.tableID, func(desc *sqlbase.MutableTableDescriptor) error {
 		// Reset vars here because update function can be called multiple times in a retry.
 		isRollback = false
 		jobSucceeded = true
+		cleanupMutationID = sqlbase.InvalidMutationID
+		cleanupFirstInLine = false
+		promotions = nil
 
 		i := 0
 		for _, mutation := range desc.Mutations {
@@ -991,6 +1041,11 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 						})
 				}
 			}
+			if promotion := mutation.GetEnumMemberPromotion(); promotion != nil {
+				// The member is promoted even if the mutation was rolled back
+				// along with others, since the member was added regardless.
+				promotions = append(promotions, *promotion)
+			}
 			if err := desc.MakeMutationComplete(mutation); err != nil {
 				return err
 			}
@@ -1004,6 +1059,13 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 		// Trim the executed mutations from the descriptor.
 		desc.Mutations = desc.Mutations[i:]
 
+		// Completing a ComputedColumnSwap queues up the drop of the column
+		// and indexes it replaces under a new mutation ID.
+		if desc.NextMutationID != desc.ClusterVersion.NextMutationID {
+			cleanupMutationID = desc.ClusterVersion.NextMutationID
+			cleanupFirstInLine = desc.Mutations[0].MutationID == cleanupMutationID
+		}
+
 		for i, g := range desc.MutationJobs {
 			if g.MutationID == sc.mutationID {
 				// Trim the executed mutation group from the descriptor.
@@ -1013,6 +1075,24 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 		}
 		return nil
 	}, func(txn *client.Txn) error {
+		cleanupJob = nil
+		promotedTypeIDs = nil
+		for _, promotion := range promotions {
+			promoted, err := maybePromoteEnumMember(ctx, txn, promotion)
+			if err != nil {
+				return err
+			}
+			if promoted {
+				promotedTypeIDs = append(promotedTypeIDs, promotion.TypeID)
+			}
+		}
+		if cleanupMutationID != sqlbase.InvalidMutationID {
+			var err error
+			cleanupJob, err = sc.createCleanupJob(ctx, txn, cleanupMutationID)
+			if err != nil {
+				return err
+			}
+		}
 		if jobSucceeded {
 			if err := sc.job.WithTxn(txn).Succeeded(ctx, jobs.NoopFn); err != nil {
 				return errors.Wrapf(err, "failed to mark job %d as successful", *sc.job.ID())
@@ -1045,6 +1125,26 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.ImmutableTableDescr
 			}{uint32(sc.mutationID)},
 		)
 	})
+	if err != nil {
+		return nil, err
+	}
+	// The other tables that use a type whose member was promoted have been
+	// leased with the member READ_ONLY. This is safe, but would prevent
+	// writing the member until the leases are renewed.
+	for _, typeID := range promotedTypeIDs {
+		if err := refreshTablesReferencingType(ctx, sc.db, sc.leaseMgr, typeID, sc.tableID); err != nil {
+			log.Warningf(ctx, "failed to refresh the tables using type %d: %s", typeID, err)
+		}
+	}
+	// Only switch to the cleanup job if the transaction has succeeded.
+	if cleanupJob != nil && cleanupFirstInLine {
+		sc.mutationID = cleanupMutationID
+		sc.job = cleanupJob
+		if err := sc.job.Started(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return desc, nil
 }
 
 // notFirstInLine returns true whenever the schema change has been queued
@@ -1093,8 +1193,16 @@ func (sc *SchemaChanger) runStateMachineAndBackfill(
 	}
 
 	// Mark the mutations as completed.
-	_, err := sc.done(ctx)
-	return err
+	mutationID := sc.mutationID
+	if _, err := sc.done(ctx); err != nil {
+		return err
+	}
+	// Run the mutations queued up by the completed ones, if done() switched
+	// to them.
+	if sc.mutationID != mutationID {
+		return sc.runStateMachineAndBackfill(ctx, lease, evalCtx)
+	}
+	return nil
 }
 
 // reverseMutations reverses the direction of all the mutations with the
@@ -1282,6 +1390,62 @@ func (sc *SchemaChanger) createRollbackJob(
 	return nil, fmt.Errorf("no job found for table %d mutation %d", sc.tableID, sc.mutationID)
 }
 
+// createCleanupJob creates a job for the mutations with the given ID queued
+// up by the completion of the schema change, and adds it to the table's
+// mutation jobs.
+func (sc *SchemaChanger) createCleanupJob(
+	ctx context.Context, txn *client.Txn, mutationID sqlbase.MutationID,
+) (*jobs.Job, error) {
+	// Read the table descriptor from the store. The Version of the
+	// descriptor has already been incremented in the transaction and
+	// this descriptor can be modified without incrementing the version.
+	tableDesc, err := sqlbase.GetTableDescFromID(ctx, txn, sc.tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize refresh spans to scan the entire table.
+	span := tableDesc.PrimaryIndexSpan()
+	var spanList []jobspb.ResumeSpanList
+	for _, m := range tableDesc.Mutations {
+		if m.MutationID == mutationID {
+			spanList = append(spanList,
+				jobspb.ResumeSpanList{
+					ResumeSpans: []roachpb.Span{span},
+				},
+			)
+		}
+	}
+	payload := sc.job.Payload()
+	cleanupJob := sc.jobRegistry.NewJob(jobs.Record{
+		Description:   fmt.Sprintf("CLEANUP JOB %d: %s", *sc.job.ID(), payload.Description),
+		Username:      payload.Username,
+		DescriptorIDs: payload.DescriptorIDs,
+		Details:       jobspb.SchemaChangeDetails{ResumeSpanList: spanList},
+		Progress:      jobspb.SchemaChangeProgress{},
+	})
+	if err := cleanupJob.WithTxn(txn).Created(ctx); err != nil {
+		return nil, err
+	}
+	// Set the transaction back to nil so that this job can
+	// be used in other transactions.
+	cleanupJob.WithTxn(nil)
+
+	tableDesc.MutationJobs = append(tableDesc.MutationJobs, sqlbase.TableDescriptor_MutationJob{
+		MutationID: mutationID, JobID: *cleanupJob.ID(),
+	})
+
+	// write descriptor, the version has already been incremented.
+	descKey := sqlbase.MakeDescMetadataKey(tableDesc.GetID())
+	descVal := sqlbase.WrapDescriptor(tableDesc)
+	b := txn.NewBatch()
+	b.Put(descKey, descVal)
+	if err := txn.Run(ctx, b); err != nil {
+		return nil, err
+	}
+	return cleanupJob, nil
+}
+
 // deleteIndexMutationsWithReversedColumns deletes mutations with a
 // different mutationID than the schema changer and with an index that
 // references one of the reversed columns. Execute this as a breadth
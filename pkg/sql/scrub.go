@@ -424,8 +424,12 @@ func createIndexCheckOperations(
 ) (results []checkOperation, err error) {
 	if indexNames == nil {
 		// Populate results with all secondary indexes of the
-		// table.
+		// table. Partial indexes are skipped, as the check expects every
+		// row of the table to be present in the index.
 		for i := range tableDesc.Indexes {
+			if tableDesc.Indexes[i].IsPartial() {
+				continue
+			}
 			results = append(results, newIndexCheckOperation(
 				tableName,
 				tableDesc,
@@ -443,6 +447,10 @@ func createIndexCheckOperations(
 	}
 	for i := range tableDesc.Indexes {
 		if _, ok := names[tableDesc.Indexes[i].Name]; ok {
+			if tableDesc.Indexes[i].IsPartial() {
+				return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+					"cannot check partial index %q", tableDesc.Indexes[i].Name)
+			}
 			results = append(results, newIndexCheckOperation(
 				tableName,
 				tableDesc,
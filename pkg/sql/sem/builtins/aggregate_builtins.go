@@ -178,6 +178,33 @@ var aggregates = map[string]builtinDefinition{
 				"Identifies the minimum selected value.")
 		}),
 
+	"mode": collectOverloads(orderedSetAggProps(), orderedSetAggregateTypes,
+		func(t types.T) tree.Overload {
+			return makeAggOverload([]types.T{t}, t, makeOrderedSetAggregate(modeFinal),
+				"Identifies the most frequent selected value, the smallest one if there are "+
+					"several. Usage: mode() WITHIN GROUP (ORDER BY value).")
+		}),
+
+	"percentile_cont": makeBuiltin(orderedSetAggProps(),
+		makeAggOverload([]types.T{types.Int, types.Float}, types.Float,
+			makeOrderedSetAggregate(percentileContFinal), percentileContInfo),
+		makeAggOverload([]types.T{types.Float, types.Float}, types.Float,
+			makeOrderedSetAggregate(percentileContFinal), percentileContInfo),
+		makeAggOverload([]types.T{types.Decimal, types.Float}, types.Float,
+			makeOrderedSetAggregate(percentileContFinal), percentileContInfo),
+		makeAggOverload([]types.T{types.Interval, types.Float}, types.Interval,
+			makeOrderedSetAggregate(percentileContFinal), percentileContInfo),
+	),
+
+	"percentile_disc": collectOverloads(orderedSetAggProps(), orderedSetAggregateTypes,
+		func(t types.T) tree.Overload {
+			return makeAggOverload([]types.T{t, types.Float}, t,
+				makeOrderedSetAggregate(percentileDiscFinal),
+				"Identifies the first selected value whose position in the ordering equals "+
+					"or exceeds the fraction. Usage: percentile_disc(fraction) WITHIN GROUP "+
+					"(ORDER BY value).")
+		}),
+
 	"string_agg": makeBuiltin(aggPropsNullableArgs(),
 		makeAggOverload([]types.T{types.String, types.String}, types.String, newStringConcatAggregate,
 			"Concatenates all selected values using the provided delimiter."),
@@ -44,6 +44,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
@@ -51,6 +52,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/knz/strtime"
 	"github.com/pkg/errors"
@@ -82,6 +85,7 @@ const (
 	categorySystemInfo    = "System info"
 	categoryGenerator     = "Set-returning"
 	categoryJSON          = "JSONB"
+	categoryTextSearch    = "Full text search"
 )
 
 func categorizeType(t types.T) string {
@@ -2615,6 +2619,104 @@ may increase either contention or retry errors, or both.`,
 
 	"jsonb_array_length": makeBuiltin(jsonProps(), jsonArrayLengthImpl),
 
+	// Full text search functions.
+
+	// https://www.postgresql.org/docs/10/static/functions-textsearch.html
+	"to_tsvector": textSearchBuiltin(types.TSVector,
+		func(config, document string) (tree.Datum, error) {
+			v, err := tsearch.ToTSVector(config, document)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDTSVector(v), nil
+		},
+		"Reduces `document` to a tsvector, with one lexeme for each of its words."),
+
+	"to_tsquery": textSearchBuiltin(types.TSQuery,
+		func(config, query string) (tree.Datum, error) {
+			q, err := tsearch.ToTSQuery(config, query)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDTSQuery(q), nil
+		},
+		"Converts `query`, which is written in the tsquery syntax, to a tsquery, "+
+			"normalizing its operands in the same way as to_tsvector."),
+
+	"plainto_tsquery": textSearchBuiltin(types.TSQuery,
+		func(config, text string) (tree.Datum, error) {
+			q, err := tsearch.PlainToTSQuery(config, text)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDTSQuery(q), nil
+		},
+		"Converts the plain text `text` to a tsquery which matches the documents "+
+			"containing all of its words."),
+
+	"ts_rank": makeBuiltin(tree.FunctionProperties{Category: categoryTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(args[0], args[1], 0)
+			},
+			Info: "Ranks `vector` for `query` based on the frequency of the lexemes of " +
+				"`query` in `vector`.",
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"vector", types.TSVector}, {"query", types.TSQuery}, {"normalization", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tsRank(args[0], args[1], int(tree.MustBeDInt(args[2])))
+			},
+			Info: "Ranks `vector` for `query` based on the frequency of the lexemes of " +
+				"`query` in `vector`. `normalization` is a bit mask which specifies how " +
+				"the length of `vector` is taken into account: 1 divides the rank by 1 + " +
+				"the logarithm of the length, 2 by the length, 8 by the number of unique " +
+				"words, 16 by 1 + the logarithm of the number of unique words and 32 by " +
+				"itself + 1.",
+		},
+	),
+
+	// Trigram functions.
+
+	// https://www.postgresql.org/docs/10/static/pgtrgm.html
+	"similarity": makeBuiltin(tree.FunctionProperties{Category: categoryString},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"left", types.String}, {"right", types.String}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				left, right := string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1]))
+				return tree.NewDFloat(tree.DFloat(trigram.Similarity(left, right))), nil
+			},
+			Info: "Returns a number between 0 and 1 which indicates how similar `left` and " +
+				"`right` are, based on the number of trigrams they share. The trigrams of " +
+				"a string are the groups of three consecutive characters of its " +
+				"lowercased words, padded with spaces.",
+		},
+	),
+
+	"show_trgm": makeBuiltin(tree.FunctionProperties{Category: categoryString},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}},
+			ReturnType: tree.FixedReturnType(types.TArray{Typ: types.String}),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arr := tree.NewDArray(types.String)
+				for _, t := range trigram.MakeTrigrams(string(tree.MustBeDString(args[0]))) {
+					if err := arr.Append(tree.NewDString(t)); err != nil {
+						return nil, err
+					}
+				}
+				return arr, nil
+			},
+			Info: "Returns the trigrams of `input`, which are used by `similarity` and " +
+				"by the inverted indexes of STRING columns.",
+		},
+	),
+
 	// Metadata functions.
 
 	// https://www.postgresql.org/docs/10/static/functions-info.html
@@ -2770,6 +2872,26 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	"crdb_internal.datums_to_bytes": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categorySystemInfo,
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types:      tree.VariadicType{VarType: types.Any},
+			ReturnType: tree.FixedReturnType(types.Bytes),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				b, err := sqlbase.DatumsToBytes(args)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDBytes(tree.DBytes(b)), nil
+			},
+			Info: "Converts datums into key-encoded bytes. Used to compute the shard of " +
+				"the rows of hash sharded indexes.",
+		},
+	),
+
 	"crdb_internal.force_error": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
@@ -3306,6 +3428,38 @@ var jsonTypeOfImpl = tree.Overload{
 	Info: "Returns the type of the outermost JSON value as a text string.",
 }
 
+// textSearchBuiltin returns a builtin which converts its text argument using
+// fn, with an optional text search configuration as the first argument.
+func textSearchBuiltin(
+	returnType types.T, fn func(config, s string) (tree.Datum, error), info string,
+) builtinDefinition {
+	return makeBuiltin(tree.FunctionProperties{Category: categoryTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return fn(tsearch.DefaultConfig, string(tree.MustBeDString(args[0])))
+			},
+			Info: info + " Uses the `simple` text search configuration.",
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"config", types.String}, {"input", types.String}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return fn(string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1])))
+			},
+			Info: info + " Uses the text search configuration `config`, which " +
+				"can be `simple` or `english`.",
+		},
+	)
+}
+
+func tsRank(vector, query tree.Datum, normalization int) (tree.Datum, error) {
+	rank := tsearch.Rank(
+		tree.MustBeDTSVector(vector).TSVector, tree.MustBeDTSQuery(query).TSQuery, normalization)
+	return tree.NewDFloat(tree.DFloat(rank)), nil
+}
+
 func jsonProps() tree.FunctionProperties {
 	return tree.FunctionProperties{
 		Category: categoryJSON,
@@ -0,0 +1,297 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package builtins
+
+import (
+	"context"
+	"math"
+	"sort"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// Ordered-set aggregates are called with a WITHIN GROUP clause, e.g.
+// percentile_disc(0.5) WITHIN GROUP (ORDER BY x). They receive the WITHIN
+// GROUP expression as their first argument, and the direct arguments (0.5)
+// as constant arguments. Their result is computed from the sorted aggregated
+// values once all of them have been added.
+
+func orderedSetAggProps() tree.FunctionProperties {
+	f := aggProps()
+	f.OrderedSetAggregate = true
+	return f
+}
+
+// orderedSetAggregateTypes are the types that can be ordered by the WITHIN
+// GROUP clause of ordered-set aggregates.
+var orderedSetAggregateTypes = func() []types.T {
+	var r []types.T
+	for _, t := range types.AnyNonArray {
+		// JSON values can't be ordered.
+		if t != types.JSON {
+			r = append(r, t)
+		}
+	}
+	return r
+}()
+
+const percentileContInfo = "Identifies the value corresponding to the fraction in the ordering, " +
+	"interpolating between the adjacent selected values if needed. Usage: " +
+	"percentile_cont(fraction) WITHIN GROUP (ORDER BY value)."
+
+// orderedSetFinalFunc computes the result of an ordered-set aggregate from its
+// direct arguments and the n aggregated values, which are produced in
+// ascending order by next. n is never zero.
+type orderedSetFinalFunc func(
+	evalCtx *tree.EvalContext, arguments tree.Datums, n int, next func() (tree.Datum, error),
+) (tree.Datum, error)
+
+var orderedSetFinalFuncs = map[string]orderedSetFinalFunc{
+	"mode":            modeFinal,
+	"percentile_cont": percentileContFinal,
+	"percentile_disc": percentileDiscFinal,
+}
+
+// IsOrderedSetAggregate returns whether the aggregate function with the given
+// name is an ordered-set aggregate.
+func IsOrderedSetAggregate(name string) bool {
+	_, ok := orderedSetFinalFuncs[name]
+	return ok
+}
+
+// EvalOrderedSetAggregate computes the result of the ordered-set aggregate
+// with the given name from its direct arguments and the n aggregated values,
+// which are produced in ascending order by next. It allows the aggregated
+// values to be stored and sorted outside of the aggregate function, e.g. on
+// disk.
+func EvalOrderedSetAggregate(
+	name string,
+	evalCtx *tree.EvalContext,
+	arguments tree.Datums,
+	n int,
+	next func() (tree.Datum, error),
+) (tree.Datum, error) {
+	final, ok := orderedSetFinalFuncs[name]
+	if !ok {
+		return nil, pgerror.NewAssertionErrorf("%s is not an ordered-set aggregate", name)
+	}
+	if n == 0 {
+		return tree.DNull, nil
+	}
+	return final(evalCtx, arguments, n, next)
+}
+
+// orderedSetAggregate is the in-memory implementation of ordered-set
+// aggregates: the aggregated values are accumulated and sorted when the
+// result is computed.
+type orderedSetAggregate struct {
+	evalCtx   *tree.EvalContext
+	arguments tree.Datums
+	final     orderedSetFinalFunc
+	values    tree.Datums
+	sorted    bool
+	acc       mon.BoundAccount
+}
+
+var _ tree.AggregateFunc = &orderedSetAggregate{}
+
+const sizeOfOrderedSetAggregate = int64(unsafe.Sizeof(orderedSetAggregate{}))
+
+func makeOrderedSetAggregate(
+	final orderedSetFinalFunc,
+) func([]types.T, *tree.EvalContext, tree.Datums) tree.AggregateFunc {
+	return func(_ []types.T, evalCtx *tree.EvalContext, arguments tree.Datums) tree.AggregateFunc {
+		return &orderedSetAggregate{
+			evalCtx:   evalCtx,
+			arguments: arguments,
+			final:     final,
+			acc:       evalCtx.Mon.MakeBoundAccount(),
+		}
+	}
+}
+
+// Add accumulates the passed datum. NULLs are ignored.
+func (a *orderedSetAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	if err := a.acc.Grow(ctx, int64(datum.Size())); err != nil {
+		return err
+	}
+	a.values = append(a.values, datum)
+	a.sorted = false
+	return nil
+}
+
+// Result returns the result of the ordered-set aggregate over the values
+// passed to Add.
+func (a *orderedSetAggregate) Result() (tree.Datum, error) {
+	if len(a.values) == 0 {
+		return tree.DNull, nil
+	}
+	if !a.sorted {
+		sort.Slice(a.values, func(i, j int) bool {
+			return a.values[i].Compare(a.evalCtx, a.values[j]) < 0
+		})
+		a.sorted = true
+	}
+	i := 0
+	return a.final(a.evalCtx, a.arguments, len(a.values), func() (tree.Datum, error) {
+		d := a.values[i]
+		i++
+		return d, nil
+	})
+}
+
+// Close allows the aggregate to release the memory it requested during
+// operation.
+func (a *orderedSetAggregate) Close(ctx context.Context) {
+	a.values = nil
+	a.acc.Close(ctx)
+}
+
+// Size is part of the tree.AggregateFunc interface.
+func (a *orderedSetAggregate) Size() int64 {
+	return sizeOfOrderedSetAggregate
+}
+
+// modeFinal returns the most frequent value, the smallest one if there are
+// several.
+func modeFinal(
+	evalCtx *tree.EvalContext, _ tree.Datums, n int, next func() (tree.Datum, error),
+) (tree.Datum, error) {
+	var mode, cur tree.Datum
+	var modeCount, curCount int
+	for i := 0; i < n; i++ {
+		d, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if cur != nil && d.Compare(evalCtx, cur) == 0 {
+			curCount++
+		} else {
+			cur, curCount = d, 1
+		}
+		if curCount > modeCount {
+			mode, modeCount = cur, curCount
+		}
+	}
+	return mode, nil
+}
+
+// percentileDiscFinal returns the first value whose position in the ordering
+// equals or exceeds the fraction.
+func percentileDiscFinal(
+	_ *tree.EvalContext, arguments tree.Datums, n int, next func() (tree.Datum, error),
+) (tree.Datum, error) {
+	fraction, ok, err := getPercentileFraction(arguments)
+	if err != nil || !ok {
+		return tree.DNull, err
+	}
+	k := int(math.Ceil(fraction * float64(n)))
+	if k < 1 {
+		k = 1
+	}
+	var d tree.Datum
+	for i := 0; i < k; i++ {
+		if d, err = next(); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// percentileContFinal returns the value corresponding to the fraction in the
+// ordering, interpolating between the adjacent values if needed.
+func percentileContFinal(
+	_ *tree.EvalContext, arguments tree.Datums, n int, next func() (tree.Datum, error),
+) (tree.Datum, error) {
+	fraction, ok, err := getPercentileFraction(arguments)
+	if err != nil || !ok {
+		return tree.DNull, err
+	}
+	pos := fraction * float64(n-1)
+	lo := int(math.Floor(pos))
+	var first tree.Datum
+	for i := 0; i <= lo; i++ {
+		if first, err = next(); err != nil {
+			return nil, err
+		}
+	}
+	second := first
+	if float64(lo) < pos {
+		if second, err = next(); err != nil {
+			return nil, err
+		}
+	}
+	proportion := pos - float64(lo)
+
+	if f, ok := first.(*tree.DInterval); ok {
+		s := second.(*tree.DInterval)
+		return &tree.DInterval{
+			Duration: f.Duration.Add(s.Duration.Sub(f.Duration).MulFloat(proportion)),
+		}, nil
+	}
+	f, err := percentileContValue(first)
+	if err != nil {
+		return nil, err
+	}
+	s, err := percentileContValue(second)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDFloat(tree.DFloat(f + (s-f)*proportion)), nil
+}
+
+// percentileContValue converts a value aggregated by percentile_cont to a
+// float.
+func percentileContValue(d tree.Datum) (float64, error) {
+	switch t := d.(type) {
+	case *tree.DFloat:
+		return float64(*t), nil
+	case *tree.DInt:
+		return float64(*t), nil
+	case *tree.DDecimal:
+		return t.Float64()
+	}
+	return 0, pgerror.NewAssertionErrorf("unexpected percentile_cont value type %s", d.ResolvedType())
+}
+
+// getPercentileFraction returns the fraction passed to percentile_disc or
+// percentile_cont, or false if it is NULL.
+func getPercentileFraction(arguments tree.Datums) (float64, bool, error) {
+	if len(arguments) != 1 {
+		return 0, false, pgerror.NewAssertionErrorf(
+			"expected 1 direct argument, found %d", len(arguments))
+	}
+	if arguments[0] == tree.DNull {
+		return 0, false, nil
+	}
+	d, ok := arguments[0].(*tree.DFloat)
+	if !ok {
+		return 0, false, pgerror.NewAssertionErrorf(
+			"unexpected percentile type %s", arguments[0].ResolvedType())
+	}
+	fraction := float64(*d)
+	if !(fraction >= 0 && fraction <= 1) {
+		return 0, false, pgerror.NewErrorf(pgerror.CodeNumericValueOutOfRangeError,
+			"percentile value %g is not between 0 and 1", fraction)
+	}
+	return fraction, true, nil
+}
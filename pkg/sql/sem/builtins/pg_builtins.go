@@ -857,6 +857,43 @@ var pgBuiltins = map[string]builtinDefinition{
 		},
 	),
 
+	// pg_notify sends a notification like NOTIFY does, but the channel and
+	// payload can be computed.
+	// https://www.postgresql.org/docs/10/static/functions-info.html
+	"pg_notify": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categorySystemInfo,
+			DistsqlBlacklist: true,
+			Impure:           true,
+			NullableArgs:     true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"channel", types.String}, {"payload", types.String}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if args[0] == tree.DNull {
+					return nil, pgerror.NewError(pgerror.CodeInvalidParameterValueError,
+						"channel name cannot be empty")
+				}
+				if ctx.Notifier == nil {
+					return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+						"pg_notify() cannot be used in this context")
+				}
+				var payload string
+				if args[1] != tree.DNull {
+					payload = string(tree.MustBeDString(args[1]))
+				}
+				channel := string(tree.MustBeDString(args[0]))
+				if err := ctx.Notifier.SendNotification(ctx.Ctx(), channel, payload); err != nil {
+					return nil, err
+				}
+				return tree.DBoolTrue, nil
+			},
+			Info: "pg_notify sends a notification with the given payload to the sessions " +
+				"listening on channel when the current transaction commits.",
+		},
+	),
+
 	// pg_is_in_recovery returns true if the Postgres database is currently in
 	// recovery.  This is not applicable so this can always return false.
 	// https://www.postgresql.org/docs/current/static/functions-admin.html#FUNCTIONS-RECOVERY-INFO-TABLE
@@ -72,6 +72,12 @@ func ComputeColNameInternal(sp sessiondata.SearchPath, target Expr) (int, string
 	case *FuncExpr:
 		fd, err := e.Func.Resolve(sp)
 		if err != nil {
+			// User-defined functions are not known here; they are named
+			// after the function as written. Unknown functions are reported
+			// during type checking.
+			if name, ok := e.Func.FunctionReference.(*UnresolvedName); ok {
+				return 2, name.Parts[0], nil
+			}
 			return 0, "", err
 		}
 		return 2, fd.Name, nil
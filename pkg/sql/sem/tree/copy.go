@@ -14,6 +14,13 @@
 
 package tree
 
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
 // CopyFrom represents a COPY FROM statement.
 type CopyFrom struct {
 	Table   TableName
@@ -35,3 +42,182 @@ func (node *CopyFrom) Format(ctx *FmtCtx) {
 		ctx.WriteString("STDIN")
 	}
 }
+
+// CopyTo represents a COPY TO STDOUT statement. Either Table or Query
+// is set.
+type CopyTo struct {
+	Table   TableName
+	Columns NameList
+	Query   *Select
+	Options CopyOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CopyTo) Format(ctx *FmtCtx) {
+	ctx.WriteString("COPY ")
+	if node.Query != nil {
+		ctx.WriteByte('(')
+		ctx.FormatNode(node.Query)
+		ctx.WriteByte(')')
+	} else {
+		ctx.FormatNode(&node.Table)
+		if len(node.Columns) > 0 {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.Columns)
+			ctx.WriteString(")")
+		}
+	}
+	ctx.WriteString(" TO STDOUT")
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteByte(')')
+	}
+}
+
+// CopyFormat identifies the data format of a COPY statement.
+type CopyFormat int
+
+// CopyFormat values.
+const (
+	CopyFormatUnspecified CopyFormat = iota
+	CopyFormatText
+	CopyFormatCSV
+	CopyFormatBinary
+)
+
+var copyFormatName = [...]string{
+	CopyFormatText:   "text",
+	CopyFormatCSV:    "csv",
+	CopyFormatBinary: "binary",
+}
+
+func (f CopyFormat) String() string {
+	return copyFormatName[f]
+}
+
+// CopyOptions holds the options of a COPY statement. The zero value
+// describes the default text format.
+type CopyOptions struct {
+	FileFormat CopyFormat
+	// Delimiter and Null are nil when not specified.
+	Delimiter *string
+	Null      *string
+	Header    *bool
+}
+
+// IsDefault returns true if no option was specified.
+func (node *CopyOptions) IsDefault() bool {
+	return *node == CopyOptions{}
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CopyOptions) Format(ctx *FmtCtx) {
+	sep := ""
+	if node.FileFormat != CopyFormatUnspecified {
+		ctx.WriteString("FORMAT ")
+		ctx.WriteString(node.FileFormat.String())
+		sep = ", "
+	}
+	if node.Delimiter != nil {
+		ctx.WriteString(sep)
+		ctx.WriteString("DELIMITER ")
+		lex.EncodeSQLStringWithFlags(ctx.Buffer, *node.Delimiter, ctx.flags.EncodeFlags())
+		sep = ", "
+	}
+	if node.Null != nil {
+		ctx.WriteString(sep)
+		ctx.WriteString("NULL ")
+		lex.EncodeSQLStringWithFlags(ctx.Buffer, *node.Null, ctx.flags.EncodeFlags())
+		sep = ", "
+	}
+	if node.Header != nil {
+		ctx.WriteString(sep)
+		ctx.WriteString("HEADER")
+		if !*node.Header {
+			ctx.WriteString(" false")
+		}
+	}
+}
+
+var errCopyConflictingOptions = pgerror.NewError(pgerror.CodeSyntaxError, "conflicting or redundant options")
+
+// Merge merges the options specified in other into node.
+// Used in the parser.
+func (node *CopyOptions) Merge(other CopyOptions) error {
+	if other.FileFormat != CopyFormatUnspecified {
+		if node.FileFormat != CopyFormatUnspecified {
+			return errCopyConflictingOptions
+		}
+		node.FileFormat = other.FileFormat
+	}
+	if other.Delimiter != nil {
+		if node.Delimiter != nil {
+			return errCopyConflictingOptions
+		}
+		node.Delimiter = other.Delimiter
+	}
+	if other.Null != nil {
+		if node.Null != nil {
+			return errCopyConflictingOptions
+		}
+		node.Null = other.Null
+	}
+	if other.Header != nil {
+		if node.Header != nil {
+			return errCopyConflictingOptions
+		}
+		node.Header = other.Header
+	}
+	return nil
+}
+
+// MakeCopyOption constructs the COPY option named name, as specified
+// in the parenthesized option list. arg is nil when the option was
+// given without an argument.
+// Used in the parser.
+func MakeCopyOption(name string, arg *string) (CopyOptions, error) {
+	var opts CopyOptions
+	switch name = strings.ToLower(name); name {
+	case "format":
+		if arg == nil {
+			return opts, pgerror.NewError(pgerror.CodeSyntaxError, "COPY format must be specified")
+		}
+		switch strings.ToLower(*arg) {
+		case "text":
+			opts.Format = CopyFormatText
+		case "csv":
+			opts.Format = CopyFormatCSV
+		case "binary":
+			opts.Format = CopyFormatBinary
+		default:
+			return opts, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"COPY format %q not recognized", *arg)
+		}
+	case "delimiter", "null":
+		if arg == nil {
+			return opts, pgerror.NewErrorf(pgerror.CodeSyntaxError, "COPY %s must be specified", name)
+		}
+		s := *arg
+		if name == "delimiter" {
+			opts.Delimiter = &s
+		} else {
+			opts.Null = &s
+		}
+	case "header":
+		header := true
+		if arg != nil {
+			switch strings.ToLower(*arg) {
+			case "true", "on", "1":
+			case "false", "off", "0":
+				header = false
+			default:
+				return opts, pgerror.NewError(pgerror.CodeSyntaxError, "header requires a Boolean value")
+			}
+		}
+		opts.Header = &header
+	default:
+		return opts, pgerror.NewErrorf(pgerror.CodeSyntaxError, "option %q not recognized", name)
+	}
+	return opts, nil
+}
@@ -105,11 +105,15 @@ type CreateIndex struct {
 	Inverted    bool
 	IfNotExists bool
 	Columns     IndexElemList
+	Sharded     *ShardedIndexDef
 	// Extra columns to be stored together with the indexed ones as an optimization
 	// for improved reading performance.
 	Storing     NameList
 	Interleave  *InterleaveDef
 	PartitionBy *PartitionBy
+	// Predicate, if not nil, restricts the index to the rows for which it
+	// evaluates to true, making this a partial index.
+	Predicate Expr
 }
 
 // Format implements the NodeFormatter interface.
@@ -134,6 +138,9 @@ func (node *CreateIndex) Format(ctx *FmtCtx) {
 	ctx.WriteString(" (")
 	ctx.FormatNode(&node.Columns)
 	ctx.WriteByte(')')
+	if node.Sharded != nil {
+		ctx.FormatNode(node.Sharded)
+	}
 	if len(node.Storing) > 0 {
 		ctx.WriteString(" STORING (")
 		ctx.FormatNode(&node.Storing)
@@ -145,6 +152,10 @@ func (node *CreateIndex) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
 }
 
 // TableDef represents a column, index or constraint definition within a CREATE
@@ -162,6 +173,7 @@ type TableDef interface {
 func (*ColumnTableDef) tableDef() {}
 func (*IndexTableDef) tableDef()  {}
 func (*FamilyTableDef) tableDef() {}
+func (*LikeTableDef) tableDef()   {}
 
 // TableDefs represents a list of table definitions.
 type TableDefs []TableDef
@@ -209,10 +221,12 @@ type ColumnTableDef struct {
 		Col            Name
 		ConstraintName Name
 		Actions        ReferenceActions
+		Deferrability  ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
 		Expr     Expr
+		Virtual  bool
 	}
 	Family struct {
 		Name        Name
@@ -314,9 +328,11 @@ func NewColumnTableDef(
 			d.References.Col = t.Col
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
+			d.References.Deferrability = t.Deferrability
 		case *ColumnComputedDef:
 			d.Computed.Computed = true
 			d.Computed.Expr = t.Expr
+			d.Computed.Virtual = t.Virtual
 		case *ColumnFamilyConstraint:
 			if d.HasColumnFamily() {
 				return nil, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
@@ -413,11 +429,16 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteByte(')')
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(&node.References.Deferrability)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
 		ctx.FormatNode(node.Computed.Expr)
-		ctx.WriteString(") STORED")
+		if node.Computed.Virtual {
+			ctx.WriteString(") VIRTUAL")
+		} else {
+			ctx.WriteString(") STORED")
+		}
 	}
 	if node.HasColumnFamily() {
 		if node.Family.Create {
@@ -486,14 +507,18 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
+	Table         TableName
+	Col           Name // empty-string means use PK
+	Actions       ReferenceActions
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
 type ColumnComputedDef struct {
 	Expr Expr
+	// Virtual is set for virtual computed columns, whose values are computed
+	// when they are read instead of being stored.
+	Virtual bool
 }
 
 // ColumnFamilyConstraint represents FAMILY on a column.
@@ -508,10 +533,13 @@ type ColumnFamilyConstraint struct {
 type IndexTableDef struct {
 	Name        Name
 	Columns     IndexElemList
+	Sharded     *ShardedIndexDef
 	Storing     NameList
 	Interleave  *InterleaveDef
 	Inverted    bool
 	PartitionBy *PartitionBy
+	// Predicate, if not nil, makes this a partial index.
+	Predicate Expr
 }
 
 // SetName implements the TableDef interface.
@@ -532,6 +560,9 @@ func (node *IndexTableDef) Format(ctx *FmtCtx) {
 	ctx.WriteByte('(')
 	ctx.FormatNode(&node.Columns)
 	ctx.WriteByte(')')
+	if node.Sharded != nil {
+		ctx.FormatNode(node.Sharded)
+	}
 	if node.Storing != nil {
 		ctx.WriteString(" STORING (")
 		ctx.FormatNode(&node.Storing)
@@ -543,6 +574,10 @@ func (node *IndexTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
 }
 
 // ConstraintTableDef represents a constraint definition within a CREATE TABLE
@@ -560,7 +595,8 @@ func (*UniqueConstraintTableDef) constraintTableDef() {}
 // TABLE statement.
 type UniqueConstraintTableDef struct {
 	IndexTableDef
-	PrimaryKey bool
+	PrimaryKey    bool
+	Deferrability ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -578,6 +614,9 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	ctx.WriteByte('(')
 	ctx.FormatNode(&node.Columns)
 	ctx.WriteByte(')')
+	if node.Sharded != nil {
+		ctx.FormatNode(node.Sharded)
+	}
 	if node.Storing != nil {
 		ctx.WriteString(" STORING (")
 		ctx.FormatNode(&node.Storing)
@@ -589,6 +628,11 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
+	ctx.FormatNode(&node.Deferrability)
 }
 
 // ReferenceAction is the method used to maintain referential integrity through
@@ -635,13 +679,41 @@ func (node *ReferenceActions) Format(ctx *FmtCtx) {
 	}
 }
 
+// ConstraintDeferrability specifies whether the checking of a constraint can
+// be deferred until the end of the transaction, and whether it is by default.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	// NotDeferrable constraints are always checked at the end of each
+	// statement.
+	NotDeferrable ConstraintDeferrability = iota
+	// DeferrableInitiallyImmediate constraints are checked at the end of each
+	// statement, unless SET CONSTRAINTS defers them.
+	DeferrableInitiallyImmediate
+	// DeferrableInitiallyDeferred constraints are checked at the end of the
+	// transaction, unless SET CONSTRAINTS says otherwise.
+	DeferrableInitiallyDeferred
+)
+
+// Format implements the NodeFormatter interface.
+func (node *ConstraintDeferrability) Format(ctx *FmtCtx) {
+	switch *node {
+	case DeferrableInitiallyImmediate:
+		ctx.WriteString(" DEFERRABLE")
+	case DeferrableInitiallyDeferred:
+		ctx.WriteString(" DEFERRABLE INITIALLY DEFERRED")
+	}
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name     Name
-	Table    TableName
-	FromCols NameList
-	ToCols   NameList
-	Actions  ReferenceActions
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Deferrability ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -664,6 +736,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(&node.Deferrability)
 }
 
 // SetName implements the TableDef interface.
@@ -725,6 +798,83 @@ func (node *FamilyTableDef) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// LikeTableDef represents a LIKE table declaration within a CREATE TABLE
+// statement.
+type LikeTableDef struct {
+	Name    TableName
+	Options []LikeTableOption
+}
+
+// LikeTableOption represents an individual INCLUDING or EXCLUDING option of a
+// LIKE table declaration.
+type LikeTableOption struct {
+	Excluded bool
+	Opt      LikeTableOpt
+}
+
+// LikeTableOpt represents one of the kinds of definitions that can be
+// included or excluded by a LIKE table declaration. It is a bitmap, where
+// each of the options other than LikeTableOptAll is a single bit.
+type LikeTableOpt int
+
+// The values for LikeTableOpt.
+const (
+	LikeTableOptConstraints LikeTableOpt = 1 << iota
+	LikeTableOptDefaults
+	LikeTableOptIndexes
+
+	LikeTableOptAll = LikeTableOptConstraints | LikeTableOptDefaults | LikeTableOptIndexes
+)
+
+var likeTableOptName = map[LikeTableOpt]string{
+	LikeTableOptConstraints: "CONSTRAINTS",
+	LikeTableOptDefaults:    "DEFAULTS",
+	LikeTableOptIndexes:     "INDEXES",
+	LikeTableOptAll:         "ALL",
+}
+
+func (o LikeTableOpt) String() string {
+	return likeTableOptName[o]
+}
+
+// Has returns whether all the options of other are set in o.
+func (o LikeTableOpt) Has(other LikeTableOpt) bool {
+	return o&other == other
+}
+
+// EffectiveOpts returns the set of options that results from applying the
+// INCLUDING and EXCLUDING options of the declaration in order, starting from
+// an empty set.
+func (node *LikeTableDef) EffectiveOpts() LikeTableOpt {
+	var opts LikeTableOpt
+	for _, o := range node.Options {
+		if o.Excluded {
+			opts &^= o.Opt
+		} else {
+			opts |= o.Opt
+		}
+	}
+	return opts
+}
+
+// SetName implements the TableDef interface. LIKE table declarations have no
+// name, so this is a no-op.
+func (node *LikeTableDef) SetName(name Name) {}
+
+// Format implements the NodeFormatter interface.
+func (node *LikeTableDef) Format(ctx *FmtCtx) {
+	ctx.WriteString("LIKE ")
+	ctx.FormatNode(&node.Name)
+	for _, o := range node.Options {
+		if o.Excluded {
+			ctx.WriteString(" EXCLUDING ")
+		} else {
+			ctx.WriteString(" INCLUDING ")
+		}
+		ctx.WriteString(o.Opt.String())
+	}
+}
+
 // InterleaveDef represents an interleave definition within a CREATE TABLE
 // or CREATE INDEX statement.
 type InterleaveDef struct {
@@ -751,6 +901,20 @@ func (node *InterleaveDef) Format(ctx *FmtCtx) {
 	}
 }
 
+// ShardedIndexDef represents a hash sharded index definition within a CREATE
+// TABLE or CREATE INDEX statement. The rows of a hash sharded index are spread
+// over ShardBuckets buckets according to a hash of their indexed values, so
+// that sequential values are not all written to the same range.
+type ShardedIndexDef struct {
+	ShardBuckets Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShardedIndexDef) Format(ctx *FmtCtx) {
+	ctx.WriteString(" USING HASH WITH BUCKET_COUNT = ")
+	ctx.FormatNode(node.ShardBuckets)
+}
+
 // PartitionByType is an enum of each type of partitioning (LIST/RANGE).
 type PartitionByType string
 
@@ -848,6 +1012,8 @@ type CreateTable struct {
 	Defs          TableDefs
 	AsSource      *Select
 	AsColumnNames NameList // Only to be used in conjunction with AsSource
+	Temporary     bool
+	StorageParams KVOptions
 }
 
 // As returns true if this table represents a CREATE TABLE ... AS statement,
@@ -858,7 +1024,11 @@ func (node *CreateTable) As() bool {
 
 // Format implements the NodeFormatter interface.
 func (node *CreateTable) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE TABLE ")
+	ctx.WriteString("CREATE ")
+	if node.Temporary {
+		ctx.WriteString("TEMPORARY ")
+	}
+	ctx.WriteString("TABLE ")
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
 	}
@@ -875,6 +1045,7 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 			ctx.FormatNode(&node.AsColumnNames)
 			ctx.WriteByte(')')
 		}
+		node.formatStorageParams(ctx)
 		ctx.WriteString(" AS ")
 		ctx.FormatNode(node.AsSource)
 	} else {
@@ -887,6 +1058,15 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 		if node.PartitionBy != nil {
 			ctx.FormatNode(node.PartitionBy)
 		}
+		node.formatStorageParams(ctx)
+	}
+}
+
+func (node *CreateTable) formatStorageParams(ctx *FmtCtx) {
+	if len(node.StorageParams) > 0 {
+		ctx.WriteString(" WITH (")
+		ctx.FormatNode(&node.StorageParams)
+		ctx.WriteByte(')')
 	}
 }
 
@@ -934,11 +1114,12 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
+					Table:         *col.References.Table,
+					FromCols:      NameList{col.Name},
+					ToCols:        targetCol,
+					Name:          col.References.ConstraintName,
+					Actions:       col.References.Actions,
+					Deferrability: col.References.Deferrability,
 				})
 				col.References.Table = nil
 			}
@@ -946,6 +1127,104 @@ func (node *CreateTable) HoistConstraints() {
 	}
 }
 
+// CreateSchema represents a CREATE SCHEMA statement.
+type CreateSchema struct {
+	IfNotExists bool
+	Schema      Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateSchema) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SCHEMA ")
+	if node.IfNotExists {
+		ctx.WriteString("IF NOT EXISTS ")
+	}
+	ctx.FormatNode(&node.Schema)
+}
+
+// CreateType represents a CREATE TYPE statement. Only ENUM types are
+// supported.
+type CreateType struct {
+	Name       Name
+	EnumLabels []string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateType) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TYPE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" AS ENUM (")
+	for i, label := range node.EnumLabels {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		lex.EncodeSQLStringWithFlags(ctx.Buffer, label, ctx.flags.EncodeFlags())
+	}
+	ctx.WriteByte(')')
+}
+
+// FuncArg is an argument in the declaration of a user-defined function.
+type FuncArg struct {
+	// Name is empty if the argument is unnamed.
+	Name Name
+	Type coltypes.T
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArg) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	node.Type.Format(ctx.Buffer, ctx.flags.EncodeFlags())
+}
+
+// FuncArgs is the list of arguments of a user-defined function.
+type FuncArgs []FuncArg
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArgs) Format(ctx *FmtCtx) {
+	ctx.WriteByte('(')
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+	ctx.WriteByte(')')
+}
+
+// CreateFunction represents a CREATE FUNCTION statement. Only functions
+// written in SQL are supported.
+type CreateFunction struct {
+	Name       Name
+	Replace    bool
+	Args       FuncArgs
+	ReturnType coltypes.T
+	ReturnsSet bool
+	// Body is the SELECT statement that computes the result of the
+	// function.
+	Body string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(&node.Name)
+	ctx.FormatNode(&node.Args)
+	ctx.WriteString(" RETURNS ")
+	if node.ReturnsSet {
+		ctx.WriteString("SETOF ")
+	}
+	node.ReturnType.Format(ctx.Buffer, ctx.flags.EncodeFlags())
+	ctx.WriteString(" LANGUAGE SQL AS ")
+	lex.EncodeSQLStringWithFlags(ctx.Buffer, node.Body, ctx.flags.EncodeFlags())
+}
+
 // CreateSequence represents a CREATE SEQUENCE statement.
 type CreateSequence struct {
 	IfNotExists bool
@@ -1104,14 +1383,19 @@ func (node *CreateRole) Format(ctx *FmtCtx) {
 
 // CreateView represents a CREATE VIEW statement.
 type CreateView struct {
-	Name        TableName
-	ColumnNames NameList
-	AsSource    *Select
+	Name         TableName
+	ColumnNames  NameList
+	AsSource     *Select
+	Materialized bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *CreateView) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE VIEW ")
+	ctx.WriteString("CREATE ")
+	if node.Materialized {
+		ctx.WriteString("MATERIALIZED ")
+	}
+	ctx.WriteString("VIEW ")
 	ctx.FormatNode(&node.Name)
 
 	if len(node.ColumnNames) > 0 {
@@ -1125,6 +1409,17 @@ func (node *CreateView) Format(ctx *FmtCtx) {
 	ctx.FormatNode(node.AsSource)
 }
 
+// RefreshMaterializedView represents a REFRESH MATERIALIZED VIEW statement.
+type RefreshMaterializedView struct {
+	Name TableName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RefreshMaterializedView) Format(ctx *FmtCtx) {
+	ctx.WriteString("REFRESH MATERIALIZED VIEW ")
+	ctx.FormatNode(&node.Name)
+}
+
 // CreateStats represents a CREATE STATISTICS statement.
 type CreateStats struct {
 	Name        Name
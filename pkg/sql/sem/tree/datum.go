@@ -41,6 +41,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/lib/pq/oid"
@@ -1626,6 +1627,140 @@ func (d *DIPAddr) Size() uintptr {
 	return unsafe.Sizeof(*d)
 }
 
+// DEnum is the Datum representation of a value of a user-defined ENUM type.
+type DEnum struct {
+	// EnumTyp is the type of the value.
+	EnumTyp types.TEnum
+	// PhysicalRep is the representation of the value in keys and values.
+	// Values of an ENUM sort by their physical representations, which
+	// follow the declaration order of the members of the type.
+	PhysicalRep []byte
+	// LogicalRep is the label of the value.
+	LogicalRep string
+}
+
+// MakeDEnumFromLogicalRep creates a DEnum of the given type from the label of
+// one of its members. Members that are still being added to the type cannot
+// be used yet.
+func MakeDEnumFromLogicalRep(typ types.TEnum, rep string) (*DEnum, error) {
+	idx := typ.LabelIndex(rep)
+	if idx == -1 {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidTextRepresentationError,
+			"invalid input value for enum %s: %q", typ, rep)
+	}
+	if typ.IsReadOnly(idx) {
+		return nil, pgerror.NewErrorf(pgerror.CodeUnsafeNewEnumValueUsageError,
+			"enum value %q is not yet public", rep)
+	}
+	return &DEnum{EnumTyp: typ, PhysicalRep: typ.PhysicalRep(idx), LogicalRep: typ.Label(idx)}, nil
+}
+
+// MakeDEnumFromPhysicalRep creates a DEnum of the given type from the
+// physical representation of one of its members.
+func MakeDEnumFromPhysicalRep(typ types.TEnum, rep []byte) (*DEnum, error) {
+	idx := typ.PhysicalRepIndex(rep)
+	if idx == -1 {
+		return nil, pgerror.NewAssertionErrorf(
+			"could not find physical representation %x in enum %s", rep, typ)
+	}
+	return &DEnum{EnumTyp: typ, PhysicalRep: typ.PhysicalRep(idx), LogicalRep: typ.Label(idx)}, nil
+}
+
+// enumMember returns the member of the type of d at the given position.
+func (d *DEnum) enumMember(idx int) *DEnum {
+	return &DEnum{
+		EnumTyp:     d.EnumTyp,
+		PhysicalRep: d.EnumTyp.PhysicalRep(idx),
+		LogicalRep:  d.EnumTyp.Label(idx),
+	}
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DEnum) ResolvedType() types.T {
+	return d.EnumTyp
+}
+
+// Compare implements the Datum interface.
+func (d *DEnum) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DEnum)
+	if !ok || v.EnumTyp.ID != d.EnumTyp.ID {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return bytes.Compare(d.PhysicalRep, v.PhysicalRep)
+}
+
+// Prev implements the Datum interface.
+func (d *DEnum) Prev(_ *EvalContext) (Datum, bool) {
+	idx := d.EnumTyp.PhysicalRepIndex(d.PhysicalRep)
+	if idx <= 0 {
+		return nil, false
+	}
+	return d.enumMember(idx - 1), true
+}
+
+// Next implements the Datum interface.
+func (d *DEnum) Next(_ *EvalContext) (Datum, bool) {
+	idx := d.EnumTyp.PhysicalRepIndex(d.PhysicalRep)
+	if idx == -1 || idx == d.EnumTyp.NumMembers()-1 {
+		return nil, false
+	}
+	return d.enumMember(idx + 1), true
+}
+
+// IsMax implements the Datum interface.
+func (d *DEnum) IsMax(_ *EvalContext) bool {
+	return d.EnumTyp.PhysicalRepIndex(d.PhysicalRep) == d.EnumTyp.NumMembers()-1
+}
+
+// IsMin implements the Datum interface.
+func (d *DEnum) IsMin(_ *EvalContext) bool {
+	return d.EnumTyp.PhysicalRepIndex(d.PhysicalRep) == 0
+}
+
+// Max implements the Datum interface.
+func (d *DEnum) Max(_ *EvalContext) (Datum, bool) {
+	if d.EnumTyp.NumMembers() == 0 {
+		return nil, false
+	}
+	return d.enumMember(d.EnumTyp.NumMembers() - 1), true
+}
+
+// Min implements the Datum interface.
+func (d *DEnum) Min(_ *EvalContext) (Datum, bool) {
+	if d.EnumTyp.NumMembers() == 0 {
+		return nil, false
+	}
+	return d.enumMember(0), true
+}
+
+// AmbiguousFormat implements the Datum interface. Values of ENUM types are
+// formatted as plain string literals: annotating them with the name of their
+// type would require the type to be resolved when the formatted expression is
+// parsed again, for example in stored DEFAULT expressions. The literals are
+// resolved from the type of the context in which they are used instead.
+func (*DEnum) AmbiguousFormat() bool {
+	return false
+}
+
+// Format implements the NodeFormatter interface.
+func (d *DEnum) Format(ctx *FmtCtx) {
+	buf, f := ctx.Buffer, ctx.flags
+	if f.HasFlags(fmtUnicodeStrings) {
+		buf.WriteString(d.LogicalRep)
+	} else {
+		lex.EncodeSQLStringWithFlags(buf, d.LogicalRep, f.EncodeFlags())
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DEnum) Size() uintptr {
+	return unsafe.Sizeof(*d) + uintptr(len(d.PhysicalRep)) + uintptr(len(d.LogicalRep))
+}
+
 // DDate is the date Datum represented as the number of days after
 // the Unix epoch.
 type DDate int64
@@ -2431,7 +2566,8 @@ func AsJSON(d Datum) (json.JSON, error) {
 			builder.Add(fmt.Sprintf("f%d", i+1), j)
 		}
 		return builder.Build(), nil
-	case *DTimestamp, *DTimestampTZ, *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DBitArray:
+	case *DTimestamp, *DTimestampTZ, *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DBitArray,
+		*DTSVector, *DTSQuery:
 		return json.FromString(AsStringWithFlags(t, FmtBareStrings)), nil
 	default:
 		if d == DNull {
@@ -2518,6 +2654,224 @@ func (d *DJSON) Size() uintptr {
 	return unsafe.Sizeof(*d) + d.JSON.Size()
 }
 
+// DTSVector is the tsvector Datum, a document prepared for full text search.
+type DTSVector struct{ tsearch.TSVector }
+
+// NewDTSVector is a helper routine to create a *DTSVector initialized from its
+// argument.
+func NewDTSVector(v tsearch.TSVector) *DTSVector {
+	return &DTSVector{v}
+}
+
+// ParseDTSVector parses and returns the *DTSVector Datum value represented by
+// the provided string, or an error if parsing is unsuccessful.
+func ParseDTSVector(s string) (*DTSVector, error) {
+	v, err := tsearch.ParseTSVector(s)
+	if err != nil {
+		return nil, makeParseError(s, types.TSVector, err)
+	}
+	return NewDTSVector(v), nil
+}
+
+// AsDTSVector attempts to retrieve a *DTSVector from an Expr, returning a
+// *DTSVector and a flag signifying whether the assertion was successful. The
+// function should be used instead of direct type assertions wherever a
+// *DTSVector wrapped by a *DOidWrapper is possible.
+func AsDTSVector(e Expr) (*DTSVector, bool) {
+	switch t := e.(type) {
+	case *DTSVector:
+		return t, true
+	case *DOidWrapper:
+		return AsDTSVector(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDTSVector attempts to retrieve a *DTSVector from an Expr, panicking
+// if the assertion fails.
+func MustBeDTSVector(e Expr) *DTSVector {
+	v, ok := AsDTSVector(e)
+	if !ok {
+		panic(pgerror.NewAssertionErrorf("expected *DTSVector, found %T", e))
+	}
+	return v
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTSVector) ResolvedType() types.T {
+	return types.TSVector
+}
+
+// Compare implements the Datum interface.
+func (d *DTSVector) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DTSVector)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.TSVector.Compare(v.TSVector)
+}
+
+// Prev implements the Datum interface.
+func (d *DTSVector) Prev(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DTSVector) Next(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DTSVector) IsMax(_ *EvalContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DTSVector) IsMin(_ *EvalContext) bool {
+	return len(d.TSVector) == 0
+}
+
+// Max implements the Datum interface.
+func (d *DTSVector) Max(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DTSVector) Min(_ *EvalContext) (Datum, bool) {
+	return &DTSVector{}, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTSVector) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTSVector) Format(ctx *FmtCtx) {
+	s := d.TSVector.String()
+	if ctx.flags.HasFlags(fmtUnicodeStrings) {
+		ctx.Buffer.WriteString(s)
+		return
+	}
+	lex.EncodeSQLStringWithFlags(ctx.Buffer, s, ctx.flags.EncodeFlags())
+}
+
+// Size implements the Datum interface.
+func (d *DTSVector) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.TSVector.Size()
+}
+
+// DTSQuery is the tsquery Datum, a full text search query.
+type DTSQuery struct{ tsearch.TSQuery }
+
+// NewDTSQuery is a helper routine to create a *DTSQuery initialized from its
+// argument.
+func NewDTSQuery(q tsearch.TSQuery) *DTSQuery {
+	return &DTSQuery{q}
+}
+
+// ParseDTSQuery parses and returns the *DTSQuery Datum value represented by
+// the provided string, or an error if parsing is unsuccessful.
+func ParseDTSQuery(s string) (*DTSQuery, error) {
+	q, err := tsearch.ParseTSQuery(s)
+	if err != nil {
+		return nil, makeParseError(s, types.TSQuery, err)
+	}
+	return NewDTSQuery(q), nil
+}
+
+// AsDTSQuery attempts to retrieve a *DTSQuery from an Expr, returning a
+// *DTSQuery and a flag signifying whether the assertion was successful. The
+// function should be used instead of direct type assertions wherever a
+// *DTSQuery wrapped by a *DOidWrapper is possible.
+func AsDTSQuery(e Expr) (*DTSQuery, bool) {
+	switch t := e.(type) {
+	case *DTSQuery:
+		return t, true
+	case *DOidWrapper:
+		return AsDTSQuery(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDTSQuery attempts to retrieve a *DTSQuery from an Expr, panicking if
+// the assertion fails.
+func MustBeDTSQuery(e Expr) *DTSQuery {
+	q, ok := AsDTSQuery(e)
+	if !ok {
+		panic(pgerror.NewAssertionErrorf("expected *DTSQuery, found %T", e))
+	}
+	return q
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTSQuery) ResolvedType() types.T {
+	return types.TSQuery
+}
+
+// Compare implements the Datum interface.
+func (d *DTSQuery) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DTSQuery)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.TSQuery.Compare(v.TSQuery)
+}
+
+// Prev implements the Datum interface.
+func (d *DTSQuery) Prev(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DTSQuery) Next(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DTSQuery) IsMax(_ *EvalContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DTSQuery) IsMin(_ *EvalContext) bool {
+	return d.Empty()
+}
+
+// Max implements the Datum interface.
+func (d *DTSQuery) Max(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DTSQuery) Min(_ *EvalContext) (Datum, bool) {
+	return &DTSQuery{}, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTSQuery) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTSQuery) Format(ctx *FmtCtx) {
+	s := d.TSQuery.String()
+	if ctx.flags.HasFlags(fmtUnicodeStrings) {
+		ctx.Buffer.WriteString(s)
+		return
+	}
+	lex.EncodeSQLStringWithFlags(ctx.Buffer, s, ctx.flags.EncodeFlags())
+}
+
+// Size implements the Datum interface.
+func (d *DTSQuery) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.TSQuery.Size()
+}
+
 // DTuple is the tuple Datum.
 type DTuple struct {
 	D Datums
@@ -3499,6 +3853,8 @@ func DatumTypeSize(t types.T) (uintptr, bool) {
 	case types.TArray:
 		// TODO(jordan,justin): This seems suspicious.
 		return unsafe.Sizeof(DString("")), variableSize
+	case types.TEnum:
+		return unsafe.Sizeof(DEnum{}), variableSize
 	}
 
 	// All the primary types have fixed size information.
@@ -3532,6 +3888,8 @@ var baseDatumTypeSizes = map[types.T]struct {
 	types.TimestampTZ: {unsafe.Sizeof(DTimestampTZ{}), fixedSize},
 	types.Interval:    {unsafe.Sizeof(DInterval{}), fixedSize},
 	types.JSON:        {unsafe.Sizeof(DJSON{}), variableSize},
+	types.TSVector:    {unsafe.Sizeof(DTSVector{}), variableSize},
+	types.TSQuery:     {unsafe.Sizeof(DTSQuery{}), variableSize},
 	types.UUID:        {unsafe.Sizeof(DUuid{}), fixedSize},
 	types.INet:        {unsafe.Sizeof(DIPAddr{}), fixedSize},
 	// TODO(jordan,justin): This seems suspicious.
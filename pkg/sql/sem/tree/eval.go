@@ -43,6 +43,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/lib/pq/oid"
 	"github.com/pkg/errors"
@@ -1272,6 +1274,16 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return dd, err
 			},
 		},
+		// For strings, % is the similarity operator of pg_trgm.
+		&BinOp{
+			LeftType:   types.String,
+			RightType:  types.String,
+			ReturnType: types.Bool,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				sim := trigram.Similarity(string(MustBeDString(left)), string(MustBeDString(right)))
+				return MakeDBool(sim >= trigram.DefaultSimilarityThreshold), nil
+			},
+		},
 	},
 
 	Concat: {
@@ -1623,9 +1635,68 @@ func init() {
 			Fn:           cmpOpScalarIsFn,
 			NullableArgs: true,
 		})
+
+		// Array containment and overlap comparisons.
+		CmpOps[Contains] = append(CmpOps[Contains], &CmpOp{
+			LeftType:  types.TArray{Typ: t},
+			RightType: types.TArray{Typ: t},
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(arrayContains(ctx, MustBeDArray(left), MustBeDArray(right)))), nil
+			},
+		})
+
+		CmpOps[ContainedBy] = append(CmpOps[ContainedBy], &CmpOp{
+			LeftType:  types.TArray{Typ: t},
+			RightType: types.TArray{Typ: t},
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(arrayContains(ctx, MustBeDArray(right), MustBeDArray(left)))), nil
+			},
+		})
+
+		CmpOps[Overlaps] = append(CmpOps[Overlaps], &CmpOp{
+			LeftType:  types.TArray{Typ: t},
+			RightType: types.TArray{Typ: t},
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(arrayOverlaps(ctx, MustBeDArray(left), MustBeDArray(right)))), nil
+			},
+		})
 	}
 }
 
+// arrayContains returns whether every element of needles is also an element
+// of haystack. As in PostgreSQL, NULL elements are never equal to anything, so
+// needles is never contained if it has a NULL element.
+func arrayContains(ctx *EvalContext, haystack, needles *DArray) bool {
+	for _, n := range needles.Array {
+		if n == DNull || !arrayHasElement(ctx, haystack, n) {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayOverlaps returns whether the two arrays have a non-NULL element in
+// common.
+func arrayOverlaps(ctx *EvalContext, left, right *DArray) bool {
+	for _, e := range right.Array {
+		if e != DNull && arrayHasElement(ctx, left, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// arrayHasElement returns whether the non-NULL datum d is equal to one of the
+// elements of array.
+func arrayHasElement(ctx *EvalContext, array *DArray, d Datum) bool {
+	for _, e := range array.Array {
+		if e != DNull && e.Compare(ctx, d) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	for op, overload := range CmpOps {
 		for i, impl := range overload {
@@ -1682,6 +1753,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.Date, types.Date),
 		makeEqFn(types.Decimal, types.Decimal),
 		makeEqFn(types.FamCollatedString, types.FamCollatedString),
+		makeEqFn(types.FamEnum, types.FamEnum),
 		makeEqFn(types.Float, types.Float),
 		makeEqFn(types.INet, types.INet),
 		makeEqFn(types.Int, types.Int),
@@ -1692,6 +1764,8 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.Time, types.Time),
 		makeEqFn(types.Timestamp, types.Timestamp),
 		makeEqFn(types.TimestampTZ, types.TimestampTZ),
+		makeEqFn(types.TSQuery, types.TSQuery),
+		makeEqFn(types.TSVector, types.TSVector),
 		makeEqFn(types.UUID, types.UUID),
 		makeEqFn(types.BitArray, types.BitArray),
 
@@ -1726,6 +1800,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeLtFn(types.Date, types.Date),
 		makeLtFn(types.Decimal, types.Decimal),
 		makeLtFn(types.FamCollatedString, types.FamCollatedString),
+		makeLtFn(types.FamEnum, types.FamEnum),
 		makeLtFn(types.Float, types.Float),
 		makeLtFn(types.INet, types.INet),
 		makeLtFn(types.Int, types.Int),
@@ -1769,6 +1844,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeLeFn(types.Date, types.Date),
 		makeLeFn(types.Decimal, types.Decimal),
 		makeLeFn(types.FamCollatedString, types.FamCollatedString),
+		makeLeFn(types.FamEnum, types.FamEnum),
 		makeLeFn(types.Float, types.Float),
 		makeLeFn(types.INet, types.INet),
 		makeLeFn(types.Int, types.Int),
@@ -1820,6 +1896,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.Date, types.Date),
 		makeIsFn(types.Decimal, types.Decimal),
 		makeIsFn(types.FamCollatedString, types.FamCollatedString),
+		makeIsFn(types.FamEnum, types.FamEnum),
 		makeIsFn(types.Float, types.Float),
 		makeIsFn(types.INet, types.INet),
 		makeIsFn(types.Int, types.Int),
@@ -1830,6 +1907,8 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.Time, types.Time),
 		makeIsFn(types.Timestamp, types.Timestamp),
 		makeIsFn(types.TimestampTZ, types.TimestampTZ),
+		makeIsFn(types.TSQuery, types.TSQuery),
+		makeIsFn(types.TSVector, types.TSVector),
 		makeIsFn(types.UUID, types.UUID),
 		makeIsFn(types.BitArray, types.BitArray),
 
@@ -1867,6 +1946,7 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(types.Date),
 		makeEvalTupleIn(types.Decimal),
 		makeEvalTupleIn(types.FamCollatedString),
+		makeEvalTupleIn(types.FamEnum),
 		makeEvalTupleIn(types.FamTuple),
 		makeEvalTupleIn(types.Float),
 		makeEvalTupleIn(types.INet),
@@ -1878,6 +1958,8 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(types.Time),
 		makeEvalTupleIn(types.Timestamp),
 		makeEvalTupleIn(types.TimestampTZ),
+		makeEvalTupleIn(types.TSQuery),
+		makeEvalTupleIn(types.TSVector),
 		makeEvalTupleIn(types.UUID),
 		makeEvalTupleIn(types.BitArray),
 	},
@@ -2025,6 +2107,63 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			},
 		},
 	},
+
+	Overlaps: {
+		&CmpOp{
+			LeftType:  types.INet,
+			RightType: types.INet,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				ipAddr := MustBeDIPAddr(left).IPAddr
+				other := MustBeDIPAddr(right).IPAddr
+				return MakeDBool(DBool(ipAddr.ContainsOrContainedBy(&other))), nil
+			},
+		},
+	},
+
+	TSMatches: {
+		&CmpOp{
+			LeftType:  types.TSVector,
+			RightType: types.TSQuery,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(tsearch.Match(
+					MustBeDTSVector(left).TSVector, MustBeDTSQuery(right).TSQuery))), nil
+			},
+		},
+		&CmpOp{
+			LeftType:  types.TSQuery,
+			RightType: types.TSVector,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(tsearch.Match(
+					MustBeDTSVector(right).TSVector, MustBeDTSQuery(left).TSQuery))), nil
+			},
+		},
+		&CmpOp{
+			LeftType:  types.String,
+			RightType: types.TSQuery,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				v, err := tsearch.ToTSVector(tsearch.DefaultConfig, string(MustBeDString(left)))
+				if err != nil {
+					return nil, err
+				}
+				return MakeDBool(DBool(tsearch.Match(v, MustBeDTSQuery(right).TSQuery))), nil
+			},
+		},
+		&CmpOp{
+			LeftType:  types.String,
+			RightType: types.String,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				v, err := tsearch.ToTSVector(tsearch.DefaultConfig, string(MustBeDString(left)))
+				if err != nil {
+					return nil, err
+				}
+				q, err := tsearch.PlainToTSQuery(tsearch.DefaultConfig, string(MustBeDString(right)))
+				if err != nil {
+					return nil, err
+				}
+				return MakeDBool(DBool(tsearch.Match(v, q))), nil
+			},
+		},
+	},
 }
 
 // This map contains the inverses for operators in the CmpOps map that have
@@ -2383,6 +2522,49 @@ type SequenceOperators interface {
 	SetSequenceValue(ctx context.Context, seqName *TableName, newVal int64, isCalled bool) error
 }
 
+// ConstraintDeferrer is used by the foreign key and deferrable UNIQUE checks to
+// find out whether a constraint is deferred until the end of the transaction
+// and to queue its checks.
+type ConstraintDeferrer interface {
+	// IsConstraintDeferred returns whether the named constraint's checks should
+	// be deferred, given the constraint's own DEFERRABLE and INITIALLY DEFERRED
+	// attributes and any SET CONSTRAINTS issued in the current transaction.
+	IsConstraintDeferred(name string, deferrable, initiallyDeferred bool) bool
+
+	// DeferConstraintCheck queues a check to be run when the transaction
+	// commits.
+	DeferConstraintCheck(check DeferredConstraintCheck)
+
+	// QueueStatementConstraintCheck queues a check to be run when the current
+	// statement completes, and in any case before the transaction commits.
+	QueueStatementConstraintCheck(check DeferredConstraintCheck)
+
+	// HasPendingConstraintChecks returns whether any check is queued.
+	HasPendingConstraintChecks() bool
+}
+
+// Notifier is used by pg_notify() to send asynchronous notifications.
+type Notifier interface {
+	// SendNotification sends a notification on the given channel when the
+	// current transaction commits.
+	SendNotification(ctx context.Context, channel, payload string) error
+}
+
+// DeferredConstraintCheck is a constraint check queued until the end of the
+// statement or the transaction. The check of a foreign key fails if
+// ReferencingSpan contains a row while ReferencedSpan does not. The check of a
+// deferrable UNIQUE constraint fails if UniqueSpan contains more than one row.
+type DeferredConstraintCheck struct {
+	// Name is the name of the constraint being checked.
+	Name            string
+	ReferencingSpan roachpb.Span
+	ReferencedSpan  roachpb.Span
+	// UniqueSpan is only set for the checks of deferrable UNIQUE constraints.
+	UniqueSpan roachpb.Span
+	// Err is the error reported if the check fails.
+	Err error
+}
+
 // EvalContextTestingKnobs contains test knobs.
 type EvalContextTestingKnobs struct {
 	// AssertFuncExprReturnTypes indicates whether FuncExpr evaluations
@@ -2475,6 +2657,13 @@ type EvalContext struct {
 
 	Sequence SequenceOperators
 
+	// ConstraintDeferrer is set for statements executed by a session. The
+	// checks of constraints can only be deferred until the end of explicit
+	// transactions.
+	ConstraintDeferrer ConstraintDeferrer
+
+	Notifier Notifier
+
 	// Ths transaction in which the statement is executing.
 	Txn *client.Txn
 
@@ -3097,6 +3286,8 @@ func PerformCast(ctx *EvalContext, d Datum, t coltypes.CastTargetType) (Datum, e
 			s = t.UUID.String()
 		case *DIPAddr:
 			s = t.String()
+		case *DEnum:
+			s = t.LogicalRep
 		case *DString:
 			s = string(*t)
 		case *DCollatedString:
@@ -3108,6 +3299,10 @@ func PerformCast(ctx *EvalContext, d Datum, t coltypes.CastTargetType) (Datum, e
 			s = t.name
 		case *DJSON:
 			s = t.JSON.String()
+		case *DTSVector:
+			s = t.TSVector.String()
+		case *DTSQuery:
+			s = t.TSQuery.String()
 		}
 		switch c := t.(type) {
 		case *coltypes.TString:
@@ -3162,6 +3357,18 @@ func PerformCast(ctx *EvalContext, d Datum, t coltypes.CastTargetType) (Datum, e
 			return d, nil
 		}
 
+	case *coltypes.TEnum:
+		switch t := d.(type) {
+		case *DString:
+			return MakeDEnumFromLogicalRep(typ.Typ, string(*t))
+		case *DCollatedString:
+			return MakeDEnumFromLogicalRep(typ.Typ, t.Contents)
+		case *DEnum:
+			if t.EnumTyp.ID == typ.Typ.ID {
+				return d, nil
+			}
+		}
+
 	case *coltypes.TDate:
 		switch d := d.(type) {
 		case *DString:
@@ -3272,6 +3479,24 @@ func PerformCast(ctx *EvalContext, d Datum, t coltypes.CastTargetType) (Datum, e
 		case *DJSON:
 			return v, nil
 		}
+	case *coltypes.TTSVector:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSVector(string(*v))
+		case *DCollatedString:
+			return ParseDTSVector(v.Contents)
+		case *DTSVector:
+			return v, nil
+		}
+	case *coltypes.TTSQuery:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSQuery(string(*v))
+		case *DCollatedString:
+			return ParseDTSQuery(v.Contents)
+		case *DTSQuery:
+			return v, nil
+		}
 	case *coltypes.TArray:
 		switch v := d.(type) {
 		case *DString:
@@ -3883,6 +4108,11 @@ func (t *DIPAddr) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DEnum) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DDate) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -3918,6 +4148,16 @@ func (t *DJSON) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DTSVector) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DTSQuery) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t dNull) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
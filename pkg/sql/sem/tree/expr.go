@@ -314,6 +314,8 @@ const (
 	JSONExists
 	JSONSomeExists
 	JSONAllExists
+	Overlaps
+	TSMatches
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -363,6 +365,8 @@ var comparisonOpName = [...]string{
 	JSONExists:        "?",
 	JSONSomeExists:    "?|",
 	JSONAllExists:     "?&",
+	Overlaps:          "&&",
+	TSMatches:         "@@",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -1194,6 +1198,11 @@ type FuncExpr struct {
 	// Filter is used for filters on aggregates: SUM(k) FILTER (WHERE k > 0)
 	Filter    Expr
 	WindowDef *WindowDef
+	// WithinGroup is set for calls to ordered-set aggregates:
+	// percentile_disc(0.5) WITHIN GROUP (ORDER BY k). The WITHIN GROUP
+	// expression is stored as the first element of Exprs, before the direct
+	// arguments.
+	WithinGroup bool
 
 	typeAnnotation
 	fnProps *FunctionProperties
@@ -1217,6 +1226,7 @@ func NewTypedFuncExpr(
 		Exprs:          make(Exprs, len(exprs)),
 		Filter:         filter,
 		WindowDef:      windowDef,
+		WithinGroup:    props != nil && props.OrderedSetAggregate,
 		typeAnnotation: typeAnnotation{typ: typ},
 		fn:             overload,
 		fnProps:        props,
@@ -1311,11 +1321,23 @@ func (node *FuncExpr) Format(ctx *FmtCtx) {
 	// We need to remove name anonymization for the function name in
 	// particular. Do this by overriding the flags.
 	subCtx := ctx.CopyWithFlags(ctx.flags & ^FmtAnonymize)
-	subCtx.FormatNode(&node.Func)
+	if IsGroupingFuncExpr(node) {
+		// GROUPING is a keyword, which would otherwise be quoted.
+		ctx.WriteString("GROUPING")
+	} else {
+		subCtx.FormatNode(&node.Func)
+	}
 
 	ctx.WriteByte('(')
 	ctx.WriteString(typ)
-	ctx.FormatNode(&node.Exprs)
+	if node.WithinGroup && len(node.Exprs) > 0 {
+		directArgs := node.Exprs[1:]
+		ctx.FormatNode(&directArgs)
+		ctx.WriteString(") WITHIN GROUP (ORDER BY ")
+		ctx.FormatNode(node.Exprs[0])
+	} else {
+		ctx.FormatNode(&node.Exprs)
+	}
 	ctx.WriteByte(')')
 	if ctx.HasFlags(FmtParsable) && node.typ != nil {
 		if node.fnProps.AmbiguousReturnType {
@@ -1474,7 +1496,8 @@ var (
 	stringCastTypes = []types.T{types.Unknown, types.Bool, types.Int, types.Float, types.Decimal, types.String, types.FamCollatedString,
 		types.BitArray,
 		types.FamArray, types.FamTuple,
-		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.UUID, types.Date, types.Time, types.Oid, types.INet, types.JSON}
+		types.Bytes, types.Timestamp, types.TimestampTZ, types.Interval, types.UUID, types.Date, types.Time, types.Oid, types.INet, types.JSON,
+		types.FamEnum, types.TSVector, types.TSQuery}
 	bytesCastTypes = []types.T{types.Unknown, types.String, types.FamCollatedString, types.Bytes, types.UUID}
 	dateCastTypes  = []types.T{types.Unknown, types.String, types.FamCollatedString, types.Date, types.Timestamp, types.TimestampTZ, types.Int}
 	timeCastTypes  = []types.T{types.Unknown, types.String, types.FamCollatedString, types.Time,
@@ -1486,6 +1509,9 @@ var (
 	inetCastTypes      = []types.T{types.Unknown, types.String, types.FamCollatedString, types.INet}
 	arrayCastTypes     = []types.T{types.Unknown, types.String}
 	jsonCastTypes      = []types.T{types.Unknown, types.String, types.JSON}
+	enumCastTypes      = []types.T{types.Unknown, types.String, types.FamCollatedString, types.FamEnum}
+	tsvectorCastTypes  = []types.T{types.Unknown, types.String, types.FamCollatedString, types.TSVector}
+	tsqueryCastTypes   = []types.T{types.Unknown, types.String, types.FamCollatedString, types.TSQuery}
 )
 
 // validCastTypes returns a set of types that can be cast into the provided type.
@@ -1515,6 +1541,10 @@ func validCastTypes(t types.T) []types.T {
 		return intervalCastTypes
 	case types.JSON:
 		return jsonCastTypes
+	case types.TSVector:
+		return tsvectorCastTypes
+	case types.TSQuery:
+		return tsqueryCastTypes
 	case types.UUID:
 		return uuidCastTypes
 	case types.INet:
@@ -1530,6 +1560,8 @@ func validCastTypes(t types.T) []types.T {
 			ret := make([]types.T, len(arrayCastTypes))
 			copy(ret, arrayCastTypes)
 			return ret
+		} else if t.FamilyEqual(types.FamEnum) {
+			return enumCastTypes
 		}
 		return nil
 	}
@@ -1687,8 +1719,11 @@ func (node *DFloat) String() string           { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
 func (node *DJSON) String() string            { return AsString(node) }
+func (node *DTSVector) String() string        { return AsString(node) }
+func (node *DTSQuery) String() string         { return AsString(node) }
 func (node *DUuid) String() string            { return AsString(node) }
 func (node *DIPAddr) String() string          { return AsString(node) }
+func (node *DEnum) String() string            { return AsString(node) }
 func (node *DString) String() string          { return AsString(node) }
 func (node *DCollatedString) String() string  { return AsString(node) }
 func (node *DTimestamp) String() string       { return AsString(node) }
@@ -1700,6 +1735,7 @@ func (node *DOidWrapper) String() string      { return AsString(node) }
 func (node *Exprs) String() string            { return AsString(node) }
 func (node *ArrayFlatten) String() string     { return AsString(node) }
 func (node *FuncExpr) String() string         { return AsString(node) }
+func (node *GroupingSet) String() string      { return AsString(node) }
 func (node *IfExpr) String() string           { return AsString(node) }
 func (node *IfErrExpr) String() string        { return AsString(node) }
 func (node *IndexedVar) String() string       { return AsString(node) }
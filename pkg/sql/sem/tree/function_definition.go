@@ -90,6 +90,10 @@ type FunctionProperties struct {
 	// determined without extra context. This is used for formatting builtins
 	// with the FmtParsable directive.
 	AmbiguousReturnType bool
+
+	// OrderedSetAggregate is set to true for aggregate functions that must
+	// be called with a WITHIN GROUP clause, e.g. percentile_disc.
+	OrderedSetAggregate bool
 }
 
 // FunctionClass specifies the class of the builtin function.
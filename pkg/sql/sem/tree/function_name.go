@@ -47,6 +47,21 @@ func (fn *ResolvableFunctionReference) String() string { return AsString(fn) }
 // resolves it as necessary.
 func (fn *ResolvableFunctionReference) Resolve(
 	searchPath sessiondata.SearchPath,
+) (*FunctionDefinition, error) {
+	return fn.resolve(searchPath, nil /* resolver */)
+}
+
+// FunctionResolver resolves references to user-defined functions.
+type FunctionResolver interface {
+	// ResolveFunction returns the definition of the user-defined function
+	// with the given name, or nil if there is no such function.
+	ResolveFunction(name *UnresolvedName) (*FunctionDefinition, error)
+}
+
+// resolve is like Resolve, but falls back to the given resolver, if any,
+// for names that do not refer to builtin functions.
+func (fn *ResolvableFunctionReference) resolve(
+	searchPath sessiondata.SearchPath, resolver FunctionResolver,
 ) (*FunctionDefinition, error) {
 	switch t := fn.FunctionReference.(type) {
 	case *FunctionDefinition:
@@ -54,7 +69,17 @@ func (fn *ResolvableFunctionReference) Resolve(
 	case *UnresolvedName:
 		fd, err := t.ResolveFunction(searchPath)
 		if err != nil {
-			return nil, err
+			if resolver == nil {
+				return nil, err
+			}
+			udf, udfErr := resolver.ResolveFunction(t)
+			if udfErr != nil {
+				return nil, udfErr
+			}
+			if udf == nil {
+				return nil, err
+			}
+			fd = udf
 		}
 		fn.FunctionReference = fd
 		return fd, nil
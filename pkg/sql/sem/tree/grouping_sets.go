@@ -0,0 +1,508 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tree
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+)
+
+// GroupingFuncName is the name of the GROUPING function. GROUPING(args...)
+// returns a bit mask whose bits are set for the arguments that are not part
+// of the grouping set of the current row, the last argument corresponding to
+// the least significant bit.
+const GroupingFuncName = "grouping"
+
+// maxGroupingSets is the maximum number of grouping sets a GROUP BY clause
+// can expand to. This is the same limit as PostgreSQL's.
+const maxGroupingSets = 4096
+
+var errGroupingNotAllowed = pgerror.NewError(pgerror.CodeGroupingError,
+	"GROUPING can only appear in the SELECT list or HAVING clause of a grouped query")
+
+var errInvalidGroupingArg = pgerror.NewError(pgerror.CodeGroupingError,
+	"arguments to GROUPING must be grouping expressions of the associated query level")
+
+// NewGroupingFuncExpr creates a call to the GROUPING function.
+func NewGroupingFuncExpr(args Exprs) *FuncExpr {
+	return &FuncExpr{
+		Func:  ResolvableFunctionReference{FunctionReference: NewUnresolvedName(GroupingFuncName)},
+		Exprs: args,
+	}
+}
+
+// IsGroupingFuncExpr returns whether the expression is a call to the
+// GROUPING function.
+func IsGroupingFuncExpr(expr *FuncExpr) bool {
+	return isGroupingFuncRef(&expr.Func)
+}
+
+func isGroupingFuncRef(fn *ResolvableFunctionReference) bool {
+	name, ok := fn.FunctionReference.(*UnresolvedName)
+	return ok && !name.Star && name.NumParts == 1 && name.Parts[0] == GroupingFuncName
+}
+
+// The names of the columns added to the FROM clause of a SELECT clause
+// rewritten by ExpandGroupingSets.
+const (
+	groupingSetsTableName  = "crdb_grouping_sets"
+	groupingSetIDColName   = "crdb_grouping_set_id"
+	groupingInputTableName = "crdb_grouping_input"
+	groupingInputColName   = "crdb_grouping_input_row"
+)
+
+// ExpandGroupingSets rewrites a SELECT clause that uses grouping sets (GROUP
+// BY GROUPING SETS, ROLLUP or CUBE) or the GROUPING function into a SELECT
+// clause with a single aggregation, so that its input is only evaluated once.
+// Each input row is paired with the index of every grouping set, and the rows
+// are grouped by that index and by all the grouping expressions. Outside of
+// aggregate function arguments, the grouping expressions are replaced by NULL
+// for the grouping sets they are not part of, and the calls to GROUPING are
+// replaced by their result for each grouping set.
+//
+// The empty grouping set must produce a row even if the input is empty. If
+// there is one, the grouping sets are left joined with the input instead, and
+// the row made up when the input is empty is ignored by the aggregate
+// functions and only kept for the empty grouping sets.
+//
+// The SELECT clause is returned unchanged if it does not use grouping sets.
+// Grouping expressions are matched by their text, so a grouping expression
+// must be written the same way in the GROUP BY clause and elsewhere.
+func ExpandGroupingSets(
+	sel *SelectClause, searchPath sessiondata.SearchPath,
+) (*SelectClause, error) {
+	if !usesGroupingSets(sel) {
+		return sel, nil
+	}
+	if sel.Window != nil || len(sel.DistinctOn) > 0 {
+		return nil, pgerror.Unimplemented("grouping sets with window",
+			"grouping sets are not supported with window functions or DISTINCT ON")
+	}
+
+	sets, err := expandGroupBy(sel.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	e := groupingSetsExpander{
+		numSets:    len(sets),
+		exprIdx:    make(map[string]int),
+		searchPath: searchPath,
+	}
+	var emptySets Exprs
+	for id, set := range sets {
+		if len(set) == 0 {
+			emptySets = append(emptySets, NewDInt(DInt(id)))
+		}
+		for _, expr := range set {
+			key := groupingExprKey(expr)
+			i, ok := e.exprIdx[key]
+			if !ok {
+				i = len(e.exprs)
+				e.exprIdx[key] = i
+				e.exprs = append(e.exprs, StripParens(expr))
+				e.setIDs = append(e.setIDs, nil)
+			}
+			if n := len(e.setIDs[i]); n == 0 || e.setIDs[i][n-1] != id {
+				e.setIDs[i] = append(e.setIDs[i], id)
+			}
+		}
+	}
+	e.hasEmptySet = len(emptySets) > 0
+
+	res := *sel
+	res.Exprs = make(SelectExprs, len(sel.Exprs))
+	for i, target := range sel.Exprs {
+		if isStarSelectExpr(target) {
+			return nil, pgerror.Unimplemented("grouping sets with star",
+				"grouping sets are not supported with SELECT *")
+		}
+		res.Exprs[i] = target
+		newExpr, err := SimpleVisit(target.Expr, e.replace)
+		if err != nil {
+			return nil, err
+		}
+		res.Exprs[i].Expr = newExpr
+		// The columns keep the names they would have without the rewrite.
+		if target.As == "" {
+			name, err := GetRenderColName(searchPath, target)
+			if err != nil {
+				return nil, err
+			}
+			res.Exprs[i].As = UnrestrictedName(name)
+		}
+	}
+	if sel.Having != nil {
+		newExpr, err := SimpleVisit(sel.Having.Expr, e.replace)
+		if err != nil {
+			return nil, err
+		}
+		res.Having = &Where{Type: sel.Having.Type, Expr: newExpr}
+	}
+
+	res.GroupBy = make(GroupBy, 0, len(e.exprs)+1)
+	res.GroupBy = append(res.GroupBy, NewUnresolvedName(groupingSetIDColName))
+	for i, expr := range e.exprs {
+		res.GroupBy = append(res.GroupBy, e.groupingExpr(i, expr))
+	}
+
+	setRows := make([]Exprs, len(sets))
+	for id := range sets {
+		setRows[id] = Exprs{NewDInt(DInt(id))}
+	}
+	setsTable := newValuesTableExpr(setRows, groupingSetsTableName, groupingSetIDColName)
+	var from From
+	if sel.From != nil {
+		from = *sel.From
+	}
+	if !e.hasEmptySet {
+		res.From = &From{Tables: append(TableExprs{setsTable}, from.Tables...), AsOf: from.AsOf}
+		return &res, nil
+	}
+
+	// The input gets a column that is NULL only in the row made up by the
+	// left join when the input is empty.
+	input := TableExpr(newValuesTableExpr(
+		[]Exprs{{DBoolTrue}}, groupingInputTableName, groupingInputColName))
+	for i := len(from.Tables) - 1; i >= 0; i-- {
+		input = &JoinTableExpr{Join: AstCrossJoin, Left: from.Tables[i], Right: input}
+	}
+	cond := Expr(DBoolTrue)
+	if sel.Where != nil {
+		cond = sel.Where.Expr
+	}
+	res.From = &From{
+		Tables: TableExprs{&JoinTableExpr{
+			Join:  AstLeftJoin,
+			Left:  setsTable,
+			Right: input,
+			Cond:  &OnJoinCond{Expr: cond},
+		}},
+		AsOf: from.AsOf,
+	}
+	res.Where = nil
+
+	// The grouping sets that are not empty only have groups for actual input
+	// rows.
+	filter := &OrExpr{
+		Left: &ComparisonExpr{
+			Operator: In,
+			Left:     NewUnresolvedName(groupingSetIDColName),
+			Right:    &Tuple{Exprs: emptySets},
+		},
+		Right: &ComparisonExpr{
+			Operator: GT,
+			Left: &FuncExpr{
+				Func:  ResolvableFunctionReference{FunctionReference: NewUnresolvedName("count")},
+				Exprs: Exprs{NewUnresolvedName(groupingInputColName)},
+			},
+			Right: NewDInt(0),
+		},
+	}
+	if res.Having == nil {
+		res.Having = &Where{Type: AstHaving, Expr: filter}
+	} else {
+		res.Having = &Where{Type: AstHaving, Expr: &AndExpr{Left: res.Having.Expr, Right: filter}}
+	}
+	return &res, nil
+}
+
+// groupingSetsExpander holds the state of ExpandGroupingSets.
+type groupingSetsExpander struct {
+	numSets int
+	// exprs are the distinct grouping expressions; setIDs[i] are the indexes
+	// of the grouping sets that include exprs[i].
+	exprs   Exprs
+	exprIdx map[string]int
+	setIDs  [][]int
+	// hasEmptySet is set if the input is left joined with the grouping sets;
+	// see ExpandGroupingSets.
+	hasEmptySet bool
+
+	searchPath sessiondata.SearchPath
+}
+
+// groupingExpr returns the expression that replaces expr, an occurrence of the
+// i-th grouping expression: expr itself for the grouping sets that include
+// it, NULL for the others.
+func (e *groupingSetsExpander) groupingExpr(i int, expr Expr) Expr {
+	if len(e.setIDs[i]) == e.numSets {
+		return expr
+	}
+	ids := make(Exprs, len(e.setIDs[i]))
+	for j, id := range e.setIDs[i] {
+		ids[j] = NewDInt(DInt(id))
+	}
+	return &CaseExpr{Whens: []*When{{
+		Cond: &ComparisonExpr{
+			Operator: In,
+			Left:     NewUnresolvedName(groupingSetIDColName),
+			Right:    &Tuple{Exprs: ids},
+		},
+		Val: expr,
+	}}}
+}
+
+// replace is the SimpleVisit function that rewrites the SELECT list and the
+// HAVING clause.
+func (e *groupingSetsExpander) replace(expr Expr) (err error, recurse bool, newExpr Expr) {
+	switch t := expr.(type) {
+	case *Subquery:
+		return nil, false, expr
+
+	case *FuncExpr:
+		if IsGroupingFuncExpr(t) {
+			masks := make([]DInt, e.numSets)
+			for _, arg := range t.Exprs {
+				i, ok := e.exprIdx[groupingExprKey(arg)]
+				if !ok {
+					return errInvalidGroupingArg, false, expr
+				}
+				ids := e.setIDs[i]
+				for id := range masks {
+					masks[id] <<= 1
+					if len(ids) > 0 && ids[0] == id {
+						ids = ids[1:]
+					} else {
+						masks[id] |= 1
+					}
+				}
+			}
+			constant := true
+			for _, mask := range masks {
+				constant = constant && mask == masks[0]
+			}
+			if constant {
+				return nil, false, NewDInt(masks[0])
+			}
+			res := &CaseExpr{Expr: NewUnresolvedName(groupingSetIDColName)}
+			for id, mask := range masks {
+				res.Whens = append(res.Whens, &When{Cond: NewDInt(DInt(id)), Val: NewDInt(mask)})
+			}
+			return nil, false, res
+		}
+		if t.WindowDef != nil {
+			return pgerror.Unimplemented("grouping sets with window",
+				"grouping sets are not supported with window functions"), false, expr
+		}
+		// The arguments of aggregate functions are not affected by the
+		// grouping sets.
+		if def, err := t.Func.Resolve(e.searchPath); err == nil && def.Class == AggregateClass {
+			if !e.hasEmptySet {
+				return nil, false, expr
+			}
+			agg := *t
+			agg.Filter = NewUnresolvedName(groupingInputColName)
+			if t.Filter != nil {
+				agg.Filter = &AndExpr{Left: agg.Filter, Right: t.Filter}
+			}
+			return nil, false, &agg
+		}
+	}
+
+	if i, ok := e.exprIdx[groupingExprKey(expr)]; ok {
+		return nil, false, e.groupingExpr(i, expr)
+	}
+	return nil, true, expr
+}
+
+// newValuesTableExpr returns the table expression
+// (VALUES <rows...>) AS <tableName>(<colName>).
+func newValuesTableExpr(rows []Exprs, tableName, colName Name) *AliasedTableExpr {
+	return &AliasedTableExpr{
+		Expr: &Subquery{Select: &ParenSelect{Select: &Select{Select: &ValuesClause{Rows: rows}}}},
+		As:   AliasClause{Alias: tableName, Cols: NameList{colName}},
+	}
+}
+
+// usesGroupingSets returns whether the GROUP BY clause of the SELECT clause
+// contains grouping sets, or whether its SELECT list or HAVING clause calls
+// the GROUPING function.
+func usesGroupingSets(sel *SelectClause) bool {
+	for _, expr := range sel.GroupBy {
+		if _, ok := expr.(*GroupingSet); ok || isEmptyGroupingSet(expr) {
+			return true
+		}
+	}
+	found := false
+	findGrouping := func(expr Expr) (err error, recurse bool, newExpr Expr) {
+		if f, ok := expr.(*FuncExpr); ok && IsGroupingFuncExpr(f) {
+			found = true
+			return nil, false, expr
+		}
+		if _, ok := expr.(*Subquery); ok {
+			return nil, false, expr
+		}
+		return nil, !found, expr
+	}
+	for _, expr := range sel.Exprs {
+		_, _ = SimpleVisit(expr.Expr, findGrouping)
+	}
+	if sel.Having != nil {
+		_, _ = SimpleVisit(sel.Having.Expr, findGrouping)
+	}
+	return found
+}
+
+// expandGroupBy returns the grouping sets of a GROUP BY clause: the cross
+// product of the grouping sets of its elements.
+func expandGroupBy(groupBy GroupBy) ([]Exprs, error) {
+	sets := []Exprs{nil}
+	for _, elem := range groupBy {
+		elemSets, err := expandGroupingElement(elem)
+		if err != nil {
+			return nil, err
+		}
+		if len(sets)*len(elemSets) > maxGroupingSets {
+			return nil, errTooManyGroupingSets
+		}
+		newSets := make([]Exprs, 0, len(sets)*len(elemSets))
+		for _, set := range sets {
+			for _, elemSet := range elemSets {
+				newSet := make(Exprs, 0, len(set)+len(elemSet))
+				newSet = append(newSet, set...)
+				newSets = append(newSets, append(newSet, elemSet...))
+			}
+		}
+		sets = newSets
+	}
+	return sets, nil
+}
+
+var errTooManyGroupingSets = pgerror.NewErrorf(pgerror.CodeProgramLimitExceededError,
+	"too many grouping sets present (maximum %d)", maxGroupingSets)
+
+// expandGroupingElement returns the grouping sets of an element of a GROUP BY
+// clause.
+func expandGroupingElement(elem Expr) ([]Exprs, error) {
+	gs, ok := elem.(*GroupingSet)
+	if !ok {
+		if isEmptyGroupingSet(elem) {
+			return []Exprs{{}}, nil
+		}
+		return []Exprs{{StripParens(elem)}}, nil
+	}
+
+	switch gs.Type {
+	case Rollup:
+		// ROLLUP (a, b) is GROUPING SETS ((a, b), (a), ()).
+		units, err := groupingUnits(gs)
+		if err != nil {
+			return nil, err
+		}
+		sets := make([]Exprs, 0, len(units)+1)
+		for i := len(units); i >= 0; i-- {
+			sets = append(sets, flattenGroupingUnits(units[:i]))
+		}
+		return sets, nil
+
+	case Cube:
+		// CUBE (a, b) is GROUPING SETS ((a, b), (a), (b), ()).
+		units, err := groupingUnits(gs)
+		if err != nil {
+			return nil, err
+		}
+		if len(units) > 12 {
+			return nil, errTooManyGroupingSets
+		}
+		n := uint(len(units))
+		sets := make([]Exprs, 0, 1<<n)
+		for mask := (1 << n) - 1; mask >= 0; mask-- {
+			var subset []Exprs
+			for i := uint(0); i < n; i++ {
+				if mask&(1<<(n-1-i)) != 0 {
+					subset = append(subset, units[i])
+				}
+			}
+			sets = append(sets, flattenGroupingUnits(subset))
+		}
+		return sets, nil
+
+	default:
+		var sets []Exprs
+		for _, e := range gs.Exprs {
+			switch t := e.(type) {
+			case *GroupingSet:
+				nestedSets, err := expandGroupingElement(t)
+				if err != nil {
+					return nil, err
+				}
+				sets = append(sets, nestedSets...)
+			case *Tuple:
+				set := make(Exprs, len(t.Exprs))
+				for i := range t.Exprs {
+					set[i] = StripParens(t.Exprs[i])
+				}
+				sets = append(sets, set)
+			default:
+				sets = append(sets, Exprs{StripParens(e)})
+			}
+			if len(sets) > maxGroupingSets {
+				return nil, errTooManyGroupingSets
+			}
+		}
+		return sets, nil
+	}
+}
+
+// isEmptyGroupingSet returns whether an element of a GROUP BY clause is the
+// empty grouping set "()", which is parsed as an empty tuple.
+func isEmptyGroupingSet(elem Expr) bool {
+	t, ok := elem.(*Tuple)
+	return ok && len(t.Exprs) == 0
+}
+
+// groupingUnits returns the elements of a ROLLUP or CUBE; parenthesized lists
+// of expressions are treated as a single element.
+func groupingUnits(gs *GroupingSet) ([]Exprs, error) {
+	units := make([]Exprs, len(gs.Exprs))
+	for i, e := range gs.Exprs {
+		switch t := e.(type) {
+		case *GroupingSet:
+			return nil, pgerror.NewErrorf(pgerror.CodeSyntaxError,
+				"%s cannot be nested in %s", t.Type, gs.Type)
+		case *Tuple:
+			for _, te := range t.Exprs {
+				units[i] = append(units[i], StripParens(te))
+			}
+		default:
+			units[i] = Exprs{StripParens(e)}
+		}
+	}
+	return units, nil
+}
+
+func flattenGroupingUnits(units []Exprs) Exprs {
+	var set Exprs
+	for _, unit := range units {
+		set = append(set, unit...)
+	}
+	return set
+}
+
+// groupingExprKey returns the key used to match grouping expressions.
+func groupingExprKey(expr Expr) string {
+	return AsString(StripParens(expr))
+}
+
+// isStarSelectExpr returns whether the target is "*" or "<table>.*".
+func isStarSelectExpr(target SelectExpr) bool {
+	switch t := target.Expr.(type) {
+	case UnqualifiedStar, *AllColumnsSelector:
+		return true
+	case *UnresolvedName:
+		return t.Star
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tree
+
+import "github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+
+// Validate verifies that the locking clause (FOR UPDATE, FOR SHARE, etc.) can
+// be applied to the given select statement. It is shared by the heuristic
+// planner and the optimizer.
+//
+// Row-level locks are only supported on the tables that appear directly in
+// the FROM clause of a simple SELECT; the planners lock the rows read from
+// these tables.
+func (node LockingClause) Validate(stmt SelectStatement) error {
+	strength := ForNone
+	for _, item := range node {
+		strength = strength.Max(item.Strength)
+		for i := range item.Targets {
+			if item.Targets[i].ExplicitCatalog || item.Targets[i].ExplicitSchema {
+				return pgerror.NewErrorf(pgerror.CodeSyntaxError,
+					"%s must specify unqualified relation names", item.Strength)
+			}
+		}
+	}
+
+	sel, ok := stmt.(*SelectClause)
+	if !ok {
+		switch stmt.(type) {
+		case *UnionClause:
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"%s is not allowed with UNION/INTERSECT/EXCEPT", strength)
+		case *ValuesClause:
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"%s cannot be applied to VALUES", strength)
+		default:
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"%s is not allowed with %s", strength, stmt.StatementTag())
+		}
+	}
+	switch {
+	case sel.Distinct:
+		return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"%s is not allowed with DISTINCT clause", strength)
+	case len(sel.GroupBy) > 0:
+		return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"%s is not allowed with GROUP BY clause", strength)
+	case sel.Having != nil:
+		return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"%s is not allowed with HAVING clause", strength)
+	case len(sel.Window) > 0:
+		return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"%s is not allowed with window functions", strength)
+	}
+
+	// Every locking target must name a data source of the FROM clause.
+	var sources []Name
+	for _, t := range sel.From.Tables {
+		sources = appendLockingSourceNames(sources, t)
+	}
+	for _, item := range node {
+	targetLoop:
+		for i := range item.Targets {
+			for _, name := range sources {
+				if name == item.Targets[i].TableName {
+					continue targetLoop
+				}
+			}
+			return pgerror.NewErrorf(pgerror.CodeUndefinedTableError,
+				"relation %q in %s clause not found in FROM clause",
+				ErrString(&item.Targets[i].TableName), item.Strength)
+		}
+	}
+	return nil
+}
+
+// ForSource returns the locking strength and wait policy that the locking
+// clause requests for the given data source of the FROM clause: the
+// strongest strength and the highest-precedence wait policy of the items that
+// either have no targets or name the data source.
+func (node LockingClause) ForSource(
+	source *AliasedTableExpr,
+) (LockingStrength, LockingWaitPolicy) {
+	name := LockingSourceName(source)
+	strength, waitPolicy := ForNone, LockWaitBlock
+	for _, item := range node {
+		matches := len(item.Targets) == 0
+		for i := range item.Targets {
+			if name != "" && item.Targets[i].TableName == name {
+				matches = true
+				break
+			}
+		}
+		if matches {
+			strength = strength.Max(item.Strength)
+			waitPolicy = waitPolicy.Max(item.WaitPolicy)
+		}
+	}
+	return strength, waitPolicy
+}
+
+// appendLockingSourceNames appends the names by which the data sources of the
+// given FROM clause item can be referenced by a locking clause.
+func appendLockingSourceNames(names []Name, t TableExpr) []Name {
+	switch s := t.(type) {
+	case *AliasedTableExpr:
+		if name := LockingSourceName(s); name != "" {
+			names = append(names, name)
+		}
+	case *ParenTableExpr:
+		names = appendLockingSourceNames(names, s.Expr)
+	case *JoinTableExpr:
+		names = appendLockingSourceNames(names, s.Left)
+		names = appendLockingSourceNames(names, s.Right)
+	}
+	return names
+}
+
+// LockingSourceName returns the name by which a data source can be referenced
+// by a locking clause: its alias if it has one, or else the name of the table.
+func LockingSourceName(t *AliasedTableExpr) Name {
+	if t.As.Alias != "" {
+		return t.As.Alias
+	}
+	if tn, ok := t.Expr.(*TableName); ok {
+		return tn.TableName
+	}
+	return ""
+}
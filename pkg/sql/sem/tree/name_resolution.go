@@ -296,6 +296,15 @@ func (t *TableName) ResolveExisting(
 	searchPath sessiondata.SearchPath,
 ) (bool, NameResolutionResult, error) {
 	if t.ExplicitSchema {
+		if t.SchemaName == sessiondata.PgTempSchemaName {
+			// pg_temp is an alias for the temporary schema of the session. If
+			// the session has none, there is nothing to find.
+			tempSchemaName := searchPath.GetTemporarySchemaName()
+			if tempSchemaName == "" {
+				return false, nil, nil
+			}
+			t.SchemaName = Name(tempSchemaName)
+		}
 		if t.ExplicitCatalog {
 			// Already 3 parts: nothing to search. Delegate to the resolver.
 			return r.LookupObject(ctx, requireMutable, t.Catalog(), t.Schema(), t.Table())
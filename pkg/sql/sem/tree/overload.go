@@ -51,6 +51,12 @@ type Overload struct {
 	WindowFunc    func([]types.T, *EvalContext) WindowFunc
 	Fn            func(*EvalContext, Datums) (Datum, error)
 	Generator     GeneratorFactory
+
+	// InlineBody is set for user-defined functions whose body is a single
+	// scalar expression. The expression refers to the arguments of the
+	// function as placeholders $1, $2, etc., and is substituted for calls to
+	// the function by the optimizer.
+	InlineBody Expr
 }
 
 // params implements the overloadImpl interface.
@@ -769,6 +775,12 @@ func checkReturn(
 		p := o.params()
 		for _, i := range s.constIdxs {
 			des := p.GetAt(i)
+			if des != nil && des.FamilyEqual(types.FamEnum) {
+				// A parameter accepting any ENUM does not specify the labels
+				// needed to resolve a constant. Use the type of a typed
+				// argument of the same ENUM type instead.
+				des = concreteEnumType(s, des)
+			}
 			typ, err := s.exprs[i].TypeCheck(ctx, des)
 			if err != nil {
 				return s.typedExprs, nil, true, errors.Wrap(err, "error type checking constant value")
@@ -795,6 +807,17 @@ func checkReturn(
 	}
 }
 
+// concreteEnumType returns the type of the first typed argument in s that is
+// an ENUM, or des if there is no such argument.
+func concreteEnumType(s typeCheckOverloadState, des types.T) types.T {
+	for _, i := range s.resolvableIdxs {
+		if typ := s.typedExprs[i].ResolvedType(); typ.FamilyEqual(types.FamEnum) {
+			return typ
+		}
+	}
+	return des
+}
+
 func formatCandidates(prefix string, candidates []overloadImpl) string {
 	var buf bytes.Buffer
 	for _, candidate := range candidates {
@@ -94,9 +94,16 @@ func parseStringAs(t types.T, s string, ctx ParseTimeContext) (Datum, error) {
 		return ParseDTimestamp(ctx, s, time.Microsecond)
 	case types.TimestampTZ:
 		return ParseDTimestampTZ(ctx, s, time.Microsecond)
+	case types.TSQuery:
+		return ParseDTSQuery(s)
+	case types.TSVector:
+		return ParseDTSVector(s)
 	case types.UUID:
 		return ParseDUuidFromString(s)
 	default:
+		if e, ok := t.(types.TEnum); ok {
+			return MakeDEnumFromLogicalRep(e, s)
+		}
 		return nil, nil
 	}
 }
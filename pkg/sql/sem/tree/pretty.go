@@ -484,9 +484,37 @@ func (node *Select) docTable(p *PrettyCfg) []pretty.RLTableRow {
 	}
 	items = append(items, node.OrderBy.docRow(p))
 	items = append(items, node.Limit.docTable(p)...)
+	items = append(items, node.Locking.docTable(p)...)
 	return items
 }
 
+func (node *LockingClause) doc(p *PrettyCfg) pretty.Doc {
+	return p.rlTable(node.docTable(p)...)
+}
+
+func (node *LockingClause) docTable(p *PrettyCfg) []pretty.RLTableRow {
+	items := make([]pretty.RLTableRow, len(*node))
+	for i, n := range *node {
+		items[i] = n.docRow(p)
+	}
+	return items
+}
+
+func (node *LockingItem) doc(p *PrettyCfg) pretty.Doc {
+	return p.unrow(node.docRow(p))
+}
+
+func (node *LockingItem) docRow(p *PrettyCfg) pretty.RLTableRow {
+	d := pretty.Nil
+	if len(node.Targets) > 0 {
+		d = pretty.ConcatSpace(pretty.Text("OF"), p.Doc(&node.Targets))
+	}
+	if node.WaitPolicy != LockWaitBlock {
+		d = pretty.ConcatSpace(d, pretty.Text(node.WaitPolicy.String()))
+	}
+	return p.row(node.Strength.String(), d)
+}
+
 func (node *SelectClause) doc(p *PrettyCfg) pretty.Doc {
 	return p.rlTable(node.docTable(p)...)
 }
@@ -565,7 +593,11 @@ func (node *With) docRow(p *PrettyCfg) pretty.RLTableRow {
 			pretty.Bracket("AS (", p.Doc(cte.Stmt), ")"),
 		)
 	}
-	return p.row("WITH", pretty.Join(",", d...))
+	kw := "WITH"
+	if node.Recursive {
+		kw = "WITH RECURSIVE"
+	}
+	return p.row(kw, pretty.Join(",", d...))
 }
 
 func (node *Subquery) doc(p *PrettyCfg) pretty.Doc {
@@ -610,9 +642,16 @@ func (node *AliasedTableExpr) doc(p *PrettyCfg) pretty.Doc {
 
 func (node *FuncExpr) doc(p *PrettyCfg) pretty.Doc {
 	d := p.Doc(&node.Func)
+	if IsGroupingFuncExpr(node) {
+		d = pretty.Text("GROUPING")
+	}
 
-	if len(node.Exprs) > 0 {
-		args := node.Exprs.doc(p)
+	exprs := node.Exprs
+	if node.WithinGroup && len(exprs) > 0 {
+		exprs = exprs[1:]
+	}
+	if len(exprs) > 0 {
+		args := exprs.doc(p)
 		if node.Type != 0 {
 			args = pretty.ConcatLine(
 				pretty.Text(funcTypeName[node.Type]),
@@ -623,6 +662,14 @@ func (node *FuncExpr) doc(p *PrettyCfg) pretty.Doc {
 	} else {
 		d = pretty.Concat(d, pretty.Text("()"))
 	}
+	if node.WithinGroup && len(node.Exprs) > 0 {
+		d = pretty.Fold(pretty.ConcatSpace,
+			d,
+			pretty.Text("WITHIN GROUP"),
+			pretty.Bracket("(",
+				p.nestUnder(pretty.Text("ORDER BY"), p.Doc(node.Exprs[0])),
+				")"))
+	}
 	if node.Filter != nil {
 		d = pretty.Fold(pretty.ConcatSpace,
 			d,
@@ -796,9 +843,16 @@ func (node *Insert) doc(p *PrettyCfg) pretty.Doc {
 
 	if node.OnConflict != nil && !node.OnConflict.IsUpsertAlias() {
 		cond := pretty.Nil
+		if node.OnConflict.Constraint != "" {
+			cond = pretty.ConcatSpace(pretty.Text("ON CONSTRAINT"), p.Doc(&node.OnConflict.Constraint))
+		}
 		if len(node.OnConflict.Columns) > 0 {
 			cond = pretty.Bracket("(", p.Doc(&node.OnConflict.Columns), ")")
 		}
+		if node.OnConflict.ArbiterPredicate != nil {
+			cond = pretty.ConcatSpace(cond,
+				pretty.ConcatSpace(pretty.Text("WHERE"), p.Doc(node.OnConflict.ArbiterPredicate)))
+		}
 		items = append(items, p.row("ON CONFLICT", cond))
 
 		if node.OnConflict.DoNothing {
@@ -976,7 +1030,7 @@ func (p *PrettyCfg) docReturning(node ReturningClause) pretty.RLTableRow {
 }
 
 func (node *Order) doc(p *PrettyCfg) pretty.Doc {
-	var d pretty.Doc
+	d := pretty.Nil
 	if node.OrderType == OrderByColumn {
 		d = p.Doc(node.Expr)
 	} else {
@@ -1016,6 +1070,9 @@ func (node *UpdateExpr) doc(p *PrettyCfg) pretty.Doc {
 
 func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 	title := "CREATE TABLE "
+	if node.Temporary {
+		title = "CREATE TEMPORARY TABLE "
+	}
 	if node.IfNotExists {
 		title += "IF NOT EXISTS "
 	}
@@ -1030,6 +1087,12 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 				pretty.Bracket("(", p.Doc(&node.AsColumnNames), ")"),
 			)
 		}
+		if len(node.StorageParams) > 0 {
+			d = pretty.ConcatSpace(
+				d,
+				pretty.Bracket("WITH (", p.Doc(&node.StorageParams), ")"),
+			)
+		}
 		d = p.nestUnder(
 			pretty.ConcatSpace(
 				d,
@@ -1048,14 +1111,21 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 		if node.PartitionBy != nil {
 			docs = append(docs, p.Doc(node.PartitionBy))
 		}
+		if len(node.StorageParams) > 0 {
+			docs = append(docs, pretty.Bracket("WITH (", p.Doc(&node.StorageParams), ")"))
+		}
 		d = pretty.Group(pretty.Stack(docs...))
 	}
 	return d
 }
 
 func (node *CreateView) doc(p *PrettyCfg) pretty.Doc {
+	title := "CREATE VIEW"
+	if node.Materialized {
+		title = "CREATE MATERIALIZED VIEW"
+	}
 	d := pretty.ConcatSpace(
-		pretty.Text("CREATE VIEW"),
+		pretty.Text(title),
 		p.Doc(&node.Name),
 	)
 	if len(node.ColumnNames) > 0 {
@@ -1234,6 +1304,13 @@ func (node *InterleaveDef) doc(p *PrettyCfg) pretty.Doc {
 	return d
 }
 
+func (node *ShardedIndexDef) doc(p *PrettyCfg) pretty.Doc {
+	return pretty.Fold(pretty.ConcatSpace,
+		pretty.Text("USING HASH WITH BUCKET_COUNT ="),
+		p.Doc(node.ShardBuckets),
+	)
+}
+
 func (node *CreateIndex) doc(p *PrettyCfg) pretty.Doc {
 	d := pretty.Text("CREATE")
 	if node.Unique {
@@ -1258,6 +1335,9 @@ func (node *CreateIndex) doc(p *PrettyCfg) pretty.Doc {
 			pretty.Bracket("(", p.Doc(&node.Columns), ")")),
 	}
 
+	if node.Sharded != nil {
+		docs = append(docs, p.Doc(node.Sharded))
+	}
 	if len(node.Storing) > 0 {
 		docs = append(docs, pretty.Bracket(
 			"STORING (",
@@ -1271,6 +1351,9 @@ func (node *CreateIndex) doc(p *PrettyCfg) pretty.Doc {
 	if node.PartitionBy != nil {
 		docs = append(docs, p.Doc(node.PartitionBy))
 	}
+	if node.Predicate != nil {
+		docs = append(docs, p.nestUnder(pretty.Text("WHERE"), p.Doc(node.Predicate)))
+	}
 	return pretty.Group(pretty.Stack(docs...))
 }
 
@@ -1362,10 +1445,14 @@ func (node *ColumnTableDef) doc(p *PrettyCfg) pretty.Doc {
 		docs = append(docs, d)
 	}
 	if node.IsComputed() {
+		kind := ") STORED"
+		if node.Computed.Virtual {
+			kind = ") VIRTUAL"
+		}
 		docs = append(docs, pretty.Bracket(
 			"AS (",
 			p.Doc(node.Computed.Expr),
-			") STORED",
+			kind,
 		))
 	}
 	if node.HasColumnFamily() {
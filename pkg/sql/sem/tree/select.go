@@ -45,6 +45,7 @@ type Select struct {
 	Select  SelectStatement
 	OrderBy OrderBy
 	Limit   *Limit
+	Locking LockingClause
 }
 
 // Format implements the NodeFormatter interface.
@@ -59,6 +60,10 @@ func (node *Select) Format(ctx *FmtCtx) {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(node.Limit)
 	}
+	if len(node.Locking) > 0 {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.Locking)
+	}
 }
 
 // ParenSelect represents a parenthesized SELECT/UNION/VALUES statement.
@@ -332,11 +337,17 @@ type AliasedTableExpr struct {
 	Expr       TableExpr
 	IndexFlags *IndexFlags
 	Ordinality bool
-	As         AliasClause
+	// Lateral is set if the table expression can refer to the columns of
+	// the data sources that precede it in the FROM clause.
+	Lateral bool
+	As      AliasClause
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AliasedTableExpr) Format(ctx *FmtCtx) {
+	if node.Lateral {
+		ctx.WriteString("LATERAL ")
+	}
 	ctx.FormatNode(node.Expr)
 	if node.IndexFlags != nil {
 		ctx.FormatNode(node.IndexFlags)
@@ -493,6 +504,46 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetType is the kind of a GroupingSet.
+type GroupingSetType int
+
+// GroupingSet.Type
+const (
+	GroupingSets GroupingSetType = iota
+	Rollup
+	Cube
+)
+
+var groupingSetTypeName = [...]string{
+	GroupingSets: "GROUPING SETS",
+	Rollup:       "ROLLUP",
+	Cube:         "CUBE",
+}
+
+func (i GroupingSetType) String() string {
+	if i < 0 || i > GroupingSetType(len(groupingSetTypeName)-1) {
+		return fmt.Sprintf("GroupingSetType(%d)", i)
+	}
+	return groupingSetTypeName[i]
+}
+
+// GroupingSet represents a GROUPING SETS, ROLLUP or CUBE element of a GROUP
+// BY clause. The elements of ROLLUP and CUBE are expressions or parenthesized
+// lists of expressions (Tuples). The elements of GROUPING SETS can also be
+// empty Tuples and nested GroupingSets.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
@@ -603,6 +654,107 @@ func (node *Limit) Format(ctx *FmtCtx) {
 	}
 }
 
+// LockingClause represents the locking clause of a SELECT statement, which
+// consists of zero or more FOR UPDATE / FOR SHARE items.
+type LockingClause []*LockingItem
+
+// Format implements the NodeFormatter interface.
+func (node *LockingClause) Format(ctx *FmtCtx) {
+	for i, n := range *node {
+		if i > 0 {
+			ctx.WriteByte(' ')
+		}
+		ctx.FormatNode(n)
+	}
+}
+
+// LockingItem represents a single locking item in a locking clause.
+type LockingItem struct {
+	Strength   LockingStrength
+	Targets    TableNames
+	WaitPolicy LockingWaitPolicy
+}
+
+// Format implements the NodeFormatter interface.
+func (node *LockingItem) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Strength.String())
+	if len(node.Targets) > 0 {
+		ctx.WriteString(" OF ")
+		ctx.FormatNode(&node.Targets)
+	}
+	if node.WaitPolicy != LockWaitBlock {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.WaitPolicy.String())
+	}
+}
+
+// LockingStrength represents the strength of the row-level locks requested
+// by a locking item. The values are ordered from weakest to strongest.
+type LockingStrength byte
+
+// The supported locking strengths.
+const (
+	ForNone LockingStrength = iota
+	ForKeyShare
+	ForShare
+	ForNoKeyUpdate
+	ForUpdate
+)
+
+var lockingStrengthName = [...]string{
+	ForNone:        "",
+	ForKeyShare:    "FOR KEY SHARE",
+	ForShare:       "FOR SHARE",
+	ForNoKeyUpdate: "FOR NO KEY UPDATE",
+	ForUpdate:      "FOR UPDATE",
+}
+
+func (s LockingStrength) String() string {
+	return lockingStrengthName[s]
+}
+
+// Max returns the stronger of the two locking strengths.
+func (s LockingStrength) Max(s2 LockingStrength) LockingStrength {
+	if s2 > s {
+		return s2
+	}
+	return s
+}
+
+// LockingWaitPolicy represents the policy a locking item uses when it
+// encounters a row that is locked by another transaction. The values are
+// ordered by precedence: when several locking items apply to the same table,
+// the one with the highest precedence is used.
+type LockingWaitPolicy byte
+
+// The supported locking wait policies.
+const (
+	// LockWaitBlock waits for the conflicting lock to be released.
+	LockWaitBlock LockingWaitPolicy = iota
+	// LockWaitSkip skips rows that cannot be locked (SKIP LOCKED).
+	LockWaitSkip
+	// LockWaitError returns an error for rows that cannot be locked (NOWAIT).
+	LockWaitError
+)
+
+var lockingWaitPolicyName = [...]string{
+	LockWaitBlock: "",
+	LockWaitSkip:  "SKIP LOCKED",
+	LockWaitError: "NOWAIT",
+}
+
+func (p LockingWaitPolicy) String() string {
+	return lockingWaitPolicyName[p]
+}
+
+// Max returns the wait policy with the higher precedence of the two.
+func (p LockingWaitPolicy) Max(p2 LockingWaitPolicy) LockingWaitPolicy {
+	if p2 > p {
+		return p2
+	}
+	return p
+}
+
 // RowsFromExpr represents a ROWS FROM(...) expression.
 type RowsFromExpr struct {
 	Items Exprs
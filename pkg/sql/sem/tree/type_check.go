@@ -64,6 +64,52 @@ type SemaContext struct {
 	AsOfTimestamp *hlc.Timestamp
 
 	Properties SemaProperties
+
+	// TypeResolver is used to resolve references to user-defined types. If
+	// it is nil, such references result in an error.
+	TypeResolver TypeResolver
+
+	// FunctionResolver is used to resolve references to user-defined
+	// functions. If it is nil, only builtin functions can be used.
+	FunctionResolver FunctionResolver
+}
+
+// TypeResolver resolves references to user-defined types.
+type TypeResolver interface {
+	// ResolveType returns the column type of the user-defined type with the
+	// given name.
+	ResolveType(name string) (coltypes.T, error)
+}
+
+// ResolveCastTargetType resolves t if it is a reference to a user-defined
+// type. Other types are returned unchanged.
+func (sc *SemaContext) ResolveCastTargetType(
+	t coltypes.CastTargetType,
+) (coltypes.CastTargetType, error) {
+	ref, ok := t.(*coltypes.TUserDefined)
+	if !ok {
+		return t, nil
+	}
+	if sc == nil || sc.TypeResolver == nil {
+		return nil, NewUndefinedTypeError(ref.Name)
+	}
+	return sc.TypeResolver.ResolveType(ref.Name)
+}
+
+// ResolveFunction resolves the function referenced by fn. Functions that
+// are not builtins are looked up with the FunctionResolver, if any.
+func (sc *SemaContext) ResolveFunction(
+	fn *ResolvableFunctionReference,
+) (*FunctionDefinition, error) {
+	if isGroupingFuncRef(fn) {
+		// The calls to GROUPING are replaced by ExpandGroupingSets; the
+		// remaining ones are misplaced.
+		return nil, errGroupingNotAllowed
+	}
+	if sc == nil {
+		return fn.Resolve(sessiondata.SearchPath{})
+	}
+	return fn.resolve(sc.SearchPath, sc.FunctionResolver)
 }
 
 // SemaProperties is a holder for required and derived properties
@@ -423,6 +469,11 @@ func isCastDeepValid(castFrom, castTo types.T) bool {
 
 // TypeCheck implements the Expr interface.
 func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ types.T) (TypedExpr, error) {
+	colType, err := ctx.ResolveCastTargetType(expr.Type)
+	if err != nil {
+		return nil, err
+	}
+	expr.Type = colType
 	returnType := expr.castType()
 
 	// The desired type provided to a CastExpr is ignored. Instead,
@@ -441,7 +492,7 @@ func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ types.T) (TypedExpr, error)
 			// precision), the CastExpr becomes a no-op and can be elided.
 			switch expr.Type.(type) {
 			case *coltypes.TBool, *coltypes.TDate, *coltypes.TTime, *coltypes.TTimestamp, *coltypes.TTimestampTZ,
-				*coltypes.TInterval, *coltypes.TBytes:
+				*coltypes.TInterval, *coltypes.TBytes, *coltypes.TEnum:
 				return expr.Expr.TypeCheck(ctx, returnType)
 			}
 		}
@@ -502,6 +553,11 @@ func (expr *IndirectionExpr) TypeCheck(ctx *SemaContext, desired types.T) (Typed
 
 // TypeCheck implements the Expr interface.
 func (expr *AnnotateTypeExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, error) {
+	colType, err := ctx.ResolveCastTargetType(expr.Type)
+	if err != nil {
+		return nil, err
+	}
+	expr.Type = colType
 	annotType := expr.annotationType()
 	subExpr, err := typeCheckAndRequire(ctx, expr.Expr, annotType,
 		fmt.Sprintf("type annotation for %v as %s, found", expr.Expr, annotType))
@@ -539,6 +595,13 @@ func NewTypeIsNotCompositeError(resolvedType types.T) error {
 	)
 }
 
+// NewUndefinedTypeError creates an error for a reference to a type that does
+// not exist.
+func NewUndefinedTypeError(name string) error {
+	return pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+		"type %q does not exist", name)
+}
+
 // TypeCheck implements the Expr interface.
 func (expr *TupleStar) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, error) {
 	subExpr, err := expr.Expr.TypeCheck(ctx, desired)
@@ -662,6 +725,7 @@ var (
 	errInvalidDefaultUsage  = pgerror.NewError(pgerror.CodeSyntaxError, "DEFAULT can only appear in a VALUES list within INSERT or on the right side of a SET")
 	errInvalidMaxUsage      = pgerror.NewError(pgerror.CodeSyntaxError, "MAXVALUE can only appear within a range partition expression")
 	errInvalidMinUsage      = pgerror.NewError(pgerror.CodeSyntaxError, "MINVALUE can only appear within a range partition expression")
+	errInvalidGroupingSet   = pgerror.NewError(pgerror.CodeSyntaxError, "GROUPING SETS, ROLLUP and CUBE can only appear within a GROUP BY clause")
 	errPrivateFunction      = pgerror.NewError(pgerror.CodeFeatureNotSupportedError, "function reserved for internal use")
 	errInsufficientPriv     = pgerror.NewError(pgerror.CodeInsufficientPrivilegeError, "insufficient privilege")
 )
@@ -757,11 +821,7 @@ func (sc *SemaContext) checkFunctionUsage(expr *FuncExpr, def *FunctionDefinitio
 
 // TypeCheck implements the Expr interface.
 func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, error) {
-	var searchPath sessiondata.SearchPath
-	if ctx != nil {
-		searchPath = ctx.SearchPath
-	}
-	def, err := expr.Func.Resolve(searchPath)
+	def, err := ctx.ResolveFunction(&expr.Func)
 	if err != nil {
 		return nil, err
 	}
@@ -769,6 +829,19 @@ func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, e
 	if err := ctx.checkFunctionUsage(expr, def); err != nil {
 		return nil, errors.Wrapf(err, "%s()", def.Name)
 	}
+	if expr.WithinGroup {
+		if !def.OrderedSetAggregate {
+			return nil, pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+				"%s is not an ordered-set aggregate, so it cannot have WITHIN GROUP", &expr.Func)
+		}
+		if expr.WindowDef != nil {
+			return nil, pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+				"OVER is not supported for ordered-set aggregate %s", &expr.Func)
+		}
+	} else if def.OrderedSetAggregate {
+		return nil, pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+			"WITHIN GROUP is required for ordered-set aggregate %s", &expr.Func)
+	}
 	if ctx != nil {
 		// We'll need to remember we are in a function application to
 		// generate suitable errors in checkFunctionUsage().  We cannot
@@ -1007,6 +1080,13 @@ func (expr *IfExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, err
 
 // TypeCheck implements the Expr interface.
 func (expr *IsOfTypeExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, error) {
+	for i, t := range expr.Types {
+		colType, err := ctx.ResolveCastTargetType(t)
+		if err != nil {
+			return nil, err
+		}
+		expr.Types[i] = colType.(coltypes.T)
+	}
 	exprTyped, err := expr.Expr.TypeCheck(ctx, types.Any)
 	if err != nil {
 		return nil, err
@@ -1171,6 +1251,11 @@ func (expr DefaultVal) TypeCheck(_ *SemaContext, desired types.T) (TypedExpr, er
 	return nil, errInvalidDefaultUsage
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSet) TypeCheck(_ *SemaContext, desired types.T) (TypedExpr, error) {
+	return nil, errInvalidGroupingSet
+}
+
 // TypeCheck implements the Expr interface.
 func (expr PartitionMinVal) TypeCheck(_ *SemaContext, desired types.T) (TypedExpr, error) {
 	return nil, errInvalidMinUsage
@@ -1359,6 +1444,10 @@ func (d *DUuid) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return
 // identity function for Datum.
 func (d *DIPAddr) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DEnum) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DDate) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
@@ -1383,6 +1472,14 @@ func (d *DInterval) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { re
 // identity function for Datum.
 func (d *DJSON) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSVector) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DTSQuery) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DTuple) TypeCheck(_ *SemaContext, _ types.T) (TypedExpr, error) { return d, nil }
@@ -1685,9 +1782,10 @@ func typeCheckComparisonOp(
 	// Throw a typing error if overload resolution found either no compatible candidates
 	// or if it found an ambiguity.
 	collationMismatch := leftReturn.FamilyEqual(types.FamCollatedString) && !leftReturn.Equivalent(rightReturn)
-	if len(fns) != 1 || collationMismatch {
+	enumMismatch := leftReturn.FamilyEqual(types.FamEnum) && !leftReturn.Equivalent(rightReturn)
+	if len(fns) != 1 || collationMismatch || enumMismatch {
 		sig := fmt.Sprintf(compSignatureFmt, leftReturn, op, rightReturn)
-		if len(fns) == 0 || collationMismatch {
+		if len(fns) == 0 || collationMismatch || enumMismatch {
 			return nil, nil, nil, false,
 				pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError, unsupportedCompErrFmt, sig)
 		}
@@ -2049,6 +2147,11 @@ func (v *placeholderAnnotationVisitor) VisitPre(expr Expr) (recurse bool, newExp
 	switch t := expr.(type) {
 	case *AnnotateTypeExpr:
 		if arg, ok := t.Expr.(*Placeholder); ok {
+			if _, ok := t.Type.(*coltypes.TUserDefined); ok {
+				// User-defined types are only resolved during type checking, so
+				// the placeholder is treated as if it were not annotated.
+				return v.VisitPre(arg)
+			}
 			assertType := t.annotationType()
 			if state, ok := v.placeholders[arg.Name]; ok && state.sawAssertion {
 				if state.shouldAnnotate && !assertType.Equivalent(state.typ) {
@@ -2067,6 +2170,9 @@ func (v *placeholderAnnotationVisitor) VisitPre(expr Expr) (recurse bool, newExp
 		}
 	case *CastExpr:
 		if arg, ok := t.Expr.(*Placeholder); ok {
+			if _, ok := t.Type.(*coltypes.TUserDefined); ok {
+				return v.VisitPre(arg)
+			}
 			castType := t.castType()
 			if state, ok := v.placeholders[arg.Name]; ok {
 				// Ignore casts once an assertion has been seen.
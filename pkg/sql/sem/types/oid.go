@@ -113,6 +113,8 @@ var OidToType = map[oid.Oid]T{
 	oid.T_bit:          typeBit,
 	oid.T__bit:         TArray{typeBit},
 	oid.T_jsonb:        JSON,
+	oid.T_tsvector:     TSVector,
+	oid.T_tsquery:      TSQuery,
 	oid.T_int2vector:   IntVector,
 	oid.T_oidvector:    OidVector,
 	oid.T_regclass:     RegClass,
@@ -17,6 +17,7 @@ package types
 import (
 	"bytes"
 	"fmt"
+	"sort"
 
 	"github.com/lib/pq/oid"
 )
@@ -75,6 +76,10 @@ var (
 	Interval T = tInterval{}
 	// JSON is the type of a DJSON. Can be compared with ==.
 	JSON T = tJSON{}
+	// TSVector is the type of a DTSVector. Can be compared with ==.
+	TSVector T = tTSVector{}
+	// TSQuery is the type of a DTSQuery. Can be compared with ==.
+	TSQuery T = tTSQuery{}
 	// UUID is the type of a DUuid. Can be compared with ==.
 	UUID T = tUUID{}
 	// INet is the type of a DIPAddr. Can be compared with ==.
@@ -102,6 +107,8 @@ var (
 		UUID,
 		INet,
 		JSON,
+		TSVector,
+		TSQuery,
 		Oid,
 	}
 
@@ -112,6 +119,8 @@ var (
 	FamTuple T = TTuple{}
 	// FamArray is the type family of a DArray. CANNOT be compared with ==.
 	FamArray T = TArray{}
+	// FamEnum is the type family of a DEnum. CANNOT be compared with ==.
+	FamEnum T = TEnum{}
 	// FamPlaceholder is the type family of a placeholder. CANNOT be compared
 	// with ==.
 	FamPlaceholder T = TPlaceholder{}
@@ -299,6 +308,30 @@ func (tJSON) Oid() oid.Oid             { return oid.T_jsonb }
 func (tJSON) SQLName() string          { return "json" }
 func (tJSON) IsAmbiguous() bool        { return false }
 
+type tTSVector struct{}
+
+func (tTSVector) String() string { return "tsvector" }
+func (tTSVector) Equivalent(other T) bool {
+	return UnwrapType(other) == TSVector || other == Any
+}
+
+func (tTSVector) FamilyEqual(other T) bool { return UnwrapType(other) == TSVector }
+func (tTSVector) Oid() oid.Oid             { return oid.T_tsvector }
+func (tTSVector) SQLName() string          { return "tsvector" }
+func (tTSVector) IsAmbiguous() bool        { return false }
+
+type tTSQuery struct{}
+
+func (tTSQuery) String() string { return "tsquery" }
+func (tTSQuery) Equivalent(other T) bool {
+	return UnwrapType(other) == TSQuery || other == Any
+}
+
+func (tTSQuery) FamilyEqual(other T) bool { return UnwrapType(other) == TSQuery }
+func (tTSQuery) Oid() oid.Oid             { return oid.T_tsquery }
+func (tTSQuery) SQLName() string          { return "tsquery" }
+func (tTSQuery) IsAmbiguous() bool        { return false }
+
 type tUUID struct{}
 
 func (tUUID) String() string           { return "uuid" }
@@ -392,6 +425,128 @@ func (t TTuple) IsAmbiguous() bool {
 	return len(t.Types) == 0
 }
 
+// TEnum is the type of a DEnum, a value of a user-defined ENUM type.
+type TEnum struct {
+	// ID is the ID of the descriptor that defines the type.
+	ID uint32
+	// Name is the name of the type.
+	Name string
+	// members is nil for FamEnum. It is held by pointer so that TEnum
+	// remains comparable with ==, like most other types.
+	members *enumMembers
+}
+
+// enumMembers describes the members of an ENUM type, in increasing order of
+// their physical representation. The physical representations are assigned
+// so that this is also the declaration order of the members.
+type enumMembers struct {
+	labels       []string
+	physicalReps [][]byte
+	// readOnly is true for the members that can be decoded but not written
+	// yet, because they are still being added to the type.
+	readOnly []bool
+}
+
+// MakeEnum returns the ENUM type with the given ID, name and members. The
+// members must be sorted by their physical representations. readOnly
+// indicates the members that can be decoded but not written yet; it may be
+// nil if all the members are writable.
+func MakeEnum(
+	id uint32, name string, labels []string, physicalReps [][]byte, readOnly []bool,
+) TEnum {
+	return TEnum{
+		ID:   id,
+		Name: name,
+		members: &enumMembers{
+			labels:       labels,
+			physicalReps: physicalReps,
+			readOnly:     readOnly,
+		},
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (t TEnum) String() string {
+	if t.Name == "" {
+		return "anyenum"
+	}
+	return t.Name
+}
+
+// Equivalent implements the T interface.
+func (t TEnum) Equivalent(other T) bool {
+	if other == Any {
+		return true
+	}
+	u, ok := UnwrapType(other).(TEnum)
+	if !ok {
+		return false
+	}
+	// Enums that aren't fully specified (have a zero ID) are equivalent to
+	// all other enums, to allow overloads to take an arbitrary enum type.
+	return t.ID == 0 || u.ID == 0 || t.ID == u.ID
+}
+
+// FamilyEqual implements the T interface.
+func (TEnum) FamilyEqual(other T) bool {
+	_, ok := UnwrapType(other).(TEnum)
+	return ok
+}
+
+// Oid implements the T interface.
+func (TEnum) Oid() oid.Oid { return oid.T_anyenum }
+
+// SQLName implements the T interface.
+func (t TEnum) SQLName() string { return t.String() }
+
+// IsAmbiguous implements the T interface.
+func (t TEnum) IsAmbiguous() bool { return t.ID == 0 }
+
+// NumMembers returns the number of members of the type.
+func (t TEnum) NumMembers() int {
+	if t.members == nil {
+		return 0
+	}
+	return len(t.members.labels)
+}
+
+// Label returns the label of the member at the given position.
+func (t TEnum) Label(i int) string { return t.members.labels[i] }
+
+// PhysicalRep returns the physical representation of the member at the given
+// position.
+func (t TEnum) PhysicalRep(i int) []byte { return t.members.physicalReps[i] }
+
+// IsReadOnly returns true if the member at the given position can be decoded
+// but not written yet.
+func (t TEnum) IsReadOnly(i int) bool {
+	return t.members.readOnly != nil && t.members.readOnly[i]
+}
+
+// LabelIndex returns the position of the member with the given label, or -1
+// if the type has no such member.
+func (t TEnum) LabelIndex(label string) int {
+	for i, n := 0, t.NumMembers(); i < n; i++ {
+		if t.members.labels[i] == label {
+			return i
+		}
+	}
+	return -1
+}
+
+// PhysicalRepIndex returns the position of the member with the given
+// physical representation, or -1 if the type has no such member.
+func (t TEnum) PhysicalRepIndex(rep []byte) int {
+	n := t.NumMembers()
+	i := sort.Search(n, func(i int) bool {
+		return bytes.Compare(t.members.physicalReps[i], rep) >= 0
+	})
+	if i < n && bytes.Equal(t.members.physicalReps[i], rep) {
+		return i
+	}
+	return -1
+}
+
 // TPlaceholder is the type of a placeholder.
 type TPlaceholder struct {
 	Name string
@@ -501,8 +656,11 @@ func IsStringType(t T) bool {
 // IsValidArrayElementType returns true if the T
 // can be used in TArray.
 func IsValidArrayElementType(t T) bool {
+	if _, ok := UnwrapType(t).(TEnum); ok {
+		return false
+	}
 	switch t {
-	case JSON:
+	case JSON, TSVector, TSQuery:
 		return false
 	default:
 		return true
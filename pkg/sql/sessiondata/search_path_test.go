@@ -58,3 +58,55 @@ func TestImpliedSearchPath(t *testing.T) {
 		})
 	}
 }
+
+func TestImpliedSearchPathWithTemporarySchema(t *testing.T) {
+	const tempSchemaName = "pg_temp_1_2"
+	testCases := []struct {
+		explicitSearchPath                         []string
+		expectedSearchPath                         []string
+		expectedSearchPathWithoutImplicitPgCatalog []string
+	}{
+		{[]string{}, []string{tempSchemaName, `pg_catalog`}, []string{}},
+		{[]string{`foobar`}, []string{tempSchemaName, `pg_catalog`, `foobar`}, []string{`foobar`}},
+		{[]string{`foobar`, `pg_temp`}, []string{`pg_catalog`, `foobar`, tempSchemaName}, []string{`foobar`, tempSchemaName}},
+		{[]string{`pg_catalog`, `pg_temp`}, []string{`pg_catalog`, tempSchemaName}, []string{`pg_catalog`, tempSchemaName}},
+	}
+
+	for _, tc := range testCases {
+		searchPath := MakeSearchPath(tc.explicitSearchPath).WithTemporarySchemaName(tempSchemaName)
+		t.Run(strings.Join(tc.explicitSearchPath, ","), func(t *testing.T) {
+			actualSearchPath := make([]string, 0)
+			iter := searchPath.Iter()
+			for p, ok := iter.Next(); ok; p, ok = iter.Next() {
+				actualSearchPath = append(actualSearchPath, p)
+			}
+			if !reflect.DeepEqual(tc.expectedSearchPath, actualSearchPath) {
+				t.Errorf(`Expected search path to be %#v, but was %#v.`, tc.expectedSearchPath, actualSearchPath)
+			}
+		})
+
+		t.Run(strings.Join(tc.explicitSearchPath, ",")+"/no-pg-catalog", func(t *testing.T) {
+			actualSearchPath := make([]string, 0)
+			iter := searchPath.IterWithoutImplicitPGCatalog()
+			for p, ok := iter.Next(); ok; p, ok = iter.Next() {
+				actualSearchPath = append(actualSearchPath, p)
+			}
+			if !reflect.DeepEqual(tc.expectedSearchPathWithoutImplicitPgCatalog, actualSearchPath) {
+				t.Errorf(`Expected search path to be %#v, but was %#v.`, tc.expectedSearchPathWithoutImplicitPgCatalog, actualSearchPath)
+			}
+		})
+	}
+}
+
+func TestSearchPathWithoutTemporarySchema(t *testing.T) {
+	// Until the session creates a temporary schema, pg_temp is skipped.
+	searchPath := MakeSearchPath([]string{`pg_temp`, `public`})
+	actualSearchPath := make([]string, 0)
+	iter := searchPath.Iter()
+	for p, ok := iter.Next(); ok; p, ok = iter.Next() {
+		actualSearchPath = append(actualSearchPath, p)
+	}
+	if expected := []string{`pg_catalog`, `public`}; !reflect.DeepEqual(expected, actualSearchPath) {
+		t.Errorf(`Expected search path to be %#v, but was %#v.`, expected, actualSearchPath)
+	}
+}
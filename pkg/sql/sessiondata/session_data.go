@@ -57,6 +57,8 @@ type SessionData struct {
 	// SearchPath is a list of databases that will be searched for a table name
 	// before the database. Currently, this is used only for SELECTs.
 	// Names in the search path must have been normalized already.
+	// The search path also tracks the temporary schema of the session, once
+	// the session has created a temporary table.
 	SearchPath SearchPath
 	// StmtTimeout is the duration a query is permitted to run before it is
 	// canceled by the session. If set to 0, there is no timeout.
@@ -0,0 +1,94 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// SetConstraints sets the checking mode of deferrable constraints for the
+// rest of the current transaction.
+// See https://www.postgresql.org/docs/10/static/sql-set-constraints.html.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	if p.EvalContext().ConstraintDeferrer == nil || p.EvalContext().TxnImplicit {
+		return nil, pgerror.NewError(pgerror.CodeNoActiveSQLTransactionError,
+			"SET CONSTRAINTS can only be used in transaction blocks")
+	}
+
+	var names []string
+	if !n.All {
+		if err := p.checkDeferrableConstraints(ctx, n.Names); err != nil {
+			return nil, err
+		}
+		names = make([]string, len(n.Names))
+		for i := range n.Names {
+			names[i] = string(n.Names[i])
+		}
+	}
+
+	if err := p.extendedEvalCtx.TxnModesSetter.setConstraintsMode(ctx, names, n.Deferred); err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// checkDeferrableConstraints verifies that each of the named constraints is
+// a deferrable foreign key or UNIQUE constraint of some table in the current
+// database.
+func (p *planner) checkDeferrableConstraints(ctx context.Context, names tree.NameList) error {
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /* required */)
+	if err != nil {
+		return err
+	}
+	descs, err := p.Tables().getAllDescriptors(ctx, p.txn)
+	if err != nil {
+		return err
+	}
+
+	// deferrable maps the names of the foreign keys and UNIQUE constraints in
+	// the database to whether any of them is deferrable.
+	deferrable := make(map[string]bool)
+	for _, desc := range descs {
+		table, ok := desc.(*sqlbase.TableDescriptor)
+		if !ok || table.ParentID != dbDesc.ID || table.Dropped() {
+			continue
+		}
+		for _, idx := range table.AllNonDropIndexes() {
+			if fk := idx.ForeignKey; fk.IsSet() {
+				deferrable[fk.Name] = deferrable[fk.Name] || fk.Deferrable
+			}
+			if idx.Unique || idx.DeferrableUnique {
+				deferrable[idx.Name] = deferrable[idx.Name] || idx.DeferrableUnique
+			}
+		}
+	}
+
+	for _, name := range names {
+		isDeferrable, ok := deferrable[string(name)]
+		if !ok {
+			return pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+				"constraint %q does not exist", tree.ErrString(&name))
+		}
+		if !isDeferrable {
+			return pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+				"constraint %q is not deferrable", tree.ErrString(&name))
+		}
+	}
+	return nil
+}
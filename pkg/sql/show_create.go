@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/pkg/errors"
@@ -46,13 +47,23 @@ func ShowCreateView(
 	ctx context.Context, tn *tree.Name, desc *sqlbase.TableDescriptor,
 ) (string, error) {
 	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
-	f.WriteString("CREATE VIEW ")
+	f.WriteString("CREATE ")
+	if desc.MaterializedView() {
+		f.WriteString("MATERIALIZED ")
+	}
+	f.WriteString("VIEW ")
 	f.FormatNode(tn)
 	f.WriteString(" (")
+	first := true
 	for i := range desc.Columns {
-		if i > 0 {
+		// Skip the hidden primary key column of a materialized view.
+		if desc.Columns[i].Hidden {
+			continue
+		}
+		if !first {
 			f.WriteString(", ")
 		}
+		first = false
 		f.FormatNameP(&desc.Columns[i].Name)
 	}
 	f.WriteString(") AS ")
@@ -112,6 +123,12 @@ func printForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	if fk.Deferrable {
+		buf.WriteString(" DEFERRABLE")
+		if fk.InitiallyDeferred {
+			buf.WriteString(" INITIALLY DEFERRED")
+		}
+	}
 	return nil
 }
 
@@ -134,6 +151,33 @@ func ShowCreateSequence(
 	return f.CloseAndGetString(), nil
 }
 
+// ShowCreateFunction returns a valid SQL representation of the CREATE
+// FUNCTION statement used to create the given user-defined function.
+func ShowCreateFunction(desc *sqlbase.FunctionDescriptor) string {
+	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
+	f.WriteString("CREATE FUNCTION ")
+	f.FormatNameP(&desc.Name)
+	f.WriteByte('(')
+	for i := range desc.ArgTypes {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		if desc.ArgNames[i] != "" {
+			f.FormatNameP(&desc.ArgNames[i])
+			f.WriteByte(' ')
+		}
+		f.WriteString(desc.ArgTypes[i].SQLString())
+	}
+	f.WriteString(") RETURNS ")
+	if desc.ReturnsSet {
+		f.WriteString("SETOF ")
+	}
+	f.WriteString(desc.ReturnType.SQLString())
+	f.WriteString(" LANGUAGE SQL AS ")
+	lex.EncodeSQLString(f.Buffer, desc.Body)
+	return f.CloseAndGetString()
+}
+
 // ShowCreateTable returns a valid SQL representation of the CREATE
 // TABLE statement used to create the given table.
 //
@@ -157,13 +201,19 @@ func ShowCreateTable(
 	f.FormatNode(tn)
 	f.WriteString(" (")
 	primaryKeyIsOnVisibleColumn := false
+	// The shard column of a hash sharded primary key is hidden; the primary
+	// key is shown if the columns it was declared on are visible.
+	pkColIdx := 0
+	if desc.PrimaryIndex.IsSharded() {
+		pkColIdx = 1
+	}
 	for i, col := range desc.VisibleColumns() {
 		if i != 0 {
 			f.WriteString(",")
 		}
 		f.WriteString("\n\t")
 		f.WriteString(col.SQLString())
-		if desc.IsPhysicalTable() && desc.PrimaryIndex.ColumnIDs[0] == col.ID {
+		if desc.IsPhysicalTable() && desc.PrimaryIndex.ColumnIDs[pkColIdx] == col.ID {
 			// Only set primaryKeyIsOnVisibleColumn to true if the primary key
 			// is on a visible column (not rowid).
 			primaryKeyIsOnVisibleColumn = true
@@ -200,6 +250,16 @@ func ShowCreateTable(
 			); err != nil {
 				return "", err
 			}
+			if idx.IsPartial() {
+				f.WriteString(" WHERE ")
+				f.WriteString(idx.Predicate)
+			}
+			if idx.DeferrableUnique {
+				f.WriteString(" DEFERRABLE")
+				if idx.InitiallyDeferred {
+					f.WriteString(" INITIALLY DEFERRED")
+				}
+			}
 		}
 	}
 
@@ -218,6 +278,11 @@ func ShowCreateTable(
 	}
 
 	for _, e := range desc.Checks {
+		if e.IsNonNullConstraint {
+			// The NOT NULL check constraint of a column being validated by
+			// ALTER COLUMN SET NOT NULL is an implementation detail.
+			continue
+		}
 		f.WriteString(",\n\t")
 		if len(e.Name) > 0 {
 			f.WriteString("CONSTRAINT ")
@@ -239,6 +304,11 @@ func ShowCreateTable(
 	); err != nil {
 		return "", err
 	}
+	if desc.RowLevelTTL != nil {
+		f.WriteString(" WITH (" + ttlExpireAfterParam + " = ")
+		lex.EncodeSQLString(f.Buffer, desc.RowLevelTTL.ExpireAfter)
+		f.WriteString(")")
+	}
 
 	return f.CloseAndGetString(), nil
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
 
 // ShowGrants returns grant details for the specified objects and users.
@@ -77,6 +78,36 @@ FROM "".information_schema.table_privileges`
 		} else {
 			fmt.Fprintf(&cond, `WHERE database_name IN (%s)`, strings.Join(params, ","))
 		}
+	} else if n.Targets != nil && n.Targets.Schemas != nil {
+		// Get grants of user-defined schemas of the current database from
+		// information_schema.schema_privileges if the type of target is
+		// schema.
+		scNames := n.Targets.Schemas.ToStrings()
+
+		initCheck = func(ctx context.Context) error {
+			if p.CurrentDatabase() == "" {
+				return errNoDatabase
+			}
+			dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), true /*required*/)
+			if err != nil {
+				return err
+			}
+			for _, sc := range scNames {
+				if _, ok := dbDesc.FindSchema(sc); !ok {
+					return sqlbase.NewUndefinedSchemaError(sc)
+				}
+			}
+			return nil
+		}
+
+		for _, sc := range scNames {
+			params = append(params, lex.EscapeSQLString(sc))
+		}
+
+		fmt.Fprint(&source, dbPrivQuery)
+		orderBy = "1,2,3,4"
+		fmt.Fprintf(&cond, `WHERE database_name = %s AND schema_name IN (%s)`,
+			lex.EscapeSQLString(p.CurrentDatabase()), strings.Join(params, ","))
 	} else {
 		fmt.Fprint(&source, tablePrivQuery)
 		orderBy = "1,2,3,4,5"
@@ -27,6 +27,7 @@ import (
 // CheckHelper validates check constraints on rows, on INSERT and UPDATE.
 type CheckHelper struct {
 	Exprs        []tree.TypedExpr
+	checks       []*TableDescriptor_CheckConstraint
 	cols         []ColumnDescriptor
 	sourceInfo   *DataSourceInfo
 	ivarHelper   *tree.IndexedVarHelper
@@ -57,6 +58,7 @@ func (c *CheckHelper) Init(
 		return nil
 	}
 
+	c.checks = tableDesc.Checks
 	c.cols = tableDesc.Columns
 	c.sourceInfo = NewSourceInfoForSingleTable(
 		*tn, ResultColumnsFromColDescs(tableDesc.Columns),
@@ -141,12 +143,21 @@ func (c *CheckHelper) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
 func (c *CheckHelper) Check(ctx *tree.EvalContext) error {
 	ctx.PushIVarContainer(c)
 	defer func() { ctx.PopIVarContainer() }()
-	for _, expr := range c.Exprs {
+	for i, expr := range c.Exprs {
 		if d, err := expr.Eval(ctx); err != nil {
 			return err
 		} else if res, err := tree.GetBool(d); err != nil {
 			return err
 		} else if !res && d != tree.DNull {
+			// The NOT NULL check constraint of a column being validated by
+			// ALTER COLUMN SET NOT NULL is reported like the column's.
+			if check := c.checks[i]; check.IsNonNullConstraint && len(check.ColumnIDs) == 1 {
+				for j := range c.cols {
+					if c.cols[j].ID == check.ColumnIDs[0] {
+						return NewNonNullViolationError(c.cols[j].Name)
+					}
+				}
+			}
 			// Failed to satisfy CHECK constraint.
 			return pgerror.NewErrorf(pgerror.CodeCheckViolationError,
 				"failed to satisfy CHECK constraint (%s)", expr)
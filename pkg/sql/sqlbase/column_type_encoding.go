@@ -130,6 +130,11 @@ func EncodeTableKey(b []byte, val tree.Datum, dir encoding.Direction) ([]byte, e
 			return encoding.EncodeBytesAscending(b, data), nil
 		}
 		return encoding.EncodeBytesDescending(b, data), nil
+	case *tree.DEnum:
+		if dir == encoding.Ascending {
+			return encoding.EncodeBytesAscending(b, t.PhysicalRep), nil
+		}
+		return encoding.EncodeBytesDescending(b, t.PhysicalRep), nil
 	case *tree.DTuple:
 		for _, datum := range t.D {
 			var err error
@@ -240,7 +245,8 @@ func DecodeTableKey(
 			rkey, r, err = encoding.DecodeUnsafeStringDescending(key, nil)
 		}
 		return a.NewDName(tree.DString(r)), rkey, err
-	case types.JSON:
+	case types.JSON, types.TSVector:
+		// Inverted index keys cannot be decoded back into the indexed value.
 		return tree.DNull, []byte{}, nil
 	case types.Bytes:
 		var r []byte
@@ -332,6 +338,18 @@ func DecodeTableKey(
 				return nil, nil, err
 			}
 			return tree.NewDCollatedString(r, t.Locale, &a.env), rkey, err
+		case types.TEnum:
+			var r []byte
+			if dir == encoding.Ascending {
+				rkey, r, err = encoding.DecodeBytesAscending(key, nil)
+			} else {
+				rkey, r, err = encoding.DecodeBytesDescending(key, nil)
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			d, err := tree.MakeDEnumFromPhysicalRep(t, r)
+			return d, rkey, err
 		}
 		return nil, nil, errors.Errorf("TODO(pmattis): decoded index key: %s", valType)
 	}
@@ -383,12 +401,18 @@ func EncodeTableValue(
 		return encoding.EncodeUUIDValue(appendTo, uint32(colID), t.UUID), nil
 	case *tree.DIPAddr:
 		return encoding.EncodeIPAddrValue(appendTo, uint32(colID), t.IPAddr), nil
+	case *tree.DEnum:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.PhysicalRep), nil
 	case *tree.DJSON:
 		encoded, err := json.EncodeJSON(scratch, t.JSON)
 		if err != nil {
 			return nil, err
 		}
 		return encoding.EncodeJSONValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DTSVector:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.TSVector.String())), nil
+	case *tree.DTSQuery:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.TSQuery.String())), nil
 	case *tree.DArray:
 		a, err := encodeArray(t, scratch)
 		if err != nil {
@@ -516,6 +540,20 @@ func decodeUntaggedDatum(a *DatumAlloc, t types.T, buf []byte) (tree.Datum, []by
 			return nil, b, err
 		}
 		return a.NewDJSON(tree.DJSON{JSON: j}), b, nil
+	case types.TSVector:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		d, err := tree.ParseDTSVector(string(data))
+		return d, b, err
+	case types.TSQuery:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		d, err := tree.ParseDTSQuery(string(data))
+		return d, b, err
 	case types.Oid:
 		b, data, err := encoding.DecodeUntaggedIntValue(buf)
 		return a.NewDOid(tree.MakeDOid(tree.DInt(data))), b, err
@@ -534,6 +572,13 @@ func decodeUntaggedDatum(a *DatumAlloc, t types.T, buf []byte) (tree.Datum, []by
 		case types.TCollatedString:
 			b, data, err := encoding.DecodeUntaggedBytesValue(buf)
 			return tree.NewDCollatedString(string(data), typ.Locale, &a.env), b, err
+		case types.TEnum:
+			b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+			if err != nil {
+				return nil, b, err
+			}
+			d, err := tree.MakeDEnumFromPhysicalRep(typ, data)
+			return d, b, err
 		case types.TArray:
 			return decodeArray(a, typ.Typ, buf)
 		case types.TTuple:
@@ -656,6 +701,11 @@ func MarshalColumnValue(col ColumnDescriptor, val tree.Datum) (roachpb.Value, er
 			r.SetBytes(data)
 			return r, nil
 		}
+	case ColumnType_ENUM:
+		if v, ok := val.(*tree.DEnum); ok {
+			r.SetBytes(v.PhysicalRep)
+			return r, nil
+		}
 	case ColumnType_JSONB:
 		if v, ok := val.(*tree.DJSON); ok {
 			data, err := json.EncodeJSON(nil, v.JSON)
@@ -665,6 +715,16 @@ func MarshalColumnValue(col ColumnDescriptor, val tree.Datum) (roachpb.Value, er
 			r.SetBytes(data)
 			return r, nil
 		}
+	case ColumnType_TSVECTOR:
+		if v, ok := val.(*tree.DTSVector); ok {
+			r.SetBytes([]byte(v.TSVector.String()))
+			return r, nil
+		}
+	case ColumnType_TSQUERY:
+		if v, ok := val.(*tree.DTSQuery); ok {
+			r.SetBytes([]byte(v.TSQuery.String()))
+			return r, nil
+		}
 	case ColumnType_ARRAY:
 		if v, ok := val.(*tree.DArray); ok {
 			if err := checkElementType(v.ParamTyp, col.Type); err != nil {
@@ -816,6 +876,25 @@ func UnmarshalColumnValue(a *DatumAlloc, typ ColumnType, value roachpb.Value) (t
 			return nil, err
 		}
 		return a.NewDIPAddr(tree.DIPAddr{IPAddr: ipAddr}), nil
+	case ColumnType_ENUM:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return tree.MakeDEnumFromPhysicalRep(
+			enumDatumType(typ.EnumTypeID, typ.EnumName, typ.EnumMembers), v)
+	case ColumnType_TSVECTOR:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return tree.ParseDTSVector(string(v))
+	case ColumnType_TSQUERY:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return tree.ParseDTSQuery(string(v))
 	case ColumnType_NAME:
 		v, err := value.GetBytes()
 		if err != nil {
@@ -94,6 +94,17 @@ func DatumTypeToColumnType(ptyp types.T) (ColumnType, error) {
 		}
 		ctyp.TupleLabels = t.Labels
 		return ctyp, nil
+	case types.TEnum:
+		ctyp.SemanticType = ColumnType_ENUM
+		ctyp.EnumTypeID = ID(t.ID)
+		ctyp.EnumName = t.Name
+		ctyp.EnumMembers = make([]EnumMember, t.NumMembers())
+		for i := range ctyp.EnumMembers {
+			ctyp.EnumMembers[i] = EnumMember{LogicalRep: t.Label(i), PhysicalRep: t.PhysicalRep(i)}
+			if t.IsReadOnly(i) {
+				ctyp.EnumMembers[i].Capability = EnumMember_READ_ONLY
+			}
+		}
 	default:
 		semanticType, err := datumTypeToColumnSemanticType(ptyp)
 		if err != nil {
@@ -193,6 +204,8 @@ func PopulateTypeAttrs(base ColumnType, typ coltypes.T) (ColumnType, error) {
 	case *coltypes.TTime:
 	case *coltypes.TTimestamp:
 	case *coltypes.TTimestampTZ:
+	case *coltypes.TTSQuery:
+	case *coltypes.TTSVector:
 	case *coltypes.TUUID:
 	default:
 		return ColumnType{}, errors.Errorf("unexpected type %T", t)
@@ -297,6 +310,8 @@ func (c *ColumnType) SQLString() string {
 		}
 	case ColumnType_ARRAY:
 		return c.elementColumnType().SQLString() + "[]"
+	case ColumnType_ENUM:
+		return (&coltypes.TUserDefined{Name: c.EnumName}).String()
 	}
 	if c.VisibleType != ColumnType_NONE {
 		return c.VisibleType.String()
@@ -365,6 +380,8 @@ func (c *ColumnType) InformationSchemaVisibleType() string {
 		return "record"
 	case ColumnType_ARRAY:
 		return "ARRAY"
+	case ColumnType_ENUM:
+		return "USER-DEFINED"
 	}
 
 	// The name of the remaining semantic type constants are suitable
@@ -541,6 +558,10 @@ func datumTypeToColumnSemanticType(ptyp types.T) (ColumnType_SemanticType, error
 		return ColumnType_OIDVECTOR, nil
 	case types.JSON:
 		return ColumnType_JSONB, nil
+	case types.TSVector:
+		return ColumnType_TSVECTOR, nil
+	case types.TSQuery:
+		return ColumnType_TSQUERY, nil
 	default:
 		if ptyp.FamilyEqual(types.FamCollatedString) {
 			return ColumnType_COLLATEDSTRING, nil
@@ -548,6 +569,9 @@ func datumTypeToColumnSemanticType(ptyp types.T) (ColumnType_SemanticType, error
 		if ptyp.FamilyEqual(types.FamTuple) {
 			return ColumnType_TUPLE, nil
 		}
+		if ptyp.FamilyEqual(types.FamEnum) {
+			return ColumnType_ENUM, nil
+		}
 		if wrapper, ok := ptyp.(types.TOidWrapper); ok {
 			return datumTypeToColumnSemanticType(wrapper.T)
 		}
@@ -590,8 +614,14 @@ func columnSemanticTypeToDatumType(c *ColumnType, k ColumnType_SemanticType) typ
 		return types.INet
 	case ColumnType_JSONB:
 		return types.JSON
+	case ColumnType_TSVECTOR:
+		return types.TSVector
+	case ColumnType_TSQUERY:
+		return types.TSQuery
 	case ColumnType_TUPLE:
 		return types.FamTuple
+	case ColumnType_ENUM:
+		return enumDatumType(c.EnumTypeID, c.EnumName, c.EnumMembers)
 	case ColumnType_COLLATEDSTRING:
 		if c.Locale == nil {
 			panic("locale is required for COLLATEDSTRING")
@@ -216,13 +216,17 @@ func TestEncDatumCompare(t *testing.T) {
 	for kind := range ColumnType_SemanticType_name {
 		kind := ColumnType_SemanticType(kind)
 		if kind == ColumnType_NULL || kind == ColumnType_ARRAY || kind == ColumnType_INT2VECTOR ||
-			kind == ColumnType_OIDVECTOR || kind == ColumnType_JSONB || kind == ColumnType_TUPLE {
+			kind == ColumnType_OIDVECTOR || kind == ColumnType_JSONB || kind == ColumnType_TUPLE ||
+			kind == ColumnType_TSVECTOR || kind == ColumnType_TSQUERY {
 			continue
 		}
 		typ := ColumnType{SemanticType: kind}
 		if kind == ColumnType_COLLATEDSTRING {
 			typ.Locale = RandCollationLocale(rng)
 		}
+		if kind == ColumnType_ENUM {
+			typ = randEnumColumnType(rng)
+		}
 
 		// Generate two datums d1 < d2
 		var d1, d2 tree.Datum
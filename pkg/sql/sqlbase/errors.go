@@ -140,6 +140,21 @@ func NewRelationAlreadyExistsError(name string) error {
 	return pgerror.NewErrorf(pgerror.CodeDuplicateRelationError, "relation %q already exists", name)
 }
 
+// NewTypeAlreadyExistsError creates an error for a preexisting type.
+func NewTypeAlreadyExistsError(name string) error {
+	return pgerror.NewErrorf(pgerror.CodeDuplicateObjectError, "type %q already exists", name)
+}
+
+// NewSchemaAlreadyExistsError creates an error for a preexisting schema.
+func NewSchemaAlreadyExistsError(name string) error {
+	return pgerror.NewErrorf(pgerror.CodeDuplicateSchemaError, "schema %q already exists", name)
+}
+
+// NewUndefinedSchemaError creates an error for a missing schema.
+func NewUndefinedSchemaError(name string) error {
+	return pgerror.NewErrorf(pgerror.CodeInvalidSchemaNameError, "schema %q does not exist", name)
+}
+
 // NewWrongObjectTypeError creates a wrong object type error.
 func NewWrongObjectTypeError(name *tree.TableName, desiredObjType string) error {
 	return pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError, "%q is not a %s",
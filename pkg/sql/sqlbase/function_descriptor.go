@@ -0,0 +1,87 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
+
+var _ DescriptorProto = &FunctionDescriptor{}
+
+// SetID implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *FunctionDescriptor) TypeName() string {
+	return "function"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+// Functions cannot be audited.
+func (desc *FunctionDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// Validate validates that the function descriptor is well formed.
+func (desc *FunctionDescriptor) Validate() error {
+	if err := validateName(desc.Name, "function"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return fmt.Errorf("invalid function ID %d", desc.ID)
+	}
+	if desc.ParentID == 0 {
+		return fmt.Errorf("invalid parent ID %d for function %q", desc.ParentID, desc.Name)
+	}
+	if len(desc.ArgNames) != len(desc.ArgTypes) {
+		return fmt.Errorf("function %q has %d argument names but %d argument types",
+			desc.Name, len(desc.ArgNames), len(desc.ArgTypes))
+	}
+	if desc.Body == "" {
+		return fmt.Errorf("function %q has no body", desc.Name)
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
+// ArgDatumTypes returns the datum types of the arguments of the function.
+func (desc *FunctionDescriptor) ArgDatumTypes() []types.T {
+	return ColumnTypesToDatumTypes(desc.ArgTypes)
+}
+
+// Signature returns the name of the function followed by the list of its
+// argument types, as in "f(INT8, STRING)".
+func (desc *FunctionDescriptor) Signature() string {
+	var buf bytes.Buffer
+	buf.WriteString(desc.Name)
+	buf.WriteByte('(')
+	for i := range desc.ArgTypes {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(desc.ArgTypes[i].SQLString())
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/pkg/errors"
+)
+
+// GetShardColumnName returns the name of the hidden column holding the shard
+// of each row in a hash sharded index on the given columns.
+func GetShardColumnName(colNames []string, buckets int32) string {
+	return fmt.Sprintf("crdb_internal_%s_shard_%d", strings.Join(colNames, "_"), buckets)
+}
+
+// MakeHashShardComputeExpr returns the serialized computed expression of the
+// shard column of a hash sharded index on the given columns. The expression
+// must agree with HashShardBucket.
+func MakeHashShardComputeExpr(colNames []string, buckets int32) string {
+	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
+	f.WriteString("mod(fnv32(crdb_internal.datums_to_bytes(")
+	for i := range colNames {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		f.FormatNameP(&colNames[i])
+	}
+	f.WriteString(fmt.Sprintf(")), %d)", buckets))
+	return f.CloseAndGetString()
+}
+
+// DatumsToBytes encodes the given datums into the byte string that is hashed
+// to compute the shard of a row. The values are key encoded, so that datums
+// that compare equal, like 1.0 and 1.00, end up in the same shard.
+func DatumsToBytes(datums tree.Datums) ([]byte, error) {
+	return EncodeDatumsKeyAscending(nil, datums)
+}
+
+// HashShardBucket returns the shard, in a hash sharded index with the given
+// number of buckets, of the rows whose sharded columns have the given values.
+func HashShardBucket(datums tree.Datums, buckets int32) (int32, error) {
+	if buckets <= 0 {
+		return 0, errors.Errorf("invalid number of shard buckets: %d", buckets)
+	}
+	b, err := DatumsToBytes(datums)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New32()
+	if _, err := h.Write(b); err != nil {
+		return 0, err
+	}
+	return int32(h.Sum32() % uint32(buckets)), nil
+}
+
+// IsSharded returns true if the index is hash sharded, i.e. if its first
+// column is the hidden shard column described by desc.Sharded.
+func (desc *IndexDescriptor) IsSharded() bool {
+	return desc.Sharded.IsSharded
+}
+
+// shardedFormat writes the USING HASH clause of a hash sharded index to the
+// given buffer, or nothing if the index is not sharded.
+func (desc *IndexDescriptor) shardedFormat(ctx *tree.FmtCtxWithBuf) {
+	if desc.IsSharded() {
+		ctx.WriteString(fmt.Sprintf(" USING HASH WITH BUCKET_COUNT = %d", desc.Sharded.ShardBuckets))
+	}
+}
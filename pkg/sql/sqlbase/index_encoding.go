@@ -15,6 +15,7 @@
 package sqlbase
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/pkg/errors"
 )
 
@@ -590,10 +592,12 @@ func EncodeInvertedIndexKeys(
 	return EncodeInvertedIndexTableKeys(val, keyPrefix)
 }
 
-// EncodeInvertedIndexTableKeys encodes the paths in a JSON `val` and
-// concatenates it with `inKey`and returns a list of buffers per
-// path. The encoded values is guaranteed to be lexicographically
-// sortable, but not guaranteed to be round-trippable during decoding.
+// EncodeInvertedIndexTableKeys encodes the paths in a JSON `val`, the
+// elements of an ARRAY `val`, the lexemes of a TSVECTOR `val` or the trigrams
+// of a STRING `val`, and concatenates it with `inKey`and returns a list of
+// buffers per path, element, lexeme or trigram. The encoded values is
+// guaranteed to be lexicographically sortable, but not guaranteed to be
+// round-trippable during decoding.
 func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, err error) {
 	if val == tree.DNull {
 		return [][]byte{encoding.EncodeNullAscending(inKey)}, nil
@@ -601,8 +605,75 @@ func EncodeInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, e
 	switch t := tree.UnwrapDatum(nil, val).(type) {
 	case *tree.DJSON:
 		return json.EncodeInvertedIndexKeys(inKey, (t.JSON))
+	case *tree.DArray:
+		return encodeArrayInvertedIndexTableKeys(t, inKey)
+	case *tree.DTSVector:
+		return encodeTSVectorInvertedIndexTableKeys(t, inKey), nil
+	case *tree.DString:
+		return encodeStringInvertedIndexTableKeys(t, inKey), nil
 	}
-	return nil, pgerror.NewAssertionErrorf("trying to apply inverted index to non JSON type")
+	return nil, pgerror.NewAssertionErrorf(
+		"trying to apply inverted index to non JSON, ARRAY, TSVECTOR or STRING type")
+}
+
+// encodeStringInvertedIndexTableKeys returns one key per trigram of the
+// string `val`, each one prefixed with `inKey`. The trigrams are encoded like
+// the elements of a STRING[], so that the key of a trigram can be built from
+// an array containing only that trigram. A string without trigrams, like the
+// empty string, is not indexed.
+func encodeStringInvertedIndexTableKeys(val *tree.DString, inKey []byte) [][]byte {
+	trigrams := trigram.MakeTrigrams(string(*val))
+	outKeys := make([][]byte, len(trigrams))
+	for i := range trigrams {
+		outKeys[i] = encoding.EncodeStringAscending(append([]byte(nil), inKey...), trigrams[i])
+	}
+	return outKeys
+}
+
+// encodeTSVectorInvertedIndexTableKeys returns one key per lexeme of the
+// tsvector `val`, each one prefixed with `inKey`. The positions and weights
+// of the lexemes are not indexed. The lexemes of a tsvector are sorted and
+// distinct, so the keys are too.
+func encodeTSVectorInvertedIndexTableKeys(val *tree.DTSVector, inKey []byte) [][]byte {
+	outKeys := make([][]byte, len(val.TSVector))
+	for i := range val.TSVector {
+		outKeys[i] = encoding.EncodeStringAscending(append([]byte(nil), inKey...), val.TSVector[i].Lexeme)
+	}
+	return outKeys
+}
+
+// encodeArrayInvertedIndexTableKeys returns one key per distinct non-NULL
+// element of the array `val`, each one prefixed with `inKey`. NULL elements
+// are not indexed, since they never satisfy a containment or overlap check.
+// An empty array is encoded with a dedicated key so that it can be found
+// when looking for arrays contained by another one.
+func encodeArrayInvertedIndexTableKeys(val *tree.DArray, inKey []byte) ([][]byte, error) {
+	if val.Len() == 0 {
+		return [][]byte{encoding.EncodeEmptyArray(inKey)}, nil
+	}
+	outKeys := make([][]byte, 0, val.Len())
+	for _, d := range val.Array {
+		if d == tree.DNull {
+			continue
+		}
+		outKey, err := EncodeTableKey(append([]byte(nil), inKey...), d, encoding.Ascending)
+		if err != nil {
+			return nil, err
+		}
+		outKeys = append(outKeys, outKey)
+	}
+	// Equal elements produce equal keys, which must only be written once.
+	sort.Slice(outKeys, func(i, j int) bool {
+		return bytes.Compare(outKeys[i], outKeys[j]) < 0
+	})
+	n := 0
+	for i := range outKeys {
+		if i == 0 || !bytes.Equal(outKeys[i], outKeys[n-1]) {
+			outKeys[n] = outKeys[i]
+			n++
+		}
+	}
+	return outKeys[:n], nil
 }
 
 // EncodeSecondaryIndex encodes key/values for a secondary
@@ -50,16 +50,23 @@ type DescriptorProto interface {
 	GetAuditMode() TableDescriptor_AuditMode
 }
 
-// WrapDescriptor fills in a Descriptor.
+// WrapDescriptor fills in a Descriptor. Table descriptors are wrapped
+// without the members of their ENUM column types, which are not stored.
 func WrapDescriptor(descriptor DescriptorProto) *Descriptor {
 	desc := &Descriptor{}
 	switch t := descriptor.(type) {
 	case *MutableTableDescriptor:
-		desc.Union = &Descriptor_Table{Table: &t.TableDescriptor}
+		desc.Union = &Descriptor_Table{Table: t.TableDescriptor.withoutEnumMembers()}
 	case *TableDescriptor:
-		desc.Union = &Descriptor_Table{Table: t}
+		desc.Union = &Descriptor_Table{Table: t.withoutEnumMembers()}
 	case *DatabaseDescriptor:
 		desc.Union = &Descriptor_Database{Database: t}
+	case *TypeDescriptor:
+		desc.Union = &Descriptor_Type{Type: t}
+	case *FunctionDescriptor:
+		desc.Union = &Descriptor_Function{Function: t}
+	case *SchemaDescriptor:
+		desc.Union = &Descriptor_Schema{Schema: t}
 	default:
 		panic(fmt.Sprintf("unknown descriptor type: %s", descriptor.TypeName()))
 	}
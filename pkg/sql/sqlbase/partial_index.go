@@ -0,0 +1,109 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
+
+// MakePartialIndexExprs returns a slice of the predicate expressions of the
+// given indexes, or nil if none of the indexes is partial. The length of the
+// result slice matches the length of the input index descriptors; for every
+// index that is not partial, a nil expression is reported.
+//
+// The returned expressions refer to the columns of the table through
+// IndexedVars whose indexes are ordinals into tableDesc.Columns, so they can be
+// evaluated with a RowIndexedVarContainer (see EvalPartialIndexPredicate).
+func MakePartialIndexExprs(
+	indexes []IndexDescriptor, tableDesc *ImmutableTableDescriptor, evalCtx *tree.EvalContext,
+) ([]tree.TypedExpr, error) {
+	// Check to see if any of the indexes is partial. If there are none, we
+	// don't bother parsing anything.
+	havePartial := false
+	for i := range indexes {
+		if indexes[i].IsPartial() {
+			havePartial = true
+			break
+		}
+	}
+	if !havePartial {
+		return nil, nil
+	}
+
+	// The predicates were dequalified when the indexes were created, so the
+	// table name used to resolve the column references doesn't matter.
+	tn := tree.MakeUnqualifiedTableName(tree.Name(tableDesc.Name))
+	predExprs := make([]tree.TypedExpr, len(indexes))
+	for i := range indexes {
+		if !indexes[i].IsPartial() {
+			continue
+		}
+		raw, err := parser.ParseExpr(indexes[i].Predicate)
+		if err != nil {
+			return nil, err
+		}
+		typedExpr, err := MakeTablePredicateExpr(raw, tn, tableDesc, evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		predExprs[i] = typedExpr
+	}
+	return predExprs, nil
+}
+
+// MakeTablePredicateExpr type checks a boolean expression over the columns of
+// the given table, whose column references may be qualified with tn. Like the
+// predicates returned by MakePartialIndexExprs, the result refers to the
+// columns through IndexedVars whose indexes are ordinals into
+// tableDesc.Columns.
+func MakeTablePredicateExpr(
+	expr tree.Expr,
+	tn tree.TableName,
+	tableDesc *ImmutableTableDescriptor,
+	evalCtx *tree.EvalContext,
+) (tree.TypedExpr, error) {
+	iv := &descContainer{tableDesc.Columns}
+	ivarHelper := tree.MakeIndexedVarHelper(iv, len(tableDesc.Columns))
+	sources := MakeMultiSourceInfo(NewSourceInfoForSingleTable(
+		tn, ResultColumnsFromColDescs(tableDesc.Columns),
+	))
+
+	semaCtx := tree.MakeSemaContext(false)
+	semaCtx.IVarContainer = iv
+
+	expr, _, _, err := ResolveNames(expr, sources, ivarHelper, evalCtx.SessionData.SearchPath)
+	if err != nil {
+		return nil, err
+	}
+	return tree.TypeCheck(expr, &semaCtx, types.Bool)
+}
+
+// EvalPartialIndexPredicate evaluates the predicate of a partial index, as
+// returned by MakePartialIndexExprs, over the row loaded into iv. It returns
+// true if the row belongs in the index; a NULL result, like false, means that
+// it doesn't.
+func EvalPartialIndexPredicate(
+	evalCtx *tree.EvalContext, pred tree.TypedExpr, iv *RowIndexedVarContainer,
+) (bool, error) {
+	evalCtx.PushIVarContainer(iv)
+	defer evalCtx.PopIVarContainer()
+	d, err := pred.Eval(evalCtx)
+	if err != nil {
+		return false, err
+	}
+	return d == tree.DBoolTrue, nil
+}
@@ -290,6 +290,8 @@ func GetDatabaseDescFromID(
 // GetTableDescFromID retrieves the table descriptor for the table
 // ID passed in using an existing txn. Returns an error if the
 // descriptor doesn't exist or if it exists and is not a table.
+// The members of the ENUM column types of the table are filled in
+// from the descriptors of their types.
 func GetTableDescFromID(ctx context.Context, txn *client.Txn, id ID) (*TableDescriptor, error) {
 	desc := &Descriptor{}
 	descKey := MakeDescMetadataKey(id)
@@ -301,9 +303,29 @@ func GetTableDescFromID(ctx context.Context, txn *client.Txn, id ID) (*TableDesc
 	if table == nil {
 		return nil, ErrDescriptorNotFound
 	}
+	if err := table.HydrateEnumTypes(ctx, txn); err != nil {
+		return nil, err
+	}
 	return table, nil
 }
 
+// GetTypeDescFromID retrieves the type descriptor for the type ID passed
+// in using an existing txn. Returns an error if the descriptor doesn't exist
+// or if it exists and is not a type.
+func GetTypeDescFromID(ctx context.Context, txn *client.Txn, id ID) (*TypeDescriptor, error) {
+	desc := &Descriptor{}
+	descKey := MakeDescMetadataKey(id)
+
+	if err := txn.GetProto(ctx, descKey, desc); err != nil {
+		return nil, err
+	}
+	typ := desc.GetType()
+	if typ == nil {
+		return nil, ErrDescriptorNotFound
+	}
+	return typ, nil
+}
+
 // GetMutableTableDescFromID retrieves the table descriptor for the table
 // ID passed in using an existing txn. Returns an error if the
 // descriptor doesn't exist or if it exists and is not a table.
@@ -339,14 +361,26 @@ func (desc *IndexDescriptor) RunOverAllColumns(fn func(id ColumnID) error) error
 	return nil
 }
 
+// IsPartial returns true if the index is a partial index, i.e. if it only
+// contains entries for the rows that satisfy its predicate.
+func (desc *IndexDescriptor) IsPartial() bool {
+	return desc.Predicate != ""
+}
+
 // allocateName sets desc.Name to a value that is not EqualName to any
 // of tableDesc's indexes. allocateName roughly follows PostgreSQL's
 // convention for automatically-named indexes.
 func (desc *IndexDescriptor) allocateName(tableDesc *MutableTableDescriptor) {
-	segments := make([]string, 0, len(desc.ColumnNames)+2)
+	colNames := desc.ColumnNames
+	if desc.IsSharded() {
+		// The shard column is an implementation detail of the index: name the
+		// index after the columns it was declared on.
+		colNames = desc.Sharded.ColumnNames
+	}
+	segments := make([]string, 0, len(colNames)+2)
 	segments = append(segments, tableDesc.Name)
-	segments = append(segments, desc.ColumnNames...)
-	if desc.Unique {
+	segments = append(segments, colNames...)
+	if desc.Unique || desc.DeferrableUnique {
 		segments = append(segments, "key")
 	} else {
 		segments = append(segments, "idx")
@@ -422,10 +456,15 @@ func (desc *IndexDescriptor) FullColumnIDs() ([]ColumnID, []IndexDescriptor_Dire
 }
 
 // ColNamesFormat writes a string describing the column names and directions
-// in this index to the given buffer.
+// in this index to the given buffer. The shard column of a hash sharded index
+// is omitted, like in the statement that created the index.
 func (desc *IndexDescriptor) ColNamesFormat(ctx *tree.FmtCtxWithBuf) {
-	for i := range desc.ColumnNames {
-		if i > 0 {
+	start := 0
+	if desc.IsSharded() {
+		start = 1
+	}
+	for i := start; i < len(desc.ColumnNames); i++ {
+		if i > start {
 			ctx.WriteString(", ")
 		}
 		ctx.FormatNameP(&desc.ColumnNames[i])
@@ -445,24 +484,33 @@ func (desc *IndexDescriptor) ColNamesString() string {
 }
 
 // SQLString returns the SQL string describing this index. If non-empty,
-// "ON tableName" is included in the output in the correct place.
+// "ON tableName" is included in the output in the correct place. The index of
+// a deferrable UNIQUE constraint, which can't be created as an index, is
+// described as the constraint, without the DEFERRABLE clause.
 func (desc *IndexDescriptor) SQLString(tableName *tree.TableName) string {
 	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
-	if desc.Unique {
-		f.WriteString("UNIQUE ")
-	}
-	if desc.Type == IndexDescriptor_INVERTED {
-		f.WriteString("INVERTED ")
-	}
-	f.WriteString("INDEX ")
-	if *tableName != AnonymousTable {
-		f.WriteString("ON ")
-		f.FormatNode(tableName)
+	if desc.DeferrableUnique {
+		f.WriteString("CONSTRAINT ")
+		f.FormatNameP(&desc.Name)
+		f.WriteString(" UNIQUE")
+	} else {
+		if desc.Unique {
+			f.WriteString("UNIQUE ")
+		}
+		if desc.Type == IndexDescriptor_INVERTED {
+			f.WriteString("INVERTED ")
+		}
+		f.WriteString("INDEX ")
+		if *tableName != AnonymousTable {
+			f.WriteString("ON ")
+			f.FormatNode(tableName)
+		}
+		f.FormatNameP(&desc.Name)
 	}
-	f.FormatNameP(&desc.Name)
 	f.WriteString(" (")
 	desc.ColNamesFormat(f)
 	f.WriteByte(')')
+	desc.shardedFormat(f)
 
 	if len(desc.StoreColumnNames) > 0 {
 		f.WriteString(" STORING (")
@@ -515,12 +563,38 @@ func (desc *TableDescriptor) IsView() bool {
 	return desc.ViewQuery != ""
 }
 
+// MaterializedView returns true if the TableDescriptor describes a
+// materialized view, i.e. a view whose contents are stored like those of a
+// table.
+func (desc *TableDescriptor) MaterializedView() bool {
+	return desc.IsMaterializedView
+}
+
 // IsSequence returns true if the TableDescriptor actually describes a
 // Sequence resource rather than a Table.
 func (desc *TableDescriptor) IsSequence() bool {
 	return desc.SequenceOpts != nil
 }
 
+// IsTemporary returns true if the TableDescriptor describes a table
+// in the temporary schema of a session.
+func (desc *TableDescriptor) IsTemporary() bool {
+	return desc.TemporarySchemaID != 0
+}
+
+// GetNamespaceParentID returns the ID under which the name of the table is
+// recorded in system.namespace: the ID of its schema for tables in temporary
+// and user-defined schemas, and the ID of its database otherwise.
+func (desc *TableDescriptor) GetNamespaceParentID() ID {
+	if desc.IsTemporary() {
+		return desc.TemporarySchemaID
+	}
+	if desc.SchemaID != 0 {
+		return desc.SchemaID
+	}
+	return desc.ParentID
+}
+
 // IsVirtualTable returns true if the TableDescriptor describes a
 // virtual Table (like the information_schema tables) and thus doesn't
 // need to be physically stored.
@@ -532,10 +606,10 @@ func (desc *TableDescriptor) IsVirtualTable() bool {
 // physical Table that needs to be stored in the kv layer, as opposed to a
 // different resource like a view or a virtual table. Physical tables have
 // primary keys, column families, and indexes (unlike virtual tables).
-// Sequences count as physical tables because their values are stored in
-// the KV layer.
+// Sequences and materialized views count as physical tables because their
+// values are stored in the KV layer.
 func (desc *TableDescriptor) IsPhysicalTable() bool {
-	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable())
+	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable()) || desc.MaterializedView()
 }
 
 // KeysPerRow returns the maximum number of keys used to encode a row for the
@@ -795,6 +869,8 @@ func DatumTypeHasCompositeKeyEncoding(typ types.T) bool {
 func MustBeValueEncoded(semanticType ColumnType_SemanticType) bool {
 	return semanticType == ColumnType_ARRAY ||
 		semanticType == ColumnType_JSONB ||
+		semanticType == ColumnType_TSVECTOR ||
+		semanticType == ColumnType_TSQUERY ||
 		semanticType == ColumnType_TUPLE
 }
 
@@ -835,7 +911,7 @@ func (desc *MutableTableDescriptor) allocateIndexIDs(columnNames map[string]Colu
 	}
 
 	isCompositeColumn := make(map[ColumnID]struct{})
-	for _, col := range desc.Columns {
+	for _, col := range desc.allNonDropColumns() {
 		if HasCompositeKeyEncoding(col.Type.SemanticType) {
 			isCompositeColumn[col.ID] = struct{}{}
 		}
@@ -952,6 +1028,10 @@ func (desc *MutableTableDescriptor) allocateColumnFamilyIDs(columnNames map[stri
 		if _, ok := columnsInFamilies[col.ID]; ok {
 			return
 		}
+		if col.Virtual {
+			// Virtual computed columns are not stored in the primary index.
+			return
+		}
 		if _, ok := primaryIndexColIDs[col.ID]; ok {
 			// Primary index columns are required to be assigned to family 0.
 			desc.Families[0].ColumnNames = append(desc.Families[0].ColumnNames, col.Name)
@@ -1256,6 +1336,16 @@ func (desc *TableDescriptor) ValidateTable(st *cluster.Settings) error {
 			return fmt.Errorf("column %q invalid ID (%d) >= next column ID (%d)",
 				column.Name, column.ID, desc.NextColumnID)
 		}
+
+		if column.Virtual && !column.IsComputed() {
+			return fmt.Errorf("virtual column %q is not a computed column", column.Name)
+		}
+	}
+
+	if ttl := desc.RowLevelTTL; ttl != nil {
+		if _, ok := columnIDs[ttl.ColumnID]; !ok {
+			return fmt.Errorf("row-level TTL refers to unknown column ID %d", ttl.ColumnID)
+		}
 	}
 
 	if st != nil && st.Version.IsInitialized() {
@@ -1283,6 +1373,23 @@ func (desc *TableDescriptor) ValidateTable(st *cluster.Settings) error {
 				idx := desc.Index
 				return errors.Errorf("mutation in state %s, direction %s, index %s, id %v", m.State, m.Direction, idx.Name, idx.ID)
 			}
+		case *DescriptorMutation_ComputedColumnSwap:
+			if unSetEnums {
+				swap := desc.ComputedColumnSwap
+				return errors.Errorf("mutation in state %s, direction %s, swap of column %d with %d",
+					m.State, m.Direction, swap.OldColumnID, swap.NewColumnID)
+			}
+		case *DescriptorMutation_Check:
+			if unSetEnums {
+				return errors.Errorf("mutation in state %s, direction %s, check %q",
+					m.State, m.Direction, desc.Check.Name)
+			}
+		case *DescriptorMutation_EnumMemberPromotion:
+			if unSetEnums {
+				promotion := desc.EnumMemberPromotion
+				return errors.Errorf("mutation in state %s, direction %s, promotion of enum value %q of type %d",
+					m.State, m.Direction, promotion.Label, promotion.TypeID)
+			}
 		default:
 			return errors.Errorf("mutation in state %s, direction %s, and no column/index descriptor", m.State, m.Direction)
 		}
@@ -1324,6 +1431,20 @@ func (desc *TableDescriptor) validateColumnFamilies(
 		return nil, fmt.Errorf("the 0th family must have ID 0")
 	}
 
+	// Virtual computed columns are not stored in the primary index and thus
+	// do not belong to any column family.
+	virtualColIDs := map[ColumnID]struct{}{}
+	for _, col := range desc.Columns {
+		if col.Virtual {
+			virtualColIDs[col.ID] = struct{}{}
+		}
+	}
+	for _, m := range desc.Mutations {
+		if col := m.GetColumn(); col != nil && col.Virtual {
+			virtualColIDs[col.ID] = struct{}{}
+		}
+	}
+
 	familyNames := map[string]struct{}{}
 	familyIDs := map[FamilyID]string{}
 	colIDToFamilyID := map[ColumnID]FamilyID{}
@@ -1362,6 +1483,9 @@ func (desc *TableDescriptor) validateColumnFamilies(
 				return nil, fmt.Errorf("family %q column %d should have name %q, but found name %q",
 					family.Name, colID, name, family.ColumnNames[i])
 			}
+			if _, ok := virtualColIDs[colID]; ok {
+				return nil, fmt.Errorf("family %q contains virtual column %q", family.Name, name)
+			}
 		}
 
 		for _, colID := range family.ColumnIDs {
@@ -1372,6 +1496,9 @@ func (desc *TableDescriptor) validateColumnFamilies(
 		}
 	}
 	for colID := range columnIDs {
+		if _, ok := virtualColIDs[colID]; ok {
+			continue
+		}
 		if _, ok := colIDToFamilyID[colID]; !ok {
 			return nil, fmt.Errorf("column %d is not in any column family", colID)
 		}
@@ -1450,6 +1577,25 @@ func (desc *TableDescriptor) validateTableIndexes(
 			}
 			validateIndexDup[colID] = struct{}{}
 		}
+
+		if index.IsPartial() && index.Type == IndexDescriptor_INVERTED {
+			return fmt.Errorf("inverted index %q cannot be partial", index.Name)
+		}
+
+		if index.IsSharded() {
+			if index.ColumnNames[0] != index.Sharded.Name {
+				return fmt.Errorf("hash sharded index %q must start with its shard column %q",
+					index.Name, index.Sharded.Name)
+			}
+			if index.Sharded.ShardBuckets < 2 {
+				return fmt.Errorf("hash sharded index %q has invalid bucket count %d",
+					index.Name, index.Sharded.ShardBuckets)
+			}
+		}
+	}
+
+	if desc.PrimaryIndex.IsPartial() {
+		return fmt.Errorf("primary index %q cannot be partial", desc.PrimaryIndex.Name)
 	}
 
 	for _, colID := range desc.PrimaryIndex.ColumnIDs {
@@ -1465,9 +1611,12 @@ func (desc *TableDescriptor) validateTableIndexes(
 // PrimaryKeyString returns the pretty-printed primary key declaration for a
 // table descriptor.
 func (desc *TableDescriptor) PrimaryKeyString() string {
-	return fmt.Sprintf("PRIMARY KEY (%s)",
-		desc.PrimaryIndex.ColNamesString(),
-	)
+	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
+	f.WriteString("PRIMARY KEY (")
+	desc.PrimaryIndex.ColNamesFormat(f)
+	f.WriteByte(')')
+	desc.PrimaryIndex.shardedFormat(f)
+	return f.CloseAndGetString()
 }
 
 // validatePartitioningDescriptor validates that a PartitioningDescriptor, which
@@ -1693,7 +1842,17 @@ func columnTypeIsIndexable(t ColumnType) bool {
 // columnTypeIsInvertedIndexable returns whether the type t is valid to be indexed
 // using an inverted index.
 func columnTypeIsInvertedIndexable(t ColumnType) bool {
-	return t.SemanticType == ColumnType_JSONB
+	switch t.SemanticType {
+	case ColumnType_JSONB, ColumnType_TSVECTOR:
+		return true
+	case ColumnType_STRING:
+		// Strings are indexed under their trigrams.
+		return true
+	case ColumnType_ARRAY:
+		// The elements of the array are key-encoded in the index.
+		return t.ArrayContents != nil && !MustBeValueEncoded(*t.ArrayContents)
+	}
+	return false
 }
 
 func notIndexableError(cols []ColumnDescriptor, inverted bool) error {
@@ -1888,6 +2047,16 @@ func (desc *MutableTableDescriptor) RenameColumnDescriptor(
 				idx.StoreColumnNames[i] = newColName
 			}
 		}
+		if idx.IsSharded() {
+			if idx.ColumnIDs[0] == colID {
+				idx.Sharded.Name = newColName
+			}
+			for i, id := range idx.ColumnIDs[1:] {
+				if id == colID {
+					idx.Sharded.ColumnNames[i] = newColName
+				}
+			}
+		}
 	}
 	renameColumnInIndex(&desc.PrimaryIndex)
 	for i := range desc.Indexes {
@@ -2171,15 +2340,164 @@ func (desc *MutableTableDescriptor) MakeMutationComplete(m DescriptorMutation) e
 			if err := desc.AddIndex(*t.Index, false); err != nil {
 				return err
 			}
+
+		case *DescriptorMutation_ComputedColumnSwap:
+			if err := desc.performComputedColumnSwap(*t.ComputedColumnSwap); err != nil {
+				return err
+			}
+
+		case *DescriptorMutation_Check:
+			if err := desc.completeCheckValidation(*t.Check); err != nil {
+				return err
+			}
+
+		case *DescriptorMutation_EnumMemberPromotion:
+			// The member is promoted in the descriptor of its type by the
+			// schema changer, once the mutation has completed on all the
+			// tables that use the type.
 		}
 
 	case DescriptorMutation_DROP:
 		switch t := m.Descriptor_.(type) {
 		case *DescriptorMutation_Column:
 			desc.RemoveColumnFromFamily(t.Column.ID)
+
+		case *DescriptorMutation_Check:
+			// The validation failed: stop enforcing the check constraint.
+			desc.removeCheck(t.Check.Name)
 		}
 		// Nothing else to be done. The column/index was already removed from the
-		// set of column/index descriptors at mutation creation time.
+		// set of column/index descriptors at mutation creation time. A dropped
+		// ComputedColumnSwap is a rolled back one, which never took place.
+	}
+	return nil
+}
+
+// completeCheckValidation marks the check constraint validated by a
+// completed check mutation as validated. A validated NOT NULL check
+// constraint is replaced by the column becoming non-nullable.
+func (desc *MutableTableDescriptor) completeCheckValidation(ck TableDescriptor_CheckConstraint) error {
+	if !ck.IsNonNullConstraint {
+		for _, c := range desc.Checks {
+			if c.Name == ck.Name {
+				c.Validity = ConstraintValidity_Validated
+				return nil
+			}
+		}
+		return pgerror.NewAssertionErrorf("check %q of mutation not found", ck.Name)
+	}
+	if len(ck.ColumnIDs) != 1 {
+		return pgerror.NewAssertionErrorf("NOT NULL check %q on %d columns", ck.Name, len(ck.ColumnIDs))
+	}
+	col, err := desc.FindActiveColumnByID(ck.ColumnIDs[0])
+	if err != nil {
+		return err
+	}
+	col.Nullable = false
+	desc.removeCheck(ck.Name)
+	return nil
+}
+
+// removeCheck removes the check constraint with the given name, if any.
+func (desc *MutableTableDescriptor) removeCheck(name string) {
+	for i, c := range desc.Checks {
+		if c.Name == name {
+			desc.Checks = append(desc.Checks[:i], desc.Checks[i+1:]...)
+			return
+		}
+	}
+}
+
+// performComputedColumnSwap makes the new column and indexes of a
+// ComputedColumnSwap take the place of the old ones, which are queued up to
+// be dropped. The new column and indexes must have been made public by the
+// mutations preceding the swap.
+func (desc *MutableTableDescriptor) performComputedColumnSwap(swap ComputedColumnSwap) error {
+	oldColIdx, newColIdx := -1, -1
+	for i := range desc.Columns {
+		switch desc.Columns[i].ID {
+		case swap.OldColumnID:
+			oldColIdx = i
+		case swap.NewColumnID:
+			newColIdx = i
+		}
+	}
+	if oldColIdx == -1 || newColIdx == -1 {
+		return pgerror.NewAssertionErrorf(
+			"columns %d and %d of swap are not public", swap.OldColumnID, swap.NewColumnID)
+	}
+	oldCol, newCol := desc.Columns[oldColIdx], desc.Columns[newColIdx]
+
+	// The new column takes the name and position of the old one, and stops
+	// being computed.
+	desc.RenameColumnDescriptor(oldCol, newCol.Name)
+	desc.RenameColumnDescriptor(newCol, oldCol.Name)
+	oldCol.Name, newCol.Name = newCol.Name, oldCol.Name
+	newCol.ComputeExpr = nil
+	desc.Columns[oldColIdx] = newCol
+	desc.Columns = append(desc.Columns[:newColIdx], desc.Columns[newColIdx+1:]...)
+	for i := range desc.Families {
+		family := &desc.Families[i]
+		if family.DefaultColumnID == oldCol.ID {
+			family.DefaultColumnID = newCol.ID
+		}
+		// The new column also takes the position of the old one in its family.
+		oldPos, newPos := -1, -1
+		for j, id := range family.ColumnIDs {
+			switch id {
+			case oldCol.ID:
+				oldPos = j
+			case newCol.ID:
+				newPos = j
+			}
+		}
+		if oldPos != -1 && newPos != -1 {
+			family.ColumnIDs[oldPos], family.ColumnIDs[newPos] = family.ColumnIDs[newPos], family.ColumnIDs[oldPos]
+			family.ColumnNames[oldPos], family.ColumnNames[newPos] = family.ColumnNames[newPos], family.ColumnNames[oldPos]
+		}
+	}
+	desc.addMutation(DescriptorMutation{
+		Descriptor_: &DescriptorMutation_Column{Column: &oldCol},
+		Direction:   DescriptorMutation_DROP,
+	})
+
+	if len(swap.OldIndexIDs) != len(swap.NewIndexIDs) {
+		return pgerror.NewAssertionErrorf("mismatched index IDs in swap: %+v", swap)
+	}
+	for i := range swap.OldIndexIDs {
+		oldIdxIdx, newIdxIdx := -1, -1
+		for j := range desc.Indexes {
+			switch desc.Indexes[j].ID {
+			case swap.OldIndexIDs[i]:
+				oldIdxIdx = j
+			case swap.NewIndexIDs[i]:
+				newIdxIdx = j
+			}
+		}
+		if oldIdxIdx == -1 || newIdxIdx == -1 {
+			// One of the indexes was dropped in the meantime. The other one
+			// can't outlive it: the old index references the old column, and
+			// the new one was only meant to replace the old one.
+			for _, idxIdx := range []int{oldIdxIdx, newIdxIdx} {
+				if idxIdx != -1 {
+					idx := desc.Indexes[idxIdx]
+					desc.Indexes = append(desc.Indexes[:idxIdx], desc.Indexes[idxIdx+1:]...)
+					desc.addMutation(DescriptorMutation{
+						Descriptor_: &DescriptorMutation_Index{Index: &idx},
+						Direction:   DescriptorMutation_DROP,
+					})
+				}
+			}
+			continue
+		}
+		oldIdx, newIdx := desc.Indexes[oldIdxIdx], desc.Indexes[newIdxIdx]
+		oldIdx.Name, newIdx.Name = newIdx.Name, oldIdx.Name
+		desc.Indexes[oldIdxIdx] = newIdx
+		desc.Indexes = append(desc.Indexes[:newIdxIdx], desc.Indexes[newIdxIdx+1:]...)
+		desc.addMutation(DescriptorMutation{
+			Descriptor_: &DescriptorMutation_Index{Index: &oldIdx},
+			Direction:   DescriptorMutation_DROP,
+		})
 	}
 	return nil
 }
@@ -2213,6 +2531,41 @@ func (desc *MutableTableDescriptor) AddIndexMutation(
 	return nil
 }
 
+// AddComputedColumnSwapMutation adds a mutation to desc.Mutations that swaps
+// the column and indexes with the given IDs once the mutations preceding it
+// complete. See ComputedColumnSwap.
+func (desc *MutableTableDescriptor) AddComputedColumnSwapMutation(swap ComputedColumnSwap) {
+	m := DescriptorMutation{
+		Descriptor_: &DescriptorMutation_ComputedColumnSwap{ComputedColumnSwap: &swap},
+		Direction:   DescriptorMutation_ADD,
+	}
+	desc.addMutation(m)
+}
+
+// AddCheckValidationMutation adds a mutation to desc.Mutations that
+// validates the given check constraint against the existing data. The check
+// constraint must have been added to desc.Checks in the Validating state, so
+// that it is enforced on writes while the validation is in progress.
+func (desc *MutableTableDescriptor) AddCheckValidationMutation(ck TableDescriptor_CheckConstraint) {
+	m := DescriptorMutation{
+		Descriptor_: &DescriptorMutation_Check{Check: &ck},
+		Direction:   DescriptorMutation_ADD,
+	}
+	desc.addMutation(m)
+}
+
+// AddEnumMemberPromotionMutation adds a mutation to desc.Mutations that
+// waits for all the nodes to be able to decode a new member of an ENUM type
+// used by the table before the member is made writable. See
+// EnumMemberPromotion.
+func (desc *MutableTableDescriptor) AddEnumMemberPromotionMutation(promotion EnumMemberPromotion) {
+	m := DescriptorMutation{
+		Descriptor_: &DescriptorMutation_EnumMemberPromotion{EnumMemberPromotion: &promotion},
+		Direction:   DescriptorMutation_ADD,
+	}
+	desc.addMutation(m)
+}
+
 func (desc *MutableTableDescriptor) addMutation(m DescriptorMutation) {
 	switch m.Direction {
 	case DescriptorMutation_ADD:
@@ -2230,8 +2583,12 @@ func (desc *MutableTableDescriptor) addMutation(m DescriptorMutation) {
 }
 
 // ColumnNeedsBackfill returns true if adding the given column requires a
-// backfill (dropping a column always requires a backfill).
+// backfill (dropping a column always requires a backfill). Virtual computed
+// columns are not stored and never need one.
 func ColumnNeedsBackfill(desc *ColumnDescriptor) bool {
+	if desc.Virtual {
+		return false
+	}
 	return desc.DefaultExpr != nil || !desc.Nullable || desc.IsComputed()
 }
 
@@ -2355,10 +2712,125 @@ func (desc *DatabaseDescriptor) Validate() error {
 	// run again and mixed-version clusters always write "good" descriptors.
 	desc.Privileges.MaybeFixPrivileges(desc.GetID())
 
+	names := make(map[string]struct{}, len(desc.Types))
+	for _, t := range desc.Types {
+		if _, ok := names[t.Name]; ok {
+			return fmt.Errorf("duplicate type name: %q", t.Name)
+		}
+		names[t.Name] = struct{}{}
+	}
+	ids := make(map[ID]struct{}, len(desc.Functions))
+	for _, f := range desc.Functions {
+		if _, ok := ids[f.ID]; ok {
+			return fmt.Errorf("duplicate function ID: %d", f.ID)
+		}
+		ids[f.ID] = struct{}{}
+	}
+	names = make(map[string]struct{}, len(desc.Schemas))
+	for _, sc := range desc.Schemas {
+		if _, ok := names[sc.Name]; ok {
+			return fmt.Errorf("duplicate schema name: %q", sc.Name)
+		}
+		names[sc.Name] = struct{}{}
+	}
+
 	// Validate the privilege descriptor.
 	return desc.Privileges.Validate(desc.GetID())
 }
 
+// FindType returns the ID of the user-defined type with the given name in
+// the database.
+func (desc *DatabaseDescriptor) FindType(name string) (ID, bool) {
+	for _, t := range desc.Types {
+		if t.Name == name {
+			return t.ID, true
+		}
+	}
+	return 0, false
+}
+
+// AddType records a user-defined type in the database.
+func (desc *DatabaseDescriptor) AddType(name string, id ID) {
+	desc.Types = append(desc.Types, DatabaseDescriptor_TypeEntry{Name: name, ID: id})
+}
+
+// RemoveType removes the user-defined type with the given name from the
+// database.
+func (desc *DatabaseDescriptor) RemoveType(name string) {
+	for i, t := range desc.Types {
+		if t.Name == name {
+			desc.Types = append(desc.Types[:i], desc.Types[i+1:]...)
+			return
+		}
+	}
+}
+
+// FindFunctions returns the IDs of the overloads of the user-defined
+// function with the given name in the database.
+func (desc *DatabaseDescriptor) FindFunctions(name string) []ID {
+	var ids []ID
+	for _, f := range desc.Functions {
+		if f.Name == name {
+			ids = append(ids, f.ID)
+		}
+	}
+	return ids
+}
+
+// AddFunction records a user-defined function in the database.
+func (desc *DatabaseDescriptor) AddFunction(name string, id ID) {
+	desc.Functions = append(desc.Functions, DatabaseDescriptor_FunctionEntry{Name: name, ID: id})
+}
+
+// RemoveFunction removes the user-defined function with the given ID from
+// the database.
+func (desc *DatabaseDescriptor) RemoveFunction(id ID) {
+	for i, f := range desc.Functions {
+		if f.ID == id {
+			desc.Functions = append(desc.Functions[:i], desc.Functions[i+1:]...)
+			return
+		}
+	}
+}
+
+// FindSchema returns the ID of the user-defined schema with the given name
+// in the database.
+func (desc *DatabaseDescriptor) FindSchema(name string) (ID, bool) {
+	for _, sc := range desc.Schemas {
+		if sc.Name == name {
+			return sc.ID, true
+		}
+	}
+	return 0, false
+}
+
+// FindSchemaName returns the name of the user-defined schema with the given
+// ID, if it exists in the database.
+func (desc *DatabaseDescriptor) FindSchemaName(id ID) (string, bool) {
+	for _, sc := range desc.Schemas {
+		if sc.ID == id {
+			return sc.Name, true
+		}
+	}
+	return "", false
+}
+
+// AddSchema records a user-defined schema in the database.
+func (desc *DatabaseDescriptor) AddSchema(name string, id ID) {
+	desc.Schemas = append(desc.Schemas, DatabaseDescriptor_SchemaEntry{Name: name, ID: id})
+}
+
+// RemoveSchema removes the user-defined schema with the given name from the
+// database.
+func (desc *DatabaseDescriptor) RemoveSchema(name string) {
+	for i, sc := range desc.Schemas {
+		if sc.Name == name {
+			desc.Schemas = append(desc.Schemas[:i], desc.Schemas[i+1:]...)
+			return
+		}
+	}
+}
+
 // GetID returns the ID of the descriptor.
 func (desc *Descriptor) GetID() ID {
 	switch t := desc.Union.(type) {
@@ -2366,6 +2838,12 @@ func (desc *Descriptor) GetID() ID {
 		return t.Table.ID
 	case *Descriptor_Database:
 		return t.Database.ID
+	case *Descriptor_Type:
+		return t.Type.ID
+	case *Descriptor_Function:
+		return t.Function.ID
+	case *Descriptor_Schema:
+		return t.Schema.ID
 	default:
 		return 0
 	}
@@ -2378,6 +2856,12 @@ func (desc *Descriptor) GetName() string {
 		return t.Table.Name
 	case *Descriptor_Database:
 		return t.Database.Name
+	case *Descriptor_Type:
+		return t.Type.Name
+	case *Descriptor_Function:
+		return t.Function.Name
+	case *Descriptor_Schema:
+		return t.Schema.Name
 	default:
 		return ""
 	}
@@ -2441,7 +2925,7 @@ func (desc TableDescriptor) GetDescMetadataKey() roachpb.Key {
 
 // GetNameMetadataKey returns the namespace key for the table.
 func (desc TableDescriptor) GetNameMetadataKey() roachpb.Key {
-	return MakeNameMetadataKey(desc.ParentID, desc.Name)
+	return MakeNameMetadataKey(desc.GetNamespaceParentID(), desc.Name)
 }
 
 // SQLString returns the SQL statement describing the column.
@@ -2462,7 +2946,11 @@ func (desc *ColumnDescriptor) SQLString() string {
 	if desc.IsComputed() {
 		f.WriteString(" AS (")
 		f.WriteString(*desc.ComputeExpr)
-		f.WriteString(") STORED")
+		if desc.Virtual {
+			f.WriteString(") VIRTUAL")
+		} else {
+			f.WriteString(") STORED")
+		}
 	}
 	return f.CloseAndGetString()
 }
@@ -2593,6 +3081,11 @@ func (desc *ColumnDescriptor) IsComputed() bool {
 	return desc.ComputeExpr != nil
 }
 
+// IsVirtual is part of the opt.Column interface.
+func (desc *ColumnDescriptor) IsVirtual() bool {
+	return desc.Virtual
+}
+
 // DefaultExprStr is part of the opt.Column interface.
 func (desc *ColumnDescriptor) DefaultExprStr() string {
 	return *desc.DefaultExpr
@@ -224,6 +224,19 @@ CREATE TABLE system.comments (
    comment   STRING NOT NULL, -- the comment
    PRIMARY KEY (type, object_id, sub_id)
 );`
+
+	// notifications stores the payloads sent by NOTIFY. They are delivered to
+	// the listening sessions through rangefeeds over this table.
+	NotificationsTableSchema = `
+CREATE TABLE system.notifications (
+  timestamp     TIMESTAMP  NOT NULL,
+  "uniqueID"    INT8       DEFAULT unique_rowid(),
+  channel       STRING     NOT NULL,
+  payload       STRING     NOT NULL,
+  "reportingID" INT8       NOT NULL,
+  PRIMARY KEY (timestamp, "uniqueID"),
+  FAMILY (timestamp, "uniqueID", channel, payload, "reportingID")
+);`
 )
 
 func pk(name string) IndexDescriptor {
@@ -264,6 +277,7 @@ var SystemAllowedPrivileges = map[ID]privilege.List{
 	keys.LocationsTableID:       privilege.ReadWriteData,
 	keys.RoleMembersTableID:     privilege.ReadWriteData,
 	keys.CommentsTableID:        privilege.ReadWriteData,
+	keys.NotificationsTableID:   privilege.ReadWriteData,
 }
 
 // Helpers used to make some of the TableDescriptor literals below more concise.
@@ -869,6 +883,43 @@ var (
 		FormatVersion:  InterleavedFormatVersion,
 		NextMutationID: 1,
 	}
+
+	// NotificationsTable is the descriptor for the notifications table.
+	NotificationsTable = TableDescriptor{
+		Name:     "notifications",
+		ID:       keys.NotificationsTableID,
+		ParentID: keys.SystemDatabaseID,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "timestamp", ID: 1, Type: colTypeTimestamp},
+			{Name: "uniqueID", ID: 2, Type: colTypeInt, DefaultExpr: &uniqueRowIDString},
+			{Name: "channel", ID: 3, Type: colTypeString},
+			{Name: "payload", ID: 4, Type: colTypeString},
+			{Name: "reportingID", ID: 5, Type: colTypeInt},
+		},
+		NextColumnID: 6,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "fam_0_timestamp_uniqueID_channel_payload_reportingID",
+				ID:          0,
+				ColumnNames: []string{"timestamp", "uniqueID", "channel", "payload", "reportingID"},
+				ColumnIDs:   []ColumnID{1, 2, 3, 4, 5},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:             "primary",
+			ID:               1,
+			Unique:           true,
+			ColumnNames:      []string{"timestamp", "uniqueID"},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC, IndexDescriptor_ASC},
+			ColumnIDs:        []ColumnID{1, 2},
+		},
+		NextIndexID:    2,
+		Privileges:     NewCustomSuperuserPrivilegeDescriptor(SystemAllowedPrivileges[keys.NotificationsTableID]),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
 )
 
 // Create a kv pair for the zone config for the given key and config value.
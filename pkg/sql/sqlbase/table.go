@@ -110,6 +110,13 @@ func MakeColumnDefDescs(
 		Nullable: d.Nullable.Nullability != tree.NotNull && !d.PrimaryKey,
 	}
 
+	// Resolve references to user-defined types.
+	typ, err := semaCtx.ResolveCastTargetType(d.Type)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	d.Type = typ.(coltypes.T)
+
 	// Set Type.SemanticType and Type.Locale.
 	colDatumType := coltypes.CastTargetToDatumType(d.Type)
 	colTyp, err := DatumTypeToColumnType(colDatumType)
@@ -142,6 +149,7 @@ func MakeColumnDefDescs(
 	if d.IsComputed() {
 		s := tree.Serialize(d.Computed.Expr)
 		col.ComputeExpr = &s
+		col.Virtual = d.Computed.Virtual
 	}
 
 	var idx *IndexDescriptor
@@ -222,6 +230,11 @@ type ConstraintDetail struct {
 	Details     string
 	Unvalidated bool
 
+	// Deferrable and InitiallyDeferred are only set for FK and Unique
+	// Constraints declared DEFERRABLE.
+	Deferrable        bool
+	InitiallyDeferred bool
+
 	// Only populated for FK, PK, and Unique Constraints.
 	Index *IndexDescriptor
 
@@ -302,11 +315,15 @@ func (desc *TableDescriptor) collectConstraintInfo(
 				detail.Index = index
 			}
 			info[index.Name] = detail
-		} else if index.Unique {
+		} else if index.Unique || index.DeferrableUnique {
 			if _, ok := info[index.Name]; ok {
 				return nil, errors.Errorf("duplicate constraint name: %q", index.Name)
 			}
-			detail := ConstraintDetail{Kind: ConstraintTypeUnique}
+			detail := ConstraintDetail{
+				Kind:              ConstraintTypeUnique,
+				Deferrable:        index.DeferrableUnique,
+				InitiallyDeferred: index.InitiallyDeferred,
+			}
 			if tableLookup != nil {
 				detail.Columns = index.ColumnNames
 				detail.Index = index
@@ -320,6 +337,8 @@ func (desc *TableDescriptor) collectConstraintInfo(
 			}
 			detail := ConstraintDetail{Kind: ConstraintTypeFK}
 			detail.Unvalidated = index.ForeignKey.Validity == ConstraintValidity_Unvalidated
+			detail.Deferrable = index.ForeignKey.Deferrable
+			detail.InitiallyDeferred = index.ForeignKey.InitiallyDeferred
 			numCols := len(index.ColumnIDs)
 			if index.ForeignKey.SharedPrefixLen > 0 {
 				numCols = int(index.ForeignKey.SharedPrefixLen)
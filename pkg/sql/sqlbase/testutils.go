@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
 )
@@ -139,6 +140,10 @@ func RandDatumWithNullChance(rng *rand.Rand, typ ColumnType, nullChance int) tre
 			return nil
 		}
 		return &tree.DJSON{JSON: j}
+	case ColumnType_TSVECTOR:
+		return tree.NewDTSVector(tsearch.RandTSVector(rng))
+	case ColumnType_TSQUERY:
+		return tree.NewDTSQuery(tsearch.RandTSQuery(rng))
 	case ColumnType_TUPLE:
 		tuple := tree.DTuple{D: make(tree.Datums, len(typ.TupleContents))}
 		for i, internalType := range typ.TupleContents {
@@ -202,7 +207,8 @@ func RandDatumWithNullChance(rng *rand.Rand, typ ColumnType, nullChance int) tre
 				contentsTyp = RandColumnType(rng)
 				switch contentsTyp.SemanticType {
 				// Can't have an array of an array.
-				case ColumnType_ARRAY, ColumnType_JSONB:
+				case ColumnType_ARRAY, ColumnType_JSONB, ColumnType_ENUM,
+					ColumnType_TSVECTOR, ColumnType_TSQUERY:
 				default:
 					break LOOP
 				}
@@ -223,6 +229,16 @@ func RandDatumWithNullChance(rng *rand.Rand, typ ColumnType, nullChance int) tre
 		return tree.DNull
 	case ColumnType_OIDVECTOR:
 		return tree.DNull
+	case ColumnType_ENUM:
+		if len(typ.EnumMembers) == 0 {
+			panic("members are required for ENUM")
+		}
+		m := typ.EnumMembers[rng.Intn(len(typ.EnumMembers))]
+		return &tree.DEnum{
+			EnumTyp:     typ.ToDatumType().(types.TEnum),
+			PhysicalRep: m.PhysicalRep,
+			LogicalRep:  m.LogicalRep,
+		}
 	default:
 		panic(fmt.Sprintf("invalid type %s", typ.String()))
 	}
@@ -258,6 +274,20 @@ func RandCollationLocale(rng *rand.Rand) *string {
 	return &collationLocales[rng.Intn(len(collationLocales))]
 }
 
+// randEnumColumnType returns the column type of a random ENUM type with
+// at least two members.
+func randEnumColumnType(rng *rand.Rand) ColumnType {
+	labels := make([]string, 2+rng.Intn(5))
+	for i := range labels {
+		labels[i] = fmt.Sprintf("l%d", i)
+	}
+	desc := TypeDescriptor{ID: ID(1000 + rng.Intn(100)), Name: "rand_enum"}
+	if err := desc.InitEnumMembers(labels); err != nil {
+		panic(err)
+	}
+	return desc.ColumnType()
+}
+
 // RandColumnType returns a random ColumnType value.
 func RandColumnType(rng *rand.Rand) ColumnType {
 	typ := ColumnType{SemanticType: columnSemanticTypes[rng.Intn(len(columnSemanticTypes))]}
@@ -267,6 +297,9 @@ func RandColumnType(rng *rand.Rand) ColumnType {
 	if typ.SemanticType == ColumnType_COLLATEDSTRING {
 		typ.Locale = RandCollationLocale(rng)
 	}
+	if typ.SemanticType == ColumnType_ENUM {
+		typ = randEnumColumnType(rng)
+	}
 	if typ.SemanticType == ColumnType_ARRAY {
 		typ.ArrayContents = &arrayElemSemanticTypes[rng.Intn(len(arrayElemSemanticTypes))]
 		if *typ.ArrayContents == ColumnType_COLLATEDSTRING {
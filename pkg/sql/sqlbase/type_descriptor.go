@@ -0,0 +1,334 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
+
+var _ DescriptorProto = &TypeDescriptor{}
+
+// SetID implements the DescriptorProto interface.
+func (desc *TypeDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *TypeDescriptor) TypeName() string {
+	return "type"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *TypeDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+// Types cannot be audited.
+func (desc *TypeDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// Validate validates that the type descriptor is well formed. Checks include
+// verifying that the members of an ENUM are unique and sorted by their
+// physical representations.
+func (desc *TypeDescriptor) Validate() error {
+	if err := validateName(desc.Name, "type"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return fmt.Errorf("invalid type ID %d", desc.ID)
+	}
+	if desc.ParentID == 0 {
+		return fmt.Errorf("invalid parent ID %d for type %q", desc.ParentID, desc.Name)
+	}
+	labels := make(map[string]struct{}, len(desc.EnumMembers))
+	for i := range desc.EnumMembers {
+		m := &desc.EnumMembers[i]
+		if _, ok := labels[m.LogicalRep]; ok {
+			return fmt.Errorf("duplicate label %q in type %q", m.LogicalRep, desc.Name)
+		}
+		labels[m.LogicalRep] = struct{}{}
+		if len(m.PhysicalRep) == 0 {
+			return fmt.Errorf("label %q of type %q has no physical representation",
+				m.LogicalRep, desc.Name)
+		}
+		if i > 0 && bytes.Compare(desc.EnumMembers[i-1].PhysicalRep, m.PhysicalRep) >= 0 {
+			return fmt.Errorf("labels of type %q are not sorted by physical representation", desc.Name)
+		}
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
+// ColumnType returns the column type of columns of this type, including the
+// members of the type. Table descriptors are stored without the members,
+// which are filled in by HydrateEnumTypes.
+func (desc *TypeDescriptor) ColumnType() ColumnType {
+	return ColumnType{
+		SemanticType: ColumnType_ENUM,
+		EnumTypeID:   desc.ID,
+		EnumName:     desc.Name,
+		EnumMembers:  append([]EnumMember(nil), desc.EnumMembers...),
+	}
+}
+
+// DatumType returns the datum type of values of this type.
+func (desc *TypeDescriptor) DatumType() types.TEnum {
+	return enumDatumType(desc.ID, desc.Name, desc.EnumMembers)
+}
+
+func enumDatumType(id ID, name string, members []EnumMember) types.TEnum {
+	labels := make([]string, len(members))
+	physicalReps := make([][]byte, len(members))
+	var readOnly []bool
+	for i := range members {
+		labels[i] = members[i].LogicalRep
+		physicalReps[i] = members[i].PhysicalRep
+		if members[i].Capability == EnumMember_READ_ONLY {
+			if readOnly == nil {
+				readOnly = make([]bool, len(members))
+			}
+			readOnly[i] = true
+		}
+	}
+	return types.MakeEnum(uint32(id), name, labels, physicalReps, readOnly)
+}
+
+// InitEnumMembers populates the members of an ENUM type with the given
+// labels, in declaration order.
+func (desc *TypeDescriptor) InitEnumMembers(labels []string) error {
+	desc.EnumMembers = make([]EnumMember, 0, len(labels))
+	reps := GenEvenlySpacedPhysicalReps(len(labels))
+	for i, label := range labels {
+		if desc.FindEnumMember(label) != -1 {
+			return pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+				"enum label %q used more than once", label)
+		}
+		desc.EnumMembers = append(desc.EnumMembers, EnumMember{
+			LogicalRep:  label,
+			PhysicalRep: reps[i],
+		})
+	}
+	return nil
+}
+
+// FindEnumMember returns the position of the member with the given label,
+// or -1 if there is no such member.
+func (desc *TypeDescriptor) FindEnumMember(label string) int {
+	for i := range desc.EnumMembers {
+		if desc.EnumMembers[i].LogicalRep == label {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddEnumMember adds a new member to an ENUM type. The member is placed
+// before or after the member with the given neighbor label, or at the end of
+// the type if neighbor is empty. The member is READ_ONLY until it is promoted
+// by PromoteEnumMember, once all the nodes can decode it.
+func (desc *TypeDescriptor) AddEnumMember(label string, neighbor string, before bool) error {
+	if desc.FindEnumMember(label) != -1 {
+		return pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+			"enum label %q already exists", label)
+	}
+	// pos is the position at which the new member is inserted.
+	pos := len(desc.EnumMembers)
+	if neighbor != "" {
+		pos = desc.FindEnumMember(neighbor)
+		if pos == -1 {
+			return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+				"%q is not an existing enum label", neighbor)
+		}
+		if !before {
+			pos++
+		}
+	}
+	var prev, next []byte
+	if pos > 0 {
+		prev = desc.EnumMembers[pos-1].PhysicalRep
+	}
+	if pos < len(desc.EnumMembers) {
+		next = desc.EnumMembers[pos].PhysicalRep
+	}
+	desc.EnumMembers = append(desc.EnumMembers, EnumMember{})
+	copy(desc.EnumMembers[pos+1:], desc.EnumMembers[pos:])
+	desc.EnumMembers[pos] = EnumMember{
+		LogicalRep:  label,
+		PhysicalRep: GenPhysicalRepBetween(prev, next),
+		Capability:  EnumMember_READ_ONLY,
+	}
+	return nil
+}
+
+// PromoteEnumMember makes the READ_ONLY member with the given label
+// writable. It returns false if the type has no such READ_ONLY member.
+func (desc *TypeDescriptor) PromoteEnumMember(label string) bool {
+	i := desc.FindEnumMember(label)
+	if i == -1 || desc.EnumMembers[i].Capability != EnumMember_READ_ONLY {
+		return false
+	}
+	desc.EnumMembers[i].Capability = EnumMember_ALL
+	return true
+}
+
+// HydrateEnumTypes fills in the members of the ENUM column types of the
+// table, which are not stored in the table descriptor, from the descriptors
+// of their types. The types are read with the given transaction; since
+// ALTER TYPE increments the version of the tables that use the type, all
+// the copies of a given version of the table descriptor have the same
+// members.
+func (desc *TableDescriptor) HydrateEnumTypes(ctx context.Context, txn *client.Txn) error {
+	if desc.Dropped() {
+		// The types of a dropped table may have been dropped already.
+		return nil
+	}
+	var typDescs map[ID]*TypeDescriptor
+	hydrate := func(col *ColumnDescriptor) error {
+		if col.Type.SemanticType != ColumnType_ENUM {
+			return nil
+		}
+		typDesc, ok := typDescs[col.Type.EnumTypeID]
+		if !ok {
+			var err error
+			typDesc, err = GetTypeDescFromID(ctx, txn, col.Type.EnumTypeID)
+			if err != nil {
+				return err
+			}
+			if typDescs == nil {
+				typDescs = make(map[ID]*TypeDescriptor)
+			}
+			typDescs[col.Type.EnumTypeID] = typDesc
+		}
+		col.Type = typDesc.ColumnType()
+		return nil
+	}
+	for i := range desc.Columns {
+		if err := hydrate(&desc.Columns[i]); err != nil {
+			return err
+		}
+	}
+	for i := range desc.Mutations {
+		if col := desc.Mutations[i].GetColumn(); col != nil {
+			if err := hydrate(col); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// withoutEnumMembers returns the table descriptor as it is stored, without
+// the members of its ENUM column types. The descriptor is copied if any of
+// its column types has members.
+func (desc *TableDescriptor) withoutEnumMembers() *TableDescriptor {
+	hasMembers := func(col *ColumnDescriptor) bool {
+		return len(col.Type.EnumMembers) > 0
+	}
+	found := false
+	for i := range desc.Columns {
+		found = found || hasMembers(&desc.Columns[i])
+	}
+	for i := range desc.Mutations {
+		if col := desc.Mutations[i].GetColumn(); col != nil {
+			found = found || hasMembers(col)
+		}
+	}
+	if !found {
+		return desc
+	}
+	stored := *desc
+	stored.Columns = append([]ColumnDescriptor(nil), desc.Columns...)
+	for i := range stored.Columns {
+		stored.Columns[i].Type.EnumMembers = nil
+	}
+	stored.Mutations = append([]DescriptorMutation(nil), desc.Mutations...)
+	for i := range stored.Mutations {
+		if col := stored.Mutations[i].GetColumn(); col != nil && hasMembers(col) {
+			storedCol := *col
+			storedCol.Type.EnumMembers = nil
+			stored.Mutations[i].Descriptor_ = &DescriptorMutation_Column{Column: &storedCol}
+		}
+	}
+	return &stored
+}
+
+// GenEvenlySpacedPhysicalReps returns n increasing physical representations
+// for the members of a new ENUM type. The representations are spread across
+// the byte space, so that members added later with GenPhysicalRepBetween
+// have short representations.
+func GenEvenlySpacedPhysicalReps(n int) [][]byte {
+	// Use enough bytes that consecutive representations are at least two
+	// apart. This ensures that adjusting a representation away from a
+	// trailing zero byte (see below) keeps them distinct.
+	width := 1
+	for space := 256; space < 2*(n+1); space *= 256 {
+		width++
+	}
+	space := uint64(1) << uint(8*width)
+	reps := make([][]byte, n)
+	for i := range reps {
+		v := uint64(i+1) * space / uint64(n+1)
+		if v&0xff == 0 {
+			// Representations never end with a zero byte: there would be no
+			// room between such a representation and its prefix.
+			v++
+		}
+		rep := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			rep[j] = byte(v)
+			v >>= 8
+		}
+		reps[i] = rep
+	}
+	return reps
+}
+
+// GenPhysicalRepBetween returns a physical representation that sorts
+// strictly between prev and next. A nil prev stands for the minimum
+// representation, and a nil next for the maximum one. The returned
+// representation never ends with a zero byte.
+func GenPhysicalRepBetween(prev, next []byte) []byte {
+	var res []byte
+	// bounded is false once the result is known to sort before next, at
+	// which point only prev constrains the remaining bytes.
+	bounded := next != nil
+	for i := 0; ; i++ {
+		lo := 0
+		if i < len(prev) {
+			lo = int(prev[i])
+		}
+		hi := 256
+		if bounded {
+			if i >= len(next) {
+				panic(fmt.Sprintf("no physical representation between %x and %x", prev, next))
+			}
+			hi = int(next[i])
+		}
+		if hi-lo > 1 {
+			return append(res, byte((lo+hi)/2))
+		}
+		res = append(res, byte(lo))
+		if hi-lo == 1 {
+			bounded = false
+		}
+	}
+}
@@ -127,11 +127,17 @@ type tableWriterBase struct {
 	b *client.Batch
 	// batchSize is the current batch size (when known).
 	batchSize int
+	// deferrer holds the constraint checks queued by the rows written, if
+	// any.
+	deferrer tree.ConstraintDeferrer
 }
 
-func (tb *tableWriterBase) init(txn *client.Txn) {
+func (tb *tableWriterBase) init(txn *client.Txn, evalCtx *tree.EvalContext) {
 	tb.txn = txn
 	tb.b = txn.NewBatch()
+	if evalCtx != nil {
+		tb.deferrer = evalCtx.ConstraintDeferrer
+	}
 }
 
 // flushAndStartNewBatch shares the common flushAndStartNewBatch()
@@ -154,10 +160,12 @@ func (tb *tableWriterBase) curBatchSize() int { return tb.batchSize }
 func (tb *tableWriterBase) finalize(
 	ctx context.Context, autoCommit autoCommitOpt, tableDesc *sqlbase.ImmutableTableDescriptor,
 ) (err error) {
-	if autoCommit == autoCommitEnabled {
+	if autoCommit == autoCommitEnabled &&
+		(tb.deferrer == nil || !tb.deferrer.HasPendingConstraintChecks()) {
 		// An auto-txn can commit the transaction with the batch. This is an
 		// optimization to avoid an extra round-trip to the transaction
-		// coordinator.
+		// coordinator. It is not possible when constraint checks are pending;
+		// the connExecutor runs them before committing the transaction itself.
 		err = tb.txn.CommitInBatch(ctx, tb.b)
 	} else {
 		err = tb.txn.Run(ctx, tb.b)
@@ -36,14 +36,19 @@ type tableDeleter struct {
 
 	rd    row.Deleter
 	alloc *sqlbase.DatumAlloc
+
+	// evalCtx is used to compute virtual computed columns when rows are
+	// scanned. It may be nil.
+	evalCtx *tree.EvalContext
 }
 
 // walkExprs is part of the tableWriter interface.
 func (td *tableDeleter) walkExprs(_ func(desc string, index int, expr tree.TypedExpr)) {}
 
 // init is part of the tableWriter interface.
-func (td *tableDeleter) init(txn *client.Txn, _ *tree.EvalContext) error {
-	td.tableWriterBase.init(txn)
+func (td *tableDeleter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
+	td.tableWriterBase.init(txn, evalCtx)
+	td.evalCtx = evalCtx
 	return nil
 }
 
@@ -204,6 +209,7 @@ func (td *tableDeleter) deleteAllRowsScan(
 	}
 
 	var rf row.Fetcher
+	rf.SetEvalContext(td.evalCtx)
 	tableArgs := row.FetcherTableArgs{
 		Desc:            td.rd.Helper.TableDesc,
 		Index:           &td.rd.Helper.TableDesc.PrimaryIndex,
@@ -332,6 +338,7 @@ func (td *tableDeleter) deleteIndexScan(
 	}
 
 	var rf row.Fetcher
+	rf.SetEvalContext(td.evalCtx)
 	tableArgs := row.FetcherTableArgs{
 		Desc:            td.rd.Helper.TableDesc,
 		Index:           &td.rd.Helper.TableDesc.PrimaryIndex,
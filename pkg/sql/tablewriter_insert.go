@@ -30,8 +30,8 @@ type tableInserter struct {
 }
 
 // init is part of the tableWriter interface.
-func (ti *tableInserter) init(txn *client.Txn, _ *tree.EvalContext) error {
-	ti.tableWriterBase.init(txn)
+func (ti *tableInserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
+	ti.tableWriterBase.init(txn, evalCtx)
 	return nil
 }
 
@@ -30,8 +30,8 @@ type tableUpdater struct {
 }
 
 // init is part of the tableWriter interface.
-func (tu *tableUpdater) init(txn *client.Txn, _ *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+func (tu *tableUpdater) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
+	tu.tableWriterBase.init(txn, evalCtx)
 	return nil
 }
 
@@ -61,7 +61,7 @@ type tableUpserterBase struct {
 }
 
 func (tu *tableUpserterBase) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+	tu.tableWriterBase.init(txn, evalCtx)
 	tableDesc := tu.tableDesc()
 
 	tu.insertRows.Init(
@@ -238,6 +238,12 @@ type tableUpserter struct {
 	conflictIndex sqlbase.IndexDescriptor
 	anyComputed   bool
 
+	// conflictIndexPred is the predicate of conflictIndex if it is a partial
+	// index, set by init. Only the rows that satisfy it can conflict with the
+	// rows in the index.
+	conflictIndexPred  tree.TypedExpr
+	conflictIndexIVars sqlbase.RowIndexedVarContainer
+
 	evalCtx *tree.EvalContext
 
 	// These are set for ON CONFLICT DO UPDATE, but not for DO NOTHING
@@ -259,7 +265,7 @@ type tableUpserter struct {
 
 // init is part of the tableWriter interface.
 func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+	tu.tableWriterBase.init(txn, evalCtx)
 
 	tu.evalCtx = evalCtx
 
@@ -270,6 +276,20 @@ func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error
 
 	tableDesc := tu.tableDesc()
 
+	if tu.conflictIndex.IsPartial() {
+		preds, err := sqlbase.MakePartialIndexExprs(
+			[]sqlbase.IndexDescriptor{tu.conflictIndex}, tableDesc, evalCtx,
+		)
+		if err != nil {
+			return err
+		}
+		tu.conflictIndexPred = preds[0]
+		tu.conflictIndexIVars = sqlbase.RowIndexedVarContainer{
+			Cols:    tableDesc.Columns,
+			Mapping: tu.ri.InsertColIDtoRowIndex,
+		}
+	}
+
 	requestedCols := tableDesc.Columns
 
 	if len(tu.updateCols) == 0 {
@@ -315,6 +335,7 @@ func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error
 		ValNeededForCol: valNeededForCol,
 	}
 
+	tu.fetcher.SetEvalContext(evalCtx)
 	return tu.fetcher.Init(
 		false /* reverse */, false /*returnRangeInfo*/, false /* isCheck */, tu.alloc, tableArgs,
 	)
@@ -799,6 +820,18 @@ func (tu *tableUpserter) upsertRowPKs(
 	conflictingPKs := make(map[int]roachpb.Key)
 	for i, result := range b.Results {
 		if len(result.Rows) == 1 {
+			if tu.conflictIndexPred != nil {
+				// A row that doesn't belong in the partial conflict index
+				// can't conflict with the rows in it.
+				tu.conflictIndexIVars.CurSourceRow = tu.insertRows.At(i)
+				ok, err := sqlbase.EvalPartialIndexPredicate(tu.evalCtx, tu.conflictIndexPred, &tu.conflictIndexIVars)
+				if err != nil {
+					return nil, nil, err
+				}
+				if !ok {
+					continue
+				}
+			}
 			if result.Rows[0].Value != nil {
 				upsertRowPK, err := sqlbase.ExtractIndexKey(tu.alloc, tableDesc.TableDesc(), result.Rows[0])
 				if err != nil {
@@ -37,8 +37,8 @@ type fastTableUpserter struct {
 }
 
 // init is part of the tableWriter interface.
-func (tu *fastTableUpserter) init(txn *client.Txn, _ *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+func (tu *fastTableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
+	tu.tableWriterBase.init(txn, evalCtx)
 	return nil
 }
 
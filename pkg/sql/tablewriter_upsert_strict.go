@@ -39,7 +39,7 @@ type strictTableUpserter struct {
 }
 
 func (tu *strictTableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error {
-	tu.tableWriterBase.init(txn)
+	tu.tableWriterBase.init(txn, evalCtx)
 
 	err := tu.tableUpserterBase.init(txn, evalCtx)
 	if err != nil {
@@ -0,0 +1,344 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+	"github.com/pkg/errors"
+)
+
+// Temporary tables live in a schema private to the session that created
+// them. The schema is named after the session ID (see temporarySchemaName)
+// and is created lazily, on the first CREATE TEMP TABLE of the session in a
+// given database.
+//
+// Unlike user-defined schemas, temporary schemas do not have descriptors. A
+// temporary schema is only an entry in system.namespace, (database ID, schema
+// name) -> schema ID, and the tables of the schema are recorded in
+// system.namespace under the schema ID instead of under the database ID (see
+// TableDescriptor.GetNamespaceParentID). The ParentID of a temporary table is
+// still the ID of its database.
+//
+// The temporary schemas of a session, and all the tables in them, are
+// dropped when the session ends. Sessions that do not end cleanly, for
+// example because their node crashed, are taken care of by the
+// TemporaryObjectCleaner.
+
+const tempObjectCleanupIntervalKey = "sql.temp_object_cleaner.cleanup_interval"
+
+// TempObjectCleanupInterval is the interval at which the
+// TemporaryObjectCleaner looks for temporary schemas left behind by sessions
+// that are gone. It must be positive, as the cleaner would otherwise run
+// back to back.
+var TempObjectCleanupInterval = settings.RegisterValidatedDurationSetting(
+	tempObjectCleanupIntervalKey,
+	"how often to clean up the temporary tables of sessions that ended abnormally",
+	30*time.Minute,
+	func(v time.Duration) error {
+		if v <= 0 {
+			return errors.Errorf("cannot set %s to a non-positive duration: %s",
+				tempObjectCleanupIntervalKey, v)
+		}
+		return nil
+	},
+)
+
+// temporarySchemaName returns the name of the temporary schema of the session
+// with the given ID.
+func temporarySchemaName(sessionID ClusterWideID) string {
+	return fmt.Sprintf("%s_%d_%d", sessiondata.PgTempSchemaName, sessionID.Hi, sessionID.Lo)
+}
+
+// temporarySchemaSessionID is the inverse of temporarySchemaName.
+func temporarySchemaSessionID(scName string) (ClusterWideID, error) {
+	if !sessiondata.IsTemporarySchemaName(scName) {
+		return ClusterWideID{}, errors.Errorf("%q is not a temporary schema", scName)
+	}
+	parts := strings.Split(strings.TrimPrefix(scName, sessiondata.PgTempSchemaName+"_"), "_")
+	if len(parts) != 2 {
+		return ClusterWideID{}, errors.Errorf("malformed temporary schema name %q", scName)
+	}
+	hi, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return ClusterWideID{}, errors.Wrapf(err, "malformed temporary schema name %q", scName)
+	}
+	lo, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return ClusterWideID{}, errors.Wrapf(err, "malformed temporary schema name %q", scName)
+	}
+	return ClusterWideID{Uint128: uint128.FromInts(hi, lo)}, nil
+}
+
+// getTemporarySchemaID looks up the ID of the given temporary schema in the
+// given database. InvalidID is returned if the schema does not exist.
+func getTemporarySchemaID(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID, scName string,
+) (sqlbase.ID, error) {
+	gr, err := txn.Get(ctx, sqlbase.MakeNameMetadataKey(dbID, scName))
+	if err != nil {
+		return sqlbase.InvalidID, err
+	}
+	if !gr.Exists() {
+		return sqlbase.InvalidID, nil
+	}
+	return sqlbase.ID(gr.ValueInt()), nil
+}
+
+// getNamespaceParentID returns the ID under which the objects of the given
+// schema are recorded in system.namespace: the ID of the database for the
+// public schema, and the ID of the schema for temporary and user-defined
+// schemas. InvalidID is returned if the schema does not exist.
+func getNamespaceParentID(
+	ctx context.Context, txn *client.Txn, dbID sqlbase.ID, scName string,
+) (sqlbase.ID, error) {
+	if scName == tree.PublicSchema {
+		return dbID, nil
+	}
+	if sessiondata.IsTemporarySchemaName(scName) {
+		return getTemporarySchemaID(ctx, txn, dbID, scName)
+	}
+	// The user-defined schemas are listed in the database descriptor. It is
+	// read from the transaction and not from the database cache so that a
+	// schema created by a recent or by the current transaction is found.
+	dbDesc, err := sqlbase.GetDatabaseDescFromID(ctx, txn, dbID)
+	if err != nil {
+		return sqlbase.InvalidID, err
+	}
+	if id, ok := dbDesc.FindSchema(scName); ok {
+		return id, nil
+	}
+	return sqlbase.InvalidID, nil
+}
+
+// getOrCreateTemporarySchema returns the ID of the temporary schema of the
+// current session in the given database, creating the schema if needed.
+func (p *planner) getOrCreateTemporarySchema(
+	ctx context.Context, dbID sqlbase.ID,
+) (sqlbase.ID, error) {
+	scName := temporarySchemaName(p.ExtendedEvalContext().SessionID)
+	scID, err := getTemporarySchemaID(ctx, p.txn, dbID, scName)
+	if err != nil || scID != sqlbase.InvalidID {
+		return scID, err
+	}
+	scID, err = GenerateUniqueDescID(ctx, p.ExecCfg().DB)
+	if err != nil {
+		return sqlbase.InvalidID, err
+	}
+	key := sqlbase.MakeNameMetadataKey(dbID, scName)
+	if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(ctx, 2, "CPut %s -> %d", key, scID)
+	}
+	if err := p.txn.CPut(ctx, key, scID, nil); err != nil {
+		return sqlbase.InvalidID, err
+	}
+	p.sessionDataMutator.SetTemporarySchemaName(scName)
+	return scID, nil
+}
+
+// temporarySchema identifies a temporary schema in a database.
+type temporarySchema struct {
+	dbID   sqlbase.ID
+	dbName string
+	name   string
+	id     sqlbase.ID
+}
+
+// getTemporarySchemas returns all the temporary schemas that satisfy the
+// given predicate, across all databases.
+func getTemporarySchemas(
+	ctx context.Context, ie *InternalExecutor, txn *client.Txn, pred func(scName string) bool,
+) ([]temporarySchema, error) {
+	rows, _, err := ie.Query(
+		ctx, "get-temp-schemas", txn,
+		`SELECT sc."parentID", db.name, sc.name, sc.id
+       FROM system.namespace AS sc JOIN system.namespace AS db
+         ON db."parentID" = $1 AND db.id = sc."parentID"
+      WHERE sc.name LIKE $2`,
+		keys.RootNamespaceID, sessiondata.PgTempSchemaName+"\\_%",
+	)
+	if err != nil {
+		return nil, err
+	}
+	var schemas []temporarySchema
+	for _, row := range rows {
+		scName := string(tree.MustBeDString(row[2]))
+		if !sessiondata.IsTemporarySchemaName(scName) || !pred(scName) {
+			continue
+		}
+		schemas = append(schemas, temporarySchema{
+			dbID:   sqlbase.ID(tree.MustBeDInt(row[0])),
+			dbName: string(tree.MustBeDString(row[1])),
+			name:   scName,
+			id:     sqlbase.ID(tree.MustBeDInt(row[3])),
+		})
+	}
+	return schemas, nil
+}
+
+// getTemporaryTableNames returns the names of the tables in the given
+// temporary schema.
+func getTemporaryTableNames(
+	ctx context.Context, ie *InternalExecutor, txn *client.Txn, sc temporarySchema,
+) (tree.TableNames, error) {
+	rows, _, err := ie.Query(
+		ctx, "get-temp-tables", txn,
+		`SELECT name FROM system.namespace WHERE "parentID" = $1`, sc.id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tableNames := make(tree.TableNames, len(rows))
+	for i, row := range rows {
+		tableNames[i] = tree.MakeTableNameWithSchema(
+			tree.Name(sc.dbName), tree.Name(sc.name), tree.Name(tree.MustBeDString(row[0])),
+		)
+	}
+	return tableNames, nil
+}
+
+// cleanupTemporarySchemas drops all the tables of the temporary schemas that
+// satisfy the given predicate, and then the schemas themselves.
+func cleanupTemporarySchemas(
+	ctx context.Context, db *client.DB, ie *InternalExecutor, pred func(scName string) bool,
+) error {
+	schemas, err := getTemporarySchemas(ctx, ie, nil /* txn */, pred)
+	if err != nil {
+		return err
+	}
+	for _, sc := range schemas {
+		tableNames, err := getTemporaryTableNames(ctx, ie, nil /* txn */, sc)
+		if err != nil {
+			return err
+		}
+		if len(tableNames) > 0 {
+			drop := &tree.DropTable{Names: tableNames, IfExists: true, DropBehavior: tree.DropCascade}
+			if _, err := ie.Exec(ctx, "drop-temp-tables", nil /* txn */, drop.String()); err != nil {
+				return err
+			}
+		}
+		log.VEventf(ctx, 2, "removing temporary schema %s from database %s", sc.name, sc.dbName)
+		if err := db.Del(ctx, sqlbase.MakeNameMetadataKey(sc.dbID, sc.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanupSessionTemporarySchemas drops the temporary tables of the session
+// with the given ID. It is called when the session ends.
+func cleanupSessionTemporarySchemas(
+	ctx context.Context, db *client.DB, ie *InternalExecutor, sessionID ClusterWideID,
+) error {
+	scName := temporarySchemaName(sessionID)
+	return cleanupTemporarySchemas(ctx, db, ie, func(name string) bool {
+		return name == scName
+	})
+}
+
+// TemporaryObjectCleaner periodically drops the temporary schemas of sessions
+// that did not get to clean up after themselves.
+//
+// Every node takes care of the schemas created by its own sessions that are
+// no longer running, and of the schemas created on nodes that are not live.
+type TemporaryObjectCleaner struct {
+	settings        *cluster.Settings
+	db              *client.DB
+	ie              *InternalExecutor
+	sessionRegistry *SessionRegistry
+	nodeID          *base.NodeIDContainer
+	isLive          func(roachpb.NodeID) (bool, error)
+}
+
+// NewTemporaryObjectCleaner creates a TemporaryObjectCleaner. isLive reports
+// whether a node is considered live; usually it is NodeLiveness.IsLive.
+func NewTemporaryObjectCleaner(
+	settings *cluster.Settings,
+	db *client.DB,
+	ie *InternalExecutor,
+	sessionRegistry *SessionRegistry,
+	nodeID *base.NodeIDContainer,
+	isLive func(roachpb.NodeID) (bool, error),
+) *TemporaryObjectCleaner {
+	return &TemporaryObjectCleaner{
+		settings:        settings,
+		db:              db,
+		ie:              ie,
+		sessionRegistry: sessionRegistry,
+		nodeID:          nodeID,
+		isLive:          isLive,
+	}
+}
+
+// Start runs the cleaner until the stopper quiesces.
+func (c *TemporaryObjectCleaner) Start(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		timer := timeutil.NewTimer()
+		defer timer.Stop()
+		timer.Reset(TempObjectCleanupInterval.Get(&c.settings.SV))
+		for {
+			select {
+			case <-stopper.ShouldQuiesce():
+				return
+
+			case <-timer.C:
+				timer.Read = true
+				timer.Reset(TempObjectCleanupInterval.Get(&c.settings.SV))
+
+				if err := cleanupTemporarySchemas(ctx, c.db, c.ie, c.isOrphaned); err != nil {
+					log.Warningf(ctx, "error cleaning up temporary schemas: %s", err)
+				}
+			}
+		}
+	})
+}
+
+// isOrphaned returns true if the session that created the given temporary
+// schema is known to be gone.
+func (c *TemporaryObjectCleaner) isOrphaned(scName string) bool {
+	sessionID, err := temporarySchemaSessionID(scName)
+	if err != nil {
+		return false
+	}
+	// The low bits of a session ID hold the ID of the node the session was
+	// started on (see GenerateClusterWideID).
+	nodeID := roachpb.NodeID(uint32(sessionID.Lo))
+	if nodeID == c.nodeID.Get() {
+		return !c.sessionRegistry.hasSession(sessionID)
+	}
+	live, err := c.isLive(nodeID)
+	if err != nil {
+		// The node is unknown to liveness; leave its sessions alone.
+		return false
+	}
+	return !live
+}
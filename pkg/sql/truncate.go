@@ -267,7 +267,7 @@ func (p *planner) truncateTable(
 	newTableDesc.Mutations = nil
 	newTableDesc.GCMutations = nil
 	newTableDesc.ModificationTime = p.txn.CommitTimestamp()
-	tKey := tableKey{parentID: newTableDesc.ParentID, name: newTableDesc.Name}
+	tKey := tableKey{parentID: newTableDesc.GetNamespaceParentID(), name: newTableDesc.Name}
 	key := tKey.Key()
 	if err := p.createDescriptorWithID(
 		ctx, key, newID, newTableDesc, p.ExtendedEvalContext().Settings); err != nil {
@@ -279,6 +279,14 @@ func (p *planner) truncateTable(
 		return err
 	}
 
+	// The row-level TTL job of the old table stops once the table is dropped;
+	// start one for the new table.
+	if newTableDesc.RowLevelTTL != nil {
+		if err := p.createRowLevelTTLJob(ctx, newTableDesc); err != nil {
+			return err
+		}
+	}
+
 	// Copy the zone config.
 	b = &client.Batch{}
 	b.Get(zoneKey)
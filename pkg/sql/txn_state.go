@@ -106,8 +106,76 @@ type txnState struct {
 	// activeSavepointName stores the name of the active savepoint,
 	// or is empty if no savepoint is active.
 	activeSavepointName tree.Name
+
+	// savepoints is the stack of the regular (i.e. not cockroach_restart)
+	// savepoints of the transaction, innermost last. While it is not empty, an
+	// error does not roll back the KV txn, since the error can be rolled back
+	// to one of the savepoints.
+	savepoints []sqlSavepoint
+
+	// numDDL counts the schema changes performed by the transaction. Rolling
+	// back to a savepoint is not supported if schema changes were performed
+	// after the savepoint was created.
+	numDDL int
+
+	// deferredConstraints tracks the foreign key and deferrable UNIQUE checks
+	// deferred until the end of the statement or the transaction. It has its
+	// own mutex because statements executed in parallel can queue checks
+	// concurrently.
+	deferredConstraints struct {
+		syncutil.Mutex
+
+		// enabled is set for explicit transactions owned by this session. Other
+		// transactions check constraints immediately.
+		enabled bool
+		// allMode is the mode set by SET CONSTRAINTS ALL.
+		allMode constraintMode
+		// modes holds the modes set for individual constraints by name.
+		modes map[string]constraintMode
+		// checks are the queued checks, run at COMMIT.
+		checks []tree.DeferredConstraintCheck
+		// stmtChecks are the checks queued by the current statement for
+		// constraints that are not deferred, run when the statement completes.
+		stmtChecks []tree.DeferredConstraintCheck
+	}
+}
+
+// sqlSavepoint is a savepoint created with SAVEPOINT.
+type sqlSavepoint struct {
+	name tree.Name
+	// kvToken is used to roll back the KV txn to the savepoint.
+	kvToken client.SavepointToken
+	// numDDL is the txnState's numDDL at the time the savepoint was created.
+	numDDL int
+	// deferredChecks are the deferred constraint checks that were queued at
+	// the time the savepoint was created. They are restored when rolling back
+	// to the savepoint.
+	deferredChecks []tree.DeferredConstraintCheck
+}
+
+// findSavepoint returns the index of the innermost savepoint with the given
+// name, or -1 if there is no such savepoint.
+func (ts *txnState) findSavepoint(name tree.Name) int {
+	for i := len(ts.savepoints) - 1; i >= 0; i-- {
+		if ts.savepoints[i].name == name {
+			return i
+		}
+	}
+	return -1
 }
 
+// constraintMode is the checking mode of a deferrable constraint in a
+// transaction, as set by SET CONSTRAINTS.
+type constraintMode int
+
+const (
+	// constraintModeDefault means the constraint is checked according to
+	// whether it was declared INITIALLY DEFERRED.
+	constraintModeDefault constraintMode = iota
+	constraintModeImmediate
+	constraintModeDeferred
+)
+
 // txnType represents the type of a SQL transaction.
 type txnType int
 
@@ -214,6 +282,17 @@ func (ts *txnState) resetForNewSQLTxn(
 
 	// Discard the old schemaChangers, if any.
 	ts.schemaChangers = schemaChangerCollection{}
+
+	ts.savepoints = nil
+	ts.numDDL = 0
+
+	ts.deferredConstraints.Lock()
+	ts.deferredConstraints.enabled = txnType == explicitTxn && txn == nil
+	ts.deferredConstraints.allMode = constraintModeDefault
+	ts.deferredConstraints.modes = nil
+	ts.deferredConstraints.checks = nil
+	ts.deferredConstraints.stmtChecks = nil
+	ts.deferredConstraints.Unlock()
 }
 
 // finishSQLTxn finalizes a transaction's results and closes the root span for
@@ -272,6 +351,173 @@ func (ts *txnState) finishExternalTxn() {
 	ts.mu.txn = nil
 }
 
+// setConstraintsMode implements SET CONSTRAINTS. An empty names list stands
+// for ALL. When constraints become immediate, the checks already queued for
+// them are returned so that the caller can run them right away.
+func (ts *txnState) setConstraintsMode(
+	names []string, deferred bool,
+) []tree.DeferredConstraintCheck {
+	mode := constraintModeImmediate
+	if deferred {
+		mode = constraintModeDeferred
+	}
+	dc := &ts.deferredConstraints
+	dc.Lock()
+	defer dc.Unlock()
+	if len(names) == 0 {
+		dc.allMode = mode
+		dc.modes = nil
+	} else {
+		if dc.modes == nil {
+			dc.modes = make(map[string]constraintMode, len(names))
+		}
+		for _, name := range names {
+			dc.modes[name] = mode
+		}
+	}
+	if deferred {
+		return nil
+	}
+	var toRun []tree.DeferredConstraintCheck
+	remaining := dc.checks[:0]
+	for _, check := range dc.checks {
+		if len(names) == 0 || dc.modes[check.Name] == constraintModeImmediate {
+			toRun = append(toRun, check)
+		} else {
+			remaining = append(remaining, check)
+		}
+	}
+	dc.checks = remaining
+	return toRun
+}
+
+// IsConstraintDeferred is part of the tree.ConstraintDeferrer interface.
+func (ts *txnState) IsConstraintDeferred(name string, deferrable, initiallyDeferred bool) bool {
+	if !deferrable {
+		return false
+	}
+	dc := &ts.deferredConstraints
+	dc.Lock()
+	defer dc.Unlock()
+	if !dc.enabled {
+		return false
+	}
+	mode := dc.modes[name]
+	if mode == constraintModeDefault {
+		mode = dc.allMode
+	}
+	if mode == constraintModeDefault {
+		return initiallyDeferred
+	}
+	return mode == constraintModeDeferred
+}
+
+// DeferConstraintCheck is part of the tree.ConstraintDeferrer interface.
+func (ts *txnState) DeferConstraintCheck(check tree.DeferredConstraintCheck) {
+	ts.deferredConstraints.Lock()
+	defer ts.deferredConstraints.Unlock()
+	ts.deferredConstraints.checks = append(ts.deferredConstraints.checks, check)
+}
+
+// QueueStatementConstraintCheck is part of the tree.ConstraintDeferrer
+// interface.
+func (ts *txnState) QueueStatementConstraintCheck(check tree.DeferredConstraintCheck) {
+	ts.deferredConstraints.Lock()
+	defer ts.deferredConstraints.Unlock()
+	ts.deferredConstraints.stmtChecks = append(ts.deferredConstraints.stmtChecks, check)
+}
+
+// HasPendingConstraintChecks is part of the tree.ConstraintDeferrer interface.
+func (ts *txnState) HasPendingConstraintChecks() bool {
+	ts.deferredConstraints.Lock()
+	defer ts.deferredConstraints.Unlock()
+	return len(ts.deferredConstraints.checks)+len(ts.deferredConstraints.stmtChecks) > 0
+}
+
+// takeStatementConstraintChecks returns the checks queued by the current
+// statement and clears the queue.
+func (ts *txnState) takeStatementConstraintChecks() []tree.DeferredConstraintCheck {
+	ts.deferredConstraints.Lock()
+	defer ts.deferredConstraints.Unlock()
+	checks := ts.deferredConstraints.stmtChecks
+	ts.deferredConstraints.stmtChecks = nil
+	return checks
+}
+
+// takeDeferredConstraintChecks returns the queued constraint checks and
+// clears the queue.
+func (ts *txnState) takeDeferredConstraintChecks() []tree.DeferredConstraintCheck {
+	ts.deferredConstraints.Lock()
+	defer ts.deferredConstraints.Unlock()
+	checks := ts.deferredConstraints.checks
+	ts.deferredConstraints.checks = nil
+	return checks
+}
+
+// copyDeferredConstraintChecks returns a copy of the queued constraint checks.
+func (ts *txnState) copyDeferredConstraintChecks() []tree.DeferredConstraintCheck {
+	ts.deferredConstraints.Lock()
+	defer ts.deferredConstraints.Unlock()
+	return append([]tree.DeferredConstraintCheck(nil), ts.deferredConstraints.checks...)
+}
+
+// resetDeferredConstraintChecks replaces the queued constraint checks. The
+// checks queued by the current statement are dropped.
+func (ts *txnState) resetDeferredConstraintChecks(checks []tree.DeferredConstraintCheck) {
+	ts.deferredConstraints.Lock()
+	defer ts.deferredConstraints.Unlock()
+	ts.deferredConstraints.checks = append([]tree.DeferredConstraintCheck(nil), checks...)
+	ts.deferredConstraints.stmtChecks = nil
+}
+
+// runDeferredConstraintChecks runs the given constraint checks in txn and
+// returns the error of the first check that fails.
+func runDeferredConstraintChecks(
+	ctx context.Context, txn *client.Txn, checks []tree.DeferredConstraintCheck,
+) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	var ba roachpb.BatchRequest
+	for _, check := range checks {
+		spans := []roachpb.Span{check.ReferencingSpan, check.ReferencedSpan}
+		if check.UniqueSpan.Key != nil {
+			spans = []roachpb.Span{check.UniqueSpan}
+		}
+		for _, span := range spans {
+			ba.Add(&roachpb.ScanRequest{
+				RequestHeader: roachpb.RequestHeaderFromSpan(span),
+			})
+		}
+	}
+	br, pErr := txn.Send(ctx, ba)
+	if pErr != nil {
+		return pErr.GoError()
+	}
+	resp := 0
+	scan := func() []roachpb.KeyValue {
+		rows := br.Responses[resp].GetInner().(*roachpb.ScanResponse).Rows
+		resp++
+		return rows
+	}
+	for _, check := range checks {
+		if check.UniqueSpan.Key != nil {
+			// A deferrable UNIQUE index is encoded like a non-unique index, so
+			// each row sharing the unique values has its own index entry.
+			if len(scan()) > 1 {
+				return check.Err
+			}
+			continue
+		}
+		referencing := scan()
+		referenced := scan()
+		if len(referencing) > 0 && len(referenced) == 0 {
+			return check.Err
+		}
+	}
+	return nil
+}
+
 func (ts *txnState) setPriority(userPriority roachpb.UserPriority) error {
 	if err := ts.mu.txn.SetUserPriority(userPriority); err != nil {
 		return err
@@ -0,0 +1,447 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// maxFunctionNesting is the maximum depth of nested calls to user-defined
+// functions. It guards against functions that call themselves, directly or
+// indirectly.
+const maxFunctionNesting = 32
+
+// functionNestingKey is the context key under which the depth of nested
+// calls to user-defined functions is stored.
+type functionNestingKey struct{}
+
+// ResolveFunction implements the tree.FunctionResolver interface.
+// User-defined functions are looked up in the current database.
+func (p *planner) ResolveFunction(name *tree.UnresolvedName) (*tree.FunctionDefinition, error) {
+	if name.NumParts != 1 || p.CurrentDatabase() == "" {
+		return nil, nil
+	}
+	ctx := p.EvalContext().Context
+	dbDesc, err := p.ResolveUncachedDatabaseByName(ctx, p.CurrentDatabase(), false /*required*/)
+	if err != nil || dbDesc == nil {
+		return nil, err
+	}
+	fnDescs, err := getFunctionDescs(ctx, p.txn, dbDesc, name.Parts[0])
+	if err != nil || len(fnDescs) == 0 {
+		return nil, err
+	}
+	return makeFunctionDefinition(fnDescs, p.SessionData().SearchPath)
+}
+
+// getFunctionDescs looks up the descriptors of the overloads of the
+// user-defined function with the given name in the given database.
+func getFunctionDescs(
+	ctx context.Context, txn *client.Txn, dbDesc *DatabaseDescriptor, name string,
+) ([]*sqlbase.FunctionDescriptor, error) {
+	ids := dbDesc.FindFunctions(name)
+	fnDescs := make([]*sqlbase.FunctionDescriptor, len(ids))
+	for i, id := range ids {
+		fnDescs[i] = &sqlbase.FunctionDescriptor{}
+		if err := getDescriptorByID(ctx, txn, id, fnDescs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return fnDescs, nil
+}
+
+// makeFunctionDefinition builds the definition of a user-defined function
+// from the descriptors of its overloads.
+func makeFunctionDefinition(
+	fnDescs []*sqlbase.FunctionDescriptor, searchPath sessiondata.SearchPath,
+) (*tree.FunctionDefinition, error) {
+	name := fnDescs[0].Name
+	props := &tree.FunctionProperties{
+		// SQL functions are not declared STRICT, so they see NULL arguments.
+		NullableArgs: true,
+		// The body of the function can read the database.
+		Impure: true,
+		// The body of the function is run by the internal executor of the
+		// session, which is not available on remote nodes.
+		DistsqlBlacklist: true,
+		Category:         "User-defined",
+	}
+	if fnDescs[0].ReturnsSet {
+		props.Class = tree.GeneratorClass
+		props.ReturnLabels = []string{name}
+	}
+	overloads := make([]tree.Overload, len(fnDescs))
+	for i, fnDesc := range fnDescs {
+		ov, err := makeFunctionOverload(fnDesc, searchPath)
+		if err != nil {
+			return nil, err
+		}
+		overloads[i] = ov
+	}
+	return tree.NewFunctionDefinition(name, props, overloads), nil
+}
+
+// makeFunctionOverload builds the overload corresponding to the given
+// function descriptor.
+func makeFunctionOverload(
+	fnDesc *sqlbase.FunctionDescriptor, searchPath sessiondata.SearchPath,
+) (tree.Overload, error) {
+	argTypes := fnDesc.ArgDatumTypes()
+	params := make(tree.ArgTypes, len(argTypes))
+	for i, typ := range argTypes {
+		params[i].Name = fnDesc.ArgNames[i]
+		params[i].Typ = typ
+	}
+	retType := fnDesc.ReturnType.ToDatumType()
+	ov := tree.Overload{
+		Types:      params,
+		ReturnType: tree.FixedReturnType(retType),
+		Info:       fnDesc.Body,
+	}
+
+	if fnDesc.ReturnsSet {
+		ov.Generator = func(evalCtx *tree.EvalContext, args tree.Datums) (tree.ValueGenerator, error) {
+			return &functionValueGenerator{evalCtx: evalCtx, fnDesc: fnDesc, typ: retType, args: args}, nil
+		}
+		ov.Fn = func(*tree.EvalContext, tree.Datums) (tree.Datum, error) {
+			return nil, pgerror.NewAssertionErrorf("generator functions cannot be evaluated as scalars")
+		}
+		return ov, nil
+	}
+
+	ov.Fn = func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+		rows, err := runFunctionBody(evalCtx, fnDesc, args)
+		if err != nil {
+			return nil, err
+		}
+		// As in PostgreSQL, the result is the first row produced by the body,
+		// or NULL if there is none.
+		if len(rows) == 0 {
+			return tree.DNull, nil
+		}
+		return rows[0][0], nil
+	}
+
+	// Bodies that compute a single scalar expression are inlined by the
+	// optimizer. The arguments become placeholders, which are substituted
+	// by the arguments of each call. The placeholders are annotated with
+	// the types of the arguments so that calls are type checked as if they
+	// were not inlined.
+	body, err := parseFunctionBody(fnDesc, func(i int) (tree.Expr, error) {
+		colType, err := coltypes.DatumTypeToColumnType(argTypes[i])
+		if err != nil {
+			return nil, err
+		}
+		return &tree.AnnotateTypeExpr{Expr: tree.NewPlaceholder(strconv.Itoa(i + 1)), Type: colType}, nil
+	})
+	if err != nil {
+		return tree.Overload{}, err
+	}
+	if expr := inlinableFunctionExpr(body, searchPath); expr != nil {
+		retColType, err := coltypes.DatumTypeToColumnType(retType)
+		if err != nil {
+			return tree.Overload{}, err
+		}
+		ov.InlineBody = &tree.CastExpr{Expr: expr, Type: retColType}
+	}
+	return ov, nil
+}
+
+// runFunctionBody runs the body of a user-defined function on the given
+// arguments and returns the rows it produces.
+func runFunctionBody(
+	evalCtx *tree.EvalContext, fnDesc *sqlbase.FunctionDescriptor, args tree.Datums,
+) ([]tree.Datums, error) {
+	ie, ok := evalCtx.InternalExecutor.(*SessionBoundInternalExecutor)
+	if !ok {
+		return nil, pgerror.NewAssertionErrorf(
+			"function %s cannot be run without a session", fnDesc.Name)
+	}
+
+	ctx := evalCtx.Ctx()
+	depth, _ := ctx.Value(functionNestingKey{}).(int)
+	if depth >= maxFunctionNesting {
+		return nil, pgerror.NewErrorf(pgerror.CodeStatementTooComplexError,
+			"function %s exceeds the maximum nesting depth of %d", fnDesc.Name, maxFunctionNesting)
+	}
+	ctx = context.WithValue(ctx, functionNestingKey{}, depth+1)
+
+	body, err := parseFunctionBody(fnDesc, func(i int) (tree.Expr, error) {
+		return makeFunctionArgCast(args[i], fnDesc.ArgTypes[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+	rows, _ /* cols */, err := ie.Query(
+		ctx, "function-"+fnDesc.Name, evalCtx.Txn, tree.AsStringWithFlags(body, tree.FmtParsable))
+	return rows, err
+}
+
+// makeFunctionArgCast casts expr, which is the value of an argument of a
+// user-defined function, to the type of the argument.
+func makeFunctionArgCast(expr tree.Expr, typ sqlbase.ColumnType) (tree.Expr, error) {
+	colType, err := coltypes.DatumTypeToColumnType(typ.ToDatumType())
+	if err != nil {
+		return nil, err
+	}
+	return &tree.CastExpr{Expr: expr, Type: colType}, nil
+}
+
+// parseFunctionBody parses the body of a user-defined function. The body
+// can refer to the arguments of the function by name or by position ($1,
+// $2, etc.); every such reference is replaced by the expression that argFn
+// returns for the (zero-based) index of the argument. An argument that has
+// the same name as a column hides the column, which must then be qualified.
+func parseFunctionBody(
+	fnDesc *sqlbase.FunctionDescriptor, argFn func(i int) (tree.Expr, error),
+) (*tree.Select, error) {
+	stmt, err := parser.ParseOne(fnDesc.Body)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.(*tree.Select)
+	if !ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"the body of function %s must be a SELECT statement, found %s",
+			fnDesc.Name, stmt.StatementTag())
+	}
+	w := functionBodyWalker{fn: func(expr tree.Expr) (err error, recurse bool, newExpr tree.Expr) {
+		switch t := expr.(type) {
+		case *tree.UnresolvedName:
+			if t.NumParts != 1 || t.Star {
+				break
+			}
+			for i, name := range fnDesc.ArgNames {
+				if name != "" && name == t.Parts[0] {
+					newExpr, err := argFn(i)
+					return err, false, newExpr
+				}
+			}
+
+		case *tree.Placeholder:
+			i, err := strconv.Atoi(t.Name)
+			if err != nil || i < 1 || i > len(fnDesc.ArgTypes) {
+				return pgerror.NewErrorf(pgerror.CodeUndefinedParameterError,
+					"there is no parameter $%s", t.Name), false, expr
+			}
+			newExpr, err := argFn(i - 1)
+			return err, false, newExpr
+		}
+		return nil, true, expr
+	}}
+	if err := w.walkSelect(sel); err != nil {
+		return nil, err
+	}
+	return sel, nil
+}
+
+// functionBodyWalker applies a function to the expressions in the body of a
+// user-defined function. The body is modified in place.
+//
+// tree.SimpleVisit does not walk the FROM clauses of SELECT statements, so
+// the walker walks the statements itself and only uses tree.SimpleVisit for
+// the expressions they contain.
+type functionBodyWalker struct {
+	fn  tree.SimpleVisitFn
+	err error
+}
+
+func (w *functionBodyWalker) walkSelect(sel *tree.Select) error {
+	if sel.With != nil {
+		for _, cte := range sel.With.CTEList {
+			if s, ok := cte.Stmt.(*tree.Select); ok {
+				w.walkSelect(s)
+			}
+		}
+	}
+	w.walkSelectStatement(sel.Select)
+	for _, o := range sel.OrderBy {
+		o.Expr = w.walkExpr(o.Expr)
+	}
+	if sel.Limit != nil {
+		sel.Limit.Offset = w.walkExpr(sel.Limit.Offset)
+		sel.Limit.Count = w.walkExpr(sel.Limit.Count)
+	}
+	return w.err
+}
+
+func (w *functionBodyWalker) walkSelectStatement(stmt tree.SelectStatement) {
+	switch t := stmt.(type) {
+	case *tree.ParenSelect:
+		w.walkSelect(t.Select)
+	case *tree.UnionClause:
+		w.walkSelect(t.Left)
+		w.walkSelect(t.Right)
+	case *tree.ValuesClause:
+		for _, row := range t.Rows {
+			w.walkExprs(row)
+		}
+	case *tree.SelectClause:
+		for i := range t.Exprs {
+			t.Exprs[i].Expr = w.walkExpr(t.Exprs[i].Expr)
+		}
+		if t.From != nil {
+			for _, table := range t.From.Tables {
+				w.walkTableExpr(table)
+			}
+		}
+		if t.Where != nil {
+			t.Where.Expr = w.walkExpr(t.Where.Expr)
+		}
+		w.walkExprs(t.GroupBy)
+		if t.Having != nil {
+			t.Having.Expr = w.walkExpr(t.Having.Expr)
+		}
+	}
+}
+
+func (w *functionBodyWalker) walkTableExpr(table tree.TableExpr) {
+	switch t := table.(type) {
+	case *tree.AliasedTableExpr:
+		w.walkTableExpr(t.Expr)
+	case *tree.ParenTableExpr:
+		w.walkTableExpr(t.Expr)
+	case *tree.JoinTableExpr:
+		w.walkTableExpr(t.Left)
+		w.walkTableExpr(t.Right)
+		if cond, ok := t.Cond.(*tree.OnJoinCond); ok {
+			cond.Expr = w.walkExpr(cond.Expr)
+		}
+	case *tree.RowsFromExpr:
+		w.walkExprs(t.Items)
+	case *tree.Subquery:
+		w.walkSelectStatement(t.Select)
+	}
+}
+
+func (w *functionBodyWalker) walkExprs(exprs []tree.Expr) {
+	for i := range exprs {
+		exprs[i] = w.walkExpr(exprs[i])
+	}
+}
+
+func (w *functionBodyWalker) walkExpr(expr tree.Expr) tree.Expr {
+	if expr == nil || w.err != nil {
+		return expr
+	}
+	newExpr, err := tree.SimpleVisit(expr, func(expr tree.Expr) (err error, recurse bool, newExpr tree.Expr) {
+		if sub, ok := expr.(*tree.Subquery); ok {
+			w.walkSelectStatement(sub.Select)
+			return w.err, false, expr
+		}
+		return w.fn(expr)
+	})
+	if err != nil {
+		w.err = err
+		return expr
+	}
+	return newExpr
+}
+
+// inlinableFunctionExpr returns the expression computed by the given
+// function body if the body is a plain SELECT of a single scalar
+// expression, and nil otherwise.
+func inlinableFunctionExpr(sel *tree.Select, searchPath sessiondata.SearchPath) tree.Expr {
+	if sel.With != nil || len(sel.OrderBy) > 0 || sel.Limit != nil || len(sel.Locking) > 0 {
+		return nil
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok || clause.Distinct || len(clause.DistinctOn) > 0 || len(clause.Exprs) != 1 ||
+		clause.Where != nil || len(clause.GroupBy) > 0 || clause.Having != nil ||
+		len(clause.Window) > 0 || clause.TableSelect {
+		return nil
+	}
+	if clause.From != nil && (len(clause.From.Tables) > 0 || clause.From.AsOf.Expr != nil) {
+		return nil
+	}
+
+	// Only calls to builtin scalar functions can be inlined. Aggregates and
+	// generators would apply to the calling query instead of the body.
+	inlinable := true
+	expr := clause.Exprs[0].Expr
+	_, _ = tree.SimpleVisit(expr, func(expr tree.Expr) (err error, recurse bool, newExpr tree.Expr) {
+		switch t := expr.(type) {
+		case *tree.Subquery:
+			inlinable = false
+		case *tree.FuncExpr:
+			if t.WindowDef != nil {
+				inlinable = false
+				break
+			}
+			def, err := t.Func.Resolve(searchPath)
+			if err != nil || def.Class != tree.NormalClass {
+				inlinable = false
+			}
+		}
+		return nil, inlinable, expr
+	})
+	if !inlinable {
+		return nil
+	}
+	return expr
+}
+
+// functionValueGenerator produces the rows of a set-returning user-defined
+// function.
+type functionValueGenerator struct {
+	evalCtx *tree.EvalContext
+	fnDesc  *sqlbase.FunctionDescriptor
+	typ     types.T
+	args    tree.Datums
+
+	rows   []tree.Datums
+	rowIdx int
+}
+
+var _ tree.ValueGenerator = &functionValueGenerator{}
+
+// ResolvedType implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) ResolvedType() types.T {
+	return g.typ
+}
+
+// Start implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Start() error {
+	rows, err := runFunctionBody(g.evalCtx, g.fnDesc, g.args)
+	if err != nil {
+		return err
+	}
+	g.rows = rows
+	g.rowIdx = -1
+	return nil
+}
+
+// Next implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Next() (bool, error) {
+	g.rowIdx++
+	return g.rowIdx < len(g.rows), nil
+}
+
+// Values implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Values() tree.Datums {
+	return g.rows[g.rowIdx]
+}
+
+// Close implements the tree.ValueGenerator interface.
+func (g *functionValueGenerator) Close() {}
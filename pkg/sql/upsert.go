@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
@@ -65,7 +66,13 @@ func (p *planner) newUpsertNode(
 	// Extract the index that will detect upsert conflicts
 	// (conflictIndex) and the assignment expressions to use when
 	// conflicts are detected (updateExprs).
-	autoGenUpdates, updateExprs, conflictIndex, err := upsertExprsAndIndex(desc, *n.OnConflict, ri.InsertCols)
+	impliedIndexes, err := p.impliedPartialIndexes(desc, tn, n.OnConflict.ArbiterPredicate)
+	if err != nil {
+		return nil, err
+	}
+	autoGenUpdates, updateExprs, conflictIndex, err := upsertExprsAndIndex(
+		desc, *n.OnConflict, impliedIndexes, ri.InsertCols,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -735,9 +742,14 @@ func (uh *upsertHelper) shouldUpdate(insertRow tree.Datums, existingRow tree.Dat
 // - updateExprs: the assignment expressions in ON CONFLICT DO UPDATE,
 //   or auto-generated assignments for UPSERT.
 // - conflictIdx: the conflicting index, if specified.
+//
+// impliedIndexes is the set of partial indexes whose predicate is implied by
+// the predicate of the ON CONFLICT clause, as computed by
+// impliedPartialIndexes.
 func upsertExprsAndIndex(
 	tableDesc *sqlbase.ImmutableTableDescriptor,
 	onConflict tree.OnConflict,
+	impliedIndexes map[sqlbase.IndexID]struct{},
 	insertCols []sqlbase.ColumnDescriptor,
 ) (
 	autoGeneratedAssignments bool,
@@ -779,6 +791,22 @@ func upsertExprsAndIndex(
 		return true, updateExprs, conflictIndex, nil
 	}
 
+	if onConflict.Constraint != "" {
+		// ON CONFLICT ON CONSTRAINT: the unique constraint is designated by
+		// name. A partial unique index is not a constraint.
+		if tableDesc.PrimaryIndex.Name == string(onConflict.Constraint) {
+			return false, onConflict.Exprs, &tableDesc.PrimaryIndex, nil
+		}
+		for i := range tableDesc.Indexes {
+			index := &tableDesc.Indexes[i]
+			if index.Name == string(onConflict.Constraint) && index.Unique && !index.IsPartial() {
+				return false, onConflict.Exprs, index, nil
+			}
+		}
+		return false, nil, nil, pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+			"constraint %q for table %q does not exist", onConflict.Constraint, tableDesc.Name)
+	}
+
 	if onConflict.DoNothing && len(onConflict.Columns) == 0 {
 		return false, onConflict.Exprs, nil, nil
 	}
@@ -789,6 +817,12 @@ func upsertExprsAndIndex(
 		if !index.Unique {
 			return false
 		}
+		// A partial unique index only guarantees uniqueness among the rows
+		// satisfying its predicate, so it can only arbitrate conflicts if the
+		// ON CONFLICT clause has a predicate which implies it.
+		if _, ok := impliedIndexes[index.ID]; index.IsPartial() && !ok {
+			return false
+		}
 		if len(index.ColumnNames) != len(onConflict.Columns) {
 			return false
 		}
@@ -800,13 +834,99 @@ func upsertExprsAndIndex(
 		return true
 	}
 
+	// Unique indexes that are not partial are preferred, as they apply to
+	// every row.
+	var partialMatch *sqlbase.IndexDescriptor
 	if indexMatch(tableDesc.PrimaryIndex) {
 		return false, onConflict.Exprs, &tableDesc.PrimaryIndex, nil
 	}
 	for i := range tableDesc.Indexes {
-		if indexMatch(tableDesc.Indexes[i]) {
-			return false, onConflict.Exprs, &tableDesc.Indexes[i], nil
+		index := &tableDesc.Indexes[i]
+		if indexMatch(*index) {
+			if !index.IsPartial() {
+				return false, onConflict.Exprs, index, nil
+			}
+			if partialMatch == nil {
+				partialMatch = index
+			}
 		}
 	}
+	if partialMatch != nil {
+		return false, onConflict.Exprs, partialMatch, nil
+	}
 	return false, nil, nil, fmt.Errorf("there is no unique or exclusion constraint matching the ON CONFLICT specification")
 }
+
+// impliedPartialIndexes returns the set of partial indexes of the table whose
+// predicate is implied by arbiterPred, the predicate of an ON CONFLICT clause.
+//
+// Only a simple form of implication is detected: after normalization, every
+// conjunct of the index predicate must also be a conjunct of arbiterPred. For
+// example, `WHERE NOT deleted AND a > 0` implies the predicate `NOT deleted`,
+// but `WHERE a > 1` does not imply the predicate `a > 0`.
+func (p *planner) impliedPartialIndexes(
+	desc *sqlbase.ImmutableTableDescriptor, tn *tree.TableName, arbiterPred tree.Expr,
+) (map[sqlbase.IndexID]struct{}, error) {
+	if arbiterPred == nil {
+		return nil, nil
+	}
+	evalCtx := p.EvalContext()
+	typedPred, err := sqlbase.MakeTablePredicateExpr(arbiterPred, *tn, desc, evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	arbiterConjuncts, err := normalizedConjuncts(evalCtx, typedPred)
+	if err != nil {
+		return nil, err
+	}
+
+	indexPreds, err := sqlbase.MakePartialIndexExprs(desc.Indexes, desc, evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	implied := make(map[sqlbase.IndexID]struct{})
+	for i, pred := range indexPreds {
+		if pred == nil {
+			continue
+		}
+		indexConjuncts, err := normalizedConjuncts(evalCtx, pred)
+		if err != nil {
+			return nil, err
+		}
+		isImplied := true
+		for c := range indexConjuncts {
+			if _, ok := arbiterConjuncts[c]; !ok {
+				isImplied = false
+				break
+			}
+		}
+		if isImplied {
+			implied[desc.Indexes[i].ID] = struct{}{}
+		}
+	}
+	return implied, nil
+}
+
+// normalizedConjuncts normalizes a predicate and returns the set of the
+// serialized forms of its top-level conjuncts.
+func normalizedConjuncts(evalCtx *tree.EvalContext, pred tree.TypedExpr) (map[string]struct{}, error) {
+	normalized, err := evalCtx.NormalizeExpr(pred)
+	if err != nil {
+		return nil, err
+	}
+	conjuncts := make(map[string]struct{})
+	var split func(expr tree.Expr)
+	split = func(expr tree.Expr) {
+		switch t := expr.(type) {
+		case *tree.AndExpr:
+			split(t.Left)
+			split(t.Right)
+		case *tree.ParenExpr:
+			split(t.Expr)
+		default:
+			conjuncts[tree.Serialize(expr)] = struct{}{}
+		}
+	}
+	split(normalized)
+	return conjuncts, nil
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
 
@@ -56,6 +57,9 @@ type cteSource struct {
 	// alias holds the name of the CTE and the renaming of its columns, if
 	// present.
 	alias tree.AliasClause
+	// recursive is set if this is the self-reference of a recursive CTE from
+	// within its own recursive term; the plan is then the work table.
+	recursive bool
 }
 
 func (e cteNameEnvironment) push(frame cteNameEnvironmentFrame) cteNameEnvironment {
@@ -114,7 +118,13 @@ func (p *planner) initWith(ctx context.Context, with *tree.With) (func(p *planne
 					"WITH query name %s specified more than once",
 					cte.Name.Alias)
 			}
-			ctePlan, err := p.newPlan(ctx, cte.Stmt, nil)
+			var ctePlan planNode
+			var err error
+			if with.Recursive {
+				ctePlan, err = p.newRecursiveCTEPlan(ctx, frame, cte)
+			} else {
+				ctePlan, err = p.newPlan(ctx, cte.Stmt, nil)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -142,6 +152,10 @@ func (p *planner) getCTEDataSource(tn *tree.TableName) (planDataSource, bool, er
 		frame := p.curPlan.cteNameEnvironment[i]
 		if cteSource, ok := frame[tn.TableName]; ok {
 			if cteSource.used {
+				if cteSource.recursive {
+					return planDataSource{}, false, pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+						"recursive reference to query %q must not appear more than once", tree.ErrString(tn))
+				}
 				// TODO(jordan): figure out how to lift this restriction.
 				// CTE expressions that are used more than once will need to be
 				// pre-evaluated like subqueries, I think.
@@ -167,3 +181,131 @@ func (p *planner) getCTEDataSource(tn *tree.TableName) (planDataSource, bool, er
 	}
 	return planDataSource{}, false, nil
 }
+
+// newRecursiveCTEPlan plans a common table expression defined in a WITH
+// RECURSIVE clause. A recursive CTE must have the form
+//
+//   <initial query> UNION [ALL] <recursive query>
+//
+// where only the recursive query refers to the CTE itself. If the CTE does
+// not have this form or never refers to itself, it is planned like a regular
+// CTE.
+//
+// The recursive query is planned once here to validate it; at execution time
+// it is re-planned for every iteration with the self-reference bound to the
+// rows produced by the previous iteration (see recursiveCTENode).
+func (p *planner) newRecursiveCTEPlan(
+	ctx context.Context, frame cteNameEnvironmentFrame, cte *tree.CTE,
+) (planNode, error) {
+	sel, ok := cte.Stmt.(*tree.Select)
+	if !ok || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return p.newPlan(ctx, cte.Stmt, nil)
+	}
+	union, ok := sel.Select.(*tree.UnionClause)
+	if !ok || union.Type != tree.UnionOp {
+		return p.newPlan(ctx, cte.Stmt, nil)
+	}
+	name := cte.Name.Alias
+
+	initial, err := p.newPlan(ctx, union.Left, nil)
+	if err != nil {
+		return nil, err
+	}
+	initialCols := planColumns(initial)
+	desiredTypes := make([]types.T, len(initialCols))
+	for i := range initialCols {
+		desiredTypes[i] = initialCols[i].Typ
+	}
+
+	// Plan the recursive query once, with the self-reference bound to an
+	// empty work table, and check whether the reference is actually used.
+	numSubqueries := len(p.curPlan.subqueryPlans)
+	numUsedCTEs := p.curPlan.cteNameEnvironment.numUsed()
+	frame[name] = cteSource{
+		plan:      &scanBufferNode{columns: initialCols, label: string(name)},
+		alias:     cte.Name,
+		recursive: true,
+	}
+	recursive, err := p.newPlan(ctx, union.Right, desiredTypes)
+	isRecursive := frame[name].used
+	delete(frame, name)
+	if err != nil {
+		initial.Close(ctx)
+		return nil, err
+	}
+	if !isRecursive {
+		return p.newUnionNode(union.Type, union.All, initial, recursive)
+	}
+	recursive.Close(ctx)
+
+	if len(p.curPlan.subqueryPlans) != numSubqueries {
+		initial.Close(ctx)
+		return nil, pgerror.UnimplementedWithIssueErrorf(21085,
+			"subqueries in the recursive term of recursive query %q are not supported", name)
+	}
+	if p.curPlan.cteNameEnvironment.numUsed() != numUsedCTEs {
+		initial.Close(ctx)
+		return nil, pgerror.UnimplementedWithIssueErrorf(21085,
+			"references to other common table expressions from the recursive term "+
+				"of recursive query %q are not supported", name)
+	}
+
+	recursiveCols := planColumns(recursive)
+	if len(initialCols) != len(recursiveCols) {
+		initial.Close(ctx)
+		return nil, pgerror.NewErrorf(
+			pgerror.CodeSyntaxError,
+			"each %v query must have the same number of columns: %d vs %d",
+			union.Type, len(initialCols), len(recursiveCols),
+		)
+	}
+	for i := range initialCols {
+		l, r := initialCols[i].Typ, recursiveCols[i].Typ
+		if !(l.Equivalent(r) || r == types.Unknown) {
+			initial.Close(ctx)
+			return nil, pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s overall",
+				name, i+1, l, r)
+		}
+	}
+
+	genIterationFn := func(ctx context.Context, workTable planNode) (planNode, error) {
+		savedEnv := p.curPlan.cteNameEnvironment
+		defer func() { p.curPlan.cteNameEnvironment = savedEnv }()
+		p.curPlan.cteNameEnvironment = cteNameEnvironment{cteNameEnvironmentFrame{
+			name: cteSource{plan: workTable, alias: cte.Name, recursive: true},
+		}}
+
+		plan, err := p.newPlan(ctx, union.Right, desiredTypes)
+		if err != nil {
+			return nil, err
+		}
+		plan, err = p.optimizePlan(ctx, plan, allColumns(plan))
+		if err != nil {
+			plan.Close(ctx)
+			return nil, err
+		}
+		return plan, nil
+	}
+
+	return &recursiveCTENode{
+		initial:        initial,
+		genIterationFn: genIterationFn,
+		label:          string(name),
+		deduplicate:    !union.All,
+	}, nil
+}
+
+// numUsed returns the number of CTEs in the environment that have been used
+// as a statement source.
+func (e cteNameEnvironment) numUsed() int {
+	n := 0
+	for _, frame := range e {
+		for _, src := range frame {
+			if src.used {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -196,6 +196,12 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		workFn:           createCommentTable,
 		newDescriptorIDs: staticIDs(keys.CommentsTableID),
 	},
+	{
+		// Introduced in v2.2.
+		name:             "create system.notifications table",
+		workFn:           createNotificationsTable,
+		newDescriptorIDs: staticIDs(keys.NotificationsTableID),
+	},
 }
 
 func staticIDs(ids ...sqlbase.ID) func(ctx context.Context, db db) ([]sqlbase.ID, error) {
@@ -513,6 +519,10 @@ func createCommentTable(ctx context.Context, r runner) error {
 	return createSystemTable(ctx, r, sqlbase.CommentsTable)
 }
 
+func createNotificationsTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.NotificationsTable)
+}
+
 var reportingOptOut = envutil.EnvOrDefaultBool("COCKROACH_SKIP_ENABLING_DIAGNOSTIC_REPORTING", false)
 
 func runStmtAsRootWithRetry(
@@ -731,6 +741,9 @@ func upgradeDescsWithFn(
 						}
 					}
 
+				case *sqlbase.Descriptor_Type, *sqlbase.Descriptor_Function, *sqlbase.Descriptor_Schema:
+					// Ignore.
+
 				default:
 					return errors.Errorf("Descriptor.Union has unexpected type %T", t)
 				}
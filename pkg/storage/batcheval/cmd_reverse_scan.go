@@ -41,6 +41,7 @@ func ReverseScan(
 	var err error
 	var intents []roachpb.Intent
 	var resumeSpan *roachpb.Span
+	locking := args.KeyLocking != roachpb.NON_LOCKING
 
 	switch args.ScanFormat {
 	case roachpb.BATCH_RESPONSE:
@@ -51,6 +52,8 @@ func ReverseScan(
 			engine.MVCCScanOptions{
 				Inconsistent: h.ReadConsistency != roachpb.CONSISTENT,
 				Txn:          h.Txn,
+				Locking:      locking,
+				SkipLocked:   locking && h.WaitPolicy == roachpb.WaitPolicy_SKIP,
 				Reverse:      true,
 			})
 		if err != nil {
@@ -58,12 +61,19 @@ func ReverseScan(
 		}
 		reply.NumKeys = numKvs
 		reply.BatchResponse = kvData
+		if locking {
+			if err := lockBatchResponseKeys(ctx, batch, cArgs, kvData); err != nil {
+				return result.Result{}, err
+			}
+		}
 	case roachpb.KEY_VALUES:
 		var rows []roachpb.KeyValue
 		rows, resumeSpan, intents, err = engine.MVCCScan(
 			ctx, batch, args.Key, args.EndKey, cArgs.MaxKeys, h.Timestamp, engine.MVCCScanOptions{
 				Inconsistent: h.ReadConsistency != roachpb.CONSISTENT,
 				Txn:          h.Txn,
+				Locking:      locking,
+				SkipLocked:   locking && h.WaitPolicy == roachpb.WaitPolicy_SKIP,
 				Reverse:      true,
 			})
 		if err != nil {
@@ -71,6 +81,11 @@ func ReverseScan(
 		}
 		reply.NumKeys = int64(len(rows))
 		reply.Rows = rows
+		if locking {
+			if err := lockRows(ctx, batch, cArgs, rows); err != nil {
+				return result.Result{}, err
+			}
+		}
 	default:
 		panic(fmt.Sprintf("Unknown scanFormat %d", args.ScanFormat))
 	}
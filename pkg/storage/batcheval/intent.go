@@ -19,6 +19,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
 )
 
 // CollectIntentRows collects the key-value pairs for each intent provided. It
@@ -53,3 +54,38 @@ func CollectIntentRows(
 	}
 	return res, nil
 }
+
+// lockRows locks the keys of the rows returned by a locking scan on behalf of
+// its transaction; see engine.MVCCLock.
+func lockRows(
+	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, rows []roachpb.KeyValue,
+) error {
+	h := cArgs.Header
+	for _, row := range rows {
+		if err := engine.MVCCLock(ctx, batch, cArgs.Stats, row.Key, h.Timestamp, h.Txn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockBatchResponseKeys is like lockRows, for the rows of a scan which were
+// returned in the BATCH_RESPONSE format.
+func lockBatchResponseKeys(
+	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, kvData []byte,
+) error {
+	h := cArgs.Header
+	for len(kvData) > 0 {
+		key, _, _, rest, err := enginepb.ScanDecodeKeyValue(kvData)
+		if err != nil {
+			return err
+		}
+		// The decoded key aliases kvData, which is returned to the client, so
+		// it isn't modified here.
+		if err := engine.MVCCLock(ctx, batch, cArgs.Stats, key, h.Timestamp, h.Txn); err != nil {
+			return err
+		}
+		kvData = rest
+	}
+	return nil
+}
@@ -19,6 +19,44 @@ func (t TxnMeta) Short() string {
 	return t.ID.Short()
 }
 
+// TxnSeqIsIgnored returns true iff the sequence number overlaps with any range
+// in the ignored list. The ranges are sorted and non-overlapping.
+func TxnSeqIsIgnored(seq int32, ignored []IgnoredSeqNumRange) bool {
+	// The ranges are usually few and are consulted for the latest writes, so
+	// they are scanned from the end.
+	for i := len(ignored) - 1; i >= 0; i-- {
+		if seq > ignored[i].End {
+			return false
+		}
+		if seq >= ignored[i].Start {
+			return true
+		}
+	}
+	return false
+}
+
+// AddIgnoredSeqNumRange returns a new ignored list made of the given list and
+// the given range. Since sequence numbers only increase, the new range starts
+// after or overlaps with the last range of the list; the ranges that it
+// overlaps with are merged into it. The given list is not modified.
+func AddIgnoredSeqNumRange(
+	ignored []IgnoredSeqNumRange, newRange IgnoredSeqNumRange,
+) []IgnoredSeqNumRange {
+	i := len(ignored)
+	for i > 0 && ignored[i-1].End+1 >= newRange.Start {
+		i--
+		if ignored[i].Start < newRange.Start {
+			newRange.Start = ignored[i].Start
+		}
+		if ignored[i].End > newRange.End {
+			newRange.End = ignored[i].End
+		}
+	}
+	res := make([]IgnoredSeqNumRange, i, i+1)
+	copy(res, ignored[:i])
+	return append(res, newRange)
+}
+
 // Total returns the range size as the sum of the key and value
 // bytes. This includes all non-live keys and all versioned values.
 func (ms MVCCStats) Total() int64 {
@@ -130,8 +168,26 @@ func (meta MVCCMetadata) IsInline() bool {
 	return meta.RawBytes != nil
 }
 
+// IsLockOnly returns true if the metadata describes an intent which only
+// locks the key, without changing its value.
+func (meta MVCCMetadata) IsLockOnly() bool {
+	return meta.LockOnly != nil && *meta.LockOnly
+}
+
 // AddToIntentHistory adds the sequence and value to the intent history.
 func (meta *MVCCMetadata) AddToIntentHistory(seq int32, val []byte) {
 	meta.IntentHistory = append(meta.IntentHistory,
 		MVCCMetadata_SequencedIntent{Sequence: seq, Value: val})
 }
+
+// GetLatestUnignoredIntent returns the index in the intent history of the
+// latest write whose sequence number is not ignored, or -1 if all the writes
+// in the history are ignored.
+func (meta *MVCCMetadata) GetLatestUnignoredIntent(ignored []IgnoredSeqNumRange) int {
+	for i := len(meta.IntentHistory) - 1; i >= 0; i-- {
+		if !TxnSeqIsIgnored(meta.IntentHistory[i].Sequence, ignored) {
+			return i
+		}
+	}
+	return -1
+}
@@ -879,6 +879,20 @@ func mvccGetInternal(
 					txn.Epoch, meta.Txn.Epoch)
 			}
 			seekKey = seekKey.Next()
+		} else if ownIntent && enginepb.TxnSeqIsIgnored(meta.Txn.Sequence, txn.IgnoredSeqNums) {
+			// The intent was written by our transaction, but its write was
+			// rolled back to a savepoint. Read the latest write in the intent
+			// history that was not rolled back or, if there is none, the value
+			// below the intent as if the intent didn't exist.
+			if i := meta.GetLatestUnignoredIntent(txn.IgnoredSeqNums); i >= 0 {
+				value := &buf.value
+				*value = roachpb.Value{RawBytes: meta.IntentHistory[i].Value, Timestamp: metaTimestamp}
+				if err := value.Verify(metaKey.Key); err != nil {
+					return nil, nil, safeValue, err
+				}
+				return value, ignoredIntents, allowedSafety, nil
+			}
+			seekKey = seekKey.Next()
 		}
 	} else if txn != nil && timestamp.Less(txn.MaxTimestamp) {
 		// In this branch, the latest timestamp is ahead, and so the read of an
@@ -1074,6 +1088,100 @@ func MVCCDelete(
 	return mvccPutUsingIter(ctx, engine, iter, ms, key, timestamp, noValue, txn, nil /* valueFn */)
 }
 
+// MVCCLock locks the specified key on behalf of a transaction, for a locking
+// read (SELECT ... FOR UPDATE). The key is locked by writing an intent whose
+// provisional value is a copy of the latest version of the key, and which is
+// marked as lock-only: like any other intent, it conflicts with the writes
+// and the locking reads of other transactions, but their non-locking reads
+// ignore it, and it is removed without creating a new version of the key when
+// the transaction commits (see MVCCResolveWriteIntent). Keys which don't
+// exist, or which hold an intent of the transaction already, are left as is.
+//
+// A WriteTooOldError is returned if the latest version of the key is not
+// older than the timestamp at which the key is locked.
+func MVCCLock(
+	ctx context.Context,
+	engine ReadWriter,
+	ms *enginepb.MVCCStats,
+	key roachpb.Key,
+	timestamp hlc.Timestamp,
+	txn *roachpb.Transaction,
+) error {
+	if len(key) == 0 {
+		return emptyKeyError()
+	}
+	if txn == nil {
+		return errors.Errorf("%q: locking reads are only allowed within transactions", key)
+	}
+	iter := engine.NewIterator(IterOptions{Prefix: true})
+	defer iter.Close()
+	buf := newPutBuffer()
+	defer buf.release()
+
+	metaKey := MakeMVCCMetadataKey(key)
+	ok, origMetaKeySize, origMetaValSize, err := mvccGetMetadata(iter, metaKey, &buf.meta)
+	if err != nil || !ok {
+		return err
+	}
+	meta := &buf.meta
+	if meta.IsInline() {
+		return errors.Errorf("%q: cannot lock an inline value", metaKey)
+	}
+	if meta.Txn != nil {
+		if meta.Txn.ID != txn.ID {
+			return &roachpb.WriteIntentError{Intents: []roachpb.Intent{{Span: roachpb.Span{Key: key}, Status: roachpb.PENDING, Txn: *meta.Txn}}}
+		}
+		// The key already holds an intent of the transaction, which locks it
+		// until the transaction finishes.
+		return nil
+	}
+	if meta.Deleted {
+		return nil
+	}
+	metaTimestamp := hlc.Timestamp(meta.Timestamp)
+	if !metaTimestamp.Less(timestamp) {
+		return &roachpb.WriteTooOldError{Timestamp: timestamp, ActualTimestamp: metaTimestamp.Next()}
+	}
+	// In the absence of an intent, mvccGetMetadata leaves the iterator
+	// positioned at the latest version of the key.
+	value := append([]byte(nil), iter.UnsafeValue()...)
+
+	txnMeta := txn.TxnMeta
+	// The ignored sequence numbers are not persisted with the intent; see
+	// mvccPutInternal.
+	txnMeta.IgnoredSeqNums = nil
+	lockOnly := true
+	buf.newMeta = enginepb.MVCCMetadata{
+		Txn:       &txnMeta,
+		Timestamp: hlc.LegacyTimestamp(timestamp),
+		KeyBytes:  mvccVersionTimestampSize,
+		ValBytes:  int64(len(value)),
+		LockOnly:  &lockOnly,
+	}
+	newMeta := &buf.newMeta
+	metaKeySize, metaValSize, err := buf.putMeta(engine, metaKey, newMeta)
+	if err != nil {
+		return err
+	}
+	if err := engine.Put(MVCCKey{Key: key, Timestamp: timestamp}, value); err != nil {
+		return err
+	}
+	if ms != nil {
+		ms.Add(updateStatsOnPut(key, 0 /* prevValSize */, origMetaKeySize, origMetaValSize,
+			metaKeySize, metaValSize, meta, newMeta))
+	}
+
+	logicalOpDetails := MVCCLogicalOpDetails{
+		Txn:       txnMeta,
+		Key:       key,
+		Timestamp: timestamp,
+		Safe:      true,
+	}
+	logicalOpDetails.Timestamp.Forward(txnMeta.Timestamp)
+	engine.LogLogicalOp(MVCCWriteIntentOpType, logicalOpDetails)
+	return nil
+}
+
 var noValue = roachpb.Value{}
 
 // mvccPutUsingIter sets the value for a specified key using the provided
@@ -1310,11 +1418,17 @@ func mvccPutInternal(
 			}
 			// Since an intent with a smaller sequence number exists for the
 			// same transaction, we must add the previous value and sequence
-			// to the intent history.
+			// to the intent history, unless its write was rolled back to a
+			// savepoint, or unless the intent only locked the key and its
+			// value is not a write of the transaction; in those cases, it is
+			// simply dropped.
 			//
 			// If the epoch of the transaction doesn't match the epoch of the
 			// intent, blow away the intent history.
-			if txn.Epoch == meta.Txn.Epoch {
+			if txn.Epoch != meta.Txn.Epoch {
+				buf.newMeta.IntentHistory = nil
+			} else if !meta.IsLockOnly() &&
+				!enginepb.TxnSeqIsIgnored(prevIntentSequence, txn.IgnoredSeqNums) {
 				// This case shouldn't pop up, but it is worth asserting
 				// that it doesn't. We shouldn't write invalid intents
 				// to the history
@@ -1324,8 +1438,6 @@ func mvccPutInternal(
 						metaKey, txn)
 				}
 				buf.newMeta.AddToIntentHistory(prevIntentSequence, prevIntentValBytes)
-			} else {
-				buf.newMeta.IntentHistory = nil
 			}
 		} else if !metaTimestamp.Less(timestamp) {
 			// This is the case where we're trying to write under a
@@ -1374,6 +1486,14 @@ func mvccPutInternal(
 		var txnMeta *enginepb.TxnMeta
 		if txn != nil {
 			txnMeta = &txn.TxnMeta
+			// The ignored sequence numbers are not persisted with the intent;
+			// they are always provided by the transaction that reads the
+			// intent or resolves it.
+			if len(txnMeta.IgnoredSeqNums) > 0 {
+				txnMetaCopy := *txnMeta
+				txnMetaCopy.IgnoredSeqNums = nil
+				txnMeta = &txnMetaCopy
+			}
 		}
 		buf.newMeta.Txn = txnMeta
 		buf.newMeta.Timestamp = hlc.LegacyTimestamp(timestamp)
@@ -1788,6 +1908,8 @@ type MVCCScanOptions struct {
 	Tombstones   bool
 	Reverse      bool
 	Txn          *roachpb.Transaction
+	Locking      bool
+	SkipLocked   bool
 }
 
 // MVCCScan scans the key range [key, endKey) in the provided engine up to some
@@ -1816,6 +1938,12 @@ type MVCCScanOptions struct {
 //
 // Note that transactional scans must be consistent. Put another way, only
 // non-transactional scans may be inconsistent.
+//
+// Intents which only lock a key (see MVCCLock) are ignored by the scans of
+// other transactions, unless the scan is a locking scan: when Locking is set,
+// the intents of other transactions conflict with the scan regardless of their
+// timestamp. When SkipLocked is also set, the keys holding such intents are
+// skipped instead.
 func MVCCScan(
 	ctx context.Context,
 	engine Reader,
@@ -2077,6 +2205,27 @@ func mvccResolveWriteIntent(
 	timestampsValid := !intent.Txn.Timestamp.Less(hlc.Timestamp(meta.Timestamp))
 	commit := intent.Status == roachpb.COMMITTED && epochsMatch && timestampsValid
 
+	// If the write of the intent being committed was rolled back to a
+	// savepoint, the intent is first replaced by the latest write of the
+	// transaction to the key that was not rolled back. If there is none, the
+	// intent is removed as if the transaction had aborted.
+	if commit && enginepb.TxnSeqIsIgnored(meta.Txn.Sequence, intent.Txn.IgnoredSeqNums) {
+		var removeIntent bool
+		removeIntent, origMetaKeySize, origMetaValSize, err = mvccRewriteIntentFromHistory(
+			engine, ms, intent, metaKey, meta, origMetaKeySize, origMetaValSize, buf)
+		if err != nil {
+			return false, err
+		}
+		commit = !removeIntent
+	}
+
+	// An intent which only locks its key is removed when its transaction
+	// commits, as if the transaction had aborted: its provisional value is a
+	// copy of the previous version of the key (see MVCCLock).
+	if commit && meta.IsLockOnly() {
+		commit = false
+	}
+
 	// Note the small difference to commit epoch handling here: We allow
 	// a push from a previous epoch to move a newer intent. That's not
 	// necessary, but useful for allowing pushers to make forward
@@ -2260,6 +2409,52 @@ func mvccResolveWriteIntent(
 	return true, nil
 }
 
+// mvccRewriteIntentFromHistory replaces the value of an intent whose write
+// was rolled back to a savepoint with the latest value in its intent history
+// that was not rolled back. The intent's metadata is updated in place, and the
+// sizes of its new metadata key and value are returned. If all the writes in
+// the intent history were rolled back as well, nothing is written and
+// removeIntent is returned as true: the caller must remove the intent.
+func mvccRewriteIntentFromHistory(
+	engine ReadWriter,
+	ms *enginepb.MVCCStats,
+	intent roachpb.Intent,
+	metaKey MVCCKey,
+	meta *enginepb.MVCCMetadata,
+	origMetaKeySize, origMetaValSize int64,
+	buf *putBuffer,
+) (removeIntent bool, metaKeySize, metaValSize int64, err error) {
+	i := meta.GetLatestUnignoredIntent(intent.Txn.IgnoredSeqNums)
+	if i < 0 {
+		return true, origMetaKeySize, origMetaValSize, nil
+	}
+	latest := meta.IntentHistory[i]
+
+	// The value is rewritten at the timestamp of the intent, so the stats of
+	// the version below it are unaffected.
+	buf.newMeta = *meta
+	txnMeta := *meta.Txn
+	txnMeta.Sequence = latest.Sequence
+	buf.newMeta.Txn = &txnMeta
+	buf.newMeta.IntentHistory = meta.IntentHistory[:i]
+	buf.newMeta.ValBytes = int64(len(latest.Value))
+	buf.newMeta.Deleted = len(latest.Value) == 0
+	metaKeySize, metaValSize, err = buf.putMeta(engine, metaKey, &buf.newMeta)
+	if err != nil {
+		return false, 0, 0, err
+	}
+	versionKey := MVCCKey{Key: intent.Key, Timestamp: hlc.Timestamp(meta.Timestamp)}
+	if err := engine.Put(versionKey, latest.Value); err != nil {
+		return false, 0, 0, err
+	}
+	if ms != nil {
+		ms.Add(updateStatsOnPut(intent.Key, 0 /* prevValSize */, origMetaKeySize, origMetaValSize,
+			metaKeySize, metaValSize, meta, &buf.newMeta))
+	}
+	*meta = buf.newMeta
+	return false, metaKeySize, metaValSize, nil
+}
+
 // IterAndBuf used to pass iterators and buffers between MVCC* calls, allowing
 // reuse without the callers needing to know the particulars.
 type IterAndBuf struct {
@@ -2859,6 +2859,77 @@ func TestMVCCResolveTxn(t *testing.T) {
 	}
 }
 
+// TestMVCCLock verifies that a lock-only intent conflicts with the writes and
+// the locking reads of other transactions but not with their non-locking
+// reads, and that it is removed without writing a new version of the key when
+// its transaction commits.
+func TestMVCCLock(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	if err := MVCCPut(ctx, engine, nil, testKey1, hlc.Timestamp{WallTime: 1}, value1, nil); err != nil {
+		t.Fatal(err)
+	}
+	lockTxn := makeTxn(*txn1, hlc.Timestamp{WallTime: 2})
+	if err := MVCCLock(ctx, engine, nil, testKey1, lockTxn.Timestamp, lockTxn); err != nil {
+		t.Fatal(err)
+	}
+
+	// The non-locking reads of other transactions read the locked version.
+	otherTxn := makeTxn(*txn2, hlc.Timestamp{WallTime: 3})
+	value, _, err := MVCCGet(ctx, engine, testKey1, otherTxn.Timestamp, true, otherTxn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value == nil || !bytes.Equal(value1.RawBytes, value.RawBytes) {
+		t.Fatalf("expected value %s, got %v", value1.RawBytes, value)
+	}
+
+	// The locking reads of other transactions conflict with the lock, unless
+	// they skip the locked keys.
+	_, _, _, err = MVCCScan(ctx, engine, testKey1, testKey2, math.MaxInt64, otherTxn.Timestamp,
+		MVCCScanOptions{Txn: otherTxn, Locking: true})
+	if _, ok := err.(*roachpb.WriteIntentError); !ok {
+		t.Fatalf("expected WriteIntentError, got %v", err)
+	}
+	kvs, _, _, err := MVCCScan(ctx, engine, testKey1, testKey2, math.MaxInt64, otherTxn.Timestamp,
+		MVCCScanOptions{Txn: otherTxn, Locking: true, SkipLocked: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kvs) != 0 {
+		t.Fatalf("expected the locked key to be skipped, got %v", kvs)
+	}
+
+	// The writes of other transactions conflict with the lock.
+	err = MVCCPut(ctx, engine, nil, testKey1, otherTxn.Timestamp, value2, otherTxn)
+	if _, ok := err.(*roachpb.WriteIntentError); !ok {
+		t.Fatalf("expected WriteIntentError, got %v", err)
+	}
+
+	// Committing the transaction removes the lock without writing a new
+	// version.
+	lockTxnCommit := lockTxn.Clone()
+	lockTxnCommit.Status = roachpb.COMMITTED
+	if err := MVCCResolveWriteIntent(ctx, engine, nil, roachpb.Intent{
+		Span:   roachpb.Span{Key: testKey1},
+		Txn:    lockTxnCommit.TxnMeta,
+		Status: lockTxnCommit.Status,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	value, _, err = MVCCGet(ctx, engine, testKey1, otherTxn.Timestamp, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expTS := (hlc.Timestamp{WallTime: 1}); value == nil || value.Timestamp != expTS {
+		t.Fatalf("expected the value written at %s, got %v", expTS, value)
+	}
+}
+
 // TestMVCCResolveNewerIntent verifies that resolving a newer intent
 // than the committing transaction aborts the intent.
 func TestMVCCResolveNewerIntent(t *testing.T) {
@@ -4550,6 +4621,100 @@ func TestMVCCIntentHistory(t *testing.T) {
 	}
 }
 
+// TestMVCCIgnoredSeqNums verifies that the writes of a transaction whose
+// sequence numbers are ignored are not visible to the transaction and are not
+// committed when the intent is resolved.
+func TestMVCCIgnoredSeqNums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	ts := hlc.Timestamp{WallTime: 1}
+
+	testCases := []struct {
+		name    string
+		ignored []enginepb.IgnoredSeqNumRange
+		// expected is the value visible to the txn and committed by it, or nil
+		// if the key has no value.
+		expected *roachpb.Value
+	}{
+		{"none", nil, &value3},
+		{"last", []enginepb.IgnoredSeqNumRange{{Start: 3, End: 3}}, &value2},
+		{"last two", []enginepb.IgnoredSeqNumRange{{Start: 2, End: 3}}, &value1},
+		{"middle", []enginepb.IgnoredSeqNumRange{{Start: 2, End: 2}}, &value3},
+		{"all", []enginepb.IgnoredSeqNumRange{{Start: 1, End: 3}}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			engine := createTestEngine()
+			defer engine.Close()
+
+			var ms enginepb.MVCCStats
+			txn := makeTxn(*txn1, ts)
+			for i, value := range []roachpb.Value{value1, value2, value3} {
+				txn.Sequence = int32(i + 1)
+				if err := MVCCPut(ctx, engine, &ms, testKey1, ts, value, txn); err != nil {
+					t.Fatal(err)
+				}
+			}
+			txn.IgnoredSeqNums = tc.ignored
+
+			checkValue := func(value *roachpb.Value) {
+				t.Helper()
+				if value == nil && tc.expected == nil {
+					return
+				}
+				if value == nil || tc.expected == nil || !bytes.Equal(value.RawBytes, tc.expected.RawBytes) {
+					t.Fatalf("expected %v, got %v", tc.expected, value)
+				}
+			}
+
+			value, _, err := MVCCGet(ctx, engine, testKey1, ts, true /* consistent */, txn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkValue(value)
+
+			kvs, _, _, err := MVCCScan(
+				ctx, engine, testKey1, testKey1.PrefixEnd(), math.MaxInt64, ts, MVCCScanOptions{Txn: txn},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(kvs) > 1 {
+				t.Fatalf("expected at most one key, got %v", kvs)
+			} else if len(kvs) == 1 {
+				checkValue(&kvs[0].Value)
+			} else {
+				checkValue(nil)
+			}
+
+			txnCommit := *txn
+			txnCommit.Status = roachpb.COMMITTED
+			if err := MVCCResolveWriteIntent(ctx, engine, &ms, roachpb.Intent{
+				Span:   roachpb.Span{Key: testKey1},
+				Status: txnCommit.Status,
+				Txn:    txnCommit.TxnMeta,
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			value, _, err = MVCCGet(ctx, engine, testKey1, ts, true /* consistent */, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkValue(value)
+
+			it := engine.NewIterator(IterOptions{UpperBound: roachpb.KeyMax})
+			expMS, err := ComputeStatsGo(it, MVCCKey{}, MVCCKey{Key: roachpb.KeyMax}, ms.LastUpdateNanos)
+			it.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEq(t, engine, "after resolve", &ms, &expMS)
+		})
+	}
+}
+
 // TestMVCCTimeSeriesPartialMerge ensures that "partial merges" of merged time
 // series data does not result in a different final result than a "full merge".
 func TestMVCCTimeSeriesPartialMerge(t *testing.T) {
@@ -2315,6 +2315,7 @@ func (r *rocksDBIterator) MVCCScan(
 		r.iter, goToCSlice(start), goToCSlice(end),
 		goToCTimestamp(timestamp), C.int64_t(max),
 		goToCTxn(opts.Txn), C.bool(!opts.Inconsistent), C.bool(opts.Reverse), C.bool(opts.Tombstones),
+		C.bool(opts.Locking), C.bool(opts.SkipLocked),
 	)
 
 	if err := statusToError(state.status); err != nil {
@@ -2493,6 +2494,10 @@ func goToCTxn(txn *roachpb.Transaction) C.DBTxn {
 		r.id = goToCSlice(txn.ID.GetBytes())
 		r.epoch = C.uint32_t(txn.Epoch)
 		r.max_timestamp = goToCTimestamp(txn.MaxTimestamp)
+		if n := len(txn.IgnoredSeqNums); n > 0 {
+			r.ignored_seqnums.ranges = (*C.DBIgnoredSeqNumRange)(unsafe.Pointer(&txn.IgnoredSeqNums[0]))
+			r.ignored_seqnums.len = C.int(n)
+		}
 	}
 	return r
 }
@@ -427,10 +427,18 @@ func (ir *intentResolver) processWriteIntentError(
 		log.Infof(ctx, "resolving write intent %s", wiErr)
 	}
 
+	// A request which doesn't wait for conflicting transactions (NOWAIT) only
+	// pushes the transactions which are finished or abandoned, and gets the
+	// WriteIntentError back if any of them is still running.
+	noWait := h.WaitPolicy == roachpb.WaitPolicy_ERROR
+	if noWait {
+		pushType = roachpb.PUSH_TOUCH
+	}
+
 	// Possibly queue this processing if the write intent error is for a
 	// single intent affecting a unitary key.
 	var cleanup func(*roachpb.WriteIntentError, *enginepb.TxnMeta)
-	if len(wiErr.Intents) == 1 && len(wiErr.Intents[0].Span.EndKey) == 0 {
+	if !noWait && len(wiErr.Intents) == 1 && len(wiErr.Intents[0].Span.EndKey) == 0 {
 		var done bool
 		// Note that the write intent error may be mutated here in the event
 		// that this pusher is queued to wait for a different transaction
@@ -444,6 +452,9 @@ func (ir *intentResolver) processWriteIntentError(
 		ctx, wiErr.Intents, h, pushType, false, /* skipIfInFlight */
 	)
 	if pErr != nil {
+		if _, ok := pErr.GetDetail().(*roachpb.TransactionPushError); ok && noWait {
+			return cleanup, wiPErr
+		}
 		return cleanup, pErr
 	}
 
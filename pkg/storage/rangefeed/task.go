@@ -159,6 +159,10 @@ func (s *catchUpScan) iterateAndSend(ctx context.Context) error {
 	// versions of each key that are after the registration's startTS, so we
 	// can't use NextKey.
 	var meta enginepb.MVCCMetadata
+	// skipProvisional is set when the provisional value of an intent which
+	// only locks its key follows; that value is a copy of the previous version
+	// of the key (see engine.MVCCLock) and must not be published.
+	var skipProvisional bool
 	for s.it.Seek(startKey); ; s.it.Next() {
 		if ok, err := s.it.Valid(); err != nil {
 			return err
@@ -175,6 +179,7 @@ func (s *catchUpScan) iterateAndSend(ctx context.Context) error {
 			}
 			if !meta.IsInline() {
 				// Not an inline value. Ignore.
+				skipProvisional = meta.Txn != nil && meta.IsLockOnly()
 				continue
 			}
 
@@ -182,6 +187,9 @@ func (s *catchUpScan) iterateAndSend(ctx context.Context) error {
 			// filter on the registration's starting timestamp. Instead, we
 			// return all inline writes.
 			unsafeVal = meta.RawBytes
+		} else if skipProvisional {
+			skipProvisional = false
+			continue
 		} else if !s.r.startTS.Less(unsafeKey.Timestamp) {
 			// At or before the registration's exclusive starting timestamp.
 			// Ignore.
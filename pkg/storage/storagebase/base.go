@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -34,6 +35,18 @@ var MergeQueueEnabled = settings.RegisterBoolSetting(
 	true,
 )
 
+// BulkIOWriteLimit is the rate limit for the writes to disk on behalf of bulk
+// IO operations. It is defined here because it is also used outside of the
+// stores to pace the bulk writes of SQL jobs.
+var BulkIOWriteLimit = settings.RegisterByteSizeSetting(
+	"kv.bulk_io_write.max_rate",
+	"the rate limit (bytes/sec) to use for writes to disk on behalf of bulk io ops",
+	math.MaxInt64,
+)
+
+// BulkIOWriteBurst is the burst for the limiters of BulkIOWriteLimit.
+const BulkIOWriteBurst = 2 * 1024 * 1024 // 2MB
+
 // TxnCleanupThreshold is the threshold after which a transaction is
 // considered abandoned and fit for removal, as measured by the
 // maximum of its last heartbeat and timestamp. Abort spans for the
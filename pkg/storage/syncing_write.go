@@ -24,14 +24,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"golang.org/x/time/rate"
 )
 
-// bulkIOWriteBurst is the burst for the BulkIOWriteLimiter.
-const bulkIOWriteBurst = 2 * 1024 * 1024 // 2MB
-
 const bulkIOWriteLimiterLongWait = 500 * time.Millisecond
 
 func limitBulkIOWrite(ctx context.Context, limiter *rate.Limiter, cost int) {
@@ -43,8 +41,8 @@ func limitBulkIOWrite(ctx context.Context, limiter *rate.Limiter, cost int) {
 	// that didn't seem to be as smooth in practice (NB [dt]: that was when this
 	// limit was done before writing the whole file, rather than on individual
 	// chunks).
-	if cost > bulkIOWriteBurst {
-		cost = bulkIOWriteBurst
+	if cost > storagebase.BulkIOWriteBurst {
+		cost = storagebase.BulkIOWriteBurst
 	}
 
 	begin := timeutil.Now()
@@ -62,7 +60,7 @@ func limitBulkIOWrite(ctx context.Context, limiter *rate.Limiter, cost int) {
 var sstWriteSyncRate = settings.RegisterByteSizeSetting(
 	"kv.bulk_sst.sync_size",
 	"threshold after which non-Rocks SST writes must fsync (0 disables)",
-	bulkIOWriteBurst,
+	storagebase.BulkIOWriteBurst,
 )
 
 // writeFileSyncing is essentially ioutil.WriteFile -- writes data to a file
@@ -84,7 +82,7 @@ func writeFileSyncing(
 	chunkSize := sstWriteSyncRate.Get(&settings.SV)
 	sync := true
 	if chunkSize == 0 {
-		chunkSize = bulkIOWriteBurst
+		chunkSize = storagebase.BulkIOWriteBurst
 		sync = false
 	}
 
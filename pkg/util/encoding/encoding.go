@@ -80,6 +80,9 @@ const (
 	bitArrayDataTerminator     = 0x00
 	bitArrayDataDescTerminator = 0xff
 
+	// arrayEmpty is the key used for empty arrays in inverted indexes.
+	arrayEmpty = bitArrayDescMarker + 1
+
 	// IntMin is chosen such that the range of int tags does not overlap the
 	// ascii character set that is frequently used in testing.
 	IntMin      = 0x80 // 128
@@ -730,6 +733,12 @@ func EncodeJSONEmptyArray(b []byte) []byte {
 	return append(b, escape, escapedTerm, jsonEmptyArray)
 }
 
+// EncodeEmptyArray returns a byte array b with a byte to signify an empty
+// array in an inverted index.
+func EncodeEmptyArray(b []byte) []byte {
+	return append(b, arrayEmpty)
+}
+
 // AddJSONPathTerminator adds a json path terminator to a byte array.
 func AddJSONPathTerminator(b []byte) []byte {
 	return append(b, escape, escapedTerm)
@@ -1306,7 +1315,7 @@ func PeekLength(b []byte) (int, error) {
 	m := b[0]
 	switch m {
 	case encodedNull, encodedNullDesc, encodedNotNull, encodedNotNullDesc,
-		floatNaN, floatNaNDesc, floatZero, decimalZero, byte(True), byte(False):
+		floatNaN, floatNaNDesc, floatZero, decimalZero, byte(True), byte(False), arrayEmpty:
 		// interleavedSentinel also falls into this path. Since it
 		// contains the same byte value as encodedNotNullDesc, it
 		// cannot be included explicitly in the case statement.
@@ -976,6 +976,17 @@ func TestEncodeDecodeInterleavedSentinel(t *testing.T) {
 	}
 }
 
+func TestEncodeEmptyArray(t *testing.T) {
+	const hello = "hello"
+
+	buf := EncodeEmptyArray([]byte(hello))
+	expected := []byte(hello + "\x3c")
+	if !bytes.Equal(expected, buf) {
+		t.Fatalf("expected %q, but found %q", expected, buf)
+	}
+	testPeekLength(t, buf[len(hello):])
+}
+
 func TestEncodeDecodeTime(t *testing.T) {
 	zeroTime := timeutil.Unix(0, 0)
 
@@ -0,0 +1,72 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package trigram
+
+import "regexp/syntax"
+
+// RegexpTrigrams returns the sorted, distinct trigrams that every string
+// matched by the regular expression must contain. Only the literal
+// characters which are required by the expression are taken into account:
+// alternations, repetitions and character classes are treated as wildcards.
+// An expression that fails to parse has no trigrams.
+func RegexpTrigrams(pattern string) []string {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	// An unanchored expression can match anywhere in the string.
+	tokens := []token{{kind: wildcard}}
+	tokens = appendRegexpTokens(tokens, re.Simplify())
+	tokens = append(tokens, token{kind: wildcard})
+	return patternTrigrams(tokens)
+}
+
+// appendRegexpTokens appends the tokens of the required parts of re.
+func appendRegexpTokens(tokens []token, re *syntax.Regexp) []token {
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return tokens
+
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			tokens = append(tokens, token{kind: literal, r: r})
+		}
+		return tokens
+
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			tokens = appendRegexpTokens(tokens, sub)
+		}
+		return tokens
+
+	case syntax.OpCapture:
+		return appendRegexpTokens(tokens, re.Sub[0])
+
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+		return append(tokens, token{kind: boundary})
+
+	case syntax.OpWordBoundary:
+		// \b is not a boundary of the words of trigrams: it only knows the ASCII
+		// word characters, and _ is one of them. For example, \bcat matches
+		// "écat", whose word has no "  c" trigram.
+		return append(tokens, token{kind: wildcard})
+
+	case syntax.OpPlus:
+		// x+ is xx*, so the first x is required.
+		tokens = appendRegexpTokens(tokens, re.Sub[0])
+		return append(tokens, token{kind: wildcard})
+	}
+	return append(tokens, token{kind: wildcard})
+}
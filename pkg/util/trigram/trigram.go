@@ -0,0 +1,169 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package trigram implements the trigram matching of the pg_trgm PostgreSQL
+// extension: the similarity of strings based on the groups of three
+// consecutive characters they share, and the trigrams that every string
+// matched by a LIKE pattern or a regular expression must contain.
+package trigram
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultSimilarityThreshold is the similarity above which the % operator
+// considers two strings to be similar.
+const DefaultSimilarityThreshold = 0.3
+
+// isWordChar returns true if r can be part of a word. Trigrams are only
+// extracted from words; the other characters separate them.
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// MakeTrigrams returns the sorted, distinct trigrams of s. s is split into
+// lowercased words, and each word is padded with two spaces before it and one
+// space after it, so that "cat" has the trigrams "  c", " ca", "cat" and
+// "at ".
+func MakeTrigrams(s string) []string {
+	var res []string
+	for _, w := range strings.FieldsFunc(s, func(r rune) bool { return !isWordChar(r) }) {
+		res = appendTrigrams(res, strings.ToLower(w), true /* padStart */, true /* padEnd */)
+	}
+	return sortUnique(res)
+}
+
+// appendTrigrams appends the trigrams of word to res, padding the word at the
+// start and at the end as requested.
+func appendTrigrams(res []string, word string, padStart, padEnd bool) []string {
+	runes := []rune(word)
+	if padStart {
+		runes = append([]rune("  "), runes...)
+	}
+	if padEnd {
+		runes = append(runes, ' ')
+	}
+	for i := 0; i+3 <= len(runes); i++ {
+		res = append(res, string(runes[i:i+3]))
+	}
+	return res
+}
+
+func sortUnique(trigrams []string) []string {
+	sort.Strings(trigrams)
+	n := 0
+	for i := range trigrams {
+		if n == 0 || trigrams[i] != trigrams[n-1] {
+			trigrams[n] = trigrams[i]
+			n++
+		}
+	}
+	return trigrams[:n]
+}
+
+// Similarity returns the number of trigrams shared by a and b, divided by the
+// number of distinct trigrams of a and b. It is 1 for strings that have the
+// same trigrams, and 0 for strings that have none in common.
+func Similarity(a, b string) float64 {
+	ta, tb := MakeTrigrams(a), MakeTrigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for i, j := 0, 0; i < len(ta) && j < len(tb); {
+		switch {
+		case ta[i] < tb[j]:
+			i++
+		case tb[j] < ta[i]:
+			j++
+		default:
+			shared++
+			i++
+			j++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// tokenKind is the kind of a token of a pattern.
+type tokenKind int
+
+const (
+	// literal is a character that must be present in the matched strings.
+	literal tokenKind = iota
+	// boundary is a position which is the start or the end of the matched
+	// strings, like the start of a LIKE pattern or the ^ anchor of a regular
+	// expression. A word of the pattern next to a boundary is also a complete
+	// word of the matched strings.
+	boundary
+	// wildcard stands for any number of unknown characters.
+	wildcard
+)
+
+type token struct {
+	kind tokenKind
+	r    rune
+}
+
+// patternTrigrams returns the sorted, distinct trigrams that every string
+// matched by the pattern made of tokens must contain. Each maximal sequence of
+// literal word characters of the pattern is a part of a word of the matched
+// strings; it is only padded on the sides where the word is known to end,
+// which is where it is followed by a boundary or a literal character which is
+// not a word character. The first and the last tokens must not be literals.
+func patternTrigrams(tokens []token) []string {
+	var res []string
+	for i := 0; i < len(tokens); {
+		if tokens[i].kind != literal || !isWordChar(tokens[i].r) {
+			i++
+			continue
+		}
+		start := i
+		var word strings.Builder
+		for ; i < len(tokens) && tokens[i].kind == literal && isWordChar(tokens[i].r); i++ {
+			word.WriteRune(tokens[i].r)
+		}
+		padStart := tokens[start-1].kind != wildcard
+		padEnd := tokens[i].kind != wildcard
+		res = appendTrigrams(res, strings.ToLower(word.String()), padStart, padEnd)
+	}
+	return sortUnique(res)
+}
+
+// LikeTrigrams returns the sorted, distinct trigrams that every string
+// matched by the LIKE pattern must contain. The pattern uses \ as its escape
+// character. Since trigrams are lowercased, the same trigrams apply to the
+// pattern used with ILIKE.
+func LikeTrigrams(pattern string) []string {
+	// A LIKE pattern must match the whole string.
+	tokens := []token{{kind: boundary}}
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			tokens = append(tokens, token{kind: literal, r: r})
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%' || r == '_':
+			tokens = append(tokens, token{kind: wildcard})
+		default:
+			tokens = append(tokens, token{kind: literal, r: r})
+		}
+	}
+	tokens = append(tokens, token{kind: boundary})
+	return patternTrigrams(tokens)
+}
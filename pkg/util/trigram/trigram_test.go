@@ -0,0 +1,160 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package trigram
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMakeTrigrams(t *testing.T) {
+	testCases := []struct {
+		s   string
+		exp []string
+	}{
+		{"", nil},
+		{"!?", nil},
+		{"a", []string{"  a", " a "}},
+		{"cat", []string{"  c", " ca", "at ", "cat"}},
+		{"Cat, cat!", []string{"  c", " ca", "at ", "cat"}},
+		{"a cat", []string{"  a", "  c", " a ", " ca", "at ", "cat"}},
+		{"über", []string{"  ü", " üb", "ber", "er ", "übe"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.s, func(t *testing.T) {
+			if res := MakeTrigrams(tc.s); !reflect.DeepEqual(res, tc.exp) {
+				t.Fatalf("expected %q, got %q", tc.exp, res)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		exp  float64
+	}{
+		{"", "", 0},
+		{"cat", "", 0},
+		{"cat", "cat", 1},
+		{"cat", "CAT!", 1},
+		{"cat", "dog", 0},
+		{"cat", "cats", 0.5},
+		{"word", "two words", 4.0 / 11},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s,%s", tc.a, tc.b), func(t *testing.T) {
+			if res := Similarity(tc.a, tc.b); math.Abs(res-tc.exp) > 1e-9 {
+				t.Fatalf("expected %g, got %g", tc.exp, res)
+			}
+			if res := Similarity(tc.b, tc.a); math.Abs(res-tc.exp) > 1e-9 {
+				t.Fatalf("expected %g for the reverse, got %g", tc.exp, res)
+			}
+		})
+	}
+}
+
+// checkSubset checks that the trigrams of a pattern are trigrams of a string
+// matched by the pattern.
+func checkSubset(t *testing.T, trigrams []string, match string) {
+	t.Helper()
+	all := MakeTrigrams(match)
+	for _, tg := range trigrams {
+		found := false
+		for _, a := range all {
+			if a == tg {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("%q is matched but does not have the trigram %q", match, tg)
+		}
+	}
+}
+
+func TestLikeTrigrams(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		exp     string
+		match   string
+	}{
+		{"", "", ""},
+		{"%", "", "anything"},
+		{"%ab%", "", "xaby"},
+		{"%cat%", "cat", "concatenate"},
+		{"cat%", "  c| ca|cat", "catalog"},
+		{"%cat", "at |cat", "bobcat"},
+		{"cat", "  c| ca|at |cat", "cat"},
+		{"%Fat cat%", "  c| ca|at |cat|fat", "a fat cats"},
+		{"%fat-cat%", "  c| ca|at |cat|fat", "fat-cats"},
+		{"c_t%", "  c", "cat"},
+		{"%c\\_t%", "  t", "c_t"},
+		{"%c\\at%", "cat", "cats"},
+		{"%über%", "übe|ber", "Dürer über"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			var exp []string
+			if tc.exp != "" {
+				exp = sortUnique(strings.Split(tc.exp, "|"))
+			}
+			res := LikeTrigrams(tc.pattern)
+			if !reflect.DeepEqual(res, exp) {
+				t.Fatalf("expected %q, got %q", exp, res)
+			}
+			checkSubset(t, res, tc.match)
+		})
+	}
+}
+
+func TestRegexpTrigrams(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		exp     string
+		match   string
+	}{
+		{"", "", ""},
+		{"(", "", ""},
+		{"cat", "cat", "concatenate"},
+		{"^cat", "  c| ca|cat", "catalog"},
+		{"cat$", "at |cat", "bobcat"},
+		{"\\bcat\\b", "cat", "a cat"},
+		{"\\bcat", "cat", "écat"},
+		{"fat.*cat", "fat|cat", "fatty bobcat"},
+		{"fat cats?", "  c| ca|at |cat|fat", "fat cat"},
+		{"(?i)CAT", "cat", "cat"},
+		{"(cat)+s", "cat", "catcats"},
+		{"cat|dog", "", "dog"},
+		{"[cb]ats", "ats", "bats"},
+		{"ca(t|r)s", "", "cars"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			var exp []string
+			if tc.exp != "" {
+				exp = sortUnique(strings.Split(tc.exp, "|"))
+			}
+			res := RegexpTrigrams(tc.pattern)
+			if !reflect.DeepEqual(res, exp) {
+				t.Fatalf("expected %q, got %q", exp, res)
+			}
+			checkSubset(t, res, tc.match)
+		})
+	}
+}
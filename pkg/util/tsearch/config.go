@@ -0,0 +1,159 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// DefaultConfig is the text search configuration used when none is
+// specified.
+const DefaultConfig = "simple"
+
+// config is a text search configuration. In every configuration, documents
+// are split into words made of letters and digits, which are lowercased; the
+// configuration then determines the lexeme of each word.
+type config struct {
+	// stopWords are the words which have no lexeme. They still take a
+	// position in the document.
+	stopWords map[string]struct{}
+	// stem, if set, reduces a word to its lexeme. Otherwise the word is its own
+	// lexeme.
+	stem func(word string) string
+}
+
+// The simple configuration uses the words as lexemes, without stemming or
+// stop words. The english configuration ignores common English words and
+// reduces the others to their stem, so that for example "cats" matches
+// "cat".
+var (
+	simpleConfig  = &config{}
+	englishConfig = &config{stopWords: englishStopWords, stem: stemEnglish}
+)
+
+var configs = map[string]*config{
+	"simple":             simpleConfig,
+	"pg_catalog.simple":  simpleConfig,
+	"english":            englishConfig,
+	"pg_catalog.english": englishConfig,
+}
+
+// getConfig returns the text search configuration with the given name, or an
+// error if it does not exist.
+func getConfig(name string) (*config, error) {
+	cfg, ok := configs[strings.ToLower(name)]
+	if !ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+			"text search configuration %q does not exist", name)
+	}
+	return cfg, nil
+}
+
+// lexeme returns the lexeme of a lowercased word, or false if the word is a
+// stop word.
+func (cfg *config) lexeme(word string) (string, bool) {
+	if _, ok := cfg.stopWords[word]; ok {
+		return "", false
+	}
+	if cfg.stem != nil {
+		word = cfg.stem(word)
+	}
+	return word, true
+}
+
+// tokenize splits a document into lowercased words.
+func tokenize(document string) []string {
+	words := strings.FieldsFunc(document, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i := range words {
+		words[i] = strings.ToLower(words[i])
+	}
+	return words
+}
+
+// ToTSVector converts a document to a tsvector, using the given text search
+// configuration.
+func ToTSVector(config, document string) (TSVector, error) {
+	cfg, err := getConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	words := tokenize(document)
+	terms := make([]Term, 0, len(words))
+	for i, w := range words {
+		w, ok := cfg.lexeme(w)
+		if !ok || len(w) > maxLexemeLen {
+			continue
+		}
+		pos := i + 1
+		if pos > maxPosition {
+			pos = maxPosition
+		}
+		terms = append(terms, Term{Lexeme: w, Positions: []Position{{Pos: uint16(pos)}}})
+	}
+	return MakeTSVector(terms), nil
+}
+
+// ToTSQuery parses a query written in the text format of tsquery, normalizing
+// the operands using the given text search configuration. Operands which
+// normalize to several words are replaced by a phrase of these words, and
+// operands which normalize to no word, such as stop words, are removed.
+func ToTSQuery(config, query string) (TSQuery, error) {
+	cfg, err := getConfig(config)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	return parseTSQuery(query, func(n *Node) *Node {
+		var res *Node
+		// The stop words between two words of the phrase increase the distance
+		// between them.
+		distance := uint16(1)
+		for _, w := range tokenize(n.Lexeme) {
+			w, ok := cfg.lexeme(w)
+			if !ok {
+				if res != nil {
+					distance++
+				}
+				continue
+			}
+			word := &Node{Op: OpOperand, Lexeme: w, Weights: n.Weights, Prefix: n.Prefix}
+			res = makeBinary(OpPhrase, distance, res, word)
+			distance = 1
+		}
+		return res
+	})
+}
+
+// PlainToTSQuery converts plain text to a tsquery which matches the documents
+// containing all its words, using the given text search configuration.
+func PlainToTSQuery(config, text string) (TSQuery, error) {
+	cfg, err := getConfig(config)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	var res *Node
+	for _, w := range tokenize(text) {
+		w, ok := cfg.lexeme(w)
+		if !ok {
+			continue
+		}
+		res = makeBinary(OpAnd, 0, res, &Node{Op: OpOperand, Lexeme: w})
+	}
+	return TSQuery{Root: res}, nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import "sort"
+
+// Match returns true if the vector matches the query, which is the result of
+// the @@ operator.
+func Match(v TSVector, q TSQuery) bool {
+	if q.Root == nil {
+		return false
+	}
+	return matchNode(v, q.Root)
+}
+
+func matchNode(v TSVector, n *Node) bool {
+	switch n.Op {
+	case OpOperand:
+		return matchPositions(v, n).matches()
+	case OpNot:
+		return !matchNode(v, n.Left)
+	case OpAnd:
+		return matchNode(v, n.Left) && matchNode(v, n.Right)
+	case OpOr:
+		return matchNode(v, n.Left) || matchNode(v, n.Right)
+	default:
+		return matchPositions(v, n).matches()
+	}
+}
+
+// positionSet is a set of positions in a document. If negated is set, the
+// set contains all the positions except the listed ones.
+type positionSet struct {
+	positions []uint16
+	negated   bool
+}
+
+// everywhere is the set of all the positions. It is used for lexemes that
+// were stored without positions, which match at any position.
+var everywhere = positionSet{negated: true}
+
+func (s positionSet) matches() bool {
+	return s.negated || len(s.positions) > 0
+}
+
+func (s positionSet) contains(pos int) bool {
+	i := sort.Search(len(s.positions), func(i int) bool { return int(s.positions[i]) >= pos })
+	found := i < len(s.positions) && int(s.positions[i]) == pos
+	return found != s.negated
+}
+
+// matchPositions returns the positions at which a node matches. For
+// phrases, these are the positions of the last lexeme of the phrase.
+func matchPositions(v TSVector, n *Node) positionSet {
+	switch n.Op {
+	case OpOperand:
+		return operandPositions(v, n)
+	case OpNot:
+		s := matchPositions(v, n.Left)
+		s.negated = !s.negated
+		return s
+	}
+	l, r := matchPositions(v, n.Left), matchPositions(v, n.Right)
+	switch n.Op {
+	case OpOr:
+		switch {
+		case !l.negated && !r.negated:
+			return positionSet{positions: union(l.positions, r.positions)}
+		case l.negated && r.negated:
+			return positionSet{positions: intersect(l.positions, r.positions), negated: true}
+		case l.negated:
+			return positionSet{positions: difference(l.positions, r.positions), negated: true}
+		default:
+			return positionSet{positions: difference(r.positions, l.positions), negated: true}
+		}
+	case OpAnd:
+		switch {
+		case !l.negated && !r.negated:
+			// Inside a phrase, both operands must match but the phrase can
+			// continue from either of them.
+			if len(l.positions) == 0 || len(r.positions) == 0 {
+				return positionSet{}
+			}
+			return positionSet{positions: union(l.positions, r.positions)}
+		case l.negated && r.negated:
+			return positionSet{positions: union(l.positions, r.positions), negated: true}
+		case l.negated:
+			return positionSet{positions: difference(r.positions, l.positions)}
+		default:
+			return positionSet{positions: difference(l.positions, r.positions)}
+		}
+	default:
+		// OpPhrase: the right operand must match Distance positions after the
+		// left operand.
+		d := int(n.Distance)
+		switch {
+		case !r.negated:
+			var res []uint16
+			for _, p := range r.positions {
+				if int(p) > d && l.contains(int(p)-d) {
+					res = append(res, p)
+				}
+			}
+			return positionSet{positions: res}
+		case !l.negated:
+			var res []uint16
+			for _, p := range l.positions {
+				if int(p)+d <= maxPosition && r.contains(int(p)+d) {
+					res = append(res, p+uint16(d))
+				}
+			}
+			return positionSet{positions: res}
+		default:
+			shifted := make([]uint16, 0, len(l.positions))
+			for _, p := range l.positions {
+				if int(p)+d <= maxPosition {
+					shifted = append(shifted, p+uint16(d))
+				}
+			}
+			return positionSet{positions: union(shifted, r.positions), negated: true}
+		}
+	}
+}
+
+// operandPositions returns the positions of the occurrences of the lexemes
+// matched by an operand, filtered by the weights of the operand.
+func operandPositions(v TSVector, n *Node) positionSet {
+	var terms []Term
+	if n.Prefix {
+		terms = v.findPrefix(n.Lexeme)
+	} else if t := v.find(n.Lexeme); t != nil {
+		terms = []Term{*t}
+	}
+	var res []uint16
+	for _, t := range terms {
+		if len(t.Positions) == 0 {
+			// Lexemes without positions have the default weight.
+			if n.Weights == 0 || n.Weights&(1<<WeightD) != 0 {
+				return everywhere
+			}
+			continue
+		}
+		for _, p := range t.Positions {
+			if n.Weights == 0 || n.Weights&(1<<p.Weight) != 0 {
+				res = append(res, p.Pos)
+			}
+		}
+	}
+	if len(terms) > 1 {
+		sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	}
+	return positionSet{positions: res}
+}
+
+// union returns the sorted union of two sorted lists of positions.
+func union(a, b []uint16) []uint16 {
+	res := make([]uint16, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			res = append(res, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			res = append(res, b[j])
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+// intersect returns the positions present in both sorted lists.
+func intersect(a, b []uint16) []uint16 {
+	var res []uint16
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case b[j] < a[i]:
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+// difference returns the positions of the sorted list a that are not in the
+// sorted list b.
+func difference(a, b []uint16) []uint16 {
+	var res []uint16
+	j := 0
+	for _, p := range a {
+		for j < len(b) && b[j] < p {
+			j++
+		}
+		if j == len(b) || b[j] != p {
+			res = append(res, p)
+		}
+	}
+	return res
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import "math/rand"
+
+// Lexemes are drawn from a small pool so that random vectors and queries
+// often have lexemes in common.
+var staticLexemes = []string{"a", "b", "cat", "fat", "rat", "it's", "a b"}
+
+func randomLexeme(rng *rand.Rand) string {
+	return staticLexemes[rng.Intn(len(staticLexemes))]
+}
+
+// RandTSVector generates a random tsvector.
+func RandTSVector(rng *rand.Rand) TSVector {
+	terms := make([]Term, rng.Intn(5))
+	for i := range terms {
+		terms[i].Lexeme = randomLexeme(rng)
+		positions := make([]Position, rng.Intn(3))
+		for j := range positions {
+			positions[j] = Position{Pos: uint16(1 + rng.Intn(20)), Weight: Weight(rng.Intn(4))}
+		}
+		terms[i].Positions = positions
+	}
+	return MakeTSVector(terms)
+}
+
+// RandTSQuery generates a random tsquery.
+func RandTSQuery(rng *rand.Rand) TSQuery {
+	if rng.Intn(10) == 0 {
+		return TSQuery{}
+	}
+	return TSQuery{Root: randomNode(rng, 3)}
+}
+
+func randomNode(rng *rand.Rand, complexity int) *Node {
+	if complexity <= 0 || rng.Intn(3) == 0 {
+		return &Node{
+			Op:      OpOperand,
+			Lexeme:  randomLexeme(rng),
+			Weights: uint8(rng.Intn(4)),
+			Prefix:  rng.Intn(4) == 0,
+		}
+	}
+	op := Operator(1 + rng.Intn(int(OpNot)))
+	n := &Node{Op: op, Left: randomNode(rng, complexity-1)}
+	if op != OpNot {
+		n.Right = randomNode(rng, complexity-1)
+	}
+	if op == OpPhrase {
+		n.Distance = uint16(rng.Intn(3))
+	}
+	return n
+}
@@ -0,0 +1,213 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import (
+	"math"
+	"sort"
+)
+
+// The normalization options of Rank, which can be combined. They divide the
+// rank by:
+const (
+	// NormLogLength: 1 + the logarithm of the document length.
+	NormLogLength = 1
+	// NormLength: the document length.
+	NormLength = 2
+	// NormUniq: the number of unique words in the document.
+	NormUniq = 8
+	// NormLogUniq: 1 + the logarithm of the number of unique words.
+	NormLogUniq = 16
+	// NormRank: itself + 1.
+	NormRank = 32
+)
+
+// defaultWeights are the weights of the D, C, B and A occurrences.
+var defaultWeights = [4]float64{0.1, 0.2, 0.4, 1.0}
+
+// nullPosition is used in place of the positions of lexemes stored without
+// positions.
+var nullPosition = []Position{{}}
+
+// Rank returns the relevance of the vector for the query, computed from the
+// frequency of the lexemes of the query in the vector. This is the ts_rank
+// builtin, and uses the same algorithm as PostgreSQL.
+func Rank(v TSVector, q TSQuery, normalization int) float64 {
+	if q.Root == nil {
+		return 0
+	}
+	var res float64
+	if q.Root.Op == OpAnd || q.Root.Op == OpPhrase {
+		res = rankAnd(v, q)
+	} else {
+		res = rankOr(v, q)
+	}
+	if res < 0 {
+		res = 1e-20
+	}
+	if normalization&NormLogLength != 0 && len(v) > 0 {
+		res /= math.Log2(float64(documentLength(v) + 1))
+	}
+	if normalization&NormLength != 0 {
+		if l := documentLength(v); l > 0 {
+			res /= float64(l)
+		}
+	}
+	if normalization&NormUniq != 0 && len(v) > 0 {
+		res /= float64(len(v))
+	}
+	if normalization&NormLogUniq != 0 && len(v) > 0 {
+		res /= math.Log2(float64(len(v) + 1))
+	}
+	if normalization&NormRank != 0 {
+		res /= res + 1
+	}
+	return res
+}
+
+// documentLength returns the number of lexeme occurrences in the vector.
+func documentLength(v TSVector) int {
+	n := 0
+	for i := range v {
+		if len(v[i].Positions) == 0 {
+			n++
+		} else {
+			n += len(v[i].Positions)
+		}
+	}
+	return n
+}
+
+// uniqueOperands returns the distinct operands of the query, sorted by lexeme.
+func uniqueOperands(q TSQuery) []*Node {
+	ops := q.Operands()
+	sort.SliceStable(ops, func(i, j int) bool { return ops[i].Lexeme < ops[j].Lexeme })
+	n := 0
+	for i := range ops {
+		if n > 0 && *ops[n-1] == *ops[i] {
+			continue
+		}
+		ops[n] = ops[i]
+		n++
+	}
+	return ops[:n]
+}
+
+// operandTerms returns the terms of the vector matched by an operand.
+func operandTerms(v TSVector, n *Node) []Term {
+	if n.Prefix {
+		return v.findPrefix(n.Lexeme)
+	}
+	if t := v.find(n.Lexeme); t != nil {
+		return []Term{*t}
+	}
+	return nil
+}
+
+func termPositions(t Term) []Position {
+	if len(t.Positions) == 0 {
+		return nullPosition
+	}
+	return t.Positions
+}
+
+func weight(p Position) float64 {
+	return defaultWeights[p.Weight]
+}
+
+// wordDistance returns the contribution of two occurrences of lexemes
+// separated by the given distance.
+func wordDistance(dist int) float64 {
+	if dist > 100 {
+		return 1e-30
+	}
+	return 1.0 / (1.005 + 0.05*math.Exp(float64(dist)/1.5-2))
+}
+
+// rankOr ranks the vector using the number of occurrences of each lexeme of
+// the query, independently of each other.
+func rankOr(v TSVector, q TSQuery) float64 {
+	ops := uniqueOperands(q)
+	var res float64
+	for _, op := range ops {
+		for _, t := range operandTerms(v, op) {
+			var resj, wjm float64
+			wjm = -1
+			jm := 0
+			for j, p := range termPositions(t) {
+				w := weight(p)
+				resj += w / float64((j+1)*(j+1))
+				if w > wjm {
+					wjm = w
+					jm = j
+				}
+			}
+			// The limit of sum(1/i^2), i = 1..inf, is pi^2/6.
+			res += (wjm + resj - wjm/float64((jm+1)*(jm+1))) / 1.64493406685
+		}
+	}
+	if len(ops) > 0 {
+		res /= float64(len(ops))
+	}
+	return res
+}
+
+// rankAnd ranks the vector using the distance between the occurrences of the
+// pairs of lexemes of the query.
+func rankAnd(v TSVector, q TSQuery) float64 {
+	ops := uniqueOperands(q)
+	if len(ops) < 2 {
+		return rankOr(v, q)
+	}
+	positions := make([][]Position, len(ops))
+	// noPositions[i] is set if the lexeme of the i-th operand was stored
+	// without positions.
+	noPositions := make([]bool, len(ops))
+	res := -1.0
+	for i, op := range ops {
+		terms := operandTerms(v, op)
+		if len(terms) == 0 {
+			continue
+		}
+		positions[i] = termPositions(terms[0])
+		noPositions[i] = len(terms[0].Positions) == 0
+		for k := 0; k < i; k++ {
+			if positions[k] == nil {
+				continue
+			}
+			for _, pi := range positions[i] {
+				for _, pk := range positions[k] {
+					dist := int(pi.Pos) - int(pk.Pos)
+					if dist < 0 {
+						dist = -dist
+					}
+					if dist == 0 {
+						if !noPositions[i] && !noPositions[k] {
+							continue
+						}
+						dist = maxPosition + 1
+					}
+					curw := math.Sqrt(weight(pi) * weight(pk) * wordDistance(dist))
+					if res < 0 {
+						res = curw
+					} else {
+						res = 1.0 - (1.0-res)*(1.0-curw)
+					}
+				}
+			}
+		}
+	}
+	return res
+}
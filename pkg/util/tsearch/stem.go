@@ -0,0 +1,398 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import "strings"
+
+// englishStopWords are the words which the english configuration ignores.
+// This is the list of the english_stem dictionary of PostgreSQL.
+var englishStopWords = makeWordSet(
+	"i", "me", "my", "myself", "we", "our", "ours", "ourselves", "you", "your",
+	"yours", "yourself", "yourselves", "he", "him", "his", "himself", "she",
+	"her", "hers", "herself", "it", "its", "itself", "they", "them", "their",
+	"theirs", "themselves", "what", "which", "who", "whom", "this", "that",
+	"these", "those", "am", "is", "are", "was", "were", "be", "been", "being",
+	"have", "has", "had", "having", "do", "does", "did", "doing", "a", "an",
+	"the", "and", "but", "if", "or", "because", "as", "until", "while", "of",
+	"at", "by", "for", "with", "about", "against", "between", "into",
+	"through", "during", "before", "after", "above", "below", "to", "from",
+	"up", "down", "in", "out", "on", "off", "over", "under", "again",
+	"further", "then", "once", "here", "there", "when", "where", "why", "how",
+	"all", "any", "both", "each", "few", "more", "most", "other", "some",
+	"such", "no", "nor", "not", "only", "own", "same", "so", "than", "too",
+	"very", "s", "t", "can", "will", "just", "don", "should", "now",
+)
+
+func makeWordSet(words ...string) map[string]struct{} {
+	m := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		m[w] = struct{}{}
+	}
+	return m
+}
+
+// stemEnglishExceptions are the words which stemEnglish doesn't reduce with
+// the rules of the algorithm.
+var stemEnglishExceptions = map[string]string{
+	"skies":  "ski",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+// stemEnglishInvariants are the words which are left unchanged once their
+// plural ending has been removed.
+var stemEnglishInvariants = makeWordSet(
+	"inning", "outing", "canning", "herring", "earring", "proceed", "exceed",
+	"succeed",
+)
+
+// stemEnglish reduces a lowercased English word to its stem, with the English
+// (Porter2) stemming algorithm of the Snowball project, which PostgreSQL also
+// uses. See https://snowballstem.org/algorithms/english/stemmer.html.
+func stemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	if stem, ok := stemEnglishExceptions[word]; ok {
+		return stem
+	}
+	s := stemmer{b: []byte(strings.TrimPrefix(word, "'"))}
+	s.markConsonantYs()
+	s.findRegions()
+
+	s.step0()
+	s.step1a()
+	if _, ok := stemEnglishInvariants[string(s.b)]; ok {
+		return string(s.b)
+	}
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+
+	return strings.Replace(string(s.b), "Y", "y", -1)
+}
+
+// stemmer holds the state of stemEnglish. The regions r1 and r2 of the
+// algorithm are stored as the offsets at which they start in the word.
+type stemmer struct {
+	b      []byte
+	r1, r2 int
+}
+
+func isVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+func (s *stemmer) isVowelAt(i int) bool {
+	return isVowel(s.b[i])
+}
+
+// markConsonantYs replaces the initial y and each y after a vowel by Y, so
+// that these ys are treated as consonants.
+func (s *stemmer) markConsonantYs() {
+	for i := range s.b {
+		if s.b[i] == 'y' && (i == 0 || s.isVowelAt(i-1)) {
+			s.b[i] = 'Y'
+		}
+	}
+}
+
+// findRegions sets r1, the region after the first non-vowel following a
+// vowel, and r2, the same region in r1.
+func (s *stemmer) findRegions() {
+	s.r1 = len(s.b)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(s.b), prefix) {
+			s.r1 = len(prefix)
+			break
+		}
+	}
+	if s.r1 == len(s.b) {
+		s.r1 = s.regionAfter(0)
+	}
+	s.r2 = s.regionAfter(s.r1)
+}
+
+func (s *stemmer) regionAfter(start int) int {
+	for i := start + 1; i < len(s.b); i++ {
+		if !s.isVowelAt(i) && s.isVowelAt(i-1) {
+			return i + 1
+		}
+	}
+	return len(s.b)
+}
+
+func (s *stemmer) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(s.b), suffix)
+}
+
+// longestSuffix returns the longest of the given suffixes which ends the
+// word, or the empty string if there is none.
+func (s *stemmer) longestSuffix(suffixes ...string) string {
+	var res string
+	for _, suffix := range suffixes {
+		if len(suffix) > len(res) && s.hasSuffix(suffix) {
+			res = suffix
+		}
+	}
+	return res
+}
+
+// inR1 and inR2 return whether the given suffix of the word is in r1 or r2.
+func (s *stemmer) inR1(suffix string) bool {
+	return len(s.b)-len(suffix) >= s.r1
+}
+
+func (s *stemmer) inR2(suffix string) bool {
+	return len(s.b)-len(suffix) >= s.r2
+}
+
+func (s *stemmer) replaceSuffix(suffix, replacement string) {
+	s.b = append(s.b[:len(s.b)-len(suffix)], replacement...)
+}
+
+// hasVowelBefore returns whether the word has a vowel before the given
+// suffix.
+func (s *stemmer) hasVowelBefore(suffix string) bool {
+	for i := 0; i < len(s.b)-len(suffix); i++ {
+		if s.isVowelAt(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsWithShortSyllable returns whether the first n bytes of the word end with
+// a short syllable, i.e. either a non-vowel followed by a vowel followed by a
+// non-vowel other than w, x or Y, or a vowel at the beginning of the word
+// followed by a non-vowel.
+func (s *stemmer) endsWithShortSyllable(n int) bool {
+	if n == 2 {
+		return s.isVowelAt(0) && !s.isVowelAt(1)
+	}
+	if n < 3 {
+		return false
+	}
+	switch s.b[n-1] {
+	case 'w', 'x', 'Y':
+		return false
+	}
+	return !s.isVowelAt(n-3) && s.isVowelAt(n-2) && !s.isVowelAt(n-1)
+}
+
+// isShort returns whether the word ends with a short syllable and r1 is
+// empty.
+func (s *stemmer) isShort() bool {
+	return s.r1 >= len(s.b) && s.endsWithShortSyllable(len(s.b))
+}
+
+// step0 removes the possessive endings.
+func (s *stemmer) step0() {
+	if suffix := s.longestSuffix("'", "'s", "'s'"); suffix != "" {
+		s.replaceSuffix(suffix, "")
+	}
+}
+
+// step1a removes the plural endings.
+func (s *stemmer) step1a() {
+	switch suffix := s.longestSuffix("sses", "ied", "ies", "s", "us", "ss"); suffix {
+	case "sses":
+		s.replaceSuffix(suffix, "ss")
+	case "ied", "ies":
+		if len(s.b) > 4 {
+			s.replaceSuffix(suffix, "i")
+		} else {
+			s.replaceSuffix(suffix, "ie")
+		}
+	case "s":
+		// The s is removed if a vowel precedes the letter before it.
+		for i := 0; i < len(s.b)-2; i++ {
+			if s.isVowelAt(i) {
+				s.replaceSuffix(suffix, "")
+				break
+			}
+		}
+	}
+}
+
+// step1b removes the past tense and gerund endings.
+func (s *stemmer) step1b() {
+	switch suffix := s.longestSuffix("eed", "eedly", "ed", "edly", "ing", "ingly"); suffix {
+	case "eed", "eedly":
+		if s.inR1(suffix) {
+			s.replaceSuffix(suffix, "ee")
+		}
+	case "ed", "edly", "ing", "ingly":
+		if !s.hasVowelBefore(suffix) {
+			return
+		}
+		s.replaceSuffix(suffix, "")
+		switch {
+		case s.hasSuffix("at"), s.hasSuffix("bl"), s.hasSuffix("iz"):
+			s.b = append(s.b, 'e')
+		case s.longestSuffix("bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt") != "":
+			s.b = s.b[:len(s.b)-1]
+		case s.isShort():
+			s.b = append(s.b, 'e')
+		}
+	}
+}
+
+// step1c replaces a final y by i if it follows a non-vowel which is not the
+// first letter of the word.
+func (s *stemmer) step1c() {
+	n := len(s.b)
+	if n > 2 && (s.b[n-1] == 'y' || s.b[n-1] == 'Y') && !s.isVowelAt(n-2) {
+		s.b[n-1] = 'i'
+	}
+}
+
+var step2Suffixes = map[string]string{
+	"tional":  "tion",
+	"enci":    "ence",
+	"anci":    "ance",
+	"abli":    "able",
+	"entli":   "ent",
+	"izer":    "ize",
+	"ization": "ize",
+	"ational": "ate",
+	"ation":   "ate",
+	"ator":    "ate",
+	"alism":   "al",
+	"aliti":   "al",
+	"alli":    "al",
+	"fulness": "ful",
+	"ousli":   "ous",
+	"ousness": "ous",
+	"iveness": "ive",
+	"iviti":   "ive",
+	"biliti":  "ble",
+	"bli":     "ble",
+	"ogi":     "og",
+	"fulli":   "ful",
+	"lessli":  "less",
+	"li":      "",
+}
+
+var step2SuffixList = suffixList(step2Suffixes)
+
+// step2 replaces the derivational suffixes in r1.
+func (s *stemmer) step2() {
+	suffix := s.longestSuffix(step2SuffixList...)
+	if suffix == "" || !s.inR1(suffix) {
+		return
+	}
+	switch suffix {
+	case "ogi":
+		if s.b[len(s.b)-4] != 'l' {
+			return
+		}
+	case "li":
+		switch s.b[len(s.b)-3] {
+		case 'c', 'd', 'e', 'g', 'h', 'k', 'm', 'n', 'r', 't':
+		default:
+			return
+		}
+	}
+	s.replaceSuffix(suffix, step2Suffixes[suffix])
+}
+
+var step3Suffixes = map[string]string{
+	"tional":  "tion",
+	"ational": "ate",
+	"alize":   "al",
+	"icate":   "ic",
+	"iciti":   "ic",
+	"ical":    "ic",
+	"ful":     "",
+	"ness":    "",
+	"ative":   "",
+}
+
+var step3SuffixList = suffixList(step3Suffixes)
+
+// step3 replaces more derivational suffixes in r1.
+func (s *stemmer) step3() {
+	suffix := s.longestSuffix(step3SuffixList...)
+	if suffix == "" || !s.inR1(suffix) {
+		return
+	}
+	if suffix == "ative" && !s.inR2(suffix) {
+		return
+	}
+	s.replaceSuffix(suffix, step3Suffixes[suffix])
+}
+
+var step4SuffixList = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+}
+
+// step4 removes the remaining derivational suffixes in r2.
+func (s *stemmer) step4() {
+	suffix := s.longestSuffix(step4SuffixList...)
+	if suffix == "" || !s.inR2(suffix) {
+		return
+	}
+	if suffix == "ion" {
+		if c := s.b[len(s.b)-4]; c != 's' && c != 't' {
+			return
+		}
+	}
+	s.replaceSuffix(suffix, "")
+}
+
+// step5 removes a final e, or the final l of a double l.
+func (s *stemmer) step5() {
+	switch {
+	case s.hasSuffix("e"):
+		if s.inR2("e") || (s.inR1("e") && !s.endsWithShortSyllable(len(s.b)-1)) {
+			s.replaceSuffix("e", "")
+		}
+	case s.hasSuffix("l"):
+		if s.inR2("l") && s.hasSuffix("ll") {
+			s.replaceSuffix("l", "")
+		}
+	}
+}
+
+func suffixList(m map[string]string) []string {
+	res := make([]string, 0, len(m))
+	for suffix := range m {
+		res = append(res, suffix)
+	}
+	return res
+}
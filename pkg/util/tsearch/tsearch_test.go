@@ -0,0 +1,354 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+)
+
+func TestParseTSVector(t *testing.T) {
+	testCases := []struct {
+		s   string
+		exp string
+		err string
+	}{
+		{"", "", ""},
+		{"a fat cat", "'a' 'cat' 'fat'", ""},
+		{"b a b", "'a' 'b'", ""},
+		{"a:1 fat:2 cat:3,5", "'a':1 'cat':3,5 'fat':2", ""},
+		{"a:3,1,3 a:2", "'a':1,2,3", ""},
+		{"a:1A,2b,3c,4D", "'a':1A,2B,3C,4", ""},
+		{"a:1,1A", "'a':1A", ""},
+		{"a:99999", "'a':16383", ""},
+		{"'  ' 'it''s' 'a\\\\b'", "'  ' 'a\\\\b' 'it''s'", ""},
+		{`\:x`, "':x'", ""},
+		{"a:", "", "syntax error in tsvector"},
+		{"a:x", "", "syntax error in tsvector"},
+		{"a:1x", "", "syntax error in tsvector"},
+		{"a:0", "", "wrong position info in tsvector"},
+		{"'a", "", "syntax error in tsvector"},
+		{"''", "", "syntax error in tsvector"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.s, func(t *testing.T) {
+			v, err := ParseTSVector(tc.s)
+			if !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if s := v.String(); s != tc.exp {
+				t.Fatalf("expected %s, got %s", tc.exp, s)
+			}
+			// The output must round-trip.
+			v2, err := ParseTSVector(v.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.Compare(v2) != 0 {
+				t.Fatalf("%s did not round-trip: %s", v, v2)
+			}
+		})
+	}
+}
+
+func TestParseTSQuery(t *testing.T) {
+	testCases := []struct {
+		s   string
+		exp string
+		err string
+	}{
+		{"", "", ""},
+		{"a", "'a'", ""},
+		{"a & b | c", "'a' & 'b' | 'c'", ""},
+		{"a & (b | c)", "'a' & ( 'b' | 'c' )", ""},
+		{"a | b & c", "'a' | 'b' & 'c'", ""},
+		{"!a & !(b | c)", "!'a' & !( 'b' | 'c' )", ""},
+		{"!!a", "!!'a'", ""},
+		{"a <-> b & c", "'a' <-> 'b' & 'c'", ""},
+		{"a <-> (b & c)", "'a' <-> ( 'b' & 'c' )", ""},
+		{"a <-> b <-> c", "'a' <-> 'b' <-> 'c'", ""},
+		{"a <-> (b <-> c)", "'a' <-> ( 'b' <-> 'c' )", ""},
+		{"a <2> b", "'a' <2> 'b'", ""},
+		{"a <0> b", "'a' <0> 'b'", ""},
+		{"a:* & b:ab & c:*Dc", "'a':* & 'b':AB & 'c':*CD", ""},
+		{"'a b' & 'it''s'", "'a b' & 'it''s'", ""},
+		{"a &", "", "syntax error in tsquery"},
+		{"& a", "", "syntax error in tsquery"},
+		{"(a", "", "syntax error in tsquery"},
+		{"a b", "", "syntax error in tsquery"},
+		{"a <x> b", "", "syntax error in tsquery"},
+		{"a <99999> b", "", "distance in phrase operator should not be greater than 16383"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.s, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.s)
+			if !testutils.IsError(err, tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if s := q.String(); s != tc.exp {
+				t.Fatalf("expected %s, got %s", tc.exp, s)
+			}
+			// The output must round-trip.
+			q2, err := ParseTSQuery(q.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := q2.String(); s != tc.exp {
+				t.Fatalf("%s did not round-trip: %s", tc.exp, s)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		vector string
+		query  string
+		exp    bool
+	}{
+		{"a fat cat", "", false},
+		{"a fat cat", "cat", true},
+		{"a fat cat", "dog", false},
+		{"a fat cat", "fat & cat", true},
+		{"a fat cat", "fat & dog", false},
+		{"a fat cat", "fat | dog", true},
+		{"a fat cat", "!dog", true},
+		{"a fat cat", "!cat", false},
+		{"a fat cat", "cat & !dog", true},
+		{"a fat cat", "c:*", true},
+		{"a fat cat", "d:*", false},
+
+		// Weights.
+		{"cat:1A fat:2", "cat:A", true},
+		{"cat:1A fat:2", "cat:B", false},
+		{"cat:1A fat:2", "fat:D", true},
+		{"cat:1A fat:2", "fat:AB", false},
+		{"cat fat", "cat:D", true},
+		{"cat fat", "cat:A", false},
+
+		// Phrases.
+		{"a:1 fat:2 cat:3", "fat <-> cat", true},
+		{"a:1 fat:2 cat:3", "cat <-> fat", false},
+		{"a:1 fat:2 cat:3", "a <2> cat", true},
+		{"a:1 fat:2 cat:3", "a <-> cat", false},
+		{"a:1 fat:2 cat:3", "a <-> fat <-> cat", true},
+		{"a:1 fat:2 cat:3", "a <-> (fat <-> cat)", false},
+		{"a:1 fat:2 cat:3", "(a | the) <-> fat", true},
+		{"a:1 fat:2 cat:3", "!a <-> cat", true},
+		{"a:1 fat:2 cat:3", "!fat <-> cat", false},
+		{"a:1 fat:2 cat:3", "fat <-> !cat", false},
+		{"a:1 fat:2 cat:3", "fat <-> !dog", true},
+		{"a:1 fat:2 cat:3", "f:* <-> c:*", true},
+		{"a:1 fat:2A cat:3", "fat:A <-> cat", true},
+		{"a:1 fat:2A cat:3", "fat:B <-> cat", false},
+
+		// Lexemes without positions match at any position.
+		{"a fat cat", "fat <-> cat", true},
+		{"a fat cat", "cat <-> fat", true},
+		{"a fat cat", "fat <-> dog", false},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s @@ %s", tc.vector, tc.query), func(t *testing.T) {
+			v, err := ParseTSVector(tc.vector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q, err := ParseTSQuery(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res := Match(v, q); res != tc.exp {
+				t.Fatalf("expected %t, got %t", tc.exp, res)
+			}
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	const document = "The quick brown fox jumps over the lazy dog."
+	vectorTestCases := []struct {
+		config string
+		exp    string
+	}{
+		{"simple", "'brown':3 'dog':9 'fox':4 'jumps':5 'lazy':8 'over':6 'quick':2 'the':1,7"},
+		{"english", "'brown':3 'dog':9 'fox':4 'jump':5 'lazi':8 'quick':2"},
+	}
+	for _, tc := range vectorTestCases {
+		v, err := ToTSVector(tc.config, document)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := v.String(); s != tc.exp {
+			t.Errorf("to_tsvector(%s): expected %s, got %s", tc.config, tc.exp, s)
+		}
+	}
+
+	queryTestCases := []struct {
+		config string
+		s      string
+		exp    string
+		plain  string
+	}{
+		{"pg_catalog.simple", "Fat & Cats", "'fat' & 'cats'", "'fat' & 'cats'"},
+		{"Simple", "fat:AB & cat:*", "'fat':AB & 'cat':*", "'fat' & 'ab' & 'cat'"},
+		{"simple", "'fat cats' | dogs", "'fat' <-> 'cats' | 'dogs'", "'fat' & 'cats' & 'dogs'"},
+		{"simple", "fat & '-' & !'?'", "'fat'", "'fat'"},
+		{"simple", "!'?'", "", ""},
+		{"english", "Fat & Cats", "'fat' & 'cat'", "'fat' & 'cat'"},
+		{"pg_catalog.english", "'fat the cats' | dogs", "'fat' <2> 'cat' | 'dog'", "'fat' & 'cat' & 'dog'"},
+		{"English", "the & cats:*", "'cat':*", "'cat'"},
+		{"english", "'the' | !'and'", "", ""},
+	}
+	for _, tc := range queryTestCases {
+		q, err := ToTSQuery(tc.config, tc.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := q.String(); s != tc.exp {
+			t.Errorf("to_tsquery(%s, %s): expected %s, got %s", tc.config, tc.s, tc.exp, s)
+		}
+		q, err = PlainToTSQuery(tc.config, tc.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := q.String(); s != tc.plain {
+			t.Errorf("plainto_tsquery(%s, %s): expected %s, got %s", tc.config, tc.s, tc.plain, s)
+		}
+	}
+
+	if _, err := ToTSVector("french", "x"); !testutils.IsError(err, `text search configuration "french" does not exist`) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestStemEnglish(t *testing.T) {
+	testCases := []struct {
+		word string
+		exp  string
+	}{
+		{"a", "a"},
+		{"cats", "cat"},
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"ties", "tie"},
+		{"gas", "gas"},
+		{"gaps", "gap"},
+		{"agreed", "agre"},
+		{"feed", "feed"},
+		{"hopping", "hop"},
+		{"hoping", "hope"},
+		{"abated", "abat"},
+		{"abatements", "abat"},
+		{"abilities", "abil"},
+		{"abruption", "abrupt"},
+		{"consistency", "consist"},
+		{"knackeries", "knackeri"},
+		{"knightly", "knight"},
+		{"happily", "happili"},
+		{"hopeful", "hope"},
+		{"relational", "relat"},
+		{"generously", "generous"},
+		{"communication", "communic"},
+		{"says", "say"},
+		{"crying", "cri"},
+		{"skies", "ski"},
+		{"news", "news"},
+		{"innings", "inning"},
+		{"succeeding", "succeed"},
+	}
+	for _, tc := range testCases {
+		if s := stemEnglish(tc.word); s != tc.exp {
+			t.Errorf("%s: expected %s, got %s", tc.word, tc.exp, s)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	testCases := []struct {
+		vector        string
+		query         string
+		normalization int
+		exp           float64
+	}{
+		{"a:1 fat:2 cat:3", "cat", 0, 0.0607927},
+		{"a:1 fat:2 cat:3,4A", "cat", 0, 0.668720},
+		{"a:1 fat:2 cat:3", "cat | dog", 0, 0.0303964},
+		{"a:1 fat:2 cat:3", "fat & cat", 0, 0.0991032},
+		{"a:1 fat:2 cat:3", "fat <-> cat", 0, 0.0991032},
+		{"a:1 fat:2 cat:3", "a & cat", 0, 0.0985009},
+		{"a:1 fat:2 cat:3", "cat", NormLength, 0.0202642},
+		{"a:1 fat:2 cat:3", "cat", NormLogLength | NormRank, 0.0294997},
+		{"a:1 fat:2 cat:3", "dog", 0, 0},
+		{"a:1 fat:2 cat:3", "dog & cow", 0, 1e-20},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s %s %d", tc.vector, tc.query, tc.normalization), func(t *testing.T) {
+			v, err := ParseTSVector(tc.vector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q, err := ParseTSQuery(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := Rank(v, q, tc.normalization)
+			if math.Abs(res-tc.exp) > 1e-6 {
+				t.Fatalf("expected %g, got %g", tc.exp, res)
+			}
+		})
+	}
+}
+
+func TestRequiredLexeme(t *testing.T) {
+	testCases := []struct {
+		query  string
+		lexeme string
+		exact  bool
+		ok     bool
+	}{
+		{"", "", false, false},
+		{"a", "a", true, true},
+		{"a:A", "a", false, true},
+		{"a:*", "", false, false},
+		{"a & b", "a", false, true},
+		{"!a & b", "b", false, true},
+		{"a:* <-> b", "b", false, true},
+		{"a | b", "", false, false},
+		{"!a", "", false, false},
+		{"(a | b) & (c | d)", "", false, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lexeme, exact, ok := q.RequiredLexeme()
+			if lexeme != tc.lexeme || exact != tc.exact || ok != tc.ok {
+				t.Fatalf("expected (%q, %t, %t), got (%q, %t, %t)",
+					tc.lexeme, tc.exact, tc.ok, lexeme, exact, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,395 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tsearch
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// Operator is the operator of a node of a tsquery.
+type Operator uint8
+
+const (
+	// OpOperand is used for the leaves of a tsquery, which are lexemes.
+	OpOperand Operator = iota
+	// OpOr is the | operator.
+	OpOr
+	// OpAnd is the & operator.
+	OpAnd
+	// OpPhrase is the <-> operator, or <N> for a distance other than 1.
+	OpPhrase
+	// OpNot is the ! operator.
+	OpNot
+)
+
+// priority returns the binding strength of the operator; operators with a
+// higher priority bind tighter. Operands bind tighter than any operator.
+func (o Operator) priority() int {
+	if o == OpOperand {
+		return int(OpNot) + 1
+	}
+	return int(o)
+}
+
+// Node is a node of a tsquery. A node is either an operand, which is a
+// lexeme, or an operator applied to one (for OpNot) or two other nodes.
+type Node struct {
+	Op Operator
+
+	// Lexeme, Weights and Prefix are set for operands. Weights is a bitmask
+	// of the weights the occurrences of the lexeme can have, with bit i set
+	// for Weight i; zero means any weight. Prefix is set if the operand
+	// matches every lexeme that starts with Lexeme.
+	Lexeme  string
+	Weights uint8
+	Prefix  bool
+
+	// Distance is the distance between the operands of OpPhrase.
+	Distance uint16
+
+	// Left is the operand of OpNot and the left operand of the binary
+	// operators. Right is the right operand of the binary operators.
+	Left, Right *Node
+}
+
+// TSQuery is a text search query. The zero value is the empty query, which
+// matches nothing.
+type TSQuery struct {
+	Root *Node
+}
+
+// Empty returns true if the query has no operands.
+func (q TSQuery) Empty() bool {
+	return q.Root == nil
+}
+
+// String returns the text format of the query, e.g. 'a' & ( 'b' | !'c' ).
+func (q TSQuery) String() string {
+	var buf strings.Builder
+	if q.Root != nil {
+		q.Root.format(&buf)
+	}
+	return buf.String()
+}
+
+// Compare returns -1, 0 or 1 depending on whether q sorts before, the same as
+// or after other. Queries are compared by their text format.
+func (q TSQuery) Compare(other TSQuery) int {
+	return strings.Compare(q.String(), other.String())
+}
+
+// Size returns the approximate size of the query in bytes.
+func (q TSQuery) Size() uintptr {
+	var sz uintptr
+	q.walk(func(n *Node) {
+		sz += uintptr(len(n.Lexeme)) + 48
+	})
+	return sz
+}
+
+// walk calls fn for every node of the query, in prefix order.
+func (q TSQuery) walk(fn func(*Node)) {
+	var rec func(*Node)
+	rec = func(n *Node) {
+		if n == nil {
+			return
+		}
+		fn(n)
+		rec(n.Left)
+		rec(n.Right)
+	}
+	rec(q.Root)
+}
+
+// Operands returns the operands of the query, in the order in which they
+// appear in the query.
+func (q TSQuery) Operands() []*Node {
+	var res []*Node
+	q.walk(func(n *Node) {
+		if n.Op == OpOperand {
+			res = append(res, n)
+		}
+	})
+	return res
+}
+
+func (n *Node) format(buf *strings.Builder) {
+	switch n.Op {
+	case OpOperand:
+		writeLexeme(buf, n.Lexeme)
+		if n.Prefix || n.Weights != 0 {
+			buf.WriteByte(':')
+			if n.Prefix {
+				buf.WriteByte('*')
+			}
+			for w := WeightA; ; w-- {
+				if n.Weights&(1<<w) != 0 {
+					buf.WriteString(w.String())
+				}
+				if w == WeightD {
+					break
+				}
+			}
+		}
+	case OpNot:
+		buf.WriteByte('!')
+		n.Left.formatChild(buf, n.Left.Op != OpOperand && n.Left.Op != OpNot)
+	default:
+		n.Left.formatChild(buf, n.Left.Op.priority() < n.Op.priority())
+		switch n.Op {
+		case OpOr:
+			buf.WriteString(" | ")
+		case OpAnd:
+			buf.WriteString(" & ")
+		case OpPhrase:
+			if n.Distance == 1 {
+				buf.WriteString(" <-> ")
+			} else {
+				buf.WriteString(" <")
+				buf.WriteString(strconv.Itoa(int(n.Distance)))
+				buf.WriteString("> ")
+			}
+		}
+		// Phrases are not associative, so a phrase on the right of another
+		// phrase needs parentheses.
+		n.Right.formatChild(buf, n.Right.Op.priority() < n.Op.priority() ||
+			(n.Op == OpPhrase && n.Right.Op == OpPhrase))
+	}
+}
+
+func (n *Node) formatChild(buf *strings.Builder, parens bool) {
+	if parens {
+		buf.WriteString("( ")
+	}
+	n.format(buf)
+	if parens {
+		buf.WriteString(" )")
+	}
+}
+
+// ParseTSQuery parses the text format of a tsquery. The lexemes are used as
+// is, without normalization.
+func ParseTSQuery(s string) (TSQuery, error) {
+	return parseTSQuery(s, func(n *Node) *Node { return n })
+}
+
+// parseTSQuery parses the text format of a tsquery, calling normalize on each
+// operand. normalize can return nil to remove the operand from the query, or
+// an arbitrary tree to replace it.
+func parseTSQuery(s string, normalize func(*Node) *Node) (TSQuery, error) {
+	p := queryParser{
+		textParser: textParser{input: s, typ: "tsquery"},
+		normalize:  normalize,
+	}
+	p.skipSpaces()
+	if p.eof() {
+		return TSQuery{}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return TSQuery{}, err
+	}
+	p.skipSpaces()
+	if !p.eof() {
+		return TSQuery{}, p.syntaxError()
+	}
+	return TSQuery{Root: root}, nil
+}
+
+// queryParser is a recursive descent parser for tsquery. Operands removed by
+// normalization are represented as nil nodes, which are then removed from
+// the operators that use them.
+type queryParser struct {
+	textParser
+	normalize func(*Node) *Node
+}
+
+func (p *queryParser) parseOr() (*Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.skipSpaces(); !p.eof() && p.peek() == '|'; p.skipSpaces() {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = makeBinary(OpOr, 0, left, right)
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (*Node, error) {
+	left, err := p.parsePhrase()
+	if err != nil {
+		return nil, err
+	}
+	for p.skipSpaces(); !p.eof() && p.peek() == '&'; p.skipSpaces() {
+		p.pos++
+		right, err := p.parsePhrase()
+		if err != nil {
+			return nil, err
+		}
+		left = makeBinary(OpAnd, 0, left, right)
+	}
+	return left, nil
+}
+
+func (p *queryParser) parsePhrase() (*Node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.skipSpaces(); !p.eof() && p.peek() == '<'; p.skipSpaces() {
+		distance, err := p.phraseDistance()
+		if err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = makeBinary(OpPhrase, distance, left, right)
+	}
+	return left, nil
+}
+
+// phraseDistance parses a phrase operator, <-> or <N>, and returns its
+// distance.
+func (p *queryParser) phraseDistance() (uint16, error) {
+	p.pos++
+	if strings.HasPrefix(p.input[p.pos:], "->") {
+		p.pos += 2
+		return 1, nil
+	}
+	start := p.pos
+	for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+	if start == p.pos || p.eof() || p.peek() != '>' {
+		return 0, p.syntaxError()
+	}
+	n, err := strconv.Atoi(p.input[start:p.pos])
+	p.pos++
+	if err != nil || n > maxPosition {
+		return 0, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"distance in phrase operator should not be greater than %d", maxPosition)
+	}
+	return uint16(n), nil
+}
+
+func (p *queryParser) parseNot() (*Node, error) {
+	p.skipSpaces()
+	if p.eof() {
+		return nil, p.syntaxError()
+	}
+	switch p.peek() {
+	case '!':
+		p.pos++
+		n, err := p.parseNot()
+		if err != nil || n == nil {
+			return nil, err
+		}
+		return &Node{Op: OpNot, Left: n}, nil
+	case '(':
+		p.pos++
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.eof() || p.peek() != ')' {
+			return nil, p.syntaxError()
+		}
+		p.pos++
+		return n, nil
+	}
+	return p.parseOperand()
+}
+
+func (p *queryParser) parseOperand() (*Node, error) {
+	switch p.peek() {
+	case '&', '|', ')', '<', ':':
+		return nil, p.syntaxError()
+	}
+	lexeme, err := p.lexeme(func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(":&|!()<", r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	n := &Node{Op: OpOperand, Lexeme: lexeme}
+	if !p.eof() && p.peek() == ':' {
+		p.pos++
+		for ; !p.eof(); p.pos++ {
+			if p.peek() == '*' {
+				n.Prefix = true
+			} else if w, ok := parseWeight(p.peek()); ok {
+				n.Weights |= 1 << w
+			} else {
+				break
+			}
+		}
+	}
+	return p.normalize(n), nil
+}
+
+// makeBinary returns a node for a binary operator. If one of the operands
+// was removed by normalization, the other operand is returned.
+func makeBinary(op Operator, distance uint16, left, right *Node) *Node {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return &Node{Op: op, Distance: distance, Left: left, Right: right}
+}
+
+// RequiredLexeme returns a lexeme that is present in every tsvector matched
+// by the query, if there is one. exact is true if the query matches exactly
+// the tsvectors that contain the lexeme.
+func (q TSQuery) RequiredLexeme() (lexeme string, exact bool, ok bool) {
+	if q.Root == nil {
+		return "", false, false
+	}
+	n := q.Root
+	if n.Op == OpOperand {
+		if n.Prefix {
+			return "", false, false
+		}
+		return n.Lexeme, n.Weights == 0, true
+	}
+	lexeme, ok = requiredLexeme(n)
+	return lexeme, false, ok
+}
+
+func requiredLexeme(n *Node) (string, bool) {
+	switch n.Op {
+	case OpOperand:
+		return n.Lexeme, !n.Prefix
+	case OpAnd, OpPhrase:
+		if l, ok := requiredLexeme(n.Left); ok {
+			return l, true
+		}
+		return requiredLexeme(n.Right)
+	}
+	return "", false
+}
@@ -0,0 +1,402 @@
+// Copyright 2019 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package tsearch implements the full text search types of PostgreSQL:
+// tsvector, a sorted list of normalized words (lexemes) with their positions
+// in a document, and tsquery, a boolean expression of lexemes that can be
+// matched against a tsvector.
+//
+// See https://www.postgresql.org/docs/current/datatype-textsearch.html.
+package tsearch
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// Weight is the weight of a lexeme occurrence, used to mark the occurrences
+// coming from different parts of a document (e.g. the title or the body).
+type Weight uint8
+
+// The weights, from the lowest to the highest. D is the default weight.
+const (
+	WeightD Weight = iota
+	WeightC
+	WeightB
+	WeightA
+)
+
+// String returns the letter used for the weight in the text format.
+func (w Weight) String() string {
+	return string("DCBA"[w])
+}
+
+const (
+	// maxLexemeLen is the maximum length of a lexeme in bytes.
+	maxLexemeLen = 2047
+	// maxPosition is the maximum position of a lexeme. Larger positions are
+	// silently reduced to this value, as in PostgreSQL.
+	maxPosition = 16383
+	// maxPositionsPerLexeme is the maximum number of positions kept for a
+	// single lexeme. Additional positions are dropped.
+	maxPositionsPerLexeme = 256
+)
+
+// Position is the position of an occurrence of a lexeme in a document, along
+// with its weight.
+type Position struct {
+	Pos    uint16
+	Weight Weight
+}
+
+// Term is a lexeme of a tsvector, with the sorted positions of its
+// occurrences. A term without positions matches any position.
+type Term struct {
+	Lexeme    string
+	Positions []Position
+}
+
+// TSVector is a document represented as a list of distinct lexemes, sorted in
+// byte order.
+type TSVector []Term
+
+// MakeTSVector returns the tsvector containing the given terms. The terms are
+// sorted and the positions of duplicate lexemes are merged.
+func MakeTSVector(terms []Term) TSVector {
+	v := make(TSVector, len(terms))
+	copy(v, terms)
+	sort.SliceStable(v, func(i, j int) bool { return v[i].Lexeme < v[j].Lexeme })
+	n := 0
+	for i := range v {
+		if n > 0 && v[n-1].Lexeme == v[i].Lexeme {
+			v[n-1].Positions = append(v[n-1].Positions, v[i].Positions...)
+			continue
+		}
+		v[n] = Term{Lexeme: v[i].Lexeme, Positions: append([]Position(nil), v[i].Positions...)}
+		n++
+	}
+	v = v[:n]
+	for i := range v {
+		v[i].Positions = normalizePositions(v[i].Positions)
+	}
+	return v
+}
+
+// normalizePositions sorts the positions and removes the duplicates, keeping
+// the highest weight for each position.
+func normalizePositions(p []Position) []Position {
+	if len(p) == 0 {
+		return nil
+	}
+	sort.SliceStable(p, func(i, j int) bool { return p[i].Pos < p[j].Pos })
+	n := 0
+	for i := range p {
+		if n > 0 && p[n-1].Pos == p[i].Pos {
+			if p[i].Weight > p[n-1].Weight {
+				p[n-1].Weight = p[i].Weight
+			}
+			continue
+		}
+		p[n] = p[i]
+		n++
+	}
+	if n > maxPositionsPerLexeme {
+		n = maxPositionsPerLexeme
+	}
+	return p[:n]
+}
+
+// find returns the term for the given lexeme, or nil if the lexeme is not
+// in the vector.
+func (v TSVector) find(lexeme string) *Term {
+	i := sort.Search(len(v), func(i int) bool { return v[i].Lexeme >= lexeme })
+	if i < len(v) && v[i].Lexeme == lexeme {
+		return &v[i]
+	}
+	return nil
+}
+
+// findPrefix returns the terms whose lexeme starts with the given prefix.
+func (v TSVector) findPrefix(prefix string) []Term {
+	i := sort.Search(len(v), func(i int) bool { return v[i].Lexeme >= prefix })
+	j := i
+	for j < len(v) && strings.HasPrefix(v[j].Lexeme, prefix) {
+		j++
+	}
+	return v[i:j]
+}
+
+// Lexemes returns the lexemes of the vector, in sorted order.
+func (v TSVector) Lexemes() []string {
+	res := make([]string, len(v))
+	for i := range v {
+		res[i] = v[i].Lexeme
+	}
+	return res
+}
+
+// Compare returns -1, 0 or 1 depending on whether v sorts before, the same
+// as or after other. Vectors are compared term by term, first by lexeme and
+// then by positions.
+func (v TSVector) Compare(other TSVector) int {
+	for i := 0; i < len(v) && i < len(other); i++ {
+		if c := strings.Compare(v[i].Lexeme, other[i].Lexeme); c != 0 {
+			return c
+		}
+		a, b := v[i].Positions, other[i].Positions
+		for j := 0; j < len(a) && j < len(b); j++ {
+			switch {
+			case a[j].Pos < b[j].Pos:
+				return -1
+			case a[j].Pos > b[j].Pos:
+				return 1
+			case a[j].Weight < b[j].Weight:
+				return -1
+			case a[j].Weight > b[j].Weight:
+				return 1
+			}
+		}
+		if len(a) != len(b) {
+			return compareInts(len(a), len(b))
+		}
+	}
+	return compareInts(len(v), len(other))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Size returns the approximate size of the vector in bytes.
+func (v TSVector) Size() uintptr {
+	var sz uintptr
+	for i := range v {
+		sz += uintptr(len(v[i].Lexeme)) + 4*uintptr(len(v[i].Positions)) + 40
+	}
+	return sz
+}
+
+// String returns the text format of the vector, e.g. 'a':1,3 'b':2A.
+func (v TSVector) String() string {
+	var buf strings.Builder
+	for i := range v {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		writeLexeme(&buf, v[i].Lexeme)
+		for j, p := range v[i].Positions {
+			if j == 0 {
+				buf.WriteByte(':')
+			} else {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.Itoa(int(p.Pos)))
+			if p.Weight != WeightD {
+				buf.WriteString(p.Weight.String())
+			}
+		}
+	}
+	return buf.String()
+}
+
+// writeLexeme writes a lexeme enclosed in single quotes, doubling the quotes
+// and backslashes it contains.
+func writeLexeme(buf *strings.Builder, lexeme string) {
+	buf.WriteByte('\'')
+	for _, r := range lexeme {
+		if r == '\'' || r == '\\' {
+			buf.WriteRune(r)
+		}
+		buf.WriteRune(r)
+	}
+	buf.WriteByte('\'')
+}
+
+// ParseTSVector parses the text format of a tsvector, a list of lexemes
+// separated by spaces, each optionally followed by a colon and a list of
+// comma-separated positions with an optional weight letter.
+func ParseTSVector(s string) (TSVector, error) {
+	p := textParser{input: s, typ: "tsvector"}
+	var terms []Term
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			break
+		}
+		lexeme, err := p.lexeme(func(r rune) bool { return unicode.IsSpace(r) || r == ':' })
+		if err != nil {
+			return nil, err
+		}
+		t := Term{Lexeme: lexeme}
+		if !p.eof() && p.peek() == ':' {
+			p.pos++
+			if t.Positions, err = p.positions(); err != nil {
+				return nil, err
+			}
+		}
+		terms = append(terms, t)
+	}
+	return MakeTSVector(terms), nil
+}
+
+// textParser is a helper to parse the text format of tsvector and tsquery.
+type textParser struct {
+	input string
+	pos   int
+	// typ is the name of the parsed type, used in error messages.
+	typ string
+}
+
+func (p *textParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *textParser) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *textParser) skipSpaces() {
+	for !p.eof() {
+		r, size := p.nextRune()
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+func (p *textParser) nextRune() (rune, int) {
+	return utf8.DecodeRuneInString(p.input[p.pos:])
+}
+
+func (p *textParser) syntaxError() error {
+	return pgerror.NewErrorf(pgerror.CodeSyntaxError, "syntax error in %s: %q", p.typ, p.input)
+}
+
+// lexeme parses a quoted or unquoted lexeme. An unquoted lexeme ends at the
+// first unescaped rune for which isEnd returns true.
+func (p *textParser) lexeme(isEnd func(rune) bool) (string, error) {
+	var buf strings.Builder
+	quoted := p.peek() == '\''
+	if quoted {
+		p.pos++
+	}
+	for {
+		if p.eof() {
+			if quoted {
+				return "", p.syntaxError()
+			}
+			break
+		}
+		r, size := p.nextRune()
+		if quoted && r == '\'' {
+			p.pos += size
+			if !p.eof() && p.peek() == '\'' {
+				// A doubled quote stands for a single quote.
+				p.pos++
+				buf.WriteByte('\'')
+				continue
+			}
+			break
+		}
+		if !quoted && isEnd(r) {
+			break
+		}
+		if r == '\\' {
+			p.pos += size
+			if p.eof() {
+				return "", p.syntaxError()
+			}
+			r, size = p.nextRune()
+		}
+		p.pos += size
+		buf.WriteRune(r)
+	}
+	if buf.Len() == 0 {
+		return "", p.syntaxError()
+	}
+	if buf.Len() > maxLexemeLen {
+		return "", pgerror.NewErrorf(pgerror.CodeProgramLimitExceededError,
+			"word is too long (%d bytes, max %d bytes)", buf.Len(), maxLexemeLen)
+	}
+	return buf.String(), nil
+}
+
+// positions parses a comma-separated list of positions.
+func (p *textParser) positions() ([]Position, error) {
+	var res []Position
+	for {
+		start := p.pos
+		for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+			p.pos++
+		}
+		if start == p.pos {
+			return nil, p.syntaxError()
+		}
+		n, err := strconv.Atoi(p.input[start:p.pos])
+		if err != nil || n > maxPosition {
+			n = maxPosition
+		}
+		if n == 0 {
+			return nil, pgerror.NewErrorf(pgerror.CodeSyntaxError,
+				"wrong position info in %s: %q", p.typ, p.input)
+		}
+		pos := Position{Pos: uint16(n)}
+		if !p.eof() {
+			if w, ok := parseWeight(p.peek()); ok {
+				pos.Weight = w
+				p.pos++
+			} else if p.peek() == '*' {
+				// Older versions of PostgreSQL accept a star after positions.
+				p.pos++
+			}
+		}
+		res = append(res, pos)
+		if p.eof() || p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if !p.eof() {
+		if r, _ := p.nextRune(); !unicode.IsSpace(r) {
+			return nil, p.syntaxError()
+		}
+	}
+	return res, nil
+}
+
+func parseWeight(c byte) (Weight, bool) {
+	switch c {
+	case 'A', 'a':
+		return WeightA, true
+	case 'B', 'b':
+		return WeightB, true
+	case 'C', 'c':
+		return WeightC, true
+	case 'D', 'd':
+		return WeightD, true
+	}
+	return 0, false
+}